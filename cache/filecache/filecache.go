@@ -253,6 +253,23 @@ func (c *Cache) Get(id string) (ItemInfo, io.ReadCloser, error) {
 	return info, r, nil
 }
 
+// Has reports whether the cache holds a file with the given id that is not
+// expired. A cache with a negative max age never expires, and a cache with
+// max age 0 (disabled) holds no files.
+func (c *Cache) Has(id string) bool {
+	if c.maxAge == 0 {
+		return false
+	}
+
+	id = cleanID(id)
+	fi, err := c.Fs.Stat(id)
+	if err != nil {
+		return false
+	}
+
+	return !c.isExpired(fi.ModTime())
+}
+
 // getOrRemove gets the file with the given id. If it's expired, it will
 // be removed.
 func (c *Cache) getOrRemove(id string) hugio.ReadSeekCloser {
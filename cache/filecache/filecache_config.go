@@ -47,6 +47,9 @@ const (
 	cacheKeyImages  = "images"
 	cacheKeyAssets  = "assets"
 	cacheKeyModules = "modules"
+	cacheKeyMoves   = "moves"
+
+	cacheKeyPageStore = "pagestore"
 )
 
 type Configs map[string]Config
@@ -70,6 +73,8 @@ var defaultCacheConfigs = Configs{
 		MaxAge: -1,
 		Dir:    resourcesGenDir,
 	},
+	cacheKeyMoves:     defaultCacheConfig,
+	cacheKeyPageStore: defaultCacheConfig,
 }
 
 type Config struct {
@@ -111,6 +116,18 @@ func (f Caches) AssetsCache() *Cache {
 	return f[cacheKeyAssets]
 }
 
+// MovesCache gets the file cache for the page snapshots used to detect
+// moved pages.
+func (f Caches) MovesCache() *Cache {
+	return f[cacheKeyMoves]
+}
+
+// PageStoreCache gets the file cache for the rendered page content stored on
+// disk when build.pageStore is set to "disk".
+func (f Caches) PageStoreCache() *Cache {
+	return f[cacheKeyPageStore]
+}
+
 func DecodeConfig(fs afero.Fs, cfg config.Provider) (Configs, error) {
 	c := make(Configs)
 	valid := make(map[string]bool)
@@ -293,6 +293,36 @@ func TestFileCacheReadOrCreateErrorInRead(t *testing.T) {
 	c.Assert(err, qt.Equals, ErrFatal)
 }
 
+func TestFileCacheHas(t *testing.T) {
+	t.Parallel()
+	c := qt.New(t)
+
+	for _, test := range []struct {
+		maxAge   time.Duration
+		fresh    bool
+		stale    bool
+		expected string
+	}{
+		{-1, true, true, "never expire"},
+		{time.Hour, true, false, "expire after max age"},
+		{0, false, false, "disabled"},
+	} {
+		c.Run(test.expected, func(c *qt.C) {
+			fs := afero.NewMemMapFs()
+			cache := NewCache(fs, test.maxAge, "")
+
+			c.Assert(afero.WriteFile(fs, "fresh", []byte("v"), 0666), qt.IsNil)
+			c.Assert(afero.WriteFile(fs, "stale", []byte("v"), 0666), qt.IsNil)
+			old := time.Now().Add(-2 * time.Hour)
+			c.Assert(fs.Chtimes("stale", old, old), qt.IsNil)
+
+			c.Assert(cache.Has("fresh"), qt.Equals, test.fresh)
+			c.Assert(cache.Has("stale"), qt.Equals, test.stale)
+			c.Assert(cache.Has("missing"), qt.IsFalse)
+		})
+	}
+}
+
 func TestCleanID(t *testing.T) {
 	c := qt.New(t)
 	c.Assert(cleanID(filepath.FromSlash("/a/b//c.txt")), qt.Equals, filepath.FromSlash("a/b/c.txt"))
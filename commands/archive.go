@@ -0,0 +1,204 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// The modification time of all the files in the archives we create, so
+// building the same site twice gives the same archive. This is the earliest
+// time that can be represented in a zip file.
+var archiveModTime = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
+type archiveWriter interface {
+	addDir(name string) error
+	addFile(name string, size int64, r io.Reader) error
+	Close() error
+}
+
+// writeArchive writes the files below root in fs to a tar, tar.gz (or tgz)
+// or zip archive, given by the extension of filename. The files are added
+// in lexical order with fixed mtimes and modes.
+func writeArchive(fs afero.Fs, root, filename string) error {
+	var newWriter func(w io.Writer) archiveWriter
+
+	lower := strings.ToLower(filename)
+	switch {
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		newWriter = newTarGzArchiveWriter
+	case strings.HasSuffix(lower, ".tar"):
+		newWriter = newTarArchiveWriter
+	case strings.HasSuffix(lower, ".zip"):
+		newWriter = newZipArchiveWriter
+	default:
+		return errors.Errorf("unsupported archive format %q, must be one of .tar, .tar.gz, .tgz or .zip", filename)
+	}
+
+	var names []string
+	err := afero.Walk(fs, root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != root {
+			names = append(names, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	sort.Strings(names)
+
+	if dir := filepath.Dir(filename); dir != "" {
+		if err := os.MkdirAll(dir, 0777); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := newWriter(f)
+
+	for _, name := range names {
+		fi, err := fs.Stat(name)
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, name)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if fi.IsDir() {
+			if err := w.addDir(rel + "/"); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := addArchiveFile(fs, w, name, rel, fi.Size()); err != nil {
+			return err
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return f.Close()
+}
+
+func addArchiveFile(fs afero.Fs, w archiveWriter, filename, name string, size int64) error {
+	f, err := fs.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return w.addFile(name, size, f)
+}
+
+type tarArchiveWriter struct {
+	tw *tar.Writer
+	gz *gzip.Writer
+}
+
+func newTarArchiveWriter(w io.Writer) archiveWriter {
+	return &tarArchiveWriter{tw: tar.NewWriter(w)}
+}
+
+func newTarGzArchiveWriter(w io.Writer) archiveWriter {
+	gz := gzip.NewWriter(w)
+	return &tarArchiveWriter{tw: tar.NewWriter(gz), gz: gz}
+}
+
+func (a *tarArchiveWriter) addDir(name string) error {
+	return a.tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeDir,
+		Name:     name,
+		Mode:     0755,
+		ModTime:  archiveModTime,
+	})
+}
+
+func (a *tarArchiveWriter) addFile(name string, size int64, r io.Reader) error {
+	if err := a.tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Size:     size,
+		Mode:     0644,
+		ModTime:  archiveModTime,
+	}); err != nil {
+		return err
+	}
+	_, err := io.Copy(a.tw, r)
+	return err
+}
+
+func (a *tarArchiveWriter) Close() error {
+	if err := a.tw.Close(); err != nil {
+		return err
+	}
+	if a.gz != nil {
+		return a.gz.Close()
+	}
+	return nil
+}
+
+type zipArchiveWriter struct {
+	zw *zip.Writer
+}
+
+func newZipArchiveWriter(w io.Writer) archiveWriter {
+	return &zipArchiveWriter{zw: zip.NewWriter(w)}
+}
+
+func (a *zipArchiveWriter) addDir(name string) error {
+	h := &zip.FileHeader{Name: name, Modified: archiveModTime}
+	h.SetMode(os.ModeDir | 0755)
+	_, err := a.zw.CreateHeader(h)
+	return err
+}
+
+func (a *zipArchiveWriter) addFile(name string, size int64, r io.Reader) error {
+	h := &zip.FileHeader{Name: name, Method: zip.Deflate, Modified: archiveModTime}
+	h.SetMode(0644)
+	w, err := a.zw.CreateHeader(h)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, r)
+	return err
+}
+
+func (a *zipArchiveWriter) Close() error {
+	return a.zw.Close()
+}
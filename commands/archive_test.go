@@ -0,0 +1,102 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/spf13/afero"
+)
+
+func TestWriteArchive(t *testing.T) {
+	c := qt.New(t)
+
+	fs := afero.NewMemMapFs()
+	for name, content := range map[string]string{
+		"/public/index.html":     "<h1>Home</h1>",
+		"/public/b/index.html":   "<h1>B</h1>",
+		"/public/a/index.html":   "<h1>A</h1>",
+		"/public/css/styles.css": "body{}",
+	} {
+		c.Assert(afero.WriteFile(fs, filepath.FromSlash(name), []byte(content), 0755), qt.IsNil)
+	}
+
+	dir, err := ioutil.TempDir("", "hugo-archive")
+	c.Assert(err, qt.IsNil)
+	defer os.RemoveAll(dir)
+
+	expected := []string{"a/", "a/index.html", "b/", "b/index.html", "css/", "css/styles.css", "index.html"}
+
+	c.Run("tar.gz", func(c *qt.C) {
+		filename := filepath.Join(dir, "site.tar.gz")
+		c.Assert(writeArchive(fs, filepath.FromSlash("/public"), filename), qt.IsNil)
+
+		f, err := os.Open(filename)
+		c.Assert(err, qt.IsNil)
+		defer f.Close()
+		gz, err := gzip.NewReader(f)
+		c.Assert(err, qt.IsNil)
+		tr := tar.NewReader(gz)
+
+		var names []string
+		for {
+			h, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			c.Assert(err, qt.IsNil)
+			c.Assert(h.ModTime.Equal(archiveModTime), qt.IsTrue)
+			names = append(names, h.Name)
+			if h.Name == "a/index.html" {
+				b, err := ioutil.ReadAll(tr)
+				c.Assert(err, qt.IsNil)
+				c.Assert(string(b), qt.Equals, "<h1>A</h1>")
+			}
+		}
+		c.Assert(names, qt.DeepEquals, expected)
+	})
+
+	c.Run("zip", func(c *qt.C) {
+		filename := filepath.Join(dir, "site.zip")
+		c.Assert(writeArchive(fs, filepath.FromSlash("/public"), filename), qt.IsNil)
+
+		zr, err := zip.OpenReader(filename)
+		c.Assert(err, qt.IsNil)
+		defer zr.Close()
+
+		var names []string
+		for _, f := range zr.File {
+			c.Assert(f.Modified.Equal(archiveModTime), qt.IsTrue)
+			names = append(names, f.Name)
+		}
+		c.Assert(names, qt.DeepEquals, expected)
+	})
+
+	c.Run("Deterministic", func(c *qt.C) {
+		filename1, filename2 := filepath.Join(dir, "site1.tar"), filepath.Join(dir, "site2.tar")
+		c.Assert(writeArchive(fs, filepath.FromSlash("/public"), filename1), qt.IsNil)
+		c.Assert(writeArchive(fs, filepath.FromSlash("/public"), filename2), qt.IsNil)
+		c.Assert(readFileFrom(c, filename1), qt.Equals, readFileFrom(c, filename2))
+	})
+
+	c.Assert(writeArchive(fs, "/public", filepath.Join(dir, "site.7z")), qt.ErrorMatches, "unsupported archive format.*")
+}
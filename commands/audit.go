@@ -0,0 +1,153 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"os"
+	"strings"
+
+	"github.com/gohugoio/hugo/hugolib"
+	"github.com/spf13/cobra"
+	jww "github.com/spf13/jwalterweatherman"
+)
+
+var _ cmder = (*auditCmd)(nil)
+
+type auditCmd struct {
+	*baseBuilderCmd
+
+	write bool
+}
+
+func (ac *auditCmd) buildSites() (*hugolib.HugoSites, error) {
+	c, err := initializeConfig(true, false, &ac.hugoBuilderCommon, ac, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	sites, err := hugolib.NewHugoSites(*c.DepsCfg)
+	if err != nil {
+		return nil, newSystemError("Error creating sites", err)
+	}
+
+	if err := sites.Build(hugolib.BuildCfg{SkipRender: true}); err != nil {
+		return nil, newSystemError("Error Processing Source Content", err)
+	}
+
+	return sites, nil
+}
+
+func (b *commandsBuilder) newAuditCmd() *auditCmd {
+	cc := &auditCmd{}
+
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Audit your site",
+		Long: `Audit your site.
+
+Audit requires a subcommand, e.g. ` + "`hugo audit moves`.",
+		RunE: nil,
+	}
+
+	movesCmd := &cobra.Command{
+		Use:   "moves",
+		Short: "List pages moved since the last build",
+		Long: `List the pages that look moved since the last build, matched by page ID or
+content similarity, and the aliases needed to keep their old URLs working.
+
+The pages from the last build are only stored when moves.detect is enabled
+in your site configuration.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sites, err := cc.buildSites()
+			if err != nil {
+				return newSystemError("Error building sites", err)
+			}
+
+			moves, err := sites.DetectMoves()
+			if err != nil {
+				return newSystemError("Error detecting moved pages", err)
+			}
+
+			if len(moves) == 0 {
+				jww.FEEDBACK.Println("No moved pages found.")
+				return nil
+			}
+
+			for _, m := range moves {
+				filename := strings.TrimPrefix(m.Page.File().Filename(), sites.WorkingDir+string(os.PathSeparator))
+				jww.FEEDBACK.Printf("%s: add alias %q (similarity %.2f)\n", filename, m.OldPath, m.Similarity)
+			}
+
+			if !cc.write {
+				return nil
+			}
+
+			if err := sites.WriteMoveAliases(moves); err != nil {
+				return newSystemError("Error writing aliases", err)
+			}
+
+			jww.FEEDBACK.Printf("Added aliases to %d pages.\n", len(moves))
+
+			return nil
+		},
+	}
+
+	movesCmd.Flags().BoolVar(&cc.write, "write", false, "add the aliases to the front matter of the moved pages")
+
+	reviewsCmd := &cobra.Command{
+		Use:   "reviews",
+		Short: "List pages missing review fields or overdue for review",
+		Long: `List the pages missing the front matter fields required by the review
+section in your site configuration, and the pages not reviewed within the
+interval configured for their section.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sites, err := cc.buildSites()
+			if err != nil {
+				return newSystemError("Error building sites", err)
+			}
+
+			issues, err := sites.ReviewIssues()
+			if err != nil {
+				return newSystemError("Error checking reviews", err)
+			}
+
+			if len(issues) == 0 {
+				jww.FEEDBACK.Println("No review issues found.")
+				return nil
+			}
+
+			var overdue int
+			for _, issue := range issues {
+				filename := strings.TrimPrefix(issue.Page.File().Filename(), sites.WorkingDir+string(os.PathSeparator))
+				if issue.Missing != nil {
+					jww.FEEDBACK.Printf("%s: missing %s\n", filename, strings.Join(issue.Missing, ", "))
+				}
+				if issue.Overdue() {
+					overdue++
+					jww.FEEDBACK.Printf("%s: overdue for review since %s\n", filename, issue.Due.Format("2006-01-02"))
+				}
+			}
+
+			jww.FEEDBACK.Printf("%d pages overdue for review.\n", overdue)
+
+			return nil
+		},
+	}
+
+	cmd.AddCommand(movesCmd, reviewsCmd)
+
+	cc.baseBuilderCmd = b.newBuilderBasicCmd(cmd)
+
+	return cc
+}
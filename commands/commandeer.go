@@ -34,6 +34,7 @@ import (
 
 	"github.com/gohugoio/hugo/common/loggers"
 	"github.com/gohugoio/hugo/config"
+	"github.com/gohugoio/hugo/config/webhook"
 
 	"github.com/spf13/cobra"
 
@@ -60,6 +61,7 @@ type commandeer struct {
 
 	logger       loggers.Logger
 	serverConfig *config.Server
+	webhook      webhook.Config
 
 	// Currently only set when in "fast render mode". But it seems to
 	// be fast enough that we could maybe just add it for all server modes.
@@ -94,6 +96,10 @@ type commandeer struct {
 
 	fullRebuildSem *semaphore.Weighted
 
+	// Serializes the rebuilds from the file watcher and the ones triggered
+	// by cache invalidation and expiry.
+	rebuildMu sync.Mutex
+
 	// Any error from the last build.
 	buildErr error
 }
@@ -345,8 +351,12 @@ func (c *commandeer) loadConfig(mustHaveConfigFile, running bool) error {
 	if err != nil {
 		return err
 	}
+	c.webhook, err = webhook.DecodeConfig(cfg.Cfg)
+	if err != nil {
+		return err
+	}
 
-	createMemFs := config.GetBool("renderToMemory")
+	createMemFs := config.GetBool("renderToMemory") || config.GetString("renderToArchive") != ""
 
 	if createMemFs {
 		// Rendering to memoryFS, publish to Root regardless of publishDir.
@@ -45,6 +45,7 @@ func (b *commandsBuilder) addCommands(commands ...cmder) *commandsBuilder {
 func (b *commandsBuilder) addAll() *commandsBuilder {
 	b.addCommands(
 		b.newServerCmd(),
+		b.newServeBuildsCmd(),
 		newVersionCmd(),
 		newEnvCmd(),
 		b.newConfigCmd(),
@@ -53,10 +54,15 @@ func (b *commandsBuilder) addAll() *commandsBuilder {
 		b.newConvertCmd(),
 		b.newNewCmd(),
 		b.newListCmd(),
+		b.newAuditCmd(),
+		b.newTestCmd(),
+		b.newContentCmd(),
+		b.newLintCmd(),
 		newImportCmd(),
 		newGenCmd(),
 		createReleaser(),
 		b.newModCmd(),
+		b.newSecretsCmd(),
 	)
 
 	return b
@@ -196,6 +202,7 @@ Complete documentation is available at http://gohugo.io/.`,
 	cc.cmd.Flags().BoolVarP(&cc.buildWatch, "watch", "w", false, "watch filesystem for changes and recreate as needed")
 
 	cc.cmd.Flags().Bool("renderToMemory", false, "render to memory (only useful for benchmark testing)")
+	cc.cmd.Flags().String("renderToArchive", "", "render to memory and write the site to the given .tar, .tar.gz, .tgz or .zip archive")
 
 	// Set bash-completion
 	_ = cc.cmd.PersistentFlags().SetAnnotation("logFile", cobra.BashCompFilenameExt, []string{})
@@ -309,6 +316,7 @@ func (cc *hugoBuilderCommon) handleFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolP("noChmod", "", false, "don't sync permission mode of files")
 	cmd.Flags().BoolP("i18n-warnings", "", false, "print missing translations")
 	cmd.Flags().BoolP("path-warnings", "", false, "print warnings on duplicate target paths etc.")
+	cmd.Flags().Bool("asset-report", false, "write hugo_asset_report.json and hugo_asset_report.html with the composition of the js.Build bundles")
 	cmd.Flags().StringVarP(&cc.cpuprofile, "profile-cpu", "", "", "write cpu profile to `file`")
 	cmd.Flags().StringVarP(&cc.memprofile, "profile-mem", "", "", "write memory profile to `file`")
 	cmd.Flags().BoolVarP(&cc.printm, "print-mem", "", false, "print memory usage to screen at intervals")
@@ -64,6 +64,22 @@ func TestExecute(t *testing.T) {
 		c.Assert(result.Sites[0].Info.Params()["myparam"], qt.Equals, "paramstaging")
 	})
 
+	c.Run("hugo, render to archive", func(c *qt.C) {
+		dir, clean := createSite(c)
+		defer clean()
+		archive1, archive2 := filepath.Join(dir, "out", "site1.tar.gz"), filepath.Join(dir, "out", "site2.tar.gz")
+		resp := Execute([]string{"-s=" + dir, "--renderToArchive=" + archive1})
+		c.Assert(resp.Err, qt.IsNil)
+		resp = Execute([]string{"-s=" + dir, "--renderToArchive=" + archive2})
+		c.Assert(resp.Err, qt.IsNil)
+		c.Assert(readFileFrom(c, archive1), qt.Equals, readFileFrom(c, archive2))
+		_, err := os.Stat(filepath.Join(dir, "public", "index.html"))
+		c.Assert(os.IsNotExist(err), qt.IsTrue)
+
+		resp = Execute([]string{"-s=" + dir, "--renderToArchive=" + filepath.Join(dir, "site.rar")})
+		c.Assert(resp.Err, qt.ErrorMatches, ".*unsupported archive format.*")
+	})
+
 	c.Run("convert toJSON", func(c *qt.C) {
 		dir, clean := createSite(c)
 		output := filepath.Join(dir, "myjson")
@@ -74,6 +90,61 @@ func TestExecute(t *testing.T) {
 		c.Assert(converted, qt.Equals, "{\n   \"title\": \"P1\",\n   \"weight\": 1\n}\n\nContent\n\n", qt.Commentf(converted))
 	})
 
+	c.Run("convert frontmatter", func(c *qt.C) {
+		dir, clean := createSite(c)
+		defer clean()
+		resp := Execute([]string{"convert", "frontmatter", "--to=toml", "-s=" + dir, "--in-place"})
+		c.Assert(resp.Err, qt.IsNil)
+		converted := readFileFrom(c, filepath.Join(dir, "content", "p1.md"))
+		c.Assert(converted, qt.Equals, "+++\ntitle = \"P1\"\nweight = 1\n\n+++\n\nContent\n\n", qt.Commentf(converted))
+
+		resp = Execute([]string{"convert", "frontmatter", "--to=xml", "-s=" + dir, "--in-place"})
+		c.Assert(resp.Err, qt.Not(qt.IsNil))
+		c.Assert(resp.Err.Error(), qt.Contains, `unsupported front matter format "xml"`)
+	})
+
+	c.Run("content set", func(c *qt.C) {
+		dir, clean := createSite(c)
+		defer clean()
+		resp := Execute([]string{"content", "set", "--match=section:", "--match=path:*.md", "-s=" + dir, "draft=true", "weight=3"})
+		c.Assert(resp.Err, qt.IsNil)
+		edited := readFileFrom(c, filepath.Join(dir, "content", "p1.md"))
+		c.Assert(edited, qt.Equals, "\n---\ntitle: \"P1\"\nweight: 3\ndraft: true\n---\n\nContent\n\n", qt.Commentf(edited))
+
+		resp = Execute([]string{"content", "rename", "--match=section:blog", "-s=" + dir, "weight=order"})
+		c.Assert(resp.Err, qt.IsNil)
+		c.Assert(readFileFrom(c, filepath.Join(dir, "content", "p1.md")), qt.Equals, edited)
+
+		resp = Execute([]string{"content", "delete", "-s=" + dir, "draft"})
+		c.Assert(resp.Err, qt.Not(qt.IsNil))
+		c.Assert(resp.Err.Error(), qt.Contains, "--match is required")
+	})
+
+	c.Run("lint frontmatter", func(c *qt.C) {
+		dir, clean := createSite(c)
+		defer clean()
+		filename := filepath.Join(dir, "content", "p2.md")
+		c.Assert(ioutil.WriteFile(filename, []byte(`---
+title: "P2"
+# The author.
+author: Jane
+_build:
+  list: never
+---
+`), 0666), qt.IsNil)
+		resp := Execute([]string{"lint", "frontmatter", "-s=" + dir})
+		c.Assert(resp.Err, qt.Not(qt.IsNil))
+		c.Assert(resp.Err.Error(), qt.Contains, "2 deprecated front matter keys found")
+
+		resp = Execute([]string{"lint", "frontmatter", "--fix", "-s=" + dir})
+		c.Assert(resp.Err, qt.IsNil)
+		fixed := readFileFrom(c, filename)
+		c.Assert(fixed, qt.Equals, "---\ntitle: \"P2\"\n# The author.\nbuild:\n  list: never\nparams:\n  author: Jane\n---\n", qt.Commentf(fixed))
+
+		resp = Execute([]string{"lint", "frontmatter", "-s=" + dir})
+		c.Assert(resp.Err, qt.IsNil)
+	})
+
 	c.Run("config, set environment", func(c *qt.C) {
 		dir, clean := createSite(c)
 		defer clean()
@@ -288,6 +359,8 @@ func TestCommandsExecute(t *testing.T) {
 		{[]string{"convert", "toTOML"}, []string{sourceFlag, "-o=" + filepath.Join(dirOut, "toml")}, ""},
 		{[]string{"convert", "toYAML"}, []string{sourceFlag, "-o=" + filepath.Join(dirOut, "yaml")}, ""},
 		{[]string{"convert", "toJSON"}, []string{sourceFlag, "-o=" + filepath.Join(dirOut, "json")}, ""},
+		{[]string{"content", "set"}, []string{sourceFlag, "--match=section:blog", "--dryRun", "title=Test"}, ""},
+		{[]string{"convert", "frontmatter"}, []string{sourceFlag, "--to=yaml", "-o=" + filepath.Join(dirOut, "frontmatter")}, ""},
 		{[]string{"gen", "autocomplete"}, []string{"--completionfile=" + filepath.Join(dirOut, "autocomplete.txt")}, ""},
 		{[]string{"gen", "chromastyles"}, []string{"--style=manni"}, ""},
 		{[]string{"gen", "doc"}, []string{"--dir=" + filepath.Join(dirOut, "doc")}, ""},
@@ -295,6 +368,8 @@ func TestCommandsExecute(t *testing.T) {
 		{[]string{"list", "drafts"}, []string{sourceFlag}, ""},
 		{[]string{"list", "expired"}, []string{sourceFlag}, ""},
 		{[]string{"list", "future"}, []string{sourceFlag}, ""},
+		{[]string{"audit", "moves"}, []string{sourceFlag}, ""},
+		{[]string{"audit", "reviews"}, []string{sourceFlag}, ""},
 		{[]string{"new", "new-page.md"}, []string{sourceFlag}, ""},
 		{[]string{"new", "site", filepath.Join(dirOut, "new-site")}, nil, ""},
 		{[]string{"unknowncommand"}, nil, "unknown command"},
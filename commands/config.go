@@ -87,7 +87,7 @@ func (c *configCmd) printConfig(cmd *cobra.Command, args []string) error {
 
 	// We need to clean up this, but we store objects in the config that
 	// isn't really interesting to the end user, so filter these.
-	ignoreKeysRe := regexp.MustCompile("client|sorted|filecacheconfigs|allmodules|multilingual")
+	ignoreKeysRe := regexp.MustCompile("client|sorted|filecacheconfigs|allmodules|multilingual|secrets|signingkeys")
 
 	separator := ": "
 
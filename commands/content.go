@@ -0,0 +1,302 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gohugoio/hugo/helpers"
+	"github.com/gohugoio/hugo/hugofs/glob"
+	"github.com/gohugoio/hugo/hugolib"
+	"github.com/gohugoio/hugo/parser"
+	"github.com/gohugoio/hugo/resources/page"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	jww "github.com/spf13/jwalterweatherman"
+)
+
+var _ cmder = (*contentCmd)(nil)
+
+type contentCmd struct {
+	*baseBuilderCmd
+
+	match  []string
+	dryRun bool
+}
+
+func (b *commandsBuilder) newContentCmd() *contentCmd {
+	cc := &contentCmd{}
+
+	cmd := &cobra.Command{
+		Use:   "content",
+		Short: "Edit the front matter of your content",
+		Long: `Edit the front matter of the content files matched by --match.
+
+A match is on the form "field:value", where field is one of section, type,
+kind, lang or path. The path is a Glob pattern matched against the file path
+relative to the content dir, e.g. "path:blog/2021/**". A file must match all
+the --match flags given.
+
+YAML and TOML front matter is edited line by line, preserving the formatting
+and comments of the keys not edited.
+
+Content requires a subcommand, e.g. ` + "`hugo content set --match section:blog draft=false`.",
+		RunE: nil,
+	}
+
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "set key=value...",
+			Short: "Set front matter keys",
+			Long: `Set front matter keys in the matched files.
+
+Values are written as numbers or booleans if they look like one, and as
+lists if given as a JSON array, e.g. tags=["a","b"]. Nested keys are
+separated by a ".", e.g. params.author=Jane.`,
+			Args: cobra.MinimumNArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				var edits []parser.FrontMatterEdit
+				for _, arg := range args {
+					key, value, err := splitKeyValue(arg)
+					if err != nil {
+						return err
+					}
+					edits = append(edits, parser.FrontMatterEdit{Op: parser.FrontMatterSet, Key: key, Value: parseFrontMatterValue(value)})
+				}
+				return cc.edit(edits)
+			},
+		},
+		&cobra.Command{
+			Use:   "delete key...",
+			Short: "Delete front matter keys",
+			Long:  `Delete front matter keys in the matched files.`,
+			Args:  cobra.MinimumNArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				var edits []parser.FrontMatterEdit
+				for _, key := range args {
+					edits = append(edits, parser.FrontMatterEdit{Op: parser.FrontMatterDelete, Key: key})
+				}
+				return cc.edit(edits)
+			},
+		},
+		&cobra.Command{
+			Use:   "rename oldkey=newkey...",
+			Short: "Rename front matter keys",
+			Long: `Rename front matter keys in the matched files. Nested keys keep their
+parent, e.g. params.author=writer renames params.author to params.writer.`,
+			Args: cobra.MinimumNArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				var edits []parser.FrontMatterEdit
+				for _, arg := range args {
+					key, newKey, err := splitKeyValue(arg)
+					if err != nil {
+						return err
+					}
+					edits = append(edits, parser.FrontMatterEdit{Op: parser.FrontMatterRename, Key: key, NewKey: newKey})
+				}
+				return cc.edit(edits)
+			},
+		},
+	)
+
+	cmd.PersistentFlags().StringSliceVar(&cc.match, "match", nil, `match the content files to edit, e.g. "section:blog"`)
+	cmd.PersistentFlags().BoolVar(&cc.dryRun, "dryRun", false, "list the files that would be edited without editing them")
+
+	cc.baseBuilderCmd = b.newBuilderBasicCmd(cmd)
+
+	return cc
+}
+
+func (cc *contentCmd) edit(edits []parser.FrontMatterEdit) error {
+	if len(cc.match) == 0 {
+		return newUserError("At least one --match is required")
+	}
+
+	match, err := newContentMatcher(cc.match)
+	if err != nil {
+		return newUserError(err)
+	}
+
+	c, err := initializeConfig(true, false, &cc.hugoBuilderCommon, cc, nil)
+	if err != nil {
+		return err
+	}
+
+	c.Cfg.Set("buildDrafts", true)
+	c.Cfg.Set("buildFuture", true)
+	c.Cfg.Set("buildExpired", true)
+
+	h, err := hugolib.NewHugoSites(*c.DepsCfg)
+	if err != nil {
+		return err
+	}
+
+	if err := h.Build(hugolib.BuildCfg{SkipRender: true}); err != nil {
+		return err
+	}
+
+	var (
+		seen   = make(map[string]bool)
+		edited int
+	)
+
+	var editPage func(p page.Page) error
+	editPage = func(p page.Page) error {
+		// The resources are not in .Site.AllPages.
+		for _, r := range p.Resources().ByType("page") {
+			if err := editPage(r.(page.Page)); err != nil {
+				return err
+			}
+		}
+
+		if p.File().IsZero() || !match(p) {
+			return nil
+		}
+
+		filename := p.File().Filename()
+		if seen[filename] {
+			return nil
+		}
+		seen[filename] = true
+
+		f, err := p.File().FileInfo().Meta().Open()
+		if err != nil {
+			return err
+		}
+		content, err := ioutil.ReadAll(f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+
+		b, changed, err := parser.EditFrontMatter(content, edits...)
+		if err != nil {
+			return errors.Wrapf(err, "failed to edit %q", filename)
+		}
+		if !changed {
+			return nil
+		}
+
+		edited++
+		relFilename := strings.TrimPrefix(filename, h.WorkingDir+string(os.PathSeparator))
+
+		if cc.dryRun {
+			jww.FEEDBACK.Println("Would edit", relFilename)
+			return nil
+		}
+
+		if err := helpers.WriteToDisk(filename, bytes.NewReader(b), h.Fs.Source); err != nil {
+			return errors.Wrapf(err, "failed to save %q", filename)
+		}
+		jww.FEEDBACK.Println("Edited", relFilename)
+
+		return nil
+	}
+
+	for _, p := range h.Sites[0].AllPages() {
+		if err := editPage(p); err != nil {
+			return err
+		}
+	}
+
+	if cc.dryRun {
+		jww.FEEDBACK.Printf("Would edit %d content files.\n", edited)
+	} else {
+		jww.FEEDBACK.Printf("Edited %d content files.\n", edited)
+	}
+
+	return nil
+}
+
+// newContentMatcher creates a func matching the pages matching all the given
+// matches on the form "field:value".
+func newContentMatcher(matches []string) (func(p page.Page) bool, error) {
+	var matchers []func(p page.Page) bool
+
+	for _, m := range matches {
+		parts := strings.SplitN(m, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid match %q, must be on the form field:value", m)
+		}
+		field, value := strings.ToLower(strings.TrimSpace(parts[0])), strings.TrimSpace(parts[1])
+
+		var matcher func(p page.Page) bool
+
+		switch field {
+		case "section":
+			matcher = func(p page.Page) bool { return strings.EqualFold(p.Section(), value) }
+		case "type":
+			matcher = func(p page.Page) bool { return strings.EqualFold(p.Type(), value) }
+		case "kind":
+			matcher = func(p page.Page) bool { return strings.EqualFold(p.Kind(), value) }
+		case "lang":
+			matcher = func(p page.Page) bool { return strings.EqualFold(p.Lang(), value) }
+		case "path":
+			g, err := glob.GetGlob(strings.TrimPrefix(filepath.ToSlash(value), "/"))
+			if err != nil {
+				return nil, err
+			}
+			matcher = func(p page.Page) bool { return g.Match(filepath.ToSlash(p.File().Path())) }
+		default:
+			return nil, fmt.Errorf("invalid match %q, field must be one of section, type, kind, lang or path", m)
+		}
+
+		matchers = append(matchers, matcher)
+	}
+
+	return func(p page.Page) bool {
+		for _, m := range matchers {
+			if !m(p) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+func splitKeyValue(s string) (string, string, error) {
+	parts := strings.SplitN(s, "=", 2)
+	if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+		return "", "", newUserError(fmt.Sprintf("invalid argument %q, must be on the form key=value", s))
+	}
+	return strings.TrimSpace(parts[0]), parts[1], nil
+}
+
+// parseFrontMatterValue parses s as an integer, float, boolean or a JSON
+// array, falling back to the string itself.
+func parseFrontMatterValue(s string) interface{} {
+	if i, err := strconv.Atoi(s); err == nil {
+		return i
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f
+	}
+	if s == "true" || s == "false" {
+		return s == "true"
+	}
+	if strings.HasPrefix(s, "[") {
+		var v []interface{}
+		if err := json.Unmarshal([]byte(s), &v); err == nil {
+			return v
+		}
+	}
+	return s
+}
@@ -18,9 +18,10 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
-	"time"
 
+	"github.com/gohugoio/hugo/config"
 	"github.com/gohugoio/hugo/parser/pageparser"
+	"github.com/gohugoio/hugo/resources/page/pagemeta"
 
 	"github.com/gohugoio/hugo/resources/page"
 
@@ -44,6 +45,10 @@ type convertCmd struct {
 	outputDir string
 	unsafe    bool
 
+	// Used by the frontmatter subcommand.
+	to      string
+	inPlace bool
+
 	*baseBuilderCmd
 }
 
@@ -55,18 +60,36 @@ func (b *commandsBuilder) newConvertCmd() *convertCmd {
 		Short: "Convert your content to different formats",
 		Long: `Convert your content (e.g. front matter) to different formats.
 
-See convert's subcommands toJSON, toTOML and toYAML for more information.`,
+See convert's subcommands frontmatter, toJSON, toTOML and toYAML for more information.`,
 		RunE: nil,
 	}
 
+	frontMatterCmd := &cobra.Command{
+		Use:   "frontmatter",
+		Short: "Convert front matter to the configured format",
+		Long: `frontmatter converts all front matter in the content directory
+to the format given with --to, or to the format set in the frontmatter.format
+and frontmatter.archetypeFormats config, using the page's section as the
+archetype.
+
+Files that already use the target format are left untouched, so their
+comments are preserved. Comments in converted front matter are lost.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cc.convertFrontMatter()
+		},
+	}
+	frontMatterCmd.Flags().StringVar(&cc.to, "to", "", "the target format, one of yaml, toml or json")
+	frontMatterCmd.Flags().BoolVar(&cc.inPlace, "in-place", false, "overwrite the content files, please backup first")
+
 	cmd.AddCommand(
+		frontMatterCmd,
 		&cobra.Command{
 			Use:   "toJSON",
 			Short: "Convert front matter to JSON",
 			Long: `toJSON converts all front matter in the content directory
 to use JSON for the front matter.`,
 			RunE: func(cmd *cobra.Command, args []string) error {
-				return cc.convertContents(metadecoders.JSON)
+				return cc.convertContents(toFormat(metadecoders.JSON), false)
 			},
 		},
 		&cobra.Command{
@@ -75,7 +98,7 @@ to use JSON for the front matter.`,
 			Long: `toTOML converts all front matter in the content directory
 to use TOML for the front matter.`,
 			RunE: func(cmd *cobra.Command, args []string) error {
-				return cc.convertContents(metadecoders.TOML)
+				return cc.convertContents(toFormat(metadecoders.TOML), false)
 			},
 		},
 		&cobra.Command{
@@ -84,7 +107,7 @@ to use TOML for the front matter.`,
 			Long: `toYAML converts all front matter in the content directory
 to use YAML for the front matter.`,
 			RunE: func(cmd *cobra.Command, args []string) error {
-				return cc.convertContents(metadecoders.YAML)
+				return cc.convertContents(toFormat(metadecoders.YAML), false)
 			},
 		},
 	)
@@ -97,7 +120,53 @@ to use YAML for the front matter.`,
 	return cc
 }
 
-func (cc *convertCmd) convertContents(format metadecoders.Format) error {
+func toFormat(format metadecoders.Format) func(p page.Page) metadecoders.Format {
+	return func(p page.Page) metadecoders.Format {
+		return format
+	}
+}
+
+func (cc *convertCmd) convertFrontMatter() error {
+	var format metadecoders.Format
+	if cc.to != "" {
+		format = metadecoders.FormatFromString(cc.to)
+		switch format {
+		case metadecoders.YAML, metadecoders.TOML, metadecoders.JSON:
+		default:
+			return newUserError(fmt.Sprintf("unsupported front matter format %q, use one of yaml, toml or json", cc.to))
+		}
+	}
+
+	cc.unsafe = cc.unsafe || cc.inPlace
+
+	var fmCfg pagemeta.FrontMatterFormat
+
+	return cc.convertContentsWithConfig(func(cfg config.Provider) error {
+		if format != "" {
+			return nil
+		}
+		var err error
+		fmCfg, err = pagemeta.DecodeFrontMatterFormat(cfg)
+		if err != nil {
+			return err
+		}
+		if fmCfg.Format == "" && len(fmCfg.ArchetypeFormats) == 0 {
+			return newUserError("No target format, use --to or set frontmatter.format in the config")
+		}
+		return nil
+	}, func(p page.Page) metadecoders.Format {
+		if format != "" {
+			return format
+		}
+		return metadecoders.FormatFromString(fmCfg.FormatFor(p.Section()))
+	}, true)
+}
+
+func (cc *convertCmd) convertContents(formatFor func(p page.Page) metadecoders.Format, skipSameFormat bool) error {
+	return cc.convertContentsWithConfig(nil, formatFor, skipSameFormat)
+}
+
+func (cc *convertCmd) convertContentsWithConfig(withConfig func(cfg config.Provider) error, formatFor func(p page.Page) metadecoders.Format, skipSameFormat bool) error {
 	if cc.outputDir == "" && !cc.unsafe {
 		return newUserError("Unsafe operation not allowed, use --unsafe or set a different output path")
 	}
@@ -107,6 +176,12 @@ func (cc *convertCmd) convertContents(format metadecoders.Format) error {
 		return err
 	}
 
+	if withConfig != nil {
+		if err := withConfig(c.Cfg); err != nil {
+			return err
+		}
+	}
+
 	c.Cfg.Set("buildDrafts", true)
 
 	h, err := hugolib.NewHugoSites(*c.DepsCfg)
@@ -122,17 +197,17 @@ func (cc *convertCmd) convertContents(format metadecoders.Format) error {
 
 	site.Log.Println("processing", len(site.AllPages()), "content files")
 	for _, p := range site.AllPages() {
-		if err := cc.convertAndSavePage(p, site, format); err != nil {
+		if err := cc.convertAndSavePage(p, site, formatFor, skipSameFormat); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (cc *convertCmd) convertAndSavePage(p page.Page, site *hugolib.Site, targetFormat metadecoders.Format) error {
+func (cc *convertCmd) convertAndSavePage(p page.Page, site *hugolib.Site, formatFor func(p page.Page) metadecoders.Format, skipSameFormat bool) error {
 	// The resources are not in .Site.AllPages.
 	for _, r := range p.Resources().ByType("page") {
-		if err := cc.convertAndSavePage(r.(page.Page), site, targetFormat); err != nil {
+		if err := cc.convertAndSavePage(r.(page.Page), site, formatFor, skipSameFormat); err != nil {
 			return err
 		}
 	}
@@ -163,24 +238,17 @@ func (cc *convertCmd) convertAndSavePage(p page.Page, site *hugolib.Site, target
 
 	file.Close()
 
-	// better handling of dates in formats that don't have support for them
-	if pf.FrontMatterFormat == metadecoders.JSON || pf.FrontMatterFormat == metadecoders.YAML || pf.FrontMatterFormat == metadecoders.TOML {
-		for k, v := range pf.FrontMatter {
-			switch vv := v.(type) {
-			case time.Time:
-				pf.FrontMatter[k] = vv.Format(time.RFC3339)
-			}
-		}
+	targetFormat := formatFor(p)
+	if targetFormat == "" || (skipSameFormat && (!parser.IsConvertibleFrontMatter(pf) || pf.FrontMatterFormat == targetFormat)) {
+		return nil
 	}
 
-	var newContent bytes.Buffer
-	err = parser.InterfaceToFrontMatter(pf.FrontMatter, targetFormat, &newContent)
+	b, err := parser.FrontMatterToContent(pf, targetFormat)
 	if err != nil {
 		site.Log.Errorln(errMsg)
 		return err
 	}
-
-	newContent.Write(pf.Content)
+	newContent := bytes.NewReader(b)
 
 	newFilename := p.File().Filename()
 
@@ -192,7 +260,7 @@ func (cc *convertCmd) convertAndSavePage(p page.Page, site *hugolib.Site, target
 	}
 
 	fs := hugofs.Os
-	if err := helpers.WriteToDisk(newFilename, &newContent, fs); err != nil {
+	if err := helpers.WriteToDisk(newFilename, newContent, fs); err != nil {
 		return errors.Wrapf(err, "Failed to save file %q:", newFilename)
 	}
 
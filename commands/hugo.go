@@ -55,6 +55,7 @@ import (
 	"github.com/gohugoio/hugo/helpers"
 	"github.com/gohugoio/hugo/hugolib"
 	"github.com/gohugoio/hugo/livereload"
+	"github.com/gohugoio/hugo/modules"
 	"github.com/gohugoio/hugo/watcher"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
@@ -222,6 +223,7 @@ func initializeFlags(cmd *cobra.Command, cfg config.Provider) {
 		"maxDeletes",
 		"quiet",
 		"renderToMemory",
+		"renderToArchive",
 		"source",
 		"target",
 		"theme",
@@ -244,6 +246,17 @@ func initializeFlags(cmd *cobra.Command, cfg config.Provider) {
 	setValueFromFlag(cmd.Flags(), "destination", cfg, "publishDir", false)
 	setValueFromFlag(cmd.Flags(), "i18n-warnings", cfg, "logI18nWarnings", false)
 	setValueFromFlag(cmd.Flags(), "path-warnings", cfg, "logPathWarnings", false)
+
+	if cmd.Flags().Changed("asset-report") {
+		// Setting build.writeAssetReport directly would shadow the rest of
+		// the build config.
+		build := make(map[string]interface{})
+		for k, v := range cfg.GetStringMap("build") {
+			build[k] = v
+		}
+		build["writeAssetReport"], _ = cmd.Flags().GetBool("asset-report")
+		cfg.Set("build", build)
+	}
 }
 
 func setValueFromFlag(flags *flag.FlagSet, key string, cfg config.Provider, targetKey string, force bool) {
@@ -496,10 +509,21 @@ func (c *commandeer) build() error {
 		}
 	}()
 
+	archive := c.Cfg.GetString("renderToArchive")
+	if archive != "" && c.h.buildWatch {
+		return errors.New("renderToArchive cannot be combined with watch")
+	}
+
 	if err := c.fullBuild(); err != nil {
 		return err
 	}
 
+	if archive != "" {
+		if err := writeArchive(c.destinationFs, "/", archive); err != nil {
+			return errors.Wrap(err, "failed to write archive")
+		}
+	}
+
 	// TODO(bep) Feedback?
 	if !c.h.quiet {
 		fmt.Println()
@@ -551,10 +575,21 @@ func (c *commandeer) serverBuild() error {
 		}
 	}()
 
+	archive := c.Cfg.GetString("renderToArchive")
+	if archive != "" && c.h.buildWatch {
+		return errors.New("renderToArchive cannot be combined with watch")
+	}
+
 	if err := c.fullBuild(); err != nil {
 		return err
 	}
 
+	if archive != "" {
+		if err := writeArchive(c.destinationFs, "/", archive); err != nil {
+			return errors.Wrap(err, "failed to write archive")
+		}
+	}
+
 	// TODO(bep) Feedback?
 	if !c.h.quiet {
 		fmt.Println()
@@ -644,8 +679,9 @@ func (c *commandeer) copyStaticTo(sourceFs *filesystems.SourceFilesystem) (uint6
 	syncer.NoTimes = c.Cfg.GetBool("noTimes")
 	syncer.NoChmod = c.Cfg.GetBool("noChmod")
 	syncer.ChmodFilter = chmodFilter
+	precompress := c.hugo().ResourceSpec.Precompress
 	syncer.SrcFs = fs
-	syncer.DestFs = c.Fs.Destination
+	syncer.DestFs = precompress.Fs(c.Fs.Destination)
 	// Now that we are using a unionFs for the static directories
 	// We can effectively clean the publishDir on initial sync
 	syncer.Delete = c.Cfg.GetBool("cleanDestinationDir")
@@ -654,7 +690,8 @@ func (c *commandeer) copyStaticTo(sourceFs *filesystems.SourceFilesystem) (uint6
 		c.logger.Infoln("removing all files from destination that don't exist in static dirs")
 
 		syncer.DeleteFilter = func(f os.FileInfo) bool {
-			return f.IsDir() && strings.HasPrefix(f.Name(), ".")
+			// The compressed siblings are removed with their file.
+			return (f.IsDir() && strings.HasPrefix(f.Name(), ".")) || precompress.IsCompressed(f.Name())
 		}
 	}
 	c.logger.Infoln("syncing static files to", publishDir)
@@ -736,6 +773,8 @@ func (c *commandeer) handleBuildErr(err error, msg string) {
 }
 
 func (c *commandeer) rebuildSites(events []fsnotify.Event) error {
+	c.rebuildMu.Lock()
+	defer c.rebuildMu.Unlock()
 
 	c.buildErr = nil
 	visited := c.visitedURLs.PeekAllSet()
@@ -791,6 +830,8 @@ func (c *commandeer) fullRebuild(changeType string) {
 
 		defer c.timeTrack(time.Now(), "Rebuilt")
 
+		prev := c.hugoSites
+
 		c.commandeerHugoState = newCommandeerHugoState()
 		err := c.loadConfig(true, true)
 		if err != nil {
@@ -802,6 +843,15 @@ func (c *commandeer) fullRebuild(changeType string) {
 			c.paused = false
 		}
 
+		if !c.paused && prev != nil && changeType != configChangeGoMod {
+			// Keep the caches not depending on the changed config, e.g. the
+			// processed images when only the site params changed.
+			changed := c.hugo().ReuseCaches(prev)
+			if len(changed) > 0 {
+				c.logger.Infoln("Changed config keys:", strings.Join(changed, ", "))
+			}
+		}
+
 		if !c.paused {
 			_, err := c.copyStatic()
 			if err != nil {
@@ -877,9 +927,57 @@ func (c *commandeer) newWatcher(pollIntervalStr string, dirList ...string) (*wat
 		}
 	}()
 
+	go c.refreshFeeds()
+
 	return watcher, nil
 }
 
+// refreshFeeds rebuilds the site with the shortest refresh interval of the
+// feed mounts, which fetches any new items.
+func (c *commandeer) refreshFeeds() {
+	for {
+		interval, found := c.feedRefreshInterval()
+		if !found {
+			// The config may change.
+			time.Sleep(time.Minute)
+			continue
+		}
+		if interval < time.Minute {
+			interval = time.Minute
+		}
+		time.Sleep(interval)
+		c.fullRebuild(configChangeFeeds)
+	}
+}
+
+func (c *commandeer) feedRefreshInterval() (time.Duration, bool) {
+	if c.Cfg == nil {
+		return 0, false
+	}
+	allModules, ok := c.Cfg.Get("allmodules").(modules.Modules)
+	if !ok {
+		return 0, false
+	}
+
+	var (
+		interval time.Duration
+		found    bool
+	)
+	for _, m := range allModules {
+		for _, mount := range m.Mounts() {
+			if mount.Feed == nil {
+				continue
+			}
+			if d := mount.Feed.RefreshInterval(); !found || d < interval {
+				interval = d
+				found = true
+			}
+		}
+	}
+
+	return interval, found
+}
+
 func (c *commandeer) printChangeDetected(typ string) {
 	msg := "\nChange"
 	if typ != "" {
@@ -892,9 +990,25 @@ func (c *commandeer) printChangeDetected(typ string) {
 	c.logger.Println(time.Now().Format(layout))
 }
 
+// isStylesheetsOrScripts reports whether all of the given files are
+// stylesheets or scripts, which the browser can reload one by one: the
+// stylesheets are replaced in place, as are any scripts marked with the
+// data-hugo-hmr attribute, while other scripts reload the page.
+func isStylesheetsOrScripts(filenames []string) bool {
+	for _, filename := range filenames {
+		switch strings.ToLower(filepath.Ext(filename)) {
+		case ".css", ".js", ".mjs":
+		default:
+			return false
+		}
+	}
+	return true
+}
+
 const (
 	configChangeConfig = "config file"
 	configChangeGoMod  = "go.mod file"
+	configChangeFeeds  = "feeds"
 )
 
 func (c *commandeer) handleEvents(watcher *watcher.Batcher,
@@ -1140,9 +1254,11 @@ func (c *commandeer) handleEvents(watcher *watcher.Batcher,
 				if c.changeDetector != nil && len(changed) == 0 {
 					// Nothing has changed.
 					return
-				} else if len(changed) == 1 {
-					pathToRefresh := c.firstPathSpec().RelURL(helpers.ToSlashTrimLeading(changed[0]), false)
-					livereload.RefreshPath(pathToRefresh)
+				} else if len(changed) == 1 || isStylesheetsOrScripts(changed) {
+					for _, filename := range changed {
+						pathToRefresh := c.firstPathSpec().RelURL(helpers.ToSlashTrimLeading(filename), false)
+						livereload.RefreshPath(pathToRefresh)
+					}
 				} else {
 					livereload.ForceRefresh()
 				}
@@ -14,6 +14,9 @@
 package commands
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	qt "github.com/frankban/quicktest"
@@ -45,3 +48,48 @@ contentDir = "thisdoesnotexist"
 	_, err = cmd.ExecuteC()
 	c.Assert(err, qt.IsNil)
 }
+
+func TestHugoPrecompress(t *testing.T) {
+	c := qt.New(t)
+
+	hugoCmd := newCommandsBuilder().addAll().build()
+	cmd := hugoCmd.getCommand()
+
+	cfgStr := `
+
+baseURL = "https://example.org"
+title = "Hugo Commands"
+
+[precompress]
+encodings = ["gzip"]
+minSize = 10
+
+`
+	dir, clean, err := createSimpleTestSite(t, testSiteConfig{configTOML: cfgStr})
+	c.Assert(err, qt.IsNil)
+	defer clean()
+
+	writeFile(t, filepath.Join(dir, "static", "css", "main.css"), strings.Repeat("body { color: red; }\n", 20))
+	writeFile(t, filepath.Join(dir, "static", "images", "logo.png"), strings.Repeat("png", 20))
+
+	cmd.SetArgs([]string{"-s=" + dir})
+
+	_, err = cmd.ExecuteC()
+	c.Assert(err, qt.IsNil)
+
+	for _, filename := range []string{"public/index.html.gz", "public/p1/index.html.gz", "public/css/main.css.gz"} {
+		_, err := os.Stat(filepath.Join(dir, filepath.FromSlash(filename)))
+		c.Assert(err, qt.IsNil, qt.Commentf(filename))
+	}
+
+	_, err = os.Stat(filepath.Join(dir, "public", "images", "logo.png.gz"))
+	c.Assert(os.IsNotExist(err), qt.IsTrue)
+}
+
+func TestIsStylesheetsOrScripts(t *testing.T) {
+	c := qt.New(t)
+
+	c.Assert(isStylesheetsOrScripts([]string{"/css/main.css", "/js/main.js", "/js/mod.MJS"}), qt.Equals, true)
+	c.Assert(isStylesheetsOrScripts([]string{"/js/main.js", "/index.html"}), qt.Equals, false)
+	c.Assert(isStylesheetsOrScripts([]string{"/images/a.png"}), qt.Equals, false)
+}
@@ -0,0 +1,121 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"os"
+	"strings"
+
+	"github.com/gohugoio/hugo/hugolib"
+	"github.com/spf13/cobra"
+	jww "github.com/spf13/jwalterweatherman"
+)
+
+var _ cmder = (*lintCmd)(nil)
+
+type lintCmd struct {
+	*baseBuilderCmd
+
+	fix bool
+}
+
+func (b *commandsBuilder) newLintCmd() *lintCmd {
+	cc := &lintCmd{}
+
+	cmd := &cobra.Command{
+		Use:   "lint",
+		Short: "Lint your site",
+		Long: `Lint your site.
+
+Lint requires a subcommand, e.g. ` + "`hugo lint frontmatter`.",
+		RunE: nil,
+	}
+
+	frontMatterCmd := &cobra.Command{
+		Use:   "frontmatter",
+		Short: "List deprecated front matter keys",
+		Long: `List the deprecated top level keys in the front matter of your content:
+
+  * _build, which is now build.
+  * Custom params, e.g. author, which are now set in the params map.
+
+With --fix the keys are replaced in the content files. YAML and TOML front
+matter is edited line by line, preserving the formatting and comments.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cc.lintFrontMatter()
+		},
+	}
+
+	frontMatterCmd.Flags().BoolVar(&cc.fix, "fix", false, "replace the deprecated keys in the content files")
+
+	cmd.AddCommand(frontMatterCmd)
+
+	cc.baseBuilderCmd = b.newBuilderBasicCmd(cmd)
+
+	return cc
+}
+
+func (cc *lintCmd) lintFrontMatter() error {
+	c, err := initializeConfig(true, false, &cc.hugoBuilderCommon, cc, nil)
+	if err != nil {
+		return err
+	}
+
+	c.Cfg.Set("buildDrafts", true)
+	c.Cfg.Set("buildFuture", true)
+	c.Cfg.Set("buildExpired", true)
+
+	sites, err := hugolib.NewHugoSites(*c.DepsCfg)
+	if err != nil {
+		return newSystemError("Error creating sites", err)
+	}
+
+	if err := sites.Build(hugolib.BuildCfg{SkipRender: true}); err != nil {
+		return newSystemError("Error Processing Source Content", err)
+	}
+
+	issues, err := sites.LintFrontMatter()
+	if err != nil {
+		return newSystemError("Error linting front matter", err)
+	}
+
+	if len(issues) == 0 {
+		jww.FEEDBACK.Println("No deprecated front matter keys found.")
+		return nil
+	}
+
+	relFilename := func(filename string) string {
+		return strings.TrimPrefix(filename, sites.WorkingDir+string(os.PathSeparator))
+	}
+
+	for _, issue := range issues {
+		jww.FEEDBACK.Printf("%s: %q is deprecated, use %q\n", relFilename(issue.Filename), issue.Key, issue.Replacement())
+	}
+
+	if !cc.fix {
+		return newSystemErrorF("%d deprecated front matter keys found; run with --fix to replace them", len(issues))
+	}
+
+	fixed, err := sites.FixFrontMatter(issues)
+	for _, filename := range fixed {
+		jww.FEEDBACK.Println("Fixed", relFilename(filename))
+	}
+	if err != nil {
+		return newSystemError("Error fixing front matter", err)
+	}
+
+	jww.FEEDBACK.Printf("Fixed %d content files.\n", len(fixed))
+
+	return nil
+}
@@ -0,0 +1,106 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+
+	"github.com/gohugoio/hugo/config/secrets"
+	"github.com/pkg/errors"
+
+	"github.com/spf13/cobra"
+)
+
+var _ cmder = (*secretsCmd)(nil)
+
+type secretsCmd struct {
+	*baseBuilderCmd
+}
+
+func (b *commandsBuilder) newSecretsCmd() *secretsCmd {
+	cc := &secretsCmd{}
+	cmd := &cobra.Command{
+		Use:   "secrets",
+		Short: "Manage the encrypted front matter values",
+		Long: `Manage the encrypted front matter values.
+
+Front matter values that are ASCII armored age messages, starting with
+-----BEGIN AGE ENCRYPTED FILE-----, are decrypted in the build with the age
+identities set in secrets.key, usually with the HUGO_SECRETS_KEY environment
+variable, or in the identity file set in secrets.keyFile, e.g. the one used
+with SOPS.
+
+Values encrypted with the age command line tool, e.g. with
+age -a -r age1..., work the same as those encrypted with hugo secrets encrypt.`,
+		RunE: nil,
+	}
+
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "genkey",
+			Short: "Generate a new age identity",
+			RunE: func(cmd *cobra.Command, args []string) error {
+				key, err := secrets.GenerateKey()
+				if err != nil {
+					return err
+				}
+				fmt.Print(key)
+				return nil
+			},
+		},
+		&cobra.Command{
+			Use:   "encrypt VALUE",
+			Short: "Encrypt a value to the configured identities and recipients",
+			Long: `Encrypt a value to the public keys of the configured identities and
+to the recipients in secrets.recipients, printing the value to use in front
+matter.`,
+			RunE: cc.encrypt,
+		},
+	)
+
+	cc.baseBuilderCmd = b.newBuilderBasicCmd(cmd)
+
+	return cc
+}
+
+func (c *secretsCmd) encrypt(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return errors.New("encrypt requires exactly one value")
+	}
+
+	cfg, err := initializeConfig(true, false, &c.hugoBuilderCommon, c, nil)
+	if err != nil {
+		return err
+	}
+
+	conf, err := secrets.DecodeConfig(cfg.Cfg)
+	if err != nil {
+		return err
+	}
+	d, err := secrets.New(conf, cfg.Fs.Source, cfg.Cfg.GetString("workingDir"))
+	if err != nil {
+		return err
+	}
+	if d == nil {
+		return errors.New("no secrets.key or secrets.keyFile configured; generate a key with hugo secrets genkey")
+	}
+
+	s, err := d.Encrypt(args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Print(s)
+
+	return nil
+}
@@ -0,0 +1,497 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/gohugoio/hugo/config/netpolicy"
+	"github.com/gohugoio/hugo/hugofs"
+	"github.com/gohugoio/hugo/hugolib"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	jww "github.com/spf13/jwalterweatherman"
+)
+
+type serveBuildsCmd struct {
+	// Can be used to stop the server. Useful in tests
+	stop <-chan bool
+
+	serverInterface string
+	serverPort      int
+	token           string
+
+	// Serializes the builds.
+	mu sync.Mutex
+
+	// The warm builders by environment.
+	builders map[string]*envBuilder
+
+	*baseBuilderCmd
+}
+
+func (b *commandsBuilder) newServeBuildsCmd() *serveBuildsCmd {
+	return b.newServeBuildsCmdSignaled(nil)
+}
+
+func (b *commandsBuilder) newServeBuildsCmdSignaled(stop <-chan bool) *serveBuildsCmd {
+	cc := &serveBuildsCmd{stop: stop, builders: make(map[string]*envBuilder)}
+
+	cc.baseBuilderCmd = b.newBuilderCmd(&cobra.Command{
+		Use:   "serve-builds",
+		Short: "Run a build server that keeps the sites warm between builds",
+		Long: `Run a long-running build server that builds the site on request.
+
+The parsed templates, modules and caches are kept in memory between the
+builds, one set per environment, so only the first build for an environment
+pays the full start-up cost. Later builds rebuild the files changed since the
+previous one; a change to the site configuration starts the environment over.
+
+Builds are requested with a POST to /build, which responds with the outcome
+as JSON when the build is done:
+
+    curl -X POST "http://127.0.0.1:1314/build?environment=staging"
+
+Or with:
+
+    hugo serve-builds build -e staging
+
+Add full=true to the request (--full on the command line) to start the
+environment over.`,
+		RunE: cc.serveBuilds,
+	})
+
+	cc.cmd.PersistentFlags().StringVar(&cc.token, "token", "", "require this bearer token in the build requests")
+	cc.cmd.Flags().IntVarP(&cc.serverPort, "port", "p", 1314, "port on which the server will listen")
+	cc.cmd.Flags().StringVarP(&cc.serverInterface, "bind", "", "127.0.0.1", "interface to which the server will bind")
+
+	cc.cmd.AddCommand(cc.newBuildRequestCmd())
+
+	return cc
+}
+
+// envBuilder holds the warm sites for an environment.
+type envBuilder struct {
+	c *commandeer
+
+	// The source files as of the start of the last build.
+	files map[string]fileStamp
+}
+
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// buildResult is the JSON response to a build request.
+type buildResult struct {
+	Environment string   `json:"environment"`
+	Mode        string   `json:"mode"`
+	Changed     []string `json:"changed,omitempty"`
+	DurationMs  int64    `json:"durationMs"`
+	Error       string   `json:"error,omitempty"`
+}
+
+const (
+	buildModeFull        = "full"
+	buildModeIncremental = "incremental"
+	buildModeRerender    = "rerender"
+)
+
+func (sc *serveBuildsCmd) serveBuilds(cmd *cobra.Command, args []string) error {
+	// silence errors in cobra so we can handle them here
+	cmd.SilenceErrors = true
+
+	mu := http.NewServeMux()
+	mu.HandleFunc("/build", sc.handleBuild)
+
+	l, err := net.Listen("tcp", net.JoinHostPort(sc.serverInterface, strconv.Itoa(sc.serverPort)))
+	if err != nil {
+		return newSystemErrorF("Server startup failed: %s", err)
+	}
+
+	srv := &http.Server{Handler: mu}
+	go func() {
+		if err := srv.Serve(l); err != nil && err != http.ErrServerClosed {
+			jww.ERROR.Printf("Error: %s\n", err.Error())
+			os.Exit(1)
+		}
+	}()
+
+	jww.FEEDBACK.Printf("Build server is available at http://%s\n", l.Addr())
+	jww.FEEDBACK.Println("Press Ctrl+C to stop")
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+
+	if sc.stop != nil {
+		select {
+		case <-sigs:
+		case <-sc.stop:
+		}
+	} else {
+		<-sigs
+	}
+
+	srv.Close()
+
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	for _, b := range sc.builders {
+		b.c.hugo().Close()
+	}
+
+	return nil
+}
+
+func (sc *serveBuildsCmd) handleBuild(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if sc.token != "" {
+		expected := []byte("Bearer " + sc.token)
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	query := r.URL.Query()
+	full, _ := strconv.ParseBool(query.Get("full"))
+
+	result := sc.build(query.Get("environment"), full)
+
+	w.Header().Set("Content-Type", "application/json")
+	if result.Error != "" {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	json.NewEncoder(w).Encode(result)
+}
+
+// build builds the sites for the given environment, starting over if full
+// is set or if this is the first build for it.
+func (sc *serveBuildsCmd) build(environment string, full bool) buildResult {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	if environment == "" {
+		environment = sc.getEnvironment(false)
+	}
+
+	start := time.Now()
+	result := buildResult{Environment: environment}
+
+	err := func() error {
+		b := sc.builders[environment]
+
+		var changed []string
+		if b != nil && !full {
+			files, err := b.c.snapshotFiles()
+			if err != nil {
+				return err
+			}
+			changed = changedFiles(b.files, files)
+			b.files = files
+			if b.c.isConfigChange(changed) {
+				full = true
+			}
+		}
+
+		if b == nil || full {
+			if b != nil {
+				b.c.hugo().Close()
+			}
+			delete(sc.builders, environment)
+
+			result.Mode = buildModeFull
+			c, err := sc.newEnvCommandeer(environment)
+			if err != nil {
+				return err
+			}
+			files, err := c.snapshotFiles()
+			if err != nil {
+				return err
+			}
+			if err := c.fullBuild(); err != nil {
+				return err
+			}
+			sc.builders[environment] = &envBuilder{c: c, files: files}
+			return nil
+		}
+
+		c := b.c
+		result.Changed = changed
+
+		var err error
+		if len(changed) == 0 {
+			result.Mode = buildModeRerender
+			err = c.buildSites()
+		} else {
+			result.Mode = buildModeIncremental
+			err = c.rebuildChanged(b.files, changed)
+		}
+		c.wasError = err != nil
+
+		return err
+	}()
+
+	result.DurationMs = time.Since(start).Milliseconds()
+
+	if err != nil {
+		result.Error = err.Error()
+		jww.ERROR.Printf("Build of environment %q failed: %s\n", environment, err)
+	} else {
+		jww.FEEDBACK.Printf("Built environment %q (%s) in %d ms\n", environment, result.Mode, result.DurationMs)
+	}
+
+	return result
+}
+
+func (sc *serveBuildsCmd) newEnvCommandeer(environment string) (*commandeer, error) {
+	h := sc.hugoBuilderCommon
+	h.environment = environment
+
+	cfgInit := func(c *commandeer) error {
+		c.Set("buildServer", true)
+		c.Set("disableLiveReload", true)
+		c.Set("watch", false)
+		return nil
+	}
+
+	// The sites must be running to allow partial rebuilds.
+	return initializeConfig(true, true, &h, sc, cfgInit)
+}
+
+// rebuildChanged rebuilds the sites for the changed files, given the current
+// file snapshot.
+func (c *commandeer) rebuildChanged(files map[string]fileStamp, changed []string) error {
+	var (
+		events        []fsnotify.Event
+		staticChanged bool
+	)
+
+	for _, filename := range changed {
+		if c.hugo().ShouldSkipFileChangeEvent(fsnotify.Event{Name: filename}) {
+			continue
+		}
+
+		if c.hugo().BaseFs.SourceFilesystems.IsStatic(filename) {
+			staticChanged = true
+			continue
+		}
+
+		op := fsnotify.Write
+		if _, found := files[filename]; !found {
+			op = fsnotify.Remove
+		}
+		events = append(events, fsnotify.Event{Name: filename, Op: op})
+	}
+
+	if staticChanged {
+		if _, err := c.copyStatic(); err != nil {
+			return errors.Wrap(err, "Error copying static files")
+		}
+	}
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	return c.rebuildSites(events)
+}
+
+// snapshotFiles returns the size and modification time of the files in the
+// watched directories and the config files.
+func (c *commandeer) snapshotFiles() (map[string]fileStamp, error) {
+	dirs, err := c.getDirList()
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]fileStamp)
+
+	for _, filename := range append(dirs, c.configFiles...) {
+		fi, err := c.Fs.Source.Stat(filename)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		if !fi.IsDir() {
+			files[filename] = fileStamp{modTime: fi.ModTime(), size: fi.Size()}
+			continue
+		}
+
+		fis, err := afero.ReadDir(c.Fs.Source, filename)
+		if err != nil {
+			return nil, err
+		}
+		for _, fi := range fis {
+			if fi.IsDir() {
+				continue
+			}
+			files[filepath.Join(filename, fi.Name())] = fileStamp{modTime: fi.ModTime(), size: fi.Size()}
+		}
+	}
+
+	return files, nil
+}
+
+// isConfigChange reports whether any of the changed files is a config file
+// or in a config dir.
+func (c *commandeer) isConfigChange(changed []string) bool {
+	for _, filename := range changed {
+		for _, configFile := range c.configFiles {
+			if filename == configFile || filepath.Dir(filename) == configFile {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// changedFiles returns the files added, changed or removed from prev to
+// current, sorted.
+func changedFiles(prev, current map[string]fileStamp) []string {
+	var changed []string
+
+	for filename, stamp := range current {
+		if prevStamp, found := prev[filename]; !found || !prevStamp.modTime.Equal(stamp.modTime) || prevStamp.size != stamp.size {
+			changed = append(changed, filename)
+		}
+	}
+
+	for filename := range prev {
+		if _, found := current[filename]; !found {
+			changed = append(changed, filename)
+		}
+	}
+
+	sort.Strings(changed)
+
+	return changed
+}
+
+// loadNetworkPolicy loads the network policy of the site the build is
+// requested for.
+func (sc *serveBuildsCmd) loadNetworkPolicy() (*netpolicy.Policy, error) {
+	dir := sc.source
+	if dir == "" {
+		dir, _ = os.Getwd()
+	} else {
+		dir, _ = filepath.Abs(dir)
+	}
+
+	return hugolib.LoadNetworkPolicy(hugolib.ConfigSourceDescriptor{
+		Fs:           hugofs.Os,
+		Path:         dir,
+		WorkingDir:   dir,
+		Filename:     sc.cfgFile,
+		AbsConfigDir: sc.getConfigDir(dir),
+		Environment:  sc.environment,
+	})
+}
+
+func (sc *serveBuildsCmd) newBuildRequestCmd() *cobra.Command {
+	var (
+		serverURL string
+		full      bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "build",
+		Short: "Request a build from a running build server",
+		Long: `Request a build from a running build server and wait for it to finish.
+
+The build environment is set with --environment, the default is production.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			u, err := url.Parse(serverURL)
+			if err != nil {
+				return err
+			}
+			u.Path = "/build"
+
+			query := url.Values{}
+			if sc.environment != "" {
+				query.Set("environment", sc.environment)
+			}
+			if full {
+				query.Set("full", "true")
+			}
+			u.RawQuery = query.Encode()
+
+			req, err := http.NewRequest(http.MethodPost, u.String(), nil)
+			if err != nil {
+				return err
+			}
+			if sc.token != "" {
+				req.Header.Set("Authorization", "Bearer "+sc.token)
+			}
+
+			policy, err := sc.loadNetworkPolicy()
+			if err != nil {
+				return err
+			}
+
+			resp, err := policy.Client(http.DefaultClient).Do(req)
+			if err != nil {
+				return err
+			}
+			defer resp.Body.Close()
+
+			if resp.Header.Get("Content-Type") != "application/json" {
+				return errors.Errorf("build request failed: %s", resp.Status)
+			}
+
+			var result buildResult
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				return errors.Wrap(err, "failed to decode build result")
+			}
+
+			if result.Error != "" {
+				return errors.Errorf("build of environment %q failed: %s", result.Environment, result.Error)
+			}
+
+			fmt.Printf("Built environment %q (%s) in %d ms\n", result.Environment, result.Mode, result.DurationMs)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&serverURL, "server", "http://127.0.0.1:1314", "URL of the build server")
+	cmd.Flags().BoolVar(&full, "full", false, "start the environment over with a full build")
+
+	return cmd
+}
@@ -0,0 +1,141 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestServeBuilds(t *testing.T) {
+	if isWindowsCI() {
+		t.Skip("Skip server test on appveyor")
+	}
+	c := qt.New(t)
+	dir, clean, err := createSimpleTestSite(t, testSiteConfig{})
+	defer clean()
+	c.Assert(err, qt.IsNil)
+
+	writeFile(t, filepath.Join(dir, "layouts", "_default", "list.html"), `
+Environment: {{ hugo.Environment }}|IsServer: {{ site.IsServer }}|Param: {{ site.Params.myparam }}
+`)
+
+	port := 1333
+
+	stop := make(chan bool)
+
+	b := newCommandsBuilder()
+	scmd := b.newServeBuildsCmdSignaled(stop)
+
+	cmd := scmd.getCommand()
+	cmd.SetArgs([]string{"-s=" + dir, fmt.Sprintf("-p=%d", port), "--token=s3cret"})
+
+	go func() {
+		_, err = cmd.ExecuteC()
+		c.Assert(err, qt.IsNil)
+	}()
+
+	time.Sleep(1 * time.Second)
+
+	build := func(query, authorization string) (int, buildResult) {
+		req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("http://localhost:%d/build?%s", port, query), nil)
+		c.Assert(err, qt.IsNil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		c.Assert(err, qt.IsNil)
+		defer resp.Body.Close()
+		var result buildResult
+		if resp.StatusCode != http.StatusUnauthorized {
+			c.Assert(json.NewDecoder(resp.Body).Decode(&result), qt.IsNil)
+		}
+		return resp.StatusCode, result
+	}
+
+	readFile := func(filename string) string {
+		b, err := ioutil.ReadFile(filepath.Join(dir, "public", filepath.FromSlash(filename)))
+		c.Assert(err, qt.IsNil)
+		return string(b)
+	}
+
+	status, _ := build("", "Bearer wrong")
+	c.Assert(status, qt.Equals, http.StatusUnauthorized)
+
+	status, result := build("", "Bearer s3cret")
+	c.Assert(status, qt.Equals, http.StatusOK)
+	c.Assert(result.Error, qt.Equals, "")
+	c.Assert(result.Environment, qt.Equals, "production")
+	c.Assert(result.Mode, qt.Equals, buildModeFull)
+	c.Assert(readFile("index.html"), qt.Contains, "Environment: production|IsServer: false|Param: paramproduction")
+	c.Assert(readFile("p1/index.html"), qt.Contains, "Single: P1")
+
+	_, result = build("", "Bearer s3cret")
+	c.Assert(result.Mode, qt.Equals, buildModeRerender)
+
+	writeFile(t, filepath.Join(dir, "content", "p1.md"), `
+---
+title: "P1 Edited"
+---
+`)
+
+	_, result = build("environment=production", "Bearer s3cret")
+	c.Assert(result.Error, qt.Equals, "")
+	c.Assert(result.Mode, qt.Equals, buildModeIncremental)
+	c.Assert(result.Changed, qt.DeepEquals, []string{filepath.Join(dir, "content", "p1.md")})
+	c.Assert(readFile("p1/index.html"), qt.Contains, "Single: P1 Edited")
+
+	_, result = build("environment=staging", "Bearer s3cret")
+	c.Assert(result.Mode, qt.Equals, buildModeFull)
+	c.Assert(readFile("index.html"), qt.Contains, "Environment: staging|IsServer: false|Param: paramstaging")
+
+	writeFile(t, filepath.Join(dir, "config", "staging", "params.toml"), `myparam="paramstaging2"`)
+
+	_, result = build("environment=staging", "Bearer s3cret")
+	c.Assert(result.Mode, qt.Equals, buildModeFull)
+	c.Assert(readFile("index.html"), qt.Contains, "Param: paramstaging2")
+
+	stop <- true
+}
+
+func TestChangedFiles(t *testing.T) {
+	c := qt.New(t)
+
+	t1 := time.Now()
+	t2 := t1.Add(time.Second)
+
+	prev := map[string]fileStamp{
+		"a": {modTime: t1, size: 1},
+		"b": {modTime: t1, size: 1},
+		"c": {modTime: t1, size: 1},
+		"d": {modTime: t1, size: 1},
+	}
+
+	current := map[string]fileStamp{
+		"a": {modTime: t1, size: 1},
+		"b": {modTime: t2, size: 1},
+		"c": {modTime: t1, size: 2},
+		"e": {modTime: t1, size: 1},
+	}
+
+	c.Assert(changedFiles(prev, current), qt.DeepEquals, []string{"b", "c", "d", "e"})
+	c.Assert(changedFiles(current, current), qt.IsNil)
+}
@@ -17,6 +17,7 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net"
 	"net/http"
 	"net/url"
@@ -31,6 +32,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/fsnotify/fsnotify"
 	"github.com/gohugoio/hugo/common/paths"
 
 	"github.com/pkg/errors"
@@ -57,6 +59,7 @@ type serverCmd struct {
 	liveReloadPort    int
 	serverWatch       bool
 	noHTTPCache       bool
+	workspace         string
 
 	disableFastRender   bool
 	disableBrowserError bool
@@ -101,6 +104,7 @@ of a second, you will be able to save and see your changes nearly instantly.`,
 	cc.cmd.Flags().BoolVar(&cc.renderToDisk, "renderToDisk", false, "render to Destination path (default is render to memory & serve from there)")
 	cc.cmd.Flags().BoolVar(&cc.disableFastRender, "disableFastRender", false, "enables full re-renders on changes")
 	cc.cmd.Flags().BoolVar(&cc.disableBrowserError, "disableBrowserError", false, "do not show build errors in the browser")
+	cc.cmd.Flags().StringVar(&cc.workspace, "workspace", "", "serve the projects listed in this workspace config file under their path prefixes")
 
 	cc.cmd.Flags().String("memstats", "", "log memory usage to this file")
 	cc.cmd.Flags().String("meminterval", "100ms", "interval to poll memory usage (requires --memstats), valid time units are \"ns\", \"us\" (or \"µs\"), \"ms\", \"s\", \"m\", \"h\".")
@@ -130,6 +134,26 @@ func (f noDirFile) Readdir(count int) ([]os.FileInfo, error) {
 
 var serverPorts []int
 
+// setServerFlags applies the server flags to the config of c.
+func (sc *serverCmd) setServerFlags(cmd *cobra.Command, c *commandeer) {
+	c.Set("renderToMemory", !sc.renderToDisk)
+	if cmd.Flags().Changed("navigateToChanged") {
+		c.Set("navigateToChanged", sc.navigateToChanged)
+	}
+	if cmd.Flags().Changed("disableLiveReload") {
+		c.Set("disableLiveReload", sc.disableLiveReload)
+	}
+	if cmd.Flags().Changed("disableFastRender") {
+		c.Set("disableFastRender", sc.disableFastRender)
+	}
+	if cmd.Flags().Changed("disableBrowserError") {
+		c.Set("disableBrowserError", sc.disableBrowserError)
+	}
+	if sc.serverWatch {
+		c.Set("watch", true)
+	}
+}
+
 func (sc *serverCmd) server(cmd *cobra.Command, args []string) error {
 	// If a Destination is provided via flag write to disk
 	destination, _ := cmd.Flags().GetString("destination")
@@ -137,25 +161,14 @@ func (sc *serverCmd) server(cmd *cobra.Command, args []string) error {
 		sc.renderToDisk = true
 	}
 
+	if sc.workspace != "" {
+		return sc.serveWorkspace(cmd)
+	}
+
 	var serverCfgInit sync.Once
 
 	cfgInit := func(c *commandeer) error {
-		c.Set("renderToMemory", !sc.renderToDisk)
-		if cmd.Flags().Changed("navigateToChanged") {
-			c.Set("navigateToChanged", sc.navigateToChanged)
-		}
-		if cmd.Flags().Changed("disableLiveReload") {
-			c.Set("disableLiveReload", sc.disableLiveReload)
-		}
-		if cmd.Flags().Changed("disableFastRender") {
-			c.Set("disableFastRender", sc.disableFastRender)
-		}
-		if cmd.Flags().Changed("disableBrowserError") {
-			c.Set("disableBrowserError", sc.disableBrowserError)
-		}
-		if sc.serverWatch {
-			c.Set("watch", true)
-		}
+		sc.setServerFlags(cmd, c)
 
 		// TODO(bep) yes, we should fix.
 		if !c.languagesConfigured {
@@ -481,23 +494,11 @@ func (c *commandeer) serve(s *serverCmd) error {
 		roots = []string{""}
 	}
 
-	templ, err := c.hugo().TextTmpl().Parse("__default_server_error", buildErrorTemplate)
+	srv, err := c.newFileServer(s, baseURLs, roots)
 	if err != nil {
 		return err
 	}
 
-	srv := &fileServer{
-		baseURLs: baseURLs,
-		roots:    roots,
-		c:        c,
-		s:        s,
-		errorTemplate: func(ctx interface{}) (io.Reader, error) {
-			b := &bytes.Buffer{}
-			err := c.hugo().Tmpl().Execute(templ, b, ctx)
-			return b, err
-		},
-	}
-
 	doLiveReload := !c.Cfg.GetBool("disableLiveReload")
 
 	if doLiveReload {
@@ -510,15 +511,12 @@ func (c *commandeer) serve(s *serverCmd) error {
 	for i := range baseURLs {
 		mu, serverURL, endpoint, err := srv.createEndpoint(i)
 
-		if doLiveReload {
-			u, err := url.Parse(helpers.SanitizeURL(baseURLs[i]))
-			if err != nil {
-				return err
-			}
-
-			mu.HandleFunc(u.Path+"/livereload.js", livereload.ServeJS)
-			mu.HandleFunc(u.Path+"/livereload", livereload.Handler)
+		u, err := url.Parse(helpers.SanitizeURL(baseURLs[i]))
+		if err != nil {
+			return err
 		}
+
+		c.handleServerEndpoints(mu, u.Path, doLiveReload)
 		jww.FEEDBACK.Printf("Web Server is available at %s (bind address %s)\n", serverURL, s.serverInterface)
 		go func() {
 			err = http.ListenAndServe(endpoint, mu)
@@ -529,6 +527,8 @@ func (c *commandeer) serve(s *serverCmd) error {
 		}()
 	}
 
+	go c.rebuildOnCacheExpiry()
+
 	jww.FEEDBACK.Println("Press Ctrl+C to stop")
 
 	if s.stop != nil {
@@ -545,6 +545,185 @@ func (c *commandeer) serve(s *serverCmd) error {
 	return nil
 }
 
+func (c *commandeer) newFileServer(s *serverCmd, baseURLs, roots []string) (*fileServer, error) {
+	templ, err := c.hugo().TextTmpl().Parse("__default_server_error", buildErrorTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fileServer{
+		baseURLs: baseURLs,
+		roots:    roots,
+		c:        c,
+		s:        s,
+		errorTemplate: func(ctx interface{}) (io.Reader, error) {
+			b := &bytes.Buffer{}
+			err := c.hugo().Tmpl().Execute(templ, b, ctx)
+			return b, err
+		},
+	}, nil
+}
+
+// handleServerEndpoints adds the livereload and /__hugo handlers below path to mu.
+func (c *commandeer) handleServerEndpoints(mu *http.ServeMux, path string, doLiveReload bool) {
+	if doLiveReload {
+		mu.HandleFunc(path+"/livereload.js", livereload.ServeJS)
+		mu.HandleFunc(path+"/livereload", livereload.Handler)
+	}
+
+	// The endpoints triggering rebuilds are only available with a webhook
+	// secret set, so they can't be triggered by anyone reaching the server.
+	if c.webhook.Enabled() {
+		mu.HandleFunc(path+"/__hugo/invalidate", c.handleInvalidate)
+		mu.HandleFunc(path+"/__hugo/rebuild", c.handleRebuild)
+	}
+}
+
+// handleInvalidate invalidates the values cached with the keys given in the
+// key query parameters and rebuilds the sites on requests authenticated as
+// for /__hugo/rebuild, e.g. for a webhook:
+//
+//	curl -X POST -H "Authorization: Bearer $SECRET" http://localhost:1313/__hugo/invalidate?key=products
+func (c *commandeer) handleInvalidate(w http.ResponseWriter, r *http.Request) {
+	if !c.authenticateServerRequest(w, r) {
+		return
+	}
+
+	keys := r.URL.Query()["key"]
+	if len(keys) == 0 {
+		http.Error(w, "no key given", http.StatusBadRequest)
+		return
+	}
+
+	c.invalidateCaches(keys)
+	c.rebuildAndRefresh(fmt.Sprintf("Cache invalidated for %s", strings.Join(keys, ", ")), nil)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleRebuild rebuilds the sites on requests authenticated with the secret
+// in the server webhook config, e.g. from a CMS when content is published.
+// The rebuild can be scoped to the files given in the path query parameters,
+// relative to the working dir, and any keys given as for /__hugo/invalidate
+// are invalidated first:
+//
+//	curl -X POST -H "Authorization: Bearer $SECRET" http://localhost:1313/__hugo/rebuild?path=content/posts/post.md
+func (c *commandeer) handleRebuild(w http.ResponseWriter, r *http.Request) {
+	if !c.authenticateServerRequest(w, r) {
+		return
+	}
+
+	query := r.URL.Query()
+
+	events, err := c.rebuildEvents(query["path"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if keys := query["key"]; len(keys) > 0 {
+		c.invalidateCaches(keys)
+	}
+
+	reason := "Rebuild requested"
+	if len(events) > 0 {
+		reason = fmt.Sprintf("Rebuild of %s requested", strings.Join(query["path"], ", "))
+	}
+
+	if err := c.rebuildAndRefresh(reason, events); err != nil {
+		http.Error(w, "rebuild failed", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// invalidateCaches invalidates the values cached with keys. It takes the
+// rebuild lock so it does not race with a build in progress.
+func (c *commandeer) invalidateCaches(keys []string) {
+	c.rebuildMu.Lock()
+	defer c.rebuildMu.Unlock()
+	c.hugo().InvalidateCaches(keys...)
+}
+
+// authenticateServerRequest checks that r is a POST request, authenticated
+// with the webhook secret, and writes an error response if not.
+func (c *commandeer) authenticateServerRequest(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return false
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return false
+	}
+
+	if !c.webhook.Verify(r.Header.Get("Authorization"), r.Header.Get(c.webhook.SignatureHeader), body) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return false
+	}
+
+	return true
+}
+
+// rebuildEvents creates the events to rebuild the files with the given paths,
+// relative to the working dir.
+func (c *commandeer) rebuildEvents(paths []string) ([]fsnotify.Event, error) {
+	workingDir := c.Cfg.GetString("workingDir")
+
+	var events []fsnotify.Event
+	for _, p := range paths {
+		filename := filepath.Join(workingDir, filepath.FromSlash(p))
+		if rel, err := filepath.Rel(workingDir, filename); err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+			return nil, errors.Errorf("invalid path %q", p)
+		}
+
+		op := fsnotify.Write
+		if _, err := c.Fs.Source.Stat(filename); os.IsNotExist(err) {
+			op = fsnotify.Remove
+		}
+		events = append(events, fsnotify.Event{Name: filename, Op: op})
+	}
+
+	return events, nil
+}
+
+// rebuildOnCacheExpiry rebuilds the sites when a value cached with a TTL
+// expires, e.g. a partial showing remote data.
+func (c *commandeer) rebuildOnCacheExpiry() {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		expiry := c.hugo().Deps.BuildState.CacheExpiry()
+		if expiry.IsZero() || time.Now().Before(expiry) {
+			continue
+		}
+		c.rebuildAndRefresh("Cache expired", nil)
+	}
+}
+
+func (c *commandeer) rebuildAndRefresh(reason string, events []fsnotify.Event) error {
+	c.logger.Printf("\n%s, rebuilding site.", reason)
+
+	err := func() error {
+		defer c.timeTrack(time.Now(), "Total")
+		err := c.rebuildSites(events)
+		if err != nil {
+			c.handleBuildErr(err, "Rebuild failed")
+		}
+		return err
+	}()
+
+	if !c.Cfg.GetBool("disableLiveReload") {
+		livereload.ForceRefresh()
+	}
+
+	return err
+}
+
 // fixURL massages the baseURL into a form needed for serving
 // all pages correctly.
 func (sc *serverCmd) fixURL(cfg config.Provider, s string, port int) (string, error) {
@@ -17,6 +17,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"testing"
@@ -26,6 +27,7 @@ import (
 	"github.com/gohugoio/hugo/helpers"
 
 	qt "github.com/frankban/quicktest"
+	"github.com/spf13/afero"
 )
 
 func TestServer(t *testing.T) {
@@ -71,10 +73,222 @@ func TestServer(t *testing.T) {
 	c.Assert(homeContent, qt.Contains, "List: Hugo Commands")
 	c.Assert(homeContent, qt.Contains, "Environment: development")
 
+	// No webhook secret set, so no rebuilds can be triggered.
+	resp, err = http.Post("http://localhost:1331/__hugo/invalidate?key=foo", "", nil)
+	c.Assert(err, qt.IsNil)
+	resp.Body.Close()
+	c.Assert(resp.StatusCode, qt.Equals, http.StatusNotFound)
+
 	// Stop the server.
 	stop <- true
 }
 
+func TestServerWebhook(t *testing.T) {
+	if isWindowsCI() {
+		t.Skip("Skip server test on appveyor")
+	}
+	c := qt.New(t)
+	dir, clean, err := createSimpleTestSite(t, testSiteConfig{configTOML: `
+baseURL = "https://example.org"
+title = "Hugo Commands"
+
+[server.webhook]
+secret = "s3cret"
+`})
+	defer clean()
+	c.Assert(err, qt.IsNil)
+
+	port := 1332
+
+	stop := make(chan bool)
+
+	b := newCommandsBuilder()
+	scmd := b.newServerCmdSignaled(stop)
+
+	cmd := scmd.getCommand()
+	cmd.SetArgs([]string{"-s=" + dir, fmt.Sprintf("-p=%d", port), "--watch=false", "--disableLiveReload"})
+
+	go func() {
+		_, err = cmd.ExecuteC()
+		c.Assert(err, qt.IsNil)
+	}()
+
+	time.Sleep(2 * time.Second)
+
+	serverURL := fmt.Sprintf("http://localhost:%d", port)
+
+	post := func(path, authorization string) int {
+		req, err := http.NewRequest(http.MethodPost, serverURL+path, nil)
+		c.Assert(err, qt.IsNil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		c.Assert(err, qt.IsNil)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	get := func(path string) string {
+		resp, err := http.Get(serverURL + path)
+		c.Assert(err, qt.IsNil)
+		defer resp.Body.Close()
+		return helpers.ReaderToString(resp.Body)
+	}
+
+	c.Assert(get("/p1/"), qt.Contains, "Single: P1")
+
+	writeFile(t, filepath.Join(dir, "content", "p1.md"), `
+---
+title: "P1 Edited"
+---
+`)
+
+	c.Assert(post("/__hugo/rebuild?path=content/p1.md", ""), qt.Equals, http.StatusUnauthorized)
+	c.Assert(post("/__hugo/rebuild?path=content/p1.md", "Bearer wrong"), qt.Equals, http.StatusUnauthorized)
+	c.Assert(post("/__hugo/invalidate?key=foo", ""), qt.Equals, http.StatusUnauthorized)
+	c.Assert(post("/__hugo/rebuild?path=../p1.md", "Bearer s3cret"), qt.Equals, http.StatusBadRequest)
+	c.Assert(get("/p1/"), qt.Contains, "Single: P1\n")
+
+	c.Assert(post("/__hugo/rebuild?path=content/p1.md", "Bearer s3cret"), qt.Equals, http.StatusNoContent)
+	c.Assert(get("/p1/"), qt.Contains, "Single: P1 Edited")
+	c.Assert(post("/__hugo/invalidate?key=foo", "Bearer s3cret"), qt.Equals, http.StatusNoContent)
+
+	stop <- true
+}
+
+func TestServerWorkspace(t *testing.T) {
+	if isWindowsCI() {
+		t.Skip("Skip server test on appveyor")
+	}
+	c := qt.New(t)
+
+	docs, clean1, err := createSimpleTestSite(t, testSiteConfig{configTOML: `
+baseURL = "https://docs.example.org"
+title = "Docs"
+`})
+	c.Assert(err, qt.IsNil)
+	defer clean1()
+	blog, clean2, err := createSimpleTestSite(t, testSiteConfig{configTOML: `
+baseURL = "https://blog.example.org"
+title = "Blog"
+`})
+	c.Assert(err, qt.IsNil)
+	defer clean2()
+
+	cacheDir := c.TempDir()
+	workspace := filepath.Join(c.TempDir(), "workspace.toml")
+	writeFile(t, workspace, fmt.Sprintf(`
+[[projects]]
+name = "docs"
+source = %q
+[[projects]]
+source = %q
+prefix = "/news"
+`, docs, blog))
+
+	port := 1333
+	stop := make(chan bool)
+
+	b := newCommandsBuilder()
+	scmd := b.newServerCmdSignaled(stop)
+
+	cmd := scmd.getCommand()
+	cmd.SetArgs([]string{"--workspace=" + workspace, "--cacheDir=" + cacheDir, fmt.Sprintf("-p=%d", port)})
+
+	go func() {
+		_, err = cmd.ExecuteC()
+		c.Assert(err, qt.IsNil)
+	}()
+
+	time.Sleep(3 * time.Second)
+
+	get := func(path string) (int, string) {
+		resp, err := http.Get(fmt.Sprintf("http://localhost:%d%s", port, path))
+		c.Assert(err, qt.IsNil)
+		defer resp.Body.Close()
+		return resp.StatusCode, helpers.ReaderToString(resp.Body)
+	}
+
+	_, index := get("/")
+	c.Assert(index, qt.Contains, `<a href="/docs/">docs</a>`)
+	c.Assert(index, qt.Contains, fmt.Sprintf(`<a href="/news/">%s</a>`, filepath.Base(blog)))
+
+	_, home := get("/docs/")
+	c.Assert(home, qt.Contains, "List: Docs")
+	_, home = get("/news/")
+	c.Assert(home, qt.Contains, "List: Blog")
+	_, p1 := get("/news/p1/")
+	c.Assert(p1, qt.Contains, "Single: P1")
+
+	status, _ := get("/docs/livereload.js")
+	c.Assert(status, qt.Equals, http.StatusOK)
+	status, _ = get("/blog/")
+	c.Assert(status, qt.Equals, http.StatusNotFound)
+
+	for _, name := range []string{"docs", filepath.Base(blog)} {
+		fi, err := os.Stat(filepath.Join(cacheDir, "workspace", name))
+		c.Assert(err, qt.IsNil)
+		c.Assert(fi.IsDir(), qt.IsTrue)
+	}
+
+	stop <- true
+}
+
+func TestDecodeWorkspaceConfig(t *testing.T) {
+	c := qt.New(t)
+
+	fs := afero.NewMemMapFs()
+	decode := func(s string) (workspaceConfig, error) {
+		filename := filepath.FromSlash("/ws/workspace.toml")
+		c.Assert(afero.WriteFile(fs, filename, []byte(s), 0666), qt.IsNil)
+		return decodeWorkspaceConfig(fs, filename)
+	}
+
+	conf, err := decode(`
+[[projects]]
+source = "sites/docs"
+[[projects]]
+name = "blog"
+source = "sites/b"
+prefix = "news/latest"
+`)
+	c.Assert(err, qt.IsNil)
+	c.Assert(conf.Projects, qt.DeepEquals, []workspaceProject{
+		{Name: "docs", Source: filepath.FromSlash("/ws/sites/docs"), Prefix: "/docs/"},
+		{Name: "blog", Source: filepath.FromSlash("/ws/sites/b"), Prefix: "/news/latest/"},
+	})
+
+	_, err = decode(``)
+	c.Assert(err, qt.ErrorMatches, ".*no projects")
+	_, err = decode(`
+[[projects]]
+name = "docs"
+`)
+	c.Assert(err, qt.ErrorMatches, ".*project 1: no source")
+	_, err = decode(`
+[[projects]]
+source = "a/docs"
+[[projects]]
+source = "b/docs"
+`)
+	c.Assert(err, qt.ErrorMatches, `.*duplicate project name "docs"`)
+	_, err = decode(`
+[[projects]]
+source = "docs"
+prefix = "/"
+`)
+	c.Assert(err, qt.ErrorMatches, `.*the prefix must not be /`)
+	_, err = decode(`
+[[projects]]
+source = "docs"
+[[projects]]
+source = "api"
+prefix = "/docs/api"
+`)
+	c.Assert(err, qt.ErrorMatches, `.*the prefix "/docs/api/" of project "api" overlaps with "/docs/" of project "docs"`)
+}
+
 func TestFixURL(t *testing.T) {
 	type data struct {
 		TestName   string
@@ -0,0 +1,322 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"html/template"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/gohugoio/hugo/config"
+	"github.com/gohugoio/hugo/helpers"
+	"github.com/gohugoio/hugo/hugofs"
+	"github.com/gohugoio/hugo/livereload"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	jww "github.com/spf13/jwalterweatherman"
+)
+
+// workspaceConfig configures a set of independent Hugo projects served by
+// one hugo server, each below its own path prefix, e.g.:
+//
+//	[[projects]]
+//	name = "docs"
+//	source = "sites/docs"
+//	prefix = "/docs/"
+type workspaceConfig struct {
+	Projects []workspaceProject
+}
+
+type workspaceProject struct {
+	// The project name, used for its cache dir.
+	// Defaults to the base name of Source.
+	Name string
+
+	// The project directory, relative to the workspace config file.
+	Source string
+
+	// The URL path the project is served below. Defaults to /<name>/.
+	Prefix string
+}
+
+func decodeWorkspaceConfig(fs afero.Fs, filename string) (workspaceConfig, error) {
+	var conf workspaceConfig
+
+	m, err := config.FromFileToMap(fs, filename)
+	if err != nil {
+		return conf, err
+	}
+
+	if err := mapstructure.WeakDecode(m, &conf); err != nil {
+		return conf, errors.Wrapf(err, "failed to decode workspace config %q", filename)
+	}
+
+	if len(conf.Projects) == 0 {
+		return conf, errors.Errorf("workspace config %q: no projects", filename)
+	}
+
+	dir := filepath.Dir(filename)
+	names := make(map[string]bool)
+
+	for i, p := range conf.Projects {
+		if p.Source == "" {
+			return conf, errors.Errorf("workspace config %q: project %d: no source", filename, i+1)
+		}
+		if !filepath.IsAbs(p.Source) {
+			p.Source = filepath.Join(dir, filepath.FromSlash(p.Source))
+		}
+		if p.Name == "" {
+			p.Name = filepath.Base(p.Source)
+		}
+		if p.Prefix == "" {
+			p.Prefix = p.Name
+		}
+		p.Prefix = path.Clean("/" + filepath.ToSlash(p.Prefix))
+		if p.Prefix == "/" {
+			return conf, errors.Errorf("workspace config %q: project %q: the prefix must not be /", filename, p.Name)
+		}
+		p.Prefix += "/"
+
+		if names[p.Name] {
+			return conf, errors.Errorf("workspace config %q: duplicate project name %q", filename, p.Name)
+		}
+		names[p.Name] = true
+
+		for _, pp := range conf.Projects[:i] {
+			if strings.HasPrefix(p.Prefix, pp.Prefix) || strings.HasPrefix(pp.Prefix, p.Prefix) {
+				return conf, errors.Errorf("workspace config %q: the prefix %q of project %q overlaps with %q of project %q", filename, p.Prefix, p.Name, pp.Prefix, pp.Name)
+			}
+		}
+
+		conf.Projects[i] = p
+	}
+
+	return conf, nil
+}
+
+// workspaceFlags applies the server flags and a project specific cache dir
+// to the config of a project in a workspace.
+type workspaceFlags struct {
+	*serverCmd
+	cacheDir string
+}
+
+func (f workspaceFlags) flagsToConfig(cfg config.Provider) {
+	f.serverCmd.flagsToConfig(cfg)
+	cfg.Set("cacheDir", f.cacheDir)
+}
+
+// serveWorkspace builds and serves the projects in the workspace config
+// given in the --workspace flag on one port. Every project is built and
+// watched independently with its own caches, and a rebuild of any project
+// reloads the browsers connected to the shared livereload server.
+func (sc *serverCmd) serveWorkspace(cmd *cobra.Command) error {
+	if sc.renderToDisk {
+		return newSystemError("--destination and --renderToDisk are not supported with --workspace")
+	}
+
+	workspace, err := filepath.Abs(sc.workspace)
+	if err != nil {
+		return err
+	}
+
+	conf, err := decodeWorkspaceConfig(hugofs.Os, workspace)
+	if err != nil {
+		return err
+	}
+
+	port := sc.serverPort
+	l, err := net.Listen("tcp", net.JoinHostPort(sc.serverInterface, strconv.Itoa(port)))
+	if err == nil {
+		l.Close()
+	} else {
+		if cmd.Flags().Changed("port") {
+			return newSystemErrorF("Server startup failed: %s", err)
+		}
+		jww.FEEDBACK.Println("port", sc.serverPort, "already in use, attempting to use an available port")
+		sp, err := helpers.FindAvailablePort()
+		if err != nil {
+			return newSystemError("Unable to find alternative port to use:", err)
+		}
+		port = sp.Port
+	}
+
+	cacheRoot, _ := cmd.Flags().GetString("cacheDir")
+	if cacheRoot == "" {
+		cacheRoot = helpers.GetTempDir("hugo_cache", hugofs.Os)
+	}
+
+	cmd.SilenceErrors = true
+
+	var commandeers []*commandeer
+	defer func() {
+		for _, c := range commandeers {
+			c.hugo().Close()
+		}
+	}()
+
+	mu := http.NewServeMux()
+
+	for _, p := range conf.Projects {
+		p := p
+
+		h := sc.hugoBuilderCommon
+		h.source = p.Source
+
+		cfgInit := func(c *commandeer) error {
+			sc.setServerFlags(cmd, c)
+
+			if !c.languagesConfigured {
+				return nil
+			}
+
+			if c.languages.IsMultihost() {
+				return newSystemErrorF("workspace project %q: multihost sites are not supported", p.Name)
+			}
+
+			c.serverPorts = []int{port}
+			c.Set("port", port)
+			if sc.liveReloadPort != -1 {
+				c.Set("liveReloadPort", sc.liveReloadPort)
+			} else {
+				c.Set("liveReloadPort", port)
+			}
+
+			baseURL, err := sc.fixURL(c.Cfg, sc.baseURL, port)
+			if err != nil {
+				return err
+			}
+			u, err := url.Parse(baseURL)
+			if err != nil {
+				return err
+			}
+			u.Path = p.Prefix
+			c.Set("baseURL", u.String())
+
+			return nil
+		}
+
+		flags := workspaceFlags{serverCmd: sc, cacheDir: filepath.Join(cacheRoot, "workspace", p.Name)}
+
+		c, err := initializeConfig(true, true, &h, flags, cfgInit)
+		if err != nil {
+			cmd.PrintErrln("Error:", err.Error())
+			return errors.Wrapf(err, "workspace project %q", p.Name)
+		}
+		commandeers = append(commandeers, c)
+
+		jww.FEEDBACK.Printf("Building project %q from %s\n", p.Name, p.Source)
+
+		err = func() error {
+			defer c.timeTrack(time.Now(), "Built")
+			return c.serverBuild()
+		}()
+		if err != nil {
+			cmd.PrintErrln("Error:", err.Error())
+			return errors.Wrapf(err, "workspace project %q", p.Name)
+		}
+
+		for _, s := range c.hugo().Sites {
+			s.RegisterMediaTypes()
+		}
+
+		if sc.serverWatch {
+			watchDirs, err := c.getDirList()
+			if err != nil {
+				return err
+			}
+			for _, group := range helpers.ExtractAndGroupRootPaths(watchDirs) {
+				jww.FEEDBACK.Printf("Watching for changes in %s\n", group)
+			}
+			watcher, err := c.newWatcher(sc.poll, watchDirs...)
+			if err != nil {
+				return err
+			}
+			defer watcher.Close()
+		}
+
+		srv, err := c.newFileServer(sc, []string{c.hugo().Sites[0].BaseURL.String()}, []string{""})
+		if err != nil {
+			return err
+		}
+		pmu, serverURL, _, err := srv.createEndpoint(0)
+		if err != nil {
+			return err
+		}
+		c.handleServerEndpoints(pmu, strings.TrimSuffix(p.Prefix, "/"), !c.Cfg.GetBool("disableLiveReload"))
+		mu.Handle(p.Prefix, pmu)
+
+		jww.FEEDBACK.Printf("Project %q is available at %s\n", p.Name, serverURL)
+
+		go c.rebuildOnCacheExpiry()
+	}
+
+	livereload.Initialize()
+
+	mu.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := workspaceIndexTemplate.Execute(w, conf); err != nil {
+			jww.ERROR.Println("failed to render workspace index:", err)
+		}
+	})
+
+	endpoint := net.JoinHostPort(sc.serverInterface, strconv.Itoa(port))
+	jww.FEEDBACK.Printf("Workspace is available at http://localhost:%d/ (bind address %s)\n", port, sc.serverInterface)
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- http.ListenAndServe(endpoint, mu)
+	}()
+
+	jww.FEEDBACK.Println("Press Ctrl+C to stop")
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+
+	select {
+	case <-sigs:
+	case <-sc.stop:
+	case err := <-errc:
+		return err
+	}
+
+	return nil
+}
+
+var workspaceIndexTemplate = template.Must(template.New("workspace").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Hugo Workspace</title></head>
+<body>
+<h1>Hugo Workspace</h1>
+<ul>
+{{ range .Projects }}<li><a href="{{ .Prefix }}">{{ .Name }}</a></li>
+{{ end }}</ul>
+</body>
+</html>
+`))
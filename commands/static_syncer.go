@@ -55,7 +55,7 @@ func (s *staticSyncer) syncsStaticEvents(staticEvents []fsnotify.Event) error {
 		syncer.NoChmod = c.Cfg.GetBool("noChmod")
 		syncer.ChmodFilter = chmodFilter
 		syncer.SrcFs = sourceFs.Fs
-		syncer.DestFs = c.Fs.Destination
+		syncer.DestFs = c.hugo().ResourceSpec.Precompress.Fs(c.Fs.Destination)
 
 		// prevent spamming the log on changes
 		logger := helpers.NewDistinctErrorLogger()
@@ -101,7 +101,7 @@ func (s *staticSyncer) syncsStaticEvents(staticEvents []fsnotify.Event) error {
 					toRemove := filepath.Join(publishDir, relPath)
 
 					logger.Println("File no longer exists in static dir, removing", toRemove)
-					_ = c.Fs.Destination.RemoveAll(toRemove)
+					_ = syncer.DestFs.RemoveAll(toRemove)
 				} else if err == nil {
 					// If file still exists, sync it
 					logger.Println("Syncing", relPath, "to", publishDir)
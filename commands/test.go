@@ -0,0 +1,441 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gobwas/glob"
+	"github.com/gohugoio/hugo/hugofs"
+	hglob "github.com/gohugoio/hugo/hugofs/glob"
+	"github.com/gohugoio/hugo/hugolib"
+	"github.com/kylelemons/godebug/diff"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	jww "github.com/spf13/jwalterweatherman"
+)
+
+// The file in the snapshot dir with the glob patterns of the published
+// files to ignore, one per line.
+const snapshotIgnoreFilename = ".snapshotignore"
+
+var _ cmder = (*testCmd)(nil)
+
+type testCmd struct {
+	snapshotDir string
+	update      bool
+	diff        bool
+	ignore      []string
+
+	templateTestDir string
+
+	*baseBuilderCmd
+}
+
+func (b *commandsBuilder) newTestCmd() *testCmd {
+	cc := &testCmd{}
+
+	cc.baseBuilderCmd = b.newBuilderCmd(&cobra.Command{
+		Use:   "test",
+		Short: "Test the rendered site against stored snapshots",
+		Long: `Build the site in memory and compare the published files with the
+snapshots stored in the snapshot dir, "snapshots" in the working dir by
+default. This is useful for theme and module authors wanting to catch
+rendering regressions in CI, e.g. by building an example site:
+
+    hugo test -s exampleSite --themesDir ../..
+
+The command fails if any published file is changed, added or removed
+compared to the snapshots. Run with --diff to see what changed, and with
+--update to write the current output as the new snapshots.
+
+Published files matching any of the glob patterns in --ignore, or in the
+.snapshotignore file in the snapshot dir (one per line), are ignored, e.g.
+"sitemap.xml" or "**.xml".`,
+		RunE: cc.test,
+	})
+
+	cc.cmd.Flags().StringVar(&cc.snapshotDir, "snapshotDir", "snapshots", "the dir with the snapshots, relative to the working dir")
+	cc.cmd.Flags().BoolVar(&cc.update, "update", false, "write the published files as the new snapshots")
+	cc.cmd.Flags().BoolVar(&cc.diff, "diff", false, "print the differences in the changed files")
+	cc.cmd.Flags().StringSliceVar(&cc.ignore, "ignore", nil, "glob patterns of published files to ignore")
+
+	templatesCmd := &cobra.Command{
+		Use:   "templates",
+		Short: "Run the partial and shortcode tests",
+		Long: `Run the tests of the partials and shortcodes in the YAML, TOML and JSON
+files in the test dir, "tests/templates" in the working dir by default.
+
+Each test executes a partial with a fixture context, or a shortcode with
+fixture parameters and inner content, and asserts on the output:
+
+    tests:
+      - name: Card title
+        partial: card.html
+        context:
+          Title: My Card
+        contains: ['<h2 class="card-title">My Card</h2>']
+        notContains: ['<ul class="tags">']
+      - name: Warning note
+        shortcode: note
+        params:
+          type: warning
+        inner: Some text
+        page: /posts/my-post
+      - name: Summary
+        partial: summary.json
+        context:
+          items: [1, 2]
+        json:
+          count: 2
+
+The partial context defaults to the page set in page, or the home page. The
+value returned by a partial using return is compared as JSON.`,
+		RunE: cc.testTemplates,
+	}
+
+	templatesCmd.Flags().StringVar(&cc.templateTestDir, "testDir", "tests/templates", "the dir with the test files, relative to the working dir")
+
+	cc.cmd.AddCommand(templatesCmd)
+
+	return cc
+}
+
+func (tc *testCmd) test(cmd *cobra.Command, args []string) error {
+	cfgInit := func(c *commandeer) error {
+		c.Set("renderToMemory", true)
+		return nil
+	}
+
+	c, err := initializeConfig(true, false, &tc.hugoBuilderCommon, tc, cfgInit)
+	if err != nil {
+		return err
+	}
+
+	if err := c.fullBuild(); err != nil {
+		return err
+	}
+
+	dir := tc.snapshotDir
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(c.Cfg.GetString("workingDir"), dir)
+	}
+
+	ignore, err := loadSnapshotIgnore(hugofs.Os, dir, tc.ignore)
+	if err != nil {
+		return err
+	}
+
+	s := &snapshotter{
+		published: c.destinationFs,
+		snapshots: hugofs.Os,
+		dir:       dir,
+		ignore:    ignore,
+	}
+
+	if tc.update {
+		n, err := s.update()
+		if err != nil {
+			return newSystemError("Error updating snapshots", err)
+		}
+		jww.FEEDBACK.Printf("Updated %d snapshots in %s\n", n, dir)
+		return nil
+	}
+
+	diffs, err := s.compare(tc.diff)
+	if err != nil {
+		return err
+	}
+
+	if len(diffs) == 0 {
+		jww.FEEDBACK.Println("The published files match the snapshots.")
+		return nil
+	}
+
+	for _, d := range diffs {
+		jww.FEEDBACK.Printf("%s: %s\n", d.status, d.path)
+		if d.diff != "" {
+			jww.FEEDBACK.Println(d.diff)
+		}
+	}
+
+	return newSystemErrorF("%d files differ from the snapshots in %s; run with --diff to see the changes or --update to update the snapshots", len(diffs), dir)
+}
+
+func (tc *testCmd) testTemplates(cmd *cobra.Command, args []string) error {
+	c, err := initializeConfig(true, false, &tc.hugoBuilderCommon, tc, nil)
+	if err != nil {
+		return err
+	}
+
+	sites, err := hugolib.NewHugoSites(*c.DepsCfg)
+	if err != nil {
+		return newSystemError("Error creating sites", err)
+	}
+
+	if err := sites.Build(hugolib.BuildCfg{SkipRender: true}); err != nil {
+		return newSystemError("Error Processing Source Content", err)
+	}
+
+	results, err := sites.RunTemplateTests(tc.templateTestDir)
+	if err != nil {
+		return err
+	}
+
+	var failed int
+	for _, r := range results {
+		if r.Passed() {
+			continue
+		}
+		failed++
+		jww.FEEDBACK.Printf("FAIL: %s: %s\n", r.Filename, r.Test.Name)
+		for _, f := range r.Failures {
+			jww.FEEDBACK.Printf("    %s\n", f)
+		}
+		jww.FEEDBACK.Printf("    output: %q\n", r.Output)
+	}
+
+	jww.FEEDBACK.Printf("%d passed, %d failed\n", len(results)-failed, failed)
+
+	if failed > 0 {
+		return newSystemErrorF("%d template tests failed", failed)
+	}
+
+	return nil
+}
+
+const (
+	snapshotChanged = "changed"
+	snapshotAdded   = "added"
+	snapshotRemoved = "removed"
+)
+
+// snapshotDiff describes a published file differing from its snapshot.
+type snapshotDiff struct {
+	path   string
+	status string
+
+	// The line diff for changed text files, if requested.
+	diff string
+}
+
+// snapshotter compares the published files with their snapshots.
+type snapshotter struct {
+	// The published files, relative to the root.
+	published afero.Fs
+
+	// The snapshots, in dir.
+	snapshots afero.Fs
+	dir       string
+
+	ignore []glob.Glob
+}
+
+// loadSnapshotIgnore returns the ignore patterns in the ignore file in dir,
+// if any, and the given patterns.
+func loadSnapshotIgnore(fs afero.Fs, dir string, patterns []string) ([]glob.Glob, error) {
+	b, err := afero.ReadFile(fs, filepath.Join(dir, snapshotIgnoreFilename))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+
+	var globs []glob.Glob
+	for _, pattern := range patterns {
+		g, err := hglob.GetGlob(hglob.NormalizePath(pattern))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid ignore pattern %q", pattern)
+		}
+		globs = append(globs, g)
+	}
+
+	return globs, nil
+}
+
+func (s *snapshotter) isIgnored(path string) bool {
+	for _, g := range s.ignore {
+		if g.Match(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// files returns the files below root in fs, not ignored, with their
+// slash separated paths relative to root.
+func (s *snapshotter) files(fs afero.Fs, root string) (map[string]string, error) {
+	files := make(map[string]string)
+
+	err := afero.Walk(fs, root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if rel == snapshotIgnoreFilename || s.isIgnored(rel) {
+			return nil
+		}
+
+		files[rel] = path
+
+		return nil
+	})
+
+	return files, err
+}
+
+// compare returns the published files differing from their snapshots,
+// sorted by path. With withDiff, the line diffs of the changed text files
+// are included.
+func (s *snapshotter) compare(withDiff bool) ([]snapshotDiff, error) {
+	published, err := s.files(s.published, string(filepath.Separator))
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots, err := s.files(s.snapshots, s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(snapshots) == 0 {
+		return nil, errors.Errorf("no snapshots found in %s; run with --update to create them", s.dir)
+	}
+
+	var diffs []snapshotDiff
+
+	for rel, filename := range published {
+		snapshotFilename, found := snapshots[rel]
+		if !found {
+			diffs = append(diffs, snapshotDiff{path: rel, status: snapshotAdded})
+			continue
+		}
+
+		b1, err := afero.ReadFile(s.snapshots, snapshotFilename)
+		if err != nil {
+			return nil, err
+		}
+		b2, err := afero.ReadFile(s.published, filename)
+		if err != nil {
+			return nil, err
+		}
+
+		if bytes.Equal(b1, b2) {
+			continue
+		}
+
+		d := snapshotDiff{path: rel, status: snapshotChanged}
+		if withDiff {
+			if utf8.Valid(b1) && utf8.Valid(b2) {
+				d.diff = diff.Diff(string(b1), string(b2))
+			} else {
+				d.diff = "Binary files differ"
+			}
+		}
+		diffs = append(diffs, d)
+	}
+
+	for rel := range snapshots {
+		if _, found := published[rel]; !found {
+			diffs = append(diffs, snapshotDiff{path: rel, status: snapshotRemoved})
+		}
+	}
+
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].path < diffs[j].path
+	})
+
+	return diffs, nil
+}
+
+// update writes the published files to the snapshot dir and removes the
+// snapshots of the files no longer published, returning the number of
+// snapshots written or removed. The ignored files are left as is.
+func (s *snapshotter) update() (int, error) {
+	published, err := s.files(s.published, string(filepath.Separator))
+	if err != nil {
+		return 0, err
+	}
+
+	snapshots, err := s.files(s.snapshots, s.dir)
+	if err != nil {
+		return 0, err
+	}
+
+	var n int
+
+	for rel, filename := range published {
+		b, err := afero.ReadFile(s.published, filename)
+		if err != nil {
+			return 0, err
+		}
+
+		if snapshotFilename, found := snapshots[rel]; found {
+			old, err := afero.ReadFile(s.snapshots, snapshotFilename)
+			if err != nil {
+				return 0, err
+			}
+			if bytes.Equal(old, b) {
+				continue
+			}
+		}
+
+		snapshotFilename := filepath.Join(s.dir, filepath.FromSlash(rel))
+		if err := s.snapshots.MkdirAll(filepath.Dir(snapshotFilename), 0777); err != nil {
+			return 0, err
+		}
+		if err := afero.WriteFile(s.snapshots, snapshotFilename, b, 0666); err != nil {
+			return 0, err
+		}
+		n++
+	}
+
+	for rel, filename := range snapshots {
+		if _, found := published[rel]; found {
+			continue
+		}
+		if err := s.snapshots.Remove(filename); err != nil {
+			return 0, err
+		}
+		n++
+	}
+
+	return n, nil
+}
@@ -0,0 +1,140 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gohugoio/hugo/htesting/hqt"
+	"github.com/spf13/afero"
+)
+
+func TestSnapshotter(t *testing.T) {
+	c := qt.New(t)
+
+	published := afero.NewMemMapFs()
+	snapshots := afero.NewMemMapFs()
+
+	write := func(fs afero.Fs, name, content string) {
+		c.Assert(afero.WriteFile(fs, filepath.FromSlash(name), []byte(content), 0666), qt.IsNil)
+	}
+
+	write(published, "/index.html", "<h1>Home</h1>\n")
+	write(published, "/a/index.html", "<h1>A</h1>\n")
+	write(published, "/sitemap.xml", "<urlset/>")
+
+	dir := filepath.FromSlash("/snapshots")
+	write(snapshots, "/snapshots/.snapshotignore", "# Changes on every build.\nsitemap.xml\n")
+
+	ignore, err := loadSnapshotIgnore(snapshots, dir, []string{"**.json"})
+	c.Assert(err, qt.IsNil)
+
+	s := &snapshotter{published: published, snapshots: snapshots, dir: dir, ignore: ignore}
+
+	_, err = s.compare(false)
+	c.Assert(err, qt.ErrorMatches, "no snapshots found.*")
+
+	n, err := s.update()
+	c.Assert(err, qt.IsNil)
+	c.Assert(n, qt.Equals, 2)
+	_, err = snapshots.Stat(filepath.FromSlash("/snapshots/sitemap.xml"))
+	c.Assert(os.IsNotExist(err), qt.IsTrue)
+
+	diffs, err := s.compare(false)
+	c.Assert(err, qt.IsNil)
+	c.Assert(diffs, qt.HasLen, 0)
+
+	write(published, "/a/index.html", "<h1>A changed</h1>\n")
+	write(published, "/b/index.html", "<h1>B</h1>\n")
+	write(published, "/data.json", "{}")
+	write(published, "/sitemap.xml", "<urlset>changed</urlset>")
+	c.Assert(published.Remove(filepath.FromSlash("/index.html")), qt.IsNil)
+
+	diffs, err = s.compare(true)
+	c.Assert(err, qt.IsNil)
+	c.Assert(diffs, qt.CmpEquals(hqt.DeepAllowUnexported(snapshotDiff{})), []snapshotDiff{
+		{path: "a/index.html", status: snapshotChanged, diff: "-<h1>A</h1>\n+<h1>A changed</h1>\n "},
+		{path: "b/index.html", status: snapshotAdded},
+		{path: "index.html", status: snapshotRemoved},
+	})
+
+	n, err = s.update()
+	c.Assert(err, qt.IsNil)
+	c.Assert(n, qt.Equals, 3)
+
+	diffs, err = s.compare(false)
+	c.Assert(err, qt.IsNil)
+	c.Assert(diffs, qt.HasLen, 0)
+}
+
+func TestTestCmd(t *testing.T) {
+	c := qt.New(t)
+
+	dir, clean, err := createSimpleTestSite(t, testSiteConfig{})
+	c.Assert(err, qt.IsNil)
+	defer clean()
+
+	args := []string{"test", "-s=" + dir, "--ignore=**.xml"}
+
+	resp := Execute(args)
+	c.Assert(resp.Err, qt.ErrorMatches, ".*no snapshots found.*")
+
+	resp = Execute(append(args, "--update"))
+	c.Assert(resp.Err, qt.IsNil)
+	c.Assert(readFileFrom(c, filepath.Join(dir, "snapshots", "p1", "index.html")), qt.Contains, "Single: P1")
+	_, err = os.Stat(filepath.Join(dir, "public", "index.html"))
+	c.Assert(os.IsNotExist(err), qt.IsTrue)
+
+	resp = Execute(args)
+	c.Assert(resp.Err, qt.IsNil)
+
+	writeFile(t, filepath.Join(dir, "layouts", "_default", "single.html"), `Single: {{ .Title }}|Changed`)
+
+	resp = Execute(append(args, "--diff"))
+	c.Assert(resp.Err, qt.ErrorMatches, ".*1 files differ from the snapshots.*")
+}
+
+func TestTestTemplatesCmd(t *testing.T) {
+	c := qt.New(t)
+
+	dir, clean, err := createSimpleTestSite(t, testSiteConfig{})
+	c.Assert(err, qt.IsNil)
+	defer clean()
+
+	writeFile(t, filepath.Join(dir, "layouts", "partials", "greeting.html"), `Hello {{ .name }}!`)
+	writeFile(t, filepath.Join(dir, "tests", "templates", "greeting.yaml"), `
+tests:
+  - partial: greeting.html
+    context:
+      name: World
+    contains: [Hello World!]
+`)
+
+	resp := Execute([]string{"test", "templates", "-s=" + dir})
+	c.Assert(resp.Err, qt.IsNil)
+
+	writeFile(t, filepath.Join(dir, "tests", "templates", "greeting.yaml"), `
+tests:
+  - partial: greeting.html
+    context:
+      name: World
+    contains: [Hello Hugo!]
+`)
+
+	resp = Execute([]string{"test", "templates", "-s=" + dir})
+	c.Assert(resp.Err, qt.ErrorMatches, ".*1 template tests failed.*")
+}
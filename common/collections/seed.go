@@ -0,0 +1,37 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collections
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"time"
+)
+
+// SeededPerm returns a pseudo-random permutation of the integers [0,n)
+// determined by seed, which can be any value, e.g. a date or a string.
+// The same seed gives the same permutation.
+func SeededPerm(seed interface{}, n int) []int {
+	var s int64
+	if t, ok := seed.(time.Time); ok {
+		s = t.UnixNano()
+	} else {
+		h := fnv.New64a()
+		fmt.Fprint(h, seed)
+		s = int64(h.Sum64())
+	}
+
+	return rand.New(rand.NewSource(s)).Perm(n)
+}
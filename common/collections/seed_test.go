@@ -0,0 +1,45 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collections
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestSeededPerm(t *testing.T) {
+	c := qt.New(t)
+
+	d1 := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	d2 := d1.Add(24 * time.Hour)
+
+	for _, seed := range []interface{}{"foo", 42, d1} {
+		p := SeededPerm(seed, 20)
+		c.Assert(p, qt.HasLen, 20)
+		c.Assert(SeededPerm(seed, 20), qt.DeepEquals, p)
+
+		sorted := append([]int(nil), p...)
+		sort.Ints(sorted)
+		for i, v := range sorted {
+			c.Assert(v, qt.Equals, i)
+		}
+	}
+
+	c.Assert(SeededPerm("foo", 20), qt.Not(qt.DeepEquals), SeededPerm("bar", 20))
+	c.Assert(SeededPerm(d1, 20), qt.Not(qt.DeepEquals), SeededPerm(d2, 20))
+	c.Assert(SeededPerm("foo", 0), qt.HasLen, 0)
+}
@@ -0,0 +1,299 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hexec
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gohugoio/hugo/common/maps"
+	"github.com/gohugoio/hugo/config"
+	"github.com/gohugoio/hugo/config/netpolicy"
+	"github.com/gohugoio/hugo/config/security"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+)
+
+const (
+	externalToolsConfigKey = "externalTools"
+
+	// The key the Limiter of a build is stored with in the configuration.
+	limiterKey = "externalToolsLimiter"
+)
+
+// Limits limits the runs of an external tool.
+type Limits struct {
+	// The max number of concurrent runs. Any other runs wait for their
+	// turn. 0 means no limit.
+	MaxConcurrency int
+
+	// The max duration of a run, e.g. "2m". Empty means no limit.
+	Timeout string
+
+	// The max heap size in MB of the Node.js based tools, e.g. Babel and
+	// PostCSS, set with --max-old-space-size. 0 means the Node.js default.
+	MaxMemory int
+}
+
+// LimitsConfig configures the limits of the external tools.
+type LimitsConfig struct {
+	// The limits of every tool not set in Tools.
+	Limits `mapstructure:",squash"`
+
+	// The limits per tool, keyed by the name of its executable without
+	// any extension, e.g. "asciidoctor", "pandoc" or "babel". The values
+	// not set default to the values above.
+	Tools map[string]Limits
+}
+
+// DecodeLimitsConfig creates a LimitsConfig from the given configuration.
+func DecodeLimitsConfig(cfg config.Provider) (conf LimitsConfig, err error) {
+	v := cfg.Get(externalToolsConfigKey)
+	if v == nil {
+		return
+	}
+
+	err = mapstructure.WeakDecode(maps.ToStringMap(v), &conf)
+
+	return
+}
+
+// Limiter enforces the limits of the external tools. A nil Limiter limits
+// nothing.
+type Limiter struct {
+	conf LimitsConfig
+
+	mu   sync.Mutex
+	sems map[string]chan struct{}
+}
+
+// NewLimiter creates a new Limiter from the given configuration. It returns
+// nil if the configuration doesn't limit anything.
+func NewLimiter(conf LimitsConfig) (*Limiter, error) {
+	if conf.Limits == (Limits{}) && len(conf.Tools) == 0 {
+		return nil, nil
+	}
+
+	tools := make(map[string]Limits)
+	for name, limits := range conf.Tools {
+		tools[strings.ToLower(name)] = limits
+	}
+	conf.Tools = tools
+
+	check := func(name string, limits Limits) error {
+		if limits.Timeout == "" {
+			return nil
+		}
+		if _, err := time.ParseDuration(limits.Timeout); err != nil {
+			return errors.Wrapf(err, "%s: invalid timeout for %s", externalToolsConfigKey, name)
+		}
+		return nil
+	}
+
+	if err := check("all tools", conf.Limits); err != nil {
+		return nil, err
+	}
+	for name, limits := range conf.Tools {
+		if err := check(name, limits); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Limiter{conf: conf, sems: make(map[string]chan struct{})}, nil
+}
+
+// SetLimiter stores l as the Limiter of the build with the configuration
+// cfg.
+func SetLimiter(cfg config.Provider, l *Limiter) {
+	cfg.Set(limiterKey, l)
+}
+
+// GetLimiter returns the Limiter of the build with the configuration cfg,
+// nil if none.
+func GetLimiter(cfg config.Provider) *Limiter {
+	if cfg == nil {
+		return nil
+	}
+	l, _ := cfg.Get(limiterKey).(*Limiter)
+	return l
+}
+
+// ToolName returns the name the limits of the executable name are
+// configured with, e.g. "babel" for "node_modules/.bin/babel.cmd".
+func ToolName(name string) string {
+	name = strings.ToLower(filepath.Base(name))
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
+
+// Limits returns the limits of the external tool name.
+func (l *Limiter) Limits(name string) Limits {
+	if l == nil {
+		return Limits{}
+	}
+
+	limits := l.conf.Limits
+	tool, found := l.conf.Tools[ToolName(name)]
+	if !found {
+		return limits
+	}
+
+	if tool.MaxConcurrency != 0 {
+		limits.MaxConcurrency = tool.MaxConcurrency
+	}
+	if tool.Timeout != "" {
+		limits.Timeout = tool.Timeout
+	}
+	if tool.MaxMemory != 0 {
+		limits.MaxMemory = tool.MaxMemory
+	}
+
+	return limits
+}
+
+// Acquire waits until the external tool name can be run, or ctx is done.
+// The returned function must be called when the run is done.
+func (l *Limiter) Acquire(ctx context.Context, name string) (func(), error) {
+	limits := l.Limits(name)
+	if limits.MaxConcurrency <= 0 {
+		return func() {}, nil
+	}
+
+	name = ToolName(name)
+	l.mu.Lock()
+	sem, found := l.sems[name]
+	if !found {
+		sem = make(chan struct{}, limits.MaxConcurrency)
+		l.sems[name] = sem
+	}
+	l.mu.Unlock()
+
+	select {
+	case sem <- struct{}{}:
+		return func() { <-sem }, nil
+	case <-ctx.Done():
+		return nil, errors.Wrapf(ctx.Err(), "%s: timed out waiting for a free slot", name)
+	}
+}
+
+// MemoryEnv returns the environment variables limiting the memory of the
+// external tool name, if any.
+func (l *Limiter) MemoryEnv(name string) []string {
+	limits := l.Limits(name)
+	if limits.MaxMemory <= 0 {
+		return nil
+	}
+
+	opts := fmt.Sprintf("--max-old-space-size=%d", limits.MaxMemory)
+	if existing := os.Getenv("NODE_OPTIONS"); existing != "" {
+		opts = existing + " " + opts
+	}
+
+	return []string{"NODE_OPTIONS=" + opts}
+}
+
+// Command creates a command running the external tool name in the
+// directory dir with the network policy, the security policy and the limits
+// of the build with the configuration cfg applied, waiting until it can be
+// run. An empty dir means the current directory. The returned function must
+// be called when the command is done.
+func Command(cfg config.Provider, dir, name string, arg ...string) (*exec.Cmd, func(), error) {
+	return CommandContext(context.Background(), cfg, dir, name, arg...)
+}
+
+// CommandContext is like Command, but the command is also killed when ctx
+// is done.
+func CommandContext(ctx context.Context, cfg config.Provider, dir, name string, arg ...string) (*exec.Cmd, func(), error) {
+	policy := netpolicy.Get(cfg)
+	if err := policy.CheckExec(name); err != nil {
+		return nil, nil, err
+	}
+
+	sec := security.Get(cfg)
+	runDir := dir
+	if runDir == "" {
+		var err error
+		if runDir, err = os.Getwd(); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := CheckExec(cfg, name, arg, runDir); err != nil {
+		return nil, nil, err
+	}
+
+	limiter := GetLimiter(cfg)
+	limits := limiter.Limits(name)
+
+	ctx, cancel := policy.Context(ctx)
+	if limits.Timeout != "" {
+		timeout, _ := time.ParseDuration(limits.Timeout)
+		ctx, cancel = withTimeout(ctx, cancel, timeout)
+	}
+
+	release, err := limiter.Acquire(ctx, name)
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+
+	done := func() {
+		cancel()
+		release()
+	}
+
+	cmd, err := SafeCommandContext(ctx, name, arg...)
+	if err != nil {
+		done()
+		return nil, nil, err
+	}
+	cmd.Dir = dir
+
+	if env := limiter.MemoryEnv(name); env != nil {
+		cmd.Env = append(os.Environ(), env...)
+	}
+
+	if err := sec.Audit(cmd.Path, arg, runDir, nil); err != nil {
+		done()
+		return nil, nil, err
+	}
+
+	return cmd, done, nil
+}
+
+// CheckExec returns an error if the security policy of the build with the
+// configuration cfg doesn't allow running name with the arguments args in
+// the directory dir. Denied executions are written to the audit log.
+func CheckExec(cfg config.Provider, name string, args []string, dir string) error {
+	sec := security.Get(cfg)
+	if err := sec.CheckExec(name, args, dir); err != nil {
+		if auditErr := sec.Audit(name, args, dir, err); auditErr != nil {
+			return auditErr
+		}
+		return err
+	}
+	return nil
+}
+
+func withTimeout(ctx context.Context, cancel context.CancelFunc, timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, cancel2 := context.WithTimeout(ctx, timeout)
+	return ctx, func() {
+		cancel2()
+		cancel()
+	}
+}
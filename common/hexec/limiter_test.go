@@ -0,0 +1,189 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hexec
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gohugoio/hugo/config"
+	"github.com/gohugoio/hugo/config/netpolicy"
+	"github.com/gohugoio/hugo/config/security"
+)
+
+func TestDecodeLimitsConfig(t *testing.T) {
+	c := qt.New(t)
+
+	cfg, err := config.FromConfigString(`
+[externalTools]
+maxConcurrency = 4
+timeout = "2m"
+[externalTools.tools.asciidoctor]
+maxConcurrency = 1
+[externalTools.tools.babel]
+maxMemory = 512
+`, "toml")
+	c.Assert(err, qt.IsNil)
+
+	conf, err := DecodeLimitsConfig(cfg)
+	c.Assert(err, qt.IsNil)
+
+	l, err := NewLimiter(conf)
+	c.Assert(err, qt.IsNil)
+	c.Assert(l.Limits("asciidoctor"), qt.Equals, Limits{MaxConcurrency: 1, Timeout: "2m"})
+	c.Assert(l.Limits("/usr/bin/asciidoctor"), qt.Equals, Limits{MaxConcurrency: 1, Timeout: "2m"})
+	c.Assert(l.Limits("node_modules/.bin/babel.cmd"), qt.Equals, Limits{MaxConcurrency: 4, Timeout: "2m", MaxMemory: 512})
+	c.Assert(l.Limits("pandoc"), qt.Equals, Limits{MaxConcurrency: 4, Timeout: "2m"})
+	c.Assert(l.MemoryEnv("pandoc"), qt.IsNil)
+	c.Assert(l.MemoryEnv("babel")[0], qt.Contains, "--max-old-space-size=512")
+
+	_, err = NewLimiter(LimitsConfig{Tools: map[string]Limits{"pandoc": {Timeout: "soon"}}})
+	c.Assert(err, qt.ErrorMatches, "externalTools: invalid timeout for pandoc.*")
+
+	l, err = NewLimiter(LimitsConfig{})
+	c.Assert(err, qt.IsNil)
+	c.Assert(l, qt.IsNil)
+	c.Assert(l.Limits("pandoc"), qt.Equals, Limits{})
+}
+
+func TestLimiterAcquire(t *testing.T) {
+	c := qt.New(t)
+
+	l, _ := NewLimiter(LimitsConfig{Limits: Limits{MaxConcurrency: 1}})
+
+	release, err := l.Acquire(context.Background(), "pandoc")
+	c.Assert(err, qt.IsNil)
+
+	// Other tools have their own slots.
+	releaseOther, err := l.Acquire(context.Background(), "asciidoctor")
+	c.Assert(err, qt.IsNil)
+	releaseOther()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = l.Acquire(ctx, "pandoc")
+	c.Assert(err, qt.ErrorMatches, "pandoc: timed out waiting for a free slot.*")
+
+	acquired := make(chan bool)
+	go func() {
+		release, err := l.Acquire(context.Background(), "pandoc")
+		c.Check(err, qt.IsNil)
+		release()
+		acquired <- true
+	}()
+
+	release()
+	c.Assert(<-acquired, qt.IsTrue)
+}
+
+func TestCommand(t *testing.T) {
+	c := qt.New(t)
+
+	if _, err := SafeCommand("sleep"); err != nil {
+		c.Skip("sleep not found")
+	}
+
+	cfg := config.New()
+	l, _ := NewLimiter(LimitsConfig{Tools: map[string]Limits{"sleep": {Timeout: "50ms"}}})
+	SetLimiter(cfg, l)
+
+	cmd, done, err := Command(cfg, "", "sleep", "5")
+	c.Assert(err, qt.IsNil)
+	start := time.Now()
+	err = cmd.Run()
+	done()
+	c.Assert(err, qt.Not(qt.IsNil))
+	c.Assert(time.Since(start) < 5*time.Second, qt.IsTrue)
+
+	p, _ := netpolicy.New(netpolicy.Config{DenyAll: true})
+	netpolicy.Set(cfg, p)
+	_, _, err = Command(cfg, "", "sleep", "5")
+	c.Assert(err, qt.ErrorMatches, `network policy: exec "sleep" denied.*`)
+}
+
+func TestCommandSecurityPolicy(t *testing.T) {
+	c := qt.New(t)
+
+	if _, err := SafeCommand("sleep"); err != nil {
+		c.Skip("sleep not available")
+	}
+
+	dir := c.TempDir()
+	wd, err := os.Getwd()
+	c.Assert(err, qt.IsNil)
+
+	cfg := config.New()
+	p, err := security.New(security.Config{Exec: security.Exec{
+		Allow:    []security.ExecRule{{Name: "sleep", Args: []string{`0\.\d+`}}},
+		AuditLog: "audit.log",
+	}}, dir)
+	c.Assert(err, qt.IsNil)
+	security.Set(cfg, p)
+
+	cmd, done, err := Command(cfg, "", "sleep", "0.01")
+	c.Assert(err, qt.IsNil)
+	c.Assert(cmd.Run(), qt.IsNil)
+	done()
+
+	_, _, err = Command(cfg, "", "sleep", "5")
+	c.Assert(err, qt.ErrorMatches, `security: sleep 5 is not allowed by security.exec.allow`)
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, "audit.log"))
+	c.Assert(err, qt.IsNil)
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	c.Assert(lines, qt.HasLen, 2)
+	c.Assert(lines[0], qt.Contains, fmt.Sprintf(`"command":%q,"dir":%q}`, cmd.Path+" 0.01", wd))
+	c.Assert(lines[1], qt.Contains, `"command":"sleep 5"`)
+	c.Assert(lines[1], qt.Contains, `"denied":"security: sleep 5 is not allowed`)
+}
+
+func TestCommandDir(t *testing.T) {
+	c := qt.New(t)
+
+	if _, err := SafeCommand("sleep"); err != nil {
+		c.Skip("sleep not available")
+	}
+
+	workingDir := c.TempDir()
+	subDir := filepath.Join(workingDir, "sub")
+	c.Assert(os.Mkdir(subDir, 0777), qt.IsNil)
+
+	cfg := config.New()
+	p, err := security.New(security.Config{Exec: security.Exec{
+		Allow:    []security.ExecRule{{Name: "sleep", Dir: "sub"}},
+		AuditLog: "audit.log",
+	}}, workingDir)
+	c.Assert(err, qt.IsNil)
+	security.Set(cfg, p)
+
+	cmd, done, err := Command(cfg, subDir, "sleep", "0")
+	c.Assert(err, qt.IsNil)
+	c.Assert(cmd.Dir, qt.Equals, subDir)
+	c.Assert(cmd.Run(), qt.IsNil)
+	done()
+
+	_, _, err = Command(cfg, workingDir, "sleep", "0")
+	c.Assert(err, qt.ErrorMatches, `security: sleep 0 is not allowed by security.exec.allow`)
+
+	b, err := ioutil.ReadFile(filepath.Join(workingDir, "audit.log"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(b), qt.Contains, fmt.Sprintf(`"dir":%q}`, subDir))
+}
@@ -0,0 +1,184 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugo
+
+import (
+	"context"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gohugoio/hugo/common/hexec"
+	"github.com/gohugoio/hugo/config"
+)
+
+// BuildContext holds facts about the environment the site is built in,
+// e.g. the CI provider and the Git revision, available in the templates as
+// hugo.BuildContext.
+type BuildContext struct {
+	// The CI provider, e.g. "github", "gitlab" or "netlify". It is "unknown"
+	// for an unknown provider setting the CI environment variable, and empty
+	// when not built in CI.
+	CI string
+
+	// The Git branch and commit hash, from the CI provider or, if not set,
+	// the Git repository in the working dir.
+	Branch string
+	Commit string
+
+	// Whether the Git working tree has uncommitted changes.
+	Dirty bool
+
+	// The build number set by the CI provider, if any.
+	BuildNumber string
+
+	// The environment variables allowed in the build.env config, e.g.
+	// ["DEPLOY_URL"], with an empty value if not set.
+	Env map[string]string
+}
+
+// IsCI reports whether the site is built in CI.
+func (b BuildContext) IsCI() bool {
+	return b.CI != ""
+}
+
+// ShortCommit returns the abbreviated commit hash.
+func (b BuildContext) ShortCommit() string {
+	if len(b.Commit) > 7 {
+		return b.Commit[:7]
+	}
+	return b.Commit
+}
+
+type ciProvider struct {
+	name string
+
+	// The environment variable set by the provider in its builds.
+	detect string
+
+	// The environment variables holding the branch, the commit hash and the
+	// build number, in order of preference.
+	branch      []string
+	commit      []string
+	buildNumber []string
+}
+
+var ciProviders = []ciProvider{
+	{"github", "GITHUB_ACTIONS", []string{"GITHUB_HEAD_REF", "GITHUB_REF_NAME"}, []string{"GITHUB_SHA"}, []string{"GITHUB_RUN_NUMBER"}},
+	{"gitlab", "GITLAB_CI", []string{"CI_COMMIT_REF_NAME"}, []string{"CI_COMMIT_SHA"}, []string{"CI_PIPELINE_IID"}},
+	{"netlify", "NETLIFY", []string{"HEAD"}, []string{"COMMIT_REF"}, []string{"BUILD_ID"}},
+	{"vercel", "VERCEL", []string{"VERCEL_GIT_COMMIT_REF"}, []string{"VERCEL_GIT_COMMIT_SHA"}, nil},
+	{"cloudflare", "CF_PAGES", []string{"CF_PAGES_BRANCH"}, []string{"CF_PAGES_COMMIT_SHA"}, nil},
+	{"circleci", "CIRCLECI", []string{"CIRCLE_BRANCH"}, []string{"CIRCLE_SHA1"}, []string{"CIRCLE_BUILD_NUM"}},
+	{"travis", "TRAVIS", []string{"TRAVIS_BRANCH"}, []string{"TRAVIS_COMMIT"}, []string{"TRAVIS_BUILD_NUMBER"}},
+	{"azure", "TF_BUILD", []string{"BUILD_SOURCEBRANCHNAME"}, []string{"BUILD_SOURCEVERSION"}, []string{"BUILD_BUILDNUMBER"}},
+	{"bitbucket", "BITBUCKET_BUILD_NUMBER", []string{"BITBUCKET_BRANCH"}, []string{"BITBUCKET_COMMIT"}, []string{"BITBUCKET_BUILD_NUMBER"}},
+	{"buildkite", "BUILDKITE", []string{"BUILDKITE_BRANCH"}, []string{"BUILDKITE_COMMIT"}, []string{"BUILDKITE_BUILD_NUMBER"}},
+	{"jenkins", "JENKINS_URL", []string{"BRANCH_NAME", "GIT_BRANCH"}, []string{"GIT_COMMIT"}, []string{"BUILD_NUMBER"}},
+}
+
+// NewBuildContext creates a BuildContext for a site in workingDir, with the
+// environment variables in envNames. Git is run, with the policies and
+// limits of the build with the configuration execCfg, for the revision facts
+// not set by the CI provider and to check for uncommitted changes.
+func NewBuildContext(execCfg config.Provider, workingDir string, envNames []string) BuildContext {
+	return newBuildContext(os.Getenv, runGit(execCfg, workingDir), envNames)
+}
+
+func newBuildContext(getenv func(string) string, git func(args ...string) (string, bool), envNames []string) BuildContext {
+	var b BuildContext
+
+	first := func(names []string) string {
+		for _, name := range names {
+			if v := getenv(name); v != "" {
+				return v
+			}
+		}
+		return ""
+	}
+
+	for _, p := range ciProviders {
+		if getenv(p.detect) == "" {
+			continue
+		}
+		b.CI = p.name
+		b.Branch = strings.TrimPrefix(first(p.branch), "refs/heads/")
+		b.Commit = first(p.commit)
+		b.BuildNumber = first(p.buildNumber)
+		break
+	}
+
+	if b.CI == "" {
+		if ci := strings.ToLower(getenv("CI")); ci != "" && ci != "false" && ci != "0" {
+			b.CI = "unknown"
+			b.BuildNumber = getenv("BUILD_NUMBER")
+		}
+	}
+
+	if b.Branch == "" {
+		if branch, ok := git("rev-parse", "--abbrev-ref", "HEAD"); ok && branch != "HEAD" {
+			b.Branch = branch
+		}
+	}
+	if b.Commit == "" {
+		b.Commit, _ = git("rev-parse", "HEAD")
+	}
+	if status, ok := git("status", "--porcelain", "--untracked-files=no"); ok {
+		b.Dirty = status != ""
+	}
+
+	if len(envNames) > 0 {
+		b.Env = make(map[string]string, len(envNames))
+		for _, name := range envNames {
+			b.Env[name] = getenv(name)
+		}
+	}
+
+	return b
+}
+
+// runGit returns a func that runs git in dir, returning the trimmed output
+// and whether the command succeeded.
+func runGit(execCfg config.Provider, dir string) func(args ...string) (string, bool) {
+	return func(args ...string) (string, bool) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		cmd, done, err := hexec.CommandContext(ctx, execCfg, dir, "git", args...)
+		if err != nil {
+			return "", false
+		}
+		defer done()
+		out, err := cmd.Output()
+		if err != nil {
+			return "", false
+		}
+		return strings.TrimSpace(string(out)), true
+	}
+}
+
+// lazyBuildContext returns a func that creates the BuildContext on first
+// use, as most sites never use it.
+func lazyBuildContext(execCfg config.Provider, workingDir string, envNames []string) func() BuildContext {
+	var (
+		once sync.Once
+		b    BuildContext
+	)
+	return func() BuildContext {
+		once.Do(func() {
+			b = NewBuildContext(execCfg, workingDir, envNames)
+		})
+		return b
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugo
+
+import (
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestNewBuildContext(t *testing.T) {
+	c := qt.New(t)
+
+	env := func(vars ...string) func(string) string {
+		m := make(map[string]string)
+		for i := 0; i < len(vars); i += 2 {
+			m[vars[i]] = vars[i+1]
+		}
+		return func(key string) string {
+			return m[key]
+		}
+	}
+
+	var gitArgs []string
+	git := func(out map[string]string) func(args ...string) (string, bool) {
+		return func(args ...string) (string, bool) {
+			gitArgs = append(gitArgs, args[0])
+			v, found := out[args[0]]
+			return v, found
+		}
+	}
+
+	c.Run("GitHub", func(c *qt.C) {
+		gitArgs = nil
+		b := newBuildContext(
+			env("GITHUB_ACTIONS", "true", "GITHUB_REF_NAME", "main", "GITHUB_SHA", "0123456789abcdef", "GITHUB_RUN_NUMBER", "42", "DEPLOY_URL", "https://example.org", "SECRET", "s"),
+			git(map[string]string{"status": ""}),
+			[]string{"DEPLOY_URL", "MISSING"},
+		)
+		c.Assert(b, qt.DeepEquals, BuildContext{
+			CI:          "github",
+			Branch:      "main",
+			Commit:      "0123456789abcdef",
+			BuildNumber: "42",
+			Env:         map[string]string{"DEPLOY_URL": "https://example.org", "MISSING": ""},
+		})
+		c.Assert(b.IsCI(), qt.IsTrue)
+		c.Assert(b.ShortCommit(), qt.Equals, "0123456")
+		c.Assert(gitArgs, qt.DeepEquals, []string{"status"})
+	})
+
+	c.Run("Pull request", func(c *qt.C) {
+		b := newBuildContext(
+			env("GITHUB_ACTIONS", "true", "GITHUB_HEAD_REF", "feature", "GITHUB_REF_NAME", "1/merge"),
+			git(nil),
+			nil,
+		)
+		c.Assert(b.Branch, qt.Equals, "feature")
+	})
+
+	c.Run("Local", func(c *qt.C) {
+		gitArgs = nil
+		b := newBuildContext(
+			env(),
+			git(map[string]string{"rev-parse": "main", "status": "M content/p1.md"}),
+			nil,
+		)
+		c.Assert(b.IsCI(), qt.IsFalse)
+		c.Assert(b.Branch, qt.Equals, "main")
+		c.Assert(b.Dirty, qt.IsTrue)
+		c.Assert(strings.Join(gitArgs, " "), qt.Equals, "rev-parse rev-parse status")
+	})
+
+	c.Run("Unknown CI", func(c *qt.C) {
+		b := newBuildContext(env("CI", "true", "BUILD_NUMBER", "7"), git(nil), nil)
+		c.Assert(b.CI, qt.Equals, "unknown")
+		c.Assert(b.BuildNumber, qt.Equals, "7")
+		c.Assert(b.Commit, qt.Equals, "")
+		c.Assert(b.Dirty, qt.IsFalse)
+	})
+
+	c.Run("No Git", func(c *qt.C) {
+		b := NewBuildContext(nil, c.TempDir(), nil)
+		c.Assert(b.Dirty, qt.IsFalse)
+		c.Assert(NewInfo("").BuildContext(), qt.DeepEquals, BuildContext{})
+	})
+}
@@ -57,6 +57,8 @@ type Info struct {
 	// This can also be set by the user.
 	// It can be any string, but it will be all lower case.
 	Environment string
+
+	buildContext func() BuildContext
 }
 
 // Version returns the current version as a comparable version string.
@@ -77,6 +79,15 @@ func (i Info) IsExtended() bool {
 	return IsExtended
 }
 
+// BuildContext returns facts about the environment the site is built in,
+// e.g. the CI provider and the Git branch.
+func (i Info) BuildContext() BuildContext {
+	if i.buildContext == nil {
+		return BuildContext{}
+	}
+	return i.buildContext()
+}
+
 // NewInfo creates a new Hugo Info object.
 func NewInfo(environment string) Info {
 	if environment == "" {
@@ -89,6 +100,15 @@ func NewInfo(environment string) Info {
 	}
 }
 
+// NewInfoWithBuildContext creates a new Hugo Info object with the
+// BuildContext of the site in workingDir, created on first use. See
+// NewBuildContext.
+func NewInfoWithBuildContext(execCfg config.Provider, environment, workingDir string, envNames []string) Info {
+	info := NewInfo(environment)
+	info.buildContext = lazyBuildContext(execCfg, workingDir, envNames)
+	return info
+}
+
 func GetExecEnviron(workDir string, cfg config.Provider, fs afero.Fs) []string {
 	env := os.Environ()
 	nodepath := filepath.Join(workDir, "node_modules")
@@ -18,6 +18,8 @@ import (
 	"fmt"
 	"html/template"
 	"reflect"
+	"regexp"
+	"strconv"
 	"time"
 
 	"github.com/spf13/cast"
@@ -42,6 +44,37 @@ func ToDurationE(v interface{}) (time.Duration, error) {
 	return d, nil
 }
 
+var durationDaysWeeksRe = regexp.MustCompile(`(\d+(?:\.\d*)?|\.\d+)([dw])`)
+
+// ParseDuration parses a duration string as time.ParseDuration does, but
+// also accepts the units "d" for days, 24 hours, and "w" for weeks, 7 days,
+// e.g. "2w3d" or "1.5d".
+func ParseDuration(s string) (time.Duration, error) {
+	var err error
+	ss := durationDaysWeeksRe.ReplaceAllStringFunc(s, func(m string) string {
+		sm := durationDaysWeeksRe.FindStringSubmatch(m)
+		n, perr := strconv.ParseFloat(sm[1], 64)
+		if perr != nil {
+			err = perr
+			return m
+		}
+		hours := n * 24
+		if sm[2] == "w" {
+			hours *= 7
+		}
+		return strconv.FormatFloat(hours, 'f', -1, 64) + "h"
+	})
+	if err != nil {
+		return 0, fmt.Errorf("time: invalid duration %q", s)
+	}
+
+	d, err := time.ParseDuration(ss)
+	if err != nil {
+		return 0, fmt.Errorf("time: invalid duration %q", s)
+	}
+	return d, nil
+}
+
 // ToStringSlicePreserveString is the same as ToStringSlicePreserveStringE,
 // but it never fails.
 func ToStringSlicePreserveString(v interface{}) []string {
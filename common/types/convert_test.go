@@ -47,3 +47,28 @@ func TestToDuration(t *testing.T) {
 	c.Assert(ToDuration("asdfadf"), qt.Equals, time.Duration(0))
 
 }
+
+func TestParseDuration(t *testing.T) {
+	c := qt.New(t)
+
+	for _, test := range []struct {
+		in     string
+		expect time.Duration
+	}{
+		{"200ms", 200 * time.Millisecond},
+		{"1h30m", 90 * time.Minute},
+		{"2d", 48 * time.Hour},
+		{"1.5d", 36 * time.Hour},
+		{"1w2d3h", (7*24 + 2*24 + 3) * time.Hour},
+		{"-2w", -14 * 24 * time.Hour},
+	} {
+		d, err := ParseDuration(test.in)
+		c.Assert(err, qt.IsNil, qt.Commentf(test.in))
+		c.Assert(d, qt.Equals, test.expect, qt.Commentf(test.in))
+	}
+
+	for _, in := range []string{"", "2", "2y", "d", "w3"} {
+		_, err := ParseDuration(in)
+		c.Assert(err, qt.ErrorMatches, `time: invalid duration ".*"`, qt.Commentf(in))
+	}
+}
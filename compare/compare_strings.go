@@ -111,3 +111,121 @@ func compareFold(s, t string) int {
 func LessStrings(s, t string) bool {
 	return Strings(s, t) < 0
 }
+
+// NaturalStrings returns an integer comparing two strings in natural order,
+// i.e. lexicographically, but with runs of digits compared by their numeric
+// value, so "page2" is less than "page10".
+func NaturalStrings(s, t string) int {
+	ss, tt := s, t
+	for ss != "" && tt != "" {
+		var sc, tc string
+		sc, ss = nextNaturalChunk(ss)
+		tc, tt = nextNaturalChunk(tt)
+
+		var c int
+		if isDigit(sc[0]) && isDigit(tc[0]) {
+			c = compareNumbers(sc, tc)
+		} else {
+			c = compareFold(sc, tc)
+		}
+		if c != 0 {
+			return c
+		}
+	}
+
+	if ss == "" && tt == "" {
+		// "a01" and "a1" would be the same so we need a tiebreaker.
+		return Strings(s, t)
+	}
+
+	if ss == "" {
+		return -1
+	}
+
+	return 1
+}
+
+// LessStringsNatural returns whether s is less than t in natural order.
+func LessStringsNatural(s, t string) bool {
+	return NaturalStrings(s, t) < 0
+}
+
+// nextNaturalChunk splits s after its leading run of digits or non-digits.
+func nextNaturalChunk(s string) (string, string) {
+	digits := isDigit(s[0])
+	i := 1
+	for i < len(s) && isDigit(s[i]) == digits {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
+// compareNumbers compares two strings of decimal digits by their value.
+func compareNumbers(s, t string) int {
+	s, t = strings.TrimLeft(s, "0"), strings.TrimLeft(t, "0")
+	if len(s) != len(t) {
+		if len(s) < len(t) {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(s, t)
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+// Levenshtein returns the Levenshtein distance between s and t, i.e. the
+// minimum number of single rune insertions, deletions and substitutions
+// needed to change s into t.
+func Levenshtein(s, t string) int {
+	sr, tr := []rune(s), []rune(t)
+	if len(sr) < len(tr) {
+		sr, tr = tr, sr
+	}
+
+	// The distances from the first i runes of sr to the prefixes of tr.
+	row := make([]int, len(tr)+1)
+	for j := range row {
+		row[j] = j
+	}
+
+	for i := 1; i <= len(sr); i++ {
+		prev := row[0]
+		row[0] = i
+		for j := 1; j <= len(tr); j++ {
+			cost := 1
+			if sr[i-1] == tr[j-1] {
+				cost = 0
+			}
+			next := min3(row[j]+1, row[j-1]+1, prev+cost)
+			prev, row[j] = row[j], next
+		}
+	}
+
+	return row[len(tr)]
+}
+
+// Similarity returns the similarity of s and t, from 0 to 1, as 1 minus
+// their Levenshtein distance relative to the length of the longest.
+func Similarity(s, t string) float64 {
+	n := utf8.RuneCountInString(s)
+	if m := utf8.RuneCountInString(t); m > n {
+		n = m
+	}
+	if n == 0 {
+		return 1
+	}
+	return 1 - float64(Levenshtein(s, t))/float64(n)
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
@@ -62,3 +62,50 @@ func TestLexicographicSort(t *testing.T) {
 
 	c.Assert(s, qt.DeepEquals, []string{"A", "b", "Ba", "ba", "ba", "Bz"})
 }
+
+func TestNaturalSort(t *testing.T) {
+	c := qt.New(t)
+
+	s := []string{"page10", "page2", "Page1", "page02", "chapter 10 part 2", "chapter 9", "chapter 10 part 10", "page", "10", "9", "a1b", "a01b", "page1a"}
+
+	sort.Slice(s, func(i, j int) bool {
+		return LessStringsNatural(s[i], s[j])
+	})
+
+	c.Assert(s, qt.DeepEquals, []string{"9", "10", "a01b", "a1b", "chapter 9", "chapter 10 part 2", "chapter 10 part 10", "page", "Page1", "page1a", "page02", "page2", "page10"})
+
+	c.Assert(NaturalStrings("v1.10.0", "v1.9.3"), qt.Equals, 1)
+	c.Assert(NaturalStrings("img12.jpg", "img12.jpg"), qt.Equals, 0)
+	c.Assert(NaturalStrings("99999999999999999999999", "100000000000000000000000"), qt.Equals, -1)
+}
+
+func TestLevenshtein(t *testing.T) {
+	c := qt.New(t)
+
+	for _, test := range []struct {
+		s, t     string
+		expected int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"", "abc", 3},
+		{"kitten", "sitting", 3},
+		{"sitting", "kitten", 3},
+		{"flaw", "lawn", 2},
+		{"Hugo", "hugo", 1},
+		{"Straße", "Strasse", 2},
+		{"日本語", "日本", 1},
+	} {
+		c.Assert(Levenshtein(test.s, test.t), qt.Equals, test.expected, qt.Commentf("%q %q", test.s, test.t))
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	c := qt.New(t)
+
+	c.Assert(Similarity("", ""), qt.Equals, 1.0)
+	c.Assert(Similarity("hugo", "hugo"), qt.Equals, 1.0)
+	c.Assert(Similarity("abc", "xyz"), qt.Equals, 0.0)
+	c.Assert(Similarity("kitten", "sitting"), qt.Equals, 1-float64(3)/float64(7))
+	c.Assert(Similarity("日本語", "日本"), qt.Equals, 1-float64(1)/float64(3))
+}
@@ -0,0 +1,66 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package attestations configures the build attestations, used to trace the
+// published pages back to the sources and templates they were built from.
+package attestations
+
+import (
+	"strings"
+
+	"github.com/gohugoio/hugo/config"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+)
+
+const attestationsConfigKey = "attestations"
+
+// Config configures the build attestations.
+type Config struct {
+	// Enable to create the attestations.
+	Enable bool
+
+	// How to embed the attestation of a page in its HTML: "comment"
+	// (default) adds an HTML comment at the end, "meta" adds a meta tag
+	// to the head and "none" only adds it to the site attestation.
+	Embed string
+
+	// The site attestation document with the attestations of all the pages,
+	// relative to the publish dir. Default is attestation.json.
+	Filename string
+}
+
+var DefaultConfig = Config{
+	Embed:    "comment",
+	Filename: "attestation.json",
+}
+
+// DecodeConfig creates an attestations Config from the given configuration.
+func DecodeConfig(cfg config.Provider) (Config, error) {
+	c := DefaultConfig
+	m := cfg.GetStringMap(attestationsConfigKey)
+	if m == nil {
+		return c, nil
+	}
+
+	if err := mapstructure.WeakDecode(m, &c); err != nil {
+		return c, errors.Wrap(err, "failed to decode attestations config")
+	}
+
+	c.Embed = strings.ToLower(c.Embed)
+	if c.Embed != "comment" && c.Embed != "meta" && c.Embed != "none" {
+		return c, errors.Errorf("attestations: embed must be \"comment\", \"meta\" or \"none\", got %q", c.Embed)
+	}
+
+	return c, nil
+}
@@ -32,6 +32,8 @@ import (
 var DefaultBuild = Build{
 	UseResourceCacheWhen: "fallback",
 	WriteStats:           false,
+	PageStore:            "memory",
+	PageStoreMemoryLimit: 1000,
 }
 
 // Build holds some build related configuration.
@@ -42,9 +44,48 @@ type Build struct {
 	// related aggregated data (e.g. CSS class names).
 	WriteStats bool
 
+	// When enabled, will write a hugo_manifest.json to the publish dir with
+	// the content hash of every rendered page output, keyed by its relative
+	// permalink.
+	WriteManifest bool
+
+	// When enabled, will write an assets-manifest.json to the publish dir
+	// mapping the logical names of the resources published by the resource
+	// transformations, e.g. "css/main.css", to their final URLs and
+	// integrity hashes.
+	WriteAssetsManifest bool
+
+	// When enabled, will write hugo_asset_report.json and
+	// hugo_asset_report.html with the composition of the bundles built by
+	// js.Build, i.e. the size of every input in every bundle.
+	WriteAssetReport bool
+
 	// Can be used to toggle off writing of the intellinsense /assets/jsconfig.js
 	// file.
 	NoJSConfigInAssets bool
+
+	// When enabled, the results of js.Build and the Sass transformations
+	// are stored in the resource file cache keyed by their inputs, and
+	// reused in later builds, e.g. after a server restart, if none of the
+	// inputs changed.
+	PersistTransformations bool
+
+	// Where to keep the rendered page content, summary and table of
+	// contents: "memory" (default) or "disk". With "disk", they are stored in
+	// the pagestore file cache and only the most recently used are kept in
+	// memory, which keeps the memory usage of very large sites down at some
+	// cost in build time. The page source is always kept in memory. The maxAge
+	// of the pagestore cache sets how long the stored content is reused
+	// across builds (-1, the default, means forever; 0 means never).
+	PageStore string
+
+	// The max number of stored entries to keep in memory when PageStore is
+	// "disk". Default is 1000.
+	PageStoreMemoryLimit int
+
+	// The names of the environment variables available to the templates in
+	// hugo.BuildContext.Env, e.g. ["DEPLOY_URL", "CONTEXT"].
+	Env []string
 }
 
 func (b Build) UseResourceCache(err error) bool {
@@ -77,6 +118,14 @@ func DecodeBuild(cfg Provider) Build {
 		b.UseResourceCacheWhen = "fallback"
 	}
 
+	b.PageStore = strings.ToLower(b.PageStore)
+	if b.PageStore != "disk" {
+		b.PageStore = "memory"
+	}
+	if b.PageStoreMemoryLimit <= 0 {
+		b.PageStoreMemoryLimit = DefaultBuild.PageStoreMemoryLimit
+	}
+
 	return b
 }
 
@@ -85,6 +134,13 @@ type Sitemap struct {
 	ChangeFreq string
 	Priority   float64
 	Filename   string
+
+	// Disable excludes the page from the sitemap.
+	Disable bool
+
+	// The names of the output formats to list in the sitemap. If not set,
+	// the output formats with sitemap = true are listed.
+	Formats []string
 }
 
 func DecodeSitemap(prototype Sitemap, input map[string]interface{}) Sitemap {
@@ -96,6 +152,10 @@ func DecodeSitemap(prototype Sitemap, input map[string]interface{}) Sitemap {
 			prototype.Priority = cast.ToFloat64(value)
 		case "filename":
 			prototype.Filename = cast.ToString(value)
+		case "disable":
+			prototype.Disable = cast.ToBool(value)
+		case "formats":
+			prototype.Formats = cast.ToStringSlice(value)
 		default:
 			jww.WARN.Printf("Unknown Sitemap field: %s\n", key)
 		}
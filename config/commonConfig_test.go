@@ -42,6 +42,18 @@ func TestBuild(t *testing.T) {
 	b = DecodeBuild(v)
 
 	c.Assert(b.UseResourceCacheWhen, qt.Equals, "fallback")
+	c.Assert(b.PageStore, qt.Equals, "memory")
+	c.Assert(b.PageStoreMemoryLimit, qt.Equals, 1000)
+
+	v.Set("build", map[string]interface{}{
+		"pageStore":            "Disk",
+		"pageStoreMemoryLimit": 20,
+	})
+
+	b = DecodeBuild(v)
+
+	c.Assert(b.PageStore, qt.Equals, "disk")
+	c.Assert(b.PageStoreMemoryLimit, qt.Equals, 20)
 
 	c.Assert(b.UseResourceCache(herrors.ErrFeatureNotAvailable), qt.Equals, true)
 	c.Assert(b.UseResourceCache(errors.New("err")), qt.Equals, false)
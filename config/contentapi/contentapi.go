@@ -0,0 +1,63 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package contentapi configures the JSON documents rendered by the
+// ContentAPI output format.
+package contentapi
+
+import (
+	"github.com/gohugoio/hugo/config"
+	"github.com/spf13/cast"
+	jww "github.com/spf13/jwalterweatherman"
+)
+
+const contentAPIConfigKey = "contentAPI"
+
+// Config configures the JSON documents rendered by the ContentAPI output
+// format.
+type Config struct {
+	// The fields to include in a page document. Any name not known as a
+	// page field will be looked up in the page params.
+	Fields []string
+
+	// The fields to include for every page listed in a collection.
+	ListFields []string
+
+	// The number of pages per collection page. If not set, the site's
+	// paginate setting is used.
+	PageSize int
+}
+
+var DefaultConfig = Config{
+	Fields:     []string{"kind", "type", "section", "title", "date", "lastmod", "permalink", "summary", "content", "params"},
+	ListFields: []string{"title", "date", "permalink", "summary"},
+}
+
+// DecodeConfig creates a content API Config from the given configuration.
+func DecodeConfig(cfg config.Provider) Config {
+	c := DefaultConfig
+	for key, value := range cfg.GetStringMap(contentAPIConfigKey) {
+		switch key {
+		case "fields":
+			c.Fields = cast.ToStringSlice(value)
+		case "listfields":
+			c.ListFields = cast.ToStringSlice(value)
+		case "pagesize":
+			c.PageSize = cast.ToInt(value)
+		default:
+			jww.WARN.Printf("Unknown ContentAPI field: %s\n", key)
+		}
+	}
+
+	return c
+}
@@ -0,0 +1,65 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package llms configures the llms.txt and llms-full.txt files to be
+// generated.
+package llms
+
+import (
+	"github.com/gohugoio/hugo/config"
+	"github.com/spf13/cast"
+	jww "github.com/spf13/jwalterweatherman"
+)
+
+const llmsConfigKey = "llms"
+
+// Config configures the llms.txt and llms-full.txt files.
+type Config struct {
+	// The filename of the page index. Default is llms.txt.
+	Filename string
+
+	// The filename of the full content variant. Default is llms-full.txt.
+	FullFilename string
+
+	// Whether to also render the full content variant.
+	Full bool
+
+	// If set, only list regular pages in these top level sections.
+	Sections []string
+}
+
+var DefaultConfig = Config{
+	Filename:     "llms.txt",
+	FullFilename: "llms-full.txt",
+}
+
+// DecodeConfig creates a llms Config from the given configuration.
+func DecodeConfig(cfg config.Provider) Config {
+	c := DefaultConfig
+	for key, value := range cfg.GetStringMap(llmsConfigKey) {
+		switch key {
+		case "filename":
+			c.Filename = cast.ToString(value)
+		case "fullfilename":
+			c.FullFilename = cast.ToString(value)
+		case "full":
+			c.Full = cast.ToBool(value)
+		case "sections":
+			c.Sections = cast.ToStringSlice(value)
+		default:
+			jww.WARN.Printf("Unknown LLMs field: %s\n", key)
+		}
+	}
+
+	return c
+}
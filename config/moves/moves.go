@@ -0,0 +1,62 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package moves configures the detection of moved pages.
+package moves
+
+import (
+	"github.com/gohugoio/hugo/config"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+)
+
+const movesConfigKey = "moves"
+
+// Config configures the detection of moved pages.
+type Config struct {
+	// Enable to compare the pages with the ones in the previous build and
+	// log the pages that look moved, with the aliases to add for their old
+	// paths.
+	Detect bool
+
+	// The minimum content similarity, between 0 and 1, for a page to be
+	// considered moved. Pages with the same ID are always considered moved.
+	Threshold float64
+
+	// Enable to add the suggested aliases to the front matter of the moved
+	// pages.
+	WriteAliases bool
+}
+
+var DefaultConfig = Config{
+	Threshold: 0.8,
+}
+
+// DecodeConfig creates a moves Config from the given configuration.
+func DecodeConfig(cfg config.Provider) (Config, error) {
+	c := DefaultConfig
+	m := cfg.GetStringMap(movesConfigKey)
+	if m == nil {
+		return c, nil
+	}
+
+	if err := mapstructure.WeakDecode(m, &c); err != nil {
+		return c, errors.Wrap(err, "failed to decode moves config")
+	}
+
+	if c.Threshold <= 0 || c.Threshold > 1 {
+		return c, errors.Errorf("moves: threshold must be > 0 and <= 1, got %v", c.Threshold)
+	}
+
+	return c, nil
+}
@@ -0,0 +1,424 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package netpolicy restricts the network access in builds, i.e. remote
+// resources, module downloads and external tools.
+package netpolicy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gobwas/glob"
+	"github.com/gohugoio/hugo/common/maps"
+	"github.com/gohugoio/hugo/config"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+)
+
+const (
+	networkPolicyConfigKey = "networkPolicy"
+
+	// The key the Policy of a build is stored with in the configuration.
+	policyKey = "networkPolicyInstance"
+)
+
+// The kinds of violations.
+const (
+	KindHTTP     = "http"
+	KindModule   = "module"
+	KindExec     = "exec"
+	KindDownload = "download"
+)
+
+// Config configures the network policy of a build.
+type Config struct {
+	// Deny all network access.
+	DenyAll bool
+
+	// If set, only the hosts matching any of these Glob patterns, e.g.
+	// "*.example.org", can be accessed.
+	AllowHosts []string
+
+	// The external tools, e.g. "postcss", that can be run when the network
+	// access is restricted, i.e. with denyAll or allowHosts set. Any tool
+	// can be run otherwise.
+	AllowExec []string
+
+	// The max total size in bytes of the remote resources downloaded in a
+	// build. 0 means no limit.
+	MaxDownloadSize int64
+
+	// The max duration of a remote request or an external tool run, e.g.
+	// "30s". Empty means no limit.
+	Timeout string
+
+	// If set, the violations in a build are written as JSON to this file,
+	// relative to the working dir.
+	Report string
+}
+
+// DecodeConfig creates a network policy Config from the given configuration.
+func DecodeConfig(cfg config.Provider) (conf Config, err error) {
+	v := cfg.Get(networkPolicyConfigKey)
+	if v == nil {
+		return
+	}
+
+	err = mapstructure.WeakDecode(maps.ToStringMap(v), &conf)
+
+	return
+}
+
+// Violation is an attempt to access the network denied by the policy.
+type Violation struct {
+	Kind   string `json:"kind"`
+	Target string `json:"target"`
+	Reason string `json:"reason"`
+}
+
+func (v Violation) Error() string {
+	return fmt.Sprintf("network policy: %s %q denied: %s", v.Kind, v.Target, v.Reason)
+}
+
+// Policy enforces a network policy Config. A nil Policy allows everything.
+type Policy struct {
+	conf Config
+
+	allowHosts []glob.Glob
+	allowExec  []glob.Glob
+	timeout    time.Duration
+
+	downloaded int64
+
+	mu         sync.Mutex
+	violations []Violation
+}
+
+// New creates a new Policy from the given configuration. It returns nil if
+// the configuration doesn't restrict anything.
+func New(conf Config) (*Policy, error) {
+	if !conf.DenyAll && len(conf.AllowHosts) == 0 && conf.MaxDownloadSize == 0 && conf.Timeout == "" {
+		return nil, nil
+	}
+
+	p := &Policy{conf: conf}
+
+	compile := func(patterns []string) ([]glob.Glob, error) {
+		var globs []glob.Glob
+		for _, pattern := range patterns {
+			g, err := glob.Compile(strings.ToLower(pattern))
+			if err != nil {
+				return nil, errors.Wrapf(err, "%s: invalid pattern %q", networkPolicyConfigKey, pattern)
+			}
+			globs = append(globs, g)
+		}
+		return globs, nil
+	}
+
+	var err error
+	if p.allowHosts, err = compile(conf.AllowHosts); err != nil {
+		return nil, err
+	}
+	if p.allowExec, err = compile(conf.AllowExec); err != nil {
+		return nil, err
+	}
+
+	if conf.Timeout != "" {
+		if p.timeout, err = time.ParseDuration(conf.Timeout); err != nil {
+			return nil, errors.Wrapf(err, "%s: invalid timeout", networkPolicyConfigKey)
+		}
+	}
+
+	return p, nil
+}
+
+// Set stores p as the Policy of the build with the configuration cfg.
+func Set(cfg config.Provider, p *Policy) {
+	cfg.Set(policyKey, p)
+}
+
+// Get returns the Policy of the build with the configuration cfg, nil if
+// none.
+func Get(cfg config.Provider) *Policy {
+	if cfg == nil {
+		return nil
+	}
+	p, _ := cfg.Get(policyKey).(*Policy)
+	return p
+}
+
+// Config returns the configuration of p.
+func (p *Policy) Config() Config {
+	if p == nil {
+		return Config{}
+	}
+	return p.conf
+}
+
+func (p *Policy) restricted() bool {
+	return p.conf.DenyAll || len(p.conf.AllowHosts) > 0
+}
+
+func (p *Policy) violation(kind, target, reason string) error {
+	v := Violation{Kind: kind, Target: target, Reason: reason}
+	p.mu.Lock()
+	p.violations = append(p.violations, v)
+	p.mu.Unlock()
+	return v
+}
+
+// CheckHost returns an error if host can not be accessed for kind, e.g.
+// KindHTTP.
+func (p *Policy) CheckHost(kind, host string) error {
+	if p == nil {
+		return nil
+	}
+	if p.conf.DenyAll {
+		return p.violation(kind, host, "all network access is denied")
+	}
+	if p.allowsHost(host) {
+		return nil
+	}
+	return p.violation(kind, host, "host not in allowHosts")
+}
+
+// allowsHost reports whether host matches allowHosts, if set.
+func (p *Policy) allowsHost(host string) bool {
+	if len(p.allowHosts) == 0 {
+		return true
+	}
+	host = strings.ToLower(host)
+	for _, g := range p.allowHosts {
+		if g.Match(host) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckModule returns an error if the Go module with the given path can not
+// be downloaded.
+func (p *Policy) CheckModule(path string) error {
+	if p == nil {
+		return nil
+	}
+	host := strings.SplitN(path, "/", 2)[0]
+	if err := p.CheckHost(KindModule, host); err != nil {
+		return errors.Wrapf(err, "failed to download module %q", path)
+	}
+	return nil
+}
+
+// GoEnv returns the environment variables, as key/value pairs, restricting
+// the module downloads of the go command with the given GOPROXY and GOSUMDB
+// to the allowed hosts: the proxies not allowed are removed from GOPROXY,
+// GOVCS only allows fetching directly from the allowed hosts, and the
+// checksum database is turned off if its host is not allowed.
+func (p *Policy) GoEnv(goproxy, gosumdb string) []string {
+	if p == nil || !p.restricted() {
+		return nil
+	}
+	if p.conf.DenyAll {
+		return []string{"GOPROXY", "off"}
+	}
+
+	var proxies []string
+	for _, proxy := range strings.FieldsFunc(goproxy, func(r rune) bool { return r == ',' || r == '|' }) {
+		if proxy == "direct" || proxy == "off" {
+			proxies = append(proxies, proxy)
+			continue
+		}
+		if u, err := url.Parse(proxy); err == nil && (u.Scheme == "file" || p.allowsHost(u.Hostname())) {
+			proxies = append(proxies, proxy)
+		}
+	}
+	if len(proxies) == 0 {
+		proxies = []string{"off"}
+	}
+
+	vcs := make([]string, 0, len(p.conf.AllowHosts)+1)
+	for _, host := range p.conf.AllowHosts {
+		vcs = append(vcs, strings.ToLower(host)+":all")
+	}
+	vcs = append(vcs, "*:off")
+
+	env := []string{
+		"GOPROXY", strings.Join(proxies, ","),
+		"GOVCS", strings.Join(vcs, ","),
+	}
+
+	if host := sumdbHost(gosumdb); host != "" && !p.allowsHost(host) {
+		env = append(env, "GOSUMDB", "off")
+	}
+
+	return env
+}
+
+// sumdbHost returns the host of the checksum database set with GOSUMDB,
+// e.g. "sum.golang.org+<publickey> https://sum.example.org", empty if off.
+func sumdbHost(gosumdb string) string {
+	if gosumdb == "" {
+		gosumdb = "sum.golang.org"
+	}
+	fields := strings.Fields(gosumdb)
+	if fields[0] == "off" {
+		return ""
+	}
+	if len(fields) > 1 {
+		if u, err := url.Parse(fields[1]); err == nil {
+			return u.Hostname()
+		}
+	}
+	return strings.SplitN(fields[0], "+", 2)[0]
+}
+
+// CheckExec returns an error if the external tool name can not be run.
+// The go command is always allowed, its module downloads are restricted
+// with the environment from GoEnv.
+func (p *Policy) CheckExec(name string) error {
+	if p == nil || !p.restricted() {
+		return nil
+	}
+	lname := strings.ToLower(name)
+	if base := filepath.Base(lname); base == "go" || base == "go.exe" {
+		return nil
+	}
+	for _, g := range p.allowExec {
+		if g.Match(lname) {
+			return nil
+		}
+	}
+	return p.violation(KindExec, name, "external tool not in allowExec")
+}
+
+// Context returns a context with the timeout of p applied.
+func (p *Policy) Context(ctx context.Context) (context.Context, context.CancelFunc) {
+	if p == nil || p.timeout == 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, p.timeout)
+}
+
+// Client returns a copy of c enforcing p.
+func (p *Policy) Client(c *http.Client) *http.Client {
+	if p == nil {
+		return c
+	}
+
+	cc := *c
+	cc.Transport = p.Transport(c.Transport)
+	if p.timeout != 0 && (cc.Timeout == 0 || p.timeout < cc.Timeout) {
+		cc.Timeout = p.timeout
+	}
+	return &cc
+}
+
+// Transport returns a RoundTripper enforcing p with next, or
+// http.DefaultTransport if nil, doing the requests allowed.
+func (p *Policy) Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if p == nil {
+		return next
+	}
+	return &policyTransport{p: p, next: next}
+}
+
+// Violations returns the violations recorded since the last Reset.
+func (p *Policy) Violations() []Violation {
+	if p == nil {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	violations := make([]Violation, len(p.violations))
+	copy(violations, p.violations)
+	sort.SliceStable(violations, func(i, j int) bool {
+		if violations[i].Kind != violations[j].Kind {
+			return violations[i].Kind < violations[j].Kind
+		}
+		return violations[i].Target < violations[j].Target
+	})
+	return violations
+}
+
+// Reset resets the violations and the total download size, e.g. before a
+// rebuild.
+func (p *Policy) Reset() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	p.violations = nil
+	p.mu.Unlock()
+	atomic.StoreInt64(&p.downloaded, 0)
+}
+
+func (p *Policy) addDownloaded(target string, n int) error {
+	if p.conf.MaxDownloadSize == 0 {
+		return nil
+	}
+	if atomic.AddInt64(&p.downloaded, int64(n)) > p.conf.MaxDownloadSize {
+		return p.violation(KindDownload, target, fmt.Sprintf("exceeds maxDownloadSize of %d bytes", p.conf.MaxDownloadSize))
+	}
+	return nil
+}
+
+type policyTransport struct {
+	p    *Policy
+	next http.RoundTripper
+}
+
+func (t *policyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.p.CheckHost(KindHTTP, req.URL.Hostname()); err != nil {
+		return nil, err
+	}
+	res, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	res.Body = &countingBody{ReadCloser: res.Body, p: t.p, target: req.URL.String()}
+	return res, nil
+}
+
+// countingBody adds the bytes read to the total download size.
+type countingBody struct {
+	io.ReadCloser
+	p      *Policy
+	target string
+	err    error
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	if b.err != nil {
+		return 0, b.err
+	}
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		if b.err = b.p.addDownloaded(b.target, n); b.err != nil {
+			return n, b.err
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,171 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package netpolicy
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gohugoio/hugo/config"
+)
+
+func TestDecodeConfig(t *testing.T) {
+	c := qt.New(t)
+
+	tomlConfig := `
+[networkPolicy]
+denyAll = true
+allowHosts = ["*.example.org"]
+allowExec = ["postcss"]
+maxDownloadSize = 1000
+timeout = "30s"
+report = "network.json"
+`
+	cfg, err := config.FromConfigString(tomlConfig, "toml")
+	c.Assert(err, qt.IsNil)
+
+	conf, err := DecodeConfig(cfg)
+	c.Assert(err, qt.IsNil)
+	c.Assert(conf, qt.DeepEquals, Config{
+		DenyAll:         true,
+		AllowHosts:      []string{"*.example.org"},
+		AllowExec:       []string{"postcss"},
+		MaxDownloadSize: 1000,
+		Timeout:         "30s",
+		Report:          "network.json",
+	})
+
+	p, err := New(conf)
+	c.Assert(err, qt.IsNil)
+	c.Assert(p.timeout.String(), qt.Equals, "30s")
+
+	_, err = New(Config{Timeout: "soon"})
+	c.Assert(err, qt.ErrorMatches, "networkPolicy: invalid timeout.*")
+
+	p, err = New(Config{})
+	c.Assert(err, qt.IsNil)
+	c.Assert(p, qt.IsNil)
+}
+
+func TestPolicy(t *testing.T) {
+	c := qt.New(t)
+
+	c.Run("Nil", func(c *qt.C) {
+		var p *Policy
+		c.Assert(p.CheckHost(KindHTTP, "example.org"), qt.IsNil)
+		c.Assert(p.CheckModule("github.com/gohugoio/hugo"), qt.IsNil)
+		c.Assert(p.CheckExec("postcss"), qt.IsNil)
+		c.Assert(p.Client(http.DefaultClient), qt.Equals, http.DefaultClient)
+		c.Assert(p.Violations(), qt.IsNil)
+	})
+
+	c.Run("Deny all", func(c *qt.C) {
+		p, _ := New(Config{DenyAll: true, AllowExec: []string{"post*"}})
+		c.Assert(p.CheckHost(KindHTTP, "example.org"), qt.ErrorMatches, `network policy: http "example.org" denied: all network access is denied`)
+		c.Assert(p.CheckModule("github.com/gohugoio/hugo"), qt.ErrorMatches, `failed to download module "github.com/gohugoio/hugo": network policy: module "github.com" denied.*`)
+		c.Assert(p.CheckExec("postcss"), qt.IsNil)
+		c.Assert(p.CheckExec("babel"), qt.ErrorMatches, `network policy: exec "babel" denied: external tool not in allowExec`)
+		c.Assert(p.Violations(), qt.DeepEquals, []Violation{
+			{Kind: KindExec, Target: "babel", Reason: "external tool not in allowExec"},
+			{Kind: KindHTTP, Target: "example.org", Reason: "all network access is denied"},
+			{Kind: KindModule, Target: "github.com", Reason: "all network access is denied"},
+		})
+		p.Reset()
+		c.Assert(p.Violations(), qt.HasLen, 0)
+	})
+
+	c.Run("Allow hosts", func(c *qt.C) {
+		p, _ := New(Config{AllowHosts: []string{"*.example.org", "github.com"}})
+		c.Assert(p.CheckHost(KindHTTP, "API.example.org"), qt.IsNil)
+		c.Assert(p.CheckModule("github.com/gohugoio/hugo"), qt.IsNil)
+		c.Assert(p.CheckHost(KindHTTP, "example.com"), qt.ErrorMatches, `.*host not in allowHosts`)
+		c.Assert(p.CheckExec("postcss"), qt.Not(qt.IsNil))
+	})
+
+	c.Run("Unrestricted hosts", func(c *qt.C) {
+		p, _ := New(Config{MaxDownloadSize: 10})
+		c.Assert(p.CheckHost(KindHTTP, "example.com"), qt.IsNil)
+		c.Assert(p.CheckExec("postcss"), qt.IsNil)
+	})
+}
+
+func TestPolicyGoEnv(t *testing.T) {
+	c := qt.New(t)
+
+	var nilPolicy *Policy
+	c.Assert(nilPolicy.GoEnv("direct", ""), qt.IsNil)
+
+	p, _ := New(Config{DenyAll: true})
+	c.Assert(p.GoEnv("direct", ""), qt.DeepEquals, []string{"GOPROXY", "off"})
+
+	p, _ = New(Config{AllowHosts: []string{"github.com", "*.Example.org"}})
+	c.Assert(p.GoEnv("https://proxy.golang.org,https://goproxy.example.org|direct", ""), qt.DeepEquals, []string{
+		"GOPROXY", "https://goproxy.example.org,direct",
+		"GOVCS", "github.com:all,*.example.org:all,*:off",
+		"GOSUMDB", "off",
+	})
+	c.Assert(p.GoEnv("https://proxy.golang.org", "sum.golang.org+abc https://sum.example.org"), qt.DeepEquals, []string{
+		"GOPROXY", "off",
+		"GOVCS", "github.com:all,*.example.org:all,*:off",
+	})
+
+	p, _ = New(Config{MaxDownloadSize: 10})
+	c.Assert(p.GoEnv("direct", ""), qt.IsNil)
+}
+
+func TestPolicyClient(t *testing.T) {
+	c := qt.New(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Repeat("a", 10)))
+	}))
+	defer srv.Close()
+
+	u, _ := url.Parse(srv.URL)
+
+	get := func(p *Policy) (string, error) {
+		res, err := p.Client(http.DefaultClient).Get(srv.URL)
+		if err != nil {
+			return "", err
+		}
+		defer res.Body.Close()
+		b, err := ioutil.ReadAll(res.Body)
+		return string(b), err
+	}
+
+	p, _ := New(Config{AllowHosts: []string{u.Hostname()}, MaxDownloadSize: 25, Timeout: "1m"})
+	c.Assert(p.Client(http.DefaultClient).Timeout.String(), qt.Equals, "1m0s")
+
+	for i := 0; i < 2; i++ {
+		s, err := get(p)
+		c.Assert(err, qt.IsNil)
+		c.Assert(s, qt.Equals, "aaaaaaaaaa")
+	}
+	_, err := get(p)
+	c.Assert(err, qt.ErrorMatches, `network policy: download ".*" denied: exceeds maxDownloadSize of 25 bytes`)
+	c.Assert(p.Violations(), qt.HasLen, 1)
+
+	p.Reset()
+	_, err = get(p)
+	c.Assert(err, qt.IsNil)
+
+	p, _ = New(Config{AllowHosts: []string{"example.org"}})
+	_, err = get(p)
+	c.Assert(err, qt.ErrorMatches, `.*network policy: http "127.0.0.1" denied: host not in allowHosts`)
+}
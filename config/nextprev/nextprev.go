@@ -0,0 +1,80 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package nextprev configures the Next/Prev and NextInSection/PrevInSection
+// navigation of the pages in a section and its sub sections.
+package nextprev
+
+import (
+	"strings"
+
+	"github.com/gohugoio/hugo/config"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+)
+
+const nextPrevConfigKey = "nextPrev"
+
+// Config configures the navigation of the pages in a section.
+type Config struct {
+	// How to order the pages: "default", "weight", "date", "title",
+	// "linkTitle" or "params.<key>".
+	OrderBy string
+
+	// Reverse reverses the order.
+	Reverse bool
+
+	// Wrap around, i.e. Next of the last page is the first page and Prev
+	// of the first page is the last.
+	Wrap bool
+
+	// Exclude the pages with any of these params values, e.g.
+	// { hidden = true }.
+	Exclude map[string]interface{}
+}
+
+// DecodeConfig decodes the nextPrev config, the navigation settings keyed
+// by section path, e.g. "docs" or "docs/guides".
+func DecodeConfig(cfg config.Provider) (map[string]Config, error) {
+	m := cfg.GetStringMap(nextPrevConfigKey)
+	if m == nil {
+		return nil, nil
+	}
+
+	sections := make(map[string]Config)
+	for k, v := range m {
+		c := Config{OrderBy: "default"}
+		if err := mapstructure.WeakDecode(v, &c); err != nil {
+			return nil, errors.Wrapf(err, "failed to decode nextPrev config for section %q", k)
+		}
+
+		c.OrderBy = strings.ToLower(c.OrderBy)
+		switch c.OrderBy {
+		case "default", "weight", "date", "title", "linktitle":
+		default:
+			if !strings.HasPrefix(c.OrderBy, "params.") || c.OrderBy == "params." {
+				return nil, errors.Errorf("nextPrev: section %q: orderBy must be one of \"default\", \"weight\", \"date\", \"title\", \"linkTitle\" or \"params.<key>\", got %q", k, c.OrderBy)
+			}
+		}
+
+		exclude := make(map[string]interface{})
+		for kk, vv := range c.Exclude {
+			exclude[strings.ToLower(kk)] = vv
+		}
+		c.Exclude = exclude
+
+		sections[strings.ToLower(strings.Trim(k, "/"))] = c
+	}
+
+	return sections, nil
+}
@@ -0,0 +1,54 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nextprev
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gohugoio/hugo/config"
+)
+
+func TestDecodeConfig(t *testing.T) {
+	c := qt.New(t)
+
+	v := config.New()
+	v.Set("nextPrev", map[string]interface{}{
+		"/Docs/": map[string]interface{}{
+			"orderBy": "Weight",
+			"wrap":    true,
+			"exclude": map[string]interface{}{"Hidden": true},
+		},
+		"blog": map[string]interface{}{
+			"orderBy": "params.Rank",
+		},
+		"news": map[string]interface{}{},
+	})
+
+	np, err := DecodeConfig(v)
+	c.Assert(err, qt.IsNil)
+	c.Assert(np, qt.DeepEquals, map[string]Config{
+		"docs": {OrderBy: "weight", Wrap: true, Exclude: map[string]interface{}{"hidden": true}},
+		"blog": {OrderBy: "params.rank", Exclude: map[string]interface{}{}},
+		"news": {OrderBy: "default", Exclude: map[string]interface{}{}},
+	})
+
+	for _, orderBy := range []string{"random", "params."} {
+		v.Set("nextPrev", map[string]interface{}{
+			"docs": map[string]interface{}{"orderBy": orderBy},
+		})
+		_, err = DecodeConfig(v)
+		c.Assert(err, qt.Not(qt.IsNil))
+	}
+}
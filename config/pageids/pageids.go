@@ -0,0 +1,62 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pageids configures the tracking of stable page IDs.
+package pageids
+
+import (
+	"github.com/gohugoio/hugo/config"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+)
+
+const pageIDsConfigKey = "pageIDs"
+
+// Config configures the tracking of stable page IDs.
+type Config struct {
+	// Enable to record the paths of the pages with an ID in a sidecar file
+	// and to create redirects from the old paths when a page is moved.
+	Enable bool
+
+	// Enable to generate IDs for the pages without an id in front matter.
+	// The generated IDs are written to the front matter of the content
+	// files, so they are kept when the files are moved.
+	Generate bool
+
+	// The sidecar file, relative to the working dir. Default is pageids.json.
+	Filename string
+}
+
+var DefaultConfig = Config{
+	Filename: "pageids.json",
+}
+
+// DecodeConfig creates a page IDs Config from the given configuration.
+func DecodeConfig(cfg config.Provider) (Config, error) {
+	c := DefaultConfig
+	m := cfg.GetStringMap(pageIDsConfigKey)
+	if m == nil {
+		return c, nil
+	}
+
+	if err := mapstructure.WeakDecode(m, &c); err != nil {
+		return c, errors.Wrap(err, "failed to decode pageIDs config")
+	}
+
+	if c.Generate {
+		// Generated IDs must be stored.
+		c.Enable = true
+	}
+
+	return c, nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package permalinkhistory configures the history of published URLs used to
+// detect URLs dropped without an alias.
+package permalinkhistory
+
+import (
+	"strings"
+
+	"github.com/gohugoio/hugo/config"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+)
+
+const permalinkHistoryConfigKey = "permalinkHistory"
+
+// Config configures the history of published URLs.
+type Config struct {
+	// Enable to store the published URLs and check that they're still
+	// published, as a page or an alias, in later builds.
+	Enable bool
+
+	// The history file, relative to the working dir. Default is
+	// permalinks-history.json.
+	Filename string
+
+	// How to report dropped URLs: "error" (default) fails the build,
+	// "warning" logs them and removes them from the history.
+	ErrorLevel string
+}
+
+var DefaultConfig = Config{
+	Filename:   "permalinks-history.json",
+	ErrorLevel: "error",
+}
+
+// DecodeConfig creates a permalink history Config from the given
+// configuration.
+func DecodeConfig(cfg config.Provider) (Config, error) {
+	c := DefaultConfig
+	m := cfg.GetStringMap(permalinkHistoryConfigKey)
+	if m == nil {
+		return c, nil
+	}
+
+	if err := mapstructure.WeakDecode(m, &c); err != nil {
+		return c, errors.Wrap(err, "failed to decode permalinkHistory config")
+	}
+
+	c.ErrorLevel = strings.ToLower(c.ErrorLevel)
+	if c.ErrorLevel != "error" && c.ErrorLevel != "warning" {
+		return c, errors.Errorf("permalinkHistory: errorLevel must be \"error\" or \"warning\", got %q", c.ErrorLevel)
+	}
+
+	return c, nil
+}
@@ -14,6 +14,9 @@
 package privacy
 
 import (
+	"sort"
+	"strings"
+
 	"github.com/gohugoio/hugo/config"
 	"github.com/mitchellh/mapstructure"
 )
@@ -23,6 +26,22 @@ const privacyConfigKey = "privacy"
 // Service is the common values for a service in a policy definition.
 type Service struct {
 	Disable bool
+
+	// Enabling this will make the templates for the service not load
+	// anything from the service until the user has given consent.
+	// Analytics wait for hugoAnalytics.grantConsent() and embeds are
+	// rendered as placeholders for a client-side consent manager to activate:
+	//
+	//     <div data-hugo-consent="youtube" data-hugo-consent-category="marketing">
+	//       <template>The embed.</template>
+	//     </div>
+	RequireConsent bool
+
+	// The consent category of the service, e.g. "analytics" or "marketing".
+	Category string
+
+	// The domains the service loads content from.
+	Domains []string
 }
 
 // Config is a privacy configuration for all the relevant services in Hugo.
@@ -30,9 +49,74 @@ type Config struct {
 	Disqus          Disqus
 	GoogleAnalytics GoogleAnalytics
 	Instagram       Instagram
+	Matomo          Matomo
+	Plausible       Plausible
 	Twitter         Twitter
 	Vimeo           Vimeo
 	YouTube         YouTube
+
+	// Other services, e.g. used in themes, keyed by name.
+	Services map[string]Service
+
+	// If set, a consent manifest listing the services requiring consent
+	// is written to this path in the publish dir, e.g. "consent.json".
+	ConsentManifest string
+}
+
+// Get returns the service with the given name, e.g. "youtube", or a zero
+// Service if not found. The name is case insensitive.
+func (c Config) Get(name string) Service {
+	return c.All()[strings.ToLower(name)]
+}
+
+// All returns all the services keyed by their lower case name.
+func (c Config) All() map[string]Service {
+	m := map[string]Service{
+		"disqus":          c.Disqus.Service,
+		"googleanalytics": c.GoogleAnalytics.Service,
+		"instagram":       c.Instagram.Service,
+		"matomo":          c.Matomo.Service,
+		"plausible":       c.Plausible.Service,
+		"twitter":         c.Twitter.Service,
+		"vimeo":           c.Vimeo.Service,
+		"youtube":         c.YouTube.Service,
+	}
+	for k, v := range c.Services {
+		k = strings.ToLower(k)
+		if _, found := m[k]; !found {
+			m[k] = v
+		}
+	}
+	return m
+}
+
+// ManifestService describes a service in the consent manifest.
+type ManifestService struct {
+	Name     string   `json:"name"`
+	Category string   `json:"category"`
+	Domains  []string `json:"domains"`
+}
+
+// Manifest returns the services requiring consent, sorted by name, for
+// client-side consent managers.
+func (c Config) Manifest() []ManifestService {
+	services := []ManifestService{}
+	for name, s := range c.All() {
+		if s.Disable || !s.RequireConsent {
+			continue
+		}
+		domains := s.Domains
+		if domains == nil {
+			domains = []string{}
+		}
+		services = append(services, ManifestService{Name: name, Category: s.Category, Domains: domains})
+	}
+
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].Name < services[j].Name
+	})
+
+	return services
 }
 
 // Disqus holds the privacy configuration settings related to the Disqus template.
@@ -64,6 +148,26 @@ type Instagram struct {
 	Simple bool
 }
 
+// Matomo holds the privacy configuration settings related to the Matomo template.
+type Matomo struct {
+	Service `mapstructure:",squash"`
+
+	// Enabling this will make Matomo respect the "Do Not Track" HTTP header.
+	RespectDoNotTrack bool
+
+	// Enabling this will disable the Matomo tracking cookies.
+	DisableCookies bool
+}
+
+// Plausible holds the privacy configuration settings related to the Plausible template.
+type Plausible struct {
+	Service `mapstructure:",squash"`
+
+	// Enabling this will make the Plausible template respect the
+	// "Do Not Track" HTTP header.
+	RespectDoNotTrack bool
+}
+
 // Twitter holds the privacy configuration settingsrelated to the Twitter shortcode.
 type Twitter struct {
 	Service `mapstructure:",squash"`
@@ -102,13 +206,30 @@ type YouTube struct {
 
 // DecodeConfig creates a privacy Config from a given Hugo configuration.
 func DecodeConfig(cfg config.Provider) (pc Config, err error) {
-	if !cfg.IsSet(privacyConfigKey) {
-		return
+	if cfg.IsSet(privacyConfigKey) {
+		m := cfg.GetStringMap(privacyConfigKey)
+		if err = mapstructure.WeakDecode(m, &pc); err != nil {
+			return
+		}
 	}
 
-	m := cfg.GetStringMap(privacyConfigKey)
+	setDefaults := func(s *Service, category string, domains ...string) {
+		if s.Category == "" {
+			s.Category = category
+		}
+		if s.Domains == nil {
+			s.Domains = domains
+		}
+	}
 
-	err = mapstructure.WeakDecode(m, &pc)
+	setDefaults(&pc.Disqus.Service, "functional", "disqus.com")
+	setDefaults(&pc.GoogleAnalytics.Service, "analytics", "www.googletagmanager.com", "www.google-analytics.com")
+	setDefaults(&pc.Instagram.Service, "marketing", "www.instagram.com")
+	setDefaults(&pc.Matomo.Service, "analytics")
+	setDefaults(&pc.Plausible.Service, "analytics", "plausible.io")
+	setDefaults(&pc.Twitter.Service, "marketing", "platform.twitter.com")
+	setDefaults(&pc.Vimeo.Service, "marketing", "player.vimeo.com")
+	setDefaults(&pc.YouTube.Service, "marketing", "www.youtube.com", "www.youtube-nocookie.com")
 
 	return
 }
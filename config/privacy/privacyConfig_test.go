@@ -35,9 +35,19 @@ disable = true
 respectDoNotTrack = true
 anonymizeIP = true
 useSessionStorage = true
+requireConsent = true
 [privacy.instagram]
 disable = true
 simple = true
+[privacy.matomo]
+disable = true
+respectDoNotTrack = true
+disableCookies = true
+requireConsent = true
+[privacy.plausible]
+disable = true
+respectDoNotTrack = true
+requireConsent = true
 [privacy.twitter]
 disable = true
 enableDNT = true
@@ -61,8 +71,11 @@ simple = true
 	got := []bool{
 		pc.Disqus.Disable, pc.GoogleAnalytics.Disable,
 		pc.GoogleAnalytics.RespectDoNotTrack, pc.GoogleAnalytics.AnonymizeIP,
-		pc.GoogleAnalytics.UseSessionStorage, pc.Instagram.Disable,
-		pc.Instagram.Simple, pc.Twitter.Disable, pc.Twitter.EnableDNT,
+		pc.GoogleAnalytics.UseSessionStorage, pc.GoogleAnalytics.RequireConsent,
+		pc.Instagram.Disable, pc.Instagram.Simple,
+		pc.Matomo.Disable, pc.Matomo.RespectDoNotTrack, pc.Matomo.DisableCookies,
+		pc.Matomo.RequireConsent, pc.Plausible.Disable, pc.Plausible.RespectDoNotTrack,
+		pc.Plausible.RequireConsent, pc.Twitter.Disable, pc.Twitter.EnableDNT,
 		pc.Twitter.Simple, pc.Vimeo.Disable, pc.Vimeo.EnableDNT, pc.Vimeo.Simple,
 		pc.YouTube.PrivacyEnhanced, pc.YouTube.Disable,
 	}
@@ -97,4 +110,46 @@ func TestDecodeConfigDefault(t *testing.T) {
 	c.Assert(err, qt.IsNil)
 	c.Assert(pc, qt.Not(qt.IsNil))
 	c.Assert(pc.YouTube.PrivacyEnhanced, qt.Equals, false)
+	c.Assert(pc.YouTube.Category, qt.Equals, "marketing")
+	c.Assert(pc.Manifest(), qt.HasLen, 0)
+}
+
+func TestDecodeConfigServices(t *testing.T) {
+	c := qt.New(t)
+
+	tomlConfig := `
+[privacy]
+consentManifest = "consent.json"
+[privacy.youtube]
+requireConsent = true
+[privacy.twitter]
+requireConsent = true
+disable = true
+[privacy.googleAnalytics]
+requireConsent = true
+category = "statistics"
+[privacy.services.myMap]
+requireConsent = true
+category = "functional"
+domains = ["maps.example.com"]
+[privacy.services.youtube]
+category = "ignored"
+`
+	cfg, err := config.FromConfigString(tomlConfig, "toml")
+	c.Assert(err, qt.IsNil)
+
+	pc, err := DecodeConfig(cfg)
+	c.Assert(err, qt.IsNil)
+
+	c.Assert(pc.ConsentManifest, qt.Equals, "consent.json")
+	c.Assert(pc.Get("YouTube").Category, qt.Equals, "marketing")
+	c.Assert(pc.Get("mymap").Domains, qt.DeepEquals, []string{"maps.example.com"})
+	c.Assert(pc.Get("googleAnalytics").Category, qt.Equals, "statistics")
+	c.Assert(pc.Get("foo"), qt.DeepEquals, Service{})
+
+	c.Assert(pc.Manifest(), qt.DeepEquals, []ManifestService{
+		{Name: "googleanalytics", Category: "statistics", Domains: []string{"www.googletagmanager.com", "www.google-analytics.com"}},
+		{Name: "mymap", Category: "functional", Domains: []string{"maps.example.com"}},
+		{Name: "youtube", Category: "marketing", Domains: []string{"www.youtube.com", "www.youtube-nocookie.com"}},
+	})
 }
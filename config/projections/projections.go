@@ -0,0 +1,67 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package projections configures the JSON documents rendered for the pages
+// in the output formats without a template of their own.
+package projections
+
+import (
+	"strings"
+
+	"github.com/gohugoio/hugo/config"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+)
+
+const projectionsConfigKey = "projections"
+
+// Config selects the fields of the JSON document rendered for a page in
+// an output format without a template of its own.
+//
+// Field names are page fields (e.g. title, date, permalink), params (all
+// params) or params.name (a single param).
+type Config struct {
+	// The fields to include. Defaults to kind, type, section, title, date,
+	// lastmod, permalink, summary and params.
+	Include []string
+
+	// The fields to remove from the included fields, e.g. params.secret.
+	Exclude []string
+
+	// The params holding page references (a path or a list of paths) to
+	// resolve, one level deep. The page fields parent, prev, next,
+	// prevInSection, nextInSection and translations are always resolved.
+	References []string
+
+	// The fields to include for a resolved reference. Defaults to title and permalink.
+	ReferenceFields []string
+}
+
+// DecodeConfig decodes the projections config, keyed by the lower case
+// output format name.
+func DecodeConfig(cfg config.Provider) (map[string]Config, error) {
+	projections := make(map[string]Config)
+
+	for name, v := range cfg.GetStringMap(projectionsConfigKey) {
+		var p Config
+		if err := mapstructure.WeakDecode(v, &p); err != nil {
+			return nil, errors.Wrapf(err, "failed to decode projection for output format %q", name)
+		}
+		if len(p.ReferenceFields) == 0 {
+			p.ReferenceFields = []string{"title", "permalink"}
+		}
+		projections[strings.ToLower(name)] = p
+	}
+
+	return projections, nil
+}
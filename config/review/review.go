@@ -0,0 +1,107 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package review configures the ownership and review metadata required for
+// content.
+package review
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gohugoio/hugo/config"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+)
+
+const reviewConfigKey = "review"
+
+// Config configures the ownership and review metadata required for content.
+type Config struct {
+	// The requirements per section, keyed by the section name. Use "*" for
+	// the sections not listed.
+	Sections map[string]Section
+
+	// The front matter param with the date a page was last reviewed. If not
+	// set in a page, its lastmod date is used.
+	DateParam string
+
+	// How to report missing fields: "error" (default) fails the build,
+	// "warning" logs them.
+	ErrorLevel string
+}
+
+// Section configures the review requirements for a section.
+type Section struct {
+	// The front matter params required, e.g. ["owner", "reviewers"].
+	Required []string
+
+	// How often the pages must be reviewed, e.g. "2160h" for 90 days. Pages
+	// not reviewed within this interval are reported as overdue.
+	Interval time.Duration
+}
+
+var DefaultConfig = Config{
+	DateParam:  "lastReviewed",
+	ErrorLevel: "error",
+}
+
+// ForSection returns the review requirements for the given section, and
+// false if there are none.
+func (c Config) ForSection(section string) (Section, bool) {
+	if rs, found := c.Sections[strings.ToLower(section)]; found {
+		return rs, true
+	}
+	rs, found := c.Sections["*"]
+	return rs, found
+}
+
+// DecodeConfig creates a review Config from the given configuration.
+func DecodeConfig(cfg config.Provider) (Config, error) {
+	c := DefaultConfig
+	m := cfg.GetStringMap(reviewConfigKey)
+	if m == nil {
+		return c, nil
+	}
+
+	dc := &mapstructure.DecoderConfig{
+		Result:           &c,
+		DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+		WeaklyTypedInput: true,
+	}
+
+	decoder, err := mapstructure.NewDecoder(dc)
+	if err != nil {
+		return c, err
+	}
+
+	if err := decoder.Decode(m); err != nil {
+		return c, errors.Wrap(err, "failed to decode review config")
+	}
+
+	c.ErrorLevel = strings.ToLower(c.ErrorLevel)
+	if c.ErrorLevel != "error" && c.ErrorLevel != "warning" {
+		return c, errors.Errorf("review: errorLevel must be \"error\" or \"warning\", got %q", c.ErrorLevel)
+	}
+
+	sections := make(map[string]Section)
+	for k, v := range c.Sections {
+		if v.Interval < 0 {
+			return c, errors.Errorf("review: interval for section %q must be positive", k)
+		}
+		sections[strings.ToLower(k)] = v
+	}
+	c.Sections = sections
+
+	return c, nil
+}
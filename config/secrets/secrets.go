@@ -0,0 +1,246 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package secrets decrypts age encrypted front matter values, so values that
+// are not yet public, e.g. embargoed titles, can be stored in a public
+// repository and decrypted in the build.
+//
+// The values are ASCII armored age messages, as created with
+//
+//	echo -n "Embargoed title" | age -a -r age1...
+//
+// or with hugo secrets encrypt, and are decrypted with the same age
+// identities as used by SOPS.
+package secrets
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+	"github.com/gohugoio/hugo/common/maps"
+	"github.com/gohugoio/hugo/config"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+const secretsConfigKey = "secrets"
+
+// Config configures the age identities the front matter values are
+// decrypted with.
+type Config struct {
+	// The age identities, AGE-SECRET-KEY-1..., one per line, usually set
+	// with the HUGO_SECRETS_KEY environment variable.
+	Key string
+
+	// The age identity file, e.g. as created by age-keygen, relative to the
+	// working dir. Used if Key is not set.
+	KeyFile string
+
+	// Additional age recipients, age1..., that hugo secrets encrypt
+	// encrypts to, e.g. the public keys of the other editors.
+	Recipients []string
+}
+
+// DecodeConfig creates a secrets Config from the given configuration.
+func DecodeConfig(cfg config.Provider) (conf Config, err error) {
+	v := cfg.Get(secretsConfigKey)
+	if v == nil {
+		return
+	}
+
+	err = mapstructure.WeakDecode(maps.ToStringMap(v), &conf)
+
+	return
+}
+
+// Decrypter decrypts age encrypted values. A nil Decrypter has no
+// identities, and fails to decrypt any encrypted value.
+type Decrypter struct {
+	identities []age.Identity
+	recipients []age.Recipient
+}
+
+// New creates a new Decrypter with the identities in conf, reading any key
+// file from fs relative to workingDir. It returns nil if no key is
+// configured.
+func New(conf Config, fs afero.Fs, workingDir string) (*Decrypter, error) {
+	key := conf.Key
+	if key == "" && conf.KeyFile != "" {
+		filename := conf.KeyFile
+		if !filepath.IsAbs(filename) {
+			filename = filepath.Join(workingDir, filename)
+		}
+		b, err := afero.ReadFile(fs, filename)
+		if err != nil {
+			return nil, errors.Wrapf(err, "%s: failed to read key file", secretsConfigKey)
+		}
+		key = string(b)
+	}
+
+	if key == "" {
+		return nil, nil
+	}
+
+	return NewDecrypter(key, conf.Recipients...)
+}
+
+// NewDecrypter creates a new Decrypter with the age identities in key and
+// the additional recipients to encrypt to.
+func NewDecrypter(key string, recipients ...string) (*Decrypter, error) {
+	identities, err := age.ParseIdentities(strings.NewReader(key))
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s: invalid key", secretsConfigKey)
+	}
+
+	d := &Decrypter{identities: identities}
+	for _, id := range identities {
+		if x, ok := id.(*age.X25519Identity); ok {
+			d.recipients = append(d.recipients, x.Recipient())
+		}
+	}
+	for _, s := range recipients {
+		r, err := age.ParseX25519Recipient(s)
+		if err != nil {
+			return nil, errors.Wrapf(err, "%s: invalid recipient", secretsConfigKey)
+		}
+		d.recipients = append(d.recipients, r)
+	}
+
+	return d, nil
+}
+
+// GenerateKey returns a new age identity, prefixed with a comment with its
+// public key, in the format written by age-keygen.
+func GenerateKey() (string, error) {
+	id, err := age.GenerateX25519Identity()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("# public key: %s\n%s\n", id.Recipient(), id), nil
+}
+
+// IsEncrypted reports whether s is an encrypted value.
+func IsEncrypted(s string) bool {
+	return strings.HasPrefix(strings.TrimSpace(s), armor.Header)
+}
+
+// Encrypt encrypts s to the recipients of d, returning an ASCII armored age
+// message.
+func (d *Decrypter) Encrypt(s string) (string, error) {
+	if d == nil {
+		return "", errors.Errorf("%s: no key configured", secretsConfigKey)
+	}
+
+	var buf bytes.Buffer
+	aw := armor.NewWriter(&buf)
+	w, err := age.Encrypt(aw, d.recipients...)
+	if err != nil {
+		return "", err
+	}
+	if _, err := w.Write([]byte(s)); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	if err := aw.Close(); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// Decrypt decrypts the encrypted value s. Values that are not encrypted
+// are returned as is.
+func (d *Decrypter) Decrypt(s string) (string, error) {
+	if !IsEncrypted(s) {
+		return s, nil
+	}
+	if d == nil {
+		return "", errors.Errorf("found an encrypted value, but no %s.key or %s.keyFile is configured", secretsConfigKey, secretsConfigKey)
+	}
+
+	r, err := age.Decrypt(armor.NewReader(strings.NewReader(strings.TrimSpace(s))), d.identities...)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to decrypt value")
+	}
+	plain, err := ioutil.ReadAll(r)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to decrypt value")
+	}
+
+	return string(plain), nil
+}
+
+// DecryptValues decrypts the encrypted string values in m, and in any
+// nested maps and slices, in place.
+func (d *Decrypter) DecryptValues(m map[string]interface{}) error {
+	// Sort the keys to report the same error in every build.
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		vv, err := d.decryptValue(m[k])
+		if err != nil {
+			return errors.Wrapf(err, "%q", k)
+		}
+		m[k] = vv
+	}
+	return nil
+}
+
+func (d *Decrypter) decryptValue(v interface{}) (interface{}, error) {
+	switch vv := v.(type) {
+	case string:
+		return d.Decrypt(vv)
+	case map[string]interface{}:
+		return vv, d.DecryptValues(vv)
+	case maps.Params:
+		return vv, d.DecryptValues(vv)
+	case map[interface{}]interface{}:
+		for k, e := range vv {
+			dv, err := d.decryptValue(e)
+			if err != nil {
+				return nil, errors.Wrapf(err, "%q", fmt.Sprint(k))
+			}
+			vv[k] = dv
+		}
+	case []interface{}:
+		for i, e := range vv {
+			dv, err := d.decryptValue(e)
+			if err != nil {
+				return nil, err
+			}
+			vv[i] = dv
+		}
+	case []string:
+		for i, e := range vv {
+			dv, err := d.Decrypt(e)
+			if err != nil {
+				return nil, err
+			}
+			vv[i] = dv
+		}
+	}
+	return v, nil
+}
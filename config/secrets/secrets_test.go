@@ -0,0 +1,150 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gohugoio/hugo/config"
+	"github.com/spf13/afero"
+)
+
+func TestDecodeConfig(t *testing.T) {
+	c := qt.New(t)
+
+	cfg, err := config.FromConfigString(`
+[secrets]
+keyFile = "keys.txt"
+recipients = ["age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p"]
+`, "toml")
+	c.Assert(err, qt.IsNil)
+
+	conf, err := DecodeConfig(cfg)
+	c.Assert(err, qt.IsNil)
+	c.Assert(conf, qt.DeepEquals, Config{
+		KeyFile:    "keys.txt",
+		Recipients: []string{"age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p"},
+	})
+
+	key, err := GenerateKey()
+	c.Assert(err, qt.IsNil)
+	c.Assert(key, qt.Matches, "# public key: age1[a-z0-9]+\nAGE-SECRET-KEY-1[A-Z0-9]+\n")
+
+	fs := afero.NewMemMapFs()
+	c.Assert(afero.WriteFile(fs, filepath.FromSlash("/site/keys.txt"), []byte(key), 0666), qt.IsNil)
+
+	d, err := New(conf, fs, filepath.FromSlash("/site"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(d, qt.Not(qt.IsNil))
+	c.Assert(d.recipients, qt.HasLen, 2)
+
+	_, err = New(conf, fs, filepath.FromSlash("/other"))
+	c.Assert(err, qt.ErrorMatches, "secrets: failed to read key file.*")
+
+	d, err = New(Config{}, fs, "")
+	c.Assert(err, qt.IsNil)
+	c.Assert(d, qt.IsNil)
+
+	_, err = NewDecrypter("not a key")
+	c.Assert(err, qt.ErrorMatches, "secrets: invalid key: .*")
+
+	_, err = NewDecrypter(key, "age1invalid")
+	c.Assert(err, qt.ErrorMatches, "secrets: invalid recipient: .*")
+}
+
+// An age identity and a value encrypted to it, as with
+// age -a -r age1....
+const (
+	testKey       = "AGE-SECRET-KEY-14S7JFA2WGD2DWEFUHKUY4LU4MFMX8HHEH6J3SLWPRTU03K7GQDUSKVNNUJ"
+	testEncrypted = `-----BEGIN AGE ENCRYPTED FILE-----
+YWdlLWVuY3J5cHRpb24ub3JnL3YxCi0+IFgyNTUxOSBsWXU0aGFxNHViUU1NcnZB
+Q0c1NHN0NXgreUpJNjEzc1BrNSt6MnRWTVhBCjlweFhJd2ZaZTlPcVRPMjdMSzBJ
+UFJ3UzVITDdvQ1Q3TWxpUmFnVklGLzgKLS0tIDBUOU5ibGhiWVpYQUwzV3cvTlFO
+am9BM3RNVEUyOXlWd3JNc0pwdngyL3MKFvWH/w5Y1QuEKqL6gONtYZoV/+Jb7vJN
+X2XZdPRrj+MXAhDvWNTV4aGojXP57eY=
+-----END AGE ENCRYPTED FILE-----
+`
+)
+
+func TestDecrypter(t *testing.T) {
+	c := qt.New(t)
+
+	key, err := GenerateKey()
+	c.Assert(err, qt.IsNil)
+	d, err := NewDecrypter(key)
+	c.Assert(err, qt.IsNil)
+
+	s1, err := d.Encrypt("Embargoed title")
+	c.Assert(err, qt.IsNil)
+	s2, err := d.Encrypt("Embargoed title")
+	c.Assert(err, qt.IsNil)
+	c.Assert(IsEncrypted(s1), qt.IsTrue)
+	c.Assert(s1, qt.Matches, `(?s)-----BEGIN AGE ENCRYPTED FILE-----\n.*\n-----END AGE ENCRYPTED FILE-----\n`)
+	c.Assert(IsEncrypted("Not encrypted"), qt.IsFalse)
+	c.Assert(s1, qt.Not(qt.Equals), s2)
+
+	v, err := d.Decrypt(s1)
+	c.Assert(err, qt.IsNil)
+	c.Assert(v, qt.Equals, "Embargoed title")
+
+	// YAML block scalars may indent and strip the trailing newline.
+	v, err = d.Decrypt("  " + strings.TrimSpace(s1))
+	c.Assert(err, qt.IsNil)
+	c.Assert(v, qt.Equals, "Embargoed title")
+
+	fixed, err := NewDecrypter(testKey)
+	c.Assert(err, qt.IsNil)
+	v, err = fixed.Decrypt(testEncrypted)
+	c.Assert(err, qt.IsNil)
+	c.Assert(v, qt.Equals, "Embargoed title")
+
+	v, err = d.Decrypt("Not encrypted")
+	c.Assert(err, qt.IsNil)
+	c.Assert(v, qt.Equals, "Not encrypted")
+
+	otherKey, _ := GenerateKey()
+	other, _ := NewDecrypter(otherKey)
+	_, err = other.Decrypt(s1)
+	c.Assert(err, qt.ErrorMatches, "failed to decrypt value: no identity matched any of the recipients")
+
+	var nild *Decrypter
+	_, err = nild.Decrypt(s1)
+	c.Assert(err, qt.ErrorMatches, "found an encrypted value, but no secrets.key or secrets.keyFile is configured")
+	c.Assert(nild.DecryptValues(map[string]interface{}{"a": "b"}), qt.IsNil)
+
+	m := map[string]interface{}{
+		"title": s1,
+		"params": map[string]interface{}{
+			"links": []interface{}{s2, "https://example.org"},
+			"nested": map[interface{}]interface{}{
+				"tags": []string{s1},
+			},
+			"count": 3,
+		},
+	}
+	c.Assert(d.DecryptValues(m), qt.IsNil)
+	c.Assert(m, qt.DeepEquals, map[string]interface{}{
+		"title": "Embargoed title",
+		"params": map[string]interface{}{
+			"links": []interface{}{"Embargoed title", "https://example.org"},
+			"nested": map[interface{}]interface{}{
+				"tags": []string{"Embargoed title"},
+			},
+			"count": 3,
+		},
+	})
+}
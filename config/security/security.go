@@ -0,0 +1,312 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package security restricts the external programs run in builds.
+package security
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gohugoio/hugo/common/maps"
+	"github.com/gohugoio/hugo/config"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+)
+
+const (
+	securityConfigKey = "security"
+
+	// The key the Policy of a build is stored with in the configuration.
+	policyKey = "securityPolicyInstance"
+)
+
+// Config configures the security policy of a build.
+type Config struct {
+	Exec Exec
+}
+
+// Exec configures the external programs that can be run.
+type Exec struct {
+	// If set, only the executions matching any of these rules are allowed.
+	// A rule can also be set as a string, which is short for a rule with
+	// only the name set.
+	Allow []ExecRule
+
+	// If set, every execution is appended as a JSON line, with the resolved
+	// command line, to this file, relative to the working dir.
+	AuditLog string
+}
+
+// ExecRule allows running the programs matching Name. All the regular
+// expressions must match the whole value.
+type ExecRule struct {
+	// The regular expression matching the base name of the program, e.g.
+	// "postcss|babel".
+	Name string
+
+	// If set, every argument must match one of these regular expressions,
+	// e.g. "--config", "assets/.*\.css".
+	Args []string
+
+	// If set, the regular expression matching the directory the program
+	// is run in, relative to the working dir, e.g. ".".
+	Dir string
+}
+
+// DecodeConfig creates a security Config from the given configuration.
+func DecodeConfig(cfg config.Provider) (conf Config, err error) {
+	v := cfg.Get(securityConfigKey)
+	if v == nil {
+		return
+	}
+
+	m := maps.ToStringMap(v)
+	if exec, found := m["exec"]; found {
+		execm := maps.ToStringMap(exec)
+		if allow, ok := execm["allow"].([]interface{}); ok {
+			rules := make([]interface{}, len(allow))
+			for i, rule := range allow {
+				if s, ok := rule.(string); ok {
+					rule = map[string]interface{}{"name": s}
+				}
+				rules[i] = rule
+			}
+			execm["allow"] = rules
+		}
+		m["exec"] = execm
+	}
+
+	err = mapstructure.WeakDecode(m, &conf)
+
+	return
+}
+
+type execRule struct {
+	name *regexp.Regexp
+	args []*regexp.Regexp
+	dir  *regexp.Regexp
+}
+
+func (r execRule) match(name string, args []string, dir string) bool {
+	if !r.name.MatchString(name) {
+		return false
+	}
+	if r.dir != nil && !r.dir.MatchString(dir) {
+		return false
+	}
+	if r.args == nil {
+		return true
+	}
+	for _, arg := range args {
+		var found bool
+		for _, re := range r.args {
+			if re.MatchString(arg) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Policy enforces a security Config. A nil Policy allows everything.
+type Policy struct {
+	conf       Config
+	workingDir string
+
+	allow []execRule
+
+	// Serializes the writes to the audit log.
+	auditMu sync.Mutex
+}
+
+// New creates a new Policy from the given configuration, with relative paths
+// resolved from workingDir. It returns nil if the configuration doesn't
+// restrict or audit anything.
+func New(conf Config, workingDir string) (*Policy, error) {
+	if len(conf.Exec.Allow) == 0 && conf.Exec.AuditLog == "" {
+		return nil, nil
+	}
+
+	p := &Policy{conf: conf, workingDir: workingDir}
+
+	compile := func(s string) (*regexp.Regexp, error) {
+		re, err := regexp.Compile("^(?:" + s + ")$")
+		if err != nil {
+			return nil, errors.Wrapf(err, "%s.exec: invalid regular expression %q", securityConfigKey, s)
+		}
+		return re, nil
+	}
+
+	for _, rule := range conf.Exec.Allow {
+		if rule.Name == "" {
+			return nil, errors.Errorf("%s.exec: allow rules must have a name", securityConfigKey)
+		}
+
+		var (
+			r   execRule
+			err error
+		)
+		if r.name, err = compile(rule.Name); err != nil {
+			return nil, err
+		}
+		if rule.Dir != "" {
+			if r.dir, err = compile(rule.Dir); err != nil {
+				return nil, err
+			}
+		}
+		for _, arg := range rule.Args {
+			re, err := compile(arg)
+			if err != nil {
+				return nil, err
+			}
+			r.args = append(r.args, re)
+		}
+		p.allow = append(p.allow, r)
+	}
+
+	return p, nil
+}
+
+// Set stores p as the Policy of the build with the configuration cfg.
+func Set(cfg config.Provider, p *Policy) {
+	cfg.Set(policyKey, p)
+}
+
+// Get returns the Policy of the build with the configuration cfg, nil if
+// none.
+func Get(cfg config.Provider) *Policy {
+	if cfg == nil {
+		return nil
+	}
+	p, _ := cfg.Get(policyKey).(*Policy)
+	return p
+}
+
+// Config returns the configuration of p.
+func (p *Policy) Config() Config {
+	if p == nil {
+		return Config{}
+	}
+	return p.conf
+}
+
+// relDir returns dir relative to the working dir, if inside it.
+func (p *Policy) relDir(dir string) string {
+	if p.workingDir != "" {
+		if rel, err := filepath.Rel(p.workingDir, dir); err == nil && !strings.HasPrefix(rel, "..") {
+			dir = rel
+		}
+	}
+	return filepath.ToSlash(dir)
+}
+
+// CheckExec returns an error if the program name can not be run with the
+// arguments args in the directory dir.
+func (p *Policy) CheckExec(name string, args []string, dir string) error {
+	if p == nil || len(p.allow) == 0 {
+		return nil
+	}
+
+	base := filepath.Base(name)
+	rel := p.relDir(dir)
+	for _, r := range p.allow {
+		if r.match(base, args, rel) {
+			return nil
+		}
+	}
+
+	return errors.Errorf("%s: %s is not allowed by %s.exec.allow", securityConfigKey, CommandLine(name, args), securityConfigKey)
+}
+
+// AuditEntry is an execution in the audit log.
+type AuditEntry struct {
+	Time time.Time `json:"time"`
+
+	// The resolved command line.
+	Command string `json:"command"`
+
+	// The directory the program is run in.
+	Dir string `json:"dir"`
+
+	// Set if the execution is denied.
+	Denied string `json:"denied,omitempty"`
+}
+
+// Audit appends the execution of the program path with the arguments args
+// in the directory dir to the audit log, if configured. denied is the error
+// the execution is denied with, if any.
+func (p *Policy) Audit(path string, args []string, dir string, denied error) error {
+	if p == nil || p.conf.Exec.AuditLog == "" {
+		return nil
+	}
+
+	entry := AuditEntry{
+		Time:    time.Now(),
+		Command: CommandLine(path, args),
+		Dir:     dir,
+	}
+	if denied != nil {
+		entry.Denied = denied.Error()
+	}
+
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	filename := p.conf.Exec.AuditLog
+	if !filepath.IsAbs(filename) {
+		filename = filepath.Join(p.workingDir, filename)
+	}
+
+	p.auditMu.Lock()
+	defer p.auditMu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(filename), 0777); err != nil {
+		return errors.Wrap(err, "failed to write security audit log")
+	}
+	f, err := os.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0666)
+	if err != nil {
+		return errors.Wrap(err, "failed to write security audit log")
+	}
+	if _, err := fmt.Fprintf(f, "%s\n", b); err != nil {
+		f.Close()
+		return errors.Wrap(err, "failed to write security audit log")
+	}
+	return f.Close()
+}
+
+// CommandLine formats the program name and the arguments args as a command
+// line, quoting the arguments with spaces or quotes.
+func CommandLine(name string, args []string) string {
+	parts := make([]string, 0, len(args)+1)
+	for _, s := range append([]string{name}, args...) {
+		if s == "" || strings.ContainsAny(s, " \t\n\"'") {
+			s = strconv.Quote(s)
+		}
+		parts = append(parts, s)
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,121 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package security
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gohugoio/hugo/config"
+)
+
+func TestDecodeConfig(t *testing.T) {
+	c := qt.New(t)
+
+	yamlConfig := `
+security:
+  exec:
+    auditLog: audit.log
+    allow:
+      - babel
+      - name: postcss
+        args: ["--config", '.*\.js']
+        dir: "."
+`
+	cfg, err := config.FromConfigString(yamlConfig, "yaml")
+	c.Assert(err, qt.IsNil)
+
+	conf, err := DecodeConfig(cfg)
+	c.Assert(err, qt.IsNil)
+	c.Assert(conf, qt.DeepEquals, Config{
+		Exec: Exec{
+			AuditLog: "audit.log",
+			Allow: []ExecRule{
+				{Name: "babel"},
+				{Name: "postcss", Args: []string{"--config", `.*\.js`}, Dir: "."},
+			},
+		},
+	})
+
+	p, err := New(Config{}, "")
+	c.Assert(err, qt.IsNil)
+	c.Assert(p, qt.IsNil)
+
+	_, err = New(Config{Exec: Exec{Allow: []ExecRule{{Name: "("}}}}, "")
+	c.Assert(err, qt.ErrorMatches, `security.exec: invalid regular expression "\("`+".*")
+
+	_, err = New(Config{Exec: Exec{Allow: []ExecRule{{Args: []string{"a"}}}}}, "")
+	c.Assert(err, qt.ErrorMatches, "security.exec: allow rules must have a name")
+}
+
+func TestCheckExec(t *testing.T) {
+	c := qt.New(t)
+
+	var nilp *Policy
+	c.Assert(nilp.CheckExec("rm", []string{"-rf", "/"}, "/"), qt.IsNil)
+	c.Assert(nilp.Audit("rm", nil, "/", nil), qt.IsNil)
+
+	wd := filepath.FromSlash("/site")
+	p, err := New(Config{Exec: Exec{Allow: []ExecRule{
+		{Name: "babel"},
+		{Name: "postcss", Args: []string{"--config", `.*\.js`}, Dir: "."},
+	}}}, wd)
+	c.Assert(err, qt.IsNil)
+
+	c.Assert(p.CheckExec("babel", []string{"--anything"}, "/tmp"), qt.IsNil)
+	c.Assert(p.CheckExec(filepath.FromSlash("/site/node_modules/.bin/babel"), nil, wd), qt.IsNil)
+	c.Assert(p.CheckExec("postcss", []string{"--config", "postcss.config.js"}, wd), qt.IsNil)
+
+	// The regular expressions must match the whole value.
+	c.Assert(p.CheckExec("babel-evil", nil, wd), qt.ErrorMatches, `security: babel-evil is not allowed by security.exec.allow`)
+	c.Assert(p.CheckExec("postcss", []string{"--config", "postcss.config.js", "--use", "evil"}, wd), qt.ErrorMatches, `security: postcss --config postcss.config.js --use evil is not allowed.*`)
+	c.Assert(p.CheckExec("postcss", []string{"--config=evil"}, wd), qt.Not(qt.IsNil))
+	c.Assert(p.CheckExec("postcss", []string{"--config", "postcss.config.js"}, filepath.Join(wd, "assets")), qt.Not(qt.IsNil))
+}
+
+func TestAudit(t *testing.T) {
+	c := qt.New(t)
+
+	dir := c.TempDir()
+	p, err := New(Config{Exec: Exec{AuditLog: "logs/audit.log"}}, dir)
+	c.Assert(err, qt.IsNil)
+
+	c.Assert(p.CheckExec("anything", nil, dir), qt.IsNil)
+	c.Assert(p.Audit("/usr/bin/postcss", []string{"--config", "my config.js"}, dir, nil), qt.IsNil)
+	c.Assert(p.Audit("babel", nil, dir, errors.New("denied")), qt.IsNil)
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, "logs", "audit.log"))
+	c.Assert(err, qt.IsNil)
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	c.Assert(lines, qt.HasLen, 2)
+
+	var entries []AuditEntry
+	for _, line := range lines {
+		var entry AuditEntry
+		c.Assert(json.Unmarshal([]byte(line), &entry), qt.IsNil)
+		c.Assert(entry.Time.IsZero(), qt.IsFalse)
+		c.Assert(entry.Dir, qt.Equals, dir)
+		entries = append(entries, entry)
+	}
+
+	c.Assert(entries[0].Command, qt.Equals, `/usr/bin/postcss --config "my config.js"`)
+	c.Assert(entries[0].Denied, qt.Equals, "")
+	c.Assert(entries[1].Command, qt.Equals, "babel")
+	c.Assert(entries[1].Denied, qt.Equals, "denied")
+}
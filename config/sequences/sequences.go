@@ -0,0 +1,60 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sequences configures the named sequences of pages, see
+// Page.Sequences.
+package sequences
+
+import (
+	"strings"
+
+	"github.com/gohugoio/hugo/config"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+)
+
+const sequencesConfigKey = "sequences"
+
+// Config configures a named sequence of pages.
+type Config struct {
+	// The pages in the sequence, in order, as paths or refs as given to
+	// site.GetPage, e.g. "/docs/intro" or "docs/install.md".
+	Pages []string
+
+	// The path to the list of pages in the data files instead, e.g.
+	// "tutorials.basics" for a list in data/tutorials/basics.yaml.
+	Data string
+}
+
+// DecodeConfig decodes the sequences config, keyed by the lower case
+// sequence name.
+func DecodeConfig(cfg config.Provider) (map[string]Config, error) {
+	m := cfg.GetStringMap(sequencesConfigKey)
+	if m == nil {
+		return nil, nil
+	}
+
+	sequences := make(map[string]Config)
+	for k, v := range m {
+		var c Config
+		if err := mapstructure.WeakDecode(v, &c); err != nil {
+			return nil, errors.Wrapf(err, "failed to decode sequences config for %q", k)
+		}
+		if (len(c.Pages) == 0) == (c.Data == "") {
+			return nil, errors.Errorf("sequences: %q: set either pages or data", k)
+		}
+		sequences[strings.ToLower(k)] = c
+	}
+
+	return sequences, nil
+}
@@ -0,0 +1,56 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package series configures the navigation in the page series set in front
+// matter.
+package series
+
+import (
+	"strings"
+
+	"github.com/gohugoio/hugo/config"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+)
+
+const seriesConfigKey = "series"
+
+// Config configures the navigation in the page series.
+type Config struct {
+	// How to order the pages in a series, "date" or "weight".
+	OrderBy string
+}
+
+var DefaultConfig = Config{
+	OrderBy: "date",
+}
+
+// DecodeConfig creates a series Config from the given configuration.
+func DecodeConfig(cfg config.Provider) (Config, error) {
+	c := DefaultConfig
+	m := cfg.GetStringMap(seriesConfigKey)
+	if m == nil {
+		return c, nil
+	}
+
+	if err := mapstructure.WeakDecode(m, &c); err != nil {
+		return c, errors.Wrap(err, "failed to decode series config")
+	}
+
+	c.OrderBy = strings.ToLower(c.OrderBy)
+	if c.OrderBy != "date" && c.OrderBy != "weight" {
+		return c, errors.Errorf("series: orderBy must be \"date\" or \"weight\", got %q", c.OrderBy)
+	}
+
+	return c, nil
+}
@@ -24,6 +24,8 @@ const (
 	disqusShortnameKey = "disqusshortname"
 	googleAnalyticsKey = "googleanalytics"
 	rssLimitKey        = "rssLimit"
+
+	defaultPlausibleScript = "https://plausible.io/js/plausible.js"
 )
 
 // Config is a privacy configuration for all the relevant services in Hugo.
@@ -31,6 +33,8 @@ type Config struct {
 	Disqus          Disqus
 	GoogleAnalytics GoogleAnalytics
 	Instagram       Instagram
+	Matomo          Matomo
+	Plausible       Plausible
 	Twitter         Twitter
 	RSS             RSS
 }
@@ -60,6 +64,25 @@ type Instagram struct {
 	AccessToken string
 }
 
+// Matomo holds the functional configuration settings related to the Matomo template.
+type Matomo struct {
+	// The URL to the Matomo instance, e.g. https://analytics.example.org/.
+	URL string
+
+	// The Matomo site ID.
+	SiteID string
+}
+
+// Plausible holds the functional configuration settings related to the Plausible template.
+type Plausible struct {
+	// The domain of the site as added in Plausible.
+	Domain string
+
+	// The URL to the Plausible script. Set this when self-hosting Plausible.
+	// Default is https://plausible.io/js/plausible.js.
+	Script string
+}
+
 // Twitter holds the functional configuration settings related to the Twitter shortcodes.
 type Twitter struct {
 	// The Simple variant of Twitter is decorated with a basic set of inline styles.
@@ -89,6 +112,10 @@ func DecodeConfig(cfg config.Provider) (c Config, err error) {
 		c.Disqus.Shortname = cfg.GetString(disqusShortnameKey)
 	}
 
+	if c.Plausible.Script == "" {
+		c.Plausible.Script = defaultPlausibleScript
+	}
+
 	if c.RSS.Limit == 0 {
 		c.RSS.Limit = cfg.GetInt(rssLimitKey)
 	}
@@ -35,6 +35,11 @@ shortname = "DS"
 id = "ga_id"
 [services.instagram]
 disableInlineCSS = true
+[services.matomo]
+url = "https://matomo.example.org/"
+siteID = "42"
+[services.plausible]
+domain = "example.org"
 [services.twitter]
 disableInlineCSS = true
 `
@@ -49,6 +54,11 @@ disableInlineCSS = true
 	c.Assert(config.GoogleAnalytics.ID, qt.Equals, "ga_id")
 
 	c.Assert(config.Instagram.DisableInlineCSS, qt.Equals, true)
+
+	c.Assert(config.Matomo.URL, qt.Equals, "https://matomo.example.org/")
+	c.Assert(config.Matomo.SiteID, qt.Equals, "42")
+	c.Assert(config.Plausible.Domain, qt.Equals, "example.org")
+	c.Assert(config.Plausible.Script, qt.Equals, "https://plausible.io/js/plausible.js")
 }
 
 // Support old root-level GA settings etc.
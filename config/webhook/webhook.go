@@ -0,0 +1,96 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package webhook configures the endpoints that trigger rebuilds of a
+// running server, /__hugo/rebuild and /__hugo/invalidate, set in
+// server.webhook.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/gohugoio/hugo/common/maps"
+	"github.com/gohugoio/hugo/config"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+)
+
+// DefaultSignatureHeader is the header with the signature of the webhook
+// request body, as sent by e.g. GitHub.
+const DefaultSignatureHeader = "X-Hub-Signature-256"
+
+// Config configures the webhook endpoints.
+type Config struct {
+	// The secret to authenticate the requests with, either as a bearer token
+	// in the Authorization header, or as the key of the HMAC-SHA256 signature
+	// of the request body. The endpoints are disabled if this is not set.
+	Secret string
+
+	// The header with the hex encoded HMAC-SHA256 signature of the request
+	// body, optionally prefixed with "sha256=".
+	// Default is "X-Hub-Signature-256".
+	SignatureHeader string
+}
+
+var DefaultConfig = Config{
+	SignatureHeader: DefaultSignatureHeader,
+}
+
+// DecodeConfig creates a webhook Config from the given configuration.
+func DecodeConfig(cfg config.Provider) (Config, error) {
+	c := DefaultConfig
+	v, found := cfg.GetStringMap("server")["webhook"]
+	if !found {
+		return c, nil
+	}
+
+	if err := mapstructure.WeakDecode(maps.ToStringMap(v), &c); err != nil {
+		return c, errors.Wrap(err, "failed to decode server.webhook config")
+	}
+
+	return c, nil
+}
+
+// Enabled reports whether the webhook endpoints are enabled.
+func (c Config) Enabled() bool {
+	return c.Secret != ""
+}
+
+// Verify reports whether a request with the given Authorization header,
+// signature header and body is authenticated with the secret.
+func (c Config) Verify(authorization, signature string, body []byte) bool {
+	if !c.Enabled() {
+		return false
+	}
+
+	if token := strings.TrimPrefix(authorization, "Bearer "); token != authorization {
+		return hmac.Equal([]byte(token), []byte(c.Secret))
+	}
+
+	if signature == "" {
+		return false
+	}
+
+	expected, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(c.Secret))
+	mac.Write(body)
+
+	return hmac.Equal(mac.Sum(nil), expected)
+}
@@ -0,0 +1,53 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gohugoio/hugo/config"
+)
+
+func TestDecodeConfig(t *testing.T) {
+	c := qt.New(t)
+
+	cfg, err := config.FromConfigString(`[server.webhook]
+secret = "s3cret"
+`, "toml")
+	c.Assert(err, qt.IsNil)
+
+	w, err := DecodeConfig(cfg)
+	c.Assert(err, qt.IsNil)
+
+	c.Assert(w.Enabled(), qt.IsTrue)
+	c.Assert(w.SignatureHeader, qt.Equals, "X-Hub-Signature-256")
+
+	body := []byte(`{"event":"publish"}`)
+	// echo -n '{"event":"publish"}' | openssl dgst -sha256 -hmac s3cret
+	signature := "sha256=3f41597744dcac7029cf2182b19c43e2a11c3cf53b2a40669ec2c4b98e2243ef"
+
+	c.Assert(w.Verify("Bearer s3cret", "", body), qt.IsTrue)
+	c.Assert(w.Verify("Bearer wrong", "", body), qt.IsFalse)
+	c.Assert(w.Verify("", signature, body), qt.IsTrue)
+	c.Assert(w.Verify("", strings.TrimPrefix(signature, "sha256="), body), qt.IsTrue)
+	c.Assert(w.Verify("", signature, []byte(`{"event":"unpublish"}`)), qt.IsFalse)
+	c.Assert(w.Verify("", "", body), qt.IsFalse)
+
+	w, err = DecodeConfig(config.New())
+	c.Assert(err, qt.IsNil)
+	c.Assert(w.Enabled(), qt.IsFalse)
+	c.Assert(w.Verify("Bearer ", "", body), qt.IsFalse)
+}
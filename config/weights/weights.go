@@ -0,0 +1,80 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package weights configures how weights are assigned to the pages and
+// sections in a section with no weight set in front matter.
+package weights
+
+import (
+	"strings"
+
+	"github.com/gohugoio/hugo/config"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+)
+
+const weightsConfigKey = "weights"
+
+// The strategies to assign weights to pages, see Config.
+const (
+	ByFilename = "filename"
+	ByData     = "data"
+	ByDate     = "date"
+)
+
+// Config configures how weights are assigned in a section.
+type Config struct {
+	// The order of the pages: "filename" orders them by file or directory
+	// name in natural order, "data" by a list in a data file, the pages not
+	// listed last by filename, and "date" by date, the oldest first.
+	Strategy string
+
+	// The path to the list in the data files when ordering by "data", e.g.
+	// "docs.order" for a list in data/docs/order.yaml or under the order key
+	// in data/docs.yaml. The entries are the page file or directory names,
+	// e.g. "install.md" or "getting-started".
+	Data string
+
+	// Reverse reverses the order.
+	Reverse bool
+}
+
+// DecodeConfig decodes the weights config, the weight strategies keyed by
+// section path, e.g. "docs" or "docs/guides".
+func DecodeConfig(cfg config.Provider) (map[string]Config, error) {
+	m := cfg.GetStringMap(weightsConfigKey)
+	if m == nil {
+		return nil, nil
+	}
+
+	weights := make(map[string]Config)
+	for k, v := range m {
+		var c Config
+		if err := mapstructure.WeakDecode(v, &c); err != nil {
+			return nil, errors.Wrapf(err, "failed to decode weights config for section %q", k)
+		}
+		c.Strategy = strings.ToLower(c.Strategy)
+		switch c.Strategy {
+		case ByFilename, ByDate:
+		case ByData:
+			if c.Data == "" {
+				return nil, errors.Errorf("weights: section %q: no data path set", k)
+			}
+		default:
+			return nil, errors.Errorf("weights: section %q: strategy must be one of %q, %q or %q, got %q", k, ByFilename, ByData, ByDate, c.Strategy)
+		}
+		weights[strings.ToLower(strings.Trim(k, "/"))] = c
+	}
+
+	return weights, nil
+}
@@ -21,6 +21,9 @@ import (
 	"time"
 
 	"github.com/gohugoio/hugo/common/paths"
+	"github.com/gohugoio/hugo/parser"
+	"github.com/gohugoio/hugo/parser/metadecoders"
+	"github.com/gohugoio/hugo/resources/page/pagemeta"
 
 	"github.com/pkg/errors"
 
@@ -145,5 +148,29 @@ func executeArcheTypeAsTemplate(s *hugolib.Site, name, kind, targetPath, archety
 
 	archetypeContent = []byte(archetypeShortcodeReplacementsPost.Replace(buff.String()))
 
-	return archetypeContent, nil
+	return applyFrontMatterFormat(s, kind, archetypeContent)
+}
+
+// applyFrontMatterFormat converts the front matter in content to the format
+// configured for the archetype kind, if any.
+func applyFrontMatterFormat(s *hugolib.Site, kind string, content []byte) ([]byte, error) {
+	cfg, err := pagemeta.DecodeFrontMatterFormat(s.Cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	format := cfg.FormatFor(kind)
+	if format == "" {
+		return content, nil
+	}
+
+	converted, err := parser.ConvertFrontMatter(bytes.NewReader(content), metadecoders.FormatFromString(format))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to convert front matter to %s", format)
+	}
+	if converted == nil {
+		return content, nil
+	}
+
+	return converted, nil
 }
@@ -91,6 +91,30 @@ func TestNewContent(t *testing.T) {
 	}
 }
 
+func TestNewContentFrontMatterFormat(t *testing.T) {
+	c := qt.New(t)
+	mm := afero.NewMemMapFs()
+	c.Assert(initFs(mm), qt.IsNil)
+	cfg, fs := newTestCfg(c, mm)
+	cfg.Set("frontmatter", map[string]interface{}{
+		"format": "yaml",
+		"archetypeFormats": map[string]interface{}{
+			"product": "json",
+		},
+	})
+	h, err := hugolib.NewHugoSites(deps.DepsCfg{Cfg: cfg, Fs: fs})
+	c.Assert(err, qt.IsNil)
+
+	c.Assert(create.NewContent(h, "post", "post/sample-1.md"), qt.IsNil)
+	c.Assert(create.NewContent(h, "product", "product/sample-2.md"), qt.IsNil)
+	c.Assert(create.NewContent(h, "lang", "post/lang-1.md"), qt.IsNil)
+
+	cContains(c, readFileFromFs(t, fs.Source, filepath.Join("content", "post/sample-1.md")), "---\n", "title: Post Arch title", "test: test1")
+	cContains(c, readFileFromFs(t, fs.Source, filepath.Join("content", "product/sample-2.md")), `"title": "SAMPLE-2"`)
+	// No front matter.
+	c.Assert(readFileFromFs(t, fs.Source, filepath.Join("content", "post/lang-1.md")), qt.Equals, "Site Lang: en|Name: Lang 1|i18n: Hugo Rocks!")
+}
+
 func TestNewContentFromDir(t *testing.T) {
 	mm := afero.NewMemMapFs()
 	c := qt.New(t)
@@ -10,6 +10,7 @@ import (
 	"github.com/gohugoio/hugo/cache/filecache"
 	"github.com/gohugoio/hugo/common/loggers"
 	"github.com/gohugoio/hugo/config"
+	"github.com/gohugoio/hugo/config/secrets"
 	"github.com/gohugoio/hugo/helpers"
 	"github.com/gohugoio/hugo/hugofs"
 	"github.com/gohugoio/hugo/langs"
@@ -63,6 +64,10 @@ type Deps struct {
 	// The file cache to use.
 	FileCaches filecache.Caches
 
+	// Decrypts the encrypted front matter values and signing keys. Nil if
+	// no secrets key is configured.
+	Secrets *secrets.Decrypter
+
 	// The translation func to use
 	Translate func(translationID string, templateData interface{}) string `json:"-"`
 
@@ -91,6 +96,10 @@ type Deps struct {
 	// BuildStartListeners will be notified before a build starts.
 	BuildStartListeners *Listeners
 
+	// CacheInvalidationListeners will be notified with the keys of the cached
+	// values to invalidate, e.g. by a webhook in server mode.
+	CacheInvalidationListeners *KeyListeners
+
 	// Resources that gets closed when the build is done or the server shuts down.
 	BuildClosers *Closers
 
@@ -156,6 +165,36 @@ func (b *Listeners) Notify() {
 	}
 }
 
+// KeyListeners represents an event listener notified with a set of keys.
+type KeyListeners struct {
+	sync.Mutex
+
+	// A list of funcs to be notified about an event.
+	listeners []func(keys ...string)
+}
+
+// Add adds a function to a KeyListeners instance.
+func (b *KeyListeners) Add(f func(keys ...string)) {
+	if b == nil {
+		return
+	}
+	b.Lock()
+	defer b.Unlock()
+	b.listeners = append(b.listeners, f)
+}
+
+// Notify executes all listener functions with the given keys.
+func (b *KeyListeners) Notify(keys ...string) {
+	if b == nil {
+		return
+	}
+	b.Lock()
+	defer b.Unlock()
+	for _, notify := range b.listeners {
+		notify(keys...)
+	}
+}
+
 // ResourceProvider is used to create and refresh, and clone resources needed.
 type ResourceProvider interface {
 	Update(deps *Deps) error
@@ -240,6 +279,15 @@ func New(cfg DepsCfg) (*Deps, error) {
 		return nil, errors.WithMessage(err, "failed to create file caches from configuration")
 	}
 
+	secretsConfig, err := secrets.DecodeConfig(cfg.Cfg)
+	if err != nil {
+		return nil, err
+	}
+	secretsDecrypter, err := secrets.New(secretsConfig, fs.Source, cfg.Cfg.GetString("workingDir"))
+	if err != nil {
+		return nil, err
+	}
+
 	errorHandler := &globalErrHandler{}
 	buildState := &BuildState{}
 
@@ -248,7 +296,7 @@ func New(cfg DepsCfg) (*Deps, error) {
 		return nil, err
 	}
 
-	contentSpec, err := helpers.NewContentSpec(cfg.Language, logger, ps.BaseFs.Content.Fs)
+	contentSpec, err := helpers.NewContentSpec(cfg.Language, logger, ps.BaseFs.Content.Fs, ps.BaseFs.Assets.Fs)
 	if err != nil {
 		return nil, err
 	}
@@ -266,27 +314,29 @@ func New(cfg DepsCfg) (*Deps, error) {
 	logDistinct := helpers.NewDistinctLogger(logger)
 
 	d := &Deps{
-		Fs:                      fs,
-		Log:                     ignorableLogger,
-		LogDistinct:             logDistinct,
-		templateProvider:        cfg.TemplateProvider,
-		translationProvider:     cfg.TranslationProvider,
-		WithTemplate:            cfg.WithTemplate,
-		OverloadedTemplateFuncs: cfg.OverloadedTemplateFuncs,
-		PathSpec:                ps,
-		ContentSpec:             contentSpec,
-		SourceSpec:              sp,
-		ResourceSpec:            resourceSpec,
-		Cfg:                     cfg.Language,
-		Language:                cfg.Language,
-		Site:                    cfg.Site,
-		FileCaches:              fileCaches,
-		BuildStartListeners:     &Listeners{},
-		BuildClosers:            &Closers{},
-		BuildState:              buildState,
-		Running:                 cfg.Running,
-		Timeout:                 time.Duration(timeoutms) * time.Millisecond,
-		globalErrHandler:        errorHandler,
+		Fs:                         fs,
+		Log:                        ignorableLogger,
+		LogDistinct:                logDistinct,
+		templateProvider:           cfg.TemplateProvider,
+		translationProvider:        cfg.TranslationProvider,
+		WithTemplate:               cfg.WithTemplate,
+		OverloadedTemplateFuncs:    cfg.OverloadedTemplateFuncs,
+		PathSpec:                   ps,
+		ContentSpec:                contentSpec,
+		SourceSpec:                 sp,
+		ResourceSpec:               resourceSpec,
+		Cfg:                        cfg.Language,
+		Language:                   cfg.Language,
+		Site:                       cfg.Site,
+		FileCaches:                 fileCaches,
+		Secrets:                    secretsDecrypter,
+		BuildStartListeners:        &Listeners{},
+		CacheInvalidationListeners: &KeyListeners{},
+		BuildClosers:               &Closers{},
+		BuildState:                 buildState,
+		Running:                    cfg.Running,
+		Timeout:                    time.Duration(timeoutms) * time.Millisecond,
+		globalErrHandler:           errorHandler,
 	}
 
 	if cfg.Cfg.GetBool("templateMetrics") {
@@ -311,7 +361,7 @@ func (d Deps) ForLanguage(cfg DepsCfg, onCreated func(d *Deps) error) (*Deps, er
 		return nil, err
 	}
 
-	d.ContentSpec, err = helpers.NewContentSpec(l, d.Log, d.BaseFs.Content.Fs)
+	d.ContentSpec, err = helpers.NewContentSpec(l, d.Log, d.BaseFs.Content.Fs, d.BaseFs.Assets.Fs)
 	if err != nil {
 		return nil, err
 	}
@@ -320,14 +370,14 @@ func (d Deps) ForLanguage(cfg DepsCfg, onCreated func(d *Deps) error) (*Deps, er
 
 	// These are common for all sites, so reuse.
 	// TODO(bep) clean up these inits.
-	resourceCache := d.ResourceSpec.ResourceCache
-	postBuildAssets := d.ResourceSpec.PostBuildAssets
+	prevResourceSpec := d.ResourceSpec
 	d.ResourceSpec, err = resources.NewSpec(d.PathSpec, d.ResourceSpec.FileCaches, d.BuildState, d.Log, d.globalErrHandler, cfg.OutputFormats, cfg.MediaTypes)
 	if err != nil {
 		return nil, err
 	}
-	d.ResourceSpec.ResourceCache = resourceCache
-	d.ResourceSpec.PostBuildAssets = postBuildAssets
+	d.ResourceSpec.ResourceCache = prevResourceSpec.ResourceCache
+	d.ResourceSpec.PostBuildAssets = prevResourceSpec.PostBuildAssets
+	d.ResourceSpec.ShareImageCache(prevResourceSpec)
 
 	d.Cfg = l
 	d.Language = l
@@ -338,6 +388,9 @@ func (d Deps) ForLanguage(cfg DepsCfg, onCreated func(d *Deps) error) (*Deps, er
 		}
 	}
 
+	// The template funcs created in Clone below register with this.
+	d.CacheInvalidationListeners = &KeyListeners{}
+
 	if err := d.translationProvider.Clone(&d); err != nil {
 		return nil, err
 	}
@@ -393,12 +446,47 @@ type DepsCfg struct {
 // BuildState are flags that may be turned on during a build.
 type BuildState struct {
 	counter uint64
+
+	// The earliest expiry, in Unix nanoseconds, of the values cached with a
+	// TTL and used in the current build.
+	cacheExpiry int64
 }
 
 func (b *BuildState) Incr() int {
 	return int(atomic.AddUint64(&b.counter, uint64(1)))
 }
 
+// AddCacheExpiry registers the expiry of a value cached with a TTL and used
+// in the current build, e.g. a cached partial.
+func (b *BuildState) AddCacheExpiry(t time.Time) {
+	expiry := t.UnixNano()
+	for {
+		current := atomic.LoadInt64(&b.cacheExpiry)
+		if current != 0 && current <= expiry {
+			return
+		}
+		if atomic.CompareAndSwapInt64(&b.cacheExpiry, current, expiry) {
+			return
+		}
+	}
+}
+
+// CacheExpiry returns the earliest expiry of the values cached with a TTL and
+// used in the current build, zero if none. In server mode, the sites are
+// rebuilt when this expires.
+func (b *BuildState) CacheExpiry() time.Time {
+	expiry := atomic.LoadInt64(&b.cacheExpiry)
+	if expiry == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, expiry)
+}
+
+// ResetCacheExpiry resets the cache expiry, e.g. when a new build starts.
+func (b *BuildState) ResetCacheExpiry() {
+	atomic.StoreInt64(&b.cacheExpiry, 0)
+}
+
 func NewBuildState() BuildState {
 	return BuildState{}
 }
@@ -1,12 +1,14 @@
 module github.com/gohugoio/hugo
 
 require (
+	filippo.io/age v1.0.0
 	github.com/BurntSushi/locker v0.0.0-20171006230638-a6e239ea1c69
 	github.com/BurntSushi/toml v0.3.1
 	github.com/PuerkitoBio/purell v1.1.1
 	github.com/PuerkitoBio/urlesc v0.0.0-20170810143723-de5bf2ad4578 // indirect
 	github.com/alecthomas/chroma v0.9.2
 	github.com/alecthomas/repr v0.0.0-20181024024818-d37bc2a10ba1 // indirect
+	github.com/andybalholm/brotli v1.0.3
 	github.com/armon/go-radix v1.0.0
 	github.com/aws/aws-sdk-go v1.38.23
 	github.com/bep/debounce v1.2.0
@@ -31,8 +33,10 @@ require (
 	github.com/google/go-cmp v0.5.5
 	github.com/gorilla/websocket v1.4.2
 	github.com/jdkato/prose v1.2.1
+	github.com/kljensen/snowball v0.6.0
 	github.com/kylelemons/godebug v1.1.0
 	github.com/kyokomi/emoji/v2 v2.2.8
+	github.com/lib/pq v1.10.9
 	github.com/magefile/mage v1.11.0
 	github.com/mattn/go-isatty v0.0.12
 	github.com/miekg/mmark v1.3.6
@@ -56,6 +60,8 @@ require (
 	github.com/spf13/pflag v1.0.5
 	github.com/spf13/viper v1.7.0
 	github.com/tdewolff/minify/v2 v2.9.18
+	github.com/tdewolff/parse/v2 v2.5.18
+	github.com/tetratelabs/wazero v1.0.0
 	github.com/yuin/goldmark v1.3.9
 	github.com/yuin/goldmark-highlighting v0.0.0-20200307114337-60d527fdb691
 	gocloud.dev v0.20.0
@@ -67,6 +73,7 @@ require (
 	gopkg.in/check.v1 v1.0.0-20190902080502-41f04d3bba15 // indirect
 	gopkg.in/ini.v1 v1.51.1 // indirect
 	gopkg.in/yaml.v2 v2.4.0
+	modernc.org/sqlite v1.14.8
 )
 
 go 1.16
@@ -31,6 +31,7 @@ import (
 	"github.com/gohugoio/hugo/markup/converter"
 
 	"github.com/gohugoio/hugo/markup"
+	"github.com/gohugoio/hugo/markup/sanitize"
 
 	bp "github.com/gohugoio/hugo/bufferpool"
 	"github.com/gohugoio/hugo/config"
@@ -52,6 +53,9 @@ type ContentSpec struct {
 	MardownConverter    converter.Converter // Markdown converter with no document context
 	anchorNameSanitizer converter.AnchorNameSanitizer
 
+	// Sanitizer sanitizes rendered HTML using the configured policies.
+	Sanitizer *sanitize.Sanitizer
+
 	// SummaryLength is the length of the summary that Hugo extracts from a content.
 	summaryLength int
 
@@ -64,7 +68,7 @@ type ContentSpec struct {
 
 // NewContentSpec returns a ContentSpec initialized
 // with the appropriate fields from the given config.Provider.
-func NewContentSpec(cfg config.Provider, logger loggers.Logger, contentFs afero.Fs) (*ContentSpec, error) {
+func NewContentSpec(cfg config.Provider, logger loggers.Logger, contentFs, assetsFs afero.Fs) (*ContentSpec, error) {
 	spec := &ContentSpec{
 		summaryLength: cfg.GetInt("summaryLength"),
 		BuildFuture:   cfg.GetBool("buildFuture"),
@@ -77,6 +81,7 @@ func NewContentSpec(cfg config.Provider, logger loggers.Logger, contentFs afero.
 	converterProvider, err := markup.NewConverterProvider(converter.ProviderConfig{
 		Cfg:       cfg,
 		ContentFs: contentFs,
+		AssetsFs:  assetsFs,
 		Logger:    logger,
 	})
 	if err != nil {
@@ -84,6 +89,12 @@ func NewContentSpec(cfg config.Provider, logger loggers.Logger, contentFs afero.
 	}
 
 	spec.Converters = converterProvider
+
+	spec.Sanitizer, err = sanitize.New(converterProvider.GetMarkupConfig().Sanitize)
+	if err != nil {
+		return nil, err
+	}
+
 	p := converterProvider.Get("markdown")
 	conv, err := p.New(converter.DocumentContext{})
 	if err != nil {
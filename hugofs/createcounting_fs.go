@@ -33,6 +33,11 @@ type DuplicatesReporter interface {
 	ReportDuplicates() string
 }
 
+// CreatedFilesReporter reports about the files created.
+type CreatedFilesReporter interface {
+	CreatedFiles() []string
+}
+
 func NewCreateCountingFs(fs afero.Fs) afero.Fs {
 	return &createCountingFs{Fs: fs, fileCount: make(map[string]int)}
 }
@@ -59,6 +64,22 @@ func (c *createCountingFs) ReportDuplicates() string {
 	return strings.Join(dupes, ", ")
 }
 
+// CreatedFiles returns the sorted filenames of the files created or opened
+// for writing.
+func (c *createCountingFs) CreatedFiles() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	filenames := make([]string, 0, len(c.fileCount))
+	for k := range c.fileCount {
+		filenames = append(filenames, k)
+	}
+
+	sort.Strings(filenames)
+
+	return filenames
+}
+
 // createCountingFs counts filenames of created files or files opened
 // for writing.
 type createCountingFs struct {
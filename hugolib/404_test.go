@@ -15,6 +15,8 @@ package hugolib
 
 import (
 	"testing"
+
+	qt "github.com/frankban/quicktest"
 )
 
 func Test404(t *testing.T) {
@@ -77,3 +79,40 @@ Page not found
 Base:
 Page not found`)
 }
+
+func Test404PerSectionAndLanguage(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t)
+	b.WithConfigFile("toml", `
+baseURL = "https://example.org/"
+defaultContentLanguage = "en"
+[languages]
+[languages.en]
+weight = 1
+[languages.de]
+weight = 2
+`)
+
+	b.WithContent(
+		"docs/_index.md", "---\ntitle: Docs\n---",
+		"docs/_index.de.md", "---\ntitle: Dokumentation\n---",
+		"blog/_index.md", "---\ntitle: Blog\n---",
+	)
+
+	b.WithTemplatesAdded(
+		"404.html", `404 root: {{ .Lang }}|Section: {{ .Section }}|`,
+		"404.de.html", `404 root de: {{ .Lang }}|`,
+		"docs/404.html", `404 docs: {{ .Lang }}|Section: {{ .Section }}|Parent: {{ .Parent.Title }}|CurrentSection: {{ .CurrentSection.Title }}|{{ .RelPermalink }}|`,
+	)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/404.html", "404 root: en|Section: |")
+	b.AssertFileContent("public/de/404.html", "404 root de: de|")
+	b.AssertFileContent("public/docs/404.html", "404 docs: en|Section: docs|Parent: Docs|CurrentSection: Docs|/docs/404.html|")
+	b.AssertFileContent("public/de/docs/404.html", "404 docs: de|Section: docs|Parent: Dokumentation|CurrentSection: Dokumentation|/de/docs/404.html|")
+
+	// No section specific template.
+	b.Assert(b.CheckExists("public/blog/404.html"), qt.IsFalse)
+}
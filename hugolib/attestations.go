@@ -0,0 +1,212 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"html"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gohugoio/hugo/common/hexec"
+	"github.com/gohugoio/hugo/common/hugo"
+	"github.com/gohugoio/hugo/config"
+	attestationsconfig "github.com/gohugoio/hugo/config/attestations"
+	"github.com/gohugoio/hugo/tpl"
+	"github.com/spf13/afero"
+)
+
+// pageAttestation traces a published page back to the inputs it was built
+// from.
+type pageAttestation struct {
+	// The published file, relative to the publish dir.
+	Target string `json:"target"`
+
+	// The content file, if any.
+	Source *sourceAttestation `json:"source,omitempty"`
+
+	// The last commit of the content file with enableGitInfo, else the
+	// commit checked out in the working dir, if any.
+	Commit string `json:"commit,omitempty"`
+
+	// The SHA-256 hashes of the templates, keyed by name.
+	Templates map[string]string `json:"templates"`
+}
+
+type sourceAttestation struct {
+	// The path relative to the content dir.
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+}
+
+// siteAttestation is the attestation document of the sites.
+type siteAttestation struct {
+	HugoVersion string            `json:"hugoVersion"`
+	Commit      string            `json:"commit,omitempty"`
+	Pages       []pageAttestation `json:"pages"`
+}
+
+// attestations collects the attestations of the pages published in the
+// build.
+type attestations struct {
+	cfg        attestationsconfig.Config
+	workingDir string
+	execCfg    config.Provider
+
+	commitInit sync.Once
+	commit     string
+
+	mu    sync.Mutex
+	pages map[string]pageAttestation
+}
+
+func newAttestations(cfg config.Provider) (*attestations, error) {
+	c, err := attestationsconfig.DecodeConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &attestations{
+		cfg:        c,
+		workingDir: cfg.GetString("workingDir"),
+		execCfg:    cfg,
+		pages:      make(map[string]pageAttestation),
+	}, nil
+}
+
+// headCommit returns the commit checked out in the working dir, empty if
+// not in a Git repository.
+func (a *attestations) headCommit() string {
+	a.commitInit.Do(func() {
+		cmd, done, err := hexec.Command(a.execCfg, a.workingDir, "git", "rev-parse", "HEAD")
+		if err != nil {
+			return
+		}
+		defer done()
+		out, err := cmd.Output()
+		if err != nil {
+			return
+		}
+		a.commit = strings.TrimSpace(string(out))
+	})
+	return a.commit
+}
+
+// attest creates the attestation of p rendered to targetPath with templ.
+func (a *attestations) attest(p *pageState, targetPath string, templ tpl.Template) pageAttestation {
+	pa := pageAttestation{
+		Target:    filepath.ToSlash(strings.TrimPrefix(targetPath, string(filepath.Separator))),
+		Templates: make(map[string]string),
+	}
+
+	if !p.File().IsZero() && p.source.parsed != nil {
+		sum := sha256.Sum256(p.source.parsed.Input())
+		pa.Source = &sourceAttestation{
+			Path:   filepath.ToSlash(p.File().Path()),
+			SHA256: hex.EncodeToString(sum[:]),
+		}
+	}
+
+	if gi := p.GitInfo(); gi != nil {
+		pa.Commit = gi.Hash
+	} else {
+		pa.Commit = a.headCommit()
+	}
+
+	if hp, ok := templ.(tpl.HashesProvider); ok {
+		for k, v := range hp.TemplateHashes() {
+			pa.Templates[k] = v
+		}
+	}
+
+	return pa
+}
+
+// add stores the attestation of a published page.
+func (a *attestations) add(pa pageAttestation) {
+	a.mu.Lock()
+	a.pages[pa.Target] = pa
+	a.mu.Unlock()
+}
+
+// embed returns the HTML document b with the attestation pa embedded as
+// configured.
+func (a *attestations) embed(b []byte, pa pageAttestation) []byte {
+	if a.cfg.Embed == "none" {
+		return b
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	// Escapes <, > and &, so it can't end the comment or the tag.
+	enc.SetEscapeHTML(true)
+	if err := enc.Encode(pa); err != nil {
+		return b
+	}
+	js := bytes.TrimSpace(buf.Bytes())
+
+	if a.cfg.Embed == "meta" {
+		if i := bytes.Index(bytes.ToLower(b), []byte("</head>")); i != -1 {
+			meta := `<meta name="hugo-attestation" content="` + html.EscapeString(string(js)) + `">`
+			return append(append(append([]byte{}, b[:i]...), meta...), b[i:]...)
+		}
+	}
+
+	// A comment can't contain "--".
+	js = bytes.ReplaceAll(js, []byte("--"), []byte(`-\u002d`))
+
+	return append(append(append(append([]byte{}, b...), "\n<!-- hugo-attestation "...), js...), " -->\n"...)
+}
+
+// write writes the site attestation document to fs.
+func (a *attestations) write(fs afero.Fs) error {
+	doc := siteAttestation{
+		HugoVersion: hugo.CurrentVersion.String(),
+		Commit:      a.headCommit(),
+	}
+
+	a.mu.Lock()
+	for _, pa := range a.pages {
+		doc.Pages = append(doc.Pages, pa)
+	}
+	a.mu.Unlock()
+
+	sort.Slice(doc.Pages, func(i, j int) bool {
+		return doc.Pages[i].Target < doc.Pages[j].Target
+	})
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+
+	return afero.WriteFile(fs, a.cfg.Filename, buf.Bytes(), 0666)
+}
+
+// writeAttestations writes the site attestation document to the publish
+// dir, when enabled.
+func (h *HugoSites) writeAttestations() error {
+	if !h.attestations.cfg.Enable {
+		return nil
+	}
+	return h.attestations.write(h.BaseFs.PublishFs)
+}
@@ -0,0 +1,110 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestAttestations(t *testing.T) {
+	const content = `---
+title: "P1"
+---
+Content.
+`
+	sum := sha256.Sum256([]byte(content))
+	contentHash := hex.EncodeToString(sum[:])
+
+	partial := `<footer>Footer</footer>`
+	sum = sha256.Sum256([]byte(partial))
+	partialHash := hex.EncodeToString(sum[:])
+
+	newBuilder := func(embed string) *sitesBuilder {
+		b := newTestSitesBuilder(t)
+		b.WithConfigFile("toml", `
+baseURL = "https://example.org"
+disableKinds = ["taxonomy", "term", "section", "sitemap", "RSS", "robotsTXT", "404"]
+[attestations]
+enable = true
+embed = "`+embed+`"
+`)
+		b.WithContent("p1.md", content)
+		b.WithTemplates(
+			"_default/baseof.html", `<html><head><title>{{ .Title }}</title></head><body>{{ block "main" . }}{{ end }}{{ partial "footer.html" . }}</body></html>`,
+			"_default/single.html", `{{ define "main" }}{{ .Content }}{{ end }}`,
+			"index.html", `Home`,
+			"partials/footer.html", partial,
+		)
+		return b
+	}
+
+	c := qt.New(t)
+
+	c.Run("Comment", func(c *qt.C) {
+		b := newBuilder("comment")
+		b.Build(BuildCfg{})
+
+		html := b.FileContent("public/p1/index.html")
+		m := regexp.MustCompile(`</html>\n<!-- hugo-attestation (.*) -->\n$`).FindStringSubmatch(html)
+		c.Assert(m, qt.HasLen, 2)
+
+		var pa pageAttestation
+		c.Assert(json.Unmarshal([]byte(m[1]), &pa), qt.IsNil)
+		c.Assert(pa.Target, qt.Equals, "p1/index.html")
+		c.Assert(pa.Source, qt.DeepEquals, &sourceAttestation{Path: "p1.md", SHA256: contentHash})
+		c.Assert(pa.Templates["partials/footer.html"], qt.Equals, partialHash)
+		c.Assert(pa.Templates["_default/single.html"], qt.Not(qt.Equals), "")
+		c.Assert(pa.Templates["_default/baseof.html"], qt.Not(qt.Equals), "")
+
+		var doc siteAttestation
+		c.Assert(json.Unmarshal([]byte(b.FileContent("public/attestation.json")), &doc), qt.IsNil)
+		c.Assert(doc.HugoVersion, qt.Not(qt.Equals), "")
+		c.Assert(doc.Pages, qt.HasLen, 2)
+		c.Assert(doc.Pages[0].Target, qt.Equals, "index.html")
+		c.Assert(doc.Pages[0].Source, qt.IsNil)
+		c.Assert(doc.Pages[1], qt.DeepEquals, pa)
+	})
+
+	c.Run("Meta", func(c *qt.C) {
+		b := newBuilder("meta")
+		b.Build(BuildCfg{})
+
+		b.AssertFileContent("public/p1/index.html", `<meta name="hugo-attestation" content="{&#34;target&#34;:&#34;p1/index.html&#34;,&#34;source&#34;:{&#34;path&#34;:&#34;p1.md&#34;,&#34;sha256&#34;:&#34;`+contentHash+`&#34;}`, "</head>")
+		c.Assert(b.FileContent("public/p1/index.html"), qt.Not(qt.Contains), "<!-- hugo-attestation")
+		// No head.
+		b.AssertFileContent("public/index.html", "Home\n<!-- hugo-attestation ")
+	})
+
+	c.Run("Escape comment", func(c *qt.C) {
+		a := &attestations{}
+		a.cfg.Embed = "comment"
+		b := a.embed([]byte("<p>"), pageAttestation{Target: "a--b/index.html", Source: &sourceAttestation{Path: "a--b.md"}})
+		c.Assert(string(b), qt.Equals, "<p>\n<!-- hugo-attestation {\"target\":\"a-\\u002db/index.html\",\"source\":{\"path\":\"a-\\u002db.md\",\"sha256\":\"\"},\"templates\":null} -->\n")
+	})
+
+	c.Run("Disabled", func(c *qt.C) {
+		b := newTestSitesBuilder(t)
+		b.WithContent("p1.md", content)
+		b.Build(BuildCfg{})
+
+		c.Assert(b.FileContent("public/p1/index.html"), qt.Not(qt.Contains), "hugo-attestation")
+		c.Assert(b.CheckExists("public/attestation.json"), qt.IsFalse)
+	})
+}
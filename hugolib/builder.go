@@ -0,0 +1,212 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/gohugoio/hugo/common/loggers"
+	"github.com/gohugoio/hugo/config"
+	"github.com/gohugoio/hugo/deps"
+	"github.com/gohugoio/hugo/hugofs"
+	"github.com/gohugoio/hugo/hugolib/paths"
+	"github.com/gohugoio/hugo/resources/page"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	jww "github.com/spf13/jwalterweatherman"
+)
+
+// BuilderConfig configures a Builder.
+type BuilderConfig struct {
+	// The project directory. This is required.
+	WorkingDir string
+
+	// The file system to read the project from. Defaults to the OS file
+	// system.
+	Fs afero.Fs
+
+	// The file system to publish to, below publishDir. Defaults to Fs.
+	PublishFs afero.Fs
+
+	// The build environment, e.g. production. Defaults to production.
+	Environment string
+
+	// Comma separated list of config files to load, relative to WorkingDir.
+	// Defaults to config.toml, config.yaml or config.json.
+	ConfigFilename string
+
+	// Configuration that overrides the values set in the config files,
+	// e.g. {"baseURL": "https://example.org/", "buildDrafts": true}.
+	Config map[string]interface{}
+
+	// The Logger to use. Defaults to a logger writing warnings and errors
+	// to stderr.
+	Logger loggers.Logger
+}
+
+// Builder builds the Hugo project described by a BuilderConfig. It is the
+// supported way of embedding Hugo builds in other Go programs.
+type Builder struct {
+	cfg BuilderConfig
+}
+
+// NewBuilder creates a new Builder for the given configuration.
+func NewBuilder(cfg BuilderConfig) *Builder {
+	return &Builder{cfg: cfg}
+}
+
+// BuildResult holds the result of a build.
+type BuildResult struct {
+	// The sites built, one per language.
+	Sites *HugoSites
+
+	// All the pages built in all languages.
+	Pages page.Pages
+
+	// The build error, if any, followed by the errors logged during the
+	// build.
+	Errors []error
+
+	// The files written, relative to publishDir and sorted.
+	PublishedFiles []string
+}
+
+// Err returns the first error in Errors, or nil if there is none.
+func (r *BuildResult) Err() error {
+	if len(r.Errors) == 0 {
+		return nil
+	}
+	return r.Errors[0]
+}
+
+// Build does a full build of all sites, reading the configuration from
+// scratch. Static files are not copied, that is left to the caller.
+//
+// The returned error is set if the project could not be configured or if
+// the build failed; in the latter case the BuildResult is also returned.
+// The context is checked for cancellation before the build starts, an
+// ongoing build cannot be cancelled.
+func (b *Builder) Build(ctx context.Context) (*BuildResult, error) {
+	if b.cfg.WorkingDir == "" {
+		return nil, errors.New("builder: WorkingDir must be set")
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	sourceFs := b.cfg.Fs
+	if sourceFs == nil {
+		sourceFs = hugofs.Os
+	}
+
+	logger := b.cfg.Logger
+	if logger == nil {
+		logger = loggers.NewLogger(jww.LevelWarn, jww.LevelError, os.Stderr, ioutil.Discard, true)
+	}
+
+	cfg, _, err := LoadConfig(
+		ConfigSourceDescriptor{
+			Fs:           sourceFs,
+			Logger:       logger,
+			WorkingDir:   b.cfg.WorkingDir,
+			Filename:     b.cfg.ConfigFilename,
+			AbsConfigDir: filepath.Join(b.cfg.WorkingDir, "config"),
+			Environment:  b.cfg.Environment,
+		},
+		func(cfg config.Provider) error {
+			for k, v := range b.cfg.Config {
+				cfg.Set(k, v)
+			}
+			cfg.Set("workingDir", b.cfg.WorkingDir)
+			if b.cfg.Environment != "" {
+				cfg.Set("environment", b.cfg.Environment)
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, errors.Wrap(err, "builder: failed to load config")
+	}
+
+	fs := hugofs.NewFrom(sourceFs, cfg)
+	if b.cfg.PublishFs != nil {
+		fs.Destination = b.cfg.PublishFs
+	}
+	fs.Destination = hugofs.NewCreateCountingFs(fs.Destination)
+
+	sites, err := NewHugoSites(deps.DepsCfg{Fs: fs, Cfg: cfg, Logger: logger})
+	if err != nil {
+		return nil, errors.Wrap(err, "builder: failed to create sites")
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	result := &BuildResult{Sites: sites}
+
+	if err := sites.Build(BuildCfg{}); err != nil {
+		result.Errors = append(result.Errors, err)
+	}
+
+	result.Errors = append(result.Errors, parseLoggedErrors(logger.Errors())...)
+	result.Pages = sites.Pages()
+
+	publishDir := paths.AbsPathify(b.cfg.WorkingDir, cfg.GetString("publishDir"))
+	for _, filename := range fs.Destination.(hugofs.CreatedFilesReporter).CreatedFiles() {
+		if rel, err := filepath.Rel(publishDir, filename); err == nil && !strings.HasPrefix(rel, "..") {
+			filename = rel
+		}
+		result.PublishedFiles = append(result.PublishedFiles, filepath.ToSlash(filename))
+	}
+
+	return result, result.Err()
+}
+
+var loggedErrorPrefixRe = regexp.MustCompile(`^ERROR \S+ \S+ `)
+
+// parseLoggedErrors parses the errors saved by the logger, one per ERROR
+// line, with any following lines belonging to the same error.
+func parseLoggedErrors(s string) []error {
+	var (
+		errs    []error
+		current []string
+	)
+
+	flush := func() {
+		if len(current) > 0 {
+			errs = append(errs, errors.New(strings.Join(current, "\n")))
+			current = nil
+		}
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(s), "\n") {
+		if loggedErrorPrefixRe.MatchString(line) {
+			flush()
+			line = loggedErrorPrefixRe.ReplaceAllString(line, "")
+		} else if len(current) == 0 {
+			continue
+		}
+		current = append(current, line)
+	}
+
+	flush()
+
+	return errs
+}
@@ -0,0 +1,135 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/gohugoio/hugo/common/loggers"
+	"github.com/spf13/afero"
+	jww "github.com/spf13/jwalterweatherman"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestBuilder(t *testing.T) {
+	c := qt.New(t)
+
+	newSourceFs := func(single string) afero.Fs {
+		fs := afero.NewMemMapFs()
+		files := map[string]string{
+			"config.toml": `
+baseURL = "https://example.org/"
+disableKinds = ["taxonomy", "term", "RSS", "sitemap", "robotsTXT", "404"]
+[params]
+greeting = "Hello"
+`,
+			"config/staging/params.toml":     `greeting = "Hi"`,
+			"content/p1.md":                  "---\ntitle: P1\n---\n",
+			"content/p2.md":                  "---\ntitle: P2\ndraft: true\n---\n",
+			"layouts/_default/single.html":   single,
+			"layouts/_default/list.html":     "List: {{ site.Params.greeting }}|{{ hugo.Environment }}",
+			"layouts/partials/unused.html":   "",
+			"static/unused-static-file.html": "",
+		}
+		for name, content := range files {
+			c.Assert(afero.WriteFile(fs, filepath.Join("/site", filepath.FromSlash(name)), []byte(content), 0755), qt.IsNil)
+		}
+		return fs
+	}
+
+	c.Run("Basic", func(c *qt.C) {
+		publishFs := afero.NewMemMapFs()
+
+		result, err := NewBuilder(BuilderConfig{
+			WorkingDir: "/site",
+			Fs:         newSourceFs("Single: {{ .Title }}"),
+			PublishFs:  publishFs,
+			Logger:     loggers.NewErrorLogger(),
+		}).Build(context.Background())
+
+		c.Assert(err, qt.IsNil)
+		c.Assert(result.Errors, qt.HasLen, 0)
+		c.Assert(result.Pages, qt.HasLen, 2)
+		c.Assert(result.PublishedFiles, qt.DeepEquals, []string{"index.html", "p1/index.html"})
+
+		b, err := afero.ReadFile(publishFs, filepath.FromSlash("/site/public/index.html"))
+		c.Assert(err, qt.IsNil)
+		c.Assert(string(b), qt.Equals, "List: Hello|production")
+	})
+
+	c.Run("Environment and config", func(c *qt.C) {
+		fs := newSourceFs("Single: {{ .Title }}")
+
+		result, err := NewBuilder(BuilderConfig{
+			WorkingDir:  "/site",
+			Fs:          fs,
+			Environment: "staging",
+			Config:      map[string]interface{}{"buildDrafts": true, "publishDir": "dist"},
+			Logger:      loggers.NewErrorLogger(),
+		}).Build(context.Background())
+
+		c.Assert(err, qt.IsNil)
+		c.Assert(result.PublishedFiles, qt.DeepEquals, []string{"index.html", "p1/index.html", "p2/index.html"})
+
+		b, err := afero.ReadFile(fs, filepath.FromSlash("/site/dist/index.html"))
+		c.Assert(err, qt.IsNil)
+		c.Assert(string(b), qt.Equals, "List: Hi|staging")
+	})
+
+	c.Run("Errors", func(c *qt.C) {
+		var buf bytes.Buffer
+
+		result, err := NewBuilder(BuilderConfig{
+			WorkingDir: "/site",
+			Fs:         newSourceFs(`{{ errorf "failed: %s" .Title }}`),
+			PublishFs:  afero.NewMemMapFs(),
+			Logger:     loggers.NewLogger(jww.LevelError, jww.LevelError, &buf, ioutil.Discard, true),
+		}).Build(context.Background())
+
+		c.Assert(err, qt.Not(qt.IsNil))
+		c.Assert(result.Err(), qt.Equals, err)
+		c.Assert(result.Errors, qt.HasLen, 2)
+		c.Assert(result.Errors[0], qt.ErrorMatches, "logged 1 error.*")
+		c.Assert(result.Errors[1], qt.ErrorMatches, "failed: P1")
+	})
+
+	c.Run("Cancelled", func(c *qt.C) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := NewBuilder(BuilderConfig{WorkingDir: "/site", Fs: newSourceFs("")}).Build(ctx)
+		c.Assert(err, qt.Equals, context.Canceled)
+	})
+
+	c.Run("No working dir", func(c *qt.C) {
+		_, err := NewBuilder(BuilderConfig{}).Build(context.Background())
+		c.Assert(err, qt.ErrorMatches, ".*WorkingDir must be set")
+	})
+}
+
+func TestParseLoggedErrors(t *testing.T) {
+	c := qt.New(t)
+
+	errs := parseLoggedErrors("ERROR 2021/01/01 10:00:00 a\nmore\nERROR 2021/01/01 10:00:01 b\n")
+	c.Assert(errs, qt.HasLen, 2)
+	c.Assert(errs[0], qt.ErrorMatches, "a\nmore")
+	c.Assert(errs[1], qt.ErrorMatches, "b")
+
+	c.Assert(parseLoggedErrors(""), qt.HasLen, 0)
+}
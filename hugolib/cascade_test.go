@@ -115,6 +115,60 @@ cascade:
 
 }
 
+func TestFrontMatterDefaults(t *testing.T) {
+	b := newTestSitesBuilder(t)
+
+	b.WithConfigFile("toml", `
+baseURL="https://example.org"
+
+[frontMatterDefaults.posts]
+toc = true
+author = "Default Author"
+img = "default.jpg"
+
+[frontMatterDefaults.page]
+toc = false
+
+[frontMatterDefaults.Gallery]
+img = "gallery.jpg"
+`)
+
+	b.WithContent(
+		"posts/_index.md", `
+---
+title: "Posts"
+cascade:
+  img: "cascade.jpg"
+---
+`,
+		"posts/p1.md", `
+---
+title: "P1"
+author: "Jane"
+---
+`,
+		"posts/p2.md", `
+---
+title: "P2"
+type: gallery
+---
+`,
+		"p3.md", `
+---
+title: "P3"
+---
+`,
+	)
+
+	b.WithTemplates("_default/single.html", `{{ .Title }}|toc: {{ .Params.toc }}/{{ .ParamSource "toc" }}|author: {{ .Params.author }}/{{ .ParamSource "Author" }}|img: {{ .Params.img }}/{{ .ParamSource "img" }}|title: {{ .ParamSource "title" }}`)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/posts/p1/index.html", "P1|toc: true/defaults|author: Jane/frontmatter|img: cascade.jpg/cascade|title: frontmatter")
+	b.AssertFileContent("public/posts/p2/index.html", "P2|toc: /|author: /|img: cascade.jpg/cascade")
+	b.AssertFileContent("public/p3/index.html", "P3|toc: false/defaults|author: /|img: /")
+}
+
 func TestCascade(t *testing.T) {
 	allLangs := []string{"en", "nn", "nb", "sv"}
 
@@ -33,6 +33,7 @@ import (
 	"github.com/gohugoio/hugo/parser/metadecoders"
 
 	"github.com/gohugoio/hugo/common/herrors"
+	"github.com/gohugoio/hugo/common/hexec"
 	"github.com/gohugoio/hugo/common/hugo"
 	"github.com/gohugoio/hugo/hugolib/paths"
 	"github.com/gohugoio/hugo/langs"
@@ -40,7 +41,9 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/gohugoio/hugo/config"
+	"github.com/gohugoio/hugo/config/netpolicy"
 	"github.com/gohugoio/hugo/config/privacy"
+	"github.com/gohugoio/hugo/config/security"
 	"github.com/gohugoio/hugo/config/services"
 	"github.com/gohugoio/hugo/helpers"
 	"github.com/spf13/afero"
@@ -60,37 +63,15 @@ func LoadConfig(d ConfigSourceDescriptor, doWithConfig ...func(cfg config.Provid
 		d.Environ = os.Environ()
 	}
 
-	var configFiles []string
-
 	l := configLoader{ConfigSourceDescriptor: d, cfg: config.New()}
 	// Make sure we always do this, even in error situations,
 	// as we have commands (e.g. "hugo mod init") that will
 	// use a partial configuration to do its job.
 	defer l.deleteMergeStrategies()
 
-	for _, name := range d.configFilenames() {
-		var filename string
-		filename, err := l.loadConfig(name)
-		if err == nil {
-			configFiles = append(configFiles, filename)
-		} else if err != ErrNoConfigFile {
-			return nil, nil, err
-		}
-	}
-
-	if d.AbsConfigDir != "" {
-		dcfg, dirnames, err := config.LoadConfigFromDir(l.Fs, d.AbsConfigDir, l.Environment)
-		if err == nil {
-			if len(dirnames) > 0 {
-				l.cfg.Set("", dcfg.Get(""))
-				configFiles = append(configFiles, dirnames...)
-			}
-		} else if err != ErrNoConfigFile {
-			if len(dirnames) > 0 {
-				return nil, nil, l.wrapFileError(err, dirnames[0])
-			}
-			return nil, nil, err
-		}
+	configFiles, err := l.loadConfigFiles()
+	if err != nil {
+		return nil, nil, err
 	}
 
 	if err := l.applyConfigDefaults(); err != nil {
@@ -163,6 +144,69 @@ func LoadConfig(d ConfigSourceDescriptor, doWithConfig ...func(cfg config.Provid
 	return l.cfg, configFiles, err
 }
 
+// LoadNetworkPolicy loads the network policy from the configuration
+// described by d, without collecting the modules, for commands that only
+// talk to a server. The modules' configuration is not applied.
+func LoadNetworkPolicy(d ConfigSourceDescriptor) (*netpolicy.Policy, error) {
+	if d.Environment == "" {
+		d.Environment = hugo.EnvironmentProduction
+	}
+	if len(d.Environ) == 0 && !hugo.IsRunningAsTest() {
+		d.Environ = os.Environ()
+	}
+
+	l := configLoader{ConfigSourceDescriptor: d, cfg: config.New()}
+	defer l.deleteMergeStrategies()
+
+	if _, err := l.loadConfigFiles(); err != nil {
+		return nil, err
+	}
+
+	if err := l.applyOsEnvOverrides(d.Environ); err != nil {
+		return nil, err
+	}
+
+	conf, err := netpolicy.DecodeConfig(l.cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return netpolicy.New(conf)
+}
+
+// loadConfigFiles loads the config files and the config dir into l.cfg and
+// returns the names of the files loaded.
+func (l configLoader) loadConfigFiles() ([]string, error) {
+	var configFiles []string
+
+	for _, name := range l.configFilenames() {
+		var filename string
+		filename, err := l.loadConfig(name)
+		if err == nil {
+			configFiles = append(configFiles, filename)
+		} else if err != ErrNoConfigFile {
+			return nil, err
+		}
+	}
+
+	if l.AbsConfigDir != "" {
+		dcfg, dirnames, err := config.LoadConfigFromDir(l.Fs, l.AbsConfigDir, l.Environment)
+		if err == nil {
+			if len(dirnames) > 0 {
+				l.cfg.Set("", dcfg.Get(""))
+				configFiles = append(configFiles, dirnames...)
+			}
+		} else if err != ErrNoConfigFile {
+			if len(dirnames) > 0 {
+				return nil, l.wrapFileError(err, dirnames[0])
+			}
+			return nil, err
+		}
+	}
+
+	return configFiles, nil
+}
+
 // LoadConfigDefault is a convenience method to load the default "config.toml" config.
 func LoadConfigDefault(fs afero.Fs) (config.Provider, error) {
 	v, _, err := LoadConfig(ConfigSourceDescriptor{Fs: fs, Filename: "config.toml"})
@@ -266,6 +310,7 @@ func (l configLoader) applyConfigDefaults() error {
 		"newContentEditor":                     "",
 		"paginate":                             10,
 		"paginatePath":                         "page",
+		"paginateKeepGroups":                   false,
 		"summaryLength":                        70,
 		"rssLimit":                             -1,
 		"sectionPagesMenu":                     "",
@@ -370,6 +415,36 @@ func (l configLoader) collectModules(modConfig modules.Config, v1 config.Provide
 
 	v1.Set("filecacheConfigs", filecacheConfigs)
 
+	netConfig, err := netpolicy.DecodeConfig(v1)
+	if err != nil {
+		return nil, nil, err
+	}
+	networkPolicy, err := netpolicy.New(netConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	netpolicy.Set(v1, networkPolicy)
+
+	limitsConfig, err := hexec.DecodeLimitsConfig(v1)
+	if err != nil {
+		return nil, nil, err
+	}
+	limiter, err := hexec.NewLimiter(limitsConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	hexec.SetLimiter(v1, limiter)
+
+	securityConfig, err := security.DecodeConfig(v1)
+	if err != nil {
+		return nil, nil, err
+	}
+	securityPolicy, err := security.New(securityConfig, workingDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	security.Set(v1, securityPolicy)
+
 	var configFilenames []string
 
 	hook := func(m *modules.ModulesConfig) error {
@@ -403,6 +478,8 @@ func (l configLoader) collectModules(modConfig modules.Config, v1 config.Provide
 		CacheDir:           filecacheConfigs.CacheDirModules(),
 		ModuleConfig:       modConfig,
 		IgnoreVendor:       ignoreVendor,
+		NetworkPolicy:      networkPolicy,
+		ExecConfig:         v1,
 	})
 
 	v1.Set("modulesClient", modulesClient)
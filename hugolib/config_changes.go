@@ -0,0 +1,218 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gohugoio/hugo/common/maps"
+	"github.com/gohugoio/hugo/config"
+	"github.com/gohugoio/hugo/resources"
+)
+
+// cacheAll is used in configKeyCaches for config keys all caches depend on.
+const cacheAll = "all"
+
+// configKeyCaches maps config keys to the in-memory caches that depend on
+// them, i.e. that need to be invalidated when they change in server mode.
+// Nested keys are matched by their longest listed prefix, with any language
+// in the languages config replaced by "*", and keys not listed invalidate
+// all caches.
+var configKeyCaches = map[string][]string{
+	"archetypedir":               nil,
+	"author":                     nil,
+	"build":                      {resources.CacheResources},
+	"canonifyurls":               nil,
+	"copyright":                  nil,
+	"disablealiases":             nil,
+	"disablekinds":               nil,
+	"disablepathtolower":         nil,
+	"enableemoji":                nil,
+	"enablegitinfo":              nil,
+	"enableinlineshortcodes":     nil,
+	"enablerobotstxt":            nil,
+	"footnotereturnlinkcontents": nil,
+	"frontmatter":                nil,
+	"frontmatterdefaults":        nil,
+	"imaging":                    {resources.CacheImages, resources.CacheResources},
+	"languages.*.menus":          nil,
+	"languages.*.params":         nil,
+	"languages.*.title":          nil,
+	"markup":                     nil,
+	"menu":                       nil,
+	"menus":                      nil,
+	"minify":                     {resources.CacheResources},
+	"nextprev":                   nil,
+	"outputs":                    nil,
+	"paginate":                   nil,
+	"paginatepath":               nil,
+	"paramtypes":                 nil,
+	"blocks":                     nil,
+	"params":                     nil,
+	"permalinks":                 nil,
+	"pluralizelisttitles":        nil,
+	"privacy":                    nil,
+	"related":                    nil,
+	"relativeurls":               nil,
+	"sectionpagesmenu":           nil,
+	"sequences":                  nil,
+	"server":                     nil,
+	"services":                   nil,
+	"sitemap":                    nil,
+	"social":                     nil,
+	"summarylength":              nil,
+	"taxonomies":                 nil,
+	"timeout":                    nil,
+	"title":                      nil,
+	"weights":                    nil,
+}
+
+// configChangeCaches returns the caches that depend on any of the changed
+// config keys.
+func configChangeCaches(changed []string) map[string]bool {
+	invalidated := make(map[string]bool)
+	for _, key := range changed {
+		caches, found := lookupConfigKeyCaches(key)
+		if !found {
+			return map[string]bool{
+				cacheAll:                 true,
+				resources.CacheImages:    true,
+				resources.CacheResources: true,
+			}
+		}
+		for _, c := range caches {
+			invalidated[c] = true
+		}
+	}
+	return invalidated
+}
+
+func lookupConfigKeyCaches(key string) ([]string, bool) {
+	parts := strings.Split(key, ".")
+	if len(parts) > 2 && parts[0] == "languages" {
+		parts[1] = "*"
+	}
+	for i := len(parts); i > 0; i-- {
+		if caches, found := configKeyCaches[strings.Join(parts[:i], ".")]; found {
+			return caches, true
+		}
+	}
+	return nil, false
+}
+
+// changedConfigKeys returns the sorted keys, nested keys joined with a ".",
+// of the values that differ in the two configs. Only the plain values, as
+// read from the config files, are compared, not the runtime state stored
+// in the config, e.g. the modules.
+func changedConfigKeys(prev, cfg config.Provider) []string {
+	var changed []string
+	diffConfigValues("", prev.Get(""), cfg.Get(""), &changed)
+	sort.Strings(changed)
+	return changed
+}
+
+func diffConfigValues(key string, v1, v2 interface{}, changed *[]string) {
+	m1, ok1 := toConfigMap(v1)
+	m2, ok2 := toConfigMap(v2)
+	// Compare a new or removed map key by key.
+	if ok1 && v2 == nil {
+		m2, ok2 = map[string]interface{}{}, true
+	}
+	if ok2 && v1 == nil {
+		m1, ok1 = map[string]interface{}{}, true
+	}
+	if ok1 && ok2 {
+		for k, v := range m1 {
+			diffConfigValues(joinConfigKey(key, k), v, m2[k], changed)
+		}
+		for k, v := range m2 {
+			if _, found := m1[k]; !found {
+				diffConfigValues(joinConfigKey(key, k), nil, v, changed)
+			}
+		}
+		return
+	}
+
+	if (v1 != nil && !isPlainConfigValue(v1)) || (v2 != nil && !isPlainConfigValue(v2)) {
+		return
+	}
+
+	if !reflect.DeepEqual(v1, v2) {
+		*changed = append(*changed, key)
+	}
+}
+
+func joinConfigKey(key, k string) string {
+	k = strings.ToLower(k)
+	if key == "" {
+		return k
+	}
+	return key + "." + k
+}
+
+func toConfigMap(v interface{}) (map[string]interface{}, bool) {
+	switch vv := v.(type) {
+	case map[string]interface{}:
+		return vv, true
+	case maps.Params:
+		return vv, true
+	}
+	return nil, false
+}
+
+// isPlainConfigValue reports whether v is a value as read from a config file.
+func isPlainConfigValue(v interface{}) bool {
+	switch vv := v.(type) {
+	case string, bool, int, int64, float64, time.Time, []string:
+		return true
+	case []interface{}:
+		for _, e := range vv {
+			if _, ok := toConfigMap(e); ok {
+				continue
+			}
+			if !isPlainConfigValue(e) {
+				return false
+			}
+		}
+		return true
+	}
+	_, ok := toConfigMap(v)
+	return ok
+}
+
+// ReuseCaches carries the in-memory caches of prev, created from a previous
+// config, over to h, except those that depend on the config keys changed
+// since, so e.g. editing the site params in server mode doesn't process the
+// images again. It returns the changed config keys.
+func (h *HugoSites) ReuseCaches(prev *HugoSites) []string {
+	changed := changedConfigKeys(prev.Cfg, h.Cfg)
+	invalidated := configChangeCaches(changed)
+	if invalidated[cacheAll] {
+		return changed
+	}
+
+	for _, s := range h.Sites {
+		for _, ps := range prev.Sites {
+			if ps.Language().Lang == s.Language().Lang {
+				s.ResourceSpec.ReuseCaches(ps.ResourceSpec, invalidated)
+				break
+			}
+		}
+	}
+
+	return changed
+}
@@ -0,0 +1,90 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gohugoio/hugo/resources"
+)
+
+func TestConfigChangeCaches(t *testing.T) {
+	c := qt.New(t)
+
+	c.Assert(configChangeCaches(nil), qt.HasLen, 0)
+	c.Assert(configChangeCaches([]string{"params.footertext", "languages.en.params.footertext", "title"}), qt.HasLen, 0)
+	c.Assert(configChangeCaches([]string{"params.footertext", "minify.tdewolff.html.keepcomments"}), qt.DeepEquals, map[string]bool{resources.CacheResources: true})
+	c.Assert(configChangeCaches([]string{"imaging.quality"}), qt.DeepEquals, map[string]bool{resources.CacheImages: true, resources.CacheResources: true})
+	c.Assert(configChangeCaches([]string{"params.footertext", "baseurl"})[cacheAll], qt.IsTrue)
+	c.Assert(configChangeCaches([]string{"languages.en.weight"})[cacheAll], qt.IsTrue)
+}
+
+func TestReuseCaches(t *testing.T) {
+	c := qt.New(t)
+
+	const template = `{{ $stamp := resources.FromString "stamp.txt" (now.UnixNano | string) }}Stamp: {{ $stamp.Content }}|Footer: {{ site.Params.footerText }}`
+
+	build := func(b *sitesBuilder, config string) string {
+		b.WithConfigFile("toml", config)
+		b.WithContent("p1.md", "---\ntitle: P1\n---")
+		b.WithTemplates("index.html", template)
+		b.Build(BuildCfg{})
+		return b.FileContent("public/index.html")
+	}
+
+	b1 := newTestSitesBuilder(t)
+	home1 := build(b1, `
+baseURL = "https://example.org"
+[params]
+footerText = "Foo"
+`)
+	c.Assert(home1, qt.Contains, "Footer: Foo")
+
+	rebuild := func(config string) (string, []string) {
+		b := newTestSitesBuilder(t)
+		b.Fs = b1.Fs
+		b.WithConfigFile("toml", config)
+		b.WithContent("p1.md", "---\ntitle: P1\n---")
+		b.WithTemplates("index.html", template)
+		b.CreateSites()
+		changed := b.H.ReuseCaches(b1.H)
+		b.Build(BuildCfg{})
+		return b.FileContent("public/index.html"), changed
+	}
+
+	stamp := func(s string) string {
+		return s[:strings.Index(s, "|")]
+	}
+
+	home2, changed := rebuild(`
+baseURL = "https://example.org"
+[params]
+footerText = "Bar"
+`)
+	c.Assert(changed, qt.DeepEquals, []string{"params.footertext"})
+	c.Assert(home2, qt.Contains, "Footer: Bar")
+	c.Assert(stamp(home2), qt.Equals, stamp(home1))
+
+	home3, changed := rebuild(`
+baseURL = "https://example.org"
+[params]
+footerText = "Foo"
+[minify]
+minifyOutput = true
+`)
+	c.Assert(changed, qt.DeepEquals, []string{"minify.minifyoutput"})
+	c.Assert(stamp(home3), qt.Not(qt.Equals), stamp(home1))
+}
@@ -749,3 +749,28 @@ theme_param="themevalue2"
 	})
 
 }
+
+func TestNetworkPolicy(t *testing.T) {
+	b := newTestSitesBuilder(t).WithWorkingDir("/site")
+	b.WithConfigFile("toml", `
+baseURL = "https://example.org"
+workingDir = "/site"
+[networkPolicy]
+denyAll = true
+report = "network-violations.json"
+`)
+	b.WithContent("p1.md", "---\ntitle: P1\n---")
+	b.WithTemplates("index.html", `{{ $data := getJSON "https://api.example.com/data.json" }}Data: {{ $data }}`)
+
+	b.BuildFail(BuildCfg{})
+
+	report, err := afero.ReadFile(b.Fs.Source, filepath.FromSlash("/site/network-violations.json"))
+	b.Assert(err, qt.IsNil)
+	b.Assert(string(report), qt.Equals, `[
+  {
+    "kind": "http",
+    "target": "api.example.com",
+    "reason": "all network access is denied"
+  }
+]`)
+}
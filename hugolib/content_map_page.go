@@ -738,6 +738,10 @@ func (m *pageMaps) AssemblePages() error {
 			return err
 		}
 
+		if err := pm.assignWeights(); err != nil {
+			return err
+		}
+
 		sw := &sectionWalker{m: pm.contentMap}
 		a := sw.applyAggregates()
 		_, mainSectionsSet := pm.s.s.Info.Params()["mainsections"]
@@ -0,0 +1,53 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"testing"
+)
+
+func TestDocumentMeta(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t)
+
+	b.WithContent("library/index.md", "---\ntitle: Library\n---\n")
+	b.WithSourceFile("content/library/report.pdf", `%PDF-1.4
+1 0 obj
+<< /Type /Catalog /Pages 2 0 R >>
+endobj
+2 0 obj
+<< /Type /Pages /Kids [] /Count 42 >>
+endobj
+3 0 obj
+<< /Title (Annual Report) /Author (Jane Doe) /CreationDate (D:20210102030405Z) >>
+endobj
+trailer
+<< /Size 4 /Root 1 0 R /Info 3 0 R >>
+%%EOF
+`)
+	b.WithSourceFile("content/library/notes.txt", "Notes")
+	b.WithTemplatesAdded("_default/single.html", `
+{{ range .Resources }}
+{{ .Name }}: {{ with .DocumentMeta }}{{ .Title }}|{{ .Author }}|{{ .PageCount }}|{{ .Created.Format "2006-01-02" }}{{ else }}none{{ end }}|
+{{ end }}
+`)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/library/index.html",
+		"report.pdf: Annual Report|Jane Doe|42|2021-01-02|",
+		"notes.txt: none|",
+	)
+}
@@ -208,6 +208,68 @@ title: Shorty
 	}
 }
 
+func TestShortcodeConsentPlaceholder(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t)
+	b.WithConfigFile("toml", `
+baseURL = "https://example.org"
+[privacy]
+consentManifest = "consent.json"
+[privacy.youtube]
+requireConsent = true
+[privacy.vimeo]
+requireConsent = true
+disable = true
+[privacy.services.mymap]
+requireConsent = true
+category = "functional"
+domains = ["maps.example.com"]
+`)
+	b.WithContent("p1.md", `---
+title: P1
+---
+{{< youtube w7Ft2ymGmfc >}}
+{{< vimeo 146022717 >}}
+`)
+	b.WithTemplatesAdded("_default/single.html", `{{ .Content }}|{{ (site.Config.Privacy.Get "MyMap").Category }}`)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/p1/index.html",
+		`<div data-hugo-consent="youtube" data-hugo-consent-category="marketing">
+<template>
+<div style="position: relative;`,
+		`</iframe>
+</div>
+</template>
+<p class="hugo-consent-message">This content is loaded from YouTube after you have given your consent.</p>
+</div>`,
+		"|functional",
+	)
+	b.AssertFileContent("public/consent.json", `{
+  "services": [
+    {
+      "name": "mymap",
+      "category": "functional",
+      "domains": [
+        "maps.example.com"
+      ]
+    },
+    {
+      "name": "youtube",
+      "category": "marketing",
+      "domains": [
+        "www.youtube.com",
+        "www.youtube-nocookie.com"
+      ]
+    }
+  ]
+}`)
+
+	b.Assert(b.FileContent("public/p1/index.html"), qt.Not(qt.Contains), "vimeo")
+}
+
 func TestShortcodeVimeo(t *testing.T) {
 	t.Parallel()
 
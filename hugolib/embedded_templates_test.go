@@ -129,6 +129,81 @@ Disqus:
 	b.AssertFileContent("public/index.html", "\"disqus_shortname\" + '.disqus.com/embed.js';")
 }
 
+func TestEmbeddedAnalyticsTemplates(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t)
+	b.WithConfigFile("toml", `
+baseURL = "https://example.org"
+[services]
+[services.googleAnalytics]
+id = "G-ga_id"
+[services.matomo]
+url = "https://matomo.example.org"
+siteID = "42"
+[services.plausible]
+domain = "example.org"
+[privacy]
+[privacy.googleAnalytics]
+requireConsent = true
+[privacy.matomo]
+requireConsent = true
+disableCookies = true
+`)
+	b.WithTemplatesAdded(
+		"index.html", `Analytics: {{ template "_internal/analytics.html" . }}`,
+		"404.html", `Plausible: {{ template "_internal/plausible.html" . }}`,
+	)
+	b.WithTemplatesAdded("_default/single.html", `{{ template "_internal/matomo.html" . }}`)
+	b.WithContent("p1.md", "")
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/index.html",
+		"gtag('consent', 'default', { 'analytics_storage': 'denied' });",
+		"window.hugoAnalytics = window.hugoAnalytics ||",
+		"hugoAnalytics.whenConsented(function() { gtag('consent', 'update', { 'analytics_storage': 'granted' }); });",
+		"_paq.push(['requireConsent']);",
+		"hugoAnalytics.whenConsented(function() { _paq.push(['setConsentGiven']); });",
+		"_paq.push(['disableCookies']);",
+		`var u = "https://matomo.example.org/";`,
+		`_paq.push(['setSiteId', "42"]);`,
+		`<script defer data-domain="example.org" src="https://plausible.io/js/plausible.js"></script>`,
+	)
+	b.AssertFileContent("public/404.html", `Plausible: 
+<script defer data-domain="example.org" src="https://plausible.io/js/plausible.js"></script>`)
+
+	b = newTestSitesBuilder(t)
+	b.WithConfigFile("toml", `
+baseURL = "https://example.org"
+[services]
+[services.matomo]
+url = "https://matomo.example.org/"
+siteID = "42"
+[services.plausible]
+domain = "example.org"
+script = "https://stats.example.org/js/script.js"
+[privacy]
+[privacy.matomo]
+disable = true
+[privacy.plausible]
+requireConsent = true
+respectDoNotTrack = true
+`)
+	b.WithTemplatesAdded("index.html", `Analytics: {{ template "_internal/analytics.html" . }}|`)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/index.html",
+		`if (dnt == "1" || dnt == "yes") { return; }`,
+		`g.setAttribute('data-domain', "example.org"); g.src = "https://stats.example.org/js/script.js";`,
+		"hugoAnalytics.whenConsented(load);",
+	)
+	c := qt.New(t)
+	c.Assert(b.FileContent("public/index.html"), qt.Not(qt.Contains), "_paq")
+	c.Assert(b.FileContent("public/index.html"), qt.Not(qt.Contains), "gtag")
+}
+
 func TestEmbeddedPaginationTemplate(t *testing.T) {
 	t.Parallel()
 
@@ -167,3 +242,30 @@ func TestEmbeddedPaginationTemplate(t *testing.T) {
 	variant = `{{ template "_internal/pagination.html" (dict "page" . "format" "terse") }}`
 	test(variant, expectedOutputTerseFormat)
 }
+
+func TestEmbeddedPageAssetsTemplate(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t)
+	b.WithTemplatesAdded("_default/single.html", `Assets: {{ template "_internal/page_assets.html" . }}|`)
+	b.WithContent(
+		"p1/index.md", "",
+		"p1/index.css", "body { color: red; }",
+		"p1/index.js", "console.log('p1');",
+		"p2/index.md", "",
+		"p2/index.css", "body { color: blue; }",
+		"p3.md", "",
+	)
+	b.WithSourceFile("assets/page-assets/shared.css", "html { margin: 0; }")
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/p1/index.html", `Assets: 
+<link rel="stylesheet" href="/page-assets/shared.`, `
+<link rel="stylesheet" href="/p1/index.`, `.css" integrity="sha256-`, `" crossorigin="anonymous">
+<script src="/p1/index.`, `.js" integrity="sha256-`, `" crossorigin="anonymous" defer></script>|`)
+	b.AssertFileContent("public/p2/index.html", `<link rel="stylesheet" href="/p2/index.`, `crossorigin="anonymous">|`)
+	b.AssertFileContent("public/p3/index.html", "Assets: |")
+	c := qt.New(t)
+	c.Assert(b.FileContent("public/p2/index.html"), qt.Not(qt.Contains), "<script")
+}
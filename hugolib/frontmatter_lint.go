@@ -0,0 +1,227 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"bytes"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/gohugoio/hugo/helpers"
+	"github.com/gohugoio/hugo/parser"
+	"github.com/gohugoio/hugo/parser/pageparser"
+	"github.com/gohugoio/hugo/resources/page"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// The top level front matter keys with a meaning to Hugo. Any other top
+// level key is a custom param, which should be set in params.
+var frontMatterKeys = map[string]bool{
+	"aliases":        true,
+	"blocks":         true,
+	"build":          true,
+	"cascade":        true,
+	"description":    true,
+	"draft":          true,
+	"headless":       true,
+	"id":             true,
+	"iscjklanguage":  true,
+	"keywords":       true,
+	"layout":         true,
+	"linktitle":      true,
+	"markup":         true,
+	"menu":           true,
+	"menus":          true,
+	"outputs":        true,
+	"params":         true,
+	"published":      true,
+	"resources":      true,
+	"sanitize":       true,
+	"sitemap":        true,
+	"slug":           true,
+	"summary":        true,
+	"title":          true,
+	"translationkey": true,
+	"type":           true,
+	"url":            true,
+	"weight":         true,
+}
+
+// FrontMatterIssue is a deprecated top level key in the front matter of a
+// content file.
+type FrontMatterIssue struct {
+	// The content filename.
+	Filename string
+
+	// The key as written in the front matter.
+	Key string
+
+	// The edit replacing the key with its current form.
+	Fix parser.FrontMatterEdit
+}
+
+// Replacement returns what the key should be replaced with, e.g. "build" or
+// "params.author".
+func (i FrontMatterIssue) Replacement() string {
+	if i.Fix.Op == parser.FrontMatterMove {
+		return i.Fix.NewKey + "." + i.Key
+	}
+	return i.Fix.NewKey
+}
+
+// LintFrontMatter returns the deprecated top level keys in the front matter
+// of the content files: _build, which is now build, and the custom params,
+// which are now set in params. The issues are sorted by filename.
+func (h *HugoSites) LintFrontMatter() ([]FrontMatterIssue, error) {
+	var (
+		issues []FrontMatterIssue
+		seen   = make(map[string]bool)
+	)
+
+	for _, s := range h.Sites {
+		var err error
+		// Walk the content tree and not .Site.AllPages to also include the
+		// bundled pages and the pages not listed.
+		s.pageMap.withEveryBundlePage(func(p *pageState) bool {
+			if p.File().IsZero() {
+				return false
+			}
+
+			filename := p.File().Filename()
+			if seen[filename] {
+				return false
+			}
+			seen[filename] = true
+
+			var pageIssues []FrontMatterIssue
+			pageIssues, err = s.lintFrontMatter(p)
+			if err != nil {
+				err = errors.Wrapf(err, "failed to lint %q", filename)
+				return true
+			}
+			issues = append(issues, pageIssues...)
+
+			return false
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Filename < issues[j].Filename
+	})
+
+	return issues, nil
+}
+
+func (s *Site) lintFrontMatter(p page.Page) ([]FrontMatterIssue, error) {
+	source, err := readPageSource(p)
+	if err != nil {
+		return nil, err
+	}
+
+	pf, err := pageparser.ParseFrontMatterAndContent(bytes.NewReader(source))
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(pf.FrontMatter))
+	for k := range pf.FrontMatter {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var issues []FrontMatterIssue
+	for _, k := range keys {
+		loki := strings.ToLower(k)
+
+		var fix parser.FrontMatterEdit
+		switch {
+		case loki == "_build":
+			fix = parser.FrontMatterEdit{Op: parser.FrontMatterRename, Key: k, NewKey: "build"}
+		case frontMatterKeys[loki], s.frontmatterHandler.IsDateKey(loki), s.isTaxonomyKey(loki):
+			continue
+		default:
+			fix = parser.FrontMatterEdit{Op: parser.FrontMatterMove, Key: k, NewKey: "params"}
+		}
+
+		issues = append(issues, FrontMatterIssue{Filename: p.File().Filename(), Key: k, Fix: fix})
+	}
+
+	return issues, nil
+}
+
+// isTaxonomyKey reports whether key is the plural name of a taxonomy, e.g.
+// "tags", or its weight, e.g. "tags_weight".
+func (s *Site) isTaxonomyKey(key string) bool {
+	key = strings.TrimSuffix(key, "_weight")
+	for _, plural := range s.siteCfg.taxonomiesConfig {
+		if strings.EqualFold(plural, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// FixFrontMatter applies the fixes of the issues and returns the names of
+// the files changed. The front matter is edited line by line, preserving the
+// formatting where possible, see parser.EditFrontMatter.
+func (h *HugoSites) FixFrontMatter(issues []FrontMatterIssue) ([]string, error) {
+	var (
+		filenames []string
+		edits     = make(map[string][]parser.FrontMatterEdit)
+	)
+
+	for _, issue := range issues {
+		if _, found := edits[issue.Filename]; !found {
+			filenames = append(filenames, issue.Filename)
+		}
+		edits[issue.Filename] = append(edits[issue.Filename], issue.Fix)
+	}
+
+	var fixed []string
+	for _, filename := range filenames {
+		source, err := afero.ReadFile(h.Fs.Source, filename)
+		if err != nil {
+			return fixed, err
+		}
+
+		b, changed, err := parser.EditFrontMatter(source, edits[filename]...)
+		if err != nil {
+			return fixed, errors.Wrapf(err, "failed to fix %q", filename)
+		}
+		if !changed {
+			continue
+		}
+
+		if err := helpers.WriteToDisk(filename, bytes.NewReader(b), h.Fs.Source); err != nil {
+			return fixed, errors.Wrapf(err, "failed to save %q", filename)
+		}
+		fixed = append(fixed, filename)
+	}
+
+	return fixed, nil
+}
+
+func readPageSource(p page.Page) ([]byte, error) {
+	f, err := p.File().FileInfo().Meta().Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ioutil.ReadAll(f)
+}
@@ -0,0 +1,126 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestLintFrontMatter(t *testing.T) {
+	b := newTestSitesBuilder(t)
+	b.WithConfigFile("toml", `
+baseURL = "https://example.org"
+[taxonomies]
+tag = "tags"
+`)
+	b.WithContent("p1.md", `---
+title: "P1"
+author: Jane
+tags: ["a"]
+_build:
+  list: never
+---
+`, "p2.md", `+++
+title = "P2"
+date = 2021-01-01
+author = "John"
+
+[_build]
+render = "never"
+
+[social]
+twitter = "@john"
++++
+`, "b1/index.md", `---
+title: "B1"
+---
+`, "b1/sub.md", `---
+title: "Sub"
+color: red
+---
+`, "p3.md", `---
+title: "P3"
+build:
+  list: never
+params:
+  author: Jim
+---
+`)
+	b.WithTemplatesAdded("_default/single.html", `{{ .Title }}|{{ .Params.author }}|{{ .Params.social.twitter }}|`, "index.html", `{{ range .RegularPages }}{{ .Title }}|{{ end }}`)
+	b.Build(BuildCfg{})
+
+	// The params and build options set in the new form.
+	b.AssertFileContent("public/p3/index.html", "P3|Jim||")
+	b.AssertFileContent("public/index.html", "P2|B1|")
+
+	issues, err := b.H.LintFrontMatter()
+	b.Assert(err, qt.IsNil)
+
+	contentDir := filepath.Join(b.Cfg.GetString("workingDir"), "content")
+	var got []string
+	for _, issue := range issues {
+		filename := filepath.ToSlash(strings.TrimPrefix(issue.Filename, contentDir))
+		got = append(got, filename+":"+issue.Key+"=>"+issue.Replacement())
+	}
+	b.Assert(got, qt.DeepEquals, []string{
+		"/b1/sub.md:color=>params.color",
+		"/p1.md:_build=>build",
+		"/p1.md:author=>params.author",
+		"/p2.md:_build=>build",
+		"/p2.md:author=>params.author",
+		"/p2.md:social=>params.social",
+	})
+
+	fixed, err := b.H.FixFrontMatter(issues)
+	b.Assert(err, qt.IsNil)
+	b.Assert(fixed, qt.HasLen, 3)
+
+	b.AssertFileContent("content/p1.md", `---
+title: "P1"
+tags: ["a"]
+build:
+  list: never
+params:
+  author: Jane
+---
+`)
+	b.AssertFileContent("content/p2.md", `+++
+title = "P2"
+date = 2021-01-01
+
+[build]
+render = "never"
+
+[params.social]
+twitter = "@john"
+
+[params]
+author = "John"
++++
+`)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/p1/index.html", "P1|Jane||")
+	b.AssertFileContent("public/index.html", "P2|B1|")
+	b.Assert(b.CheckExists("public/p2/index.html"), qt.Equals, false)
+
+	issues, err = b.H.LintFrontMatter()
+	b.Assert(err, qt.IsNil)
+	b.Assert(issues, qt.HasLen, 0)
+}
@@ -1131,3 +1131,52 @@ P1: {{ $p1.Title }}|{{ $p1.RelPermalink }}|Filename: {{ $p1.File.Filename }}
 
 	b.AssertFileContent("public/index.html", "P1: Abs|/p1/", "Filename: "+contentFilename)
 }
+
+func TestModuleBucketMount(t *testing.T) {
+	t.Parallel()
+
+	c := qt.New(t)
+	workDir, clean1, err := htesting.CreateTempDir(hugofs.Os, "hugo-project")
+	c.Assert(err, qt.IsNil)
+	defer clean1()
+	bucketDir, clean2, err := htesting.CreateTempDir(hugofs.Os, "hugo-bucket")
+	c.Assert(err, qt.IsNil)
+	defer clean2()
+
+	cfg := config.New()
+	cfg.Set("workingDir", workDir)
+	fs := hugofs.NewFrom(hugofs.Os, cfg)
+
+	config := fmt.Sprintf(`
+workingDir=%q
+cacheDir=%q
+
+[module]
+  [[module.mounts]]
+    source = %q
+    target = "content/blog"
+
+`, workDir, filepath.Join(workDir, "cache"), "file://"+filepath.ToSlash(bucketDir)+"?prefix=posts/")
+
+	c.Assert(hugofs.Os.MkdirAll(filepath.Join(bucketDir, "posts"), 0777), qt.IsNil)
+	c.Assert(afero.WriteFile(hugofs.Os, filepath.Join(bucketDir, "posts", "p1.md"), []byte(`
+---
+title: From Bucket
+---
+`), 0777), qt.IsNil)
+
+	b := newTestSitesBuilder(t)
+	b.Fs = fs
+
+	b.WithWorkingDir(workDir).WithConfigFile("toml", config)
+	b.WithContent("dummy.md", "")
+
+	b.WithTemplatesAdded("index.html", `
+{{ $p1 := site.GetPage "blog/p1" }}
+P1: {{ $p1.Title }}|{{ $p1.RelPermalink }}
+`)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/index.html", "P1: From Bucket|/blog/p1/")
+}
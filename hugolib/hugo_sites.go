@@ -74,6 +74,20 @@ type HugoSites struct {
 	// Render output formats for all sites.
 	renderFormats output.Formats
 
+	// Content hashes of the rendered page outputs, written to
+	// hugo_manifest.json when enabled.
+	publishManifest publishManifest
+
+	// The stable page IDs and the paths they were published to.
+	pageIDs *pageIDs
+
+	permalinkHistory *permalinkHistory
+
+	attestations *attestations
+
+	// Set if the rendered page content is stored on disk.
+	pageContentStore *pageContentStore
+
 	*deps.Deps
 
 	gitInfo *gitInfo
@@ -271,6 +285,15 @@ func (h *HugoSites) PrintProcessingStats(w io.Writer) {
 	helpers.ProcessingStatsTable(w, stats...)
 }
 
+// InvalidateCaches removes the values cached with any of the given keys, e.g.
+// partials cached with partialCached and CacheOptions. The sites needs to be
+// rebuilt for this to take effect.
+func (h *HugoSites) InvalidateCaches(keys ...string) {
+	for _, s := range h.Sites {
+		s.Deps.CacheInvalidationListeners.Notify(keys...)
+	}
+}
+
 // GetContentPage finds a Page with content given the absolute filename.
 // Returns nil if none found.
 func (h *HugoSites) GetContentPage(filename string) page.Page {
@@ -381,6 +404,31 @@ func newHugoSites(cfg deps.DepsCfg, sites ...*Site) (*HugoSites, error) {
 
 	h.Deps = sites[0].Deps
 
+	h.pageIDs, err = newPageIDs(h.Cfg, h.Fs.Source)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load page IDs")
+	}
+
+	h.permalinkHistory, err = newPermalinkHistory(h.Cfg, h.Fs.Source)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load permalink history")
+	}
+
+	h.attestations, err = newAttestations(h.Cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create attestations")
+	}
+
+	if h.permalinkHistory.cfg.Enable {
+		for _, s := range sites {
+			s.publisher = permalinkHistoryPublisher{Publisher: s.publisher, history: h.permalinkHistory}
+		}
+	}
+
+	if buildCfg := h.ResourceSpec.BuildConfig; buildCfg.PageStore == "disk" {
+		h.pageContentStore = newPageContentStore(h.FileCaches.PageStoreCache(), buildCfg.PageStoreMemoryLimit)
+	}
+
 	// Only needed in server mode.
 	// TODO(bep) clean up the running vs watching terms
 	if cfg.Running {
@@ -430,6 +478,9 @@ func (l configLoader) applyDeps(cfg deps.DepsCfg, sites ...*Site) error {
 		onCreated := func(d *deps.Deps) error {
 			s.Deps = d
 
+			s.blocksCache = newBlocksCache()
+			d.BuildStartListeners.Add(s.blocksCache.clear)
+
 			// Set up the main publishing chain.
 			pub, err := publisher.NewDestinationPublisher(
 				d.ResourceSpec,
@@ -688,6 +739,12 @@ func (cfg *BuildCfg) shouldRender(p *pageState) bool {
 	}
 
 	if len(cfg.RecentlyVisited) == 0 {
+		if w := cfg.whatChanged; w != nil && !w.allListsAffected && p.IsNode() {
+			// Only content changed, skip the lists not affected. We only know
+			// the pages a list shows if its template only reads the pages
+			// below it.
+			return w.listsAffected[p.RelPermalink()] || (!p.File().IsZero() && w.files[p.File().Filename()]) || p.readsOtherPages()
+		}
 		return true
 	}
 
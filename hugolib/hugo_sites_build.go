@@ -19,16 +19,20 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"runtime/trace"
 	"strings"
+	"sync"
 
 	"github.com/gohugoio/hugo/publisher"
+	"github.com/gohugoio/hugo/transform/outboundlinks"
 
 	"github.com/gohugoio/hugo/hugofs"
 
 	"github.com/gohugoio/hugo/common/para"
 	"github.com/gohugoio/hugo/config"
+	"github.com/gohugoio/hugo/config/netpolicy"
 	"github.com/gohugoio/hugo/resources/postpub"
 
 	"github.com/spf13/afero"
@@ -86,7 +90,7 @@ func (h *HugoSites) Build(config BuildCfg, events ...fsnotify.Event) error {
 
 	if conf.whatChanged == nil {
 		// Assume everything has changed
-		conf.whatChanged = &whatChanged{source: true}
+		conf.whatChanged = &whatChanged{source: true, allListsAffected: true}
 	}
 
 	var prepareErr error
@@ -94,9 +98,7 @@ func (h *HugoSites) Build(config BuildCfg, events ...fsnotify.Event) error {
 	if !config.PartialReRender {
 		prepare := func() error {
 			init := func(conf *BuildCfg) error {
-				for _, s := range h.Sites {
-					s.Deps.BuildStartListeners.Notify()
-				}
+				h.notifyBuildStart()
 
 				if len(events) > 0 {
 					// Rebuild
@@ -152,10 +154,45 @@ func (h *HugoSites) Build(config BuildCfg, events ...fsnotify.Event) error {
 		if err != nil {
 			h.SendError(err)
 		}
+		renderErr := err
 
 		if err = h.postProcess(); err != nil {
 			h.SendError(err)
 		}
+
+		// This needs to be written after the post processing, which
+		// publishes the resources passed to resources.PostProcess.
+		if err = h.writeAssetsManifest(); err != nil {
+			h.SendError(err)
+		}
+
+		if err = h.writeAssetReport(); err != nil {
+			h.SendError(err)
+		}
+
+		if err = h.writeAttestations(); err != nil {
+			h.SendError(err)
+		}
+
+		if !conf.SkipRender {
+			if err = h.handleMoves(); err != nil {
+				h.SendError(err)
+			}
+			if err = h.checkReviews(); err != nil {
+				h.SendError(err)
+			}
+		}
+
+		if !conf.SkipRender && renderErr == nil {
+			// Only complete renders can be checked.
+			if err = h.checkPermalinkHistory(); err != nil {
+				h.SendError(err)
+			}
+		}
+	}
+
+	if err := h.reportNetworkPolicyViolations(); err != nil {
+		h.SendError(err)
 	}
 
 	if h.Metrics != nil {
@@ -242,9 +279,21 @@ func (h *HugoSites) process(config *BuildCfg, init func(config *BuildCfg) error,
 		return firstSite.processPartial(config, init, events)
 	}
 
+	h.notifyBuildStart()
+
 	return firstSite.process(*config)
 }
 
+// notifyBuildStart notifies the build start listeners, e.g. to clear the
+// per-build template caches, and resets the cache expiry of the previous build.
+func (h *HugoSites) notifyBuildStart() {
+	h.Deps.BuildState.ResetCacheExpiry()
+	h.publishManifest.reset()
+	for _, s := range h.Sites {
+		s.Deps.BuildStartListeners.Notify()
+	}
+}
+
 func (h *HugoSites) assemble(bcfg *BuildCfg) error {
 	if len(h.Sites) > 1 {
 		// The first is initialized during process; initialize the rest
@@ -267,6 +316,8 @@ func (h *HugoSites) assemble(bcfg *BuildCfg) error {
 		return err
 	}
 
+	h.setListsAffected(bcfg.whatChanged)
+
 	return nil
 }
 
@@ -275,8 +326,6 @@ func (h *HugoSites) render(config *BuildCfg) error {
 		return err
 	}
 
-	siteRenderContext := &siteRenderContext{cfg: config, multihost: h.multihost}
-
 	if !config.PartialReRender {
 		h.renderFormats = output.Formats{}
 		h.withSite(func(s *Site) error {
@@ -289,41 +338,70 @@ func (h *HugoSites) render(config *BuildCfg) error {
 		}
 	}
 
-	i := 0
-	for _, s := range h.Sites {
-		for siteOutIdx, renderFormat := range s.renderFormats {
-			siteRenderContext.outIdx = siteOutIdx
-			siteRenderContext.sitesOutIdx = i
-			i++
+	for _, tasks := range h.renderTasks() {
+		select {
+		case <-h.Done():
+			return nil
+		default:
+		}
 
-			select {
-			case <-h.Done():
-				return nil
-			default:
-				for _, s2 := range h.Sites {
-					// We render site by site, but since the content is lazily rendered
-					// and a site can "borrow" content from other sites, every site
-					// needs this set.
-					s2.rc = &siteRenderingContext{Format: renderFormat}
-
-					if err := s2.preparePagesForRender(s == s2, siteRenderContext.sitesOutIdx); err != nil {
-						return err
-					}
+		for _, s2 := range h.Sites {
+			// We render one output format at a time, but since the content is
+			// lazily rendered and a site can "borrow" content from other sites,
+			// every site needs this set before any site starts rendering.
+			task := tasks[0]
+			for _, t := range tasks {
+				if t.s == s2 {
+					task = t
+					break
 				}
+			}
 
-				if !config.SkipRender {
-					if config.PartialReRender {
-						if err := s.renderPages(siteRenderContext); err != nil {
-							return err
-						}
-					} else {
-						if err := s.render(siteRenderContext); err != nil {
-							return err
-						}
-					}
+			s2.rc = &siteRenderingContext{Format: task.format}
+
+			if err := s2.preparePagesForRender(task.s == s2, task.sitesOutIdx); err != nil {
+				return err
+			}
+		}
+
+		if config.SkipRender {
+			continue
+		}
+
+		renderContext := func(t siteRenderTask) *siteRenderContext {
+			return &siteRenderContext{
+				cfg:         config,
+				multihost:   h.multihost,
+				outIdx:      t.outIdx,
+				sitesOutIdx: t.sitesOutIdx,
+			}
+		}
+
+		if !config.PartialReRender {
+			// Sites may share targets, e.g. an alias in one language
+			// pointing to a page in another or the root 404 page, so
+			// everything but the pages are rendered one site at a time.
+			for _, t := range tasks {
+				if err := t.s.renderBeforePages(renderContext(t)); err != nil {
+					return err
 				}
 			}
+		}
+
+		// The pages of the sites with this output format, typically one
+		// per language, are rendered in parallel.
+		if err := h.withRenderTask(tasks, func(t siteRenderTask) error {
+			return t.s.renderPages(renderContext(t))
+		}); err != nil {
+			return err
+		}
 
+		if !config.PartialReRender {
+			for _, t := range tasks {
+				if err := t.s.renderAfterPages(renderContext(t)); err != nil {
+					return err
+				}
+			}
 		}
 	}
 
@@ -339,6 +417,62 @@ func (h *HugoSites) render(config *BuildCfg) error {
 	return nil
 }
 
+// siteRenderTask is the rendering of a site to one of its output formats.
+type siteRenderTask struct {
+	s      *Site
+	format output.Format
+
+	// The index of the output format in the site's and in all the sites'
+	// render formats.
+	outIdx      int
+	sitesOutIdx int
+}
+
+// renderTasks returns the render tasks of all the sites grouped by the output
+// format name, in the order the output formats are first seen.
+func (h *HugoSites) renderTasks() [][]siteRenderTask {
+	var (
+		groups  [][]siteRenderTask
+		indices = make(map[string]int)
+		i       int
+	)
+
+	for _, s := range h.Sites {
+		for outIdx, f := range s.renderFormats {
+			idx, found := indices[f.Name]
+			if !found {
+				idx = len(groups)
+				indices[f.Name] = idx
+				groups = append(groups, nil)
+			}
+			groups[idx] = append(groups[idx], siteRenderTask{s: s, format: f, outIdx: outIdx, sitesOutIdx: i})
+			i++
+		}
+	}
+
+	return groups
+}
+
+func (h *HugoSites) withRenderTask(tasks []siteRenderTask, fn func(t siteRenderTask) error) error {
+	if h.workers == nil || len(tasks) == 1 {
+		for _, t := range tasks {
+			if err := fn(t); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	g, _ := h.workers.Start(context.Background())
+	for _, t := range tasks {
+		t := t
+		g.Run(func() error {
+			return fn(t)
+		})
+	}
+	return g.Wait()
+}
+
 func (h *HugoSites) postProcess() error {
 	// Make sure to write any build stats to disk first so it's available
 	// to the post processors.
@@ -346,6 +480,26 @@ func (h *HugoSites) postProcess() error {
 		return err
 	}
 
+	if err := h.writePublishManifest(); err != nil {
+		return err
+	}
+
+	if err := h.writeConsentManifest(); err != nil {
+		return err
+	}
+
+	if err := h.writeOutboundLinks(); err != nil {
+		return err
+	}
+
+	if err := h.pageIDs.writeGenerated(h); err != nil {
+		return err
+	}
+
+	if err := h.pageIDs.write(); err != nil {
+		return err
+	}
+
 	// This will only be set when js.Build have been triggered with
 	// imports that resolves to the project or a module.
 	// Write a jsconfig.json file to the project's /asset directory
@@ -464,6 +618,196 @@ func (h *HugoSites) postProcess() error {
 	return g.Wait()
 }
 
+type publishManifest struct {
+	mu     sync.Mutex
+	hashes map[string]string
+}
+
+func (m *publishManifest) add(relPermalink, hash string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.hashes == nil {
+		m.hashes = make(map[string]string)
+	}
+	m.hashes[relPermalink] = hash
+}
+
+func (m *publishManifest) reset() {
+	m.mu.Lock()
+	m.hashes = nil
+	m.mu.Unlock()
+}
+
+// publishManifestHandler returns a func that adds the hash of the bytes
+// published to targetPath to the publish manifest, nil if not enabled.
+func (s *Site) publishManifestHandler(targetPath string) func(hash string) {
+	if !s.ResourceSpec.BuildConfig.WriteManifest {
+		return nil
+	}
+	key := path.Join("/", filepath.ToSlash(targetPath))
+	if strings.HasSuffix(key, "/index.html") {
+		key = strings.TrimSuffix(key, "index.html")
+	}
+	return func(hash string) {
+		s.h.publishManifest.add(key, hash)
+	}
+}
+
+func (h *HugoSites) writePublishManifest() error {
+	if !h.ResourceSpec.BuildConfig.WriteManifest {
+		return nil
+	}
+
+	h.publishManifest.mu.Lock()
+	defer h.publishManifest.mu.Unlock()
+
+	js, err := json.MarshalIndent(map[string]interface{}{"pages": h.publishManifest.hashes}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return afero.WriteFile(h.BaseFs.PublishFs, "hugo_manifest.json", js, 0666)
+}
+
+// writeAssetsManifest writes the URLs of the resources published by the
+// resource transformations to assets-manifest.json, when enabled.
+func (h *HugoSites) writeAssetsManifest() error {
+	if !h.ResourceSpec.BuildConfig.WriteAssetsManifest {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(h.ResourceSpec.AssetsManifest.Entries()); err != nil {
+		return err
+	}
+
+	return afero.WriteFile(h.BaseFs.PublishFs, "assets-manifest.json", buf.Bytes(), 0666)
+}
+
+// writeAssetReport writes the composition of the bundles built by js.Build
+// to hugo_asset_report.json and hugo_asset_report.html, when enabled.
+func (h *HugoSites) writeAssetReport() error {
+	if !h.ResourceSpec.BuildConfig.WriteAssetReport {
+		return nil
+	}
+
+	report := h.ResourceSpec.AssetReport
+
+	var jsonBuf bytes.Buffer
+	enc := json.NewEncoder(&jsonBuf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report.Bundles()); err != nil {
+		return err
+	}
+
+	var htmlBuf bytes.Buffer
+	if err := report.WriteHTML(&htmlBuf); err != nil {
+		return err
+	}
+
+	for name, b := range map[string][]byte{
+		"hugo_asset_report.json": jsonBuf.Bytes(),
+		"hugo_asset_report.html": htmlBuf.Bytes(),
+	} {
+		filename := filepath.Join(h.WorkingDir, name)
+
+		// Make sure it's always written to the OS fs.
+		if err := afero.WriteFile(hugofs.Os, filename, b, 0666); err != nil {
+			return err
+		}
+
+		// Write to the destination, too, if a mem fs is in play.
+		if h.Fs.Source != hugofs.Os {
+			if err := afero.WriteFile(h.Fs.Destination, filename, b, 0666); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// reportNetworkPolicyViolations logs the network access denied in this
+// build, and writes them to networkPolicy.report, if set.
+func (h *HugoSites) reportNetworkPolicyViolations() error {
+	policy := netpolicy.Get(h.Cfg)
+	if policy == nil {
+		return nil
+	}
+	defer policy.Reset()
+
+	violations := policy.Violations()
+	if len(violations) > 0 {
+		h.Log.Warnf("network policy: %d violations:", len(violations))
+		for _, v := range violations {
+			h.Log.Warnf("  %s %q: %s", v.Kind, v.Target, v.Reason)
+		}
+	}
+
+	report := policy.Config().Report
+	if report == "" {
+		return nil
+	}
+
+	if violations == nil {
+		violations = []netpolicy.Violation{}
+	}
+	js, err := json.MarshalIndent(violations, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	filename := h.AbsPathify(report)
+	return helpers.WriteToDisk(filename, bytes.NewReader(js), h.Fs.Source)
+}
+
+// writeConsentManifest writes the privacy services requiring consent to
+// privacy.consentManifest, if set, for client-side consent managers.
+func (h *HugoSites) writeConsentManifest() error {
+	pc := h.Sites[0].siteConfigConfig.Privacy
+	if pc.ConsentManifest == "" {
+		return nil
+	}
+
+	js, err := json.MarshalIndent(map[string]interface{}{"services": pc.Manifest()}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return helpers.WriteToDisk(filepath.Clean(pc.ConsentManifest), bytes.NewReader(js), h.BaseFs.PublishFs)
+}
+
+// writeOutboundLinks writes the mapping from the rewritten outbound links to
+// the original URLs to outboundLinks.mappingFile, e.g. to set up the
+// redirects on the server.
+func (h *HugoSites) writeOutboundLinks() error {
+	conf, err := outboundlinks.DecodeConfig(h.Cfg)
+	if err != nil || !conf.Enabled() || conf.MappingFile == "" {
+		return err
+	}
+
+	links := make(map[string]string)
+	for _, s := range h.Sites {
+		for k, v := range s.publisher.PublishStats().OutboundLinks {
+			links[k] = v
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(links); err != nil {
+		return err
+	}
+
+	return helpers.WriteToDisk(filepath.Clean(conf.MappingFile), &buf, h.BaseFs.PublishFs)
+}
+
 type publishStats struct {
 	CSSClasses string `json:"cssClasses"`
 }
@@ -1466,3 +1466,37 @@ func TestRebuildOnAssetChange(t *testing.T) {
 	b.Build(BuildCfg{})
 	b.AssertFileContent("public/index.html", `changed data`)
 }
+
+// The languages are rendered in parallel, but the pages must always win over
+// an alias to the same target in another language.
+func TestRenderSharedTargetsMultilingual(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "https://example.org/"
+defaultContentLanguage = "en"
+disableKinds = ["RSS", "sitemap", "robotsTXT", "taxonomy", "term"]
+[languages]
+[languages.en]
+weight = 1
+[languages.nn]
+weight = 2
+`)
+
+	b.WithContent(
+		"p1.en.md", "---\ntitle: P1 en\n---\n",
+		"p2.nn.md", "---\ntitle: P2 nn\naliases: [/p1/, /old/]\n---\n",
+	)
+
+	b.WithTemplates(
+		"_default/single.html", `Single: {{ .Title }}`,
+		"_default/list.html", `List: {{ .Title }}`,
+	)
+
+	for i := 0; i < 5; i++ {
+		b.Build(BuildCfg{})
+
+		b.AssertFileContent("public/p1/index.html", "Single: P1 en")
+		b.AssertFileContent("public/old/index.html", "url=https://example.org/nn/p2/")
+	}
+}
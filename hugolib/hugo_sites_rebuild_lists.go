@@ -0,0 +1,185 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"github.com/gohugoio/hugo/helpers"
+	"github.com/gohugoio/hugo/resources/page"
+	"github.com/gohugoio/hugo/tpl"
+)
+
+// pageListKey holds the page values that decide which lists a page is in,
+// its position in them and what the lists may show of it.
+type pageListKey struct {
+	kind    string
+	section string
+	draft   bool
+	weight  int
+
+	title     string
+	linkTitle string
+
+	date        int64
+	publishDate int64
+	lastmod     int64
+	expiryDate  int64
+
+	// Covers the taxonomies and any sorting or filtering by param.
+	params string
+
+	// The lists may show e.g. the summary.
+	content string
+}
+
+func newPageListKey(p page.Page) pageListKey {
+	return pageListKey{
+		kind:        p.Kind(),
+		section:     p.Section(),
+		draft:       p.Draft(),
+		weight:      p.Weight(),
+		title:       p.Title(),
+		linkTitle:   p.LinkTitle(),
+		date:        p.Date().UnixNano(),
+		publishDate: p.PublishDate().UnixNano(),
+		lastmod:     p.Lastmod().UnixNano(),
+		expiryDate:  p.ExpiryDate().UnixNano(),
+		params:      helpers.HashString(p.Params()),
+		content:     helpers.MD5String(p.RawContent()),
+	}
+}
+
+// pageListState is the list key of a page and the list pages it is in.
+type pageListState struct {
+	lang  string
+	key   pageListKey
+	lists []string
+}
+
+func newPageListState(p *pageState) pageListState {
+	var lists []string
+
+	addAncestors := func(p page.Page) {
+		for ; p != nil; p = p.Parent() {
+			lists = append(lists, p.RelPermalink())
+		}
+	}
+
+	addAncestors(p.Parent())
+	for _, taxonomy := range p.s.siteCfg.taxonomiesConfig {
+		for _, term := range p.GetTerms(taxonomy) {
+			addAncestors(term)
+		}
+	}
+
+	return pageListState{lang: p.Lang(), key: newPageListKey(p), lists: lists}
+}
+
+// pageListStates returns the list states of the pages built from the given
+// content files, keyed by filename.
+func (h *HugoSites) pageListStates(filenames map[string]bool) map[string][]pageListState {
+	states := make(map[string][]pageListState)
+	for filename := range filenames {
+		states[filename] = nil
+	}
+
+	for _, s := range h.Sites {
+		for _, p := range s.Pages() {
+			ps := p.(*pageState)
+			if ps.File().IsZero() {
+				continue
+			}
+			filename := ps.File().Filename()
+			if !filenames[filename] {
+				continue
+			}
+			states[filename] = append(states[filename], newPageListState(ps))
+		}
+	}
+
+	return states
+}
+
+// setListsAffected sets the list pages affected by the changes in the
+// content files given in whatChanged.listsBefore. These are the lists a
+// changed page was in before or is in after the rebuild, e.g. its sections
+// and terms. The other list pages are not re-rendered, unless their
+// templates may read other pages, see readsOtherPages. A file without pages
+// before and after the change, e.g. a draft, affects no list.
+func (h *HugoSites) setListsAffected(changed *whatChanged) {
+	if changed.listsBefore == nil {
+		return
+	}
+
+	filenames := make(map[string]bool)
+	for filename := range changed.listsBefore {
+		filenames[filename] = true
+	}
+
+	affected := make(map[string]bool)
+
+	for filename, after := range h.pageListStates(filenames) {
+		before := changed.listsBefore[filename]
+
+		if before == nil && after == nil {
+			continue
+		}
+
+		for _, a := range after {
+			var found bool
+			for _, b := range before {
+				if b.lang == a.lang {
+					found = b.key == a.key
+					break
+				}
+			}
+			if !found {
+				for _, l := range a.lists {
+					affected[l] = true
+				}
+			}
+		}
+
+		for _, b := range before {
+			var found bool
+			for _, a := range after {
+				if a.lang == b.lang {
+					found = a.key == b.key
+					break
+				}
+			}
+			if !found {
+				for _, l := range b.lists {
+					affected[l] = true
+				}
+			}
+		}
+	}
+
+	changed.listsAffected = affected
+	changed.allListsAffected = false
+}
+
+// readsOtherPages reports whether the template of p in the current output
+// format may read other pages than p and the pages below it, e.g. a home
+// page listing site.RegularPages. This is assumed if not known.
+func (p *pageState) readsOtherPages() bool {
+	templ, found, err := p.resolveTemplate()
+	if err != nil || !found {
+		return true
+	}
+	if rp, ok := templ.(tpl.PageReadsProvider); ok {
+		return rp.ReadsOtherPages()
+	}
+	return true
+}
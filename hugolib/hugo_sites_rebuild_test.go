@@ -14,6 +14,7 @@
 package hugolib
 
 import (
+	"path/filepath"
 	"testing"
 
 	qt "github.com/frankban/quicktest"
@@ -314,3 +315,178 @@ P5 changed content
 P6 changed content
 `)
 }
+
+func TestRebuildListsAffected(t *testing.T) {
+	c := qt.New(t)
+
+	b := newTestSitesBuilder(t).Running()
+	b.WithConfigFile("toml", `
+baseURL = "https://example.com"
+disableKinds = ["RSS", "sitemap", "robotsTXT", "404"]
+`)
+	b.WithContent(
+		"blog/p1.md", "---\ntitle: P1\n---\nP1 content.",
+		"blog/p2.md", "---\ntitle: P2\n---\nP2 content.",
+		"docs/d1.md", "---\ntitle: D1\ntags: [a]\n---\nD1 content.",
+		"docs/draft.md", "---\ntitle: Draft\ndraft: true\n---\nDraft content.",
+	)
+	b.WithTemplatesAdded(
+		"_default/list.html", `{{ range .RegularPagesRecursive }}{{ .Title }}|{{ end }}`,
+		"_default/terms.html", `Terms: {{ range .Pages }}{{ .Title }}|{{ end }}`,
+		"_default/taxonomy.html", `Term: {{ range .Pages }}{{ .Title }}|{{ end }}`,
+		"_default/single.html", `{{ .Title }}|{{ .Content }}`,
+	)
+
+	b.Build(BuildCfg{})
+
+	lists := []string{"public/index.html", "public/blog/index.html", "public/docs/index.html", "public/tags/index.html", "public/tags/a/index.html"}
+
+	removeLists := func() {
+		for _, filename := range lists {
+			c.Assert(b.Fs.Destination.RemoveAll(filepath.FromSlash(filename)), qt.IsNil)
+		}
+	}
+
+	assertListsRendered := func(expected ...string) {
+		c.Helper()
+		for _, filename := range lists {
+			var want bool
+			for _, e := range expected {
+				want = want || e == filename
+			}
+			c.Assert(b.CheckExists(filename), qt.Equals, want, qt.Commentf(filename))
+		}
+	}
+
+	// Saved without changes.
+	removeLists()
+	b.EditFiles("content/blog/p1.md", "---\ntitle: P1\n---\nP1 content.")
+	b.Build(BuildCfg{})
+	assertListsRendered()
+
+	// Only the content changed.
+	b.EditFiles("content/blog/p1.md", "---\ntitle: P1\n---\nP1 edited.")
+	b.Build(BuildCfg{})
+	b.AssertFileContent("public/blog/p1/index.html", "P1|<p>P1 edited.</p>")
+	assertListsRendered("public/index.html", "public/blog/index.html")
+
+	// The title changed.
+	removeLists()
+	b.EditFiles("content/blog/p1.md", "---\ntitle: P1 edited\n---\nP1 edited.")
+	b.Build(BuildCfg{})
+	assertListsRendered("public/index.html", "public/blog/index.html")
+	b.AssertFileContent("public/blog/index.html", "P1 edited|P2|")
+
+	// The page moved out of a term.
+	removeLists()
+	b.EditFiles("content/docs/d1.md", "---\ntitle: D1\n---\nD1 content.")
+	b.Build(BuildCfg{})
+	assertListsRendered("public/index.html", "public/docs/index.html", "public/tags/index.html", "public/tags/a/index.html")
+
+	// A draft not built, changed with another page.
+	removeLists()
+	b.EditFiles("content/docs/draft.md", "---\ntitle: Draft\ndraft: true\n---\nDraft edited.")
+	b.EditFiles("content/blog/p2.md", "---\ntitle: P2 edited\n---\nP2 content.")
+	b.Build(BuildCfg{})
+	assertListsRendered("public/index.html", "public/blog/index.html")
+
+	// A new page.
+	removeLists()
+	b.EditFiles("content/blog/p3.md", "---\ntitle: P3\n---\nP3 content.")
+	b.Build(BuildCfg{})
+	assertListsRendered("public/index.html", "public/blog/index.html")
+	b.AssertFileContent("public/blog/index.html", "P1 edited|P2 edited|P3|")
+
+	// A template changed, so everything is rendered.
+	removeLists()
+	b.EditFiles("layouts/_default/single.html", `{{ .Title }}`)
+	b.Build(BuildCfg{})
+	assertListsRendered(lists...)
+}
+
+func TestRebuildListsReadingOtherPages(t *testing.T) {
+	c := qt.New(t)
+
+	b := newTestSitesBuilder(t).Running()
+	b.WithConfigFile("toml", `
+baseURL = "https://example.com"
+title = "My Site"
+disableKinds = ["RSS", "sitemap", "robotsTXT", "404", "taxonomy", "term"]
+`)
+	b.WithContent(
+		"blog/p1.md", "---\ntitle: P1\n---\nP1 content.",
+		"docs/d1.md", "---\ntitle: D1\n---\nD1 content.",
+	)
+	b.WithTemplatesAdded(
+		"index.html", `Recent: {{ range site.RegularPages }}{{ .Title }}|{{ end }}`,
+		"_default/list.html", `{{ .Site.Title }}: {{ range .Pages }}{{ .Title }}|{{ end }}`,
+		"_default/single.html", `{{ .Title }}`,
+	)
+
+	b.Build(BuildCfg{})
+
+	lists := []string{"public/index.html", "public/blog/index.html", "public/docs/index.html"}
+	for _, filename := range lists {
+		c.Assert(b.Fs.Destination.RemoveAll(filepath.FromSlash(filename)), qt.IsNil)
+	}
+
+	b.EditFiles("content/docs/d1.md", "---\ntitle: D1 edited\n---\nD1 content.")
+	b.Build(BuildCfg{})
+
+	// The home page reads site.RegularPages, so it is always rendered.
+	b.AssertFileContent("public/index.html", "D1 edited|")
+	b.AssertFileContent("public/docs/index.html", "My Site: D1 edited|")
+	c.Assert(b.CheckExists("public/blog/index.html"), qt.IsFalse)
+}
+
+func TestRebuildListsReadingOtherPagesIdents(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		templ    string
+		rendered bool
+	}{
+		{"Pages", `{{ range .Pages }}{{ .Title }}|{{ end }}`, false},
+		{"Ref", `{{ .Ref (dict "path" "/blog/p1") }}`, true},
+		{"RelRef", `{{ .RelRef (dict "path" "/blog/p1") }}`, true},
+		{"ref", `{{ ref . "/blog/p1" }}`, true},
+		{"relref", `{{ relref . "/blog/p1" }}`, true},
+		{"urls.RelRef", `{{ urls.RelRef . "/blog/p1" }}`, true},
+		{"SeriesPages", `{{ range .SeriesPages }}{{ .Title }}|{{ end }}`, true},
+		{"NextInSeries", `{{ with .NextInSeries }}{{ .Title }}{{ end }}`, true},
+		{"PrevInSeries", `{{ with .PrevInSeries }}{{ .Title }}{{ end }}`, true},
+		{"NextIn", `{{ with .NextIn .Pages }}{{ .Title }}{{ end }}`, true},
+		{"PrevIn", `{{ with .PrevIn .Pages }}{{ .Title }}{{ end }}`, true},
+		{"Sequences", `{{ with .Sequences.Get "tour" }}{{ range .Pages }}{{ .Title }}|{{ end }}{{ end }}`, true},
+	} {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			c := qt.New(t)
+
+			b := newTestSitesBuilder(t).Running()
+			b.WithConfigFile("toml", `
+baseURL = "https://example.com"
+disableKinds = ["RSS", "sitemap", "robotsTXT", "404", "taxonomy", "term"]
+[sequences.tour]
+pages = ["/docs", "/blog/p1"]
+`)
+			b.WithContent(
+				"blog/p1.md", "---\ntitle: P1\nseries: S\n---\nP1 content.",
+				"docs/_index.md", "---\ntitle: Docs\nseries: S\n---\n",
+				"docs/d1.md", "---\ntitle: D1\n---\nD1 content.",
+			)
+			b.WithTemplatesAdded(
+				"index.html", `Home`,
+				"_default/list.html", "{{ .Title }}|"+test.templ,
+				"_default/single.html", `{{ .Title }}`,
+			)
+
+			b.Build(BuildCfg{})
+
+			c.Assert(b.Fs.Destination.RemoveAll(filepath.FromSlash("public/docs/index.html")), qt.IsNil)
+			b.EditFiles("content/blog/p1.md", "---\ntitle: P1 edited\nseries: S\n---\nP1 content.")
+			b.Build(BuildCfg{})
+
+			c.Assert(b.CheckExists("public/docs/index.html"), qt.Equals, test.rendered)
+		})
+	}
+}
@@ -14,6 +14,7 @@
 package hugolib
 
 import (
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
@@ -245,3 +246,164 @@ SUNSET2: {{ $resized2.RelPermalink }}/{{ $resized2.Width }}/Lat: {{ $resized2.Ex
 	// TODO(bep) add this as a default assertion after Build()?
 	b.AssertNoDuplicateWrites()
 }
+
+func TestImageResizeMultilingualProcessedOnce(t *testing.T) {
+	for _, multihost := range []bool{false, true} {
+		multihost := multihost
+		t.Run(fmt.Sprintf("multihost=%t", multihost), func(t *testing.T) {
+			config := `
+baseURL="https://example.org"
+defaultContentLanguage = "en"
+
+[languages]
+[languages.en]
+weight = 1
+[languages.nn]
+weight = 2
+[languages.fr]
+weight = 3
+`
+			if multihost {
+				config = strings.Replace(config, "weight = ", "baseURL = \"https://example.org\"\nweight = ", -1)
+			}
+
+			b := newTestSitesBuilder(t).WithConfigFile("toml", config)
+			b.WithContent("_index.md", `---
+title: "Home"
+---
+`)
+			b.WithSunset("assets/images/sunset.jpg")
+			b.WithTemplates("index.html", `
+{{ $resized := (resources.Get "images/sunset.jpg").Resize "123x234" }}
+SUNSET: {{ $.Site.Language.Lang }}: {{ $resized.RelPermalink }}/{{ $resized.Width }}
+`)
+
+			b.Build(BuildCfg{})
+
+			var processed uint64
+			for _, s := range b.H.Sites {
+				processed += s.PathSpec.ProcessingStats.ProcessedImages
+			}
+
+			// The languages are rendered in parallel, but share the processed images.
+			b.Assert(processed, qt.Equals, uint64(1))
+
+			filename := "/images/sunset_hu59e56ffff1bc1d8d122b1403d34e039f_90587_123x234_resize_q75_box.jpg"
+			for _, lang := range []string{"en", "nn", "fr"} {
+				dir := "public/" + lang
+				if lang == "en" && !multihost {
+					dir = "public"
+				}
+				b.AssertFileContent(dir+"/index.html", "SUNSET: "+lang+": "+filename+"/123")
+				if multihost {
+					// Published to every host.
+					b.AssertImage(123, 234, dir+filename)
+				}
+			}
+
+			if !multihost {
+				b.AssertImage(123, 234, "public"+filename)
+			}
+		})
+	}
+}
+
+func TestImagePlaceholders(t *testing.T) {
+	c := qt.New(t)
+
+	b := newTestSitesBuilder(t)
+	b.WithTemplatesAdded("index.html", `
+{{ $img := resources.Get "images/sunset.jpg" }}
+Blurhash: {{ images.Blurhash $img }}|{{ len (images.Blurhash $img) }}|
+Blurhash54: {{ len (images.Blurhash 5 4 $img) }}|
+ThumbHash: {{ len (images.ThumbHash $img | base64Decode) }}|
+<img src="{{ images.Placeholder $img }}">
+`)
+	b.WithSunset("assets/images/sunset.jpg")
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/index.html",
+		"|28|",
+		"Blurhash54: 44|",
+		"ThumbHash: 19|",
+		`<img src="data:image/png;base64,`,
+	)
+	c.Assert(b.FileContent("public/index.html"), qt.Not(qt.Contains), "ZgotmplZ")
+	c.Assert(b.CheckExists("public/images/sunset_hu59e56ffff1bc1d8d122b1403d34e039f_90587_100x100_fit_q75_box.jpg"), qt.Equals, false)
+}
+
+func TestImageProfiles(t *testing.T) {
+	b := newTestSitesBuilder(t)
+	b.WithConfigFile("toml", `
+baseURL = "https://example.org"
+[imaging.profiles]
+hero = "fill 200x100 png"
+thumb = "fit 100x100"
+[[imaging.profileRules]]
+section = "blog"
+pattern = "*.jpg"
+profiles = ["hero", "thumb"]
+`)
+	b.WithContent(
+		"blog/post/index.md", "---\ntitle: Post\n---",
+		"docs/doc/index.md", "---\ntitle: Doc\n---",
+		"blog/custom/index.md", `---
+title: Custom
+resources:
+- src: "*.jpg"
+  params:
+    profiles: ["thumb"]
+---`,
+	)
+	b.WithSunset("content/blog/post/sunset.jpg")
+	b.WithSunset("content/docs/doc/sunset.jpg")
+	b.WithSunset("content/blog/custom/sunset.jpg")
+	b.WithTemplatesAdded("_default/single.html", `
+{{ $img := .Resources.GetMatch "sunset.jpg" }}
+{{ range $name, $derived := $img.Derived }}{{ $name }}: {{ $derived.Width }}x{{ $derived.Height }}|{{ end }}
+Profile: {{ with $img.Profile "hero" }}{{ .RelPermalink }}{{ end }}
+`)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/blog/post/index.html", "hero: 200x100|thumb: 100x62|", "Profile: /blog/post/sunset_hu")
+	b.AssertFileContent("public/blog/custom/index.html", "\nthumb: 100x62|\n")
+	b.AssertFileContent("public/docs/doc/index.html", "\n\nProfile: /docs/doc/sunset_hu")
+}
+
+func TestImageSrcset(t *testing.T) {
+	c := qt.New(t)
+
+	b := newTestSitesBuilder(t)
+	b.WithTemplatesAdded("index.html", `
+{{ $img := resources.Get "images/sunset.jpg" }}
+{{ $s := images.Srcset (dict "widths" (slice 1200 300 600) "sizes" "(min-width: 800px) 50vw, 100vw" "alt" "Sunset \"1\"" "formats" (slice "png")) $img }}
+Srcset: {{ $s.Srcset }}|{{ $s.Width }}x{{ $s.Height }}|{{ len $s.Images }}|
+Img: {{ $s.Img }}
+Picture: {{ $s.Picture }}
+{{ $s = images.Srcset (dict "widths" (slice 300 600) "ratio" "1:1" "quality" 50) $img }}
+Ratio: {{ range $s.Images }}{{ .Width }}x{{ .Height }}|{{ end }}
+{{ $s = images.Srcset (dict "widths" (slice 2000)) $img }}
+Small: {{ range $s.Images }}{{ .Width }}x{{ .Height }}|{{ end }}
+`)
+	b.WithSunset("assets/images/sunset.jpg")
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/index.html",
+		"|600x375|2|",
+		"Srcset: /images/sunset_hu59e56ffff1bc1d8d122b1403d34e039f_90587_300x0_resize_q75_box.jpg 300w, /images/sunset_hu59e56ffff1bc1d8d122b1403d34e039f_90587_600x0_resize_q75_box.jpg 600w|",
+		`Img: <img src="/images/sunset_hu59e56ffff1bc1d8d122b1403d34e039f_90587_600x0_resize_q75_box.jpg" srcset="/images/sunset_hu59e56ffff1bc1d8d122b1403d34e039f_90587_300x0_resize_q75_box.jpg 300w, /images/sunset_hu59e56ffff1bc1d8d122b1403d34e039f_90587_600x0_resize_q75_box.jpg 600w" sizes="(min-width: 800px) 50vw, 100vw" width="600" height="375" alt="Sunset &#34;1&#34;" loading="lazy">`,
+		`Picture: <picture><source type="image/png" srcset="/images/sunset_hu59e56ffff1bc1d8d122b1403d34e039f_90587_300x0_resize_box.png 300w, /images/sunset_hu59e56ffff1bc1d8d122b1403d34e039f_90587_600x0_resize_box.png 600w" sizes="(min-width: 800px) 50vw, 100vw"><img src=`,
+		"Ratio: 300x300|",
+		"Small: 900x562|",
+	)
+	c.Assert(b.CheckExists("public/images/sunset_hu59e56ffff1bc1d8d122b1403d34e039f_90587_600x0_resize_box.png"), qt.Equals, true)
+
+	b = newTestSitesBuilder(t)
+	b.WithTemplatesAdded("index.html", `{{ images.Srcset (dict "formats" (slice "avif")) (resources.Get "images/sunset.jpg") }}`)
+	b.WithSunset("assets/images/sunset.jpg")
+	err := b.BuildE(BuildCfg{})
+	c.Assert(err, qt.ErrorMatches, `.*unsupported srcset format "avif".*`)
+}
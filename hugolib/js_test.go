@@ -14,9 +14,12 @@
 package hugolib
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
 	"runtime"
 	"testing"
 
@@ -28,6 +31,7 @@ import (
 	qt "github.com/frankban/quicktest"
 
 	"github.com/gohugoio/hugo/hugofs"
+	"github.com/gohugoio/hugo/resources"
 
 	"github.com/gohugoio/hugo/common/loggers"
 )
@@ -218,3 +222,86 @@ shim cwd
 module.exports = window.ReactDOM;
 `)
 }
+
+func TestJSBuildSourceMap(t *testing.T) {
+	b := newTestSitesBuilder(t)
+	b.WithConfigFile("toml", `
+disableKinds = ["taxonomy", "term", "page", "section", "sitemap", "RSS", "robotsTXT", "404"]
+`)
+	b.WithSourceFile("assets/js/main.js", `function hello(s) { return "Hello " + s; }
+console.log(hello("world"));
+`)
+
+	b.WithTemplatesAdded("index.html", `
+{{ $js := resources.Get "js/main.js" | js.Build (dict "sourceMap" "external" "sourcesContent" false) | fingerprint }}
+Fingerprinted: {{ $js.RelPermalink }}|{{ $js.Data.Integrity }}|
+{{ $inline := resources.Get "js/main.js" | js.Build (dict "targetPath" "js/inline.js" "sourceMap" "inline") }}
+Inline: {{ $inline.RelPermalink }}|
+{{ $omitted := resources.Get "js/main.js" | js.Build (dict "targetPath" "js/omitted.js" "sourceMap" "external" "omitSourceMapInProduction" true) }}
+Omitted: {{ $omitted.RelPermalink }}|
+`)
+
+	b.Build(BuildCfg{})
+
+	content := b.FileContent("public/index.html")
+	m := regexp.MustCompile(`Fingerprinted: (/js/main\.[a-f0-9]{64}\.js)\|sha256-`).FindStringSubmatch(content)
+	b.Assert(m, qt.HasLen, 2)
+	filename := "public" + m[1]
+
+	b.AssertFileContent(filename, `console.log`, "//# sourceMappingURL="+path.Base(filename)+".map")
+	b.AssertFileContent(filename+".map", `"sources":`, `"mappings":`)
+	b.Assert(b.FileContent(filename+".map"), qt.Not(qt.Contains), "sourcesContent")
+	b.Assert(b.CheckExists("public/js/main.js.map"), qt.Equals, false)
+
+	b.AssertFileContent("public/js/inline.js", "//# sourceMappingURL=data:application/json;base64,")
+
+	// The environment defaults to production.
+	b.Assert(b.FileContent("public/js/omitted.js"), qt.Not(qt.Contains), "sourceMappingURL")
+	b.Assert(b.CheckExists("public/js/omitted.js.map"), qt.Equals, false)
+}
+
+func TestJSBuildAssetReport(t *testing.T) {
+	c := qt.New(t)
+
+	workDir, clean, err := htesting.CreateTempDir(hugofs.Os, "hugo-test-js-report")
+	c.Assert(err, qt.IsNil)
+	defer clean()
+	c.Assert(os.MkdirAll(filepath.Join(workDir, "assets", "js"), 0777), qt.IsNil)
+
+	b := newTestSitesBuilder(t)
+	b.Fs = hugofs.NewDefault(config.New())
+	b.WithWorkingDir(workDir).WithConfigFile("toml", fmt.Sprintf(`
+baseURL = "https://example.org"
+workingDir = %q
+disableKinds = ["taxonomy", "term", "page", "section", "sitemap", "RSS", "robotsTXT", "404"]
+[build]
+writeAssetReport = true
+`, workDir))
+
+	b.WithSourceFile("assets/js/main.js", `import { hello } from './lib';
+console.log(hello("world"));
+`)
+	b.WithSourceFile("assets/js/lib.js", `export function hello(s) { return "Hello " + s + "! This is a somewhat longer string to make the lib the largest input."; }
+`)
+	b.WithContent("p1.md", "").WithTemplates("index.html", `
+{{ $js := resources.Get "js/main.js" | js.Build (dict "targetPath" "js/bundle.js") }}
+JS: {{ $js.RelPermalink }}|
+`)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/index.html", "JS: /js/bundle.js|")
+
+	var bundles []resources.AssetReportBundle
+	b.Assert(json.Unmarshal([]byte(b.FileContent(filepath.Join(workDir, "hugo_asset_report.json"))), &bundles), qt.IsNil)
+	b.Assert(bundles, qt.HasLen, 1)
+	bundle := bundles[0]
+	b.Assert(bundle.EntryPoint, qt.Equals, "js/main.js")
+	b.Assert(bundle.Target, qt.Equals, "js/bundle.js")
+	b.Assert(bundle.Bytes, qt.Equals, len(b.FileContent("public/js/bundle.js")))
+	b.Assert(bundle.Inputs, qt.HasLen, 2)
+	b.Assert(bundle.Inputs[0].Path, qt.Equals, "assets/js/lib.js")
+	b.Assert(bundle.Inputs[1].Path, qt.Equals, "js/main.js")
+
+	b.AssertFileContent(filepath.Join(workDir, "hugo_asset_report.html"), "<h2>js/bundle.js</h2>", "<td>assets/js/lib.js</td>")
+}
@@ -0,0 +1,84 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestLLMsTXTOutput(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "https://example.org/"
+title = "My Docs"
+enableLLMsTXT = true
+[params]
+description = "Docs for my project."
+[llms]
+full = true
+sections = ["docs"]
+`)
+
+	b.WithContent("docs/install.md", `---
+title: "Install"
+description: "How to install."
+---
+Run the installer.
+`, "blog/post.md", `---
+title: "Post"
+---
+Not in the index.
+`)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/llms.txt",
+		"# My Docs",
+		"> Docs for my project.",
+		"- [Install](https://example.org/docs/install/): How to install.",
+	)
+	b.AssertFileContent("public/llms-full.txt",
+		"## Install",
+		"Source: https://example.org/docs/install/",
+		"Run the installer.",
+	)
+
+	b.AssertFileContentFn("public/llms.txt", func(s string) bool {
+		return !strings.Contains(s, "Post")
+	})
+}
+
+func TestLLMsTXTCustomTemplate(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "https://example.org/"
+enableLLMsTXT = true
+`)
+
+	b.WithContent("p1.md", `---
+title: "P1"
+---
+`)
+	b.WithTemplatesAdded("layouts/llms.txt", `Custom: {{ range .Pages }}{{ .Title }}|{{ end }}`)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/llms.txt", "Custom: P1|")
+	b.Assert(b.CheckExists("public/llms-full.txt"), qt.Equals, false)
+}
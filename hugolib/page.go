@@ -52,9 +52,11 @@ import (
 	"github.com/gohugoio/hugo/common/collections"
 	"github.com/gohugoio/hugo/common/text"
 	"github.com/gohugoio/hugo/markup/converter/hooks"
+	"github.com/gohugoio/hugo/related"
 	"github.com/gohugoio/hugo/resources"
 	"github.com/gohugoio/hugo/resources/page"
 	"github.com/gohugoio/hugo/resources/resource"
+	"github.com/gohugoio/hugo/transform/resourcehints"
 )
 
 var (
@@ -260,6 +262,8 @@ func (p *pageState) Pages() page.Pages {
 			pages = p.bucket.getTaxonomyEntries()
 		case page.KindTaxonomy:
 			pages = p.bucket.getTaxonomies()
+		case kindLLMsTXT:
+			pages = p.s.llmsPages()
 		default:
 			pages = p.s.Pages()
 		}
@@ -283,6 +287,26 @@ func (p *pageState) RawContent() string {
 	return string(p.source.parsed.Input()[start:])
 }
 
+// RelatedKeywords implements the related.Document interface needed for fast page searches.
+// Pages without an embedding in the param for a vector index get one computed
+// from the title and raw content.
+func (p *pageState) RelatedKeywords(cfg related.IndexConfig) ([]related.Keyword, error) {
+	if !cfg.IsVector() {
+		return p.m.RelatedKeywords(cfg)
+	}
+
+	v, err := p.m.Param(cfg.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if v == nil {
+		v = p.Title() + "\n" + p.RawContent()
+	}
+
+	return cfg.ToKeywords(v)
+}
+
 func (p *pageState) sortResources() {
 	sort.SliceStable(p.resources, func(i, j int) bool {
 		ri, rj := p.resources[i], p.resources[j]
@@ -307,6 +331,16 @@ func (p *pageState) sortResources() {
 	})
 }
 
+// ResourceHints returns a placeholder for the resource hints, see
+// page.ResourceHintsProvider.
+func (p *pageState) ResourceHints() template.HTML {
+	if p.outputFormat().Stream {
+		// Streamed output is written as it's rendered.
+		return ""
+	}
+	return template.HTML(resourcehints.Placeholder)
+}
+
 func (p *pageState) Resources() resource.Resources {
 	p.resourcesInit.Do(func() {
 		p.sortResources()
@@ -314,6 +348,7 @@ func (p *pageState) Resources() resource.Resources {
 			resources.AssignMetadata(p.m.resourcesMetadata, p.resources...)
 			p.sortResources()
 		}
+		resources.AssignImageProfiles(p.Section(), p.resources...)
 	})
 	return p.resources
 }
@@ -326,6 +361,14 @@ func (p *pageState) HasShortcode(name string) bool {
 	return p.shortcodeState.nameSet[name]
 }
 
+func (p *pageState) Shortcodes() page.ShortcodeInfos {
+	if p.shortcodeState == nil {
+		return nil
+	}
+
+	return p.shortcodeState.shortcodeInfos()
+}
+
 func (p *pageState) Site() page.Site {
 	return p.s.Info
 }
@@ -378,7 +421,9 @@ func (ps *pageState) initCommonProviders(pp pagePaths) error {
 	if ps.IsPage() {
 		ps.posNextPrev = &nextPrev{init: ps.s.init.prevNext}
 		ps.posNextPrevSection = &nextPrev{init: ps.s.init.prevNextInSection}
+		ps.posNextPrevSeries = &nextPrevInSeries{nextPrev: nextPrev{init: ps.s.init.prevNextInSeries}}
 		ps.InSectionPositioner = newPagePositionInSection(ps.posNextPrevSection)
+		ps.InSeriesPositioner = newPagePositionInSeries(ps.posNextPrevSeries)
 		ps.Positioner = newPagePosition(ps.posNextPrev)
 	}
 
@@ -50,6 +50,14 @@ func (p *pageCommon) getNextPrevInSection() *nextPrev {
 	return p.posNextPrevSection
 }
 
+type nextPrevInSeriesProvider interface {
+	getNextPrevInSeries() *nextPrevInSeries
+}
+
+func (p *pageCommon) getNextPrevInSeries() *nextPrevInSeries {
+	return p.posNextPrevSeries
+}
+
 type pageCommon struct {
 	s *Site
 	m *pageMeta
@@ -71,6 +79,7 @@ type pageCommon struct {
 	page.GetPageProvider
 	page.GitInfoProvider
 	page.InSectionPositioner
+	page.InSeriesPositioner
 	page.OutputFormatsProvider
 	page.PageMetaProvider
 	page.Positioner
@@ -107,6 +116,10 @@ type pageCommon struct {
 	// Positional navigation
 	posNextPrev        *nextPrev
 	posNextPrevSection *nextPrev
+	posNextPrevSeries  *nextPrevInSeries
+
+	// The named page sequences this page is in.
+	sequences page.Sequences
 
 	// Menus
 	pageMenus *pageMenus
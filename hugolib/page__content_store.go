@@ -0,0 +1,154 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"container/list"
+	"path/filepath"
+	"sync"
+
+	"github.com/gohugoio/hugo/cache/filecache"
+	"github.com/gohugoio/hugo/helpers"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// pageContentStore stores the rendered page content, summary and table of
+// contents on disk, keyed by their content hash, and keeps the most recently
+// used in memory. This is used instead of keeping them all in memory when
+// build.pageStore is set to "disk". The plain text is recreated from the
+// content when needed. The page source is still kept in memory, it's needed
+// to render the other output formats and to rebuild.
+//
+// The maxAge of the pagestore file cache decides whether the content stored
+// in earlier builds is reused: always with a negative maxAge (the default,
+// never expire), if not older than maxAge when positive, and never with
+// maxAge 0. The content stored in the current build is always kept.
+//
+// The content is keyed by its MD5 hash, which is checked when read, and
+// written to a temporary file first, so a partly written file, e.g. from an
+// interrupted build, is never used.
+type pageContentStore struct {
+	cache    *filecache.Cache
+	maxItems int
+
+	mu    sync.Mutex
+	lru   *list.List
+	items map[string]*list.Element
+}
+
+type pageContentStoreItem struct {
+	key     string
+	content []byte
+}
+
+func newPageContentStore(cache *filecache.Cache, maxItems int) *pageContentStore {
+	return &pageContentStore{
+		cache:    cache,
+		maxItems: maxItems,
+		lru:      list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+// put stores content with the given key, the MD5 hash of the content.
+func (s *pageContentStore) put(key string, content []byte) error {
+	if !s.has(key, content) {
+		if err := s.write(key, content); err != nil {
+			return errors.Wrap(err, "failed to write page content to store")
+		}
+	}
+
+	s.add(key, content)
+
+	return nil
+}
+
+// has reports whether the store holds content with the given key on disk. A
+// file of the wrong size is not used, it will be rewritten.
+func (s *pageContentStore) has(key string, content []byte) bool {
+	if !s.cache.Has(key) {
+		return false
+	}
+	fi, err := s.cache.Fs.Stat(key)
+	return err == nil && fi.Size() == int64(len(content))
+}
+
+// write writes content to a temporary file and renames it to key when done.
+func (s *pageContentStore) write(key string, content []byte) error {
+	dir := filepath.Dir(key)
+	if err := s.cache.Fs.MkdirAll(dir, 0777); err != nil {
+		return err
+	}
+
+	f, err := afero.TempFile(s.cache.Fs, dir, "_tmp")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(content)
+	if err1 := f.Close(); err == nil {
+		err = err1
+	}
+	if err == nil {
+		err = s.cache.Fs.Rename(f.Name(), key)
+	}
+	if err != nil {
+		s.cache.Fs.Remove(f.Name())
+	}
+
+	return err
+}
+
+// get gets the content stored with the given key.
+func (s *pageContentStore) get(key string) ([]byte, error) {
+	s.mu.Lock()
+	if el, found := s.items[key]; found {
+		s.lru.MoveToFront(el)
+		s.mu.Unlock()
+		return el.Value.(*pageContentStoreItem).content, nil
+	}
+	s.mu.Unlock()
+
+	content, err := afero.ReadFile(s.cache.Fs, key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read page content from store")
+	}
+
+	if helpers.MD5FromBytes(content) != key {
+		s.cache.Fs.Remove(key)
+		return nil, errors.Errorf("page content in store is corrupt: %q", key)
+	}
+
+	s.add(key, content)
+
+	return content, nil
+}
+
+func (s *pageContentStore) add(key string, content []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, found := s.items[key]; found {
+		s.lru.MoveToFront(el)
+		return
+	}
+
+	s.items[key] = s.lru.PushFront(&pageContentStoreItem{key: key, content: content})
+
+	for s.lru.Len() > s.maxItems {
+		el := s.lru.Back()
+		s.lru.Remove(el)
+		delete(s.items, el.Value.(*pageContentStoreItem).key)
+	}
+}
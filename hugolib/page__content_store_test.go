@@ -0,0 +1,159 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gohugoio/hugo/cache/filecache"
+	"github.com/gohugoio/hugo/helpers"
+	"github.com/spf13/afero"
+)
+
+func TestPageContentStore(t *testing.T) {
+	t.Parallel()
+
+	for _, pageStore := range []string{"memory", "disk"} {
+		pageStore := pageStore
+		t.Run(pageStore, func(t *testing.T) {
+			t.Parallel()
+			c := qt.New(t)
+
+			b := newTestSitesBuilder(t).WithConfigFile("toml", fmt.Sprintf(`
+baseURL = "https://example.org"
+[build]
+pageStore = %q
+pageStoreMemoryLimit = 1
+`, pageStore))
+
+			for i := 1; i <= 3; i++ {
+				b.WithContent(fmt.Sprintf("p%d.md", i), fmt.Sprintf(`---
+title: "P%d"
+---
+Content **%d** with some words.
+`, i, i))
+			}
+
+			b.WithContent("p4.md", `---
+title: "P4"
+---
+## Heading
+
+The summary.
+<!--more-->
+The rest.
+`)
+
+			b.WithTemplatesAdded(
+				"index.html", `{{ range .RegularPages }}{{ .Title }}|{{ .Content }}|{{ .Plain }}|{{ .Len }}|{{ .WordCount }}|{{ .Summary }}
+{{ end }}`,
+				"_default/single.html", `Single: {{ .Content }}|{{ len .PlainWords }}|TOC: {{ .TableOfContents }}|Summary: {{ .Summary }}`,
+			)
+
+			b.Build(BuildCfg{})
+
+			b.AssertFileContent("public/index.html",
+				"P1|<p>Content <strong>1</strong> with some words.</p>\n|Content 1 with some words.\n|51|5|Content 1 with some words.",
+				"P3|<p>Content <strong>3</strong> with some words.</p>\n|Content 3 with some words.\n|51|5|Content 3 with some words.",
+			)
+			b.AssertFileContent("public/p2/index.html", "Single: <p>Content <strong>2</strong> with some words.</p>\n|5")
+			b.AssertFileContent("public/p4/index.html",
+				`TOC: <nav id="TableOfContents">`, `<a href="#heading">Heading</a>`,
+				"Summary: <h2 id=\"heading\">Heading</h2>\n<p>The summary.</p>",
+			)
+
+			disk := pageStore == "disk"
+			c.Assert(b.H.pageContentStore != nil, qt.Equals, disk)
+
+			// Only the store keys are kept in the pages.
+			for _, p := range b.H.Sites[0].RegularPages() {
+				cp := p.(*pageState).pageOutput.cp
+				c.Assert(cp.content == "" && cp.summary == "" && cp.tableOfContents == "" && cp.plain == "", qt.Equals, disk)
+			}
+		})
+	}
+}
+
+func TestPageContentStoreMaxAge(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name    string
+		maxAge  time.Duration
+		rewrite bool
+	}{
+		{"never expire", -1, false},
+		{"expired", time.Hour, true},
+		{"disabled", 0, true},
+	} {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			c := qt.New(t)
+
+			fs := afero.NewMemMapFs()
+			store := newPageContentStore(filecache.NewCache(fs, test.maxAge, ""), 1)
+			key1, key2 := helpers.MD5String("content"), helpers.MD5String("content2")
+
+			// Stored in an earlier build.
+			c.Assert(afero.WriteFile(fs, key1, []byte("content"), 0666), qt.IsNil)
+			old := time.Now().Add(-2 * time.Hour)
+			c.Assert(fs.Chtimes(key1, old, old), qt.IsNil)
+
+			c.Assert(store.put(key1, []byte("content")), qt.IsNil)
+			fi, err := fs.Stat(key1)
+			c.Assert(err, qt.IsNil)
+			c.Assert(fi.ModTime().After(old), qt.Equals, test.rewrite)
+
+			// The content stored in this build is always available, also
+			// when evicted from memory.
+			c.Assert(store.put(key2, []byte("content2")), qt.IsNil)
+			b, err := store.get(key1)
+			c.Assert(err, qt.IsNil)
+			c.Assert(string(b), qt.Equals, "content")
+		})
+	}
+}
+
+func TestPageContentStoreCorrupt(t *testing.T) {
+	t.Parallel()
+	c := qt.New(t)
+
+	fs := afero.NewMemMapFs()
+	store := newPageContentStore(filecache.NewCache(fs, -1, ""), 1)
+	key1, key2 := helpers.MD5String("content"), helpers.MD5String("content2")
+
+	// Truncated in an earlier build, rewritten.
+	c.Assert(afero.WriteFile(fs, key1, []byte("cont"), 0666), qt.IsNil)
+	c.Assert(store.put(key1, []byte("content")), qt.IsNil)
+	b, err := afero.ReadFile(fs, key1)
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(b), qt.Equals, "content")
+
+	// No temporary files left.
+	fis, err := afero.ReadDir(fs, "")
+	c.Assert(err, qt.IsNil)
+	c.Assert(fis, qt.HasLen, 1)
+
+	// Changed on disk after it was stored.
+	c.Assert(store.put(key2, []byte("content2")), qt.IsNil)
+	c.Assert(afero.WriteFile(fs, key1, []byte("CONTENT"), 0666), qt.IsNil)
+	_, err = store.get(key1)
+	c.Assert(err, qt.ErrorMatches, `page content in store is corrupt: .*`)
+	exists, _ := afero.Exists(fs, key1)
+	c.Assert(exists, qt.IsFalse)
+}
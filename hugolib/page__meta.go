@@ -70,6 +70,9 @@ type pageMeta struct {
 	// Params contains configuration defined in the params section of page frontmatter.
 	params map[string]interface{}
 
+	// The structured content blocks set in front matter.
+	blocks page.Blocks
+
 	title     string
 	linkTitle string
 
@@ -79,9 +82,16 @@ type pageMeta struct {
 
 	weight int
 
+	// The weight assigned by the section's weight strategy, used if no
+	// weight is set in front matter.
+	autoWeight int
+
 	markup      string
 	contentType string
 
+	// The name of the HTML sanitization policy to apply to the rendered content, if any.
+	sanitize string
+
 	// whether the content is in a CJK language.
 	isCJKLanguage bool
 
@@ -89,6 +99,12 @@ type pageMeta struct {
 
 	aliases []string
 
+	// The stable page ID, from front matter or generated.
+	id string
+
+	// The sources of the front matter values, see ParamSource.
+	paramSources map[string]string
+
 	description string
 	keywords    []string
 
@@ -120,14 +136,27 @@ type pageMeta struct {
 	s *Site
 
 	renderingConfigOverrides map[string]interface{}
-	contentConverterInit     sync.Once
-	contentConverter         converter.Converter
+
+	// Per converter overrides of the markup config, e.g. goldmark, set in
+	// the markup map in front matter.
+	markupConfigOverrides map[string]interface{}
+
+	contentConverterInit sync.Once
+	contentConverter     converter.Converter
 }
 
 func (p *pageMeta) Aliases() []string {
 	return p.aliases
 }
 
+func (p *pageMeta) ID() string {
+	return p.id
+}
+
+func (p *pageMeta) ParamSource(key string) string {
+	return p.paramSources[strings.ToLower(key)]
+}
+
 func (p *pageMeta) Author() page.Author {
 	authors := p.Authors()
 
@@ -301,6 +330,9 @@ func (p *pageMeta) Type() string {
 }
 
 func (p *pageMeta) Weight() int {
+	if p.weight == 0 {
+		return p.autoWeight
+	}
 	return p.weight
 }
 
@@ -330,11 +362,17 @@ func (pm *pageMeta) mergeBucketCascades(b1, b2 *pagesMapBucket) {
 func (pm *pageMeta) setMetadata(parentBucket *pagesMapBucket, p *pageState, frontmatter map[string]interface{}) error {
 	pm.params = make(maps.Params)
 
-	if frontmatter == nil && (parentBucket == nil || parentBucket.cascade == nil) {
+	defaults := p.s.siteCfg.frontMatterDefaults
+
+	if frontmatter == nil && (parentBucket == nil || parentBucket.cascade == nil) && len(defaults) == 0 {
 		return nil
 	}
 
 	if frontmatter != nil {
+		if err := p.s.Secrets.DecryptValues(frontmatter); err != nil {
+			return errors.Wrapf(err, "%s: failed to decrypt front matter", p.pathOrTitle())
+		}
+
 		// Needed for case insensitive fetching of params values
 		maps.PrepareParams(frontmatter)
 		if p.bucket != nil {
@@ -351,6 +389,11 @@ func (pm *pageMeta) setMetadata(parentBucket *pagesMapBucket, p *pageState, fron
 		frontmatter = make(map[string]interface{})
 	}
 
+	pm.paramSources = make(map[string]string)
+	for k := range frontmatter {
+		pm.paramSources[k] = pagemeta.SourceFrontMatter
+	}
+
 	var cascade map[page.PageMatcher]maps.Params
 
 	if p.bucket != nil {
@@ -370,6 +413,23 @@ func (pm *pageMeta) setMetadata(parentBucket *pagesMapBucket, p *pageState, fron
 		for kk, vv := range v {
 			if _, found := frontmatter[kk]; !found {
 				frontmatter[kk] = vv
+				pm.paramSources[kk] = pagemeta.SourceCascade
+			}
+		}
+	}
+
+	if len(defaults) > 0 {
+		typ := cast.ToString(frontmatter["type"])
+		if typ == "" {
+			typ = pm.Section()
+		}
+		if typ == "" {
+			typ = defaultContentType
+		}
+		for k, v := range defaults[strings.ToLower(typ)] {
+			if _, found := frontmatter[k]; !found {
+				frontmatter[k] = v
+				pm.paramSources[k] = pagemeta.SourceDefaults
 			}
 		}
 	}
@@ -406,11 +466,20 @@ func (pm *pageMeta) setMetadata(parentBucket *pagesMapBucket, p *pageState, fron
 		p.s.Log.Errorf("Failed to handle dates for page %q: %s", p.pathOrTitle(), err)
 	}
 
-	pm.buildConfig, err = pagemeta.DecodeBuildConfig(frontmatter["_build"])
+	buildConfig, found := frontmatter["build"]
+	if !found {
+		// The old name.
+		buildConfig = frontmatter["_build"]
+	}
+	pm.buildConfig, err = pagemeta.DecodeBuildConfig(buildConfig)
 	if err != nil {
 		return err
 	}
 
+	// The params set in the params map, which take precedence over
+	// the params set as top level keys.
+	var params maps.Params
+
 	var sitemapSet bool
 
 	var draft, published, isCJKLanguage *bool
@@ -500,8 +569,18 @@ func (pm *pageMeta) setMetadata(parentBucket *pagesMapBucket, p *pageState, fron
 			pm.layout = cast.ToString(v)
 			pm.params[loki] = pm.layout
 		case "markup":
+			if m, ok := maps.ToParamsAndPrepare(v); ok {
+				// Overrides of the markup config for this page, e.g.
+				// markup.goldmark.renderer.unsafe.
+				pm.markupConfigOverrides = m
+				pm.params[loki] = m
+				break
+			}
 			pm.markup = cast.ToString(v)
 			pm.params[loki] = pm.markup
+		case "sanitize":
+			pm.sanitize = cast.ToString(v)
+			pm.params[loki] = pm.sanitize
 		case "weight":
 			pm.weight = cast.ToInt(v)
 			pm.params[loki] = pm.weight
@@ -514,10 +593,38 @@ func (pm *pageMeta) setMetadata(parentBucket *pagesMapBucket, p *pageState, fron
 				pm.aliases[i] = filepath.ToSlash(alias)
 			}
 			pm.params[loki] = pm.aliases
+		case "id":
+			pm.id = cast.ToString(v)
+			pm.params[loki] = pm.id
 		case "sitemap":
 			p.m.sitemap = config.DecodeSitemap(p.s.siteCfg.sitemap, maps.ToStringMap(v))
 			pm.params[loki] = p.m.sitemap
 			sitemapSet = true
+		case "blocks":
+			types, params, err := pagemeta.DecodeBlocks(v)
+			if err != nil {
+				p.s.Log.Warnf("%s: %s", p.pathOrTitle(), err)
+				break
+			}
+			pm.blocks = make(page.Blocks, len(types))
+			for i, typ := range types {
+				for _, err := range p.s.siteCfg.blockSchemas.Validate(typ, params[i]) {
+					p.s.Log.Warnf("%s: blocks[%d]: %s", p.pathOrTitle(), i, err)
+				}
+				pm.blocks[i] = page.Block{Type: typ, Params: params[i], Index: i, Page: p}
+			}
+			pm.params[loki] = v
+		case "params":
+			m, err := maps.ToStringMapE(v)
+			if err != nil {
+				return errors.Errorf("params: expected a map, got %T", v)
+			}
+			// Copy the map, so the one in front matter is left untouched.
+			params = make(maps.Params, len(m))
+			for kk, vv := range m {
+				params[kk] = vv
+			}
+			maps.PrepareParams(params)
 		case "iscjklanguage":
 			isCJKLanguage = new(bool)
 			*isCJKLanguage = cast.ToBool(v)
@@ -597,10 +704,24 @@ func (pm *pageMeta) setMetadata(parentBucket *pagesMapBucket, p *pageState, fron
 		}
 	}
 
+	for k, v := range params {
+		pm.params[k] = v
+	}
+
+	// Warn about, and where possible fix, params not of the types
+	// declared in the paramTypes config, e.g. a bool set as a string.
+	for _, err := range p.s.siteCfg.paramTypes.Coerce(pm.params) {
+		p.s.Log.Warnf("%s: %s", p.pathOrTitle(), err)
+	}
+
 	if !sitemapSet {
 		pm.sitemap = p.s.siteCfg.sitemap
 	}
 
+	if pm.id == "" && !p.File().IsZero() {
+		pm.id = p.s.h.pageIDs.generateID(p)
+	}
+
 	pm.markup = p.s.ContentSpec.ResolveMarkup(pm.markup)
 
 	if draft != nil && published != nil {
@@ -752,6 +873,10 @@ func (p *pageMeta) newContentConverter(ps *pageState, markup string, renderingCo
 		filename = p.f.Filename()
 	}
 
+	if m, found := p.markupConfigOverrides[cp.Name()]; found {
+		renderingConfigOverrides = maps.ToStringMap(m)
+	}
+
 	cpp, err := cp.New(
 		converter.DocumentContext{
 			Document:        newPageForRenderHook(ps),
@@ -44,6 +44,7 @@ func newPageBase(metaProvider *pageMeta) (*pageState, error) {
 			Scratcher:               maps.NewScratcher(),
 			Positioner:              page.NopPage,
 			InSectionPositioner:     page.NopPage,
+			InSeriesPositioner:      page.NopPage,
 			ResourceMetaProvider:    metaProvider,
 			ResourceParamsProvider:  metaProvider,
 			PageMetaProvider:        metaProvider,
@@ -52,7 +52,7 @@ func (p *pagePaginator) Paginate(seq interface{}, options ...interface{}) (*page
 
 		pd := p.source.targetPathDescriptor
 		pd.Type = p.source.outputFormat()
-		paginator, err := page.Paginate(pd, seq, pagerSize)
+		paginator, err := page.Paginate(pd, seq, pagerSize, p.source.s.Cfg.GetBool("paginateKeepGroups"))
 		if err != nil {
 			initErr = err
 			return
@@ -94,7 +94,7 @@ func (p *pagePaginator) Paginator(options ...interface{}) (*page.Pager, error) {
 			pages = p.source.RegularPages()
 		}
 
-		paginator, err := page.Paginate(pd, pages, pagerSize)
+		paginator, err := page.Paginate(pd, pages, pagerSize, false)
 		if err != nil {
 			initErr = err
 			return
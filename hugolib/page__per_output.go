@@ -84,6 +84,7 @@ func newPageContentOutput(p *pageState, po *pageOutput) (*pageContentOutput, err
 
 		if p.cmap == nil {
 			// Nothing to do.
+			cp.contentHash = helpers.MD5FromBytes(nil)
 			return nil
 		}
 		defer func() {
@@ -191,6 +192,32 @@ func newPageContentOutput(p *pageState, po *pageOutput) (*pageContentOutput, err
 			cp.summary = helpers.BytesToHTML(html)
 		}
 
+		if cp.p.m.sanitize != "" {
+			if err := cp.sanitize(); err != nil {
+				return err
+			}
+		}
+
+		cp.contentHash = helpers.MD5FromBytes(cp.workContent)
+
+		if store := p.s.h.pageContentStore; store != nil {
+			if err := store.put(cp.contentHash, cp.workContent); err != nil {
+				return err
+			}
+			cp.contentStored = true
+			cp.workContent = nil
+
+			if cp.summaryKey, err = cp.storeHTML(cp.summary); err != nil {
+				return err
+			}
+			if cp.tableOfContentsKey, err = cp.storeHTML(cp.tableOfContents); err != nil {
+				return err
+			}
+			cp.summary, cp.tableOfContents = "", ""
+
+			return nil
+		}
+
 		cp.content = helpers.BytesToHTML(cp.workContent)
 
 		return nil
@@ -211,7 +238,11 @@ func newPageContentOutput(p *pageState, po *pageOutput) (*pageContentOutput, err
 	}
 
 	cp.initPlain = cp.initMain.Branch(func() (interface{}, error) {
-		cp.plain = helpers.StripHTML(string(cp.content))
+		content, err := cp.getContent()
+		if err != nil {
+			return nil, err
+		}
+		cp.plain = helpers.StripHTML(string(content))
 		cp.plainWords = strings.Fields(cp.plain)
 		cp.setWordCounts(p.m.isCJKLanguage)
 
@@ -219,6 +250,20 @@ func newPageContentOutput(p *pageState, po *pageOutput) (*pageContentOutput, err
 			return err, nil
 		}
 
+		if cp.contentStored {
+			// Recreated from the stored content when needed.
+			cp.plain = ""
+			cp.plainWords = nil
+
+			if cp.summaryKey == "" {
+				key, err := cp.storeHTML(cp.summary)
+				if err != nil {
+					return nil, err
+				}
+				cp.summaryKey, cp.summary = key, ""
+			}
+		}
+
 		return nil, nil
 	})
 
@@ -264,9 +309,15 @@ type pageContentOutput struct {
 
 	// Content sections
 	content         template.HTML
+	contentHash     string
+	contentStored   bool // Set if content is kept in the page content store.
 	summary         template.HTML
 	tableOfContents template.HTML
 
+	// The keys of the summary and ToC in the page content store, if stored.
+	summaryKey         string
+	tableOfContentsKey string
+
 	truncated bool
 
 	plainWords     []string
@@ -293,11 +344,50 @@ func (p *pageContentOutput) Reset() {
 
 func (p *pageContentOutput) Content() (interface{}, error) {
 	if p.p.s.initInit(p.initMain, p.p) {
-		return p.content, nil
+		return p.getContent()
 	}
 	return nil, nil
 }
 
+// getContent returns the rendered content, read from the page content store
+// if stored there.
+func (p *pageContentOutput) getContent() (template.HTML, error) {
+	if !p.contentStored {
+		return p.content, nil
+	}
+	b, err := p.p.s.h.pageContentStore.get(p.contentHash)
+	if err != nil {
+		return "", err
+	}
+	return helpers.BytesToHTML(b), nil
+}
+
+// storeHTML puts s in the page content store and returns its key, empty if
+// s is empty.
+func (p *pageContentOutput) storeHTML(s template.HTML) (string, error) {
+	if s == "" {
+		return "", nil
+	}
+	key := helpers.MD5String(string(s))
+	if err := p.p.s.h.pageContentStore.put(key, []byte(s)); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+// loadHTML gets the HTML stored with key in the page content store, or s if
+// not stored.
+func (p *pageContentOutput) loadHTML(key string, s template.HTML) template.HTML {
+	if key == "" {
+		return s
+	}
+	b, err := p.p.s.h.pageContentStore.get(key)
+	if err != nil {
+		p.p.s.Log.Errorln(err)
+	}
+	return helpers.BytesToHTML(b)
+}
+
 func (p *pageContentOutput) FuzzyWordCount() int {
 	p.p.s.initInit(p.initPlain, p.p)
 	return p.fuzzyWordCount
@@ -305,16 +395,39 @@ func (p *pageContentOutput) FuzzyWordCount() int {
 
 func (p *pageContentOutput) Len() int {
 	p.p.s.initInit(p.initMain, p.p)
-	return len(p.content)
+	content, err := p.getContent()
+	if err != nil {
+		p.p.s.Log.Errorln(err)
+	}
+	return len(content)
+}
+
+func (p *pageContentOutput) OutputContentHash() string {
+	p.p.s.initInit(p.initMain, p.p)
+	return p.contentHash
+}
+
+func (p *pageContentOutput) ContentHash() string {
+	return p.OutputContentHash()
 }
 
 func (p *pageContentOutput) Plain() string {
 	p.p.s.initInit(p.initPlain, p.p)
+	if p.contentStored {
+		content, err := p.getContent()
+		if err != nil {
+			p.p.s.Log.Errorln(err)
+		}
+		return helpers.StripHTML(string(content))
+	}
 	return p.plain
 }
 
 func (p *pageContentOutput) PlainWords() []string {
 	p.p.s.initInit(p.initPlain, p.p)
+	if p.contentStored {
+		return strings.Fields(p.Plain())
+	}
 	return p.plainWords
 }
 
@@ -328,12 +441,12 @@ func (p *pageContentOutput) Summary() template.HTML {
 	if !p.p.source.hasSummaryDivider {
 		p.p.s.initInit(p.initPlain, p.p)
 	}
-	return p.summary
+	return p.loadHTML(p.summaryKey, p.summary)
 }
 
 func (p *pageContentOutput) TableOfContents() template.HTML {
 	p.p.s.initInit(p.initMain, p.p)
-	return p.tableOfContents
+	return p.loadHTML(p.tableOfContentsKey, p.tableOfContents)
 }
 
 func (p *pageContentOutput) Truncated() bool {
@@ -349,6 +462,28 @@ func (p *pageContentOutput) WordCount() int {
 	return p.wordCount
 }
 
+// sanitize applies the HTML sanitization policy set in front matter to the
+// rendered content and summary.
+func (p *pageContentOutput) sanitize() error {
+	sanitizer := p.p.s.ContentSpec.Sanitizer
+
+	var err error
+	p.workContent, err = sanitizer.Sanitize(p.p.m.sanitize, p.workContent)
+	if err != nil {
+		return p.p.wrapError(err)
+	}
+
+	if p.summary != "" {
+		summary, err := sanitizer.Sanitize(p.p.m.sanitize, []byte(p.summary))
+		if err != nil {
+			return p.p.wrapError(err)
+		}
+		p.summary = helpers.BytesToHTML(summary)
+	}
+
+	return nil
+}
+
 func (p *pageContentOutput) setAutoSummary() error {
 	if p.p.source.hasSummaryDivider || p.p.m.summary != "" {
 		return nil
@@ -478,7 +613,7 @@ func splitUserDefinedSummaryAndContent(markup string, c []byte) (summary []byte,
 
 	startTag := "p"
 	switch markup {
-	case "asciidocext":
+	case "asciidocext", "asciidoc":
 		startTag = "div"
 	}
 
@@ -14,8 +14,13 @@
 package hugolib
 
 import (
+	"strings"
+
+	"github.com/gohugoio/hugo/common/maps"
+	"github.com/gohugoio/hugo/config/nextprev"
 	"github.com/gohugoio/hugo/lazy"
 	"github.com/gohugoio/hugo/resources/page"
+	"github.com/spf13/cast"
 )
 
 func newPagePosition(n *nextPrev) pagePosition {
@@ -26,6 +31,10 @@ func newPagePositionInSection(n *nextPrev) pagePositionInSection {
 	return pagePositionInSection{nextPrev: n}
 }
 
+func newPagePositionInSeries(n *nextPrevInSeries) pagePositionInSeries {
+	return pagePositionInSeries{nextPrevInSeries: n}
+}
+
 type nextPrev struct {
 	init     *lazy.Init
 	prevPage page.Page
@@ -73,3 +82,169 @@ func (p pagePositionInSection) NextInSection() page.Page {
 func (p pagePositionInSection) PrevInSection() page.Page {
 	return p.prev()
 }
+
+type nextPrevInSeries struct {
+	nextPrev
+	pages page.Pages
+}
+
+func (n *nextPrevInSeries) seriesPages() page.Pages {
+	n.init.Do()
+	return n.pages
+}
+
+type pagePositionInSeries struct {
+	*nextPrevInSeries
+}
+
+func (p pagePositionInSeries) SeriesPages() page.Pages {
+	return p.seriesPages()
+}
+
+func (p pagePositionInSeries) NextInSeries() page.Page {
+	return p.next()
+}
+
+func (p pagePositionInSeries) PrevInSeries() page.Page {
+	return p.prev()
+}
+
+// NextIn returns the page after p in pages, see page.InPagesPositioner.
+func (p *pageState) NextIn(pages interface{}) (page.Page, error) {
+	pas, err := page.ToPages(pages)
+	if err != nil {
+		return nil, err
+	}
+	return pas.Next(p), nil
+}
+
+// PrevIn returns the page before p in pages, see page.InPagesPositioner.
+func (p *pageState) PrevIn(pages interface{}) (page.Page, error) {
+	pas, err := page.ToPages(pages)
+	if err != nil {
+		return nil, err
+	}
+	return pas.Prev(p), nil
+}
+
+// seriesName returns the name of the series p is in, set in the series front
+// matter key. If a list is given, which allows the same key to be used for a
+// series taxonomy, the first entry is used.
+func seriesName(p page.Page) string {
+	var name string
+	switch v := p.Params()["series"].(type) {
+	case string:
+		name = v
+	case []string:
+		if len(v) > 0 {
+			name = v[0]
+		}
+	case []interface{}:
+		if len(v) > 0 {
+			name = cast.ToString(v[0])
+		}
+	}
+	return strings.TrimSpace(name)
+}
+
+// nextPrevConfigFor returns the navigation config of the section at
+// sectionPath, i.e. that of the nearest configured section, and its key.
+func nextPrevConfigFor(cfg map[string]nextprev.Config, sectionPath string) (string, nextprev.Config, bool) {
+	if len(cfg) == 0 {
+		return "", nextprev.Config{}, false
+	}
+
+	key := strings.ToLower(strings.Trim(sectionPath, "/"))
+	for {
+		if c, found := cfg[key]; found {
+			return key, c, true
+		}
+		if key == "" {
+			return "", nextprev.Config{}, false
+		}
+		if i := strings.LastIndex(key, "/"); i != -1 {
+			key = key[:i]
+		} else {
+			key = ""
+		}
+	}
+}
+
+// setNextPrevConfigured orders pas as configured in c and sets the next and
+// prev pages of the pages not excluded, Next being the page after in that
+// order. The excluded pages get no next and prev pages.
+func setNextPrevConfigured(pas page.Pages, c nextprev.Config, getPos func(p page.Page) *nextPrev) {
+	var included page.Pages
+	for _, p := range pas {
+		if pos := getPos(p); pos != nil {
+			pos.nextPage = nil
+			pos.prevPage = nil
+		}
+		if !isExcludedFromNextPrev(p, c.Exclude) {
+			included = append(included, p)
+		}
+	}
+
+	switch c.OrderBy {
+	case "weight":
+		included = included.ByWeight()
+	case "date":
+		included = included.ByDate()
+	case "title":
+		included = included.ByTitle()
+	case "linktitle":
+		included = included.ByLinkTitle()
+	case "default":
+		page.SortByDefault(included)
+	default:
+		included = included.ByParam(strings.TrimPrefix(c.OrderBy, "params."))
+	}
+
+	if c.Reverse {
+		included = included.Reverse()
+	}
+
+	n := len(included)
+	for i, p := range included {
+		pos := getPos(p)
+		if pos == nil {
+			continue
+		}
+
+		if i < n-1 {
+			pos.nextPage = included[i+1]
+		} else if c.Wrap && n > 1 {
+			pos.nextPage = included[0]
+		}
+
+		if i > 0 {
+			pos.prevPage = included[i-1]
+		} else if c.Wrap && n > 1 {
+			pos.prevPage = included[n-1]
+		}
+	}
+}
+
+func isExcludedFromNextPrev(p page.Page, exclude map[string]interface{}) bool {
+	for k, v := range exclude {
+		pv := maps.Params(p.Params()).Get(strings.Split(k, ".")...)
+		if pv != nil && strings.EqualFold(cast.ToString(pv), cast.ToString(v)) {
+			return true
+		}
+	}
+	return false
+}
+
+func getNextPrev(p page.Page) *nextPrev {
+	if np, ok := p.(nextPrevProvider); ok {
+		return np.getNextPrev()
+	}
+	return nil
+}
+
+func getNextPrevInSection(p page.Page) *nextPrev {
+	if np, ok := p.(nextPrevInSectionProvider); ok {
+		return np.getNextPrevInSection()
+	}
+	return nil
+}
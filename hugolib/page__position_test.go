@@ -0,0 +1,131 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestNextPrevInSeries(t *testing.T) {
+	t.Parallel()
+
+	for _, orderBy := range []string{"date", "weight"} {
+		orderBy := orderBy
+		t.Run(orderBy, func(t *testing.T) {
+			b := newTestSitesBuilder(t).WithConfigFile("toml", fmt.Sprintf(`
+baseURL = "https://example.org/"
+[series]
+orderBy = %q
+`, orderBy))
+
+			b.WithContent(
+				"blog/part1.md", "---\ntitle: Part 1\nseries: Go Basics\ndate: 2021-01-01\nweight: 3\n---\n",
+				"blog/part2.md", "---\ntitle: Part 2\nseries: [Go Basics]\ndate: 2021-02-01\nweight: 2\n---\n",
+				"docs/part3.md", "---\ntitle: Part 3\nseries: Go Basics\ndate: 2021-03-01\nweight: 1\n---\n",
+				"blog/other.md", "---\ntitle: Other\nseries: Other Series\n---\n",
+				"blog/standalone.md", "---\ntitle: Standalone\n---\n",
+			)
+
+			b.WithTemplates("_default/single.html", `
+Series: {{ range .SeriesPages }}{{ .Title }}|{{ end }}
+Prev: {{ with .PrevInSeries }}{{ .Title }}{{ end }}
+Next: {{ with .NextInSeries }}{{ .Title }}{{ end }}
+`, "_default/list.html", "{{ .Title }}")
+
+			b.Build(BuildCfg{})
+
+			if orderBy == "date" {
+				b.AssertFileContent("public/blog/part2/index.html", "Series: Part 1|Part 2|Part 3|", "Prev: Part 1", "Next: Part 3")
+				b.AssertFileContent("public/blog/part1/index.html", "Prev: \n", "Next: Part 2")
+			} else {
+				b.AssertFileContent("public/blog/part2/index.html", "Series: Part 3|Part 2|Part 1|", "Prev: Part 3", "Next: Part 1")
+				b.AssertFileContent("public/blog/part1/index.html", "Prev: Part 2", "Next: \n")
+			}
+
+			b.AssertFileContent("public/blog/other/index.html", "Series: Other|", "Prev: \n", "Next: \n")
+			b.AssertFileContent("public/blog/standalone/index.html", "Series: \n", "Prev: \n", "Next: \n")
+		})
+	}
+}
+
+func TestNextPrevIn(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithSimpleConfigFile()
+
+	b.WithContent(
+		"blog/p1.md", "---\ntitle: P1\nfeatured: true\nweight: 30\n---\n",
+		"blog/p2.md", "---\ntitle: P2\nweight: 20\n---\n",
+		"docs/p3.md", "---\ntitle: P3\nfeatured: true\nweight: 10\n---\n",
+		"docs/p4.md", "---\ntitle: P4\nfeatured: true\nweight: 40\n---\n",
+	)
+
+	b.WithTemplates("_default/single.html", `
+{{ $featured := (where site.RegularPages "Params.featured" true).ByTitle }}
+Next: {{ with .NextIn $featured }}{{ .Title }}{{ end }}
+Prev: {{ with .PrevIn $featured }}{{ .Title }}{{ end }}
+NextWeighted: {{ with .NextIn site.RegularPages }}{{ .Title }}{{ end }}
+`, "_default/list.html", "{{ .Title }}")
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/blog/p1/index.html", "Next: \n", "Prev: P3", "NextWeighted: P2")
+	b.AssertFileContent("public/docs/p3/index.html", "Next: P1", "Prev: P4", "NextWeighted: \n")
+	b.AssertFileContent("public/docs/p4/index.html", "Next: P3", "Prev: \n", "NextWeighted: P1")
+	b.AssertFileContent("public/blog/p2/index.html", "Next: \n", "Prev: \n", "NextWeighted: P3")
+}
+
+func TestNextPrevConfigured(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "https://example.org/"
+[nextPrev.docs]
+orderBy = "weight"
+wrap = true
+exclude = { hidden = true }
+[nextPrev."docs/api"]
+orderBy = "title"
+reverse = true
+`)
+
+	b.WithContent(
+		"docs/intro.md", "---\ntitle: Intro\nweight: 1\n---\n",
+		"docs/install.md", "---\ntitle: Install\nweight: 2\n---\n",
+		"docs/hidden.md", "---\ntitle: Hidden\nweight: 3\nhidden: true\n---\n",
+		"docs/guides/_index.md", "---\ntitle: Guides\n---\n",
+		"docs/api/_index.md", "---\ntitle: API\n---\n",
+		"docs/guides/g1.md", "---\ntitle: G1\nweight: 10\n---\n",
+		"docs/guides/g2.md", "---\ntitle: G2\nweight: 11\n---\n",
+		"docs/api/a.md", "---\ntitle: A\n---\n",
+		"docs/api/b.md", "---\ntitle: B\n---\n",
+		"blog/b1.md", "---\ntitle: B1\ndate: 2021-01-01\n---\n",
+		"blog/b2.md", "---\ntitle: B2\ndate: 2021-02-01\n---\n",
+	)
+
+	b.WithTemplates("_default/single.html", `Next: {{ with .Next }}{{ .Title }}{{ end }}|Prev: {{ with .Prev }}{{ .Title }}{{ end }}|NextInSection: {{ with .NextInSection }}{{ .Title }}{{ end }}|PrevInSection: {{ with .PrevInSection }}{{ .Title }}{{ end }}|`,
+		"_default/list.html", "{{ .Title }}")
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/docs/intro/index.html", "Next: Install|Prev: G2|NextInSection: Install|PrevInSection: Install|")
+	b.AssertFileContent("public/docs/install/index.html", "Next: G1|Prev: Intro|NextInSection: Intro|PrevInSection: Intro|")
+	b.AssertFileContent("public/docs/hidden/index.html", "Next: |Prev: |NextInSection: |PrevInSection: |")
+	b.AssertFileContent("public/docs/guides/g1/index.html", "Next: G2|Prev: Install|NextInSection: G2|PrevInSection: G2|")
+	b.AssertFileContent("public/docs/guides/g2/index.html", "Next: Intro|Prev: G1|NextInSection: G1|PrevInSection: G1|")
+	b.AssertFileContent("public/docs/api/a/index.html", "Next: |Prev: B|NextInSection: |PrevInSection: B|")
+	b.AssertFileContent("public/docs/api/b/index.html", "Next: A|Prev: |NextInSection: A|PrevInSection: |")
+	b.AssertFileContent("public/blog/b1/index.html", "NextInSection: B2|PrevInSection: |")
+}
@@ -0,0 +1,160 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"html/template"
+	"strings"
+	"sync"
+
+	"github.com/gohugoio/hugo/helpers"
+	"github.com/gohugoio/hugo/resources/page"
+	"github.com/gohugoio/hugo/resources/resource"
+	"github.com/gohugoio/hugo/resources/resource_factories/create"
+	"github.com/gohugoio/hugo/tpl"
+	"github.com/pkg/errors"
+)
+
+// blocksCache holds the rendered blocks of the types with caching enabled.
+// It is cleared when a build starts.
+type blocksCache struct {
+	sync.RWMutex
+	m map[blocksCacheKey]string
+}
+
+type blocksCacheKey struct {
+	templ        string
+	outputFormat string
+	params       string
+}
+
+func newBlocksCache() *blocksCache {
+	return &blocksCache{m: make(map[blocksCacheKey]string)}
+}
+
+func (c *blocksCache) clear() {
+	c.Lock()
+	defer c.Unlock()
+	c.m = make(map[blocksCacheKey]string)
+}
+
+func (c *blocksCache) getOrCreate(key blocksCacheKey, create func() (string, error)) (string, error) {
+	c.RLock()
+	s, found := c.m[key]
+	c.RUnlock()
+	if found {
+		return s, nil
+	}
+
+	s, err := create()
+	if err != nil {
+		return "", err
+	}
+
+	c.Lock()
+	c.m[key] = s
+	c.Unlock()
+
+	return s, nil
+}
+
+// Blocks returns the structured content blocks set in front matter, see
+// page.BlocksProvider.
+func (p *pageState) Blocks() page.Blocks {
+	return p.m.blocks
+}
+
+// RenderBlocks renders the blocks of p, optionally only those of the given
+// types, each with the "blocks/<type>" layout, e.g.
+// layouts/_default/blocks/faq.html, with the page.Block as its context.
+func (p *pageState) RenderBlocks(types ...string) (template.HTML, error) {
+	var b strings.Builder
+
+	for _, block := range p.m.blocks {
+		if len(types) > 0 && !blockTypeIn(block.Type, types) {
+			continue
+		}
+
+		layout := "blocks/" + block.Type
+		templ, found, err := p.resolveTemplate(layout)
+		if err != nil {
+			return "", p.wrapError(err)
+		}
+		if !found {
+			p.s.Log.Warnf("%s: no %q layout found for blocks[%d]", p.pathOrTitle(), layout, block.Index)
+			continue
+		}
+
+		p.addDependency(templ.(tpl.Info))
+		res, err := p.renderBlock(templ, block)
+		if err != nil {
+			return "", p.wrapError(errors.Wrapf(err, "failed to execute template %q", layout))
+		}
+		b.WriteString(res)
+	}
+
+	return template.HTML(b.String()), nil
+}
+
+func (p *pageState) renderBlock(templ tpl.Template, block page.Block) (string, error) {
+	execute := func() (string, error) {
+		return executeToString(p.s.Tmpl(), templ, block)
+	}
+
+	if !p.s.siteCfg.blockSchemas[block.Type].Cache {
+		return execute()
+	}
+
+	key := blocksCacheKey{
+		templ:        templ.Name(),
+		outputFormat: p.outputFormat().Name,
+		params:       helpers.HashString(block.Params),
+	}
+
+	return p.s.blocksCache.getOrCreate(key, execute)
+}
+
+// BlockAssets returns the assets of the block types used in the page, see
+// page.BlocksProvider.
+func (p *pageState) BlockAssets() (resource.Resources, error) {
+	var (
+		assets resource.Resources
+		seen   = make(map[string]bool)
+		client = create.New(p.s.ResourceSpec)
+	)
+
+	for _, block := range p.m.blocks {
+		if seen[block.Type] {
+			continue
+		}
+		seen[block.Type] = true
+
+		res, err := client.Match("blocks/" + block.Type + ".*")
+		if err != nil {
+			return nil, p.wrapError(errors.Wrapf(err, "failed to get the assets of block type %q", block.Type))
+		}
+		assets = append(assets, res...)
+	}
+
+	return assets, nil
+}
+
+func blockTypeIn(typ string, types []string) bool {
+	for _, t := range types {
+		if strings.EqualFold(typ, t) {
+			return true
+		}
+	}
+	return false
+}
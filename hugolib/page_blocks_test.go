@@ -0,0 +1,167 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestPageBlocks(t *testing.T) {
+	t.Parallel()
+
+	config := `
+baseURL = "https://example.org"
+
+[blocks.faq]
+required = ["question", "answer"]
+[blocks.faq.params]
+question = "string"
+[blocks.steps]
+[blocks.steps.params]
+count = "int"
+`
+
+	b := newTestSitesBuilder(t).WithConfigFile("toml", config)
+
+	b.WithContent("p1.md", `---
+title: P1
+blocks:
+- type: faq
+  question: What is Hugo?
+  answer: A static site generator.
+- type: steps
+  count: "3"
+- type: FAQ
+  question: Is it fast?
+  answer: Yes.
+- type: faq
+  question: Missing the answer
+- type: unknown
+---
+`)
+
+	b.WithTemplates(
+		"_default/single.html", `
+All: {{ .RenderBlocks }}|
+FAQ: {{ .RenderBlocks "faq" }}|
+Len: {{ len .Blocks }}|{{ len (.Blocks.ByType "faq") }}|
+Param: {{ (index .Blocks 1).Param "count" }}|{{ printf "%T" (index .Blocks 1).Params.count }}|
+`,
+		"_default/list.html", `List`,
+		"_default/blocks/faq.html", `<dt>{{ .Params.question }}</dt><dd>{{ .Params.answer }}</dd>{{ .Page.Title }}:{{ .Index }}`,
+		"_default/blocks/steps.html", `<ol data-count="{{ .Params.count }}"></ol>`,
+	)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/p1/index.html",
+		`All: <dt>What is Hugo?</dt><dd>A static site generator.</dd>P1:0<ol data-count="3"></ol><dt>Is it fast?</dt><dd>Yes.</dd>P1:2<dt>Missing the answer</dt><dd></dd>P1:3|`,
+		"FAQ: <dt>What is Hugo?</dt><dd>A static site generator.</dd>P1:0<dt>Is it fast?</dt><dd>Yes.</dd>P1:2<dt>Missing the answer</dt><dd></dd>P1:3|",
+		"Len: 5|3|",
+		"Param: 3|int|",
+	)
+
+	// The string count, the missing answer, the unknown type and its layout.
+	b.Assert(b.H.Log.LogCounters().WarnCounter.Count(), qt.Equals, uint64(4))
+}
+
+func TestPageBlocksInvalid(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t)
+
+	b.WithContent("p1.md", `---
+title: P1
+blocks:
+- question: No type.
+---
+`)
+
+	b.WithTemplates(
+		"_default/single.html", `Len: {{ len .Blocks }}|{{ .RenderBlocks }}|`,
+		"_default/list.html", `List`,
+	)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/p1/index.html", "Len: 0||")
+	b.Assert(b.H.Log.LogCounters().WarnCounter.Count(), qt.Equals, uint64(1))
+}
+
+func TestPageBlocksCacheAndAssets(t *testing.T) {
+	t.Parallel()
+
+	config := `
+baseURL = "https://example.org"
+
+[blocks.hero]
+cache = true
+[blocks.faq]
+`
+
+	b := newTestSitesBuilder(t).WithConfigFile("toml", config)
+
+	for _, name := range []string{"p1", "p2"} {
+		b.WithContent(name+".md", `---
+title: `+strings.ToUpper(name)+`
+blocks:
+- type: hero
+  heading: Welcome
+- type: faq
+  question: Why?
+- type: hero
+  heading: Welcome
+---
+`)
+	}
+
+	b.WithTemplates(
+		"_default/single.html", `
+Blocks: {{ .RenderBlocks }}|
+Assets: {{ range .BlockAssets }}{{ .RelPermalink }}|{{ end }}
+`,
+		"_default/list.html", `List`,
+		"_default/blocks/hero.html", `<h1>{{ .Params.heading }}</h1>{{ .Page.Title }}`,
+		"_default/blocks/faq.html", `<dt>{{ .Params.question }}</dt>{{ .Page.Title }}`,
+	)
+
+	b.WithSourceFile(
+		"assets/blocks/hero.css", "h1 {}",
+		"assets/blocks/hero.js", "let a;",
+		"assets/blocks/faq.css", "dt {}",
+	)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/p1/index.html",
+		"<dt>Why?</dt>P1",
+		"Assets: /blocks/hero.css|/blocks/hero.js|/blocks/faq.css|",
+	)
+	b.AssertFileContent("public/p2/index.html",
+		"<dt>Why?</dt>P2",
+	)
+
+	// The hero blocks are rendered once and shared by both pages.
+	hero := regexp.MustCompile(`<h1>Welcome</h1>(P\d)`)
+	m1 := hero.FindAllStringSubmatch(b.FileContent("public/p1/index.html"), -1)
+	m2 := hero.FindAllStringSubmatch(b.FileContent("public/p2/index.html"), -1)
+	b.Assert(m1, qt.HasLen, 2)
+	b.Assert(m2, qt.HasLen, 2)
+	b.Assert(m1[0][1], qt.Equals, m2[0][1])
+	b.Assert(m1[1][1], qt.Equals, m2[0][1])
+}
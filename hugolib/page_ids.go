@@ -0,0 +1,216 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/gohugoio/hugo/config"
+	"github.com/gohugoio/hugo/config/pageids"
+	"github.com/gohugoio/hugo/helpers"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// pageIDs keeps track of the stable page IDs and the paths the pages with an
+// ID were published to, stored in a sidecar file between builds.
+type pageIDs struct {
+	cfg      pageids.Config
+	fs       afero.Fs
+	filename string
+
+	mu      sync.Mutex
+	langs   map[string]map[string]*pageIDEntry // lang => ID => entry
+	changed bool
+
+	// The pages with a generated ID not yet in their front matter.
+	generated []*pageState
+}
+
+// pageIDEntry is the sidecar file entry for a page ID.
+type pageIDEntry struct {
+	// The content file. Set for IDs generated before they were written to
+	// front matter, to keep them when the ID is written.
+	File string `json:"file,omitempty"`
+
+	// The target paths, keyed by output format name. The last path is the
+	// current one, the others are the ones to redirect from.
+	Paths map[string][]string `json:"paths,omitempty"`
+}
+
+func newPageIDs(cfg config.Provider, fs afero.Fs) (*pageIDs, error) {
+	c, err := pageids.DecodeConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := &pageIDs{
+		cfg:      c,
+		fs:       fs,
+		filename: filepath.Join(cfg.GetString("workingDir"), c.Filename),
+		langs:    make(map[string]map[string]*pageIDEntry),
+	}
+
+	if !c.Enable {
+		return ids, nil
+	}
+
+	b, err := afero.ReadFile(fs, ids.filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ids, nil
+		}
+		return nil, err
+	}
+
+	if err := json.Unmarshal(b, &ids.langs); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal %q", ids.filename)
+	}
+
+	return ids, nil
+}
+
+// generateID returns a new ID for p, to be written to its front matter with
+// writeGenerated so it follows the content file if it's moved. It returns an
+// empty string if ID generation is not enabled.
+func (ids *pageIDs) generateID(p *pageState) string {
+	if !ids.cfg.Generate {
+		return ""
+	}
+
+	lang := p.s.Lang()
+	filename := filepath.ToSlash(p.File().Path())
+
+	ids.mu.Lock()
+	defer ids.mu.Unlock()
+
+	ids.generated = append(ids.generated, p)
+
+	entries := ids.entries(lang)
+	for id, entry := range entries {
+		if entry.File == filename {
+			// Generated by an earlier version.
+			return id
+		}
+	}
+
+	// Use a hash of the filename, so a fresh build of the same content
+	// generates the same IDs.
+	return helpers.MD5String(lang + "/" + filename)[:16]
+}
+
+// writeGenerated writes the generated IDs to the front matter of the pages.
+func (ids *pageIDs) writeGenerated(h *HugoSites) error {
+	ids.mu.Lock()
+	generated := ids.generated
+	ids.generated = nil
+	ids.mu.Unlock()
+
+	for _, p := range generated {
+		id := p.ID()
+		if err := h.writeFrontMatter(p, func(fm map[string]interface{}) {
+			fm["id"] = id
+		}); err != nil {
+			return errors.Wrapf(err, "failed to write ID to %q", p.File().Filename())
+		}
+
+		ids.mu.Lock()
+		if entry, found := ids.entries(p.s.Lang())[id]; found && entry.File != "" {
+			entry.File = ""
+			ids.changed = true
+		}
+		ids.mu.Unlock()
+	}
+
+	return nil
+}
+
+// update records targetPath as the current path of the page with the given ID
+// in the output format f. It returns the paths the page was previously
+// published to.
+func (ids *pageIDs) update(lang, id, f, targetPath string) []string {
+	f = strings.ToLower(f)
+	targetPath = filepath.ToSlash(targetPath)
+
+	ids.mu.Lock()
+	defer ids.mu.Unlock()
+
+	entries := ids.entries(lang)
+	entry, found := entries[id]
+	if !found {
+		entry = &pageIDEntry{}
+		entries[id] = entry
+	}
+	if entry.Paths == nil {
+		entry.Paths = make(map[string][]string)
+	}
+
+	paths := entry.Paths[f]
+	if len(paths) > 0 && paths[len(paths)-1] == targetPath {
+		return paths[:len(paths)-1]
+	}
+
+	// The page may have been moved back to an earlier path.
+	var oldPaths []string
+	for _, p := range paths {
+		if p != targetPath {
+			oldPaths = append(oldPaths, p)
+		}
+	}
+
+	entry.Paths[f] = append(oldPaths, targetPath)
+	ids.changed = true
+
+	return oldPaths
+}
+
+func (ids *pageIDs) entries(lang string) map[string]*pageIDEntry {
+	entries, found := ids.langs[lang]
+	if !found {
+		entries = make(map[string]*pageIDEntry)
+		ids.langs[lang] = entries
+	}
+	return entries
+}
+
+// write writes the sidecar file if anything has changed.
+func (ids *pageIDs) write() error {
+	if !ids.cfg.Enable {
+		return nil
+	}
+
+	ids.mu.Lock()
+	defer ids.mu.Unlock()
+
+	if !ids.changed {
+		return nil
+	}
+
+	b, err := json.MarshalIndent(ids.langs, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := afero.WriteFile(ids.fs, ids.filename, b, 0666); err != nil {
+		return errors.Wrapf(err, "failed to write %q", ids.filename)
+	}
+
+	ids.changed = false
+
+	return nil
+}
@@ -0,0 +1,152 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gohugoio/hugo/helpers"
+	"github.com/spf13/afero"
+)
+
+func TestPageIDs(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "https://example.org/"
+[pageIDs]
+generate = true
+`)
+
+	b.WithSourceFile("pageids.json", `{
+  "en": {
+    "abc": {
+      "paths": {
+        "html": ["/old/p1/index.html"]
+      }
+    }
+  }
+}`)
+
+	b.WithContent("blog/p1.md", `---
+title: "P1"
+id: "abc"
+---
+`, "blog/p2.md", `---
+title: "P2"
+---
+`)
+
+	b.WithTemplates("_default/single.html", "ID: {{ .ID }}|", "_default/list.html", "{{ .Title }}")
+
+	b.Build(BuildCfg{})
+
+	p2ID := helpers.MD5String("en/blog/p2.md")[:16]
+
+	b.AssertFileContent("public/blog/p1/index.html", "ID: abc|")
+	b.AssertFileContent("public/blog/p2/index.html", "ID: "+p2ID+"|")
+	b.AssertFileContent("public/old/p1/index.html", `<meta http-equiv="refresh" content="0; url=https://example.org/blog/p1/" />`)
+
+	sidecar, err := afero.ReadFile(b.Fs.Source, "pageids.json")
+	b.Assert(err, qt.IsNil)
+	b.Assert(string(sidecar), qt.Contains, `"html": [
+          "/old/p1/index.html",
+          "/blog/p1/index.html"
+        ]`)
+	b.Assert(string(sidecar), qt.Not(qt.Contains), `"file"`)
+
+	// The generated ID is written to front matter.
+	b.AssertFileContent("content/blog/p2.md", `id: `+p2ID, `title: P2`)
+}
+
+func TestPageIDsGeneratedMoved(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "https://example.org/"
+[pageIDs]
+generate = true
+`)
+
+	// An ID generated by an earlier version, stored in the sidecar only.
+	b.WithSourceFile("pageids.json", `{
+  "en": {
+    "legacy": {
+      "file": "blog/p1.md",
+      "paths": {
+        "html": ["/blog/p1/index.html"]
+      }
+    }
+  }
+}`)
+
+	b.WithContent("blog/p1.md", `---
+title: "P1"
+---
+Content.
+`, "blog/p2.md", `---
+title: "P2"
+---
+Content.
+`)
+
+	b.WithTemplates("_default/single.html", "ID: {{ .ID }}|", "_default/list.html", "{{ .Title }}")
+
+	b.Running().Build(BuildCfg{})
+
+	p2ID := helpers.MD5String("en/blog/p2.md")[:16]
+
+	b.AssertFileContent("public/blog/p1/index.html", "ID: legacy|")
+	b.AssertFileContent("content/blog/p1.md", `id: legacy`)
+	b.AssertFileContent("content/blog/p2.md", `id: `+p2ID)
+
+	// Move the files to a new section; the IDs move with them.
+	for _, name := range []string{"p1", "p2"} {
+		content := b.FileContent("content/blog/" + name + ".md")
+		b.RemoveFiles("content/blog/" + name + ".md")
+		b.EditFiles("content/articles/"+name+".md", content)
+	}
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/articles/p1/index.html", "ID: legacy|")
+	b.AssertFileContent("public/articles/p2/index.html", "ID: "+p2ID+"|")
+	b.AssertFileContent("public/blog/p1/index.html", `<meta http-equiv="refresh" content="0; url=https://example.org/articles/p1/" />`)
+	b.AssertFileContent("public/blog/p2/index.html", `<meta http-equiv="refresh" content="0; url=https://example.org/articles/p2/" />`)
+}
+
+func TestPageIDsDuplicate(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "https://example.org/"
+[pageIDs]
+enable = true
+`)
+
+	b.WithContent("p1.md", `---
+title: "P1"
+id: "abc"
+---
+`, "p2.md", `---
+title: "P2"
+id: "abc"
+---
+`)
+
+	err := b.BuildE(BuildCfg{})
+	b.Assert(err, qt.Not(qt.IsNil))
+	b.Assert(err.Error(), qt.Contains, `page ID "abc" used by both`)
+}
@@ -32,6 +32,7 @@ const (
 	kindRSS       = "RSS"
 	kindSitemap   = "sitemap"
 	kindRobotsTXT = "robotsTXT"
+	kindLLMsTXT   = "llmsTXT"
 	kind404       = "404"
 
 	pageResourceType = "page"
@@ -41,6 +42,7 @@ var kindMap = map[string]string{
 	strings.ToLower(kindRSS):       kindRSS,
 	strings.ToLower(kindSitemap):   kindSitemap,
 	strings.ToLower(kindRobotsTXT): kindRobotsTXT,
+	strings.ToLower(kindLLMsTXT):   kindLLMsTXT,
 	strings.ToLower(kind404):       kind404,
 }
 
@@ -0,0 +1,366 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"bytes"
+	"encoding/json"
+	"hash/fnv"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gohugoio/hugo/config/moves"
+	"github.com/gohugoio/hugo/helpers"
+	"github.com/gohugoio/hugo/parser"
+	"github.com/gohugoio/hugo/parser/metadecoders"
+	"github.com/gohugoio/hugo/parser/pageparser"
+	"github.com/gohugoio/hugo/resources/page"
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+)
+
+const (
+	movesSnapshotID = "pages.json"
+
+	// The number of words in the shingles compared.
+	movesShingleSize = 3
+
+	// The number of hashes in a page's MinHash signature.
+	movesNumHashes = 32
+)
+
+// PageMove describes a page that looks moved from the path it was published
+// to in the previous build.
+type PageMove struct {
+	// The page in its new location.
+	Page page.Page
+
+	// The old path, usable as an alias.
+	OldPath string
+
+	// The estimated content similarity, from 0 to 1. It is 1 for pages
+	// with the same ID.
+	Similarity float64
+}
+
+// pageFingerprint is what we store about a page to detect if it's moved.
+type pageFingerprint struct {
+	Path    string   `json:"path"`
+	ID      string   `json:"id,omitempty"`
+	MinHash []uint64 `json:"minhash,omitempty"`
+
+	p page.Page
+}
+
+// DetectMoves compares the pages with the ones in the previous build and
+// returns the pages that look moved.
+func (h *HugoSites) DetectMoves() ([]PageMove, error) {
+	cfg, err := moves.DecodeConfig(h.Cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	old, err := h.loadMovesSnapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	return detectMoves(old, h.pageFingerprints(), cfg.Threshold), nil
+}
+
+// WriteMoveAliases adds the old paths of the moved pages to the aliases in
+// their front matter.
+func (h *HugoSites) WriteMoveAliases(moves []PageMove) error {
+	for _, m := range moves {
+		if err := h.writeAlias(m.Page, m.OldPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// handleMoves logs the pages that look moved since the previous build and
+// stores the pages for the next.
+func (h *HugoSites) handleMoves() error {
+	cfg, err := moves.DecodeConfig(h.Cfg)
+	if err != nil || !cfg.Detect {
+		return err
+	}
+
+	old, err := h.loadMovesSnapshot()
+	if err != nil {
+		return err
+	}
+
+	current := h.pageFingerprints()
+
+	if old != nil {
+		for _, m := range detectMoves(old, current, cfg.Threshold) {
+			filename := m.Page.File().Filename()
+			if !cfg.WriteAliases {
+				h.Log.Warnf("%q looks moved from %q (similarity %.2f). Add %q to its aliases to keep the old URL working.", filename, m.OldPath, m.Similarity, m.OldPath)
+				continue
+			}
+			if err := h.writeAlias(m.Page, m.OldPath); err != nil {
+				return err
+			}
+			h.Log.Printf("Added alias %q to %q, which looks moved (similarity %.2f).", m.OldPath, filename, m.Similarity)
+		}
+	}
+
+	return h.saveMovesSnapshot(current)
+}
+
+func (h *HugoSites) pageFingerprints() []pageFingerprint {
+	var fingerprints []pageFingerprint
+
+	for _, s := range h.Sites {
+		s.pageMap.pageTrees.WalkRenderable(func(ss string, n *contentNode) bool {
+			p := n.p
+			if p.File().IsZero() || len(p.m.outputFormats()) == 0 {
+				return false
+			}
+
+			d := p.targetPathDescriptor
+			d.Type = p.m.outputFormats()[0]
+			targetPath := filepath.ToSlash(page.CreateTargetPaths(d).TargetFilename)
+
+			fingerprints = append(fingerprints, pageFingerprint{
+				Path:    strings.TrimSuffix(targetPath, "index.html"),
+				ID:      p.ID(),
+				MinHash: minHash(p.Plain()),
+				p:       p,
+			})
+
+			return false
+		})
+	}
+
+	return fingerprints
+}
+
+// detectMoves matches the pages in old no longer found with the new pages in
+// current, by ID or by content similarity.
+func detectMoves(old, current []pageFingerprint, threshold float64) []PageMove {
+	oldPaths := make(map[string]bool)
+	for _, fp := range old {
+		oldPaths[fp.Path] = true
+	}
+
+	currentPaths := make(map[string]bool)
+	var added []pageFingerprint
+	for _, fp := range current {
+		currentPaths[fp.Path] = true
+		if !oldPaths[fp.Path] {
+			added = append(added, fp)
+		}
+	}
+
+	var moves []PageMove
+	matched := make(map[int]bool)
+
+	for _, fp := range old {
+		if currentPaths[fp.Path] {
+			continue
+		}
+
+		best, bestSimilarity := -1, 0.0
+		for i, candidate := range added {
+			if matched[i] {
+				continue
+			}
+			if fp.ID != "" && fp.ID == candidate.ID {
+				best, bestSimilarity = i, 1
+				break
+			}
+			if similarity := minHashSimilarity(fp.MinHash, candidate.MinHash); similarity >= threshold && similarity > bestSimilarity {
+				best, bestSimilarity = i, similarity
+			}
+		}
+
+		if best == -1 {
+			continue
+		}
+
+		matched[best] = true
+		p := added[best].p
+
+		if hasAlias(p, fp.Path) {
+			continue
+		}
+
+		moves = append(moves, PageMove{Page: p, OldPath: fp.Path, Similarity: bestSimilarity})
+	}
+
+	sort.SliceStable(moves, func(i, j int) bool {
+		return moves[i].OldPath < moves[j].OldPath
+	})
+
+	return moves
+}
+
+func hasAlias(p page.Page, alias string) bool {
+	for _, a := range p.Aliases() {
+		if strings.TrimSuffix(a, "/") == strings.TrimSuffix(alias, "/") {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *HugoSites) loadMovesSnapshot() ([]pageFingerprint, error) {
+	_, b, err := h.FileCaches.MovesCache().GetBytes(movesSnapshotID)
+	if err != nil || b == nil {
+		return nil, err
+	}
+
+	var fingerprints []pageFingerprint
+	if err := json.Unmarshal(b, &fingerprints); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal pages snapshot")
+	}
+
+	return fingerprints, nil
+}
+
+func (h *HugoSites) saveMovesSnapshot(fingerprints []pageFingerprint) error {
+	b, err := json.Marshal(fingerprints)
+	if err != nil {
+		return err
+	}
+
+	_, w, err := h.FileCaches.MovesCache().WriteCloser(movesSnapshotID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write(b); err != nil {
+		w.Close()
+		return err
+	}
+
+	return w.Close()
+}
+
+// writeAlias adds alias to the aliases in the front matter of p.
+func (h *HugoSites) writeAlias(p page.Page, alias string) error {
+	return h.writeFrontMatter(p, func(fm map[string]interface{}) {
+		key := "aliases"
+		for k := range fm {
+			if strings.EqualFold(k, key) {
+				key = k
+				break
+			}
+		}
+
+		aliases := cast.ToStringSlice(fm[key])
+		fm[key] = append(aliases, alias)
+	})
+}
+
+// writeFrontMatter applies update to the front matter of the content file of
+// p and writes the file.
+func (h *HugoSites) writeFrontMatter(p page.Page, update func(fm map[string]interface{})) error {
+	filename := p.File().Filename()
+
+	f, err := p.File().FileInfo().Meta().Open()
+	if err != nil {
+		return err
+	}
+	source, err := ioutil.ReadAll(f)
+	f.Close()
+	if err != nil {
+		return err
+	}
+
+	pf, err := pageparser.ParseFrontMatterAndContent(bytes.NewReader(source))
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse %q", filename)
+	}
+
+	if pf.FrontMatterFormat == "" {
+		// No front matter. Create it using the default format.
+		pf.FrontMatter = make(map[string]interface{})
+		pf.FrontMatterFormat = metadecoders.TOML
+		pf.Content = source
+	}
+
+	update(pf.FrontMatter)
+
+	var b bytes.Buffer
+	if err := parser.InterfaceToFrontMatter(pf.FrontMatter, pf.FrontMatterFormat, &b); err != nil {
+		return errors.Wrapf(err, "failed to write front matter for %q", filename)
+	}
+	b.Write(pf.Content)
+
+	return helpers.WriteToDisk(filename, &b, h.Fs.Source)
+}
+
+// minHash returns the MinHash signature for the word shingles in s, or nil
+// if there are too few words.
+func minHash(s string) []uint64 {
+	words := strings.Fields(strings.ToLower(s))
+	if len(words) < movesShingleSize {
+		return nil
+	}
+
+	sig := make([]uint64, movesNumHashes)
+	for i := range sig {
+		sig[i] = math.MaxUint64
+	}
+
+	h := fnv.New64a()
+	for i := 0; i+movesShingleSize <= len(words); i++ {
+		h.Reset()
+		for _, w := range words[i : i+movesShingleSize] {
+			h.Write([]byte(w))
+			h.Write([]byte{' '})
+		}
+		x := h.Sum64()
+		for j := range sig {
+			if v := mix64(x ^ mix64(uint64(j+1))); v < sig[j] {
+				sig[j] = v
+			}
+		}
+	}
+
+	return sig
+}
+
+// minHashSimilarity estimates the Jaccard similarity of the shingle sets
+// with the signatures a and b.
+func minHashSimilarity(a, b []uint64) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	var equal int
+	for i := range a {
+		if a[i] == b[i] {
+			equal++
+		}
+	}
+	return float64(equal) / float64(len(a))
+}
+
+// mix64 is the SplitMix64 finalizer.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
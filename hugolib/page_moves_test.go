@@ -0,0 +1,82 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/spf13/afero"
+)
+
+func TestPageMoves(t *testing.T) {
+	t.Parallel()
+
+	const body = `
+The quick brown fox jumps over the lazy dog. It then runs into the forest,
+where it meets a bear who is looking for honey in the old oak tree.
+`
+
+	for _, test := range []struct {
+		writeAliases bool
+		frontMatter  string
+		expected     string
+	}{
+		{false, "---\ntitle: P1\n---\n", ""},
+		{true, "---\ntitle: P1\n---\n", "aliases:\n- /blog/p1/\n"},
+		{true, "", "+++\naliases = [\"/blog/p1/\"]\n\n+++\n" + body},
+	} {
+		test := test
+		writeAliases := test.writeAliases
+		t.Run("", func(t *testing.T) {
+			b := newTestSitesBuilder(t).Running().WithConfigFile("toml", fmt.Sprintf(`
+baseURL = "https://example.org/"
+[moves]
+detect = true
+writeAliases = %t
+`, writeAliases))
+
+			b.WithContent("blog/p1.md", test.frontMatter+body, "blog/p2.md", "---\ntitle: P2\n---\nSomething else entirely.")
+			b.WithTemplates("_default/single.html", "{{ .Title }}|{{ .Aliases }}", "_default/list.html", "{{ .Title }}")
+
+			b.Build(BuildCfg{})
+			b.Assert(b.H.Log.LogCounters().WarnCounter.Count(), qt.Equals, uint64(0))
+
+			b.RemoveFiles("content/blog/p1.md")
+			b.EditFiles("content/articles/p1.md", test.frontMatter+body+"\nA new sentence.")
+			b.Build(BuildCfg{})
+
+			if !writeAliases {
+				b.Assert(b.H.Log.LogCounters().WarnCounter.Count(), qt.Equals, uint64(1))
+				return
+			}
+
+			content, err := afero.ReadFile(b.Fs.Source, filepath.Join("content", "articles", "p1.md"))
+			b.Assert(err, qt.IsNil)
+			b.Assert(string(content), qt.Contains, test.expected)
+		})
+	}
+}
+
+func TestMinHashSimilarity(t *testing.T) {
+	c := qt.New(t)
+
+	a := minHash("one two three four five six seven eight nine ten")
+
+	c.Assert(minHashSimilarity(a, a), qt.Equals, 1.0)
+	c.Assert(minHashSimilarity(a, minHash("a completely different text about something else")), qt.Equals, 0.0)
+	c.Assert(minHashSimilarity(a, minHash("one two")), qt.Equals, 0.0)
+}
@@ -0,0 +1,299 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"strings"
+
+	"github.com/gohugoio/hugo/common/types"
+	"github.com/gohugoio/hugo/config/projections"
+	"github.com/gohugoio/hugo/resources/page"
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+)
+
+var defaultProjectionFields = []string{"kind", "type", "section", "title", "date", "lastmod", "permalink", "summary", "params"}
+
+// Project creates the JSON document for p in the output format currently
+// being rendered, using the projections site config for that output format.
+// It returns nil if no projection is configured.
+// It's used by the built-in template for JSON output formats.
+func (s *SiteInfo) Project(p page.Page) (map[string]interface{}, error) {
+	ps, err := unwrapPage(p)
+	if err != nil {
+		return nil, err
+	}
+
+	pps, ok := ps.(*pageState)
+	if !ok || pps.pageOutput == nil {
+		return nil, nil
+	}
+
+	proj, found := s.s.siteCfg.projections[strings.ToLower(pps.outputFormat().Name)]
+	if !found {
+		return nil, nil
+	}
+
+	projector := &pageProjector{
+		proj:       proj,
+		exclude:    make(map[string]bool),
+		references: make(map[string]bool),
+	}
+	for _, f := range proj.Exclude {
+		projector.exclude[strings.ToLower(f)] = true
+	}
+	for _, f := range proj.References {
+		projector.references[strings.ToLower(strings.TrimPrefix(f, "params."))] = true
+	}
+
+	fields := proj.Include
+	if len(fields) == 0 {
+		fields = defaultProjectionFields
+	}
+
+	return projector.project(p, fields, true)
+}
+
+type pageProjector struct {
+	proj       projections.Config
+	exclude    map[string]bool
+	references map[string]bool // Param names.
+}
+
+func (pp *pageProjector) project(p page.Page, fields []string, resolve bool) (map[string]interface{}, error) {
+	doc := make(map[string]interface{})
+
+	for _, field := range fields {
+		name := strings.ToLower(field)
+		if resolve && pp.exclude[name] {
+			continue
+		}
+
+		var (
+			v   interface{}
+			err error
+		)
+
+		switch {
+		case name == "params":
+			v, err = pp.projectParams(p, resolve)
+		case strings.HasPrefix(name, "params."):
+			key := strings.TrimPrefix(name, "params.")
+			v, err = pp.projectParam(p, key, resolve)
+			if err == nil && v != nil {
+				params, _ := doc["params"].(map[string]interface{})
+				if params == nil {
+					params = make(map[string]interface{})
+					doc["params"] = params
+				}
+				params[key] = v
+			}
+			v = nil
+		case isPageReferenceField(name):
+			v, err = pp.projectPageReferenceField(p, name, resolve)
+		default:
+			v, err = pageFieldValue(p, name)
+		}
+
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get field %q for %q", field, p.Path())
+		}
+
+		if v != nil {
+			doc[field] = v
+		}
+	}
+
+	return doc, nil
+}
+
+func (pp *pageProjector) projectParams(p page.Page, resolve bool) (interface{}, error) {
+	params := make(map[string]interface{})
+	for k := range p.Params() {
+		if resolve && pp.exclude["params."+k] {
+			continue
+		}
+		v, err := pp.projectParam(p, k, resolve)
+		if err != nil {
+			return nil, err
+		}
+		if v != nil {
+			params[k] = v
+		}
+	}
+	return params, nil
+}
+
+func (pp *pageProjector) projectParam(p page.Page, key string, resolve bool) (interface{}, error) {
+	v, err := p.Param(key)
+	if err != nil || v == nil {
+		return nil, err
+	}
+
+	if !resolve || !pp.references[key] {
+		return v, nil
+	}
+
+	switch v.(type) {
+	case []interface{}, []string:
+		var docs []interface{}
+		for _, ref := range cast.ToStringSlice(v) {
+			doc, err := pp.projectReference(p, ref)
+			if err != nil {
+				return nil, err
+			}
+			if doc != nil {
+				docs = append(docs, doc)
+			}
+		}
+		return docs, nil
+	}
+
+	return pp.projectReference(p, v)
+}
+
+// projectReference resolves ref, a page or a page reference, relative to p.
+// It returns nil if the page is not found.
+func (pp *pageProjector) projectReference(p page.Page, ref interface{}) (interface{}, error) {
+	target, ok := ref.(page.Page)
+	if !ok {
+		var err error
+		target, err = p.GetPage(cast.ToString(ref))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if types.IsNil(target) || target == page.NilPage {
+		return nil, nil
+	}
+
+	return pp.project(target, pp.proj.ReferenceFields, false)
+}
+
+func isPageReferenceField(name string) bool {
+	switch name {
+	case "parent", "prev", "next", "previnsection", "nextinsection", "previnseries", "nextinseries", "translations":
+		return true
+	}
+	return false
+}
+
+// projectPageReferenceField resolves the page fields holding other pages.
+// If resolve is false, the permalinks of the pages are used.
+func (pp *pageProjector) projectPageReferenceField(p page.Page, name string, resolve bool) (interface{}, error) {
+	ref := func(target page.Page) (interface{}, error) {
+		if types.IsNil(target) {
+			return nil, nil
+		}
+		if !resolve {
+			return target.Permalink(), nil
+		}
+		return pp.projectReference(p, target)
+	}
+
+	switch name {
+	case "parent":
+		return ref(p.Parent())
+	case "prev":
+		return ref(p.Prev())
+	case "next":
+		return ref(p.Next())
+	case "previnsection":
+		return ref(p.PrevInSection())
+	case "nextinsection":
+		return ref(p.NextInSection())
+	case "previnseries":
+		return ref(p.PrevInSeries())
+	case "nextinseries":
+		return ref(p.NextInSeries())
+	case "translations":
+		var docs []interface{}
+		for _, t := range p.Translations() {
+			doc, err := ref(t)
+			if err != nil {
+				return nil, err
+			}
+			docs = append(docs, doc)
+		}
+		return docs, nil
+	}
+
+	return nil, nil
+}
+
+// pageFieldValue returns the value of the named page field, e.g. title, for
+// use in a JSON document. Any name not known as a page field is looked up in
+// the page params.
+func pageFieldValue(p page.Page, name string) (interface{}, error) {
+	switch name {
+	case "kind":
+		return p.Kind(), nil
+	case "type":
+		return p.Type(), nil
+	case "section":
+		return p.Section(), nil
+	case "title":
+		return p.Title(), nil
+	case "linktitle":
+		return p.LinkTitle(), nil
+	case "description":
+		return p.Description(), nil
+	case "date":
+		if p.Date().IsZero() {
+			return nil, nil
+		}
+		return p.Date(), nil
+	case "lastmod":
+		if p.Lastmod().IsZero() {
+			return nil, nil
+		}
+		return p.Lastmod(), nil
+	case "permalink":
+		return p.Permalink(), nil
+	case "relpermalink":
+		return p.RelPermalink(), nil
+	case "summary":
+		return p.Summary(), nil
+	case "content":
+		return p.Content()
+	case "plain":
+		return p.Plain(), nil
+	case "wordcount":
+		return p.WordCount(), nil
+	case "readingtime":
+		return p.ReadingTime(), nil
+	case "weight":
+		return p.Weight(), nil
+	case "draft":
+		return p.Draft(), nil
+	case "params":
+		return p.Params(), nil
+	}
+
+	v, err := p.Param(name)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, nil
+	}
+	if _, ok := v.(string); ok {
+		return v, nil
+	}
+	if vv, err := cast.ToStringSliceE(v); err == nil {
+		return vv, nil
+	}
+	return v, nil
+}
@@ -0,0 +1,94 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestPageProjection(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "https://example.org/"
+[outputs]
+page = ["html", "json"]
+section = ["html", "json"]
+[projections.json]
+include = ["title", "params", "parent"]
+exclude = ["params.secret"]
+references = ["params.author", "params.related"]
+referenceFields = ["title", "relPermalink"]
+`)
+
+	b.WithContent("blog/_index.md", `---
+title: "Blog"
+---
+`, "blog/p1.md", `---
+title: "P1"
+author: "/authors/jane"
+related: ["/blog/p2", "/blog/missing"]
+secret: "s3cret"
+color: "blue"
+---
+`, "blog/p2.md", `---
+title: "P2"
+---
+`, "authors/jane.md", `---
+title: "Jane"
+---
+`)
+
+	b.WithTemplatesAdded("_default/list.json", `{"list":{{ .Title | jsonify }}}`)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/blog/p1/index.json",
+		`"title":"P1"`,
+		`"color":"blue"`,
+		`"author":{"relPermalink":"/authors/jane/","title":"Jane"}`,
+		`"related":[{"relPermalink":"/blog/p2/","title":"P2"}]`,
+		`"parent":{"relPermalink":"/blog/","title":"Blog"}`,
+	)
+	b.AssertFileContentFn("public/blog/p1/index.json", func(s string) bool {
+		return !strings.Contains(s, "s3cret")
+	})
+
+	// A template for the output format takes precedence.
+	b.AssertFileContent("public/blog/index.json", `{"list":"Blog"}`)
+}
+
+func TestPageProjectionNotConfigured(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "https://example.org/"
+[outputs]
+page = ["html", "json"]
+`)
+
+	b.WithContent("p1.md", `---
+title: "P1"
+---
+`)
+
+	b.Build(BuildCfg{})
+
+	// Only output formats with a projection get the built-in template.
+	b.Assert(b.CheckExists("public/p1/index.json"), qt.IsFalse)
+	b.Assert(b.H.Log.LogCounters().WarnCounter.Count(), qt.Equals, uint64(1))
+}
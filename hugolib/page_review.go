@@ -0,0 +1,156 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gohugoio/hugo/config/review"
+	"github.com/gohugoio/hugo/resources/page"
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+)
+
+// The max number of pages with missing review fields listed in the error.
+const reviewMaxErrorPages = 10
+
+// ReviewIssue describes a page with missing review metadata or overdue for
+// review.
+type ReviewIssue struct {
+	Page page.Page
+
+	// The required front matter params not set.
+	Missing []string
+
+	// When the page was due for review. Zero if not overdue.
+	Due time.Time
+}
+
+// Overdue returns whether the page is overdue for review.
+func (r ReviewIssue) Overdue() bool {
+	return !r.Due.IsZero()
+}
+
+// ReviewIssues returns the pages with missing review metadata or overdue for
+// review, as configured in the review section of the site config.
+func (h *HugoSites) ReviewIssues() ([]ReviewIssue, error) {
+	cfg, err := review.DecodeConfig(h.Cfg)
+	if err != nil || len(cfg.Sections) == 0 {
+		return nil, err
+	}
+
+	return h.reviewIssues(cfg, time.Now()), nil
+}
+
+func (h *HugoSites) reviewIssues(cfg review.Config, now time.Time) []ReviewIssue {
+	var issues []ReviewIssue
+
+	for _, s := range h.Sites {
+		for _, p := range s.RegularPages() {
+			if p.File().IsZero() {
+				continue
+			}
+
+			rs, found := cfg.ForSection(p.Section())
+			if !found {
+				continue
+			}
+
+			var issue ReviewIssue
+
+			for _, key := range rs.Required {
+				if isEmptyParam(p.Params()[strings.ToLower(key)]) {
+					issue.Missing = append(issue.Missing, key)
+				}
+			}
+
+			if rs.Interval > 0 {
+				reviewed := p.Lastmod()
+				if v, found := p.Params()[strings.ToLower(cfg.DateParam)]; found {
+					if d, err := cast.ToTimeE(v); err == nil {
+						reviewed = d
+					}
+				}
+				if due := reviewed.Add(rs.Interval); due.Before(now) {
+					issue.Due = due
+				}
+			}
+
+			if issue.Missing != nil || issue.Overdue() {
+				issue.Page = p
+				issues = append(issues, issue)
+			}
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Page.File().Filename() < issues[j].Page.File().Filename()
+	})
+
+	return issues
+}
+
+// checkReviews logs the pages overdue for review and fails the build, or
+// logs, if any pages are missing required review fields.
+func (h *HugoSites) checkReviews() error {
+	cfg, err := review.DecodeConfig(h.Cfg)
+	if err != nil || len(cfg.Sections) == 0 {
+		return err
+	}
+
+	var (
+		issues  = h.reviewIssues(cfg, time.Now())
+		missing []string
+	)
+
+	for _, issue := range issues {
+		filename := issue.Page.File().Path()
+		if issue.Overdue() {
+			h.Log.Warnf("%q is overdue for review since %s.", filename, issue.Due.Format("2006-01-02"))
+		}
+		if issue.Missing == nil {
+			continue
+		}
+		if cfg.ErrorLevel == "warning" {
+			h.Log.Warnf("%q is missing required review fields: %s.", filename, strings.Join(issue.Missing, ", "))
+			continue
+		}
+		missing = append(missing, filename+": "+strings.Join(issue.Missing, ", "))
+	}
+
+	if missing == nil {
+		return nil
+	}
+
+	listed := missing
+	if len(listed) > reviewMaxErrorPages {
+		listed = append(listed[:reviewMaxErrorPages:reviewMaxErrorPages], "...")
+	}
+
+	return errors.Errorf("%d page(s) are missing required review fields: %s", len(missing), strings.Join(listed, "; "))
+}
+
+func isEmptyParam(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	switch vv := reflect.ValueOf(v); vv.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map:
+		return vv.Len() == 0
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"fmt"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestPageReview(t *testing.T) {
+	t.Parallel()
+
+	for _, errorLevel := range []string{"error", "warning"} {
+		errorLevel := errorLevel
+		t.Run(errorLevel, func(t *testing.T) {
+			b := newTestSitesBuilder(t).WithConfigFile("toml", fmt.Sprintf(`
+baseURL = "https://example.org/"
+[review]
+errorLevel = %q
+[review.sections.docs]
+required = ["owner", "reviewers"]
+interval = "2160h"
+[review.sections.blog]
+required = ["owner"]
+`, errorLevel))
+
+			b.WithContent(
+				"docs/ok.md", "---\ntitle: OK\nowner: jane\nreviewers: [joe]\nlastReviewed: 2999-01-01\n---\n",
+				"docs/overdue.md", "---\ntitle: Overdue\nowner: jane\nreviewers: [joe]\nlastReviewed: 2020-01-01\n---\n",
+				"docs/missing.md", "---\ntitle: Missing\nowner: jane\nreviewers: []\nlastReviewed: 2999-01-01\n---\n",
+				"blog/missing.md", "---\ntitle: Blog\ndate: 2020-01-01\n---\n",
+				"news/unchecked.md", "---\ntitle: News\n---\n",
+			)
+			b.WithTemplates("_default/single.html", "{{ .Title }}", "_default/list.html", "{{ .Title }}")
+
+			err := b.BuildE(BuildCfg{})
+
+			issues, ierr := b.H.ReviewIssues()
+			b.Assert(ierr, qt.IsNil)
+			b.Assert(len(issues), qt.Equals, 3)
+			b.Assert(issues[0].Page.Title(), qt.Equals, "Blog")
+			b.Assert(issues[0].Missing, qt.DeepEquals, []string{"owner"})
+			b.Assert(issues[0].Overdue(), qt.Equals, false)
+			b.Assert(issues[1].Page.Title(), qt.Equals, "Missing")
+			b.Assert(issues[1].Missing, qt.DeepEquals, []string{"reviewers"})
+			b.Assert(issues[2].Page.Title(), qt.Equals, "Overdue")
+			b.Assert(issues[2].Missing, qt.IsNil)
+			b.Assert(issues[2].Due.Format("2006-01-02"), qt.Equals, "2020-03-31")
+
+			if errorLevel == "error" {
+				b.Assert(err, qt.Not(qt.IsNil))
+				b.Assert(err.Error(), qt.Contains, "2 page(s) are missing required review fields")
+				b.Assert(err.Error(), qt.Contains, "docs/missing.md: reviewers")
+				b.Assert(b.H.Log.LogCounters().WarnCounter.Count(), qt.Equals, uint64(1))
+			} else {
+				b.Assert(err, qt.IsNil)
+				b.Assert(b.H.Log.LogCounters().WarnCounter.Count(), qt.Equals, uint64(3))
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"sort"
+
+	"github.com/gohugoio/hugo/resources/page"
+	"github.com/pkg/errors"
+)
+
+// Sequences returns the named page sequences p is in, see
+// page.SequencesProvider.
+func (p *pageState) Sequences() page.Sequences {
+	p.s.initInit(p.s.init.sequences, p)
+	return p.sequences
+}
+
+// assembleSequences resolves the pages in the sequences configured and sets
+// their positions in them.
+func (s *Site) assembleSequences() error {
+	s.pageMap.withEveryBundlePage(func(p *pageState) bool {
+		p.sequences = nil
+		return false
+	})
+
+	if len(s.siteCfg.sequences) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(s.siteCfg.sequences))
+	for name := range s.siteCfg.sequences {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		cfg := s.siteCfg.sequences[name]
+
+		refs := cfg.Pages
+		if cfg.Data != "" {
+			var err error
+			refs, err = lookupDataList(cfg.Data, s.h.Data())
+			if err != nil {
+				return errors.Wrapf(err, "sequence %q", name)
+			}
+		}
+
+		var pas page.Pages
+		seen := make(map[page.Page]bool)
+		for _, ref := range refs {
+			p, err := s.getPageNew(nil, ref)
+			if err != nil {
+				return errors.Wrapf(err, "sequence %q", name)
+			}
+			if p == nil {
+				s.Log.Warnf("Sequence %q: page %q not found", name, ref)
+				continue
+			}
+			if seen[p] {
+				s.Log.Warnf("Sequence %q: page %q listed more than once", name, ref)
+				continue
+			}
+			seen[p] = true
+			pas = append(pas, p)
+		}
+
+		for i, p := range pas {
+			ps, ok := p.(*pageState)
+			if !ok {
+				continue
+			}
+			if ps.sequences == nil {
+				ps.sequences = make(page.Sequences)
+			}
+			ps.sequences[name] = &page.SequencePosition{Name: name, Pages: pas, Index: i}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestPageSequences(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "https://example.org/"
+[sequences.basics]
+pages = ["/docs/install", "docs/intro.md", "/docs/advanced", "/docs/missing", "/docs/install"]
+[sequences.Tour]
+data = "paths.tour"
+`)
+
+	b.WithContent(
+		"docs/_index.md", "---\ntitle: Docs\n---\n",
+		"docs/intro.md", "---\ntitle: Intro\n---\n",
+		"docs/install.md", "---\ntitle: Install\n---\n",
+		"docs/advanced/_index.md", "---\ntitle: Advanced\n---\n",
+		"blog/b1.md", "---\ntitle: B1\n---\n",
+	)
+
+	b.WithSourceFile("data/paths.yaml", `
+tour:
+- /blog/b1
+- /docs/intro
+`)
+
+	b.WithTemplates(
+		"_default/single.html", `{{ .Title }}|{{ with .Sequences.Get "basics" }}Basics: {{ .Position }}/{{ .Len }} Prev: {{ with .Prev }}{{ .Title }}{{ end }} Next: {{ with .Next }}{{ .Title }}{{ end }} First: {{ .First.Title }} Last: {{ .Last.Title }} {{ .IsFirst }} {{ .IsLast }}{{ end }}|{{ with .Sequences.Get "tour" }}Tour: {{ range .Pages }}{{ .Title }},{{ end }} Next: {{ with .Next }}{{ .Title }}{{ end }}{{ end }}|`,
+		"_default/list.html", `{{ .Title }}|{{ with .Sequences.Get "basics" }}Basics: {{ .Position }}/{{ .Len }} Prev: {{ with .Prev }}{{ .Title }}{{ end }}{{ end }}|`,
+	)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/docs/install/index.html", "Install|Basics: 1/3 Prev:  Next: Intro First: Install Last: Advanced true false||")
+	b.AssertFileContent("public/docs/intro/index.html", "Intro|Basics: 2/3 Prev: Install Next: Advanced First: Install Last: Advanced false false|Tour: B1,Intro, Next: |")
+	b.AssertFileContent("public/docs/advanced/index.html", "Advanced|Basics: 3/3 Prev: Intro|")
+	b.AssertFileContent("public/blog/b1/index.html", "B1||Tour: B1,Intro, Next: Intro|")
+	b.AssertFileContent("public/docs/index.html", "Docs||")
+
+	// One missing, one duplicate.
+	b.Assert(b.H.Log.LogCounters().WarnCounter.Count(), qt.Equals, uint64(2))
+}
+
+func TestPageSequencesErrors(t *testing.T) {
+	t.Parallel()
+	c := qt.New(t)
+
+	for _, test := range []struct {
+		config string
+		expect string
+	}{
+		{`[sequences.basics]`, `.*sequences: "basics": set either pages or data.*`},
+		{"[sequences.basics]\ndata = \"missing.list\"", `.*sequence "basics": data "missing.list" not found.*`},
+	} {
+		b := newTestSitesBuilder(t)
+		b.WithConfigFile("toml", "baseURL = \"https://example.org\"\n"+test.config)
+		b.WithContent("docs/p1.md", "---\ntitle: P1\n---\n")
+		b.WithTemplates("_default/single.html", `{{ with .Sequences.Get "basics" }}{{ .Position }}{{ end }}`)
+		err := b.CreateSitesE()
+		if err == nil {
+			err = b.BuildE(BuildCfg{})
+		}
+		c.Assert(err, qt.ErrorMatches, test.expect)
+	}
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/gohugoio/hugo/markup/asciidocext"
 
 	"github.com/gohugoio/hugo/config"
+	"github.com/gohugoio/hugo/config/secrets"
 
 	"github.com/gohugoio/hugo/common/loggers"
 
@@ -760,6 +761,35 @@ Here is the last report for commits in the year 2016. It covers hrev50718-hrev50
 `)
 }
 
+func TestPageAsciidocBuiltIn(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t)
+	b.WithContent("p1.asciidoc", `---
+title: P1
+---
+The *summary*.
+
+<!--more-->
+
+== The Section
+
+See https://gohugo.io[Hugo].
+`)
+	b.WithTemplatesAdded(
+		"_default/single.html", "Summary: {{ .Summary }}|Content: {{ .Content }}|TOC: {{ .TableOfContents }}",
+		"_default/_markup/render-link.html", "Link: {{ .Destination }}|{{ .Text }}",
+	)
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/p1/index.html",
+		"Summary: <div class=\"paragraph\">\n<p>The <strong>summary</strong>.</p>\n</div>|",
+		"<h2 id=\"_the_section\">The Section</h2>",
+		"Link: https://gohugo.io|Hugo",
+		"<li><a href=\"#_the_section\">The Section</a></li>",
+	)
+}
+
 func TestPageWithDate(t *testing.T) {
 	t.Parallel()
 	cfg, fs := newTestCfg()
@@ -1605,6 +1635,52 @@ author = "Jo Nesbø"
 		"Author site config:  Kurt Vonnegut")
 }
 
+func TestPageParamTypes(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "https://example.org"
+
+[paramTypes]
+featured = "bool"
+tags = "[]string"
+[paramTypes.author]
+rating = "int"
+`)
+
+	b.WithTemplatesAdded("_default/single.html", `{{ .Title }}|featured: {{ if .Params.featured }}yes{{ else }}no{{ end }} {{ printf "%T" .Params.featured }}|tags: {{ printf "%T" .Params.tags }} {{ .Params.tags }}|rating: {{ printf "%T" .Params.author.rating }}`)
+
+	b.WithContent("p1.md", `
+---
+title: "P1"
+featured: "false"
+tags: hugo
+author:
+  rating: "4"
+---
+`,
+		"p2.md", `
+---
+title: "P2"
+featured: true
+tags: [hugo, go]
+---
+`,
+		"p3.md", `
+---
+title: "P3"
+featured: "maybe"
+---
+`)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/p1/index.html", "P1|featured: no bool|tags: []string [hugo]|rating: int")
+	b.AssertFileContent("public/p2/index.html", "P2|featured: yes bool|tags: []string [hugo go]")
+	b.AssertFileContent("public/p3/index.html", "P3|featured: yes string")
+	b.Assert(b.H.Log.LogCounters().WarnCounter.Count(), qt.Equals, uint64(4))
+}
+
 func TestGoldmark(t *testing.T) {
 	t.Parallel()
 
@@ -1740,6 +1816,103 @@ $$$
 	)
 }
 
+func TestPageGoldmarkConfigOverrides(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "https://example.org"
+`)
+	b.WithTemplatesAdded("_default/single.html", `Content: {{ .Content }}|Markup: {{ with .Params.markup }}{{ if reflect.IsMap . }}{{ .goldmark.renderer.unsafe }}{{ else }}{{ . }}{{ end }}{{ end }}|`)
+
+	content := `---
+title: %q
+%s
+---
+
+<b>raw</b> "quoted"
+`
+
+	b.WithContent(
+		"p1.md", fmt.Sprintf(content, "Default", ""),
+		"p2.md", fmt.Sprintf(content, "Unsafe", `markup:
+  goldmark:
+    renderer:
+      unsafe: true
+    extensions:
+      typographer: false`),
+		"p3.md", fmt.Sprintf(content, "Markup", "markup: md"),
+		"legacy/_index.md", `---
+title: "Legacy"
+cascade:
+  markup:
+    goldmark:
+      renderer:
+        unsafe: true
+---
+`,
+		"legacy/p1.md", fmt.Sprintf(content, "Legacy", ""),
+	)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/p1/index.html", "<!-- raw HTML omitted -->", "&ldquo;quoted&rdquo;")
+	b.AssertFileContent("public/p2/index.html", "<b>raw</b> &quot;quoted&quot;", "Markup: true|")
+	b.AssertFileContent("public/p3/index.html", "<!-- raw HTML omitted -->", "Markup: md|")
+	b.AssertFileContent("public/legacy/p1/index.html", "<b>raw</b> &ldquo;quoted&rdquo;")
+}
+
+func TestPageMath(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "https://example.org"
+[markup.goldmark.extensions.math]
+enable = true
+output = "mathml"
+macroFile = "macros.tex"
+`)
+	b.WithSourceFile("assets/macros.tex", `\RR:\mathbb{R}`)
+	b.WithTemplatesAdded("_default/single.html", `Content: {{ .Content }}`)
+	b.WithContent("p1.md", `---
+title: "P1"
+---
+
+Inline $a_1 < b_1$ in $\RR$.
+
+$$
+\sum_{i=1}^n x_i
+$$
+`, "p2.md", `---
+title: "P2"
+---
+
+Inline $x^1^2$ and $\foo$.
+`)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/p1/index.html",
+		`<p>Inline <math xmlns="http://www.w3.org/1998/Math/MathML"><semantics><mrow><msub><mi>a</mi><mn>1</mn></msub><mo>&lt;</mo><msub><mi>b</mi><mn>1</mn></msub></mrow><annotation encoding="application/x-tex">a_1 &lt; b_1</annotation></semantics></math> in <math`,
+		`<mi mathvariant="double-struck">R</mi>`,
+		`<p><math xmlns="http://www.w3.org/1998/Math/MathML" display="block"><semantics><mrow><munderover><mo>∑</mo>`,
+	)
+	// Invalid TeX is rendered in place, unsupported TeX passed through.
+	b.AssertFileContent("public/p2/index.html",
+		`<merror title="mathml: double superscript at position 3"><mtext>x^1^2</mtext></merror>`,
+		`and $\foo$.`,
+	)
+
+	b = newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "https://example.org"
+[markup.goldmark.extensions.math]
+enable = true
+output = "mathml"
+throwOnError = true
+`)
+	b.WithContent("p1.md", "Inline $x^1^2$.")
+	b.Assert(b.BuildE(BuildCfg{}), qt.ErrorMatches, `.*double superscript.*`)
+}
+
 func TestPageCaseIssues(t *testing.T) {
 	t.Parallel()
 
@@ -1760,3 +1933,230 @@ Page1: {{ $p1.Path }}
 
 	b.AssertFileContent("public/index.html", "Lang: no", filepath.FromSlash("Page1: a/B/C/Page1.md"))
 }
+
+func TestPageSanitize(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "https://example.org/"
+[markup.goldmark.renderer]
+unsafe = true
+[markup.sanitize.policies.links]
+elements = ["p", "a"]
+[markup.sanitize.policies.links.attributes]
+a = ["href"]
+`)
+
+	b.WithContent("external/_index.md", `---
+title: "External"
+cascade:
+  sanitize: default
+---
+`, "external/p1.md", `---
+title: "P1"
+---
+Hello <script>alert(1)</script>**World** <a href="javascript:alert(1)" onclick="x()">Link</a>.
+`, "links.md", `---
+title: "Links"
+sanitize: links
+---
+Hello **World** <a href="https://example.org" title="T">Link</a>.
+`, "trusted.md", `---
+title: "Trusted"
+---
+Hello <script>alert(1)</script>.
+`)
+
+	b.WithTemplatesAdded("_default/single.html", `Content: {{ .Content }}|Summary: {{ .Summary }}|`)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/external/p1/index.html",
+		"Content: <p>Hello <strong>World</strong> <a>Link</a>.</p>",
+		"Summary: Hello World Link.|",
+	)
+	b.AssertFileContent("public/links/index.html", `Content: <p>Hello World <a href="https://example.org">Link</a>.</p>`)
+	b.AssertFileContent("public/trusted/index.html", "Hello <script>alert(1)</script>.")
+}
+
+func TestPageOutputContentHash(t *testing.T) {
+	t.Parallel()
+
+	c := qt.New(t)
+
+	b := newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "https://example.org/"
+paginate = 2
+[build]
+writeManifest = true
+[outputs]
+home = ["html"]
+page = ["html", "json"]
+`)
+
+	b.WithContent("p1.md", `---
+title: "P1"
+---
+Content 1.
+`, "p2.md", `---
+title: "P2"
+---
+Content 1.
+`, "p3.md", `---
+title: "P3"
+---
+Content 3.
+`)
+
+	b.WithTemplatesAdded(
+		"index.html", `{{ range .Paginator.Pages }}{{ .Title }}|{{ end }}`,
+		"_default/single.html", `Hash: {{ .OutputContentHash }}|Alias: {{ .ContentHash }}|`,
+		"_default/single.json", `{"hash": {{ .OutputContentHash | jsonify }}}`,
+	)
+
+	b.Running().Build(BuildCfg{})
+
+	hash1 := helpers.MD5String("<p>Content 1.</p>\n")
+	hash3 := helpers.MD5String("<p>Content 3.</p>\n")
+
+	b.AssertFileContent("public/p1/index.html", "Hash: "+hash1+"|Alias: "+hash1+"|")
+	b.AssertFileContent("public/p2/index.html", "Hash: "+hash1+"|")
+	b.AssertFileContent("public/p3/index.html", "Hash: "+hash3+"|")
+	b.AssertFileContent("public/p1/index.json", `{"hash": "`+hash1+`"}`)
+
+	c.Assert(hash1, qt.Not(qt.Equals), hash3)
+
+	// The manifest holds the hashes of the published files.
+	publishedHash := func(filename string) string {
+		return helpers.MD5String(b.FileContent(filename))
+	}
+
+	b.AssertFileContent("public/hugo_manifest.json",
+		`"/": "`+publishedHash("public/index.html")+`"`,
+		`"/page/2/": "`+publishedHash("public/page/2/index.html")+`"`,
+		`"/p1/": "`+publishedHash("public/p1/index.html")+`"`,
+		`"/p1/index.json": "`+publishedHash("public/p1/index.json")+`"`,
+		`"/p3/": "`+publishedHash("public/p3/index.html")+`"`,
+	)
+
+	// The manifest is rebuilt on every build.
+	b.RemoveFiles("content/p3.md")
+	b.Build(BuildCfg{})
+
+	b.AssertFileContentFn("public/hugo_manifest.json", func(s string) bool {
+		return strings.Contains(s, `"/p1/"`) && !strings.Contains(s, `"/p3/"`) && !strings.Contains(s, `"/page/2/"`)
+	})
+}
+
+func TestRelatedVector(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "https://example.org/"
+[related]
+threshold = 50
+includeNewer = true
+[[related.indices]]
+name = "embedding"
+type = "vector"
+weight = 100
+`)
+
+	b.WithContent(
+		"p1.md", "---\ntitle: Hugo\n---\nHugo is a fast static site generator written in Go.",
+		"p2.md", "---\ntitle: Static sites\n---\nA static site generator like Hugo is fast.",
+		"p3.md", "---\ntitle: Bananas\n---\nBananas are yellow and grow in clusters.",
+		"p4.md", "---\ntitle: Vectors\nembedding: [1, 0, 0]\n---\n",
+		"p5.md", "---\ntitle: More vectors\nembedding: [0.8, 0.2, 0]\n---\n",
+	)
+
+	b.WithTemplatesAdded("_default/single.html", `Related: {{ range .Site.RegularPages.Related . }}{{ .Title }}|{{ end }}`)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/p1/index.html", "Related: Static sites|\n")
+	b.AssertFileContent("public/p3/index.html", "Related: \n")
+	b.AssertFileContent("public/p4/index.html", "Related: More vectors|\n")
+}
+
+func TestRelatedIncludeOtherLanguages(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "https://example.org/"
+defaultContentLanguage = "en"
+[languages]
+[languages.en]
+weight = 1
+[languages.nn]
+weight = 2
+[related]
+threshold = 80
+includeNewer = true
+includeOtherLanguages = true
+[[related.indices]]
+name = "tags"
+weight = 100
+`)
+
+	b.WithContent(
+		"blog/p1.md", "---\ntitle: P1 en\ntags: [a]\n---\n",
+		"blog/p1.nn.md", "---\ntitle: P1 nn\ntags: [a]\n---\n",
+		"blog/p2.md", "---\ntitle: P2 en\ntags: [a]\n---\n",
+		"blog/p2.nn.md", "---\ntitle: P2 nn\ntags: [a]\n---\n",
+		"blog/p3.nn.md", "---\ntitle: P3 nn\ntags: [a]\n---\n",
+		"docs/p4.nn.md", "---\ntitle: P4 nn\ntags: [a]\n---\n",
+	)
+
+	b.WithTemplatesAdded("_default/single.html", `Related: {{ range (where .Site.RegularPages "Section" "blog").Related . }}{{ .Title }}|{{ end }}`)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/blog/p1/index.html", "Related: P2 en|P3 nn|\n")
+	b.AssertFileContent("public/nn/blog/p1/index.html", "Related: P2 nn|P3 nn|\n")
+}
+
+func TestPageEncryptedFrontMatter(t *testing.T) {
+	c := qt.New(t)
+
+	key, err := secrets.GenerateKey()
+	c.Assert(err, qt.IsNil)
+	decrypter, err := secrets.NewDecrypter(key)
+	c.Assert(err, qt.IsNil)
+	title, err := decrypter.Encrypt("Embargoed Title")
+	c.Assert(err, qt.IsNil)
+	link, err := decrypter.Encrypt("https://internal.example.org/draft")
+	c.Assert(err, qt.IsNil)
+
+	indent := func(s string) string {
+		return "  " + strings.ReplaceAll(strings.TrimSpace(s), "\n", "\n  ")
+	}
+
+	content := fmt.Sprintf(`---
+title: |
+%s
+internal: |
+%s
+public: "Public value"
+---
+`, indent(title), indent(link))
+
+	templ := `{{ range .Site.RegularPages }}{{ .Title }}|{{ .Params.internal }}|{{ .Params.public }}|{{ end }}`
+
+	b := newTestSitesBuilder(t)
+	b.WithConfigFile("toml", fmt.Sprintf(`
+baseURL = "https://example.org"
+[secrets]
+key = %q
+`, key))
+	b.WithContent("p1.md", content).WithTemplates("index.html", templ)
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/index.html", "Embargoed Title|https://internal.example.org/draft|Public value|")
+
+	b = newTestSitesBuilder(t)
+	b.WithContent("p1.md", content).WithTemplates("index.html", templ)
+	err = b.BuildE(BuildCfg{})
+	c.Assert(err, qt.Not(qt.IsNil))
+	c.Assert(err.Error(), qt.Contains, `p1.md: failed to decrypt front matter: "internal": found an encrypted value, but no secrets.key or secrets.keyFile is configured`)
+}
@@ -0,0 +1,178 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/gohugoio/hugo/common/maps"
+	"github.com/gohugoio/hugo/compare"
+	"github.com/gohugoio/hugo/config/weights"
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+)
+
+// assignWeights assigns weights to the pages and sections with no weight in
+// front matter in the sections with a weight strategy configured. The
+// weights start at 1 and only apply to the direct children of the section.
+func (m *pageMap) assignWeights() error {
+	weights := m.s.siteCfg.weights
+	if len(weights) == 0 {
+		return nil
+	}
+
+	var err error
+
+	m.sections.Walk(func(s string, v interface{}) bool {
+		sw, found := weights[strings.ToLower(strings.Trim(s, "/"))]
+		if !found || s == "/" {
+			return false
+		}
+
+		var pages []*pageState
+		query := pageMapQuery{Prefix: s, Filter: contentTreeNoListAlwaysFilter}
+		if err = m.collectPagesAndSections(query, func(n *contentNode) {
+			if n.p != nil {
+				pages = append(pages, n.p)
+			}
+		}); err != nil {
+			return true
+		}
+
+		err = orderByWeightStrategy(pages, sw, m.s.h.Data())
+		if err != nil {
+			err = errors.Wrapf(err, "weights: section %q", strings.Trim(s, "/"))
+			return true
+		}
+
+		weight := 1
+		for _, p := range pages {
+			p.m.autoWeight = 0
+			if p.m.weight != 0 {
+				continue
+			}
+			p.m.autoWeight = weight
+			weight++
+		}
+
+		return false
+	})
+
+	return err
+}
+
+func orderByWeightStrategy(pages []*pageState, sw weights.Config, data map[string]interface{}) error {
+	byName := func(i, j int) bool {
+		n1, n2 := weightName(pages[i]), weightName(pages[j])
+		if n1 == n2 {
+			return pages[i].File().Path() < pages[j].File().Path()
+		}
+		return compare.LessStringsNatural(n1, n2)
+	}
+
+	switch sw.Strategy {
+	case weights.ByFilename:
+		sort.SliceStable(pages, byName)
+	case weights.ByDate:
+		sort.SliceStable(pages, func(i, j int) bool {
+			d1, d2 := pages[i].Date(), pages[j].Date()
+			if d1.Equal(d2) {
+				return byName(i, j)
+			}
+			return d1.Before(d2)
+		})
+	case weights.ByData:
+		order, err := weightDataOrder(sw.Data, data)
+		if err != nil {
+			return err
+		}
+		sort.SliceStable(pages, func(i, j int) bool {
+			o1, found1 := order[weightName(pages[i])]
+			o2, found2 := order[weightName(pages[j])]
+			switch {
+			case found1 && found2:
+				return o1 < o2
+			case found1 || found2:
+				return found1
+			}
+			return byName(i, j)
+		})
+	}
+
+	if sw.Reverse {
+		for i, j := 0, len(pages)-1; i < j; i, j = i+1, j-1 {
+			pages[i], pages[j] = pages[j], pages[i]
+		}
+	}
+
+	return nil
+}
+
+// weightDataOrder returns the position of every page name in the list at
+// the dot separated path in data.
+func weightDataOrder(dataPath string, data map[string]interface{}) (map[string]int, error) {
+	list, err := lookupDataList(dataPath, data)
+	if err != nil {
+		return nil, err
+	}
+
+	order := make(map[string]int)
+	for i, name := range list {
+		name = strings.ToLower(strings.Trim(name, "/"))
+		name = strings.TrimSuffix(name, path.Ext(name))
+		name = strings.TrimSuffix(strings.TrimSuffix(name, "/_index"), "/index")
+		name = path.Base(name)
+		if _, found := order[name]; !found {
+			order[name] = i
+		}
+	}
+
+	return order, nil
+}
+
+// lookupDataList returns the list of strings at the dot separated path in
+// data, e.g. "docs.order".
+func lookupDataList(dataPath string, data map[string]interface{}) ([]string, error) {
+	var v interface{} = data
+	for _, k := range strings.Split(strings.Trim(dataPath, "."), ".") {
+		m, err := maps.ToStringMapE(v)
+		if err != nil {
+			return nil, errors.Errorf("data %q not found", dataPath)
+		}
+		if v = m[k]; v == nil {
+			return nil, errors.Errorf("data %q not found", dataPath)
+		}
+	}
+
+	list, err := cast.ToStringSliceE(v)
+	if err != nil {
+		return nil, errors.Errorf("data %q: expected a list of page names, got %T", dataPath, v)
+	}
+
+	return list, nil
+}
+
+// weightName returns the name p is ordered by, its file name without
+// extension or, for bundles and sections, its directory name.
+func weightName(p *pageState) string {
+	if !p.File().IsZero() && !p.IsNode() {
+		return strings.ToLower(p.File().ContentBaseName())
+	}
+	if sections := p.SectionsEntries(); len(sections) > 0 {
+		return strings.ToLower(sections[len(sections)-1])
+	}
+	return ""
+}
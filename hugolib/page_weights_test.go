@@ -0,0 +1,98 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestPageWeightStrategies(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t)
+
+	b.WithConfigFile("toml", `
+baseURL = "https://example.org"
+
+[weights.docs]
+strategy = "filename"
+[weights.guides]
+strategy = "data"
+data = "guides.order"
+[weights.news]
+strategy = "date"
+reverse = true
+`)
+
+	b.WithContent(
+		"docs/10-advanced.md", "---\ntitle: Advanced\n---\n",
+		"docs/2-basics.md", "---\ntitle: Basics\n---\n",
+		"docs/1-intro.md", "---\ntitle: Intro\n---\n",
+		"docs/3-setup/_index.md", "---\ntitle: Setup\n---\n",
+		"docs/3-setup/install.md", "---\ntitle: Install\n---\n",
+		"docs/pinned.md", "---\ntitle: Pinned\nweight: 100\n---\n",
+		"guides/alpha.md", "---\ntitle: Alpha\n---\n",
+		"guides/beta/index.md", "---\ntitle: Beta\n---\n",
+		"guides/gamma.md", "---\ntitle: Gamma\n---\n",
+		"guides/delta.md", "---\ntitle: Delta\n---\n",
+		"news/old.md", "---\ntitle: Old\ndate: 2020-01-01\n---\n",
+		"news/new.md", "---\ntitle: New\ndate: 2021-01-01\n---\n",
+		"blog/b.md", "---\ntitle: B\n---\n",
+		"blog/a.md", "---\ntitle: A\n---\n",
+	)
+
+	b.WithSourceFile("data/guides/order.yaml", `
+- gamma.md
+- beta
+- /guides/alpha
+`)
+
+	b.WithTemplates(
+		"_default/list.html", `{{ range .Pages }}{{ .Title }}:{{ .Weight }}|{{ end }}`,
+		"_default/single.html", `{{ .Title }}`,
+	)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/docs/index.html", "Intro:1|Basics:2|Setup:3|Advanced:4|Pinned:100|")
+	b.AssertFileContent("public/docs/3-setup/index.html", "Install:0|")
+	b.AssertFileContent("public/guides/index.html", "Gamma:1|Beta:2|Alpha:3|Delta:4|")
+	b.AssertFileContent("public/news/index.html", "New:1|Old:2|")
+	b.AssertFileContent("public/blog/index.html", "A:0|B:0|")
+}
+
+func TestPageWeightStrategiesErrors(t *testing.T) {
+	t.Parallel()
+	c := qt.New(t)
+
+	for _, test := range []struct {
+		config string
+		expect string
+	}{
+		{"[weights.docs]\nstrategy = \"random\"", `.*strategy must be one of.*`},
+		{"[weights.docs]\nstrategy = \"data\"", `.*no data path set.*`},
+		{"[weights.docs]\nstrategy = \"data\"\ndata = \"missing.order\"", `.*data "missing.order" not found.*`},
+	} {
+		b := newTestSitesBuilder(t)
+		b.WithConfigFile("toml", "baseURL = \"https://example.org\"\n"+test.config)
+		b.WithContent("docs/p1.md", "---\ntitle: P1\n---\n")
+		err := b.CreateSitesE()
+		if err == nil {
+			err = b.BuildE(BuildCfg{})
+		}
+		c.Assert(err, qt.ErrorMatches, test.expect)
+	}
+}
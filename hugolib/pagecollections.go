@@ -23,6 +23,7 @@ import (
 	"github.com/gohugoio/hugo/common/paths"
 
 	"github.com/gohugoio/hugo/hugofs/files"
+	"github.com/gohugoio/hugo/hugofs/glob"
 
 	"github.com/gohugoio/hugo/helpers"
 
@@ -168,6 +169,41 @@ func (c *PageCollections) getPageNew(context page.Page, ref string) (page.Page,
 	return n.p, nil
 }
 
+// getPagesMatching returns the pages with a source path matching the Glob
+// pattern. A page matches if either its file path, e.g. "docs/v1/install.md",
+// or its path without the file extension or bundle index filename, e.g.
+// "docs/v1/install", matches.
+func (c *PageCollections) getPagesMatching(pattern string) (page.Pages, error) {
+	g, err := glob.GetGlob(strings.TrimPrefix(filepath.ToSlash(pattern), "/"))
+	if err != nil {
+		return nil, err
+	}
+
+	var pages page.Pages
+	for _, p := range c.Pages() {
+		ref := strings.TrimPrefix(p.(*pageState).sourceRef(), "/")
+		if ref == "" {
+			continue
+		}
+		if g.Match(ref) || g.Match(pageRefWithoutExt(ref)) {
+			pages = append(pages, p)
+		}
+	}
+
+	return pages, nil
+}
+
+// pageRefWithoutExt returns ref without any file extension, and without the
+// filename for bundle index files.
+func pageRefWithoutExt(ref string) string {
+	ref = strings.TrimSuffix(ref, path.Ext(ref))
+	dir, name := path.Split(ref)
+	if name == "index" || name == "_index" {
+		return strings.TrimSuffix(dir, "/")
+	}
+	return ref
+}
+
 func (c *PageCollections) getSectionOrPage(ref string) (*contentNode, string) {
 	var n *contentNode
 
@@ -372,6 +372,37 @@ NOT FOUND
 	b.AssertFileContent("public/en/index.html", `NOT FOUND`)
 }
 
+func TestGetPages(t *testing.T) {
+	b := newTestSitesBuilder(t)
+
+	b.WithContent(
+		"docs/_index.md", "---\ntitle: Docs\n---",
+		"docs/install.md", "---\ntitle: Install\n---",
+		"docs/v1/_index.md", "---\ntitle: V1\n---",
+		"docs/v1/install.md", "---\ntitle: Install V1\nweight: 1\n---",
+		"docs/v1/installation-faq.md", "---\ntitle: Install FAQ V1\nweight: 2\n---",
+		"docs/v2/install/index.md", "---\ntitle: Install V2\nweight: 3\n---",
+		"docs/v2/usage.md", "---\ntitle: Usage V2\n---",
+		"blog/install.md", "---\ntitle: Blog Install\n---",
+	)
+
+	b.WithTemplates("index.html", `
+Install: {{ range site.GetPages "docs/**/install*" }}{{ .Title }}|{{ end }}
+Exact: {{ range site.GetPages "/docs/v1/install.md" }}{{ .Title }}|{{ end }}
+Sections: {{ range site.GetPages "docs/v*/_index.md" }}{{ .Title }}|{{ end }}
+None: {{ len (site.GetPages "nope/**") }}
+`)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/index.html",
+		"Install: Install V1|Install FAQ V1|Install V2|",
+		"Exact: Install V1|",
+		"Sections: V1|",
+		"None: 0",
+	)
+}
+
 func TestShouldDoSimpleLookup(t *testing.T) {
 	c := qt.New(t)
 
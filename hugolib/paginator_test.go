@@ -136,3 +136,36 @@ weight: %d
 	b.Assert(b.CheckExists("public/page/1/index.json"), qt.Equals, false)
 	b.AssertFileContent("public/page/2/index.json", `JSON: 22: |/p11/index.json|/p12/index.json`)
 }
+
+func TestPaginateGroupBy(t *testing.T) {
+	b := newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "https://example.com/"
+paginate = 3
+paginateKeepGroups = true
+`)
+
+	for i, title := range []string{"Apple", "Avocado", "Banana", "Blueberry", "Cherry", "Clementine", "Coconut", "Date"} {
+		b.WithContent(fmt.Sprintf("fruits/p%d.md", i), fmt.Sprintf("---\ntitle: %s\ndate: 2021-%02d-01\n---\n", title, i+1))
+	}
+
+	b.WithTemplates(
+		"partials/quarter.html", `{{ return printf "%d-Q%d" .Date.Year (add (div (sub .Date.Month 1) 3) 1) }}`,
+		"_default/list.html", `
+Letters: {{ range groupBy .Pages "substr" ".Title" 0 1 }}{{ .Key }}:{{ len .Pages }}|{{ end }}
+Quarters: {{ range (groupBy .Pages "partial" "quarter.html" ".").Reverse }}{{ .Key }}:{{ len .Pages }}|{{ end }}
+{{ $pag := .Paginate (groupBy .Pages.ByTitle "substr" ".Title" 0 1) }}
+Pager {{ $pag.PageNumber }}: {{ range $pag.PageGroups }}{{ .Key }}:{{ range .Pages }}{{ .Title }},{{ end }}|{{ end }}
+`,
+		"_default/single.html", `{{ .Title }}`)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/fruits/index.html",
+		"Letters: A:2|B:2|C:3|D:1|",
+		"Quarters: 2021-Q3:2|2021-Q2:3|2021-Q1:3|",
+		"Pager 1: A:Apple,Avocado,|",
+	)
+	b.AssertFileContent("public/fruits/page/2/index.html", "Pager 2: B:Banana,Blueberry,|")
+	b.AssertFileContent("public/fruits/page/3/index.html", "Pager 3: C:Cherry,Clementine,Coconut,|")
+	b.AssertFileContent("public/fruits/page/4/index.html", "Pager 4: D:Date,|")
+}
@@ -0,0 +1,164 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gohugoio/hugo/config"
+	"github.com/gohugoio/hugo/config/permalinkhistory"
+	"github.com/gohugoio/hugo/publisher"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// The max number of dropped URLs listed in the error.
+const permalinkHistoryMaxErrorURLs = 10
+
+// permalinkHistory keeps track of the URLs published, as pages, aliases or
+// any other file published by the sites, stored between builds to detect
+// URLs dropped by e.g. a permalink config change.
+type permalinkHistory struct {
+	cfg      permalinkhistory.Config
+	fs       afero.Fs
+	filename string
+
+	// The URLs in the history file.
+	previous []string
+
+	mu        sync.Mutex
+	published map[string]bool
+}
+
+func newPermalinkHistory(cfg config.Provider, fs afero.Fs) (*permalinkHistory, error) {
+	c, err := permalinkhistory.DecodeConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	ph := &permalinkHistory{
+		cfg:       c,
+		fs:        fs,
+		filename:  filepath.Join(cfg.GetString("workingDir"), c.Filename),
+		published: make(map[string]bool),
+	}
+
+	if !c.Enable {
+		return ph, nil
+	}
+
+	b, err := afero.ReadFile(fs, ph.filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ph, nil
+		}
+		return nil, err
+	}
+
+	if err := json.Unmarshal(b, &ph.previous); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal %q", ph.filename)
+	}
+
+	return ph, nil
+}
+
+// add records the file published to targetPath, relative to the publish dir.
+func (ph *permalinkHistory) add(targetPath string) {
+	u := "/" + strings.TrimPrefix(filepath.ToSlash(targetPath), "/")
+	u = strings.TrimSuffix(u, "index.html")
+
+	ph.mu.Lock()
+	ph.published[u] = true
+	ph.mu.Unlock()
+}
+
+// check reports the URLs in the history no longer published and writes the
+// URLs published to the history file.
+func (ph *permalinkHistory) check(h *HugoSites) error {
+	ph.mu.Lock()
+	defer ph.mu.Unlock()
+
+	var dropped []string
+	for _, u := range ph.previous {
+		if !ph.published[u] {
+			dropped = append(dropped, u)
+		}
+	}
+
+	if len(dropped) > 0 {
+		if ph.cfg.ErrorLevel == "error" {
+			urls := dropped
+			if len(urls) > permalinkHistoryMaxErrorURLs {
+				urls = urls[:permalinkHistoryMaxErrorURLs]
+			}
+			return errors.Errorf("%d URL(s) in %q are no longer published; add aliases for them, or remove them from the file if this is intended: %s", len(dropped), ph.cfg.Filename, strings.Join(urls, ", "))
+		}
+		for _, u := range dropped {
+			h.Log.Warnf("URL %q in %q is no longer published. Add an alias for it to keep it working.", u, ph.cfg.Filename)
+		}
+	}
+
+	current := make([]string, 0, len(ph.published))
+	for u := range ph.published {
+		current = append(current, u)
+	}
+	sort.Strings(current)
+
+	if len(dropped) == 0 && len(current) == len(ph.previous) {
+		// Nothing changed.
+		return nil
+	}
+
+	b, err := json.MarshalIndent(current, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := afero.WriteFile(ph.fs, ph.filename, b, 0666); err != nil {
+		return errors.Wrapf(err, "failed to write %q", ph.filename)
+	}
+
+	ph.previous = current
+
+	return nil
+}
+
+// permalinkHistoryPublisher records the files published in the permalink
+// history.
+type permalinkHistoryPublisher struct {
+	publisher.Publisher
+	history *permalinkHistory
+}
+
+func (p permalinkHistoryPublisher) Publish(d publisher.Descriptor) error {
+	if err := p.Publisher.Publish(d); err != nil {
+		return err
+	}
+	p.history.add(d.TargetPath)
+	return nil
+}
+
+// checkPermalinkHistory checks that the URLs published in earlier builds are
+// still published. Builds in server mode may be partial and are not checked.
+func (h *HugoSites) checkPermalinkHistory() error {
+	if !h.permalinkHistory.cfg.Enable || h.running {
+		return nil
+	}
+	return h.permalinkHistory.check(h)
+}
@@ -0,0 +1,73 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"fmt"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/spf13/afero"
+)
+
+func TestPermalinkHistory(t *testing.T) {
+	t.Parallel()
+
+	const history = `["/", "/blog/p1/", "/old/p2/", "/removed/", "/sitemap.xml"]`
+
+	newBuilder := func(t testing.TB, errorLevel string) *sitesBuilder {
+		b := newTestSitesBuilder(t).WithConfigFile("toml", fmt.Sprintf(`
+baseURL = "https://example.org/"
+disableKinds = ["taxonomy", "term", "section", "RSS", "robotsTXT", "404"]
+[permalinkHistory]
+enable = true
+errorLevel = %q
+`, errorLevel))
+
+		b.WithSourceFile("permalinks-history.json", history)
+		b.WithContent("blog/p1.md", "---\ntitle: P1\n---\n", "blog/p2.md", "---\ntitle: P2\naliases: [/old/p2/]\n---\n")
+		b.WithTemplates("_default/single.html", "{{ .Title }}", "index.html", "Home")
+
+		return b
+	}
+
+	t.Run("Error", func(t *testing.T) {
+		b := newBuilder(t, "error")
+		err := b.BuildE(BuildCfg{})
+		b.Assert(err, qt.Not(qt.IsNil))
+		b.Assert(err.Error(), qt.Contains, `1 URL(s) in "permalinks-history.json" are no longer published`)
+		b.Assert(err.Error(), qt.Contains, "/removed/")
+
+		content, err := afero.ReadFile(b.Fs.Source, "permalinks-history.json")
+		b.Assert(err, qt.IsNil)
+		b.Assert(string(content), qt.Equals, history)
+	})
+
+	t.Run("Warning", func(t *testing.T) {
+		b := newBuilder(t, "warning")
+		b.Build(BuildCfg{})
+
+		b.Assert(b.H.Log.LogCounters().WarnCounter.Count(), qt.Equals, uint64(1))
+
+		content, err := afero.ReadFile(b.Fs.Source, "permalinks-history.json")
+		b.Assert(err, qt.IsNil)
+		b.Assert(string(content), qt.Equals, `[
+  "/",
+  "/blog/p1/",
+  "/blog/p2/",
+  "/old/p2/",
+  "/sitemap.xml"
+]`)
+	})
+}
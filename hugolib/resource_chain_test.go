@@ -31,6 +31,7 @@ import (
 
 	"github.com/gohugoio/hugo/common/hexec"
 
+	"github.com/spf13/afero"
 	jww "github.com/spf13/jwalterweatherman"
 
 	"github.com/gohugoio/hugo/common/herrors"
@@ -490,6 +491,107 @@ End.`)
 	b.AssertFileContent("public/page2/index.html", `HELLO: /hello.min.a2d1cb24f24b322a7dad520414c523e9.html`)
 }
 
+func TestResourceChainCSSPrune(t *testing.T) {
+	statsFilename := "hugo_stats.json"
+	defer os.Remove(statsFilename)
+
+	b := newTestSitesBuilder(t)
+	b.WithConfigFile("toml", `
+disableKinds = ["taxonomy", "term", "section", "sitemap", "RSS", "robotsTXT", "404"]
+[build]
+writeStats = true
+`)
+	b.WithContent("p1.md", "---\ntitle: P1\n---\n")
+	b.WithSourceFile("assets/css/main.css", `.home { color: red }
+.page { color: blue }
+.unused { color: green }
+`)
+
+	b.WithTemplates(
+		"index.html", `{{ $css := resources.Get "css/main.css" | css.Prune | resources.PostProcess }}
+<div class="home">CSS: {{ $css.RelPermalink }}</div>
+{{ $fromLayouts := resources.Get "css/main.css" | css.Prune (dict "noStats" true "content" (slice "layouts/_default/*.html") "safelist" (slice "^un")) }}
+<p>Layouts: {{ $fromLayouts.Content | safeCSS }}</p>`,
+		"_default/single.html", `<div class="page">{{ .Title }}</div>`,
+	)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/index.html", "CSS: /css/main.css", `Layouts: .page { color: blue }
+.unused { color: green }`)
+	b.AssertFileContent("public/css/main.css", ".home { color: red }\n.page { color: blue }\n")
+	b.Assert(b.FileContent("public/css/main.css"), qt.Not(qt.Contains), "unused")
+}
+
+func TestResourceChainCSSProcess(t *testing.T) {
+	b := newTestSitesBuilder(t)
+	b.WithContent("p1.md", "---\ntitle: P1\n---")
+	b.WithSourceFile("assets/css/main.css", `.nav {
+  user-select: none;
+  a { color: red; }
+  &:hover { color: blue; }
+}`)
+
+	b.WithTemplates("index.html", `
+{{ $css := resources.Get "css/main.css" | css.Process }}
+{{ $min := resources.Get "css/main.css" | css.Process (dict "minify" true) }}
+CSS: {{ $css.RelPermalink }}|{{ $css.Content | safeHTML }}|
+Min: {{ $min.RelPermalink }}|{{ $min.Content | safeHTML }}|
+`)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/index.html", `CSS: /css/main.css|.nav {
+  -webkit-user-select: none;
+  user-select: none;
+}
+.nav a {
+  color: red;
+}
+.nav:hover {
+  color: blue;
+}`, "Min: /css/main.min.css|.nav{-webkit-user-select:none;user-select:none}.nav a{color:red}.nav:hover{color:blue}|")
+}
+
+func TestResourceChainAssetsManifest(t *testing.T) {
+	b := newTestSitesBuilder(t)
+	b.WithConfigFile("toml", `
+[build]
+writeAssetsManifest = true
+`)
+	b.WithContent("p1.md", "---\ntitle: P1\n---")
+	b.WithSourceFile("assets/css/main.css", `body { color: blue; }`)
+	b.WithSourceFile("assets/js/main.js", `var a = 1;`)
+	b.WithSourceFile("assets/images/logo.txt", `logo`)
+
+	b.WithTemplates("index.html", `
+{{ $css := resources.Get "css/main.css" | minify | fingerprint }}
+{{ $js := resources.Get "js/main.js" | minify | fingerprint "md5" | resources.PostProcess }}
+{{ $plain := resources.Get "images/logo.txt" }}
+{{ $hello := "Hello" | resources.FromString "hello.txt" | minify }}
+CSS: {{ $css.RelPermalink }}|JS: {{ $js.RelPermalink }}|Plain: {{ $plain.RelPermalink }}|Hello: {{ $hello.RelPermalink }}
+`)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/index.html", "JS: /js/main.min.6a51f46c55dc568facb34800aa65041b.js")
+
+	b.AssertFileContent("public/assets-manifest.json", `
+  "css/main.min.css": {
+    "url": "/css/main.min.fab4811e9952068575a0c8966147458c2b6a74d3ce49dc8d5a45b226067ea74f.css",
+    "integrity": "sha256-`, `
+  "hello.min.txt": {
+    "url": "/hello.min.txt"
+  },
+  "js/main.min.js": {
+    "url": "/js/main.min.6a51f46c55dc568facb34800aa65041b.js",
+    "integrity": "md5-`,
+	)
+
+	c := qt.New(t)
+	c.Assert(b.FileContent("public/assets-manifest.json"), qt.Not(qt.Contains), "logo")
+}
+
 func BenchmarkResourceChainPostProcess(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		b.StopTimer()
@@ -694,6 +796,19 @@ T4: {{ $r2.Data.Integrity }}|
 			b.AssertFileContent("public/index.html", `T3: ab|/rocks/hugo.187ef4436122d1cc2f40dc2b92f0eba0.txt|text/plain|md5-GH70Q2Ei0cwvQNwrkvDroA==|`)
 			b.AssertFileContent("public/index.html", `T4: sha256-Hgu9bGhroFC46wP/7txk/cnYCUf86CGrvl1tyNJSxaw=|`)
 		}},
+		{"cssmodules", func() bool { return true }, func(b *sitesBuilder) {
+			b.WithTemplates("home.html", `
+{{ $css := ".button { color: red } :global(.dark) .button { color: white }" | resources.FromString "css/button.module.css" | resources.CSSModules }}
+{{ $custom := ".card {}" | resources.FromString "css/card.module.css" | resources.CSSModules (dict "localIdentName" "x-[local]") }}
+T1: {{ $css.Content }}|{{ $css.MediaType.Type }}|
+T2: <button class="{{ index $css.Data.Classes "button" }}">|
+T3: {{ $custom.Content }}|
+`)
+		}, func(b *sitesBuilder) {
+			b.AssertFileContent("public/index.html", `T1: .button_button_3a26b0 { color: red } .dark .button_button_3a26b0 { color: white }|text/css|`)
+			b.AssertFileContent("public/index.html", `T2: <button class="button_button_3a26b0">|`)
+			b.AssertFileContent("public/index.html", `T3: .x-card {}|`)
+		}},
 		// https://github.com/gohugoio/hugo/issues/5226
 		{"baseurl-path", func() bool { return true }, func(b *sitesBuilder) {
 			b.WithSimpleConfigFileAndBaseURL("https://example.com/hugo/")
@@ -1141,3 +1256,131 @@ XML: {{ $xml.Content | safeHTML }}|{{ $xml.RelPermalink }}
 XML: <root>   <foo> asdfasdf </foo> </root>|/xml/data.min.3be4fddd19aaebb18c48dd6645215b822df74701957d6d36e59f203f9c30fd9f.xml
 `)
 }
+
+func TestResourcePrecompress(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "https://example.org"
+
+[precompress]
+encodings = ["gzip", "br"]
+minSize = 10
+
+`)
+
+	b.WithContent("page.md", "")
+
+	b.WithSourceFile(
+		"assets/css/main.css", "body { color: red; }",
+		"assets/css/other.css", "p { color: blue; }",
+		"assets/images/logo.png", "png png png png png",
+	)
+
+	b.WithTemplates("index.html", `
+{{ $css := resources.Get "css/main.css" | minify }}
+{{ $other := resources.Get "css/other.css" }}
+{{ $img := resources.Get "images/logo.png" }}
+CSS: {{ $css.RelPermalink }}|{{ $other.RelPermalink }}|{{ $img.RelPermalink }}
+`)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/index.html", "CSS: /css/main.min.css|/css/other.css|/images/logo.png")
+
+	for _, filename := range []string{"public/css/main.min.css.gz", "public/css/main.min.css.br", "public/css/other.css.gz"} {
+		b.Assert(b.CheckExists(filename), qt.Equals, true, qt.Commentf(filename))
+	}
+	b.Assert(b.CheckExists("public/images/logo.png.gz"), qt.Equals, false)
+}
+
+func TestSCSSTranspilerFromConfig(t *testing.T) {
+	newBuilder := func(t testing.TB, transpiler string) *sitesBuilder {
+		b := newTestSitesBuilder(t)
+		b.WithConfigFile("toml", fmt.Sprintf(`
+baseURL = "https://example.org"
+[sass]
+transpiler = %q
+`, transpiler))
+		b.WithContent("p1.md", "---\ntitle: P1\n---")
+		b.WithSourceFile("assets/scss/main.scss", `
+$moolor: #fff;
+
+moo {
+  color: $moolor;
+}
+`)
+		b.WithTemplates("index.html", `
+{{ $r := resources.Get "scss/main.scss" | toCSS }}
+T1: {{ $r.Content | safeHTML }}
+`)
+		return b
+	}
+
+	t.Run("dartsass", func(t *testing.T) {
+		b := newBuilder(t, "dartsass")
+		if dartsass.Supports() {
+			b.Build(BuildCfg{})
+			b.AssertFileContent("public/index.html", "T1: moo {", "color: #fff;")
+			return
+		}
+		// No fallback to LibSass.
+		err := b.BuildE(BuildCfg{})
+		b.Assert(err, qt.Not(qt.IsNil))
+		b.Assert(err.Error(), qt.Contains, "You need dart-sass-embedded in your system $PATH, Hugo does not bundle Dart Sass")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		b := newBuilder(t, "auto")
+		err := b.BuildE(BuildCfg{})
+		b.Assert(err, qt.ErrorMatches, `.*unsupported transpiler "auto"; valid values are "libsass" or "dartsass".*`)
+	})
+}
+
+func TestResourceChainPersistTransformations(t *testing.T) {
+	files := []string{
+		"assets/js/main.js", `const hello = "Hello"; console.log(hello);`,
+		"assets/js/other.js", `console.log("Other");`,
+	}
+
+	build := func(fs *hugofs.Fs, edits ...string) *sitesBuilder {
+		b := newTestSitesBuilder(t)
+		if fs != nil {
+			// Keep the assets from the previous build.
+			b.Fs = fs
+		} else {
+			b.WithSourceFile(files...)
+		}
+		b.WithConfigFile("toml", `
+baseURL = "https://example.org"
+[build]
+persistTransformations = true
+`)
+		b.WithContent("p1.md", "---\ntitle: P1\n---")
+		b.WithTemplates("index.html", `{{ $js := resources.Get "js/main.js" | js.Build }}JS: {{ $js.Content | safeHTML }}`)
+		b.WithSourceFile(edits...)
+		b.Build(BuildCfg{})
+		return b
+	}
+
+	b := build(nil)
+	b.AssertFileContent("public/index.html", `var hello = "Hello";`)
+
+	// Tamper with the persisted result to verify that it is reused.
+	var persisted []string
+	afero.Walk(b.Fs.Source, "", func(path string, fi os.FileInfo, err error) error {
+		if err == nil && strings.Contains(path, "_p") && strings.HasSuffix(path, ".content") {
+			persisted = append(persisted, path)
+		}
+		return nil
+	})
+	b.Assert(persisted, qt.HasLen, 1)
+	b.Assert(afero.WriteFile(b.Fs.Source, persisted[0], []byte("persisted"), 0666), qt.IsNil)
+
+	b = build(b.Fs)
+	b.AssertFileContent("public/index.html", `JS: persisted`)
+
+	// Changing any of the assets invalidates it.
+	b = build(b.Fs, "assets/js/other.js", `console.log("Other, changed");`)
+	b.AssertFileContent("public/index.html", `var hello = "Hello";`)
+}
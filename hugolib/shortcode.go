@@ -67,6 +67,9 @@ type ShortcodeWithPage struct {
 	pos       text.Position
 
 	scratch *maps.Scratch
+
+	// Values added by nested shortcodes, see AddToSlot.
+	slots map[string][]interface{}
 }
 
 // Position returns this shortcode's detailed position. Note that this information
@@ -106,6 +109,50 @@ func (scp *ShortcodeWithPage) Scratch() *maps.Scratch {
 	return scp.scratch
 }
 
+// Ancestor returns the closest enclosing shortcode with the given name,
+// or nil if none found.
+func (scp *ShortcodeWithPage) Ancestor(name string) *ShortcodeWithPage {
+	for p := scp.Parent; p != nil; p = p.Parent {
+		if p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// Lookup returns the named parameter with the given key from this shortcode,
+// or, if not set, from the closest enclosing shortcode that has it.
+// This allows a shortcode to provide values to all of its nested shortcodes,
+// e.g. a "tabs" shortcode setting a group name used by every "tab" inside it.
+func (scp *ShortcodeWithPage) Lookup(key string) interface{} {
+	for p := scp; p != nil; p = p.Parent {
+		if m, ok := p.Params.(map[string]interface{}); ok {
+			if v, found := m[key]; found {
+				return v
+			}
+		}
+	}
+	return nil
+}
+
+// AddToSlot appends v to the slot with the given name in this shortcode.
+// Nested shortcodes are rendered before their parent, so this can be used to
+// pass values up, e.g. {{ .Parent.AddToSlot "tabs" (dict "title" (.Get "title")) }}.
+// The parent template can then iterate over them with {{ range .Slot "tabs" }}.
+func (scp *ShortcodeWithPage) AddToSlot(name string, v interface{}) string {
+	if scp.slots == nil {
+		scp.slots = make(map[string][]interface{})
+	}
+	scp.slots[name] = append(scp.slots[name], v)
+	return ""
+}
+
+// Slot returns the values added to the slot with the given name, in the order
+// they were added.
+func (scp *ShortcodeWithPage) Slot(name string) []interface{} {
+	return scp.slots[name]
+}
+
 // Get is a convenience method to look up shortcode parameters by its key.
 func (scp *ShortcodeWithPage) Get(key interface{}) interface{} {
 	if scp.Params == nil {
@@ -402,6 +449,40 @@ func (s *shortcodeHandler) hasShortcodes() bool {
 	return s != nil && len(s.shortcodes) > 0
 }
 
+// shortcodeInfos returns information about all the shortcodes in this page,
+// depth first, i.e. in the order they appear in the source.
+func (s *shortcodeHandler) shortcodeInfos() page.ShortcodeInfos {
+	var infos page.ShortcodeInfos
+
+	var walk func(level int, parent string, sc *shortcode)
+	walk = func(level int, parent string, sc *shortcode) {
+		info := page.ShortcodeInfo{
+			Name:     sc.name,
+			Params:   sc.params,
+			IsInline: sc.isInline,
+			Ordinal:  sc.ordinal,
+			Level:    level,
+			Parent:   parent,
+		}
+		if sc.params != nil {
+			info.IsNamedParams = reflect.TypeOf(sc.params).Kind() == reflect.Map
+		}
+		infos = append(infos, info)
+
+		for _, inner := range sc.inner {
+			if nested, ok := inner.(*shortcode); ok {
+				walk(level+1, sc.name, nested)
+			}
+		}
+	}
+
+	for _, sc := range s.shortcodes {
+		walk(0, "", sc)
+	}
+
+	return infos
+}
+
 func (s *shortcodeHandler) renderShortcodesForPage(p *pageState, f output.Format) (map[string]string, bool, error) {
 	rendered := make(map[string]string)
 
@@ -633,6 +714,55 @@ func replaceShortcodeTokens(source []byte, replacements map[string]string) ([]by
 	return source, nil
 }
 
+// RenderShortcode renders the named shortcode with the given params, a map
+// for named and a slice for positional params, and inner content. The
+// shortcode's .Page is p, or the home page if p is nil.
+func (s *SiteInfo) RenderShortcode(p page.Page, name string, params interface{}, inner string) (template.HTML, error) {
+	ps := s.s.home
+	if p != nil {
+		pp, err := unwrapPage(p)
+		if err != nil {
+			return "", err
+		}
+		var ok bool
+		if ps, ok = pp.(*pageState); !ok {
+			return "", errors.Errorf("shortcode %q: %T is not a content page", name, p)
+		}
+	}
+	if ps == nil {
+		return "", errors.Errorf("shortcode %q: no page to render the shortcode with", name)
+	}
+
+	f := output.HTMLFormat
+	if ps.pageOutput != nil {
+		f = ps.outputFormat()
+	}
+
+	tmpl, found, _ := s.s.Tmpl().LookupVariant(name, tpl.TemplateVariants{
+		Language:     ps.Language().Lang,
+		OutputFormat: f,
+	})
+	if !found {
+		return "", errors.Errorf("shortcode %q not found", name)
+	}
+
+	data := &ShortcodeWithPage{
+		Params: params,
+		Inner:  template.HTML(inner),
+		Page:   newPageForShortcode(ps),
+		Name:   name,
+	}
+	if params != nil {
+		data.IsNamedParams = reflect.TypeOf(params).Kind() == reflect.Map
+	}
+
+	res, err := renderShortcodeWithPage(s.s.Tmpl(), tmpl, data)
+	if err != nil {
+		return "", errors.Wrapf(err, "shortcode %q", name)
+	}
+	return template.HTML(res), nil
+}
+
 func renderShortcodeWithPage(h tpl.TemplateHandler, tmpl tpl.Template, data *ShortcodeWithPage) (string, error) {
 	buffer := bp.GetBuffer()
 	defer bp.PutBuffer(buffer)
@@ -1399,3 +1399,109 @@ outputs: ["html", "css", "csv", "json"]
 
 	}
 }
+
+func TestShortcodeInfos(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t)
+
+	b.WithContent("page.md", `---
+title: "Shortcodes"
+---
+
+{{< gallery dir="images" >}}{{< figure "a.jpg" >}}{{< /gallery >}}
+
+{{< figure "b.jpg" >}}
+
+`).WithTemplatesAdded(
+		"layouts/shortcodes/gallery.html", `Gallery: {{ .Inner }}`,
+		"layouts/shortcodes/figure.html", `Figure`,
+		"layouts/_default/single.html", `
+HasGallery: {{ .HasShortcode "gallery" }}
+{{ range .Shortcodes }}
+SC: {{ .Name }}|{{ .Level }}|{{ .Ordinal }}|{{ .Parent }}|{{ .IsNamedParams }}|{{ .Get "dir" }}{{ .Get 0 }}|
+{{ end }}
+Figures: {{ len (.Shortcodes.ByName "figure") }}
+`)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/page/index.html",
+		"HasGallery: true",
+		"SC: gallery|0|0||true|images|",
+		"SC: figure|1|0|gallery|false|a.jpg|",
+		"SC: figure|0|1||false|b.jpg|",
+		"Figures: 2",
+	)
+}
+
+func TestShortcodeNestedContext(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t)
+
+	b.WithContent("page.md", `---
+title: "Tabs"
+---
+
+{{< tabs group="os" >}}
+{{< tab title="Linux" >}}{{< note >}}apt{{< /note >}}{{< /tab >}}
+{{< tab title="Mac" >}}brew{{< /tab >}}
+{{< /tabs >}}
+
+`).WithTemplatesAdded(
+		"layouts/shortcodes/tabs.html", `{{ $_ := .Inner }}{{ $group := .Get "group" }}{{ range $i, $tab := .Slot "tabs" }}Tab {{ $i }}: {{ $tab.title }}|{{ $tab.content }}|{{ $group }}
+{{ end }}`,
+		"layouts/shortcodes/tab.html", `{{ .Parent.AddToSlot "tabs" (dict "title" (.Get "title") "content" .Inner) }}`,
+		"layouts/shortcodes/note.html", `Note: {{ .Inner }} in {{ with .Ancestor "tabs" }}{{ .Name }}{{ end }} group {{ .Lookup "group" }} title {{ .Lookup "title" }}{{ with .Ancestor "nope" }}FAIL{{ end }}`,
+	)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/page/index.html",
+		"Tab 0: Linux|Note: apt in tabs group os title Linux|os",
+		"Tab 1: Mac|brew|os",
+	)
+}
+
+func TestShortcodesRenderFunc(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t)
+
+	b.WithContent("page.md", `---
+title: "P1"
+---
+
+A [link](https://example.org).
+
+{{< box title="Content" >}}Inner{{< /box >}}
+`).WithTemplatesAdded(
+		"layouts/shortcodes/box.html", `<div title="{{ .Get "title" }}">{{ .Inner }}|{{ .Page.Title }}|{{ .IsNamedParams }}</div>`,
+		"layouts/shortcodes/pos.html", `Pos: {{ .Get 0 }}-{{ .Get 1 }}`,
+		"layouts/_default/_markup/render-link.html", `{{ shortcodes.Render "box" (dict "title" .Destination) .Text . }}`,
+		"layouts/_default/single.html", `
+Named: {{ shortcodes.Render "box" (dict "title" "Layout") "Layout inner" . }}|
+Positional: {{ shortcodes.Render "pos" (slice "a" "b") }}|
+Content: {{ .Content }}`,
+		"layouts/index.html", `Home: {{ shortcodes.Render "box" }}`,
+	)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/page/index.html",
+		`Named: <div title="Layout">Layout inner|P1|true</div>|`,
+		"Positional: Pos: a-b|",
+		`<p>A <div title="https://example.org">link|P1|true</div>.</p>`,
+		`<div title="Content">Inner|P1|true</div>`,
+	)
+
+	b.AssertFileContent("public/index.html", `Home: <div title="">||false</div>`)
+
+	b = newTestSitesBuilder(t)
+	b.WithContent("page.md", "---\ntitle: P1\n---\n")
+	b.WithTemplatesAdded("layouts/_default/single.html", `{{ shortcodes.Render "nope" . }}`)
+	err := b.BuildE(BuildCfg{})
+	b.Assert(err, qt.Not(qt.IsNil))
+	b.Assert(err.Error(), qt.Contains, `shortcode "nope" not found`)
+}
@@ -14,6 +14,7 @@
 package hugolib
 
 import (
+	"bufio"
 	"fmt"
 	"html/template"
 	"io"
@@ -57,6 +58,7 @@ import (
 
 	"github.com/gohugoio/hugo/common/hugo"
 	"github.com/gohugoio/hugo/publisher"
+	"github.com/gohugoio/hugo/transform/resourcehints"
 	_errors "github.com/pkg/errors"
 
 	"github.com/gohugoio/hugo/langs"
@@ -64,6 +66,13 @@ import (
 	"github.com/gohugoio/hugo/resources/page"
 
 	"github.com/gohugoio/hugo/config"
+	"github.com/gohugoio/hugo/config/contentapi"
+	"github.com/gohugoio/hugo/config/llms"
+	"github.com/gohugoio/hugo/config/nextprev"
+	"github.com/gohugoio/hugo/config/projections"
+	"github.com/gohugoio/hugo/config/sequences"
+	"github.com/gohugoio/hugo/config/series"
+	"github.com/gohugoio/hugo/config/weights"
 	"github.com/gohugoio/hugo/lazy"
 
 	"github.com/gohugoio/hugo/media"
@@ -76,6 +85,7 @@ import (
 	"github.com/gohugoio/hugo/output"
 	"github.com/gohugoio/hugo/related"
 	"github.com/gohugoio/hugo/resources/page/pagemeta"
+	"github.com/gohugoio/hugo/search"
 	"github.com/gohugoio/hugo/source"
 	"github.com/gohugoio/hugo/tpl"
 
@@ -168,6 +178,9 @@ type Site struct {
 	// The last modification date of this site.
 	lastmod time.Time
 
+	// The rendered blocks of the types with caching enabled.
+	blocksCache *blocksCache
+
 	// Lazily loaded site dependencies
 	init *siteInit
 }
@@ -193,16 +206,30 @@ func (t taxonomiesConfig) Values() []viewName {
 
 type siteConfigHolder struct {
 	sitemap          config.Sitemap
+	llms             llms.Config
+	contentAPI       contentapi.Config
+	projections      map[string]projections.Config
+	searchIndex      search.Config
 	taxonomiesConfig taxonomiesConfig
 	timeout          time.Duration
 	hasCJKLanguage   bool
 	enableEmoji      bool
+
+	// The default front matter values per content type.
+	frontMatterDefaults map[string]maps.Params
+	paramTypes          pagemeta.ParamTypes
+	blockSchemas        pagemeta.BlockSchemas
+	weights             map[string]weights.Config
+	nextPrev            map[string]nextprev.Config
+	sequences           map[string]sequences.Config
 }
 
 // Lazily loaded site dependencies.
 type siteInit struct {
 	prevNext          *lazy.Init
 	prevNextInSection *lazy.Init
+	prevNextInSeries  *lazy.Init
+	sequences         *lazy.Init
 	menus             *lazy.Init
 	taxonomies        *lazy.Init
 }
@@ -210,6 +237,8 @@ type siteInit struct {
 func (init *siteInit) Reset() {
 	init.prevNext.Reset()
 	init.prevNextInSection.Reset()
+	init.prevNextInSeries.Reset()
+	init.sequences.Reset()
 	init.menus.Reset()
 	init.taxonomies.Reset()
 }
@@ -251,6 +280,27 @@ func (s *Site) prepareInits() {
 				pos.prevPage = regularPages[i+1]
 			}
 		}
+
+		if nextPrevCfg := s.siteCfg.nextPrev; len(nextPrevCfg) > 0 {
+			// Navigate within the configured sections.
+			var keys []string
+			scoped := make(map[string]page.Pages)
+			for _, p := range regularPages {
+				key, _, found := nextPrevConfigFor(nextPrevCfg, p.SectionsPath())
+				if !found {
+					continue
+				}
+				if _, found := scoped[key]; !found {
+					keys = append(keys, key)
+				}
+				scoped[key] = append(scoped[key], p)
+			}
+
+			for _, key := range keys {
+				setNextPrevConfigured(scoped[key], nextPrevCfg[key], getNextPrev)
+			}
+		}
+
 		return nil, nil
 	})
 
@@ -260,7 +310,12 @@ func (s *Site) prepareInits() {
 			sections = append(sections, n.p)
 		})
 
-		setNextPrev := func(pas page.Pages) {
+		setNextPrev := func(sectionPath string, pas page.Pages) {
+			if _, c, found := nextPrevConfigFor(s.siteCfg.nextPrev, sectionPath); found {
+				setNextPrevConfigured(pas, c, getNextPrevInSection)
+				return
+			}
+
 			for i, p := range pas {
 				np, ok := p.(nextPrevInSectionProvider)
 				if !ok {
@@ -294,7 +349,7 @@ func (s *Site) prepareInits() {
 			})
 			page.SortByDefault(pas)
 
-			setNextPrev(pas)
+			setNextPrev(sect.SectionsPath(), pas)
 		}
 
 		// The root section only goes one level down.
@@ -306,11 +361,70 @@ func (s *Site) prepareInits() {
 		})
 		page.SortByDefault(pas)
 
-		setNextPrev(pas)
+		setNextPrev("", pas)
 
 		return nil, nil
 	})
 
+	s.init.prevNextInSeries = init.Branch(func() (interface{}, error) {
+		cfg, err := series.DecodeConfig(s.Cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		var names []string
+		series := make(map[string]page.Pages)
+		for _, p := range s.RegularPages() {
+			name := seriesName(p)
+			if name == "" {
+				continue
+			}
+			if _, found := series[name]; !found {
+				names = append(names, name)
+			}
+			series[name] = append(series[name], p)
+		}
+
+		for _, name := range names {
+			pas := series[name]
+			if cfg.OrderBy == "weight" {
+				pas = pas.ByWeight()
+			} else {
+				pas = pas.ByDate()
+			}
+
+			for i, p := range pas {
+				np, ok := p.(nextPrevInSeriesProvider)
+				if !ok {
+					continue
+				}
+
+				pos := np.getNextPrevInSeries()
+				if pos == nil {
+					continue
+				}
+
+				pos.pages = pas
+				pos.nextPage = nil
+				pos.prevPage = nil
+
+				if i > 0 {
+					pos.prevPage = pas[i-1]
+				}
+
+				if i < len(pas)-1 {
+					pos.nextPage = pas[i+1]
+				}
+			}
+		}
+
+		return nil, nil
+	})
+
+	s.init.sequences = init.Branch(func() (interface{}, error) {
+		return nil, s.assembleSequences()
+	})
+
 	s.init.menus = init.Branch(func() (interface{}, error) {
 		s.assembleMenus()
 		return nil, nil
@@ -397,6 +511,7 @@ func (s *Site) reset() *Site {
 		init:                   s.init,
 		PageCollections:        s.PageCollections,
 		siteCfg:                s.siteCfg,
+		blocksCache:            s.blocksCache,
 	}
 }
 
@@ -473,6 +588,16 @@ But this also means that your site configuration may not do what you expect. If
 		siteOutputFormatsConfig = tmp
 	}
 
+	projectionsConfig, err := projections.DecodeConfig(cfg.Language)
+	if err != nil {
+		return nil, err
+	}
+	for i, f := range siteOutputFormatsConfig {
+		if _, found := projectionsConfig[strings.ToLower(f.Name)]; found {
+			siteOutputFormatsConfig[i].Projected = true
+		}
+	}
+
 	var siteOutputs map[string]interface{}
 	if cfg.Language.IsSet("outputs") {
 		siteOutputs = cfg.Language.GetStringMap("outputs")
@@ -517,6 +642,11 @@ But this also means that your site configuration may not do what you expect. If
 		}
 	}
 
+	searchIndexConfig, err := search.DecodeConfig(cfg.Language.GetParams("searchIndex"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode searchIndex config")
+	}
+
 	titleFunc := helpers.GetTitleFunc(cfg.Language.GetString("titleCaseStyle"))
 
 	frontMatterHandler, err := pagemeta.NewFrontmatterHandler(cfg.Logger, cfg.Cfg)
@@ -533,12 +663,55 @@ But this also means that your site configuration may not do what you expect. If
 		}
 	}
 
+	contentAPIConfig := contentapi.DecodeConfig(cfg.Language)
+
+	frontMatterDefaults, err := pagemeta.DecodeDefaultsConfig(cfg.Language.GetStringMap("frontMatterDefaults"))
+	if err != nil {
+		return nil, err
+	}
+
+	paramTypes, err := pagemeta.DecodeParamTypesConfig(cfg.Language.GetStringMap("paramTypes"))
+	if err != nil {
+		return nil, err
+	}
+
+	blockSchemas, err := pagemeta.DecodeBlockSchemasConfig(cfg.Language.GetStringMap("blocks"))
+	if err != nil {
+		return nil, err
+	}
+
+	weightsConfig, err := weights.DecodeConfig(cfg.Language)
+	if err != nil {
+		return nil, err
+	}
+
+	nextPrevConfig, err := nextprev.DecodeConfig(cfg.Language)
+	if err != nil {
+		return nil, err
+	}
+
+	sequencesConfig, err := sequences.DecodeConfig(cfg.Language)
+	if err != nil {
+		return nil, err
+	}
+
 	siteConfig := siteConfigHolder{
 		sitemap:          config.DecodeSitemap(config.Sitemap{Priority: -1, Filename: "sitemap.xml"}, cfg.Language.GetStringMap("sitemap")),
+		llms:             llms.DecodeConfig(cfg.Language),
+		searchIndex:      searchIndexConfig,
+		contentAPI:       contentAPIConfig,
+		projections:      projectionsConfig,
 		taxonomiesConfig: taxonomies,
 		timeout:          timeout,
 		hasCJKLanguage:   cfg.Language.GetBool("hasCJKLanguage"),
 		enableEmoji:      cfg.Language.Cfg.GetBool("enableEmoji"),
+
+		frontMatterDefaults: frontMatterDefaults,
+		paramTypes:          paramTypes,
+		blockSchemas:        blockSchemas,
+		weights:             weightsConfig,
+		nextPrev:            nextPrevConfig,
+		sequences:           sequencesConfig,
 	}
 
 	var siteBucket *pagesMapBucket
@@ -802,7 +975,9 @@ func (s *SiteInfo) IsMultiLingual() bool {
 }
 
 func (s *SiteInfo) IsServer() bool {
-	return s.owner.running
+	// The build server keeps the sites running between builds, but its
+	// builds are meant to be published.
+	return s.owner.running && !s.s.Cfg.GetBool("buildServer")
 }
 
 type siteRefLinker struct {
@@ -810,6 +985,10 @@ type siteRefLinker struct {
 
 	errorLogger *log.Logger
 	notFoundURL string
+
+	// The languages to look in, in order, for refs not translated to this
+	// site's language.
+	fallbackLangs []string
 }
 
 func newSiteRefLinker(cfg config.Provider, s *Site) (siteRefLinker, error) {
@@ -820,7 +999,8 @@ func newSiteRefLinker(cfg config.Provider, s *Site) (siteRefLinker, error) {
 	if strings.EqualFold(errLevel, "warning") {
 		logger = s.Log.Warn()
 	}
-	return siteRefLinker{s: s, errorLogger: logger, notFoundURL: notFoundURL}, nil
+	fallbackLangs := cfg.GetStringSlice("refLinksLanguageFallback")
+	return siteRefLinker{s: s, errorLogger: logger, notFoundURL: notFoundURL, fallbackLangs: fallbackLangs}, nil
 }
 
 func (s siteRefLinker) logNotFound(ref, what string, p page.Page, position text.Position) {
@@ -833,6 +1013,75 @@ func (s siteRefLinker) logNotFound(ref, what string, p page.Page, position text.
 	}
 }
 
+func (s siteRefLinker) logUntranslated(ref, lang string, p page.Page, position text.Position) {
+	what := fmt.Sprintf("not translated, linking to the %q translation", lang)
+	if position.IsValid() {
+		s.s.Log.Warnf("[%s] REF_UNTRANSLATED: Ref %q: %s: %s", s.s.Lang(), ref, position.String(), what)
+	} else if p == nil {
+		s.s.Log.Warnf("[%s] REF_UNTRANSLATED: Ref %q: %s", s.s.Lang(), ref, what)
+	} else {
+		s.s.Log.Warnf("[%s] REF_UNTRANSLATED: Ref %q from page %q: %s", s.s.Lang(), ref, p.Path(), what)
+	}
+}
+
+// getPageRefInOtherLanguage looks up a ref not found in this site's language.
+// A ref to a content file with a language code, e.g. "post.fr.md", is looked
+// up in that language, else the languages in refLinksLanguageFallback are
+// tried in order. It returns the site the ref was found in and whether it's
+// a fallback.
+func (s *siteRefLinker) getPageRefInOtherLanguage(p page.Page, ref string) (page.Page, *Site, bool, error) {
+	if s.s.h == nil {
+		return nil, nil, false, nil
+	}
+
+	siteByLang := func(lang string) *Site {
+		for _, ss := range s.s.h.Sites {
+			if ss.Lang() == lang && ss != s.s {
+				return ss
+			}
+		}
+		return nil
+	}
+
+	if ext := path.Ext(paths.PathNoExt(ref)); ext != "" {
+		if ss := siteByLang(ext[1:]); ss != nil {
+			target, err := ss.getPageRef(p, ref)
+			return target, ss, false, err
+		}
+	}
+
+	for _, lang := range s.fallbackLangs {
+		ss := siteByLang(lang)
+		if ss == nil {
+			continue
+		}
+		target, err := ss.getPageRef(p, ref)
+		if err != nil || target != nil {
+			return target, ss, true, err
+		}
+	}
+
+	return nil, nil, false, nil
+}
+
+// translatedLangs returns the other languages ref can be found in.
+func (s *siteRefLinker) translatedLangs(p page.Page, ref string) []string {
+	if s.s.h == nil {
+		return nil
+	}
+
+	var langs []string
+	for _, ss := range s.s.h.Sites {
+		if ss == s.s {
+			continue
+		}
+		if target, err := ss.getPageRef(p, ref); err == nil && target != nil {
+			langs = append(langs, ss.Lang())
+		}
+	}
+	return langs
+}
+
 func (s *siteRefLinker) refLink(ref string, source interface{}, relative bool, outputFormat string) (string, error) {
 	p, err := unwrapPage(source)
 	if err != nil {
@@ -854,9 +1103,15 @@ func (s *siteRefLinker) refLink(ref string, source interface{}, relative bool, o
 
 	if refURL.Path != "" {
 		var err error
+		var fallback *Site
+		var isFallback bool
 		target, err = s.s.getPageRef(p, refURL.Path)
+		if err == nil && target == nil {
+			target, fallback, isFallback, err = s.getPageRefInOtherLanguage(p, refURL.Path)
+		}
+
 		var pos text.Position
-		if err != nil || target == nil {
+		if err != nil || target == nil || isFallback {
 			if p, ok := source.(text.Positioner); ok {
 				pos = p.Position()
 			}
@@ -868,10 +1123,18 @@ func (s *siteRefLinker) refLink(ref string, source interface{}, relative bool, o
 		}
 
 		if target == nil {
-			s.logNotFound(refURL.Path, "page not found", p, pos)
+			what := "page not found"
+			if langs := s.translatedLangs(p, refURL.Path); len(langs) > 0 {
+				what = fmt.Sprintf("page not translated, found in %s", strings.Join(langs, ", "))
+			}
+			s.logNotFound(refURL.Path, what, p, pos)
 			return s.notFoundURL, nil
 		}
 
+		if isFallback {
+			s.logUntranslated(refURL.Path, fallback.Lang(), p, pos)
+		}
+
 		var permalinker Permalinker = target
 
 		if outputFormat != "" {
@@ -923,6 +1186,18 @@ func (s *Site) multilingual() *Multilingual {
 type whatChanged struct {
 	source bool
 	files  map[string]bool
+
+	// Set on rebuilds where only content files changed. Holds the list
+	// states of the pages in the changed files before the rebuild.
+	listsBefore map[string][]pageListState
+
+	// Whether all list pages are re-rendered. If not, only the list pages
+	// in listsAffected are, see HugoSites.setListsAffected.
+	allListsAffected bool
+
+	// The RelPermalinks of the list pages to re-render, set from
+	// listsBefore when the pages are assembled.
+	listsAffected map[string]bool
 }
 
 // RegisterMediaTypes will register the Site's media types in the mime
@@ -1097,8 +1372,25 @@ func (s *Site) processPartial(config *BuildCfg, init func(config *BuildCfg) erro
 	}
 
 	changed := &whatChanged{
-		source: len(sourceChanged) > 0,
-		files:  sourceFilesChanged,
+		source:           len(sourceChanged) > 0,
+		files:            sourceFilesChanged,
+		allListsAffected: true,
+	}
+
+	if len(sourceChanged) > 0 && len(sourceChanged) == len(events) {
+		// Only content changed. Record the list state of the changed pages
+		// so we can skip re-rendering the lists not affected.
+		filenames := make(map[string]bool)
+		for _, ev := range sourceChanged {
+			if !files.IsContentFile(ev.Name) {
+				filenames = nil
+				break
+			}
+			filenames[ev.Name] = true
+		}
+		if filenames != nil {
+			changed.listsBefore = h.pageListStates(filenames)
+		}
 	}
 
 	config.whatChanged = changed
@@ -1214,12 +1506,20 @@ func (s *Site) process(config BuildCfg) (err error) {
 	return err
 }
 
-func (s *Site) render(ctx *siteRenderContext) (err error) {
+// renderBeforePages renders the redirects and aliases, which the pages will
+// overwrite if they share a target.
+func (s *Site) renderBeforePages(ctx *siteRenderContext) (err error) {
 	if err := page.Clear(); err != nil {
 		return err
 	}
 
 	if ctx.outIdx == 0 {
+		// Must be done before the aliases are rendered, so front matter
+		// aliases win over the redirects for moved pages.
+		if err = s.renderPageIDRedirects(); err != nil {
+			return
+		}
+
 		// Note that even if disableAliases is set, the aliases themselves are
 		// preserved on page. The motivation with this is to be able to generate
 		// 301 redirects in a .htacess file and similar using a custom output format.
@@ -1234,10 +1534,11 @@ func (s *Site) render(ctx *siteRenderContext) (err error) {
 		}
 	}
 
-	if err = s.renderPages(ctx); err != nil {
-		return
-	}
+	return
+}
 
+// renderAfterPages renders the sitemap, 404 page and the other singletons.
+func (s *Site) renderAfterPages(ctx *siteRenderContext) (err error) {
 	if ctx.outIdx == 0 {
 		if err = s.renderSitemap(); err != nil {
 			return
@@ -1252,6 +1553,10 @@ func (s *Site) render(ctx *siteRenderContext) (err error) {
 		if err = s.render404(); err != nil {
 			return
 		}
+
+		if err = s.renderLLMsTXT(); err != nil {
+			return
+		}
 	}
 
 	if !ctx.renderSingletonPages() {
@@ -1355,7 +1660,7 @@ func (s *Site) initializeSiteInfo() error {
 		permalinks:                     permalinks,
 		owner:                          s.h,
 		s:                              s,
-		hugoInfo:                       hugo.NewInfo(s.Cfg.GetString("environment")),
+		hugoInfo:                       hugo.NewInfoWithBuildContext(s.Cfg, s.Cfg.GetString("environment"), s.Cfg.GetString("workingDir"), s.ResourceSpec.BuildConfig.Env),
 	}
 
 	rssOutputFormat, found := s.outputFormats[page.KindHome].GetByName(output.RSSFormat.Name)
@@ -1620,6 +1925,15 @@ func (s *SiteInfo) GetPage(ref ...string) (page.Page, error) {
 	return p, err
 }
 
+// GetPages returns the pages with a path matching the given Glob pattern,
+// e.g. "docs/**/install*". The pattern is matched against the page's path
+// in the content dir with and without the file extension, e.g.
+// "docs/v1/install.md" and "docs/v1/install". For bundles, the latter is the
+// bundle's directory.
+func (s *SiteInfo) GetPages(pattern string) (page.Pages, error) {
+	return s.s.getPagesMatching(pattern)
+}
+
 func (s *SiteInfo) GetPageWithTemplateInfo(info tpl.Info, ref ...string) (page.Page, error) {
 	p, err := s.GetPage(ref...)
 	if p != nil {
@@ -1685,11 +1999,25 @@ func (s *Site) renderAndWriteXML(statCounter *uint64, name string, targetPath st
 
 func (s *Site) renderAndWritePage(statCounter *uint64, name string, targetPath string, p *pageState, templ tpl.Template) error {
 	s.Log.Debugf("Render %s to %q", name, targetPath)
-	renderBuffer := bp.GetBuffer()
-	defer bp.PutBuffer(renderBuffer)
 
 	of := p.outputFormat()
 
+	attestations := s.h.attestations
+	var attestation pageAttestation
+	if attestations.cfg.Enable {
+		attestation = attestations.attest(p, targetPath, templ)
+	}
+
+	if of.Stream {
+		if attestations.cfg.Enable {
+			attestations.add(attestation)
+		}
+		return s.renderAndStreamPage(statCounter, targetPath, p, templ)
+	}
+
+	renderBuffer := bp.GetBuffer()
+	defer bp.PutBuffer(renderBuffer)
+
 	if err := s.renderForTemplate(p.Kind(), of.Name, p, renderBuffer, templ); err != nil {
 		return err
 	}
@@ -1699,20 +2027,49 @@ func (s *Site) renderAndWritePage(statCounter *uint64, name string, targetPath s
 	}
 
 	isHTML := of.IsHTML
+
+	if attestations.cfg.Enable {
+		attestations.add(attestation)
+		if isHTML {
+			b := attestations.embed(renderBuffer.Bytes(), attestation)
+			renderBuffer.Reset()
+			renderBuffer.Write(b)
+		}
+	}
 	isRSS := of.Name == "RSS"
 
 	pd := publisher.Descriptor{
-		Src:          renderBuffer,
-		TargetPath:   targetPath,
-		StatCounter:  statCounter,
-		OutputFormat: p.outputFormat(),
+		Src:           renderBuffer,
+		TargetPath:    targetPath,
+		StatCounter:   statCounter,
+		OutputFormat:  p.outputFormat(),
+		OnContentHash: s.publishManifestHandler(targetPath),
+	}
+
+	if resourcehints.Contains(renderBuffer.Bytes()) {
+		if isHTML {
+			pd.ResourceHints = true
+			if p.paginator != nil && p.paginator.current != nil {
+				if next := p.paginator.current.Next(); next != nil {
+					pd.NextPageURL = string(next.URL())
+				}
+			}
+		} else {
+			// Resource hints are only supported in HTML.
+			b := resourcehints.Strip(renderBuffer.Bytes())
+			renderBuffer.Reset()
+			renderBuffer.Write(b)
+		}
 	}
 
 	if isRSS {
 		// Always canonify URLs in RSS
 		pd.AbsURLPath = s.absURLPath(targetPath)
 	} else if isHTML {
-		if s.Info.relativeURLs || s.Info.canonifyURLs {
+		if of.IsEmail {
+			// Relative URLs do not work in email clients.
+			pd.AbsURLPath = strings.TrimSuffix(s.PathSpec.BaseURL.String(), "/") + "/"
+		} else if s.Info.relativeURLs || s.Info.canonifyURLs {
 			pd.AbsURLPath = s.absURLPath(targetPath)
 		}
 
@@ -1733,6 +2090,45 @@ func (s *Site) renderAndWritePage(statCounter *uint64, name string, targetPath s
 	return s.publisher.Publish(pd)
 }
 
+// renderAndStreamPage renders p for output formats with Stream set, piping the
+// template output to the publisher so the full output is never held in memory.
+func (s *Site) renderAndStreamPage(statCounter *uint64, targetPath string, p *pageState, templ tpl.Template) error {
+	of := p.outputFormat()
+
+	pr, pw := io.Pipe()
+	renderErr := make(chan error, 1)
+
+	go func() {
+		err := s.renderForTemplate(p.Kind(), of.Name, p, pw, templ)
+		pw.CloseWithError(err)
+		renderErr <- err
+	}()
+
+	src := bufio.NewReader(pr)
+	if _, err := src.Peek(1); err != nil {
+		// Empty output (io.EOF) or a render error.
+		return <-renderErr
+	}
+
+	err := s.publisher.Publish(publisher.Descriptor{
+		Src:           src,
+		TargetPath:    targetPath,
+		StatCounter:   statCounter,
+		OutputFormat:  of,
+		OnContentHash: s.publishManifestHandler(targetPath),
+	})
+
+	// Unblock the renderer if publishing failed.
+	pr.CloseWithError(err)
+	rerr := <-renderErr
+
+	if err != nil {
+		return err
+	}
+
+	return rerr
+}
+
 var infoOnMissingLayout = map[string]bool{
 	// The 404 layout is very much optional in Hugo, but we do look for it.
 	"404": true,
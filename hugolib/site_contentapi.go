@@ -0,0 +1,124 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"strings"
+
+	"github.com/gohugoio/hugo/output"
+	"github.com/gohugoio/hugo/resources/page"
+	"github.com/pkg/errors"
+)
+
+// ContentAPI returns the helper used by the built-in templates for the
+// ContentAPI output format.
+func (s *SiteInfo) ContentAPI() ContentAPI {
+	return ContentAPI{s: s.s}
+}
+
+// ContentAPI builds the JSON documents for the ContentAPI output format,
+// using the contentAPI site config to select the fields.
+type ContentAPI struct {
+	s *Site
+}
+
+// PageSize returns the configured number of pages per collection page.
+func (a ContentAPI) PageSize() int {
+	if a.s.siteCfg.contentAPI.PageSize > 0 {
+		return a.s.siteCfg.contentAPI.PageSize
+	}
+	return a.s.Language().GetInt("paginate")
+}
+
+// Document creates the document for a single page.
+func (a ContentAPI) Document(p page.Page) (map[string]interface{}, error) {
+	return a.fields(p, a.s.siteCfg.contentAPI.Fields)
+}
+
+// Collection creates the document for a list page. The pages listed are the
+// ones in the given pager, which must be created with .Paginate.
+func (a ContentAPI) Collection(p page.Page, pager *page.Pager) (map[string]interface{}, error) {
+	doc, err := a.fields(p, []string{"kind", "type", "section", "title", "permalink"})
+	if err != nil {
+		return nil, err
+	}
+
+	var sections []map[string]interface{}
+	for _, sect := range p.Sections() {
+		sections = append(sections, map[string]interface{}{
+			"title": sect.Title(),
+			"api":   a.apiPermalink(sect),
+		})
+	}
+	if sections != nil {
+		doc["sections"] = sections
+	}
+
+	if pager == nil {
+		return doc, nil
+	}
+
+	items := make([]map[string]interface{}, 0)
+	for _, pp := range pager.Pages() {
+		item, err := a.fields(pp, a.s.siteCfg.contentAPI.ListFields)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	doc["items"] = items
+
+	pagination := map[string]interface{}{
+		"pageNumber": pager.PageNumber(),
+		"pageSize":   pager.PageSize(),
+		"totalPages": pager.TotalPages(),
+		"totalItems": pager.TotalNumberOfElements(),
+	}
+	if pager.HasPrev() {
+		pagination["prev"] = a.s.PathSpec.AbsURL(string(pager.Prev().URL()), false)
+	}
+	if pager.HasNext() {
+		pagination["next"] = a.s.PathSpec.AbsURL(string(pager.Next().URL()), false)
+	}
+	doc["pagination"] = pagination
+
+	return doc, nil
+}
+
+func (a ContentAPI) fields(p page.Page, fields []string) (map[string]interface{}, error) {
+	doc := make(map[string]interface{})
+
+	if api := a.apiPermalink(p); api != "" {
+		doc["api"] = api
+	}
+
+	for _, field := range fields {
+		v, err := pageFieldValue(p, strings.ToLower(field))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get field %q for %q", field, p.Path())
+		}
+		if v != nil {
+			doc[field] = v
+		}
+	}
+
+	return doc, nil
+}
+
+func (a ContentAPI) apiPermalink(p page.Page) string {
+	if f := p.OutputFormats().Get(output.ContentAPIFormat.Name); f != nil {
+		return f.Permalink()
+	}
+	return ""
+}
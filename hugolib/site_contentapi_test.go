@@ -0,0 +1,75 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"testing"
+)
+
+func TestContentAPIOutputFormat(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "https://example.org/"
+[contentAPI]
+fields = ["title", "permalink", "content", "tags"]
+listFields = ["title"]
+pageSize = 2
+[outputs]
+home = ["html", "contentapi"]
+section = ["html", "contentapi"]
+page = ["html", "contentapi"]
+`)
+
+	for _, name := range []string{"p1", "p2", "p3"} {
+		b.WithContent("posts/"+name+".md", `---
+title: "`+name+`"
+weight: 1
+tags: ["a", "b"]
+---
+Content of `+name+`.
+`)
+	}
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/api/posts/p1/index.json",
+		`"api":"https://example.org/api/posts/p1/index.json"`,
+		`"content":"\u003cp\u003eContent of p1.\u003c/p\u003e\n"`,
+		`"permalink":"https://example.org/posts/p1/"`,
+		`"tags":["a","b"]`,
+		`"title":"p1"`,
+	)
+
+	b.AssertFileContent("public/api/index.json",
+		`"kind":"home"`,
+		`"sections":[{"api":"https://example.org/api/posts/index.json","title":"Posts"}]`,
+		`"items":[{"api":"https://example.org/api/posts/p1/index.json","title":"p1"},{"api":"https://example.org/api/posts/p2/index.json","title":"p2"}]`,
+		`"pagination":{"next":"https://example.org/api/page/2/index.json","pageNumber":1,"pageSize":2,"totalItems":3,"totalPages":2}`,
+	)
+
+	b.AssertFileContent("public/api/page/2/index.json",
+		`"items":[{"api":"https://example.org/api/posts/p3/index.json","title":"p3"}]`,
+		`"prev":"https://example.org/api/index.json"`,
+	)
+
+	b.AssertFileContent("public/api/posts/index.json",
+		`"kind":"section"`,
+		`"totalItems":3`,
+		`"next":"https://example.org/api/posts/page/2/index.json"`,
+	)
+	b.AssertFileContent("public/api/posts/page/2/index.json",
+		`"prev":"https://example.org/api/posts/index.json"`,
+	)
+}
@@ -0,0 +1,94 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"testing"
+)
+
+func TestEmailOutputFormats(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "https://example.org/"
+relativeURLs = true
+[outputs]
+section = ["html", "email", "emailtext"]
+page = ["html", "email", "emailtext"]
+`)
+
+	b.WithContent("newsletter/_index.md", `---
+title: "Newsletter"
+---
+`, "newsletter/issue-1.md", `---
+title: "Issue 1"
+---
+Hello **readers**, see [the news](/news/).
+
+![Logo](/images/logo.png)
+`, "digest/_index.md", `---
+title: "Digest"
+---
+`, "digest/issue-2/index.md", `---
+title: "Issue 2"
+---
+Second issue.
+`)
+
+	b.WithTemplatesAdded("newsletter/single.email.html", `<!DOCTYPE html>
+<html><head><style>
+p { margin: 0 0 16px }
+.lead { font-size: 20px }
+a:hover { color: red }
+</style></head>
+<body>{{ template "_internal/email/container_start.html" (dict "width" 500) }}<p class="lead">{{ .Title }}</p>{{ .Content }}{{ template "_internal/email/container_end.html" }}</body></html>`)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/newsletter/issue-1/email.html",
+		`<style>a:hover{color:red;}</style>`,
+		`<table role="presentation" width="500"`,
+		`style="width:100%;max-width:500px;background-color:#ffffff"`,
+		`<p class="lead" style="margin:0 0 16px;font-size:20px">Issue 1</p>`,
+		`<p style="margin:0 0 16px">Hello <strong>readers</strong>, see <a href="https://example.org/news/">the news</a>.</p>`,
+		`<img src="https://example.org/images/logo.png" alt="Logo"/>`,
+	)
+
+	// The built-in templates.
+	b.AssertFileContent("public/digest/issue-2/email.html",
+		`<body style="margin:0;padding:0;background-color:#f4f4f4;`,
+		`<h1 style="margin:0 0 16px;font-size:24px;line-height:1.25">Issue 2</h1>`,
+		`<a href="https://example.org/digest/issue-2/" target="_blank" style="display:inline-block;`,
+	)
+	b.AssertFileContent("public/digest/email.html",
+		`<h2 style="margin:24px 0 8px;font-size:20px;line-height:1.25"><a href="https://example.org/digest/issue-2/" style="color:#1a73e8">Issue 2</a></h2>`,
+	)
+	b.AssertFileContent("public/newsletter/issue-1/email.txt", `Issue 1
+
+Hello readers, see the news (/news/).
+
+Logo
+
+Read on the web: https://example.org/newsletter/issue-1/`)
+	b.AssertFileContent("public/newsletter/email.txt", `Newsletter
+
+Issue 1
+Hello readers, see the news.
+https://example.org/newsletter/issue-1/
+
+Read on the web: https://example.org/newsletter/`)
+
+	// The HTML output is not touched.
+	b.AssertFileContent("public/newsletter/issue-1/index.html", `Issue 1`)
+}
@@ -0,0 +1,74 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"fmt"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestLiteOutputFormat(t *testing.T) {
+	t.Parallel()
+
+	config := `
+baseURL = "https://example.org/"
+[outputs]
+page = ["html", "lite"]
+[lite]
+placeholders = ["iframe"]
+placeholderClass = "embed"
+maxSize = "%s"
+failOnMaxSize = true
+`
+
+	build := func(maxSize string) (*sitesBuilder, error) {
+		b := newTestSitesBuilder(t).WithConfigFile("toml", fmt.Sprintf(config, maxSize))
+		b.WithContent("posts/p1.md", `---
+title: "P1"
+---
+Some content.
+`)
+		b.WithSourceFile(
+			"static/css/static.css", `h1 { color: blue }`,
+			"assets/css/main.css", `body { background: url(bg.png) }`,
+		)
+		b.WithTemplatesAdded("_default/single.html", `<html><head>
+{{ with resources.Get "css/main.css" }}<link rel="stylesheet" href="{{ .RelPermalink }}">{{ end }}
+<link rel="stylesheet" href="/css/static.css">
+<script src="/js/app.js"></script>
+</head><body><h1>{{ .Title }}</h1><iframe src="https://example.com/map" title="Map"></iframe>{{ .Content }}</body></html>`)
+		return b, b.BuildE(BuildCfg{})
+	}
+
+	b, err := build("10KB")
+	b.Assert(err, qt.IsNil)
+
+	b.AssertFileContent("public/lite/posts/p1/index.html",
+		`<style>body { background: url("/css/bg.png") }</style>`,
+		`<style>h1 { color: blue }</style>`,
+		`<h1>P1</h1><a href="https://example.com/map" class="embed" data-lite-element="iframe">Map</a>`,
+		`<p>Some content.</p>`,
+	)
+	b.AssertFileContent("public/posts/p1/index.html",
+		`<script src="/js/app.js"></script>`,
+		`<link rel="stylesheet" href="/css/static.css">`,
+	)
+	b.Assert(b.FileContent("public/lite/posts/p1/index.html"), qt.Not(qt.Contains), "<script")
+
+	_, err = build("100B")
+	b.Assert(err, qt.Not(qt.IsNil))
+	b.Assert(err.Error(), qt.Contains, "exceeds the max size of 100 B")
+}
@@ -26,6 +26,7 @@ func createDefaultOutputFormats(allFormats output.Formats) map[string]output.For
 	rssOut, rssFound := allFormats.GetByName(output.RSSFormat.Name)
 	htmlOut, _ := allFormats.GetByName(output.HTMLFormat.Name)
 	robotsOut, _ := allFormats.GetByName(output.RobotsTxtFormat.Name)
+	llmsOut, _ := allFormats.GetByName(output.LLMsTxtFormat.Name)
 	sitemapOut, _ := allFormats.GetByName(output.SitemapFormat.Name)
 
 	defaultListTypes := output.Formats{htmlOut}
@@ -42,6 +43,7 @@ func createDefaultOutputFormats(allFormats output.Formats) map[string]output.For
 		// Below are for consistency. They are currently not used during rendering.
 		kindSitemap:   {sitemapOut},
 		kindRobotsTXT: {robotsOut},
+		kindLLMsTXT:   {llmsOut},
 		kind404:       {htmlOut},
 	}
 
@@ -616,3 +616,40 @@ WordCount: {{ .WordCount }}
 	b.AssertFileContent("public/outputs-empty/index.html", "HTML:", "Word1. Word2.")
 	b.AssertFileContent("public/outputs-string/index.html", "O1:", "Word1. Word2.")
 }
+
+func TestOutputFormatStream(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "https://example.org/"
+minify = true
+[outputFormats.export]
+mediaType = "application/json"
+baseName = "export"
+isPlainText = true
+stream = true
+[outputs]
+home = ["html", "export"]
+section = ["html", "export"]
+`)
+
+	b.WithContent("p1.md", `---
+title: "P1"
+---
+`, "empty/_index.md", `---
+title: "Empty"
+---
+`)
+
+	b.WithTemplatesAdded(
+		"index.export.json", `{
+  "pages": [{{ range $i, $e := seq 1000 }}{{ if $i }}, {{ end }}{{ $i }}{{ end }}]
+}`,
+		"_default/list.export.json", ``,
+	)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/export.json", "{\n  \"pages\": [0, 1, 2,", "998, 999]\n}")
+	b.Assert(b.CheckExists("public/empty/export.json"), qt.Equals, false)
+}
@@ -0,0 +1,101 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestPrintOutputFormat(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "https://example.org/"
+`)
+
+	b.WithContent("guide/_index.md", `---
+title: "The Guide"
+outputs: ["html", "print"]
+print:
+  tocDepth: 1
+---
+Welcome to the guide.
+`, "guide/a.md", `---
+title: "Getting Started"
+weight: 1
+---
+## Install
+
+Install it[^1], then see [usage]({{< relref "b.md#options" >}}).
+
+[^1]: It's easy.
+`, "guide/more/_index.md", `---
+title: "More"
+---
+`, "guide/more/b.md", `---
+title: "Usage"
+weight: 2
+---
+## Options
+
+Some options[^1].
+
+[^1]: Many options.
+`, "manual/_index.md", `---
+title: "Manual"
+outputs: ["html", "print"]
+print:
+  toc: false
+  numberHeadings: false
+  recursive: false
+---
+`, "manual/c.md", `---
+title: "C"
+---
+## Section
+`, "manual/sub/_index.md", `---
+title: "Sub"
+---
+`, "manual/sub/d.md", `---
+title: "D"
+---
+`)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/guide/print.html",
+		`<meta name="robots" content="noindex"/>`,
+		`<h1 class="print-title">The Guide</h1>`,
+		`<p>Welcome to the guide.</p>`,
+		`<nav class="print-toc"><ol><li><a href="#chapter-1-h1"><span class="print-number">1</span> Getting Started</a></li><li><a href="#chapter-2-h1"><span class="print-number">2</span> Usage</a></li></ol></nav>`,
+		`<article class="print-chapter" id="chapter-1">`,
+		`<h2 id="chapter-1-install"><span class="print-number">1.1</span> Install</h2>`,
+		`<sup id="chapter-1-fnref:1"><a href="#chapter-1-fn:1" class="footnote-ref" role="doc-noteref">1</a></sup>`,
+		`<a href="#chapter-2-options">usage</a>`,
+		`<li id="chapter-1-fn:1" role="doc-endnote">`,
+		`<h2 id="chapter-2-options"><span class="print-number">2.1</span> Options</h2>`,
+		`<li id="chapter-2-fn:1" role="doc-endnote">`,
+	)
+
+	manual := b.FileContent("public/manual/print.html")
+	b.Assert(manual, qt.Contains, `<h2 id="chapter-1-section">Section</h2>`)
+	b.Assert(manual, qt.Not(qt.Contains), "<nav")
+	b.Assert(manual, qt.Not(qt.Contains), "<h1>D</h1>")
+
+	// Only for the sections with the format in outputs.
+	b.Assert(b.CheckExists("public/guide/index.html"), qt.Equals, true)
+	b.Assert(b.CheckExists("public/print.html"), qt.Equals, false)
+}
@@ -221,42 +221,80 @@ func (s *Site) renderPaginator(p *pageState, templ tpl.Template) error {
 	return nil
 }
 
+// render404 renders the 404 page for this site/language and for every top
+// level section with its own 404 template, e.g. layouts/docs/404.html.
 func (s *Site) render404() error {
-	p, err := newPageStandalone(&pageMeta{
-		s:    s,
-		kind: kind404,
-		urlPaths: pagemeta.URLPath{
-			URL: "404.html",
-		},
-	},
-		output.HTMLFormat,
-	)
+	templ, err := s.render404ForSection(nil, nil)
 	if err != nil {
 		return err
 	}
 
-	if !p.render {
+	if s.home == nil {
 		return nil
 	}
 
-	var d output.LayoutDescriptor
-	d.Kind = kind404
+	for _, sect := range s.home.Sections() {
+		if _, err := s.render404ForSection(sect.(*pageState), templ); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// render404ForSection renders the 404 page for the given section, or the
+// root 404 page if sect is nil. The page is not rendered if the
+// template found is the same as rootTempl.
+func (s *Site) render404ForSection(sect *pageState, rootTempl tpl.Template) (tpl.Template, error) {
+	var section string
+	if sect != nil {
+		section = sect.Section()
+	}
+
+	d := output.LayoutDescriptor{
+		Kind:    kind404,
+		Lang:    s.Lang(),
+		Section: section,
+	}
 
 	templ, found, err := s.Tmpl().LookupLayout(d, output.HTMLFormat)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	if rootTempl != nil && templ.Name() == rootTempl.Name() {
+		// No section specific template.
+		return templ, nil
+	}
+
+	m := &pageMeta{
+		s:    s,
+		kind: kind404,
+		urlPaths: pagemeta.URLPath{
+			URL: path.Join(section, "404.html"),
+		},
+	}
+	if sect != nil {
+		m.sections = []string{section}
+	}
+
+	p, err := newPageStandalone(m, output.HTMLFormat)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if !found {
-		return nil
+	p.parent = sect
+
+	if !p.render {
+		return templ, nil
 	}
 
 	targetPath := p.targetPaths().TargetFilename
 
 	if targetPath == "" {
-		return errors.New("failed to create targetPath for 404 page")
+		return nil, errors.New("failed to create targetPath for 404 page")
 	}
 
-	return s.renderAndWritePage(&s.PathSpec.ProcessingStats.Pages, "404 page", targetPath, p, templ)
+	return templ, s.renderAndWritePage(&s.PathSpec.ProcessingStats.Pages, "404 page", targetPath, p, templ)
 }
 
 func (s *Site) renderSitemap() error {
@@ -314,6 +352,121 @@ func (s *Site) renderRobotsTXT() error {
 	return s.renderAndWritePage(&s.PathSpec.ProcessingStats.Pages, "Robots Txt", p.targetPaths().TargetFilename, p, templ)
 }
 
+func (s *Site) renderLLMsTXT() error {
+	if !s.Cfg.GetBool("enableLLMsTXT") {
+		return nil
+	}
+
+	cfg := s.siteCfg.llms
+
+	render := func(filename, name string) error {
+		p, err := newPageStandalone(&pageMeta{
+			s:    s,
+			kind: kindLLMsTXT,
+			urlPaths: pagemeta.URLPath{
+				URL: filename,
+			},
+		},
+			output.LLMsTxtFormat)
+		if err != nil {
+			return err
+		}
+
+		if !p.render {
+			return nil
+		}
+
+		templ := s.lookupLayouts(name, "_default/"+name, "_internal/_default/"+name)
+
+		return s.renderAndWritePage(&s.PathSpec.ProcessingStats.Pages, "LLMs Txt", p.targetPaths().TargetFilename, p, templ)
+	}
+
+	if err := render(cfg.Filename, "llms.txt"); err != nil {
+		return err
+	}
+
+	if cfg.Full {
+		return render(cfg.FullFilename, "llms-full.txt")
+	}
+
+	return nil
+}
+
+// llmsPages returns the pages to list in llms.txt and llms-full.txt.
+func (s *Site) llmsPages() page.Pages {
+	sections := s.siteCfg.llms.Sections
+	if len(sections) == 0 {
+		return s.RegularPages()
+	}
+
+	var pages page.Pages
+	for _, p := range s.RegularPages() {
+		for _, section := range sections {
+			if p.Section() == section {
+				pages = append(pages, p)
+				break
+			}
+		}
+	}
+
+	return pages
+}
+
+// renderPageIDRedirects records the paths of the pages with an ID and creates
+// redirects from the paths they were published to in earlier builds.
+func (s *Site) renderPageIDRedirects() error {
+	ids := s.h.pageIDs
+	if !ids.cfg.Enable {
+		return nil
+	}
+
+	disableAliases := s.Cfg.GetBool("disableAliases")
+	seen := make(map[string]*pageState)
+
+	var err error
+	s.pageMap.pageTrees.WalkLinkable(func(ss string, n *contentNode) bool {
+		p := n.p
+		id := p.ID()
+		if id == "" {
+			return false
+		}
+
+		if other, found := seen[id]; found {
+			err = errors.Errorf("page ID %q used by both %q and %q", id, other.pathOrTitle(), p.pathOrTitle())
+			return true
+		}
+		seen[id] = p
+
+		formatSeen := make(map[string]bool)
+		for _, po := range p.pageOutputs {
+			if !po.render || !po.f.IsHTML || formatSeen[po.f.Name] {
+				continue
+			}
+			formatSeen[po.f.Name] = true
+
+			of := p.OutputFormats().Get(po.f.Name)
+			if of == nil {
+				continue
+			}
+
+			oldPaths := ids.update(s.Lang(), id, po.f.Name, po.targetPaths().TargetFilename)
+			if disableAliases {
+				continue
+			}
+
+			for _, oldPath := range oldPaths {
+				if err = s.writeDestAlias(oldPath, of.Permalink(), po.f, p); err != nil {
+					return true
+				}
+			}
+		}
+
+		return false
+	})
+
+	return err
+}
+
 // renderAliases renders shell pages that simply have a redirect in the header.
 func (s *Site) renderAliases() error {
 	var err error
@@ -0,0 +1,83 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/gohugoio/hugo/helpers"
+	"github.com/gohugoio/hugo/resources/page"
+	"github.com/gohugoio/hugo/search"
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+)
+
+var headingRe = regexp.MustCompile(`(?is)<h[1-6][^>]*>(.*?)</h[1-6]>`)
+
+// SearchIndex builds a search index for the given pages using the
+// searchIndex config for this site's language.
+// It's used by the built-in template for the SearchIndex output format.
+func (s *SiteInfo) SearchIndex(pages page.Pages) (*search.Index, error) {
+	cfg := s.s.siteCfg.searchIndex
+	idx := search.NewIndex(cfg, s.language.Lang)
+
+	for _, p := range pages {
+		values := make(map[string]interface{})
+		for _, f := range cfg.Fields {
+			v, err := searchIndexFieldValue(p, f.Name)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to index %q", p.Path())
+			}
+			if v != nil {
+				values[f.Name] = v
+			}
+		}
+		idx.Add(p.Permalink(), values)
+	}
+
+	return idx.Finish(), nil
+}
+
+func searchIndexFieldValue(p page.Page, name string) (interface{}, error) {
+	switch name {
+	case "title":
+		return p.Title(), nil
+	case "summary":
+		return strings.TrimSpace(helpers.StripHTML(string(p.Summary()))), nil
+	case "content":
+		return p.Plain(), nil
+	case "headings":
+		content, err := p.Content()
+		if err != nil {
+			return nil, err
+		}
+		var headings []string
+		for _, m := range headingRe.FindAllStringSubmatch(cast.ToString(content), -1) {
+			if heading := strings.TrimSpace(helpers.StripHTML(m[1])); heading != "" {
+				headings = append(headings, heading)
+			}
+		}
+		return headings, nil
+	}
+
+	v := p.Params()[name]
+	if v == nil {
+		return nil, nil
+	}
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+	return cast.ToStringSliceE(v)
+}
@@ -0,0 +1,80 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSearchIndexOutputFormat(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "https://example.org/"
+defaultContentLanguage = "en"
+[outputs]
+home = ["html", "searchindex"]
+section = ["html", "searchindex"]
+[languages]
+[languages.en]
+weight = 1
+[languages.fr]
+weight = 2
+[languages.fr.searchIndex]
+stem = false
+[[languages.fr.searchIndex.fields]]
+name = "title"
+weight = 2
+store = true
+`)
+
+	b.WithContent("docs/templates.md", `---
+title: "Running Templates"
+tags: ["go"]
+---
+Some summary.
+
+## Template Functions
+`, "blog/post.md", `---
+title: "Post"
+---
+Post content.
+`, "blog/post.fr.md", `---
+title: "Les Pages"
+---
+Contenu.
+`)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/searchindex.json",
+		`"lang":"en","stemmed":true`,
+		`"fields":{"headings":3,"summary":1,"tags":5,"title":10}`,
+		`"headings":["Template Functions"]`,
+		`"permalink":"https://example.org/docs/templates/"`,
+		`"tags":["go"]`,
+		`"run":[[`,
+		`"templat":[[`,
+	)
+	b.AssertFileContent("public/docs/searchindex.json", `"title":"Running Templates"`)
+	b.AssertFileContentFn("public/docs/searchindex.json", func(s string) bool {
+		return !strings.Contains(s, "Post")
+	})
+	b.AssertFileContent("public/fr/searchindex.json",
+		`"lang":"fr","stemmed":false`,
+		`"fields":{"title":2}`,
+		`"pages":[[`,
+	)
+}
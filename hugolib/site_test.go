@@ -24,6 +24,7 @@ import (
 
 	"github.com/gobuffalo/flect"
 	"github.com/gohugoio/hugo/config"
+	"github.com/gohugoio/hugo/helpers"
 	"github.com/gohugoio/hugo/publisher"
 
 	qt "github.com/frankban/quicktest"
@@ -979,6 +980,52 @@ func TestRefIssues(t *testing.T) {
 	b.AssertFileContent("public/post/nested-a/content-a/index.html", `Content: http://example.com/post/nested-b/content-b/`)
 }
 
+func TestRefLanguageFallback(t *testing.T) {
+	t.Parallel()
+
+	config := `
+baseURL = "https://example.org/"
+defaultContentLanguage = "en"
+%s
+
+[languages]
+[languages.en]
+weight = 1
+[languages.nn]
+weight = 2
+[languages.sv]
+weight = 3
+`
+
+	files := []string{
+		"b1.md", "---\ntitle: b1\n---\n",
+		"b2.nn.md", "---\ntitle: b2\n---\n",
+		"b2.sv.md", "---\ntitle: b2\n---\n",
+		"a1.sv.md", "---\ntitle: a1\n---\nRef b1: {{< relref \"b1\" >}}|Ref b2: {{< relref \"b2.nn.md\" >}}|",
+	}
+
+	c := qt.New(t)
+
+	b := newTestSitesBuilder(t).WithConfigFile("toml", fmt.Sprintf(config, `refLinksLanguageFallback = ["nn", "en"]`))
+	b.WithContent(files...)
+	b.WithTemplates("_default/single.html", `Content: {{ .Content }}`, "_default/list.html", `List`)
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/sv/a1/index.html", "Ref b1: /b1/|Ref b2: /nn/b2/|")
+	// b1 is not translated to Swedish. The explicit reference to b2 in
+	// Nynorsk is not reported.
+	c.Assert(b.H.Log.LogCounters().WarnCounter.Count(), qt.Equals, uint64(1))
+
+	b = newTestSitesBuilder(t).WithConfigFile("toml", fmt.Sprintf(config, ""))
+	b.WithContent(files...)
+	b.WithTemplates("_default/single.html", `Content: {{ .Content }}`, "_default/list.html", `List`)
+	err := b.BuildE(BuildCfg{})
+	c.Assert(err, qt.Not(qt.IsNil))
+
+	b.AssertFileContent("public/sv/a1/index.html", "Ref b1: |Ref b2: /nn/b2/|")
+	c.Assert(b.H.Log.LogCounters().ErrorCounter.Count(), qt.Equals, uint64(1))
+}
+
 func TestClassCollector(t *testing.T) {
 	for _, minify := range []bool{false, true} {
 		t.Run(fmt.Sprintf("minify-%t", minify), func(t *testing.T) {
@@ -1116,3 +1163,105 @@ ABC.
 		b.Assert(els.IDs, qt.HasLen, 1)
 	}
 }
+
+func TestOutboundLinks(t *testing.T) {
+	b := newTestSitesBuilder(t)
+	b.WithConfigFile("toml", `
+baseURL = "https://example.com/"
+disableKinds = ["section", "term", "taxonomy", "RSS", "sitemap"]
+
+[outboundLinks]
+prefix = "/go"
+excludes = ["*.example.net"]
+`)
+
+	b.WithContent("p1.md", `---
+title: P1
+---
+
+[Ext](https://example.org/a?b=1&c=2), [Own](https://example.com/p2/), [Excluded](https://www.example.net/).
+`)
+
+	b.WithTemplates("_default/single.html", `{{ .Content }}`)
+
+	b.Build(BuildCfg{})
+
+	id := "/go/" + helpers.MD5String("https://example.org/a?b=1&c=2")[:10]
+
+	b.AssertFileContent("public/p1/index.html",
+		`<a href="`+id+`">Ext</a>`,
+		`<a href="https://example.com/p2/">Own</a>`,
+		`<a href="https://www.example.net/">Excluded</a>`,
+	)
+
+	b.AssertFileContent("public/outbound_links.json", `"`+id+`": "https://example.org/a?b=1&c=2"`)
+}
+
+func TestResourceHints(t *testing.T) {
+	b := newTestSitesBuilder(t)
+	b.WithConfigFile("toml", `
+baseURL = "https://example.com/"
+disableKinds = ["section", "term", "taxonomy", "RSS", "sitemap"]
+paginate = 1
+[outputs]
+home = ["html"]
+page = ["html", "json"]
+`)
+
+	b.WithContent("p1.md", `---
+title: P1
+---
+`, "p2.md", `---
+title: P2
+---
+`)
+
+	b.WithSourceFile("assets/css/main.css", "body { color: red; }")
+
+	b.WithTemplates(
+		"_default/single.html", `<head>{{ .ResourceHints }}</head><body>{{ $js := resources.FromString "js/main.js" "var a;" | fingerprint }}<script src="{{ $js.RelPermalink }}"></script><script src="/not/published.js"></script></body>`,
+		"_default/single.json", `{"hints": "{{ .ResourceHints }}"}`,
+		"index.html", `<head>{{ .ResourceHints }}{{ with resources.Get "css/main.css" }}<link rel="stylesheet" href="{{ .RelPermalink }}">{{ end }}</head><body>{{ range .Paginator.Pages }}{{ .Title }}{{ end }}</body>`,
+		"_default/list.html", `<head></head><body>{{ with resources.Get "css/main.css" }}<link rel="stylesheet" href="{{ .RelPermalink }}">{{ end }}</body>`,
+	)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/p1/index.html", `<head><link rel="preload" href="/js/main.`, `.js" as="script" integrity="sha256-`, `"></head>`)
+	b.AssertFileContentFn("public/p1/index.html", func(s string) bool {
+		return !strings.Contains(s, "/not/published.js\" as") && !strings.Contains(s, "__hugo_resource_hints__")
+	})
+	b.AssertFileContent("public/p1/index.json", `{"hints": ""}`)
+	b.AssertFileContent("public/index.html", `<link rel="preload" href="/css/main.css" as="style">
+<link rel="prefetch" href="/page/2/"><link rel="stylesheet" href="/css/main.css"></head>`)
+}
+
+func TestCanonicalHTML(t *testing.T) {
+	b := newTestSitesBuilder(t)
+	b.WithConfigFile("toml", `
+baseURL = "https://example.com/"
+disableKinds = ["section", "term", "taxonomy", "RSS", "sitemap"]
+
+[canonicalHTML]
+enable = true
+`)
+
+	b.WithContent("p1.md", `---
+title: P1
+---
+`)
+
+	b.WithTemplates("_default/single.html", `<html><body><div id="main"   class="c">
+    <h1>{{ .Title }}</h1>   <a title="t" href="/">Home</a></div></body></html>`)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/p1/index.html", `<html>
+  <body>
+    <div class="c" id="main">
+      <h1>P1</h1>
+      <a href="/" title="t">Home</a>
+    </div>
+  </body>
+</html>`)
+}
@@ -15,6 +15,7 @@ package hugolib
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 
 	qt "github.com/frankban/quicktest"
@@ -87,11 +88,13 @@ func doTestSitemapOutput(t *testing.T, internal bool) {
 
 func TestParseSitemap(t *testing.T) {
 	t.Parallel()
-	expected := config.Sitemap{Priority: 3.0, Filename: "doo.xml", ChangeFreq: "3"}
+	expected := config.Sitemap{Priority: 3.0, Filename: "doo.xml", ChangeFreq: "3", Disable: true, Formats: []string{"html", "amp"}}
 	input := map[string]interface{}{
 		"changefreq": "3",
 		"priority":   3.0,
 		"filename":   "doo.xml",
+		"disable":    true,
+		"formats":    []interface{}{"html", "amp"},
 		"unknown":    "ignore",
 	}
 	result := config.DecodeSitemap(config.Sitemap{}, input)
@@ -118,3 +121,63 @@ outputs: [ "html", "amp" ]
 	// Should link to the HTML version.
 	b.AssertFileContent("public/sitemap.xml", " <loc>http://example.com/blog/html-amp/</loc>")
 }
+
+func TestSitemapAndRobotsOutputFormatsConfig(t *testing.T) {
+	b := newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "http://example.com/"
+enableRobotsTXT = true
+defaultContentLanguage = "en"
+[outputFormats.print]
+mediaType = "text/html"
+baseName = "print"
+isHTML = true
+noIndex = true
+[outputFormats.amp]
+sitemap = true
+[outputs]
+page = ["html", "amp", "print"]
+[languages.en]
+weight = 1
+[languages.nn]
+weight = 2
+`)
+
+	b.WithContent("blog/p1.md", `---
+title: "P1"
+---
+`, "blog/p1.nn.md", `---
+title: "P1 nn"
+---
+`, "blog/p2.md", `---
+title: "P2"
+sitemap:
+  formats: ["amp", "print"]
+---
+`, "blog/p3.md", `---
+title: "P3"
+sitemap:
+  disable: true
+---
+`)
+
+	b.WithTemplates("_default/single.html", "{{ .Title }}", "_default/list.html", "{{ .Title }}")
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/en/sitemap.xml",
+		"<loc>http://example.com/blog/p1/</loc>",
+		"<loc>http://example.com/amp/blog/p1/</loc>",
+		`href="http://example.com/nn/amp/blog/p1/"`,
+		"<loc>http://example.com/amp/blog/p2/</loc>",
+	)
+	b.AssertFileContentFn("public/en/sitemap.xml", func(s string) bool {
+		return !strings.Contains(s, "print") && !strings.Contains(s, "p3") &&
+			!strings.Contains(s, "<loc>http://example.com/blog/p2/</loc>")
+	})
+
+	b.AssertFileContent("public/robots.txt",
+		"User-agent: *",
+		"Disallow: /blog/p1/print.html",
+		"Disallow: /blog/p3/print.html",
+	)
+}
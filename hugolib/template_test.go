@@ -16,8 +16,10 @@ package hugolib
 import (
 	"fmt"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gohugoio/hugo/config"
 	"github.com/gohugoio/hugo/identity"
@@ -498,6 +500,51 @@ Partial cached3: {{ partialCached "p1" "input3" $key2 }}
 `)
 }
 
+func TestPartialCachedWithCacheOptions(t *testing.T) {
+	b := newTestSitesBuilder(t).Running()
+
+	b.WithTemplatesAdded(
+		"index.html", `
+TTL: {{ partialCached "now.html" . "ttl" (partials.CacheOptions (dict "ttl" "1h" "keys" "k1")) }}|
+Short TTL: {{ partialCached "now.html" . "short" (partials.CacheOptions (dict "ttl" "1ns")) }}|
+Build: {{ partialCached "now.html" . "build" }}|
+`,
+		"partials/now.html", `{{ now.UnixNano }}`,
+	)
+
+	values := func() (ttl, short, build string) {
+		content := b.FileContent("public/index.html")
+		get := func(name string) string {
+			m := regexp.MustCompile(name + `: (\d+)\|`).FindStringSubmatch(content)
+			b.Assert(m, qt.HasLen, 2)
+			return m[1]
+		}
+		return get("TTL"), get("Short TTL"), get("Build")
+	}
+
+	b.Build(BuildCfg{})
+	ttl1, short1, build1 := values()
+	expiry := b.H.Deps.BuildState.CacheExpiry()
+	b.Assert(expiry.IsZero(), qt.IsFalse)
+	b.Assert(expiry.Before(time.Now().Add(time.Hour)), qt.IsTrue)
+
+	b.Build(BuildCfg{})
+	ttl2, short2, build2 := values()
+	b.Assert(ttl2, qt.Equals, ttl1)
+	b.Assert(short2, qt.Not(qt.Equals), short1)
+	b.Assert(build2, qt.Not(qt.Equals), build1)
+
+	b.H.InvalidateCaches("k2")
+	b.Build(BuildCfg{})
+	ttl3, _, _ := values()
+	b.Assert(ttl3, qt.Equals, ttl1)
+
+	b.H.InvalidateCaches("k1")
+	b.Build(BuildCfg{})
+	ttl4, _, _ := values()
+	b.Assert(ttl4, qt.Not(qt.Equals), ttl1)
+}
+
 // https://github.com/gohugoio/hugo/issues/6615
 func TestTemplateTruth(t *testing.T) {
 	b := newTestSitesBuilder(t)
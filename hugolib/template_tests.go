@@ -0,0 +1,336 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/gohugoio/hugo/output"
+	"github.com/gohugoio/hugo/parser/metadecoders"
+	"github.com/gohugoio/hugo/tpl"
+	"github.com/gohugoio/hugo/tpl/partials"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"github.com/spf13/cast"
+)
+
+// TemplateTest tests the output of a partial or a shortcode executed with a
+// fixture context, see RunTemplateTests.
+type TemplateTest struct {
+	// The name of the test, used when reporting failures.
+	Name string
+
+	// The partial to execute, e.g. "card.html".
+	Partial string
+
+	// The shortcode to execute, e.g. "note".
+	Shortcode string
+
+	// The context passed to the partial. Defaults to the page.
+	Context interface{}
+
+	// The path to the page, e.g. "/posts/p1", used as the partial context
+	// if no context is set and as .Page in shortcodes. Defaults to the home
+	// page.
+	Page string
+
+	// The shortcode parameters, a map for named parameters or a list for
+	// positional parameters.
+	Params interface{}
+
+	// The inner content of the shortcode.
+	Inner string
+
+	// The strings the output must contain.
+	Contains []string
+
+	// The strings the output must not contain.
+	NotContains []string
+
+	// If set, the output, or the value returned by the partial, must be
+	// equal to this when both are decoded as JSON.
+	JSON interface{}
+}
+
+// TemplateTestResult is the result of running a TemplateTest.
+type TemplateTestResult struct {
+	// The filename of the test file, relative to the working dir.
+	Filename string
+
+	Test TemplateTest
+
+	// The rendered output, or the value returned by the partial encoded as
+	// JSON.
+	Output string
+
+	// The failed assertions, empty if the test passed.
+	Failures []string
+}
+
+// Passed reports whether all the assertions in the test passed.
+func (r TemplateTestResult) Passed() bool {
+	return len(r.Failures) == 0
+}
+
+// RunTemplateTests runs the template tests in the YAML, TOML and JSON files
+// below dir, relative to the working dir, with the tests in a list named
+// "tests", e.g.:
+//
+//	tests:
+//	  - name: Card title
+//	    partial: card.html
+//	    context:
+//	      title: My Card
+//	    contains: ['<h2 class="card-title">My Card</h2>']
+//
+// The sites must be built before running the tests; there is no need to
+// render them.
+func (h *HugoSites) RunTemplateTests(dir string) ([]TemplateTestResult, error) {
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(h.WorkingDir, dir)
+	}
+
+	var filenames []string
+	err := afero.Walk(h.Fs.Source, dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		if f := metadecoders.FormatFromString(filepath.Ext(path)); f == metadecoders.YAML || f == metadecoders.TOML || f == metadecoders.JSON {
+			filenames = append(filenames, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(filenames) == 0 {
+		return nil, errors.Errorf("no template tests found in %s", dir)
+	}
+
+	sort.Strings(filenames)
+
+	var results []TemplateTestResult
+
+	for _, filename := range filenames {
+		tests, err := decodeTemplateTests(h.Fs.Source, filename)
+		if err != nil {
+			return nil, err
+		}
+
+		rel, err := filepath.Rel(h.WorkingDir, filename)
+		if err != nil {
+			rel = filename
+		}
+
+		for _, test := range tests {
+			result, err := h.Sites[0].runTemplateTest(test)
+			if err != nil {
+				return nil, errors.Wrapf(err, "%s: failed to run test %q", rel, test.Name)
+			}
+			result.Filename = rel
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
+
+func decodeTemplateTests(fs afero.Fs, filename string) ([]TemplateTest, error) {
+	b, err := afero.ReadFile(fs, filename)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := metadecoders.Default.UnmarshalToMap(b, metadecoders.FormatFromString(filepath.Ext(filename)))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decode %s", filename)
+	}
+
+	var file struct {
+		Tests []TemplateTest
+	}
+
+	if err := mapstructure.WeakDecode(m, &file); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode the tests in %s", filename)
+	}
+
+	for i, test := range file.Tests {
+		if (test.Partial == "") == (test.Shortcode == "") {
+			return nil, errors.Errorf("%s: test %d must have either a partial or a shortcode", filename, i+1)
+		}
+		if test.Name == "" {
+			file.Tests[i].Name = fmt.Sprintf("#%d", i+1)
+		}
+		file.Tests[i].Context = normalizeTemplateTestValue(test.Context)
+		file.Tests[i].Params = normalizeTemplateTestValue(test.Params)
+		file.Tests[i].JSON = normalizeTemplateTestValue(test.JSON)
+	}
+
+	return file.Tests, nil
+}
+
+// normalizeTemplateTestValue converts any map[interface{}]interface{} in v,
+// decoded from a test file, to map[string]interface{}.
+func normalizeTemplateTestValue(v interface{}) interface{} {
+	switch vv := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{})
+		for k, e := range vv {
+			m[cast.ToString(k)] = normalizeTemplateTestValue(e)
+		}
+		return m
+	case map[string]interface{}:
+		for k, e := range vv {
+			vv[k] = normalizeTemplateTestValue(e)
+		}
+	case []interface{}:
+		for i, e := range vv {
+			vv[i] = normalizeTemplateTestValue(e)
+		}
+	}
+	return v
+}
+
+func (s *Site) runTemplateTest(test TemplateTest) (TemplateTestResult, error) {
+	result := TemplateTestResult{Test: test}
+
+	p := s.home
+	if test.Page != "" {
+		pp, err := s.getPageNew(nil, test.Page)
+		if err != nil {
+			return result, err
+		}
+		if pp == nil {
+			return result, errors.Errorf("page %q not found", test.Page)
+		}
+		p = pp.(*pageState)
+	}
+
+	var (
+		output interface{}
+		err    error
+	)
+
+	if test.Partial != "" {
+		context := test.Context
+		if context == nil && p != nil {
+			context = p
+		}
+		output, err = partials.New(s.Deps).Include(test.Partial, context)
+	} else {
+		output, err = s.executeShortcodeForTest(test, p)
+	}
+
+	if err != nil {
+		return result, err
+	}
+
+	switch v := output.(type) {
+	case string:
+		result.Output = v
+	case template.HTML:
+		result.Output = string(v)
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return result, errors.Wrap(err, "failed to encode the value returned by the partial")
+		}
+		result.Output = string(b)
+	}
+
+	for _, s := range test.Contains {
+		if !strings.Contains(result.Output, s) {
+			result.Failures = append(result.Failures, fmt.Sprintf("output does not contain %q", s))
+		}
+	}
+
+	for _, s := range test.NotContains {
+		if strings.Contains(result.Output, s) {
+			result.Failures = append(result.Failures, fmt.Sprintf("output contains %q", s))
+		}
+	}
+
+	if test.JSON != nil {
+		if failure := compareTemplateTestJSON(result.Output, test.JSON); failure != "" {
+			result.Failures = append(result.Failures, failure)
+		}
+	}
+
+	return result, nil
+}
+
+func (s *Site) executeShortcodeForTest(test TemplateTest, p *pageState) (interface{}, error) {
+	if p == nil {
+		return nil, errors.New("no page to execute the shortcode with; set page or enable the home page")
+	}
+
+	tmpl, found, _ := s.Tmpl().LookupVariant(test.Shortcode, tpl.TemplateVariants{
+		Language:     s.language.Lang,
+		OutputFormat: output.HTMLFormat,
+	})
+	if !found {
+		return nil, errors.Errorf("shortcode %q not found", test.Shortcode)
+	}
+
+	data := &ShortcodeWithPage{
+		Params: test.Params,
+		Inner:  template.HTML(test.Inner),
+		Page:   newPageForShortcode(p),
+		Name:   test.Shortcode,
+	}
+	if test.Params != nil {
+		data.IsNamedParams = reflect.TypeOf(test.Params).Kind() == reflect.Map
+	}
+
+	return renderShortcodeWithPage(s.Tmpl(), tmpl, data)
+}
+
+// compareTemplateTestJSON compares the JSON in output with expected,
+// returning a description of the failure, if any.
+func compareTemplateTestJSON(output string, expected interface{}) string {
+	var got interface{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(output)), &got); err != nil {
+		return fmt.Sprintf("output is not valid JSON: %s", err)
+	}
+
+	b, err := json.Marshal(expected)
+	if err != nil {
+		return fmt.Sprintf("failed to encode the expected JSON: %s", err)
+	}
+	var want interface{}
+	json.Unmarshal(b, &want)
+
+	if !reflect.DeepEqual(got, want) {
+		bb, _ := json.Marshal(got)
+		return fmt.Sprintf("output JSON %s is not equal to %s", bytes.TrimSpace(bb), b)
+	}
+
+	return ""
+}
@@ -0,0 +1,114 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestRunTemplateTests(t *testing.T) {
+	c := qt.New(t)
+
+	b := newTestSitesBuilder(t)
+	b.WithConfigFile("toml", `baseURL = "https://example.org"
+title = "My Site"
+`)
+	b.WithContent("posts/p1.md", "---\ntitle: P1\n---\nContent.")
+	b.WithTemplatesAdded(
+		"partials/card.html", `<div class="card"><h2>{{ .Title }}</h2>{{ with .Tags }}<ul>{{ range . }}<li>{{ . }}</li>{{ end }}</ul>{{ end }}</div>`,
+		"partials/page-title.html", `{{ .Title }}|{{ .Site.Title }}`,
+		"partials/summary.html", `{{ return (dict "title" .title "count" (len .items)) }}`,
+		"partials/data.json", `{"title": {{ .title | jsonify }}, "count": 3}`,
+		"shortcodes/note.html", `<aside class="note note-{{ .Get "type" }}">{{ .Inner }}</aside>|{{ .Page.Title }}`,
+		"shortcodes/pos.html", `{{ .Get 0 }}-{{ .Get 1 }}`,
+	)
+
+	b.WithSourceFile("tests/templates/partials.yaml", `
+tests:
+  - name: Card
+    partial: card.html
+    context:
+      Title: My Card
+      Tags: [a, b]
+    contains:
+      - <h2>My Card</h2>
+      - <li>b</li>
+    notContains: [<li>c</li>]
+  - name: Card fails
+    partial: card.html
+    context:
+      Title: My Card
+    contains: [<h2>Other</h2>]
+    notContains: [My Card]
+  - partial: page-title.html
+    page: /posts/p1
+    contains: [P1|My Site]
+  - name: Return
+    partial: summary.html
+    context:
+      title: Summary
+      items: [1, 2]
+    json:
+      title: Summary
+      count: 2
+  - name: JSON
+    partial: data.json
+    context:
+      title: Data
+    json: {count: 3, title: Data}
+`)
+
+	b.WithSourceFile("tests/templates/shortcodes.toml", `
+[[tests]]
+name = "Note"
+shortcode = "note"
+inner = "Some text"
+page = "/posts/p1"
+contains = ['<aside class="note note-warning">Some text</aside>|P1']
+[tests.params]
+type = "warning"
+
+[[tests]]
+name = "Positional"
+shortcode = "pos"
+params = ["a", "b"]
+contains = ["a-b"]
+`)
+
+	b.Build(BuildCfg{SkipRender: true})
+
+	results, err := b.H.RunTemplateTests("tests/templates")
+	c.Assert(err, qt.IsNil)
+	c.Assert(results, qt.HasLen, 7)
+
+	failures := make(map[string][]string)
+	for _, r := range results {
+		if !r.Passed() {
+			failures[r.Test.Name] = r.Failures
+		}
+	}
+
+	c.Assert(failures, qt.DeepEquals, map[string][]string{
+		"Card fails": {`output does not contain "<h2>Other</h2>"`, `output contains "My Card"`},
+	})
+
+	c.Assert(results[2].Test.Name, qt.Equals, "#3")
+	c.Assert(results[3].Output, qt.Equals, `{"count":2,"title":"Summary"}`)
+	c.Assert(results[5].Filename, qt.Equals, "tests/templates/shortcodes.toml")
+
+	_, err = b.H.RunTemplateTests("tests/none")
+	c.Assert(err, qt.ErrorMatches, "no template tests found in .*")
+}
@@ -0,0 +1,80 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/gohugoio/hugo/htesting"
+	"github.com/gohugoio/hugo/hugofs"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestVideo(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses shell scripts for ffprobe and ffmpeg")
+	}
+
+	c := qt.New(t)
+
+	// Stand-ins for ffprobe and ffmpeg.
+	binDir, clean, err := htesting.CreateTempDir(hugofs.Os, "hugo-video-bin")
+	c.Assert(err, qt.IsNil)
+	defer clean()
+
+	sunset, err := filepath.Abs(filepath.FromSlash("testdata/sunset.jpg"))
+	c.Assert(err, qt.IsNil)
+	ffprobe := filepath.Join(binDir, "ffprobe")
+	ffmpeg := filepath.Join(binDir, "ffmpeg")
+	c.Assert(ioutil.WriteFile(ffprobe, []byte(`#!/bin/sh
+echo '{"streams": [{"width": 640, "height": 360}], "format": {"duration": "4.500000"}}'
+`), 0755), qt.IsNil)
+	c.Assert(ioutil.WriteFile(ffmpeg, []byte(fmt.Sprintf(`#!/bin/sh
+cat %q
+`, sunset)), 0755), qt.IsNil)
+
+	b := newTestSitesBuilder(t)
+	b.WithConfigFile("toml", fmt.Sprintf(`
+baseURL = "https://example.org"
+[video]
+ffprobe = %q
+ffmpeg = %q
+`, ffprobe, ffmpeg))
+
+	b.WithContent("gallery/index.md", "---\ntitle: Gallery\n---\n")
+	b.WithSourceFile("content/gallery/clip.mp4", "video")
+	b.WithTemplatesAdded("_default/single.html", `
+{{ with .Resources.GetMatch "*.mp4" }}
+Video: {{ .ResourceType }}|{{ .RelPermalink }}|{{ .Width }}x{{ .Height }}|{{ .Duration }}|
+{{ $poster := .Poster }}
+Poster: {{ $poster.RelPermalink }}|{{ $poster.Width }}|
+{{ $thumb := $poster.Resize "300x" }}
+Thumb: {{ $thumb.Width }}x{{ $thumb.Height }}|
+{{ end }}
+`)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/gallery/index.html",
+		"Video: video|/gallery/clip.mp4|640x360|4.5s|",
+		"Poster: /gallery/clip_poster_",
+		".jpg|900|",
+		"Thumb: 300x187|",
+	)
+}
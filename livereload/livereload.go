@@ -161,6 +161,10 @@ HugoReload.version = '0.9';
 HugoReload.prototype.reload = function(path, options) {
 	var prefix = %q;
 
+	if (this.reloadScripts(path)) {
+		return true
+	}
+
 	if (path.lastIndexOf(prefix, 0) !== 0) {
 		return false
 	}
@@ -182,6 +186,48 @@ HugoReload.prototype.reload = function(path, options) {
 	return true;
 };
 
+/*
+Scripts with the data-hugo-hmr attribute are replaced in place when changed,
+without reloading the page. A "hugo:hmr" event is dispatched on the window
+first, which the scripts can use to clean up, or cancel to reload the page.
+*/
+HugoReload.prototype.reloadScripts = function(path) {
+	if (!/\.m?js$/.test(path)) {
+		return false;
+	}
+
+	var scripts = document.querySelectorAll('script[data-hugo-hmr][src]');
+	var matches = [];
+	for (var i = 0; i < scripts.length; i++) {
+		if (new URL(scripts[i].src, window.location.href).pathname === path) {
+			matches.push(scripts[i]);
+		}
+	}
+
+	if (matches.length === 0) {
+		return false;
+	}
+
+	var event = new CustomEvent('hugo:hmr', { detail: { path: path }, cancelable: true });
+	if (!window.dispatchEvent(event)) {
+		window.location.reload();
+		return true;
+	}
+
+	matches.forEach(function(script) {
+		var replacement = document.createElement('script');
+		for (var i = 0; i < script.attributes.length; i++) {
+			replacement.setAttribute(script.attributes[i].name, script.attributes[i].value);
+		}
+		var src = new URL(script.src, window.location.href);
+		src.searchParams.set('hmr', Date.now());
+		replacement.src = src.toString();
+		script.parentNode.replaceChild(replacement, script);
+	});
+
+	return true;
+};
+
 LiveReload.addPlugin(HugoReload)
 `, hugoNavigatePrefix)
 )
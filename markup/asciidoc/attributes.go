@@ -0,0 +1,176 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asciidoc
+
+import (
+	"regexp"
+	"strings"
+)
+
+// blockAttrs holds the attributes set in a block attribute line, e.g.
+// [source,go] or [quote, Author, Source], or in a macro, e.g.
+// image::sunset.jpg[Sunset,300,200].
+type blockAttrs struct {
+	// The block style, e.g. "source", the first positional attribute in a
+	// block attribute line.
+	style string
+
+	// The positional attributes.
+	pos []string
+
+	// The named attributes, e.g. cols="1,2".
+	named map[string]string
+
+	id      string
+	roles   []string
+	options []string
+}
+
+func (a blockAttrs) positional(i int) string {
+	if i < len(a.pos) {
+		return a.pos[i]
+	}
+	return ""
+}
+
+func (a blockAttrs) get(name string, i int) string {
+	if v, found := a.named[name]; found {
+		return v
+	}
+	return a.positional(i)
+}
+
+func (a blockAttrs) hasOption(name string) bool {
+	for _, o := range a.options {
+		if o == name {
+			return true
+		}
+	}
+	return false
+}
+
+var namedAttrRe = regexp.MustCompile(`^([\w][\w-]*)\s*=\s*(.*)$`)
+
+// parseBlockAttrs parses the attributes in a block attribute line, where
+// the first positional attribute may hold an ID, roles and options in
+// shorthand form, e.g. [source#hello.hl%linenums,go].
+func parseBlockAttrs(s string) blockAttrs {
+	return parseAttrs(s, true)
+}
+
+// parseMacroAttrs parses the attributes of a macro, e.g. an image.
+func parseMacroAttrs(s string) blockAttrs {
+	return parseAttrs(s, false)
+}
+
+func parseAttrs(s string, shorthand bool) blockAttrs {
+	a := blockAttrs{named: make(map[string]string)}
+
+	for i, part := range splitAttrs(s) {
+		if m := namedAttrRe.FindStringSubmatch(part); m != nil {
+			if i == 0 {
+				a.pos = append(a.pos, "")
+			}
+			name, value := strings.ToLower(m[1]), unquote(m[2])
+			switch name {
+			case "id":
+				a.id = value
+			case "role", "roles":
+				a.roles = append(a.roles, strings.Fields(value)...)
+			case "options", "opts":
+				for _, o := range strings.Split(value, ",") {
+					if o = strings.TrimSpace(o); o != "" {
+						a.options = append(a.options, o)
+					}
+				}
+			}
+			a.named[name] = value
+			continue
+		}
+
+		part = unquote(part)
+		if i == 0 && shorthand {
+			part = a.parseShorthand(part)
+			a.style = part
+		}
+		a.pos = append(a.pos, part)
+	}
+
+	return a
+}
+
+// parseShorthand sets the ID, roles and options in s, e.g.
+// "source#hello.hl%linenums", and returns the style, e.g. "source".
+func (a *blockAttrs) parseShorthand(s string) string {
+	end := strings.IndexAny(s, "#.%")
+	if end == -1 || strings.ContainsAny(s, " \t") {
+		return s
+	}
+	style := s[:end]
+
+	for rest := s[end:]; rest != ""; {
+		kind := rest[0]
+		rest = rest[1:]
+		n := strings.IndexAny(rest, "#.%")
+		if n == -1 {
+			n = len(rest)
+		}
+		value := rest[:n]
+		rest = rest[n:]
+		if value == "" {
+			continue
+		}
+		switch kind {
+		case '#':
+			a.id = value
+		case '.':
+			a.roles = append(a.roles, value)
+		case '%':
+			a.options = append(a.options, value)
+		}
+	}
+
+	return style
+}
+
+// splitAttrs splits s on the commas not inside double quotes.
+func splitAttrs(s string) []string {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+	var (
+		parts  []string
+		start  int
+		quoted bool
+	)
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			quoted = !quoted
+		case ',':
+			if !quoted {
+				parts = append(parts, strings.TrimSpace(s[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, strings.TrimSpace(s[start:]))
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
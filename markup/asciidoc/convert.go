@@ -0,0 +1,86 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package asciidoc converts AsciiDoc to HTML in pure Go, without the need
+// of an external Asciidoctor install. It supports the common block and
+// inline features and renders HTML compatible with the embedded output of
+// Asciidoctor, e.g. <div class="paragraph"><p>...</p></div>.
+package asciidoc
+
+import (
+	"bytes"
+
+	"github.com/gohugoio/hugo/identity"
+	"github.com/gohugoio/hugo/markup/converter"
+	"github.com/gohugoio/hugo/markup/tableofcontents"
+)
+
+// Provider is the package entry point.
+var Provider converter.ProviderProvider = provider{}
+
+type provider struct{}
+
+func (p provider) New(cfg converter.ProviderConfig) (converter.Provider, error) {
+	return converter.NewProvider("asciidoc", func(ctx converter.DocumentContext) (converter.Converter, error) {
+		return &asciidocConverter{
+			ctx: ctx,
+			cfg: cfg,
+		}, nil
+	}), nil
+}
+
+var _ identity.IdentitiesProvider = (*converterResult)(nil)
+
+type converterResult struct {
+	converter.Result
+	toc tableofcontents.Root
+	ids identity.Identities
+}
+
+func (r converterResult) TableOfContents() tableofcontents.Root {
+	return r.toc
+}
+
+func (r converterResult) GetIdentities() identity.Identities {
+	return r.ids
+}
+
+type asciidocConverter struct {
+	ctx converter.DocumentContext
+	cfg converter.ProviderConfig
+}
+
+var converterIdentity = identity.KeyValueIdentity{Key: "asciidoc", Value: "converter"}
+
+func (c *asciidocConverter) Convert(ctx converter.RenderContext) (converter.Result, error) {
+	d := newDocument(c.cfg, c.ctx, ctx)
+
+	var buf bytes.Buffer
+	if err := d.render(&buf); err != nil {
+		return nil, err
+	}
+
+	return converterResult{
+		Result: converter.Bytes(buf.Bytes()),
+		toc:    d.toc,
+		ids:    d.ids.GetIdentities(),
+	}, nil
+}
+
+var featureSet = map[identity.Identity]bool{
+	converter.FeatureRenderHooks: true,
+}
+
+func (c *asciidocConverter) Supports(feature identity.Identity) bool {
+	return featureSet[feature.GetIdentity()]
+}
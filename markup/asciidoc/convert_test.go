@@ -0,0 +1,236 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asciidoc
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/gohugoio/hugo/common/loggers"
+	"github.com/gohugoio/hugo/identity"
+	"github.com/gohugoio/hugo/markup/converter"
+	"github.com/gohugoio/hugo/markup/converter/hooks"
+	"github.com/gohugoio/hugo/markup/markup_config"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func convert(c *qt.C, mconf markup_config.Config, rctx converter.RenderContext) converter.Result {
+	p, err := Provider.New(
+		converter.ProviderConfig{
+			MarkupConfig: mconf,
+			Logger:       loggers.NewErrorLogger(),
+		},
+	)
+	c.Assert(err, qt.IsNil)
+	conv, err := p.New(converter.DocumentContext{DocumentID: "thedoc"})
+	c.Assert(err, qt.IsNil)
+	b, err := conv.Convert(rctx)
+	c.Assert(err, qt.IsNil)
+
+	return b
+}
+
+func convertString(c *qt.C, content string) string {
+	return string(convert(c, markup_config.Default, converter.RenderContext{Src: []byte(content)}).Bytes())
+}
+
+func TestConvert(t *testing.T) {
+	c := qt.New(t)
+
+	for _, test := range []struct {
+		name     string
+		content  string
+		expected []string
+	}{
+		{"Paragraph", "Hello *bold* and _em_ and `code`.\nNext line +\nbreak.", []string{
+			"<div class=\"paragraph\">\n<p>Hello <strong>bold</strong> and <em>em</em> and <code>code</code>.\nNext line<br>\nbreak.</p>\n</div>",
+		}},
+		{"Constrained", "snake_case_name and a*b*c but **un**constrained", []string{
+			"<p>snake_case_name and a*b*c but <strong>un</strong>constrained</p>",
+		}},
+		{"Header", "= The Title\nThe Author\n:product: Hugo\n\nBuilt with {product} and {unknown}.", []string{
+			"<p>Built with Hugo and {unknown}.</p>",
+		}},
+		{"Special characters", "a < b & c > d &copy; {nbsp}", []string{
+			"<p>a &lt; b &amp; c &gt; d &copy; &#160;</p>",
+		}},
+		{"Passthrough", "+++<span>raw</span>+++ and +*<kbd>*+ and pass:[<b>b</b>]", []string{
+			"<p><span>raw</span> and *&lt;kbd&gt;* and <b>b</b></p>",
+		}},
+		{"Replacements", "(C) it's -- a...", []string{
+			"<p>&#169; it&#8217;s&#8201;&#8212;&#8201;a&#8230;&#8203;</p>",
+		}},
+		{"Sections", "== First\n\nText.\n\n[[custom]]\n=== Second", []string{
+			"<div class=\"sect1\">\n<h2 id=\"_first\">First</h2>\n<div class=\"sectionbody\">",
+			"<div class=\"sect2\">\n<h3 id=\"custom\">Second</h3>\n</div>",
+		}},
+		{"Section numbers", ":sectnums:\n\n== One\n\n== Two", []string{
+			"<h2 id=\"_one\">1. One</h2>",
+			"<h2 id=\"_two\">2. Two</h2>",
+		}},
+		{"Unordered list", "* one\n* two\n** nested\n* [x] done", []string{
+			"<div class=\"ulist checklist\">\n<ul class=\"checklist\">",
+			"<li>\n<p>two</p>\n<div class=\"ulist\">\n<ul>\n<li>\n<p>nested</p>\n</li>\n</ul>\n</div>\n</li>",
+			"<p>&#10003; done</p>",
+		}},
+		{"Ordered list", "[start=3]\n. one\n. two", []string{
+			"<div class=\"olist arabic\">\n<ol class=\"arabic\" start=\"3\">\n<li>\n<p>one</p>\n</li>",
+		}},
+		{"Description list", "CPU:: The brain.", []string{
+			"<div class=\"dlist\">\n<dl>\n<dt class=\"hdlist1\">CPU</dt>\n<dd>\n<p>The brain.</p>\n</dd>\n</dl>\n</div>",
+		}},
+		{"Listing", "[source,go]\n----\nfmt.Println(\"<hi>\")\n----", []string{
+			`<div class="listingblock">`,
+			`<code class="language-go" data-lang="go">`,
+		}},
+		{"Literal", "....\nliteral <x>\n....", []string{
+			"<div class=\"literalblock\">\n<div class=\"content\">\n<pre>literal &lt;x&gt;</pre>\n</div>\n</div>",
+		}},
+		{"Admonition", "NOTE: Take care.", []string{
+			"<div class=\"admonitionblock note\">",
+			"<div class=\"title\">Note</div>",
+			"<td class=\"content\">\nTake care.\n</td>",
+		}},
+		{"Table", ".Numbers\n|===\n|A |B\n\n|1 |2\n|===", []string{
+			"<table class=\"tableblock frame-all grid-all stretch\">\n<caption class=\"title\">Table 1. Numbers</caption>",
+			"<col style=\"width: 50%;\">",
+			"<th class=\"tableblock halign-left valign-top\">A</th>",
+			"<td class=\"tableblock halign-left valign-top\"><p class=\"tableblock\">2</p></td>",
+		}},
+		{"Block image", "image::sunset.jpg[Sunset,300,200]", []string{
+			"<div class=\"imageblock\">\n<div class=\"content\">\n<img src=\"sunset.jpg\" alt=\"Sunset\" width=\"300\" height=\"200\">\n</div>\n</div>",
+		}},
+		{"Inline image", "An image:icons/tip.png[] icon.", []string{
+			`<span class="image"><img src="icons/tip.png" alt="tip"></span>`,
+		}},
+		{"Links", "https://gohugo.io. https://gohugo.io[Hugo^] link:/docs[Docs] mailto:a@b.com[]", []string{
+			`<a href="https://gohugo.io" class="bare">https://gohugo.io</a>.`,
+			`<a href="https://gohugo.io" target="_blank" rel="noopener">Hugo</a>`,
+			`<a href="/docs">Docs</a>`,
+			`<a href="mailto:a@b.com">a@b.com</a>`,
+		}},
+		{"Cross references", "== Install\n\nSee <<_install>>, <<_install,here>> and xref:other.adoc#intro[Intro].", []string{
+			`<a href="#_install">Install</a>`,
+			`<a href="#_install">here</a>`,
+			`<a href="other.html#intro">Intro</a>`,
+		}},
+		{"Footnotes", "A.footnote:[First.] B.footnote:[Second.]", []string{
+			`<sup class="footnote">[<a id="_footnoteref_2" class="footnote" href="#_footnotedef_2" title="View footnote.">2</a>]</sup>`,
+			"<div class=\"footnote\" id=\"_footnotedef_1\">\n<a href=\"#_footnoteref_1\">1</a>. First.\n</div>",
+		}},
+		{"Comments", "// A comment.\n////\nBlock comment.\n////\nText.", []string{
+			"<div class=\"paragraph\">\n<p>Text.</p>\n</div>\n",
+		}},
+	} {
+		test := test
+		c.Run(test.name, func(c *qt.C) {
+			got := convertString(c, test.content)
+			for _, expected := range test.expected {
+				c.Assert(got, qt.Contains, expected)
+			}
+		})
+	}
+}
+
+func TestConvertTableOfContents(t *testing.T) {
+	c := qt.New(t)
+
+	content := `
+== First
+
+=== Sub
+
+== Second
+`
+
+	b := convert(c, markup_config.Default, converter.RenderContext{Src: []byte(content), RenderTOC: true})
+	toc := b.(converter.TableOfContentsProvider).TableOfContents()
+
+	c.Assert(toc.ToHTML(2, 3, false), qt.Equals, `<nav id="TableOfContents">
+  <ul>
+    <li><a href="#_first">First</a>
+      <ul>
+        <li><a href="#_sub">Sub</a></li>
+      </ul>
+    </li>
+    <li><a href="#_second">Second</a></li>
+  </ul>
+</nav>`, qt.Commentf(toc.ToHTML(2, 3, false)))
+}
+
+type testLinkRenderer struct {
+	prefix string
+}
+
+func (r testLinkRenderer) RenderLink(w io.Writer, ctx hooks.LinkContext) error {
+	_, err := fmt.Fprintf(w, "%s|%s|%s", r.prefix, ctx.Destination(), ctx.Text())
+	return err
+}
+
+func (r testLinkRenderer) GetIdentity() identity.Identity {
+	return identity.NewPathIdentity("layouts", r.prefix)
+}
+
+type testHeadingRenderer struct{}
+
+func (r testHeadingRenderer) RenderHeading(w io.Writer, ctx hooks.HeadingContext) error {
+	_, err := fmt.Fprintf(w, "heading|%d|%s|%s", ctx.Level(), ctx.Anchor(), ctx.PlainText())
+	return err
+}
+
+func (r testHeadingRenderer) GetIdentity() identity.Identity {
+	return identity.NewPathIdentity("layouts", "heading")
+}
+
+func TestConvertRenderHooks(t *testing.T) {
+	c := qt.New(t)
+
+	content := `
+== The *Heading*
+
+https://gohugo.io[Hugo] and image:logo.png[Logo].
+`
+
+	b := convert(c, markup_config.Default, converter.RenderContext{
+		Src: []byte(content),
+		RenderHooks: hooks.Renderers{
+			LinkRenderer:    testLinkRenderer{prefix: "link"},
+			ImageRenderer:   testLinkRenderer{prefix: "image"},
+			HeadingRenderer: testHeadingRenderer{},
+		},
+	})
+
+	got := string(b.Bytes())
+	c.Assert(got, qt.Contains, "heading|2|_the_heading|The Heading")
+	c.Assert(got, qt.Contains, "link|https://gohugo.io|Hugo and")
+	c.Assert(got, qt.Contains, `<span class="image">image|logo.png|Logo</span>`)
+
+	ids := b.(identity.IdentitiesProvider).GetIdentities()
+	c.Assert(ids, qt.HasLen, 4)
+	c.Assert(ids[converterIdentity], qt.Not(qt.IsNil))
+}
+
+func TestConvertSupports(t *testing.T) {
+	c := qt.New(t)
+
+	p, err := Provider.New(converter.ProviderConfig{Logger: loggers.NewErrorLogger()})
+	c.Assert(err, qt.IsNil)
+	conv, err := p.New(converter.DocumentContext{})
+	c.Assert(err, qt.IsNil)
+	c.Assert(conv.Supports(converter.FeatureRenderHooks), qt.IsTrue)
+	c.Assert(strings.Contains(p.Name(), "asciidoc"), qt.IsTrue)
+}
@@ -0,0 +1,727 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asciidoc
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gohugoio/hugo/identity"
+	"github.com/gohugoio/hugo/markup/converter"
+	"github.com/gohugoio/hugo/markup/tableofcontents"
+)
+
+var (
+	attributeEntryRe = regexp.MustCompile(`^:(!?)([\w][\w-]*)(!?):(?:\s+(.*))?$`)
+	blockAnchorRe    = regexp.MustCompile(`^\[\[([^\[\],]+)(?:,\s*(.+))?\]\]$`)
+	blockAttrsRe     = regexp.MustCompile(`^\[([^\[\]].*)?\]$`)
+	blockTitleRe     = regexp.MustCompile(`^\.([^\s.].*)$`)
+	sectionTitleRe   = regexp.MustCompile(`^(={1,6})\s+(\S.*?)(?:\s+=+)?$`)
+	blockMacroRe     = regexp.MustCompile(`^(image|include|toc)::([^\[\s]*)\[(.*)\]$`)
+	listItemRe       = regexp.MustCompile(`^\s*(\*{1,5}|-|\.{1,5}|\d+\.)\s+(.*)$`)
+	dlistItemRe      = regexp.MustCompile(`^(\S.*?)(:{2,4}|;;)(?:\s+(.*))?$`)
+	admonitionRe     = regexp.MustCompile(`^(NOTE|TIP|IMPORTANT|WARNING|CAUTION):\s+(.*)$`)
+)
+
+var admonitionLabels = map[string]string{
+	"NOTE":      "Note",
+	"TIP":       "Tip",
+	"IMPORTANT": "Important",
+	"WARNING":   "Warning",
+	"CAUTION":   "Caution",
+}
+
+// document holds the state of a conversion.
+type document struct {
+	cfg  converter.ProviderConfig
+	dctx converter.DocumentContext
+	rctx converter.RenderContext
+
+	lines []string
+	attrs map[string]string
+
+	ids identity.Manager
+	toc tableofcontents.Root
+
+	tocRow      int
+	sectionNums []int
+	usedIDs     map[string]bool
+
+	// The section titles keyed by their ID, the default text of the cross
+	// references to them.
+	sectionTitles map[string]string
+
+	footnotes   []string
+	footnoteIDs map[string]int
+
+	// The figure, table and example counters used in captions.
+	counters map[string]int
+
+	err error
+}
+
+func newDocument(cfg converter.ProviderConfig, dctx converter.DocumentContext, rctx converter.RenderContext) *document {
+	src := strings.ReplaceAll(string(rctx.Src), "\r\n", "\n")
+
+	d := &document{
+		cfg:           cfg,
+		dctx:          dctx,
+		rctx:          rctx,
+		lines:         strings.Split(src, "\n"),
+		ids:           identity.NewManager(converterIdentity),
+		tocRow:        -1,
+		sectionTitles: make(map[string]string),
+		footnoteIDs:   make(map[string]int),
+		counters:      make(map[string]int),
+		attrs: map[string]string{
+			"idprefix":        "_",
+			"idseparator":     "_",
+			"sectids":         "",
+			"figure-caption":  "Figure",
+			"table-caption":   "Table",
+			"example-caption": "Example",
+		},
+	}
+
+	for k, v := range cfg.MarkupConfig.AsciidocExt.Attributes {
+		d.attrs[strings.ToLower(k)] = v
+	}
+
+	return d
+}
+
+func (d *document) render(w *bytes.Buffer) error {
+	lines := d.parseHeader(d.lines)
+
+	d.usedIDs = make(map[string]bool)
+	d.collectSectionTitles(lines)
+	d.usedIDs = make(map[string]bool)
+
+	d.renderBlocks(w, lines)
+	d.renderFootnotes(w)
+
+	return d.err
+}
+
+// parseHeader handles the document title, which is not rendered, and the
+// author, revision and attribute lines below it. It returns the lines of
+// the document body.
+func (d *document) parseHeader(lines []string) []string {
+	i := 0
+	for i < len(lines) && (strings.TrimSpace(lines[i]) == "" || isCommentLine(lines[i])) {
+		i++
+	}
+	if i == len(lines) {
+		return nil
+	}
+
+	m := sectionTitleRe.FindStringSubmatch(lines[i])
+	if m == nil || len(m[1]) != 1 {
+		return lines
+	}
+	d.attrs["doctitle"] = m[2]
+
+	for i++; i < len(lines) && strings.TrimSpace(lines[i]) != ""; i++ {
+		if m := attributeEntryRe.FindStringSubmatch(lines[i]); m != nil {
+			d.setAttribute(m)
+		}
+	}
+
+	return lines[i:]
+}
+
+func isCommentLine(line string) bool {
+	return strings.HasPrefix(line, "//") && !strings.HasPrefix(line, "////")
+}
+
+func (d *document) setAttribute(m []string) {
+	name := strings.ToLower(m[2])
+	if m[1] == "!" || m[3] == "!" {
+		delete(d.attrs, name)
+		return
+	}
+	d.attrs[name] = d.substituteAttributes(strings.TrimSpace(m[4]))
+}
+
+// delimiterKind returns the kind of the delimited block opened by line,
+// if any.
+func delimiterKind(line string) (string, bool) {
+	line = strings.TrimRight(line, " \t")
+	if line == "--" {
+		return "open", true
+	}
+	if strings.HasPrefix(line, "|===") && strings.Trim(line[1:], "=") == "" {
+		return "table", true
+	}
+	if len(line) < 4 || strings.Count(line, line[:1]) != len(line) {
+		return "", false
+	}
+	switch line[0] {
+	case '-':
+		return "listing", true
+	case '.':
+		return "literal", true
+	case '=':
+		return "example", true
+	case '*':
+		return "sidebar", true
+	case '_':
+		return "quote", true
+	case '+':
+		return "pass", true
+	case '/':
+		return "comment", true
+	}
+	return "", false
+}
+
+// findClose returns the index of the line closing the delimited block
+// opened at lines[i], or len(lines) if it's not closed.
+func findClose(lines []string, i int) int {
+	delim := strings.TrimRight(lines[i], " \t")
+	for j := i + 1; j < len(lines); j++ {
+		if strings.TrimRight(lines[j], " \t") == delim {
+			return j
+		}
+	}
+	return len(lines)
+}
+
+func isBlockMetaLine(line string) bool {
+	return blockAnchorRe.MatchString(line) || blockAttrsRe.MatchString(line) || blockTitleRe.MatchString(line)
+}
+
+// sectionEnd returns the index of the first line after the section body
+// starting at lines[start], i.e. the next section of the same or a higher
+// level, including the block attribute lines above it.
+func sectionEnd(lines []string, start, level int) int {
+	for j := start; j < len(lines); j++ {
+		if _, ok := delimiterKind(lines[j]); ok {
+			j = findClose(lines, j)
+			continue
+		}
+		if m := sectionTitleRe.FindStringSubmatch(lines[j]); m != nil && len(m[1])-1 <= level {
+			for j > start && isBlockMetaLine(lines[j-1]) {
+				j--
+			}
+			return j
+		}
+	}
+	return len(lines)
+}
+
+// collectSectionTitles collects the IDs and titles of the sections, so the
+// cross references before a section can use its title.
+func (d *document) collectSectionTitles(lines []string) {
+	var id string
+	for j := 0; j < len(lines); j++ {
+		line := lines[j]
+		if _, ok := delimiterKind(line); ok {
+			j = findClose(lines, j)
+			id = ""
+			continue
+		}
+		if m := blockAnchorRe.FindStringSubmatch(line); m != nil {
+			id = m[1]
+			continue
+		}
+		if m := blockAttrsRe.FindStringSubmatch(line); m != nil {
+			if a := parseBlockAttrs(m[1]); a.id != "" {
+				id = a.id
+			}
+			continue
+		}
+		if m := sectionTitleRe.FindStringSubmatch(line); m != nil {
+			d.sectionTitles[d.sectionID(m[2], id)] = m[2]
+		}
+		if strings.TrimSpace(line) == "" || !blockTitleRe.MatchString(line) {
+			id = ""
+		}
+	}
+}
+
+// sectionID returns the given explicit ID, or one generated from the
+// title, e.g. "_getting_started", unique in the document.
+func (d *document) sectionID(title, id string) string {
+	if id != "" {
+		d.usedIDs[id] = true
+		return id
+	}
+	if _, ok := d.attrs["sectids"]; !ok {
+		return ""
+	}
+
+	sep := d.attrs["idseparator"]
+	var b strings.Builder
+	b.WriteString(d.attrs["idprefix"])
+	for _, r := range strings.ToLower(stripTags(d.substituteAttributes(title))) {
+		switch {
+		case r == ' ' || r == '.' || r == '-':
+			if sep != "" && !strings.HasSuffix(b.String(), sep) {
+				b.WriteString(sep)
+			}
+		case r == '_' || isWordRune(r):
+			b.WriteRune(r)
+		}
+	}
+	id = b.String()
+	if sep != "" {
+		id = strings.TrimSuffix(id, sep)
+	}
+
+	base := id
+	for i := 2; d.usedIDs[id]; i++ {
+		id = base + sep + strconv.Itoa(i)
+	}
+	d.usedIDs[id] = true
+
+	return id
+}
+
+var tagRe = regexp.MustCompile(`<[^>]+>`)
+
+func stripTags(s string) string {
+	return tagRe.ReplaceAllString(s, "")
+}
+
+// blockMeta holds the title, ID and attributes set on the lines above a
+// block.
+type blockMeta struct {
+	title string
+	id    string
+	attrs blockAttrs
+}
+
+func (m blockMeta) style() string {
+	return m.attrs.style
+}
+
+// openDiv writes the opening div of a block with the given class, and its
+// title, if any.
+func (d *document) openDiv(w *bytes.Buffer, class string, meta blockMeta) {
+	d.openTag(w, "div", class, meta)
+	w.WriteString(">\n")
+	if meta.title != "" {
+		fmt.Fprintf(w, "<div class=\"title\">%s</div>\n", d.inline(meta.title))
+	}
+}
+
+func (d *document) openTag(w *bytes.Buffer, tag, class string, meta blockMeta) {
+	w.WriteString("<" + tag)
+	if meta.id != "" {
+		fmt.Fprintf(w, " id=\"%s\"", html.EscapeString(meta.id))
+	}
+	classes := strings.TrimSpace(strings.Join(append([]string{class}, meta.attrs.roles...), " "))
+	if classes != "" {
+		fmt.Fprintf(w, " class=\"%s\"", html.EscapeString(classes))
+	}
+}
+
+// caption returns the numbered caption of a block title, e.g. "Table 1. ".
+func (d *document) caption(kind string, meta blockMeta) string {
+	if meta.title == "" {
+		return ""
+	}
+	if c, found := meta.attrs.named["caption"]; found {
+		return c
+	}
+	label, found := d.attrs[kind+"-caption"]
+	if !found || label == "" {
+		return ""
+	}
+	d.counters[kind]++
+	return fmt.Sprintf("%s %d. ", label, d.counters[kind])
+}
+
+func (d *document) renderBlocks(w *bytes.Buffer, lines []string) {
+	var meta blockMeta
+
+	for i := 0; i < len(lines); {
+		line := strings.TrimRight(lines[i], " \t")
+
+		if line == "" {
+			i++
+			continue
+		}
+
+		if kind, ok := delimiterKind(line); ok {
+			end := findClose(lines, i)
+			if kind != "comment" {
+				d.renderDelimited(w, kind, lines[i+1:min(end, len(lines))], meta)
+			}
+			meta = blockMeta{}
+			i = end + 1
+			continue
+		}
+
+		if isCommentLine(line) {
+			i++
+			continue
+		}
+
+		if m := attributeEntryRe.FindStringSubmatch(line); m != nil {
+			d.setAttribute(m)
+			i++
+			continue
+		}
+
+		if m := blockAnchorRe.FindStringSubmatch(line); m != nil {
+			meta.id = m[1]
+			i++
+			continue
+		}
+
+		if m := blockAttrsRe.FindStringSubmatch(line); m != nil {
+			meta.attrs = parseBlockAttrs(m[1])
+			if meta.attrs.id != "" {
+				meta.id = meta.attrs.id
+			}
+			i++
+			continue
+		}
+
+		if m := blockTitleRe.FindStringSubmatch(line); m != nil {
+			meta.title = m[1]
+			i++
+			continue
+		}
+
+		if m := sectionTitleRe.FindStringSubmatch(line); m != nil {
+			level := len(m[1]) - 1
+			end := sectionEnd(lines, i+1, level)
+			d.renderSection(w, level, m[2], lines[i+1:end], meta)
+			meta = blockMeta{}
+			i = end
+			continue
+		}
+
+		switch line {
+		case "'''":
+			w.WriteString("<hr>\n")
+			meta = blockMeta{}
+			i++
+			continue
+		case "<<<":
+			w.WriteString("<div style=\"page-break-after: always;\"></div>\n")
+			meta = blockMeta{}
+			i++
+			continue
+		}
+
+		if m := blockMacroRe.FindStringSubmatch(line); m != nil {
+			d.renderBlockMacro(w, m[1], m[2], m[3], meta)
+			meta = blockMeta{}
+			i++
+			continue
+		}
+
+		if listItemRe.MatchString(line) && meta.style() != "literal" && meta.style() != "source" {
+			i = d.renderList(w, lines, i, nil, meta)
+			meta = blockMeta{}
+			continue
+		}
+
+		if dlistItemRe.MatchString(line) && !strings.HasPrefix(line, " ") {
+			i = d.renderDlist(w, lines, i, meta)
+			meta = blockMeta{}
+			continue
+		}
+
+		end := i + 1
+		for end < len(lines) && strings.TrimSpace(lines[end]) != "" {
+			if _, ok := delimiterKind(lines[end]); ok {
+				break
+			}
+			end++
+		}
+
+		d.renderParagraph(w, lines[i:end], meta)
+		meta = blockMeta{}
+		i = end
+	}
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func (d *document) renderSection(w *bytes.Buffer, level int, title string, body []string, meta blockMeta) {
+	id := d.sectionID(title, meta.id)
+	text := d.inline(title)
+
+	if level > 0 {
+		if _, ok := d.attrs["sectnums"]; ok {
+			for len(d.sectionNums) < level {
+				d.sectionNums = append(d.sectionNums, 0)
+			}
+			d.sectionNums = d.sectionNums[:level]
+			d.sectionNums[level-1]++
+			var num string
+			for _, n := range d.sectionNums {
+				num += strconv.Itoa(n) + "."
+			}
+			text = num + " " + text
+		}
+	}
+
+	if level == 0 || d.tocRow == -1 {
+		d.tocRow++
+	}
+	d.toc.AddAt(tableofcontents.Heading{ID: id, Text: text}, d.tocRow, level)
+
+	if level == 0 {
+		meta.attrs.roles = append([]string{"sect0"}, meta.attrs.roles...)
+		d.renderHeading(w, 1, id, text, title, meta)
+		d.renderBlocks(w, body)
+		return
+	}
+
+	d.openTag(w, "div", "sect"+strconv.Itoa(level), blockMeta{attrs: meta.attrs})
+	w.WriteString(">\n")
+	d.renderHeading(w, level+1, id, text, title, blockMeta{})
+	if level == 1 {
+		w.WriteString("<div class=\"sectionbody\">\n")
+	}
+	d.renderBlocks(w, body)
+	if level == 1 {
+		w.WriteString("</div>\n")
+	}
+	w.WriteString("</div>\n")
+}
+
+func (d *document) renderParagraph(w *bytes.Buffer, lines []string, meta blockMeta) {
+	switch style := meta.style(); {
+	case style == "source" || style == "listing":
+		d.renderListing(w, lines, meta)
+		return
+	case style == "literal" || (style == "" && (strings.HasPrefix(lines[0], " ") || strings.HasPrefix(lines[0], "\t"))):
+		d.renderLiteral(w, dedent(lines), meta)
+		return
+	case style == "quote" || style == "verse":
+		d.renderQuote(w, lines, meta, false)
+		return
+	case admonitionLabels[style] != "":
+		d.renderAdmonition(w, style, meta, func() {
+			w.WriteString(d.inlineLines(lines))
+			w.WriteString("\n")
+		})
+		return
+	}
+
+	if m := admonitionRe.FindStringSubmatch(lines[0]); m != nil {
+		rest := append([]string{m[2]}, lines[1:]...)
+		d.renderAdmonition(w, m[1], meta, func() {
+			w.WriteString(d.inlineLines(rest))
+			w.WriteString("\n")
+		})
+		return
+	}
+
+	d.openDiv(w, "paragraph", meta)
+	fmt.Fprintf(w, "<p>%s</p>\n</div>\n", d.inlineLines(lines))
+}
+
+// dedent removes the indentation common to all the lines.
+func dedent(lines []string) []string {
+	indent := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		n := len(line) - len(strings.TrimLeft(line, " \t"))
+		if indent == -1 || n < indent {
+			indent = n
+		}
+	}
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		if len(line) >= indent && indent > 0 {
+			line = line[indent:]
+		}
+		out[i] = line
+	}
+	return out
+}
+
+func (d *document) renderDelimited(w *bytes.Buffer, kind string, lines []string, meta blockMeta) {
+	style := meta.style()
+
+	switch kind {
+	case "listing":
+		d.renderListing(w, lines, meta)
+	case "literal":
+		d.renderLiteral(w, lines, meta)
+	case "pass":
+		w.WriteString(strings.Join(lines, "\n"))
+		w.WriteString("\n")
+	case "quote":
+		d.renderQuote(w, lines, meta, true)
+	case "table":
+		d.renderTable(w, lines, meta)
+	case "example", "open", "sidebar":
+		if admonitionLabels[style] != "" {
+			d.renderAdmonition(w, style, meta, func() {
+				d.renderBlocks(w, lines)
+			})
+			return
+		}
+		class := kind + "block"
+		if kind == "example" {
+			if c := d.caption("example", meta); c != "" {
+				meta.title = c + meta.title
+			}
+		}
+		d.openDiv(w, class, meta)
+		w.WriteString("<div class=\"content\">\n")
+		d.renderBlocks(w, lines)
+		w.WriteString("</div>\n</div>\n")
+	}
+}
+
+func (d *document) renderListing(w *bytes.Buffer, lines []string, meta blockMeta) {
+	code := strings.Join(lines, "\n")
+
+	lang := ""
+	if meta.style() == "source" {
+		lang = meta.attrs.positional(1)
+		if lang == "" {
+			lang = d.attrs["source-language"]
+		}
+	}
+
+	d.openDiv(w, "listingblock", meta)
+	w.WriteString("<div class=\"content\">\n")
+
+	if lang != "" && d.cfg.Highlight != nil && d.cfg.MarkupConfig.Highlight.CodeFences {
+		if highlighted, err := d.cfg.Highlight(code, lang, ""); err == nil {
+			w.WriteString(highlighted)
+			w.WriteString("\n</div>\n</div>\n")
+			return
+		}
+	}
+
+	switch {
+	case lang != "":
+		fmt.Fprintf(w, "<pre class=\"highlight\"><code class=\"language-%[1]s\" data-lang=\"%[1]s\">%s</code></pre>\n", html.EscapeString(lang), html.EscapeString(code))
+	case meta.style() == "source":
+		fmt.Fprintf(w, "<pre class=\"highlight\"><code>%s</code></pre>\n", html.EscapeString(code))
+	default:
+		fmt.Fprintf(w, "<pre>%s</pre>\n", html.EscapeString(code))
+	}
+
+	w.WriteString("</div>\n</div>\n")
+}
+
+func (d *document) renderLiteral(w *bytes.Buffer, lines []string, meta blockMeta) {
+	d.openDiv(w, "literalblock", meta)
+	fmt.Fprintf(w, "<div class=\"content\">\n<pre>%s</pre>\n</div>\n</div>\n", html.EscapeString(strings.Join(lines, "\n")))
+}
+
+func (d *document) renderQuote(w *bytes.Buffer, lines []string, meta blockMeta, delimited bool) {
+	verse := meta.style() == "verse"
+	if verse {
+		d.openDiv(w, "verseblock", meta)
+		fmt.Fprintf(w, "<pre class=\"content\">%s</pre>\n", d.inline(strings.Join(lines, "\n")))
+	} else {
+		d.openDiv(w, "quoteblock", meta)
+		w.WriteString("<blockquote>\n")
+		if delimited {
+			d.renderBlocks(w, lines)
+		} else {
+			w.WriteString(d.inlineLines(lines))
+			w.WriteString("\n")
+		}
+		w.WriteString("</blockquote>\n")
+	}
+
+	attribution, citetitle := meta.attrs.positional(1), meta.attrs.positional(2)
+	if attribution != "" || citetitle != "" {
+		w.WriteString("<div class=\"attribution\">\n")
+		if attribution != "" {
+			fmt.Fprintf(w, "&#8212; %s", d.inline(attribution))
+		}
+		if citetitle != "" {
+			if attribution != "" {
+				w.WriteString("<br>\n")
+			}
+			fmt.Fprintf(w, "<cite>%s</cite>", d.inline(citetitle))
+		}
+		w.WriteString("\n</div>\n")
+	}
+
+	w.WriteString("</div>\n")
+}
+
+func (d *document) renderAdmonition(w *bytes.Buffer, name string, meta blockMeta, content func()) {
+	d.openTag(w, "div", "admonitionblock "+strings.ToLower(name), meta)
+	w.WriteString(">\n<table>\n<tr>\n<td class=\"icon\">\n")
+	fmt.Fprintf(w, "<div class=\"title\">%s</div>\n", admonitionLabels[name])
+	w.WriteString("</td>\n<td class=\"content\">\n")
+	if meta.title != "" {
+		fmt.Fprintf(w, "<div class=\"title\">%s</div>\n", d.inline(meta.title))
+	}
+	content()
+	w.WriteString("</td>\n</tr>\n</table>\n</div>\n")
+}
+
+func (d *document) renderBlockMacro(w *bytes.Buffer, name, target, attrs string, meta blockMeta) {
+	target = d.substituteAttributes(target)
+	a := parseMacroAttrs(attrs)
+
+	switch name {
+	case "image":
+		caption := d.caption("figure", meta)
+		d.openTag(w, "div", "imageblock", meta)
+		w.WriteString(">\n<div class=\"content\">\n")
+		w.WriteString(d.image(target, a, meta.title))
+		w.WriteString("\n</div>\n")
+		if meta.title != "" {
+			fmt.Fprintf(w, "<div class=\"title\">%s%s</div>\n", caption, d.inline(meta.title))
+		}
+		w.WriteString("</div>\n")
+	case "include":
+		// Includes are not supported, render them as Asciidoctor does
+		// unresolved ones.
+		d.openDiv(w, "paragraph", blockMeta{})
+		fmt.Fprintf(w, "<p>Unresolved directive in %s - include::%s[%s]</p>\n</div>\n", html.EscapeString(d.documentName()), html.EscapeString(target), html.EscapeString(attrs))
+	case "toc":
+		// The table of contents is available in .TableOfContents.
+	}
+}
+
+func (d *document) documentName() string {
+	if d.dctx.DocumentName != "" {
+		return d.dctx.DocumentName
+	}
+	return "<stdin>"
+}
+
+func (d *document) renderFootnotes(w *bytes.Buffer) {
+	if len(d.footnotes) == 0 {
+		return
+	}
+	w.WriteString("<div id=\"footnotes\">\n<hr>\n")
+	for i, text := range d.footnotes {
+		n := i + 1
+		fmt.Fprintf(w, "<div class=\"footnote\" id=\"_footnotedef_%d\">\n<a href=\"#_footnoteref_%d\">%d</a>. %s\n</div>\n", n, n, n, text)
+	}
+	w.WriteString("</div>\n")
+}
@@ -0,0 +1,122 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asciidoc
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/gohugoio/hugo/markup/converter/hooks"
+)
+
+type linkContext struct {
+	page        interface{}
+	destination string
+	title       string
+	text        string
+	plainText   string
+}
+
+func (ctx linkContext) Page() interface{} {
+	return ctx.page
+}
+
+func (ctx linkContext) Destination() string {
+	return ctx.destination
+}
+
+func (ctx linkContext) Title() string {
+	return ctx.title
+}
+
+func (ctx linkContext) Text() string {
+	return ctx.text
+}
+
+func (ctx linkContext) PlainText() string {
+	return ctx.plainText
+}
+
+type headingContext struct {
+	page       interface{}
+	level      int
+	anchor     string
+	text       string
+	plainText  string
+	attributes map[string]string
+}
+
+func (ctx headingContext) Page() interface{} {
+	return ctx.page
+}
+
+func (ctx headingContext) Level() int {
+	return ctx.level
+}
+
+func (ctx headingContext) Anchor() string {
+	return ctx.anchor
+}
+
+func (ctx headingContext) Text() string {
+	return ctx.text
+}
+
+func (ctx headingContext) PlainText() string {
+	return ctx.plainText
+}
+
+func (ctx headingContext) Attributes() map[string]string {
+	return ctx.attributes
+}
+
+var (
+	_ hooks.LinkContext    = linkContext{}
+	_ hooks.HeadingContext = headingContext{}
+)
+
+func (d *document) renderHeading(w io.Writer, level int, id, text, title string, meta blockMeta) {
+	if h := d.rctx.RenderHooks.HeadingRenderer; h != nil {
+		attributes := make(map[string]string)
+		if len(meta.attrs.roles) > 0 {
+			attributes["class"] = strings.Join(meta.attrs.roles, " ")
+		}
+		err := h.RenderHeading(w, headingContext{
+			page:       d.dctx.Document,
+			level:      level,
+			anchor:     id,
+			text:       text,
+			plainText:  plainText(text),
+			attributes: attributes,
+		})
+		d.ids.Add(h)
+		d.setErr(err)
+		io.WriteString(w, "\n")
+		return
+	}
+
+	var b bytes.Buffer
+	d.openTag(&b, "h"+strconv.Itoa(level), "", blockMeta{id: id, attrs: blockAttrs{roles: meta.attrs.roles}})
+	fmt.Fprintf(&b, ">%s</h%d>\n", text, level)
+	w.Write(b.Bytes())
+}
+
+func (d *document) setErr(err error) {
+	if err != nil && d.err == nil {
+		d.err = err
+	}
+}
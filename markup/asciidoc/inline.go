@@ -0,0 +1,555 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asciidoc
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// The placeholders for the already converted inline content.
+const (
+	placeholderStart = "\u0096"
+	placeholderEnd   = "\u0097"
+)
+
+var (
+	placeholderRe = regexp.MustCompile(placeholderStart + `(\d+)` + placeholderEnd)
+
+	passMacroRe     = regexp.MustCompile(`pass:[a-z,]*\[((?:\\\]|[^\]])*)\]`)
+	triplePlusRe    = regexp.MustCompile(`\+\+\+(.+?)\+\+\+`)
+	doublePlusRe    = regexp.MustCompile(`\+\+(.+?)\+\+`)
+	attributeRefRe  = regexp.MustCompile(`\\?\{([\w][\w-]*)\}`)
+	footnoteRe      = regexp.MustCompile(`footnote:([\w][\w-]*)?\[((?:\\\]|[^\]])*)\]`)
+	inlineAnchorRe  = regexp.MustCompile(`\[\[([\w:][\w:.-]*)(?:,\s*[^\]]*)?\]\]|anchor:([\w:][\w:.-]*)\[[^\]]*\]`)
+	xrefRe          = regexp.MustCompile(`<<([\w":./#-][^,>]*?)(?:,\s*([^>]+?))?>>|xref:([^\s\[]+)\[((?:\\\]|[^\]])*)\]`)
+	inlineImageRe   = regexp.MustCompile(`image:([^\s:\[][^\s\[]*)\[((?:\\\]|[^\]])*)\]`)
+	linkMacroRe     = regexp.MustCompile(`(link|mailto):([^\s\[]+)\[((?:\\\]|[^\]])*)\]`)
+	angleURLRe      = regexp.MustCompile(`<((?:https?|ftp|irc)://[^\s>]+)>`)
+	urlRe           = regexp.MustCompile(`(^|[^\w/"'=])((?:https?|ftp|irc)://[^\s\[\]<>"]+)(?:\[((?:\\\]|[^\]])*)\])?`)
+	superscriptRe   = regexp.MustCompile(`\^(\S+?)\^`)
+	subscriptRe     = regexp.MustCompile(`~(\S+?)~`)
+	hardBreakRe     = regexp.MustCompile(`(?m) \+$`)
+	entityRefRe     = regexp.MustCompile(`&amp;([a-zA-Z][a-zA-Z]+\d{0,2}|#\d\d\d{0,4}|#x[\da-fA-F][\da-fA-F][\da-fA-F]{0,3});`)
+	spacedDashRe    = regexp.MustCompile(`(^|\n| )--( |\n|$)`)
+	wordDashRe      = regexp.MustCompile(`(\w)--(\w)`)
+	apostropheRe    = regexp.MustCompile(`(\w)'(\w)`)
+	specialCharsRep = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	replacementsRep = strings.NewReplacer(
+		"(C)", "&#169;",
+		"(R)", "&#174;",
+		"(TM)", "&#8482;",
+		"...", "&#8230;&#8203;",
+		"-&gt;", "&#8594;",
+		"=&gt;", "&#8658;",
+		"&lt;-", "&#8592;",
+		"&lt;=", "&#8656;",
+	)
+)
+
+// The predefined attributes for special characters.
+var characterAttributes = map[string]string{
+	"empty":          "",
+	"sp":             " ",
+	"nbsp":           "&#160;",
+	"zwsp":           "&#8203;",
+	"wj":             "&#8288;",
+	"apos":           "&#39;",
+	"quot":           "&#34;",
+	"lsquo":          "&#8216;",
+	"rsquo":          "&#8217;",
+	"ldquo":          "&#8220;",
+	"rdquo":          "&#8221;",
+	"deg":            "&#176;",
+	"plus":           "&#43;",
+	"brvbar":         "&#166;",
+	"vbar":           "|",
+	"amp":            "&amp;",
+	"lt":             "&lt;",
+	"gt":             "&gt;",
+	"startsb":        "[",
+	"endsb":          "]",
+	"caret":          "^",
+	"asterisk":       "*",
+	"tilde":          "~",
+	"backslash":      "\\",
+	"backtick":       "`",
+	"two-colons":     "::",
+	"two-semicolons": ";;",
+}
+
+// substituteAttributes replaces the attribute references in s, e.g.
+// {name}, with their values.
+func (d *document) substituteAttributes(s string) string {
+	if !strings.Contains(s, "{") {
+		return s
+	}
+	return attributeRefRe.ReplaceAllStringFunc(s, func(m string) string {
+		if m[0] == '\\' {
+			return m[1:]
+		}
+		name := strings.ToLower(m[1 : len(m)-1])
+		if v, found := d.attrs[name]; found {
+			return v
+		}
+		if v, found := characterAttributes[name]; found {
+			return html.UnescapeString(v)
+		}
+		return m
+	})
+}
+
+// inlineLines converts the inline content in lines.
+func (d *document) inlineLines(lines []string) string {
+	trimmed := make([]string, len(lines))
+	for i, line := range lines {
+		trimmed[i] = strings.TrimRight(line, " \t")
+	}
+	return d.inline(strings.Join(trimmed, "\n"))
+}
+
+// inline converts the inline content in s, doing the substitutions in
+// mostly the same order as Asciidoctor.
+func (d *document) inline(s string) string {
+	st := &inlineState{d: d}
+
+	s = st.passthroughs(s)
+	s = st.attributes(s)
+	s = st.macros(s)
+	s = specialCharsRep.Replace(s)
+	s = quotes(s)
+	s = replacements(s)
+	s = hardBreakRe.ReplaceAllString(s, "<br>")
+
+	return st.restore(s)
+}
+
+type inlineState struct {
+	d            *document
+	placeholders []string
+}
+
+// hold returns a placeholder for the converted content s.
+func (st *inlineState) hold(s string) string {
+	st.placeholders = append(st.placeholders, s)
+	return placeholderStart + strconv.Itoa(len(st.placeholders)-1) + placeholderEnd
+}
+
+func (st *inlineState) restore(s string) string {
+	if len(st.placeholders) == 0 {
+		return s
+	}
+	return placeholderRe.ReplaceAllStringFunc(s, func(m string) string {
+		i, _ := strconv.Atoi(m[len(placeholderStart) : len(m)-len(placeholderEnd)])
+		return st.placeholders[i]
+	})
+}
+
+func (st *inlineState) passthroughs(s string) string {
+	if !strings.ContainsAny(s, "+:") {
+		return s
+	}
+	s = passMacroRe.ReplaceAllStringFunc(s, func(m string) string {
+		return st.hold(strings.ReplaceAll(passMacroRe.FindStringSubmatch(m)[1], `\]`, "]"))
+	})
+	s = triplePlusRe.ReplaceAllStringFunc(s, func(m string) string {
+		return st.hold(m[3 : len(m)-3])
+	})
+	s = doublePlusRe.ReplaceAllStringFunc(s, func(m string) string {
+		return st.hold(specialCharsRep.Replace(m[2 : len(m)-2]))
+	})
+	return replaceConstrained(s, '+', func(inner string) string {
+		return st.hold(specialCharsRep.Replace(inner))
+	})
+}
+
+func (st *inlineState) attributes(s string) string {
+	if !strings.Contains(s, "{") {
+		return s
+	}
+	return attributeRefRe.ReplaceAllStringFunc(s, func(m string) string {
+		if m[0] == '\\' {
+			return st.hold(m[1:])
+		}
+		name := strings.ToLower(m[1 : len(m)-1])
+		if v, found := st.d.attrs[name]; found {
+			return v
+		}
+		if v, found := characterAttributes[name]; found {
+			return st.hold(v)
+		}
+		return st.hold(m)
+	})
+}
+
+func (st *inlineState) macros(s string) string {
+	d := st.d
+
+	if strings.Contains(s, "footnote:") {
+		s = footnoteRe.ReplaceAllStringFunc(s, func(m string) string {
+			sm := footnoteRe.FindStringSubmatch(m)
+			return st.hold(d.footnote(sm[1], unescapeBracket(sm[2])))
+		})
+	}
+
+	if strings.Contains(s, "[[") || strings.Contains(s, "anchor:") {
+		s = inlineAnchorRe.ReplaceAllStringFunc(s, func(m string) string {
+			sm := inlineAnchorRe.FindStringSubmatch(m)
+			id := sm[1] + sm[2]
+			return st.hold(fmt.Sprintf("<a id=\"%s\"></a>", html.EscapeString(id)))
+		})
+	}
+
+	if strings.Contains(s, "<<") || strings.Contains(s, "xref:") {
+		s = xrefRe.ReplaceAllStringFunc(s, func(m string) string {
+			sm := xrefRe.FindStringSubmatch(m)
+			target, text := sm[1], sm[2]
+			if target == "" {
+				target, text = sm[3], unescapeBracket(sm[4])
+			}
+			return st.hold(d.xref(strings.Trim(target, `"`), strings.Trim(text, `"`)))
+		})
+	}
+
+	if strings.Contains(s, "image:") {
+		s = inlineImageRe.ReplaceAllStringFunc(s, func(m string) string {
+			sm := inlineImageRe.FindStringSubmatch(m)
+			a := parseMacroAttrs(unescapeBracket(sm[2]))
+			return st.hold("<span class=\"image\">" + d.image(sm[1], a, a.named["title"]) + "</span>")
+		})
+	}
+
+	if strings.Contains(s, ":") {
+		s = linkMacroRe.ReplaceAllStringFunc(s, func(m string) string {
+			sm := linkMacroRe.FindStringSubmatch(m)
+			target := sm[2]
+			if sm[1] == "mailto" {
+				target = "mailto:" + target
+			}
+			text := unescapeBracket(sm[3])
+			if text == "" && sm[1] == "mailto" {
+				text = sm[2]
+			}
+			return st.hold(d.link(target, text, ""))
+		})
+		s = angleURLRe.ReplaceAllStringFunc(s, func(m string) string {
+			return st.hold(d.link(m[1:len(m)-1], "", "bare"))
+		})
+		s = urlRe.ReplaceAllStringFunc(s, func(m string) string {
+			sm := urlRe.FindStringSubmatch(m)
+			prefix, target := sm[1], sm[2]
+			if !strings.HasSuffix(m, "]") {
+				// Trailing punctuation is not part of a bare URL.
+				var trailing string
+				for len(target) > 0 && strings.ContainsAny(target[len(target)-1:], ".,;:!?)") {
+					trailing = target[len(target)-1:] + trailing
+					target = target[:len(target)-1]
+				}
+				return prefix + st.hold(d.link(target, "", "bare")) + trailing
+			}
+			return prefix + st.hold(d.link(target, unescapeBracket(sm[3]), ""))
+		})
+	}
+
+	return s
+}
+
+func unescapeBracket(s string) string {
+	return strings.ReplaceAll(s, `\]`, "]")
+}
+
+// link renders a link, with the link render hook if set. A link text ending
+// with a caret opens the link in a new window.
+func (d *document) link(target, text, class string) string {
+	var window string
+	if strings.HasSuffix(text, "^") {
+		text = strings.TrimSuffix(text, "^")
+		window = "_blank"
+	}
+	if i := strings.Index(text, ",window="); i != -1 {
+		window = unquote(strings.TrimSpace(text[i+len(",window="):]))
+		text = text[:i]
+	}
+	text = unquote(text)
+
+	var textHTML string
+	if text == "" {
+		textHTML = specialCharsRep.Replace(strings.TrimPrefix(target, "mailto:"))
+		if class == "" {
+			class = "bare"
+		}
+	} else {
+		textHTML = d.inline(text)
+		class = ""
+	}
+
+	if h := d.rctx.RenderHooks.LinkRenderer; h != nil {
+		var b bytes.Buffer
+		err := h.RenderLink(&b, linkContext{
+			page:        d.dctx.Document,
+			destination: target,
+			text:        textHTML,
+			plainText:   plainText(textHTML),
+		})
+		d.ids.Add(h)
+		d.setErr(err)
+		return b.String()
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "<a href=\"%s\"", html.EscapeString(target))
+	if class != "" {
+		fmt.Fprintf(&b, " class=\"%s\"", class)
+	}
+	if window != "" {
+		fmt.Fprintf(&b, " target=\"%s\"", html.EscapeString(window))
+		if window == "_blank" {
+			b.WriteString(" rel=\"noopener\"")
+		}
+	}
+	fmt.Fprintf(&b, ">%s</a>", textHTML)
+
+	return b.String()
+}
+
+// xref renders a cross reference to an ID in this or another document,
+// e.g. "other.adoc#install".
+func (d *document) xref(target, text string) string {
+	var href string
+	if i := strings.Index(target, "#"); i != -1 || strings.HasSuffix(target, ".adoc") {
+		if i == -1 {
+			i = len(target)
+		}
+		doc, fragment := target[:i], target[i:]
+		if doc != "" {
+			suffix, found := d.attrs["outfilesuffix"]
+			if !found {
+				suffix = ".html"
+			}
+			doc = strings.TrimSuffix(doc, ".adoc") + suffix
+		}
+		href = doc + fragment
+	} else {
+		href = "#" + target
+	}
+
+	if text == "" {
+		if title, found := d.sectionTitles[target]; found {
+			text = title
+		} else {
+			text = "[" + target + "]"
+		}
+	}
+
+	return d.link(href, text, "")
+}
+
+// footnote adds a footnote, or references the one with the given ID, and
+// returns its reference.
+func (d *document) footnote(id, text string) string {
+	if id != "" {
+		if n, found := d.footnoteIDs[id]; found {
+			return fmt.Sprintf("<sup class=\"footnoteref\">[<a class=\"footnote\" href=\"#_footnotedef_%d\" title=\"View footnote.\">%d</a>]</sup>", n, n)
+		}
+	}
+
+	d.footnotes = append(d.footnotes, d.inline(text))
+	n := len(d.footnotes)
+	if id != "" {
+		d.footnoteIDs[id] = n
+	}
+
+	return fmt.Sprintf("<sup class=\"footnote\">[<a id=\"_footnoteref_%d\" class=\"footnote\" href=\"#_footnotedef_%d\" title=\"View footnote.\">%d</a>]</sup>", n, n, n)
+}
+
+// image renders an image, with the image render hook if set.
+func (d *document) image(target string, a blockAttrs, title string) string {
+	target = d.substituteAttributes(target)
+
+	alt := a.get("alt", 0)
+	if alt == "" {
+		alt = strings.TrimSuffix(path.Base(target), path.Ext(target))
+		alt = strings.NewReplacer("-", " ", "_", " ").Replace(alt)
+	}
+
+	src := target
+	if dir := d.attrs["imagesdir"]; dir != "" && !strings.Contains(src, "://") && !strings.HasPrefix(src, "/") {
+		src = strings.TrimSuffix(dir, "/") + "/" + src
+	}
+
+	if h := d.rctx.RenderHooks.ImageRenderer; h != nil {
+		var b bytes.Buffer
+		err := h.RenderLink(&b, linkContext{
+			page:        d.dctx.Document,
+			destination: src,
+			title:       title,
+			text:        specialCharsRep.Replace(alt),
+			plainText:   alt,
+		})
+		d.ids.Add(h)
+		d.setErr(err)
+		return b.String()
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "<img src=\"%s\" alt=\"%s\"", html.EscapeString(src), html.EscapeString(alt))
+	if width := a.get("width", 1); width != "" {
+		fmt.Fprintf(&b, " width=\"%s\"", html.EscapeString(width))
+	}
+	if height := a.get("height", 2); height != "" {
+		fmt.Fprintf(&b, " height=\"%s\"", html.EscapeString(height))
+	}
+	if title != "" && a.named["title"] != "" {
+		fmt.Fprintf(&b, " title=\"%s\"", html.EscapeString(title))
+	}
+	b.WriteString(">")
+
+	img := b.String()
+	if link := a.named["link"]; link != "" {
+		img = fmt.Sprintf("<a class=\"image\" href=\"%s\">%s</a>", html.EscapeString(link), img)
+	}
+
+	return img
+}
+
+func plainText(s string) string {
+	return html.UnescapeString(stripTags(s))
+}
+
+// quotes applies the inline formatting, e.g. *strong* and _emphasis_.
+func quotes(s string) string {
+	s = replaceUnconstrained(s, "**", "<strong>", "</strong>")
+	s = replaceConstrained(s, '*', wrap("<strong>", "</strong>"))
+	s = replaceUnconstrained(s, "``", "<code>", "</code>")
+	s = replaceConstrained(s, '`', wrap("<code>", "</code>"))
+	s = replaceUnconstrained(s, "__", "<em>", "</em>")
+	s = replaceConstrained(s, '_', wrap("<em>", "</em>"))
+	s = replaceUnconstrained(s, "##", "<mark>", "</mark>")
+	s = replaceConstrained(s, '#', wrap("<mark>", "</mark>"))
+	if strings.Contains(s, "^") {
+		s = superscriptRe.ReplaceAllString(s, "<sup>$1</sup>")
+	}
+	if strings.Contains(s, "~") {
+		s = subscriptRe.ReplaceAllString(s, "<sub>$1</sub>")
+	}
+	return s
+}
+
+func wrap(open, close string) func(string) string {
+	return func(inner string) string {
+		return open + inner + close
+	}
+}
+
+// replaceUnconstrained replaces the text enclosed in a pair of marks
+// anywhere, e.g. **strong**.
+func replaceUnconstrained(s, mark, open, close string) string {
+	var b strings.Builder
+	for {
+		i := strings.Index(s, mark)
+		if i == -1 {
+			break
+		}
+		j := strings.Index(s[i+len(mark):], mark)
+		if j <= 0 {
+			break
+		}
+		j += i + len(mark)
+		b.WriteString(s[:i])
+		b.WriteString(open + s[i+len(mark):j] + close)
+		s = s[j+len(mark):]
+	}
+	b.WriteString(s)
+	return b.String()
+}
+
+// replaceConstrained replaces the text enclosed in a mark not next to a
+// word character, e.g. *strong* but not in snake_case_names.
+func replaceConstrained(s string, mark byte, f func(inner string) string) string {
+	if strings.IndexByte(s, mark) == -1 {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != mark || !constrainedOpen(s, i) {
+			b.WriteByte(s[i])
+			continue
+		}
+		j := constrainedClose(s, i, mark)
+		if j == -1 {
+			b.WriteByte(s[i])
+			continue
+		}
+		b.WriteString(f(s[i+1 : j]))
+		i = j
+	}
+	return b.String()
+}
+
+func constrainedOpen(s string, i int) bool {
+	if i+1 >= len(s) || unicode.IsSpace(rune(s[i+1])) || s[i+1] == s[i] {
+		return false
+	}
+	if i == 0 {
+		return true
+	}
+	prev, _ := utf8.DecodeLastRuneInString(s[:i])
+	return !isWordRune(prev) && prev != ';' && prev != ':' && prev != '}' && prev != rune(s[i])
+}
+
+func constrainedClose(s string, i int, mark byte) int {
+	for j := i + 1; j < len(s); j++ {
+		if s[j] != mark || unicode.IsSpace(rune(s[j-1])) {
+			continue
+		}
+		if j+1 < len(s) {
+			next, _ := utf8.DecodeRuneInString(s[j+1:])
+			if isWordRune(next) || next == rune(mark) {
+				continue
+			}
+		}
+		return j
+	}
+	return -1
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func replacements(s string) string {
+	s = replacementsRep.Replace(s)
+	if strings.Contains(s, "--") {
+		s = spacedDashRe.ReplaceAllStringFunc(s, func(m string) string {
+			// The spaces around the dash are replaced with thin spaces.
+			return strings.Replace(strings.Trim(m, " "), "--", "&#8201;&#8212;&#8201;", 1)
+		})
+		s = wordDashRe.ReplaceAllString(s, "$1&#8212;&#8203;$2")
+	}
+	if strings.Contains(s, "'") {
+		s = apostropheRe.ReplaceAllString(s, "$1&#8217;$2")
+	}
+	if strings.Contains(s, "&amp;") {
+		s = entityRefRe.ReplaceAllString(s, "&$1;")
+	}
+	return s
+}
@@ -0,0 +1,270 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asciidoc
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+var orderedListStyles = []string{"arabic", "loweralpha", "lowerroman", "upperalpha", "upperroman"}
+
+var orderedListTypes = map[string]string{
+	"loweralpha": "a",
+	"lowerroman": "i",
+	"upperalpha": "A",
+	"upperroman": "I",
+}
+
+// listMarker returns the marker of the list item in line, e.g. "**" or
+// ".", and its text.
+func listMarker(line string) (marker, text string, ok bool) {
+	m := listItemRe.FindStringSubmatch(line)
+	if m == nil {
+		return "", "", false
+	}
+	marker = m[1]
+	if marker[0] >= '0' && marker[0] <= '9' {
+		marker = "1."
+	}
+	return marker, m[2], true
+}
+
+func isBlank(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
+
+// nextNonBlank returns the index of the first non blank line at or after i.
+func nextNonBlank(lines []string, i int) int {
+	for i < len(lines) && isBlank(lines[i]) {
+		i++
+	}
+	return i
+}
+
+// attachedBlockEnd returns the index of the first line after the block
+// starting at lines[i] attached to a list item with a list continuation.
+func attachedBlockEnd(lines []string, i int) int {
+	j := i
+	for j < len(lines) && isBlockMetaLine(lines[j]) {
+		j++
+	}
+	if j < len(lines) {
+		if _, ok := delimiterKind(lines[j]); ok {
+			return min(findClose(lines, j)+1, len(lines))
+		}
+	}
+	for j < len(lines) && !isBlank(lines[j]) {
+		j++
+	}
+	return j
+}
+
+type listItem struct {
+	text   []string
+	blocks bytes.Buffer
+}
+
+// renderList renders the list starting at lines[i] and returns the index of
+// the first line after it. The ancestors are the markers of the lists it's
+// nested in.
+func (d *document) renderList(w *bytes.Buffer, lines []string, i int, ancestors []string, meta blockMeta) int {
+	marker, _, _ := listMarker(lines[i])
+
+	var (
+		items []*listItem
+		cur   *listItem
+		j     = i
+	)
+
+	for j < len(lines) {
+		line := lines[j]
+
+		if isBlank(line) {
+			k := nextNonBlank(lines, j)
+			if k < len(lines) {
+				if _, _, ok := listMarker(lines[k]); ok {
+					j = k
+					continue
+				}
+			}
+			break
+		}
+
+		if m, text, ok := listMarker(line); ok {
+			if m == marker {
+				cur = &listItem{text: []string{text}}
+				items = append(items, cur)
+				j++
+				continue
+			}
+			if containsString(ancestors, m) {
+				break
+			}
+			j = d.renderList(&cur.blocks, lines, j, append(ancestors, marker), blockMeta{})
+			continue
+		}
+
+		if strings.TrimRight(line, " \t") == "+" {
+			end := attachedBlockEnd(lines, j+1)
+			d.renderBlocks(&cur.blocks, lines[j+1:end])
+			j = end
+			continue
+		}
+
+		if _, ok := delimiterKind(line); ok || isBlockMetaLine(line) || cur.blocks.Len() > 0 {
+			break
+		}
+
+		cur.text = append(cur.text, strings.TrimSpace(line))
+		j++
+	}
+
+	ordered := marker[0] == '.' || marker == "1."
+
+	var checklist bool
+	if !ordered {
+		for _, item := range items {
+			if _, ok := checkbox(item.text[0]); ok {
+				checklist = true
+				break
+			}
+		}
+	}
+
+	var divClass, listOpen string
+	switch {
+	case ordered:
+		style := meta.style()
+		if style == "" {
+			style = orderedListStyles[0]
+			if marker != "1." {
+				style = orderedListStyles[(len(marker)-1)%len(orderedListStyles)]
+			}
+		}
+		divClass = "olist " + style
+		listOpen = fmt.Sprintf("<ol class=\"%s\"", style)
+		if typ, found := orderedListTypes[style]; found {
+			listOpen += fmt.Sprintf(" type=\"%s\"", typ)
+		}
+		if start := meta.attrs.named["start"]; start != "" {
+			listOpen += fmt.Sprintf(" start=\"%s\"", start)
+		}
+		listOpen += ">"
+	case checklist:
+		divClass = "ulist checklist"
+		listOpen = "<ul class=\"checklist\">"
+	default:
+		divClass = "ulist"
+		listOpen = "<ul>"
+	}
+
+	d.openDiv(w, divClass, meta)
+	w.WriteString(listOpen + "\n")
+	for _, item := range items {
+		text := d.inlineLines(item.text)
+		if box, ok := checkbox(item.text[0]); ok && checklist {
+			text = box + d.inlineLines(append([]string{item.text[0][4:]}, item.text[1:]...))
+		}
+		fmt.Fprintf(w, "<li>\n<p>%s</p>\n", text)
+		w.Write(item.blocks.Bytes())
+		w.WriteString("</li>\n")
+	}
+	if ordered {
+		w.WriteString("</ol>\n</div>\n")
+	} else {
+		w.WriteString("</ul>\n</div>\n")
+	}
+
+	return j
+}
+
+// checkbox returns the checkbox of a checklist item, if text starts with
+// one, e.g. "[x] ".
+func checkbox(text string) (string, bool) {
+	switch {
+	case strings.HasPrefix(text, "[x] "), strings.HasPrefix(text, "[X] "), strings.HasPrefix(text, "[*] "):
+		return "&#10003; ", true
+	case strings.HasPrefix(text, "[ ] "):
+		return "&#10063; ", true
+	}
+	return "", false
+}
+
+func containsString(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// renderDlist renders the description list starting at lines[i] and
+// returns the index of the first line after it.
+func (d *document) renderDlist(w *bytes.Buffer, lines []string, i int, meta blockMeta) int {
+	d.openDiv(w, "dlist", meta)
+	w.WriteString("<dl>\n")
+
+	j := i
+	for j < len(lines) {
+		if isBlank(lines[j]) {
+			k := nextNonBlank(lines, j)
+			if k < len(lines) && dlistItemRe.MatchString(lines[k]) && !listItemRe.MatchString(lines[k]) {
+				j = k
+				continue
+			}
+			break
+		}
+
+		m := dlistItemRe.FindStringSubmatch(lines[j])
+		if m == nil {
+			break
+		}
+		j++
+
+		fmt.Fprintf(w, "<dt class=\"hdlist1\">%s</dt>\n", d.inline(m[1]))
+
+		var text []string
+		if m[3] != "" {
+			text = append(text, m[3])
+		}
+		for j < len(lines) && !isBlank(lines[j]) && !dlistItemRe.MatchString(lines[j]) && !listItemRe.MatchString(lines[j]) {
+			if _, ok := delimiterKind(lines[j]); ok || isBlockMetaLine(lines[j]) {
+				break
+			}
+			text = append(text, strings.TrimSpace(lines[j]))
+			j++
+		}
+
+		var blocks bytes.Buffer
+		if k := nextNonBlank(lines, j); k < len(lines) && listItemRe.MatchString(lines[k]) && (len(text) == 0 || k == j) {
+			j = d.renderList(&blocks, lines, k, nil, blockMeta{})
+		}
+
+		if len(text) > 0 || blocks.Len() > 0 {
+			w.WriteString("<dd>\n")
+			if len(text) > 0 {
+				fmt.Fprintf(w, "<p>%s</p>\n", d.inlineLines(text))
+			}
+			w.Write(blocks.Bytes())
+			w.WriteString("</dd>\n")
+		}
+	}
+
+	w.WriteString("</dl>\n</div>\n")
+
+	return j
+}
@@ -0,0 +1,180 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asciidoc
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var colSpecWidthRe = regexp.MustCompile(`(\d+)%?[a-z]?$`)
+
+// splitCells splits a table line into the text before the first cell
+// separator, which belongs to the previous cell, and the cells.
+func splitCells(line string) (before string, cells []string) {
+	var (
+		b     strings.Builder
+		found bool
+	)
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == '|':
+			b.WriteByte('|')
+			i++
+		case line[i] == '|':
+			if found {
+				cells = append(cells, b.String())
+			} else {
+				before = b.String()
+				found = true
+			}
+			b.Reset()
+		default:
+			b.WriteByte(line[i])
+		}
+	}
+	if !found {
+		return b.String(), nil
+	}
+	return before, append(cells, b.String())
+}
+
+// columnWidths returns the relative widths of the columns in the cols
+// attribute, e.g. "1,2", "3*" or "3", or n columns of the same width.
+func columnWidths(cols string, n int) []float64 {
+	var widths []float64
+	if cols != "" {
+		if count, err := strconv.Atoi(strings.TrimSpace(cols)); err == nil {
+			n = count
+		} else {
+			for _, spec := range strings.FieldsFunc(cols, func(r rune) bool { return r == ',' || r == ';' }) {
+				spec = strings.TrimSpace(spec)
+				repeat := 1
+				if i := strings.Index(spec, "*"); i != -1 {
+					if r, err := strconv.Atoi(spec[:i]); err == nil {
+						repeat = r
+					}
+					spec = spec[i+1:]
+				}
+				width := 1.0
+				if m := colSpecWidthRe.FindStringSubmatch(spec); m != nil {
+					width, _ = strconv.ParseFloat(m[1], 64)
+				}
+				for i := 0; i < repeat; i++ {
+					widths = append(widths, width)
+				}
+			}
+		}
+	}
+	if len(widths) == 0 {
+		for i := 0; i < n; i++ {
+			widths = append(widths, 1)
+		}
+	}
+	return widths
+}
+
+func (d *document) renderTable(w *bytes.Buffer, lines []string, meta blockMeta) {
+	var (
+		cells          []string
+		firstRowCells  = -1
+		implicitHeader bool
+	)
+
+	for i, line := range lines {
+		if isBlank(line) {
+			if len(cells) > 0 {
+				cells[len(cells)-1] += "\n\n"
+			}
+			continue
+		}
+		before, lineCells := splitCells(line)
+		if len(cells) > 0 {
+			if len(lineCells) == 0 {
+				cells[len(cells)-1] += "\n" + before
+				continue
+			}
+			if strings.TrimSpace(before) != "" {
+				cells[len(cells)-1] += "\n" + before
+			}
+		}
+		if firstRowCells == -1 && len(lineCells) > 0 {
+			firstRowCells = len(lineCells)
+			implicitHeader = i+1 < len(lines) && isBlank(lines[i+1])
+		}
+		cells = append(cells, lineCells...)
+	}
+
+	widths := columnWidths(meta.attrs.named["cols"], firstRowCells)
+	cols := len(widths)
+	if cols == 0 {
+		return
+	}
+
+	header := meta.attrs.hasOption("header") || (implicitHeader && !meta.attrs.hasOption("noheader") && meta.attrs.named["cols"] == "")
+
+	var total float64
+	for _, width := range widths {
+		total += width
+	}
+
+	d.openTag(w, "table", "tableblock frame-all grid-all stretch", meta)
+	w.WriteString(">\n")
+	if meta.title != "" {
+		fmt.Fprintf(w, "<caption class=\"title\">%s%s</caption>\n", d.caption("table", meta), d.inline(meta.title))
+	}
+	w.WriteString("<colgroup>\n")
+	for _, width := range widths {
+		pct := strconv.FormatFloat(float64(int(width/total*1000000+0.5))/10000, 'f', -1, 64)
+		fmt.Fprintf(w, "<col style=\"width: %s%%;\">\n", pct)
+	}
+	w.WriteString("</colgroup>\n")
+
+	for row := 0; row*cols < len(cells); row++ {
+		end := min((row+1)*cols, len(cells))
+		rowCells := cells[row*cols : end]
+
+		if row == 0 && header {
+			w.WriteString("<thead>\n<tr>\n")
+			for _, cell := range rowCells {
+				fmt.Fprintf(w, "<th class=\"tableblock halign-left valign-top\">%s</th>\n", d.inline(strings.TrimSpace(cell)))
+			}
+			w.WriteString("</tr>\n</thead>\n")
+			continue
+		}
+
+		if row == 0 || (row == 1 && header) {
+			w.WriteString("<tbody>\n")
+		}
+		w.WriteString("<tr>\n")
+		for _, cell := range rowCells {
+			w.WriteString("<td class=\"tableblock halign-left valign-top\">")
+			for _, para := range strings.Split(strings.TrimSpace(cell), "\n\n") {
+				if para = strings.TrimSpace(para); para != "" {
+					fmt.Fprintf(w, "<p class=\"tableblock\">%s</p>", d.inline(para))
+				}
+			}
+			w.WriteString("</td>\n")
+		}
+		w.WriteString("</tr>\n")
+	}
+
+	if len(cells) > cols || (len(cells) > 0 && !header) {
+		w.WriteString("</tbody>\n")
+	}
+	w.WriteString("</table>\n")
+}
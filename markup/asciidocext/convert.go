@@ -12,20 +12,22 @@
 // limitations under the License.
 
 // Package asciidocext converts AsciiDoc to HTML using Asciidoctor
-// external binary. The `asciidoc` module is reserved for a future golang
-// implementation.
+// external binary. If Asciidoctor is not installed, the content is
+// converted with the native Go implementation in the `asciidoc` module.
 package asciidocext
 
 import (
 	"bytes"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/gohugoio/hugo/htesting"
 
 	"github.com/cli/safeexec"
 
 	"github.com/gohugoio/hugo/identity"
+	"github.com/gohugoio/hugo/markup/asciidoc"
 	"github.com/gohugoio/hugo/markup/asciidocext/asciidocext_config"
 	"github.com/gohugoio/hugo/markup/converter"
 	"github.com/gohugoio/hugo/markup/internal"
@@ -44,10 +46,23 @@ var Provider converter.ProviderProvider = provider{}
 type provider struct{}
 
 func (p provider) New(cfg converter.ProviderConfig) (converter.Provider, error) {
+	native, err := asciidoc.Provider.New(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	warnOnce := &sync.Once{}
+
 	return converter.NewProvider("asciidocext", func(ctx converter.DocumentContext) (converter.Converter, error) {
+		nativeConverter, err := native.New(ctx)
+		if err != nil {
+			return nil, err
+		}
 		return &asciidocConverter{
-			ctx: ctx,
-			cfg: cfg,
+			ctx:      ctx,
+			cfg:      cfg,
+			native:   nativeConverter,
+			warnOnce: warnOnce,
 		}, nil
 	}), nil
 }
@@ -64,9 +79,19 @@ func (r asciidocResult) TableOfContents() tableofcontents.Root {
 type asciidocConverter struct {
 	ctx converter.DocumentContext
 	cfg converter.ProviderConfig
+
+	// Used when Asciidoctor is not installed.
+	native   converter.Converter
+	warnOnce *sync.Once
 }
 
 func (a *asciidocConverter) Convert(ctx converter.RenderContext) (converter.Result, error) {
+	if getAsciidoctorExecPath() == "" {
+		a.warnOnce.Do(func() {
+			a.cfg.Logger.Warnln("asciidoctor not found in $PATH: Using the built-in AsciiDoc converter, which supports a subset of the Asciidoctor features.")
+		})
+		return a.native.Convert(ctx)
+	}
 	content, toc, err := a.extractTOC(a.getAsciidocContent(ctx.Src, a.ctx))
 	if err != nil {
 		return nil, err
@@ -77,7 +102,10 @@ func (a *asciidocConverter) Convert(ctx converter.RenderContext) (converter.Resu
 	}, nil
 }
 
-func (a *asciidocConverter) Supports(_ identity.Identity) bool {
+func (a *asciidocConverter) Supports(feature identity.Identity) bool {
+	if getAsciidoctorExecPath() == "" {
+		return a.native.Supports(feature)
+	}
 	return false
 }
 
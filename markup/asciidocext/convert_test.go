@@ -303,6 +303,29 @@ func TestConvert(t *testing.T) {
 	c.Assert(string(b.Bytes()), qt.Equals, "<div class=\"paragraph\">\n<p>testContent</p>\n</div>\n")
 }
 
+func TestConvertWithoutAsciidoctor(t *testing.T) {
+	if Supports() {
+		t.Skip("asciidoctor installed")
+	}
+	c := qt.New(t)
+
+	p, err := Provider.New(
+		converter.ProviderConfig{
+			MarkupConfig: markup_config.Default,
+			Logger:       loggers.NewErrorLogger(),
+		},
+	)
+	c.Assert(err, qt.IsNil)
+
+	conv, err := p.New(converter.DocumentContext{})
+	c.Assert(err, qt.IsNil)
+	c.Assert(conv.Supports(converter.FeatureRenderHooks), qt.IsTrue)
+
+	b, err := conv.Convert(converter.RenderContext{Src: []byte("testContent")})
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(b.Bytes()), qt.Equals, "<div class=\"paragraph\">\n<p>testContent</p>\n</div>\n")
+}
+
 func TestTableOfContents(t *testing.T) {
 	if !Supports() {
 		t.Skip("asciidoctor not installed")
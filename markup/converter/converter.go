@@ -31,6 +31,7 @@ type ProviderConfig struct {
 
 	Cfg       config.Provider // Site config
 	ContentFs afero.Fs
+	AssetsFs  afero.Fs // May be nil.
 	Logger    loggers.Logger
 	Highlight func(code, lang, optsStr string) (string, error)
 }
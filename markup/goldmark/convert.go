@@ -20,8 +20,13 @@ import (
 	"math/bits"
 	"path/filepath"
 	"runtime/debug"
+	"sync"
 
+	"github.com/gohugoio/hugo/markup/goldmark/goldmark_config"
 	"github.com/gohugoio/hugo/markup/goldmark/internal/extensions/attributes"
+	"github.com/gohugoio/hugo/markup/goldmark/internal/extensions/markdowninhtml"
+	mathext "github.com/gohugoio/hugo/markup/goldmark/internal/extensions/math"
+	pluginsext "github.com/gohugoio/hugo/markup/goldmark/internal/extensions/plugins"
 	"github.com/yuin/goldmark/ast"
 
 	"github.com/gohugoio/hugo/identity"
@@ -51,9 +56,38 @@ type provide struct {
 }
 
 func (p provide) New(cfg converter.ProviderConfig) (converter.Provider, error) {
-	md := newMarkdown(cfg)
+	plugins, err := loadPlugins(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	md, err := newMarkdown(cfg, plugins)
+	if err != nil {
+		return nil, err
+	}
+
+	mds := &markdowns{
+		pcfg:    cfg,
+		plugins: plugins,
+		m:       map[goldmark_config.Config]goldmark.Markdown{cfg.MarkupConfig.Goldmark: md},
+	}
 
 	return converter.NewProvider("goldmark", func(ctx converter.DocumentContext) (converter.Converter, error) {
+		cfg := cfg
+		if ctx.ConfigOverrides != nil {
+			// Page level overrides, e.g. markup.goldmark.renderer.unsafe.
+			var err error
+			cfg.MarkupConfig.Goldmark, err = goldmark_config.ApplyOverrides(cfg.MarkupConfig.Goldmark, ctx.ConfigOverrides)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to apply Goldmark config overrides")
+			}
+		}
+
+		md, err := mds.get(cfg.MarkupConfig.Goldmark)
+		if err != nil {
+			return nil, err
+		}
+
 		return &goldmarkConverter{
 			ctx: ctx,
 			cfg: cfg,
@@ -65,6 +99,40 @@ func (p provide) New(cfg converter.ProviderConfig) (converter.Provider, error) {
 	}), nil
 }
 
+// markdowns holds a Goldmark instance per config, so pages sharing the same
+// overrides share the same instance.
+type markdowns struct {
+	pcfg    converter.ProviderConfig
+	plugins *pluginRunner
+
+	mu sync.RWMutex
+	m  map[goldmark_config.Config]goldmark.Markdown
+}
+
+func (m *markdowns) get(cfg goldmark_config.Config) (goldmark.Markdown, error) {
+	m.mu.RLock()
+	md, found := m.m[cfg]
+	m.mu.RUnlock()
+	if found {
+		return md, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if md, found := m.m[cfg]; found {
+		return md, nil
+	}
+	pcfg := m.pcfg
+	pcfg.MarkupConfig.Goldmark = cfg
+	md, err := newMarkdown(pcfg, m.plugins)
+	if err != nil {
+		return nil, err
+	}
+	m.m[cfg] = md
+
+	return md, nil
+}
+
 var _ converter.AnchorNameSanitizer = (*goldmarkConverter)(nil)
 
 type goldmarkConverter struct {
@@ -79,7 +147,7 @@ func (c *goldmarkConverter) SanitizeAnchorName(s string) string {
 	return c.sanitizeAnchorName(s)
 }
 
-func newMarkdown(pcfg converter.ProviderConfig) goldmark.Markdown {
+func newMarkdown(pcfg converter.ProviderConfig, plugins *pluginRunner) (goldmark.Markdown, error) {
 	mcfg := pcfg.MarkupConfig
 	cfg := pcfg.MarkupConfig.Goldmark
 	var rendererOptions []renderer.Option
@@ -148,6 +216,22 @@ func newMarkdown(pcfg converter.ProviderConfig) goldmark.Markdown {
 		extensions = append(extensions, attributes.New())
 	}
 
+	if cfg.Parser.MarkdownInHTML {
+		extensions = append(extensions, markdowninhtml.New())
+	}
+
+	if cfg.Extensions.Math.Enable {
+		render, err := newMathRenderer(pcfg)
+		if err != nil {
+			return nil, err
+		}
+		extensions = append(extensions, mathext.New(render))
+	}
+
+	if plugins != nil && !cfg.Plugins.Disable {
+		extensions = append(extensions, pluginsext.New(plugins.blocks, plugins.inlines, plugins.render))
+	}
+
 	md := goldmark.New(
 		goldmark.WithExtensions(
 			extensions...,
@@ -160,7 +244,7 @@ func newMarkdown(pcfg converter.ProviderConfig) goldmark.Markdown {
 		),
 	)
 
-	return md
+	return md, nil
 }
 
 var _ identity.IdentitiesProvider = (*converterResult)(nil)
@@ -332,6 +332,176 @@ func TestConvertAttributes(t *testing.T) {
 
 }
 
+func TestConvertMarkdownInHTML(t *testing.T) {
+	c := qt.New(t)
+
+	content := `
+<div class="note" markdown="1">
+## Note
+
+Some **bold** text.
+<div class="inner">
+Raw
+</div>
+</div>
+
+<div class="raw">
+**Not Markdown**
+</div>
+`
+
+	c.Run("Enabled", func(c *qt.C) {
+		mconf := markup_config.Default
+		mconf.Goldmark.Renderer.Unsafe = true
+		mconf.Goldmark.Parser.MarkdownInHTML = true
+		got := string(convert(c, mconf, content).Bytes())
+
+		c.Assert(got, qt.Contains, "<div class=\"note\">\n<h2 id=\"note\">Note</h2>\n<p>Some <strong>bold</strong> text.</p>\n<div class=\"inner\">\nRaw\n</div>\n</div>\n")
+		c.Assert(got, qt.Contains, "<div class=\"raw\">\n**Not Markdown**\n</div>")
+	})
+
+	c.Run("Nested", func(c *qt.C) {
+		mconf := markup_config.Default
+		mconf.Goldmark.Renderer.Unsafe = true
+		mconf.Goldmark.Parser.MarkdownInHTML = true
+		got := string(convert(c, mconf, `<section markdown="1">
+<section markdown="1">
+*Inner*
+</section>
+*Outer*
+</section>
+`).Bytes())
+
+		c.Assert(got, qt.Equals, "<section>\n<section>\n<p><em>Inner</em></p>\n</section>\n<p><em>Outer</em></p>\n</section>\n")
+	})
+
+	c.Run("Text before closing tag", func(c *qt.C) {
+		mconf := markup_config.Default
+		mconf.Goldmark.Renderer.Unsafe = true
+		mconf.Goldmark.Parser.MarkdownInHTML = true
+		got := string(convert(c, mconf, `<div markdown="1">
+*Text* foo</div>
+Bar
+</div>
+`).Bytes())
+
+		c.Assert(got, qt.Equals, "<div>\n<p><em>Text</em> foo</div>\nBar</p>\n</div>\n")
+	})
+
+	c.Run("Closing tag in code fence", func(c *qt.C) {
+		mconf := markup_config.Default
+		mconf.Goldmark.Renderer.Unsafe = true
+		mconf.Goldmark.Parser.MarkdownInHTML = true
+		got := string(convert(c, mconf, "<div markdown=\"1\">\n```\n</div>\n```\n*After*\n</div>\n").Bytes())
+
+		c.Assert(got, qt.Contains, "<pre><code>&lt;/div&gt;\n</code></pre>")
+		c.Assert(got, qt.Contains, "<p><em>After</em></p>\n</div>\n")
+	})
+
+	c.Run("Safe", func(c *qt.C) {
+		mconf := markup_config.Default
+		mconf.Goldmark.Parser.MarkdownInHTML = true
+		got := string(convert(c, mconf, content).Bytes())
+
+		c.Assert(got, qt.Contains, "<!-- raw HTML omitted -->\n<h2 id=\"note\">Note</h2>")
+		c.Assert(got, qt.Not(qt.Contains), "class=\"note\"")
+	})
+
+	c.Run("Disabled", func(c *qt.C) {
+		mconf := markup_config.Default
+		mconf.Goldmark.Renderer.Unsafe = true
+		got := string(convert(c, mconf, content).Bytes())
+
+		c.Assert(got, qt.Contains, "<div class=\"note\" markdown=\"1\">\n## Note\n")
+	})
+}
+
+func TestConvertMath(t *testing.T) {
+	c := qt.New(t)
+
+	mconf := markup_config.Default
+	mconf.Goldmark.Extensions.Math.Enable = true
+
+	for _, test := range []struct {
+		name   string
+		input  string
+		expect interface{}
+	}{
+		{"Inline", `Let $a_1 < b_1$ and *c*.`, "<p>Let $a_1 &lt; b_1$ and <em>c</em>.</p>\n"},
+		{"Inline display", `So $$\sum_{i=1}^n x_i$$ is it.`, "<p>So $$\\sum_{i=1}^n x_i$$ is it.</p>\n"},
+		{"Not math", `It costs $5 and $6, or $ 7 $.`, "<p>It costs $5 and $6, or $ 7 $.</p>\n"},
+		{"Escaped", `A \$a_1$ b_1$.`, "<p>A $a_1$ b_1$.</p>\n"},
+		{"Code span", "`$a_1$`", "<p><code>$a_1$</code></p>\n"},
+		{"Block", "Before\n$$\na_1 *\n\nb_1\n$$\nAfter", "<p>Before</p>\n<p>$$\na_1 *\n\nb_1\n$$</p>\n<p>After</p>\n"},
+		{"Block single line", "$$ a_1 * b_1 $$", "<p>$$\na_1 * b_1\n$$</p>\n"},
+		{"Block with closing on content line", "$$\na_1 \\\\\nb_1 $$", "<p>$$\na_1 \\\\\nb_1\n$$</p>\n"},
+		{"Not a block", "$$a$$ and $$b$$", "<p>$$a$$ and $$b$$</p>\n"},
+	} {
+		test := test
+		c.Run(test.name, func(c *qt.C) {
+			got := string(convert(c, mconf, test.input).Bytes())
+			c.Assert(got, qt.Equals, test.expect)
+		})
+	}
+
+	// Disabled by default.
+	got := string(convert(c, markup_config.Default, `$a*1$ and $b*1$`).Bytes())
+	c.Assert(got, qt.Equals, "<p>$a<em>1$ and $b</em>1$</p>\n")
+
+	mconf.Goldmark.Extensions.Math.Output = "mathml"
+	got = string(convert(c, mconf, `Let $a_1$.`).Bytes())
+	c.Assert(got, qt.Contains, `<p>Let <math xmlns="http://www.w3.org/1998/Math/MathML"><semantics><mrow><msub><mi>a</mi><mn>1</mn></msub></mrow>`)
+
+	// Math outside the supported subset is passed through.
+	got = string(convert(c, mconf, `Let $\overset{!}{=}$.`).Bytes())
+	c.Assert(got, qt.Equals, "<p>Let $\\overset{!}{=}$.</p>\n")
+
+	mconf.Goldmark.Extensions.Math.Output = "html"
+	_, err := Provider.New(converter.ProviderConfig{MarkupConfig: mconf, Logger: loggers.NewErrorLogger()})
+	c.Assert(err, qt.ErrorMatches, `math: unsupported output "html"; the only build-time output is "mathml"`)
+}
+
+func TestConvertConfigOverrides(t *testing.T) {
+	c := qt.New(t)
+
+	p, err := Provider.New(
+		converter.ProviderConfig{
+			MarkupConfig: markup_config.Default,
+			Logger:       loggers.NewErrorLogger(),
+		},
+	)
+	c.Assert(err, qt.IsNil)
+
+	convertWith := func(overrides map[string]interface{}) string {
+		conv, err := p.New(converter.DocumentContext{ConfigOverrides: overrides})
+		c.Assert(err, qt.IsNil)
+		b, err := conv.Convert(converter.RenderContext{Src: []byte("<b>raw</b> \"quoted\" ~~del~~\n")})
+		c.Assert(err, qt.IsNil)
+		return string(b.Bytes())
+	}
+
+	got := convertWith(nil)
+	c.Assert(got, qt.Contains, "<!-- raw HTML omitted -->")
+	c.Assert(got, qt.Contains, "&ldquo;quoted&rdquo;")
+	c.Assert(got, qt.Contains, "<del>del</del>")
+
+	overrides := map[string]interface{}{
+		"renderer":   map[string]interface{}{"unsafe": true},
+		"extensions": map[string]interface{}{"typographer": "false", "strikethrough": false},
+	}
+	got = convertWith(overrides)
+	c.Assert(got, qt.Contains, "<b>raw</b>")
+	c.Assert(got, qt.Contains, "&quot;quoted&quot;")
+	c.Assert(got, qt.Contains, "~~del~~")
+
+	// The site config is left untouched.
+	got = convertWith(nil)
+	c.Assert(got, qt.Contains, "<!-- raw HTML omitted -->")
+
+	_, err = p.New(converter.DocumentContext{ConfigOverrides: map[string]interface{}{"renderer": "foo"}})
+	c.Assert(err, qt.Not(qt.IsNil))
+}
+
 func TestConvertIssues(t *testing.T) {
 	c := qt.New(t)
 
@@ -14,6 +14,10 @@
 // Package goldmark_config holds Goldmark related configuration.
 package goldmark_config
 
+import (
+	"github.com/mitchellh/mapstructure"
+)
+
 const (
 	AutoHeadingIDTypeGitHub      = "github"
 	AutoHeadingIDTypeGitHubAscii = "github-ascii"
@@ -34,6 +38,9 @@ var Default = Config{
 	Renderer: Renderer{
 		Unsafe: false,
 	},
+	Plugins: Plugins{
+		Timeout: "30s",
+	},
 	Parser: Parser{
 		AutoHeadingID:     true,
 		AutoHeadingIDType: AutoHeadingIDTypeGitHub,
@@ -49,6 +56,7 @@ type Config struct {
 	Renderer   Renderer
 	Parser     Parser
 	Extensions Extensions
+	Plugins    Plugins
 }
 
 type Extensions struct {
@@ -61,6 +69,45 @@ type Extensions struct {
 	Strikethrough bool
 	Linkify       bool
 	TaskList      bool
+
+	// Parse inline $...$ and display $$...$$ math.
+	Math Math
+}
+
+// Math configures the math extension, which parses inline $...$ and display
+// $$...$$ math so it's not mangled by the Markdown parser.
+type Math struct {
+	Enable bool
+
+	// Set to "mathml" to render the math to MathML at build time, as KaTeX
+	// does, for the subset of TeX documented in the mathml package. Math
+	// using anything else is passed through with a warning. The default,
+	// "", passes all math through to the output with its delimiters, to be
+	// rendered client side, e.g. with MathJax.
+	Output string
+
+	// Whether to fail the build on invalid TeX. The default is to render
+	// the error in place.
+	ThrowOnError bool
+
+	// Path to a file in /assets with TeX macro definitions, one per line,
+	// e.g. \RR:\mathbb{R}.
+	MacroFile string
+}
+
+// Plugins configures the Goldmark plugins, WASM modules adding custom block
+// types and inline syntaxes, e.g. shipped by a Hugo module. A plugin named
+// foo is declared in assets/goldmark/plugins/foo/plugin.toml next to its
+// module, plugin.wasm, a WASI preview 1 command module, e.g. compiled with
+// GOOS=wasip1, TinyGo or Rust's wasm32-wasi target. The plugins run in the
+// embedded wazero runtime, with no file system or network access.
+type Plugins struct {
+	// Disables loading the plugins.
+	Disable bool
+
+	// The maximum time a plugin may run to render one block or inline
+	// element, e.g. "10s". Default is "30s".
+	Timeout string
 }
 
 type Renderer struct {
@@ -86,6 +133,11 @@ type Parser struct {
 
 	// Enables custom attributes.
 	Attribute ParserAttribute
+
+	// Enables Markdown parsing of the content inside HTML block elements
+	// marked with a markdown="1" attribute. The surrounding HTML is only
+	// rendered when unsafe is enabled.
+	MarkdownInHTML bool
 }
 
 type ParserAttribute struct {
@@ -94,3 +146,11 @@ type ParserAttribute struct {
 	// Enables custom attributeds for blocks.
 	Block bool
 }
+
+// ApplyOverrides returns a copy of cfg with the settings in m applied, e.g.
+// from the markup.goldmark map in front matter.
+func ApplyOverrides(cfg Config, m map[string]interface{}) (Config, error) {
+	err := mapstructure.WeakDecode(m, &cfg)
+
+	return cfg, err
+}
@@ -0,0 +1,239 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package markdowninhtml provides a Goldmark extension that parses the content
+// of HTML block elements marked with a markdown="1" attribute as Markdown, e.g.:
+//
+//	<div class="note" markdown="1">
+//	**Note:** This is Markdown.
+//	</div>
+package markdowninhtml
+
+import (
+	"bytes"
+	"regexp"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+var (
+	kindMarkdownHTMLBlock = ast.NewNodeKind("MarkdownHTMLBlock")
+
+	// An opening tag alone on its line with a markdown="1" or markdown="block" attribute.
+	openTagRe = regexp.MustCompile(`^[ ]{0,3}<([a-zA-Z][a-zA-Z0-9-]*)((?:\s[^>]*?)?)\s+markdown=(?:"(?:1|block)"|'(?:1|block)'|1)((?:\s[^>]*?)?)\s*>\s*$`)
+)
+
+// New returns the extension.
+func New() goldmark.Extender {
+	return &markdownInHTMLExtension{}
+}
+
+type markdownInHTMLExtension struct{}
+
+func (e *markdownInHTMLExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		parser.WithBlockParsers(
+			// Must run before the HTML block parser.
+			util.Prioritized(&blockParser{}, 850),
+		),
+	)
+	m.Renderer().AddOptions(
+		renderer.WithNodeRenderers(
+			util.Prioritized(&htmlRenderer{}, 100),
+		),
+	)
+}
+
+// markdownHTMLBlock is an HTML element with Markdown children.
+type markdownHTMLBlock struct {
+	ast.BaseBlock
+
+	tag     []byte
+	openTag []byte
+
+	// Used to track the nesting level of same name elements inside this block.
+	depth     int
+	nestedRe  *regexp.Regexp
+	closingRe *regexp.Regexp
+
+	// The closing tag must be alone on its line.
+	closingLineRe *regexp.Regexp
+
+	// The open code fence, if any. Tags inside fenced code are ignored.
+	fenceChar byte
+	fenceLen  int
+}
+
+func (b *markdownHTMLBlock) Dump(source []byte, level int) {
+	ast.DumpHelper(b, source, level, map[string]string{"Tag": string(b.tag)}, nil)
+}
+
+func (b *markdownHTMLBlock) Kind() ast.NodeKind {
+	return kindMarkdownHTMLBlock
+}
+
+type blockParser struct{}
+
+func (p *blockParser) Trigger() []byte {
+	return []byte{'<'}
+}
+
+func (p *blockParser) Open(parent ast.Node, reader text.Reader, pc parser.Context) (ast.Node, parser.State) {
+	line, _ := reader.PeekLine()
+	m := openTagRe.FindSubmatch(line)
+	if m == nil {
+		return nil, parser.NoChildren
+	}
+
+	var openTag bytes.Buffer
+	openTag.WriteByte('<')
+	openTag.Write(m[1])
+	openTag.Write(bytes.TrimRight(m[2], " \t"))
+	openTag.Write(bytes.TrimRight(m[3], " \t"))
+	openTag.WriteByte('>')
+
+	reader.Advance(lineLength(line))
+
+	tag := bytes.ToLower(m[1])
+
+	return &markdownHTMLBlock{
+		tag:       tag,
+		openTag:   openTag.Bytes(),
+		nestedRe:  regexp.MustCompile(`(?i)<` + string(tag) + `[\s/>]`),
+		closingRe: regexp.MustCompile(`(?i)</` + string(tag) + `\s*>`),
+
+		closingLineRe: regexp.MustCompile(`(?i)^[ ]{0,3}</` + string(tag) + `\s*>\s*$`),
+	}, parser.HasChildren
+}
+
+func (p *blockParser) Continue(node ast.Node, reader text.Reader, pc parser.Context) parser.State {
+	n := node.(*markdownHTMLBlock)
+	line, _ := reader.PeekLine()
+
+	if n.updateFence(line) {
+		return parser.Continue | parser.HasChildren
+	}
+
+	if n.closingLineRe.Match(line) {
+		if n.depth == 0 {
+			// Consume the closing tag.
+			reader.Advance(lineLength(line))
+			return parser.Close
+		}
+		n.depth--
+		return parser.Continue | parser.HasChildren
+	}
+
+	n.depth += len(n.nestedRe.FindAllIndex(line, -1))
+	n.depth -= len(n.closingRe.FindAllIndex(line, -1))
+	if n.depth < 0 {
+		n.depth = 0
+	}
+
+	return parser.Continue | parser.HasChildren
+}
+
+// updateFence tracks fenced code blocks inside b and reports whether line
+// is part of one, including its opening and closing fence.
+func (b *markdownHTMLBlock) updateFence(line []byte) bool {
+	char, length, rest := fence(line)
+
+	if b.fenceChar == 0 {
+		if length == 0 || (char == '`' && bytes.IndexByte(rest, '`') != -1) {
+			return false
+		}
+		b.fenceChar, b.fenceLen = char, length
+		return true
+	}
+
+	if char == b.fenceChar && length >= b.fenceLen && len(bytes.TrimSpace(rest)) == 0 {
+		b.fenceChar, b.fenceLen = 0, 0
+	}
+
+	return true
+}
+
+// fence returns the fence character and length if line starts a code fence,
+// and the remainder of the line after the fence.
+func fence(line []byte) (byte, int, []byte) {
+	i := 0
+	for i < 3 && i < len(line) && line[i] == ' ' {
+		i++
+	}
+	if i == len(line) || (line[i] != '`' && line[i] != '~') {
+		return 0, 0, nil
+	}
+	char := line[i]
+	j := i
+	for j < len(line) && line[j] == char {
+		j++
+	}
+	if j-i < 3 {
+		return 0, 0, nil
+	}
+	return char, j - i, line[j:]
+}
+
+func (p *blockParser) Close(node ast.Node, reader text.Reader, pc parser.Context) {
+}
+
+func (p *blockParser) CanInterruptParagraph() bool {
+	return true
+}
+
+func (p *blockParser) CanAcceptIndentedLine() bool {
+	return false
+}
+
+// lineLength returns the length of line without the line ending.
+func lineLength(line []byte) int {
+	return len(bytes.TrimRight(line, "\r\n"))
+}
+
+type htmlRenderer struct {
+	html.Config
+}
+
+func (r *htmlRenderer) SetOption(name renderer.OptionName, value interface{}) {
+	r.Config.SetOption(name, value)
+}
+
+func (r *htmlRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(kindMarkdownHTMLBlock, r.renderMarkdownHTMLBlock)
+}
+
+func (r *htmlRenderer) renderMarkdownHTMLBlock(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*markdownHTMLBlock)
+
+	if !r.Unsafe {
+		_, _ = w.WriteString("<!-- raw HTML omitted -->\n")
+		return ast.WalkContinue, nil
+	}
+
+	if entering {
+		_, _ = w.Write(n.openTag)
+	} else {
+		_, _ = w.WriteString("</")
+		_, _ = w.Write(n.tag)
+		_ = w.WriteByte('>')
+	}
+	_ = w.WriteByte('\n')
+
+	return ast.WalkContinue, nil
+}
@@ -0,0 +1,325 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package math provides a Goldmark extension that parses inline $...$ and
+// display $$...$$ math, so the TeX isn't mangled by the Markdown parser, e.g.
+// the underscores read as emphasis. Display math may also be set on its own
+// lines:
+//
+//	$$
+//	\sum_{i=1}^n i = \frac{n(n+1)}{2}
+//	$$
+//
+// The math is passed through to the output with its delimiters, or rendered
+// to HTML at build time with the RenderFunc given.
+package math
+
+import (
+	"bytes"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+var (
+	KindInlineMath = ast.NewNodeKind("InlineMath")
+	KindMathBlock  = ast.NewNodeKind("MathBlock")
+)
+
+// RenderFunc renders the TeX in tex to HTML, as display math if display is
+// set. A nil result without an error passes the math through.
+type RenderFunc func(tex []byte, display bool) ([]byte, error)
+
+// New returns the extension. If render is nil, the math is passed through.
+func New(render RenderFunc) goldmark.Extender {
+	return &mathExtension{render: render}
+}
+
+type mathExtension struct {
+	render RenderFunc
+}
+
+func (e *mathExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		parser.WithBlockParsers(
+			// Must run before the paragraph parser.
+			util.Prioritized(&blockParser{}, 750),
+		),
+		parser.WithInlineParsers(
+			util.Prioritized(&inlineParser{}, 150),
+		),
+	)
+	m.Renderer().AddOptions(
+		renderer.WithNodeRenderers(
+			util.Prioritized(&htmlRenderer{render: e.render}, 100),
+		),
+	)
+}
+
+// InlineMath is inline $...$ math, or display $$...$$ math inside a
+// paragraph.
+type InlineMath struct {
+	ast.BaseInline
+
+	// The TeX without the delimiters.
+	Segment text.Segment
+
+	Display bool
+}
+
+func (n *InlineMath) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"TeX": string(n.Segment.Value(source))}, nil)
+}
+
+func (n *InlineMath) Kind() ast.NodeKind {
+	return KindInlineMath
+}
+
+// MathBlock is display $$...$$ math starting its own line. The TeX is in its
+// lines.
+type MathBlock struct {
+	ast.BaseBlock
+
+	// Set when the closing $$ is on the opening line.
+	closed bool
+}
+
+func (n *MathBlock) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, nil, nil)
+}
+
+func (n *MathBlock) Kind() ast.NodeKind {
+	return KindMathBlock
+}
+
+func (n *MathBlock) IsRaw() bool {
+	return true
+}
+
+type inlineParser struct{}
+
+func (p *inlineParser) Trigger() []byte {
+	return []byte{'$'}
+}
+
+func (p *inlineParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
+	line, segment := block.PeekLine()
+
+	delim := 1
+	if len(line) > 1 && line[1] == '$' {
+		delim = 2
+	}
+
+	rest := line[delim:]
+	if delim == 1 && (len(rest) == 0 || util.IsSpace(rest[0])) {
+		// Not math, e.g. "costs $ 5".
+		return nil
+	}
+
+	end := -1
+	for i := 0; i < len(rest); i++ {
+		c := rest[i]
+		if c == '\\' {
+			// Skip the escaped char, e.g. \$.
+			i++
+			continue
+		}
+		if c != '$' {
+			continue
+		}
+		if delim == 2 {
+			if i+1 < len(rest) && rest[i+1] == '$' {
+				end = i
+				break
+			}
+			continue
+		}
+		// The closing $ must not follow a space or be followed by a
+		// digit, so "$5 and $6" is not math.
+		if util.IsSpace(rest[i-1]) || (i+1 < len(rest) && util.IsNumeric(rest[i+1])) {
+			continue
+		}
+		end = i
+		break
+	}
+
+	if end <= 0 {
+		return nil
+	}
+
+	node := &InlineMath{
+		Segment: text.NewSegment(segment.Start+delim, segment.Start+delim+end),
+		Display: delim == 2,
+	}
+
+	block.Advance(delim + end + delim)
+
+	return node
+}
+
+type blockParser struct{}
+
+func (p *blockParser) Trigger() []byte {
+	return []byte{'$'}
+}
+
+func (p *blockParser) Open(parent ast.Node, reader text.Reader, pc parser.Context) (ast.Node, parser.State) {
+	line, segment := reader.PeekLine()
+	pos := pc.BlockOffset()
+	if pos < 0 || !bytes.HasPrefix(line[pos:], []byte("$$")) {
+		return nil, parser.NoChildren
+	}
+
+	node := &MathBlock{}
+	start := pos + 2
+
+	rest := util.TrimRightSpace(line[start:])
+	if len(rest) == 0 {
+		// $$ on its own line.
+		return node, parser.NoChildren
+	}
+
+	// Else this must be $$...$$ on a single line. Anything else, e.g.
+	// "$$a$$ and $$b$$", is left to the inline parser.
+	if len(rest) < 3 || !bytes.HasSuffix(rest, []byte("$$")) || bytes.Contains(rest[:len(rest)-2], []byte("$$")) {
+		return nil, parser.NoChildren
+	}
+	rest = rest[:len(rest)-2]
+	node.closed = true
+	node.Lines().Append(text.NewSegment(segment.Start+start, segment.Start+start+len(rest)))
+
+	return node, parser.NoChildren
+}
+
+func (p *blockParser) Continue(node ast.Node, reader text.Reader, pc parser.Context) parser.State {
+	n := node.(*MathBlock)
+	if n.closed {
+		return parser.Close
+	}
+
+	line, segment := reader.PeekLine()
+	if line == nil {
+		return parser.Close
+	}
+
+	trimmed := util.TrimRightSpace(line)
+	if bytes.HasSuffix(trimmed, []byte("$$")) {
+		if n := len(trimmed) - 2; n > 0 {
+			node.Lines().Append(text.NewSegment(segment.Start, segment.Start+n))
+		}
+		newline := 1
+		if line[len(line)-1] != '\n' {
+			newline = 0
+		}
+		reader.Advance(segment.Len() - newline)
+		return parser.Close
+	}
+
+	node.Lines().Append(segment)
+	reader.Advance(segment.Len() - 1)
+
+	return parser.Continue | parser.NoChildren
+}
+
+func (p *blockParser) Close(node ast.Node, reader text.Reader, pc parser.Context) {
+}
+
+func (p *blockParser) CanInterruptParagraph() bool {
+	return true
+}
+
+func (p *blockParser) CanAcceptIndentedLine() bool {
+	return false
+}
+
+type htmlRenderer struct {
+	html.Config
+
+	render RenderFunc
+}
+
+func (r *htmlRenderer) SetOption(name renderer.OptionName, value interface{}) {
+	r.Config.SetOption(name, value)
+}
+
+func (r *htmlRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindInlineMath, r.renderInlineMath)
+	reg.Register(KindMathBlock, r.renderMathBlock)
+}
+
+func (r *htmlRenderer) renderInlineMath(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+
+	n := node.(*InlineMath)
+	delim := "$"
+	if n.Display {
+		delim = "$$"
+	}
+
+	if err := r.renderMath(w, n.Segment.Value(source), n.Display, delim, delim); err != nil {
+		return ast.WalkStop, err
+	}
+
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *htmlRenderer) renderMathBlock(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+
+	var tex bytes.Buffer
+	lines := node.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		line := lines.At(i)
+		v := line.Value(source)
+		tex.Write(v)
+		if !bytes.HasSuffix(v, []byte("\n")) {
+			tex.WriteByte('\n')
+		}
+	}
+
+	_, _ = w.WriteString("<p>")
+	if err := r.renderMath(w, bytes.TrimSpace(tex.Bytes()), true, "$$\n", "\n$$"); err != nil {
+		return ast.WalkStop, err
+	}
+	_, _ = w.WriteString("</p>\n")
+
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *htmlRenderer) renderMath(w util.BufWriter, tex []byte, display bool, open, close string) error {
+	if r.render != nil {
+		b, err := r.render(tex, display)
+		if err != nil {
+			return err
+		}
+		if b != nil {
+			_, _ = w.Write(b)
+			return nil
+		}
+	}
+
+	_, _ = w.WriteString(open)
+	_, _ = w.Write(util.EscapeHTML(tex))
+	_, _ = w.WriteString(close)
+
+	return nil
+}
@@ -0,0 +1,333 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package plugins provides a Goldmark extension that parses the block types
+// and inline syntaxes declared by plugins and renders them with the plugin
+// declaring them. A block is a fenced container with the block type and
+// optional attributes on the opening line:
+//
+//	:::admonition warning
+//	Mind the **gap**.
+//	:::
+//
+// An inline syntax is set by its delimiters, e.g. [[ and ]] for [[Ctrl]].
+package plugins
+
+import (
+	"bytes"
+	"regexp"
+	"sort"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+var (
+	KindPluginBlock  = ast.NewNodeKind("PluginBlock")
+	KindPluginInline = ast.NewNodeKind("PluginInline")
+
+	// The opening line of a block, e.g. ":::admonition warning".
+	blockOpenRe = regexp.MustCompile(`^:::([A-Za-z][A-Za-z0-9_-]*)(?:[ \t]+(.*?))?[ \t]*$`)
+)
+
+// Block is a block type handled by a plugin.
+type Block struct {
+	Plugin string
+	Type   string
+}
+
+// Inline is an inline syntax handled by a plugin.
+type Inline struct {
+	Plugin string
+	Type   string
+
+	// The delimiters, e.g. "[[" and "]]".
+	Open  string
+	Close string
+}
+
+// Request is what a plugin renders to HTML.
+type Request struct {
+	// Either "block" or "inline".
+	Kind string `json:"kind"`
+
+	// The block type or the inline syntax type.
+	Type string `json:"type"`
+
+	// Any text after the block type on the opening line of a block.
+	Attributes string `json:"attributes,omitempty"`
+
+	// The raw content, without the delimiters.
+	Content string `json:"content"`
+}
+
+// RenderFunc renders req with the named plugin.
+type RenderFunc func(plugin string, req Request) ([]byte, error)
+
+// New returns the extension.
+func New(blocks []Block, inlines []Inline, render RenderFunc) goldmark.Extender {
+	return &pluginsExtension{blocks: blocks, inlines: inlines, render: render}
+}
+
+type pluginsExtension struct {
+	blocks  []Block
+	inlines []Inline
+	render  RenderFunc
+}
+
+func (e *pluginsExtension) Extend(m goldmark.Markdown) {
+	var parserOptions []parser.Option
+
+	if len(e.blocks) > 0 {
+		bp := &blockParser{blocks: make(map[string]Block)}
+		for _, b := range e.blocks {
+			bp.blocks[b.Type] = b
+		}
+		// Must run before the paragraph parser.
+		parserOptions = append(parserOptions, parser.WithBlockParsers(util.Prioritized(bp, 750)))
+	}
+
+	if len(e.inlines) > 0 {
+		ip := &inlineParser{inlines: make([]Inline, len(e.inlines))}
+		copy(ip.inlines, e.inlines)
+		// Try the longest delimiters first.
+		sort.SliceStable(ip.inlines, func(i, j int) bool {
+			return len(ip.inlines[i].Open) > len(ip.inlines[j].Open)
+		})
+		seen := make(map[byte]bool)
+		for _, in := range ip.inlines {
+			if !seen[in.Open[0]] {
+				seen[in.Open[0]] = true
+				ip.triggers = append(ip.triggers, in.Open[0])
+			}
+		}
+		// Must run after the code span parser.
+		parserOptions = append(parserOptions, parser.WithInlineParsers(util.Prioritized(ip, 180)))
+	}
+
+	m.Parser().AddOptions(parserOptions...)
+	m.Renderer().AddOptions(
+		renderer.WithNodeRenderers(
+			util.Prioritized(&htmlRenderer{render: e.render}, 100),
+		),
+	)
+}
+
+// PluginBlock is a block rendered by a plugin. The content is in its lines.
+type PluginBlock struct {
+	ast.BaseBlock
+
+	Block Block
+
+	// Any text after the block type on the opening line.
+	Attrs []byte
+}
+
+func (n *PluginBlock) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Type": n.Block.Type, "Attributes": string(n.Attrs)}, nil)
+}
+
+func (n *PluginBlock) Kind() ast.NodeKind {
+	return KindPluginBlock
+}
+
+func (n *PluginBlock) IsRaw() bool {
+	return true
+}
+
+// PluginInline is an inline syntax rendered by a plugin.
+type PluginInline struct {
+	ast.BaseInline
+
+	Inline Inline
+
+	// The content without the delimiters.
+	Segment text.Segment
+}
+
+func (n *PluginInline) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Type": n.Inline.Type, "Content": string(n.Segment.Value(source))}, nil)
+}
+
+func (n *PluginInline) Kind() ast.NodeKind {
+	return KindPluginInline
+}
+
+type blockParser struct {
+	blocks map[string]Block
+}
+
+func (p *blockParser) Trigger() []byte {
+	return []byte{':'}
+}
+
+func (p *blockParser) Open(parent ast.Node, reader text.Reader, pc parser.Context) (ast.Node, parser.State) {
+	line, _ := reader.PeekLine()
+	pos := pc.BlockOffset()
+	if pos < 0 {
+		return nil, parser.NoChildren
+	}
+
+	m := blockOpenRe.FindSubmatch(bytes.TrimRight(line[pos:], "\r\n"))
+	if m == nil {
+		return nil, parser.NoChildren
+	}
+
+	b, found := p.blocks[string(m[1])]
+	if !found {
+		return nil, parser.NoChildren
+	}
+
+	return &PluginBlock{Block: b, Attrs: m[2]}, parser.NoChildren
+}
+
+func (p *blockParser) Continue(node ast.Node, reader text.Reader, pc parser.Context) parser.State {
+	line, segment := reader.PeekLine()
+	if line == nil {
+		return parser.Close
+	}
+
+	if string(util.TrimRightSpace(util.TrimLeftSpace(line))) == ":::" {
+		newline := 1
+		if line[len(line)-1] != '\n' {
+			newline = 0
+		}
+		reader.Advance(segment.Len() - newline)
+		return parser.Close
+	}
+
+	node.Lines().Append(segment)
+	reader.Advance(segment.Len() - 1)
+
+	return parser.Continue | parser.NoChildren
+}
+
+func (p *blockParser) Close(node ast.Node, reader text.Reader, pc parser.Context) {
+}
+
+func (p *blockParser) CanInterruptParagraph() bool {
+	return true
+}
+
+func (p *blockParser) CanAcceptIndentedLine() bool {
+	return false
+}
+
+type inlineParser struct {
+	inlines  []Inline
+	triggers []byte
+}
+
+func (p *inlineParser) Trigger() []byte {
+	return p.triggers
+}
+
+func (p *inlineParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
+	line, segment := block.PeekLine()
+
+	for _, in := range p.inlines {
+		if !bytes.HasPrefix(line, []byte(in.Open)) {
+			continue
+		}
+		rest := line[len(in.Open):]
+		end := bytes.Index(rest, []byte(in.Close))
+		if end <= 0 {
+			continue
+		}
+
+		start := segment.Start + len(in.Open)
+		node := &PluginInline{
+			Inline:  in,
+			Segment: text.NewSegment(start, start+end),
+		}
+
+		block.Advance(len(in.Open) + end + len(in.Close))
+
+		return node
+	}
+
+	return nil
+}
+
+type htmlRenderer struct {
+	html.Config
+
+	render RenderFunc
+}
+
+func (r *htmlRenderer) SetOption(name renderer.OptionName, value interface{}) {
+	r.Config.SetOption(name, value)
+}
+
+func (r *htmlRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindPluginBlock, r.renderBlock)
+	reg.Register(KindPluginInline, r.renderInline)
+}
+
+func (r *htmlRenderer) renderBlock(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+
+	n := node.(*PluginBlock)
+
+	var content bytes.Buffer
+	lines := n.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		line := lines.At(i)
+		content.Write(line.Value(source))
+	}
+
+	b, err := r.render(n.Block.Plugin, Request{
+		Kind:       "block",
+		Type:       n.Block.Type,
+		Attributes: string(n.Attrs),
+		Content:    content.String(),
+	})
+	if err != nil {
+		return ast.WalkStop, err
+	}
+
+	_, _ = w.Write(b)
+	if len(b) > 0 && b[len(b)-1] != '\n' {
+		_ = w.WriteByte('\n')
+	}
+
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *htmlRenderer) renderInline(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+
+	n := node.(*PluginInline)
+
+	b, err := r.render(n.Inline.Plugin, Request{
+		Kind:    "inline",
+		Type:    n.Inline.Type,
+		Content: string(n.Segment.Value(source)),
+	})
+	if err != nil {
+		return ast.WalkStop, err
+	}
+
+	_, _ = w.Write(bytes.TrimRight(b, "\r\n"))
+
+	return ast.WalkSkipChildren, nil
+}
@@ -0,0 +1,71 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package goldmark
+
+import (
+	"path/filepath"
+
+	"github.com/gohugoio/hugo/markup/converter"
+	mathext "github.com/gohugoio/hugo/markup/goldmark/internal/extensions/math"
+	"github.com/gohugoio/hugo/markup/mathml"
+	"github.com/pkg/errors"
+)
+
+// newMathRenderer returns the func rendering the math to MathML at build
+// time, or nil if the math is passed through to the output.
+func newMathRenderer(pcfg converter.ProviderConfig) (mathext.RenderFunc, error) {
+	cfg := pcfg.MarkupConfig.Goldmark.Extensions.Math
+	switch cfg.Output {
+	case "":
+		return nil, nil
+	case "mathml":
+	default:
+		return nil, errors.Errorf("math: unsupported output %q; the only build-time output is %q", cfg.Output, "mathml")
+	}
+
+	var macros map[string]string
+	if cfg.MacroFile != "" {
+		if pcfg.AssetsFs == nil {
+			return nil, errors.New("math: no assets filesystem to read the macro file from")
+		}
+		f, err := pcfg.AssetsFs.Open(filepath.Clean(cfg.MacroFile))
+		if err != nil {
+			return nil, errors.Wrap(err, "math: failed to open macro file")
+		}
+		defer f.Close()
+		macros, err = mathml.ParseMacros(f)
+		if err != nil {
+			return nil, errors.Wrapf(err, "math: failed to parse %q", cfg.MacroFile)
+		}
+	}
+
+	return func(tex []byte, display bool) ([]byte, error) {
+		s, err := mathml.Render(string(tex), mathml.Options{
+			Display:      display,
+			ThrowOnError: cfg.ThrowOnError,
+			Macros:       macros,
+		})
+		if perr, ok := err.(*mathml.ParseError); ok && perr.Unsupported {
+			// Leave it to be rendered client side.
+			if pcfg.Logger != nil {
+				pcfg.Logger.Warnf("math: passing through %q: %s", tex, err)
+			}
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return []byte(s), nil
+	}, nil
+}
@@ -0,0 +1,249 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package goldmark
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gohugoio/hugo/common/types"
+	"github.com/gohugoio/hugo/markup/converter"
+	pluginsext "github.com/gohugoio/hugo/markup/goldmark/internal/extensions/plugins"
+	"github.com/gohugoio/hugo/parser/metadecoders"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+	"github.com/tetratelabs/wazero/sys"
+)
+
+const (
+	// The plugins are looked up in this directory in the assets, so a Hugo
+	// module can ship them.
+	pluginsDir = "goldmark/plugins"
+
+	// The limit for the linear memory of a plugin, 256 MiB in 64 KiB pages.
+	pluginMaxMemoryPages = 4096
+)
+
+// pluginManifest is the plugin.toml declaring what a plugin renders.
+type pluginManifest struct {
+	// The block types, see the plugins extension.
+	Blocks []string
+
+	// The inline syntaxes.
+	Inline []struct {
+		Type  string
+		Open  string
+		Close string
+	}
+}
+
+type plugin struct {
+	name string
+	wasm []byte
+
+	compileInit sync.Once
+	module      wazero.CompiledModule
+	compileErr  error
+}
+
+// compile compiles the WASM module once, on first use.
+func (p *plugin) compile(rt wazero.Runtime) (wazero.CompiledModule, error) {
+	p.compileInit.Do(func() {
+		p.module, p.compileErr = rt.CompileModule(context.Background(), p.wasm)
+	})
+	return p.module, p.compileErr
+}
+
+// pluginRunner runs the Goldmark plugins.
+type pluginRunner struct {
+	timeout time.Duration
+
+	runtimeInit sync.Once
+	runtime     wazero.Runtime
+
+	plugins map[string]*plugin
+	blocks  []pluginsext.Block
+	inlines []pluginsext.Inline
+
+	mu    sync.RWMutex
+	cache map[string][]byte
+}
+
+// loadPlugins loads the plugins in the assets. It returns nil if there are
+// none.
+func loadPlugins(pcfg converter.ProviderConfig) (*pluginRunner, error) {
+	cfg := pcfg.MarkupConfig.Goldmark.Plugins
+	if cfg.Disable || pcfg.AssetsFs == nil {
+		return nil, nil
+	}
+
+	fis, err := afero.ReadDir(pcfg.AssetsFs, pluginsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	timeout, err := types.ToDurationE(cfg.Timeout)
+	if err != nil {
+		return nil, errors.Wrap(err, "goldmark plugins: invalid timeout")
+	}
+
+	r := &pluginRunner{
+		timeout: timeout,
+		plugins: make(map[string]*plugin),
+		cache:   make(map[string][]byte),
+	}
+
+	blockPlugins := make(map[string]string)
+
+	for _, fi := range fis {
+		if !fi.IsDir() {
+			continue
+		}
+		name := fi.Name()
+		dir := filepath.Join(pluginsDir, name)
+
+		b, err := afero.ReadFile(pcfg.AssetsFs, filepath.Join(dir, "plugin.toml"))
+		if err != nil {
+			return nil, errors.Wrapf(err, "goldmark plugin %q", name)
+		}
+		m, err := metadecoders.Default.UnmarshalToMap(b, metadecoders.TOML)
+		if err != nil {
+			return nil, errors.Wrapf(err, "goldmark plugin %q: failed to parse plugin.toml", name)
+		}
+		var manifest pluginManifest
+		if err := mapstructure.WeakDecode(m, &manifest); err != nil {
+			return nil, errors.Wrapf(err, "goldmark plugin %q: failed to decode plugin.toml", name)
+		}
+
+		wasm, err := afero.ReadFile(pcfg.AssetsFs, filepath.Join(dir, "plugin.wasm"))
+		if err != nil {
+			return nil, errors.Wrapf(err, "goldmark plugin %q", name)
+		}
+
+		for _, typ := range manifest.Blocks {
+			if other, found := blockPlugins[typ]; found {
+				return nil, errors.Errorf("goldmark plugin %q: block type %q is already declared by plugin %q", name, typ, other)
+			}
+			blockPlugins[typ] = name
+			r.blocks = append(r.blocks, pluginsext.Block{Plugin: name, Type: typ})
+		}
+
+		for _, in := range manifest.Inline {
+			if in.Open == "" || in.Close == "" {
+				return nil, errors.Errorf("goldmark plugin %q: inline syntax %q must have both open and close delimiters", name, in.Type)
+			}
+			r.inlines = append(r.inlines, pluginsext.Inline{Plugin: name, Type: in.Type, Open: in.Open, Close: in.Close})
+		}
+
+		r.plugins[name] = &plugin{name: name, wasm: wasm}
+	}
+
+	if len(r.plugins) == 0 {
+		return nil, nil
+	}
+
+	sort.Slice(r.blocks, func(i, j int) bool {
+		return r.blocks[i].Type < r.blocks[j].Type
+	})
+
+	return r, nil
+}
+
+// newRuntime creates the WASM runtime the plugins run in, once, on first
+// use. The plugins get WASI preview 1 with arguments, stdin, stdout,
+// stderr, clocks and random numbers, but no file system or network access.
+func (r *pluginRunner) newRuntime() wazero.Runtime {
+	r.runtimeInit.Do(func() {
+		ctx := context.Background()
+		r.runtime = wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfig().
+			WithCloseOnContextDone(true).
+			WithMemoryLimitPages(pluginMaxMemoryPages))
+		wasi_snapshot_preview1.MustInstantiate(ctx, r.runtime)
+	})
+	return r.runtime
+}
+
+// render runs the named plugin in the embedded WASM runtime with req as JSON
+// on stdin. The plugin writes the HTML to stdout. The result is cached, so a plugin is only run once for
+// the same request.
+func (r *pluginRunner) render(name string, req pluginsext.Request) ([]byte, error) {
+	b, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	key := name + "\x00" + string(b)
+
+	r.mu.RLock()
+	res, found := r.cache[key]
+	r.mu.RUnlock()
+	if found {
+		return res, nil
+	}
+
+	rt := r.newRuntime()
+	compiled, err := r.plugins[name].compile(rt)
+	if err != nil {
+		return nil, errors.Wrapf(err, "goldmark plugin %q", name)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+
+	var out, errBuf bytes.Buffer
+	mod, err := rt.InstantiateModule(ctx, compiled, wazero.NewModuleConfig().
+		// Anonymous, so the plugin can run concurrently.
+		WithName("").
+		WithArgs(name).
+		WithStdin(bytes.NewReader(b)).
+		WithStdout(&out).
+		WithStderr(&errBuf).
+		WithSysWalltime().
+		WithSysNanotime().
+		WithSysNanosleep().
+		WithRandSource(rand.Reader))
+	if mod != nil {
+		mod.Close(ctx)
+	}
+	if err != nil {
+		if exitErr, ok := err.(*sys.ExitError); ok && exitErr.ExitCode() == sys.ExitCodeDeadlineExceeded {
+			err = errors.Errorf("timed out after %s", r.timeout)
+		}
+		if stderr := strings.TrimSpace(errBuf.String()); stderr != "" {
+			err = errors.Errorf("%s: %s", err, stderr)
+		}
+		return nil, errors.Wrapf(err, "goldmark plugin %q: failed to render %s %q", name, req.Kind, req.Type)
+	}
+
+	res = out.Bytes()
+
+	r.mu.Lock()
+	r.cache[key] = res
+	r.mu.Unlock()
+
+	return res, nil
+}
@@ -0,0 +1,124 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package goldmark
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/gohugoio/hugo/common/loggers"
+	"github.com/gohugoio/hugo/config"
+	"github.com/gohugoio/hugo/markup/converter"
+	"github.com/gohugoio/hugo/markup/markup_config"
+	"github.com/spf13/afero"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestConvertPlugins(t *testing.T) {
+	c := qt.New(t)
+
+	// The demo plugin is a Go program compiled to WASI, see its main.go.
+	wasm, err := ioutil.ReadFile(filepath.Join("testdata", "plugins", "demo", "plugin.wasm"))
+	c.Assert(err, qt.IsNil)
+	manifest, err := ioutil.ReadFile(filepath.Join("testdata", "plugins", "demo", "plugin.toml"))
+	c.Assert(err, qt.IsNil)
+
+	newProvider := func(manifest string, wasm []byte) (converter.Provider, error) {
+		assetsFs := afero.NewMemMapFs()
+		c.Assert(afero.WriteFile(assetsFs, filepath.FromSlash("goldmark/plugins/demo/plugin.toml"), []byte(manifest), 0666), qt.IsNil)
+		c.Assert(afero.WriteFile(assetsFs, filepath.FromSlash("goldmark/plugins/demo/plugin.wasm"), wasm, 0666), qt.IsNil)
+
+		mconf := markup_config.Default
+		mconf.Goldmark.Plugins.Timeout = "1s"
+
+		return Provider.New(
+			converter.ProviderConfig{
+				MarkupConfig: mconf,
+				Cfg:          config.New(),
+				AssetsFs:     assetsFs,
+				Logger:       loggers.NewErrorLogger(),
+			},
+		)
+	}
+
+	convert := func(p converter.Provider, src string) (string, error) {
+		conv, err := p.New(converter.DocumentContext{})
+		c.Assert(err, qt.IsNil)
+		b, err := conv.Convert(converter.RenderContext{Src: []byte(src)})
+		if err != nil {
+			return "", err
+		}
+		return string(b.Bytes()), nil
+	}
+
+	p, err := newProvider(string(manifest), wasm)
+	c.Assert(err, qt.IsNil)
+
+	got, err := convert(p, `Press [[Ctrl]] and `+"`[[not]]`"+`.
+
+:::admonition warning
+Mind the **gap** & <go>.
+:::
+
+:::note
+Not a plugin.
+:::
+`)
+	c.Assert(err, qt.IsNil)
+	c.Assert(got, qt.Equals, `<p>Press <kbd>Ctrl</kbd> and <code>[[not]]</code>.</p>
+<div class="admonition warning">Mind the **gap** &amp; &lt;go&gt;.</div>
+<p>:::note
+Not a plugin.
+:::</p>
+`)
+
+	c.Run("Concurrent", func(c *qt.C) {
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				got, err := convert(p, "[[Key"+string(rune('A'+i))+"]]\n")
+				c.Check(err, qt.IsNil)
+				c.Check(got, qt.Equals, "<p><kbd>Key"+string(rune('A'+i))+"</kbd></p>\n")
+			}(i)
+		}
+		wg.Wait()
+	})
+
+	c.Run("Exit status", func(c *qt.C) {
+		_, err := convert(p, ":::other\nText.\n:::\n")
+		c.Assert(err, qt.ErrorMatches, `goldmark plugin "demo": failed to render block "other": module closed with exit_code\(2\): unknown type other`)
+	})
+
+	c.Run("Timeout", func(c *qt.C) {
+		_, err := convert(p, ":::spin\nText.\n:::\n")
+		c.Assert(err, qt.ErrorMatches, `goldmark plugin "demo": failed to render block "spin": timed out after 1s`)
+	})
+
+	_, err = newProvider(`
+[[inline]]
+type = "kbd"
+open = "[["
+`, wasm)
+	c.Assert(err, qt.ErrorMatches, `goldmark plugin "demo": inline syntax "kbd" must have both open and close delimiters`)
+
+	p, err = newProvider(`blocks = ["admonition"]`, []byte("WASM"))
+	c.Assert(err, qt.IsNil)
+	_, err = convert(p, ":::admonition\nText.\n:::\n")
+	c.Assert(err, qt.ErrorMatches, `goldmark plugin "demo": .*invalid magic number`)
+}
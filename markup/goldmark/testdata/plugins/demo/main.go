@@ -0,0 +1,52 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// The demo Goldmark plugin used in the tests. Build it with:
+//
+//	GOOS=wasip1 GOARCH=wasm go build -ldflags="-s -w" -o plugin.wasm main.go
+package main
+
+import (
+	"encoding/json"
+	"html"
+	"os"
+	"strings"
+)
+
+type request struct {
+	Kind       string `json:"kind"`
+	Type       string `json:"type"`
+	Attributes string `json:"attributes"`
+	Content    string `json:"content"`
+}
+
+func main() {
+	var req request
+	if err := json.NewDecoder(os.Stdin).Decode(&req); err != nil {
+		os.Stderr.WriteString(err.Error())
+		os.Exit(1)
+	}
+
+	switch req.Type {
+	case "admonition":
+		os.Stdout.WriteString(`<div class="admonition ` + html.EscapeString(req.Attributes) + `">` + html.EscapeString(strings.TrimSpace(req.Content)) + "</div>\n")
+	case "kbd":
+		os.Stdout.WriteString("<kbd>" + html.EscapeString(req.Content) + "</kbd>")
+	case "spin":
+		for {
+		}
+	default:
+		os.Stderr.WriteString("unknown type " + req.Type)
+		os.Exit(2)
+	}
+}
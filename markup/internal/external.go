@@ -15,11 +15,12 @@ func ExternallyRenderContent(
 	ctx converter.DocumentContext,
 	content []byte, path string, args []string) []byte {
 	logger := cfg.Logger
-	cmd, err := hexec.SafeCommand(path, args...)
+	cmd, done, err := hexec.Command(cfg.Cfg, "", path, args...)
 	if err != nil {
 		logger.Errorf("%s rendering %s: %v", path, ctx.DocumentName, err)
 		return nil
 	}
+	defer done()
 	cmd.Stdin = bytes.NewReader(content)
 	var out, cmderr bytes.Buffer
 	cmd.Stdout = &out
@@ -21,6 +21,7 @@ import (
 	"github.com/gohugoio/hugo/markup/blackfriday/blackfriday_config"
 	"github.com/gohugoio/hugo/markup/goldmark/goldmark_config"
 	"github.com/gohugoio/hugo/markup/highlight"
+	"github.com/gohugoio/hugo/markup/sanitize"
 	"github.com/gohugoio/hugo/markup/tableofcontents"
 	"github.com/gohugoio/hugo/parser"
 	"github.com/mitchellh/mapstructure"
@@ -35,6 +36,9 @@ type Config struct {
 	Highlight       highlight.Config
 	TableOfContents tableofcontents.Config
 
+	// HTML sanitization policies.
+	Sanitize sanitize.Config
+
 	// Content renderers
 	Goldmark    goldmark_config.Config
 	BlackFriday blackfriday_config.Config
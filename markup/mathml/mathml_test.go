@@ -0,0 +1,134 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mathml
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestRender(t *testing.T) {
+	c := qt.New(t)
+
+	c.Assert(mustRender(c, `a_1 < b`, Options{}), qt.Equals,
+		`<math xmlns="http://www.w3.org/1998/Math/MathML"><semantics><mrow><msub><mi>a</mi><mn>1</mn></msub><mo>&lt;</mo><mi>b</mi></mrow><annotation encoding="application/x-tex">a_1 &lt; b</annotation></semantics></math>`)
+	c.Assert(mustRender(c, `x`, Options{Display: true}), qt.Contains, `<math xmlns="http://www.w3.org/1998/Math/MathML" display="block">`)
+}
+
+func TestRenderKaTeX(t *testing.T) {
+	c := qt.New(t)
+
+	b, err := ioutil.ReadFile(filepath.Join("testdata", "katex.txt"))
+	c.Assert(err, qt.IsNil)
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	for len(lines) > 0 && strings.HasPrefix(lines[0], "#") {
+		lines = lines[1:]
+	}
+	c.Assert(len(lines)%2, qt.Equals, 0)
+
+	for i := 0; i < len(lines); i += 2 {
+		parts := strings.SplitN(lines[i], "\t", 2)
+		tex, display := parts[1], parts[0] == "1"
+		got := mustRender(c, tex, Options{Display: display})
+		got = got[strings.Index(got, "<semantics>")+len("<semantics>") : strings.Index(got, "<annotation")]
+		c.Assert(got, qt.Equals, lines[i+1], qt.Commentf(tex))
+	}
+}
+
+func TestRenderMacros(t *testing.T) {
+	c := qt.New(t)
+
+	macros := map[string]string{
+		"RR":   `\mathbb{R}`,
+		"norm": `\left\lVert #1 \right\rVert`,
+	}
+
+	c.Assert(mustRender(c, `\RR \norm{v}`, Options{Macros: macros}), qt.Contains,
+		`<semantics><mrow><mi mathvariant="double-struck">R</mi><mrow><mo fence="true">∥</mo><mi>v</mi><mo fence="true">∥</mo></mrow></mrow>`)
+	c.Assert(mustRender(c, "a % b\nc", Options{}), qt.Contains, `<semantics><mrow><mi>a</mi><mi>c</mi></mrow>`)
+}
+
+func TestRenderErrors(t *testing.T) {
+	c := qt.New(t)
+
+	for _, test := range []struct {
+		tex         string
+		expect      string
+		unsupported bool
+	}{
+		{`x^1^2`, `double superscript at position 3`, false},
+		{`\frac{a}`, `missing argument for \frac at position 8`, false},
+		{`{a`, `expected } but got end of input at position 2`, false},
+		{`a}`, `unexpected } at position 1`, false},
+		{`a & b`, `unexpected & at position 2`, false},
+		{`\left( a`, `missing \right at position 8`, false},
+		{`a \middle|`, `\middle without preceding \left at position 2`, false},
+		{`\begin{matrix} a \end{pmatrix}`, `\begin{matrix} ended by \end{pmatrix} at position 17`, false},
+		{`\loop`, `too many expansions of \loop at position 0`, false},
+		{`\foo`, `undefined control sequence \foo at position 0`, true},
+		{`\overset{!}{=}`, `undefined control sequence \overset at position 0`, true},
+		{`\begin{foo}\end{foo}`, `unknown environment "foo" at position 0`, true},
+		{`\begin{array}{cc} a & b \\ \hline \end{array}`, `undefined control sequence \hline at position 27`, true},
+		{`\text{\alpha}`, `undefined control sequence \alpha in text at position 6`, true},
+		{`\text{$x$}`, `unsupported math in text at position 6`, true},
+		{`\hspace{2pt}`, `unsupported width "2pt" for \hspace at position 0`, true},
+		{`a ∥ b`, `unsupported character "∥" at position 2`, true},
+	} {
+		_, err := Render(test.tex, Options{ThrowOnError: true, Macros: map[string]string{"loop": `\loop`}})
+		c.Assert(err, qt.Not(qt.IsNil), qt.Commentf(test.tex))
+		c.Assert(err, qt.ErrorMatches, "mathml: "+regexp.QuoteMeta(test.expect))
+		perr, ok := err.(*ParseError)
+		c.Assert(ok, qt.IsTrue)
+		c.Assert(perr.Unsupported, qt.Equals, test.unsupported, qt.Commentf(test.tex))
+	}
+
+	// The error is rendered in place.
+	s, err := Render(`a < x^1^2`, Options{})
+	c.Assert(err, qt.IsNil)
+	c.Assert(s, qt.Equals, `<math xmlns="http://www.w3.org/1998/Math/MathML"><merror title="mathml: double superscript at position 7"><mtext>a &lt; x^1^2</mtext></merror></math>`)
+
+	// TeX outside the subset is always returned.
+	_, err = Render(`a < \foo`, Options{})
+	c.Assert(err, qt.ErrorMatches, `mathml: undefined control sequence \\foo at position 4`)
+}
+
+func TestParseMacros(t *testing.T) {
+	c := qt.New(t)
+
+	macros, err := ParseMacros(strings.NewReader(`
+% Sets.
+\RR:\mathbb{R}
+\norm:\left\lVert #1 \right\rVert
+`))
+	c.Assert(err, qt.IsNil)
+	c.Assert(macros, qt.DeepEquals, map[string]string{
+		"RR":   `\mathbb{R}`,
+		"norm": `\left\lVert #1 \right\rVert`,
+	})
+
+	_, err = ParseMacros(strings.NewReader(`RR:\mathbb{R}`))
+	c.Assert(err, qt.ErrorMatches, `mathml: invalid macro definition on line 1: .*`)
+}
+
+func mustRender(c *qt.C, tex string, opts Options) string {
+	opts.ThrowOnError = true
+	s, err := Render(tex, opts)
+	c.Assert(err, qt.IsNil)
+	return s
+}
@@ -0,0 +1,1158 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mathml
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokChar
+	tokCommand
+	tokSpace
+	tokOpen
+	tokClose
+	tokSup
+	tokSub
+	tokAlign
+	tokParam
+)
+
+type token struct {
+	kind tokenKind
+	val  string
+	pos  int
+}
+
+func (t token) String() string {
+	if t.kind == tokEOF {
+		return "end of input"
+	}
+	return t.val
+}
+
+func isASCIILetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func lex(s string) ([]token, error) {
+	var toks []token
+	for i := 0; i < len(s); {
+		pos := i
+		c := s[i]
+		switch {
+		case c == '\\':
+			i++
+			if i == len(s) {
+				return nil, &ParseError{Msg: `unexpected end of input after \`, Pos: pos}
+			}
+			if !isASCIILetter(s[i]) {
+				_, w := utf8.DecodeRuneInString(s[i:])
+				i += w
+				toks = append(toks, token{tokCommand, s[pos:i], pos})
+				continue
+			}
+			for i < len(s) && isASCIILetter(s[i]) {
+				i++
+			}
+			toks = append(toks, token{tokCommand, s[pos:i], pos})
+			// Skip the spaces after a control word, as TeX does.
+			for i < len(s) && isSpace(s[i]) {
+				i++
+			}
+		case c == '%':
+			for i < len(s) && s[i] != '\n' {
+				i++
+			}
+		case isSpace(c):
+			for i < len(s) && isSpace(s[i]) {
+				i++
+			}
+			toks = append(toks, token{tokSpace, " ", pos})
+		case c == '#':
+			i++
+			if i == len(s) || s[i] < '1' || s[i] > '9' {
+				return nil, &ParseError{Msg: "unexpected #", Pos: pos}
+			}
+			i++
+			toks = append(toks, token{tokParam, s[pos:i], pos})
+		default:
+			kind := tokChar
+			switch c {
+			case '{':
+				kind = tokOpen
+			case '}':
+				kind = tokClose
+			case '^':
+				kind = tokSup
+			case '_':
+				kind = tokSub
+			case '&':
+				kind = tokAlign
+			}
+			_, w := utf8.DecodeRuneInString(s[i:])
+			i += w
+			toks = append(toks, token{kind, s[pos:i], pos})
+		}
+	}
+	return toks, nil
+}
+
+// The maximum number of macro expansions, to stop recursive macros.
+const maxExpansions = 1000
+
+type parser struct {
+	toks []token
+	i    int
+	end  int
+
+	macros     map[string][]token
+	expansions int
+
+	// The current style.
+	style style
+
+	// The mathvariant set by a font command, e.g. bold for \mathbf.
+	font string
+
+	// The depth of nested environments, e.g. matrix, and of \left.
+	envDepth  int
+	leftDepth int
+}
+
+func parse(tex string, opts Options) (nodes []*node, err error) {
+	toks, err := lex(tex)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{toks: toks, end: len(tex), style: styleText}
+	if opts.Display {
+		p.style = styleDisplay
+	}
+
+	if len(opts.Macros) > 0 {
+		p.macros = make(map[string][]token)
+		for name, expansion := range opts.Macros {
+			toks, err := lex(expansion)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid macro \\%s", name)
+			}
+			p.macros[name] = toks
+		}
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			perr, ok := r.(*ParseError)
+			if !ok {
+				panic(r)
+			}
+			err = perr
+		}
+	}()
+
+	nodes = p.parseExpr("")
+	if t := p.next(); t.kind != tokEOF {
+		p.errorf(t.pos, "unexpected %s", t)
+	}
+
+	return nodes, nil
+}
+
+func (p *parser) errorf(pos int, format string, args ...interface{}) {
+	panic(&ParseError{Msg: fmt.Sprintf(format, args...), Pos: pos})
+}
+
+// unsupportedf is errorf for commands and environments not supported.
+func (p *parser) unsupportedf(pos int, format string, args ...interface{}) {
+	panic(&ParseError{Msg: fmt.Sprintf(format, args...), Pos: pos, Unsupported: true})
+}
+
+// peekRaw returns the next token, expanding any macros.
+func (p *parser) peekRaw() token {
+	for {
+		if p.i >= len(p.toks) {
+			return token{kind: tokEOF, pos: p.end}
+		}
+		t := p.toks[p.i]
+		if t.kind != tokCommand {
+			return t
+		}
+		body, found := p.macros[t.val[1:]]
+		if !found {
+			return t
+		}
+		p.expand(t, body)
+	}
+}
+
+// peek returns the next token, skipping spaces.
+func (p *parser) peek() token {
+	for {
+		t := p.peekRaw()
+		if t.kind != tokSpace {
+			return t
+		}
+		p.i++
+	}
+}
+
+// next consumes the next token, skipping spaces.
+func (p *parser) next() token {
+	t := p.peek()
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+func (p *parser) expand(t token, body []token) {
+	p.expansions++
+	if p.expansions > maxExpansions {
+		p.errorf(t.pos, "too many expansions of %s", t)
+	}
+	p.i++
+
+	var nargs int
+	for _, bt := range body {
+		if bt.kind == tokParam {
+			if n := int(bt.val[1] - '0'); n > nargs {
+				nargs = n
+			}
+		}
+	}
+	args := make([][]token, nargs)
+	for i := range args {
+		args[i] = p.rawArg(t)
+	}
+
+	var toks []token
+	for _, bt := range body {
+		if bt.kind == tokParam {
+			toks = append(toks, args[bt.val[1]-'1']...)
+			continue
+		}
+		// Report any errors at the macro.
+		bt.pos = t.pos
+		toks = append(toks, bt)
+	}
+
+	p.toks = append(toks, p.toks[p.i:]...)
+	p.i = 0
+}
+
+// rawArg reads the unexpanded tokens of a macro argument.
+func (p *parser) rawArg(macro token) []token {
+	for p.i < len(p.toks) && p.toks[p.i].kind == tokSpace {
+		p.i++
+	}
+	if p.i == len(p.toks) {
+		p.errorf(p.end, "missing argument for %s", macro)
+	}
+	t := p.toks[p.i]
+	p.i++
+	if t.kind != tokOpen {
+		return []token{t}
+	}
+	start, depth := p.i, 1
+	for ; p.i < len(p.toks); p.i++ {
+		switch p.toks[p.i].kind {
+		case tokOpen:
+			depth++
+		case tokClose:
+			depth--
+			if depth == 0 {
+				arg := p.toks[start:p.i]
+				p.i++
+				return arg
+			}
+		}
+	}
+	p.errorf(t.pos, "missing }")
+	return nil
+}
+
+// parseExpr parses a list of atoms up to a }, &, \\ in environments,
+// \right, \end, the end of input, or the character stop, if set.
+func (p *parser) parseExpr(stop string) []*node {
+	var nodes []*node
+	for {
+		t := p.peek()
+		switch t.kind {
+		case tokEOF, tokClose, tokAlign:
+			return nodes
+		case tokChar:
+			if t.val == stop {
+				return nodes
+			}
+		case tokCommand:
+			switch t.val {
+			case `\right`, `\end`:
+				return nodes
+			case `\\`, `\cr`, `\newline`:
+				if p.envDepth > 0 && t.val != `\newline` {
+					return nodes
+				}
+				p.next()
+				nodes = append(nodes, newNode("mspace").withAttrs(attr{"linebreak", "newline"}))
+				continue
+			case `\displaystyle`, `\textstyle`, `\scriptstyle`, `\scriptscriptstyle`:
+				// Applies to the rest of the group.
+				p.next()
+				s := p.style
+				p.style = styles[t.val[1:]]
+				n := p.style.node(p.parseExpr(stop)...)
+				p.style = s
+				return appendNode(nodes, n)
+			case `\color`:
+				// Applies to the rest of the group.
+				p.next()
+				color := strings.TrimSpace(p.stringArg(t))
+				children := p.parseExpr(stop)
+				return appendNode(nodes, newNode("mstyle", children...).withAttrs(attr{"mathcolor", color}))
+			}
+		}
+		for _, n := range p.parseAtom() {
+			nodes = appendNode(nodes, n)
+		}
+	}
+}
+
+// parseAtom parses a base with its scripts, if any.
+func (p *parser) parseAtom() []*node {
+	base := p.parseBase()
+
+	for {
+		t := p.peek()
+		if t.kind != tokCommand || (t.val != `\limits` && t.val != `\nolimits`) {
+			break
+		}
+		p.next()
+		if base == nil || base.tag != "mo" && !base.fn {
+			p.errorf(t.pos, "%s must follow an operator", t)
+		}
+		if t.val == `\limits` {
+			base.limits = limitsAlways
+		} else {
+			base.limits = limitsNone
+		}
+	}
+
+	var sub, sup *node
+	var primes []*node
+scripts:
+	for {
+		t := p.peek()
+		switch {
+		case t.kind == tokSup:
+			if sup != nil {
+				p.errorf(t.pos, "double superscript")
+			}
+			p.next()
+			sup = p.parseArg(t)
+		case t.kind == tokSub:
+			if sub != nil {
+				p.errorf(t.pos, "double subscript")
+			}
+			p.next()
+			sub = p.parseArg(t)
+		case t.kind == tokChar && t.val == "'":
+			if sup != nil {
+				p.errorf(t.pos, "double superscript")
+			}
+			p.next()
+			primes = append(primes, newText("mo", "′", attr{"mathvariant", "normal"}))
+		default:
+			break scripts
+		}
+	}
+
+	if primes != nil {
+		if sup != nil {
+			primes = appendNode(primes, sup)
+		}
+		sup = group(primes)
+	}
+
+	if base == nil {
+		if sub == nil && sup == nil {
+			return nil
+		}
+		base = newNode("mrow")
+	}
+
+	if sub == nil && sup == nil {
+		if base.fn {
+			return []*node{base, newText("mo", "\u2061")}
+		}
+		return []*node{base}
+	}
+
+	under := base.limits == limitsAlways || base.limits == limitsDisplay && p.style == styleDisplay
+	if base.fn {
+		base = newNode("mrow", base, newText("mo", "\u2061"))
+	}
+
+	n := newNode("", base)
+	switch {
+	case sub != nil && sup != nil:
+		n.tag = "msubsup"
+		if under {
+			n.tag = "munderover"
+		}
+		n.children = append(n.children, sub, sup)
+	case sub != nil:
+		n.tag = "msub"
+		if under {
+			n.tag = "munder"
+		}
+		n.children = append(n.children, sub)
+	default:
+		n.tag = "msup"
+		if under {
+			n.tag = "mover"
+		}
+		n.children = append(n.children, sup)
+	}
+
+	return []*node{n}
+}
+
+// parseArg parses the argument of a command or script: a group or a single
+// token.
+func (p *parser) parseArg(cmd token) *node {
+	t := p.peek()
+	switch t.kind {
+	case tokEOF:
+		p.errorf(t.pos, "missing argument for %s", cmd)
+	case tokClose, tokAlign, tokSup, tokSub:
+		p.errorf(t.pos, "unexpected %s", t)
+	}
+	n := p.parseBase()
+	if n == nil {
+		p.errorf(t.pos, "unexpected %s", t)
+	}
+	return n
+}
+
+// parseArgList is parseArg for commands that keep the nodes of a group,
+// e.g. \phantom.
+func (p *parser) parseArgList(cmd token) []*node {
+	if t := p.peek(); t.kind == tokOpen {
+		p.next()
+		return p.parseGroup()
+	}
+	return []*node{p.parseArg(cmd)}
+}
+
+// parseGroup parses the nodes of a group up to the closing }.
+func (p *parser) parseGroup() []*node {
+	nodes := p.parseExpr("")
+	if end := p.next(); end.kind != tokClose {
+		p.errorf(end.pos, "expected } but got %s", end)
+	}
+	return nodes
+}
+
+// parseBase parses a group, symbol or command.
+func (p *parser) parseBase() *node {
+	t := p.peek()
+	switch t.kind {
+	case tokOpen:
+		p.next()
+		return group(p.parseGroup())
+	case tokChar:
+		if t.val == "'" {
+			// Primes are scripts.
+			return nil
+		}
+		p.next()
+		return p.parseChar(t)
+	case tokCommand:
+		p.next()
+		return p.parseCommand(t)
+	case tokParam:
+		p.errorf(t.pos, "unexpected %s", t)
+	}
+	return nil
+}
+
+func (p *parser) parseChar(t token) *node {
+	c := t.val
+	switch {
+	case len(c) == 1 && c[0] >= '0' && c[0] <= '9':
+		return p.ordinal("mn", c)
+	case len(c) == 1 && isASCIILetter(c[0]):
+		return p.letter(c, false)
+	case c == "~":
+		return newText("mtext", "\u00a0")
+	case c == "," || c == ";":
+		return newText("mo", c, attr{"separator", "true"})
+	case len(c) == 1 && strings.Contains("()[]!?", c):
+		return newText("mo", c, attr{"stretchy", "false"})
+	}
+	if s, found := charOrdinals[c]; found {
+		return p.ordinal("mi", s)
+	}
+	if s, found := charOperators[c]; found {
+		return newText("mo", s)
+	}
+	if name, found := symbolNames[c]; found {
+		return p.parseSymbol(name)
+	}
+	p.unsupportedf(t.pos, "unsupported character %q", c)
+	return nil
+}
+
+// letter returns the identifier for a letter, e.g. x or \alpha, which is
+// italic unless a font is set.
+func (p *parser) letter(s string, greek bool) *node {
+	n := newText("mi", s)
+	// There is no upright lowercase Greek, e.g. in \mathrm{\alpha}.
+	if p.font != "" && p.font != "italic" && !(greek && p.font == "normal") {
+		n.setAttr("mathvariant", p.font)
+	}
+	return n
+}
+
+// ordinal returns an upright symbol, e.g. 1 or \infty, in an mi or mn.
+func (p *parser) ordinal(tag, s string) *node {
+	n := newText(tag, s)
+	variant := p.font
+	if variant == "" {
+		variant = "normal"
+	}
+	if tag == "mi" && variant != "italic" || tag == "mn" && variant != "normal" {
+		n.setAttr("mathvariant", variant)
+	}
+	return n
+}
+
+// parseSymbol returns the symbol with the name, e.g. alpha, or nil if there
+// is no such symbol.
+func (p *parser) parseSymbol(name string) *node {
+	if s, found := letters[name]; found {
+		return p.letter(s, true)
+	}
+	if s, found := ordinals[name]; found {
+		return p.ordinal("mi", s)
+	}
+	if s, found := operators[name]; found {
+		return newText("mo", s)
+	}
+	if s, found := uprightOperators[name]; found {
+		return newText("mo", s, attr{"mathvariant", "normal"})
+	}
+	if s, found := punctuation[name]; found {
+		return newText("mo", s, attr{"separator", "true"})
+	}
+	if s, found := delimiters[name]; found {
+		return newText("mo", s, attr{"stretchy", "false"})
+	}
+	if s, found := bigOperators[name]; found {
+		n := newText("mo", s)
+		n.limits = limitsDisplay
+		return n
+	}
+	if s, found := integrals[name]; found {
+		return newText("mo", s)
+	}
+	if s, found := functions[name]; found {
+		n := newText("mi", s)
+		n.fn = true
+		return n
+	}
+	if s, found := limitFunctions[name]; found {
+		n := newText("mi", s)
+		n.fn = true
+		n.limits = limitsDisplay
+		return n
+	}
+	if s, found := limitOperatorNames[name]; found {
+		n := newText("mi", s, attr{"mathvariant", "normal"})
+		n.fn = true
+		n.limits = limitsDisplay
+		return n
+	}
+	return nil
+}
+
+func (p *parser) parseCommand(t token) *node {
+	name := t.val[1:]
+
+	if n := p.parseSymbol(name); n != nil {
+		return n
+	}
+	if a, found := accents[name]; found {
+		return p.parseAccent(t, a)
+	}
+	if v, found := fontVariants[name]; found {
+		font := p.font
+		p.font = v
+		n := p.parseArg(t)
+		p.font = font
+		return n
+	}
+	if width, found := spaces[name]; found {
+		return space(width)
+	}
+	if f, found := textFonts[name]; found {
+		return row(p.parseText(t, f))
+	}
+	if size, found := delimiterSizes[name]; found {
+		fence := strings.HasSuffix(name, "l") || strings.HasSuffix(name, "r")
+		return newText("mo", p.parseDelim(t), attr{"fence", fmt.Sprint(fence)}, attr{"stretchy", "true"}, attr{"minsize", size}, attr{"maxsize", size})
+	}
+
+	switch name {
+	case " ", "nobreakspace", "space":
+		return newText("mtext", "\u00a0")
+	case "hspace":
+		arg := strings.TrimSpace(p.stringArg(t))
+		em, err := strconv.ParseFloat(strings.TrimSuffix(arg, "em"), 64)
+		if err != nil || !strings.HasSuffix(arg, "em") {
+			p.unsupportedf(t.pos, "unsupported width %q for %s", arg, t)
+		}
+		return space(strconv.FormatFloat(math.Round(em*1e4)/1e4, 'f', -1, 64) + "em")
+	case "frac", "dfrac", "tfrac", "cfrac", "binom", "dbinom", "tbinom":
+		num := p.parseArg(t)
+		den := p.parseArg(t)
+		n := newNode("mfrac", num, den)
+		binom := strings.HasSuffix(name, "binom")
+		if binom {
+			n.withAttrs(attr{"linethickness", "0px"})
+		}
+		// Set the style if it's not the current one.
+		switch name {
+		case "dfrac", "cfrac", "dbinom":
+			if p.style != styleDisplay {
+				n = newNode("mstyle", n).withAttrs(attr{"displaystyle", "true"}, attr{"scriptlevel", "0"})
+			}
+		case "tfrac", "tbinom":
+			if p.style == styleDisplay {
+				n = newNode("mstyle", n).withAttrs(attr{"displaystyle", "false"}, attr{"scriptlevel", "0"})
+			}
+		}
+		if binom {
+			return newNode("mrow", fence("("), n, fence(")"))
+		}
+		return n
+	case "sqrt":
+		var index *node
+		if next := p.peek(); next.kind == tokChar && next.val == "[" {
+			p.next()
+			index = group(p.parseExpr("]"))
+			if end := p.next(); end.val != "]" {
+				p.errorf(end.pos, "expected ] but got %s", end)
+			}
+		}
+		radicand := p.parseArg(t)
+		if index != nil {
+			return newNode("mroot", radicand, index)
+		}
+		return newNode("msqrt", radicand)
+	case "left":
+		open := p.parseDelim(t)
+		p.leftDepth++
+		children := p.parseExpr("")
+		p.leftDepth--
+		right := p.next()
+		if right.val != `\right` {
+			p.errorf(right.pos, `missing \right`)
+		}
+		close := p.parseDelim(right)
+		var nodes []*node
+		if open != "" {
+			nodes = append(nodes, fence(open))
+		}
+		nodes = append(nodes, children...)
+		if close != "" {
+			nodes = append(nodes, fence(close))
+		}
+		return row(nodes)
+	case "middle":
+		if p.leftDepth == 0 {
+			p.errorf(t.pos, `\middle without preceding \left`)
+		}
+		s := p.parseDelim(t)
+		if s == "∣" {
+			s = "|"
+		}
+		return newText("mo", s, attr{"fence", "true"}, attr{"lspace", "0.05em"}, attr{"rspace", "0.05em"})
+	case "operatorname", "operatornamewithlimits":
+		limits := limitsNone
+		if name == "operatornamewithlimits" {
+			limits = limitsDisplay
+		}
+		if next := p.peekRaw(); next.kind == tokChar && next.val == "*" {
+			p.i++
+			limits = limitsDisplay
+		}
+		font := p.font
+		p.font = "normal"
+		nodes := p.parseArgList(t)
+		p.font = font
+		// The name must be text, e.g. not \operatorname{\frac{a}{b}}.
+		var b strings.Builder
+		for _, n := range nodes {
+			switch n.tag {
+			case "mi", "mn", "mtext":
+				b.WriteString(n.text)
+			case "mspace":
+				b.WriteString(" ")
+			case "mo":
+				b.WriteString(strings.NewReplacer("−", "-", "∗", "*").Replace(n.text))
+			default:
+				p.unsupportedf(t.pos, "unsupported argument for %s", t)
+			}
+		}
+		n := newText("mi", b.String(), attr{"mathvariant", "normal"})
+		n.fn = true
+		n.limits = limits
+		return n
+	case "not":
+		// Overlaid on the next symbol by appendNode.
+		return newText("mi", notSlash, attr{"mathvariant", "normal"})
+	case "phantom":
+		return newNode("mphantom", p.parseArgList(t)...)
+	case "textcolor":
+		color := strings.TrimSpace(p.stringArg(t))
+		return newNode("mstyle", p.parseArgList(t)...).withAttrs(attr{"mathcolor", color})
+	case "begin":
+		return p.parseEnv(t)
+	}
+
+	p.unsupportedf(t.pos, "undefined control sequence %s", t)
+	return nil
+}
+
+func (p *parser) parseAccent(t token, a accent) *node {
+	base := p.parseArg(t)
+	mark := newText("mo", a.mark)
+	if a.stretchy {
+		mark.setAttr("stretchy", "true")
+	}
+	switch {
+	case a.braces:
+		n := newNode("mover", base, mark)
+		if a.under {
+			n.tag = "munder"
+		}
+		n.limits = limitsAlways
+		return n
+	case a.under:
+		return newNode("munder", base, mark).withAttrs(attr{"accentunder", "true"})
+	}
+	return newNode("mover", base, mark).withAttrs(attr{"accent", "true"})
+}
+
+// parseDelim parses the delimiter after cmd, e.g. \left, returning "" for
+// the null delimiter.
+func (p *parser) parseDelim(cmd token) string {
+	t := p.next()
+	switch t.kind {
+	case tokChar:
+		switch t.val {
+		case ".":
+			return ""
+		case "|":
+			return "∣"
+		case "(", ")", "[", "]", "/", "<", ">":
+			return t.val
+		}
+	case tokCommand:
+		if s, found := delimiters[t.val[1:]]; found {
+			return s
+		}
+	}
+	p.errorf(t.pos, "missing or invalid delimiter after %s", cmd)
+	return ""
+}
+
+// space returns a space of the width, e.g. 1em.
+func space(width string) *node {
+	if s, found := spaceChars[width]; found {
+		return &node{tag: "mtext", text: s, space: true}
+	}
+	return &node{tag: "mspace", attrs: []attr{{"width", width}}, space: true}
+}
+
+func fence(s string) *node {
+	return newText("mo", s, attr{"fence", "true"})
+}
+
+// stringArg reads the argument of cmd as a string, e.g. for \color.
+func (p *parser) stringArg(cmd token) string {
+	t := p.next()
+	switch t.kind {
+	case tokEOF:
+		p.errorf(t.pos, "missing argument for %s", cmd)
+	case tokChar:
+		return t.val
+	case tokOpen:
+	default:
+		p.errorf(t.pos, "unexpected %s", t)
+	}
+
+	var b strings.Builder
+	for {
+		t := p.peekRaw()
+		if t.kind == tokEOF {
+			p.errorf(t.pos, "missing }")
+		}
+		p.i++
+		switch t.kind {
+		case tokChar, tokSpace:
+			b.WriteString(t.val)
+		case tokClose:
+			return b.String()
+		default:
+			p.unsupportedf(t.pos, "unsupported %s in argument of %s", t, cmd)
+		}
+	}
+}
+
+// parseText parses the argument of cmd, e.g. \text, as text in the font f.
+func (p *parser) parseText(cmd token, f textFont) []*node {
+	t := p.next()
+	switch t.kind {
+	case tokEOF:
+		p.errorf(t.pos, "missing argument for %s", cmd)
+	case tokOpen:
+	default:
+		return p.appendText(nil, t, f)
+	}
+
+	var nodes []*node
+	for {
+		t := p.peekRaw()
+		switch t.kind {
+		case tokEOF:
+			p.errorf(t.pos, "missing }")
+		case tokClose:
+			p.i++
+			return nodes
+		case tokOpen:
+			nodes = appendNode(nodes, row(p.parseText(cmd, f)))
+		default:
+			p.i++
+			nodes = p.appendText(nodes, t, f)
+		}
+	}
+}
+
+// appendText appends the text of t in the font f to nodes.
+func (p *parser) appendText(nodes []*node, t token, f textFont) []*node {
+	text := func(s string) []*node {
+		n := newText("mtext", s)
+		if v := f.variant(); v != "" {
+			n.setAttr("mathvariant", v)
+		}
+		return appendNode(nodes, n)
+	}
+
+	switch t.kind {
+	case tokSpace:
+		return appendNode(nodes, newText("mtext", "\u00a0"))
+	case tokChar:
+		// Handle the ligatures, e.g. -- for an en dash.
+		switch t.val {
+		case "~":
+			return appendNode(nodes, newText("mtext", "\u00a0"))
+		case "$":
+			p.unsupportedf(t.pos, "unsupported math in text")
+		case "-":
+			if p.nextChar("-") {
+				if p.nextChar("-") {
+					return text("—")
+				}
+				return text("–")
+			}
+		case "`":
+			if p.nextChar("`") {
+				return text("“")
+			}
+			return text("‘")
+		case "'":
+			if p.nextChar("'") {
+				return text("”")
+			}
+			return text("’")
+		}
+		return text(t.val)
+	case tokCommand:
+		name := t.val[1:]
+		if s, found := textSymbols[name]; found {
+			return text(s)
+		}
+		if width, found := spaces[name]; found {
+			return appendNode(nodes, space(width))
+		}
+		if g, found := textFonts[name]; found {
+			return appendNode(nodes, row(p.parseText(t, f.with(g))))
+		}
+		if name == " " || name == "nobreakspace" || name == "space" {
+			return appendNode(nodes, newText("mtext", "\u00a0"))
+		}
+		p.unsupportedf(t.pos, "undefined control sequence %s in text", t)
+	}
+	p.errorf(t.pos, "unexpected %s in text", t)
+	return nil
+}
+
+// nextChar consumes the next token if it's the character c.
+func (p *parser) nextChar(c string) bool {
+	if t := p.peekRaw(); t.kind == tokChar && t.val == c {
+		p.i++
+		return true
+	}
+	return false
+}
+
+// environment describes a \begin{...} environment.
+type environment struct {
+	// The fences, e.g. ( and ) for pmatrix.
+	open, close string
+
+	// The alignment of the columns, e.g. center, and their number, if
+	// fixed, e.g. 2 for cases.
+	columnAlign string
+	columns     int
+
+	rowSpacing, columnSpacing string
+
+	// Whether the columns alternate between right and left aligned with no
+	// spacing in between the pairs, e.g. aligned.
+	align bool
+
+	// The style of the cells.
+	style style
+}
+
+var environments = map[string]environment{
+	"matrix":      {columnAlign: "center", rowSpacing: "0.16em", columnSpacing: "1em", style: styleText},
+	"pmatrix":     {open: "(", close: ")", columnAlign: "center", rowSpacing: "0.16em", columnSpacing: "1em", style: styleText},
+	"bmatrix":     {open: "[", close: "]", columnAlign: "center", rowSpacing: "0.16em", columnSpacing: "1em", style: styleText},
+	"Bmatrix":     {open: "{", close: "}", columnAlign: "center", rowSpacing: "0.16em", columnSpacing: "1em", style: styleText},
+	"vmatrix":     {open: "∣", close: "∣", columnAlign: "center", rowSpacing: "0.16em", columnSpacing: "1em", style: styleText},
+	"Vmatrix":     {open: "∥", close: "∥", columnAlign: "center", rowSpacing: "0.16em", columnSpacing: "1em", style: styleText},
+	"smallmatrix": {rowSpacing: "0.1em", columnSpacing: "0.2778em", style: styleScript},
+	"array":       {rowSpacing: "0.16em", columnSpacing: "1em", style: styleText},
+	"cases":       {open: "{", columnAlign: "left", columns: 2, rowSpacing: "0.36em", columnSpacing: "1em", style: styleText},
+	"rcases":      {close: "}", columnAlign: "left", columns: 2, rowSpacing: "0.36em", columnSpacing: "1em", style: styleText},
+	"aligned":     {align: true, rowSpacing: "0.25em", style: styleDisplay},
+	"align*":      {align: true, rowSpacing: "0.25em", style: styleDisplay},
+	"split":       {align: true, rowSpacing: "0.25em", style: styleDisplay},
+	"gathered":    {columnAlign: "center", columns: 1, rowSpacing: "0.25em", columnSpacing: "0em", style: styleDisplay},
+	"gather*":     {columnAlign: "center", columns: 1, rowSpacing: "0.25em", columnSpacing: "0em", style: styleDisplay},
+}
+
+func (p *parser) parseEnv(begin token) *node {
+	name := strings.TrimSpace(p.stringArg(begin))
+	env, found := environments[name]
+	if !found {
+		p.unsupportedf(begin.pos, "unknown environment %q", name)
+	}
+
+	// The array columns, with any lines between them and around them.
+	var columnAlign, columnLines, enclose []string
+	if name == "array" {
+		spec := strings.Replace(p.stringArg(begin), " ", "", -1)
+		prevAlign := false
+		for i, c := range spec {
+			switch c {
+			case 'l', 'c', 'r':
+				if prevAlign {
+					columnLines = append(columnLines, "none")
+				}
+				columnAlign = append(columnAlign, map[rune]string{'l': "left", 'c': "center", 'r': "right"}[c])
+				prevAlign = true
+			case '|', ':':
+				line := "solid"
+				if c == ':' {
+					line = "dashed"
+				}
+				switch {
+				case i == 0:
+					enclose = append(enclose, "top")
+				case i == len(spec)-1:
+					enclose = append(enclose, "bottom")
+				case prevAlign:
+					columnLines = append(columnLines, line)
+					prevAlign = false
+				}
+			default:
+				p.unsupportedf(begin.pos, "unsupported column type %q in array", c)
+			}
+		}
+	}
+
+	s := p.style
+	p.style = env.style
+	p.envDepth++
+
+	var rows [][][]*node
+	var cells [][]*node
+	for done := false; !done; {
+		cells = append(cells, p.parseExpr(""))
+		t := p.next()
+		switch {
+		case t.kind == tokAlign:
+		case t.val == `\\` || t.val == `\cr`:
+			// Skip any row spacing, e.g. \\[2pt].
+			if next := p.peek(); next.kind == tokChar && next.val == "[" {
+				p.textUntil("]")
+			}
+			rows = append(rows, cells)
+			cells = nil
+		case t.val == `\end`:
+			if end := strings.TrimSpace(p.stringArg(t)); end != name {
+				p.errorf(t.pos, `\begin{%s} ended by \end{%s}`, name, end)
+			}
+			// Skip the empty row after a trailing \\.
+			if len(cells) > 1 || len(cells[0]) > 0 {
+				rows = append(rows, cells)
+			}
+			done = true
+		default:
+			p.errorf(t.pos, `missing \end{%s}`, name)
+		}
+	}
+
+	p.envDepth--
+	p.style = s
+
+	var numCols int
+	table := newNode("mtable")
+	for _, cells := range rows {
+		tr := newNode("mtr")
+		for i, cell := range cells {
+			if env.align && i%2 == 1 {
+				// Keep the spacing of a leading relation, e.g. &=.
+				cell = append([]*node{newNode("mrow")}, cell...)
+			}
+			tr.children = append(tr.children, newNode("mtd", env.style.node(group(cell))))
+		}
+		if len(cells) > numCols {
+			numCols = len(cells)
+		}
+		table.children = append(table.children, tr)
+	}
+
+	columnSpacing := env.columnSpacing
+	switch {
+	case env.align:
+		var spacing []string
+		for i := 0; i < numCols; i++ {
+			if i%2 == 0 {
+				columnAlign = append(columnAlign, "right")
+			} else {
+				columnAlign = append(columnAlign, "left")
+			}
+			if i > 0 && i%2 == 1 {
+				spacing = append(spacing, "0em")
+			} else if i > 0 {
+				spacing = append(spacing, "1em")
+			}
+		}
+		columnSpacing = strings.Join(spacing, " ")
+	case env.columnAlign != "":
+		n := env.columns
+		if n == 0 {
+			n = numCols
+		}
+		for i := 0; i < n; i++ {
+			columnAlign = append(columnAlign, env.columnAlign)
+		}
+	}
+
+	table.withAttrs(attr{"rowspacing", env.rowSpacing})
+	if columnAlign != nil {
+		table.withAttrs(attr{"columnalign", strings.Join(columnAlign, " ")})
+	}
+	for _, line := range columnLines {
+		if line != "none" {
+			table.withAttrs(attr{"columnlines", strings.Join(columnLines, " ")})
+			break
+		}
+	}
+	table.withAttrs(attr{"columnspacing", columnSpacing})
+
+	n := table
+	if enclose != nil {
+		n = newNode("menclose", n).withAttrs(attr{"notation", strings.Join(enclose, " ")})
+	}
+	if env.style == styleScript {
+		// The table of smallmatrix is smaller, too.
+		n = newNode("mstyle", n).withAttrs(attr{"scriptlevel", "1"})
+	}
+
+	if env.open == "" && env.close == "" {
+		return n
+	}
+
+	var nodes []*node
+	if env.open != "" {
+		nodes = append(nodes, fence(env.open))
+	}
+	nodes = append(nodes, n)
+	if env.close != "" {
+		nodes = append(nodes, fence(env.close))
+	}
+	return newNode("mrow", nodes...)
+}
+
+// textUntil consumes the tokens up to and including the character end.
+func (p *parser) textUntil(end string) {
+	for {
+		t := p.next()
+		if t.kind == tokEOF {
+			p.errorf(t.pos, "missing %s", end)
+		}
+		if t.kind == tokChar && t.val == end {
+			return
+		}
+	}
+}
+
+// The names of the letters and operators, keyed by their characters, for
+// math typed in Unicode, e.g. α.
+var symbolNames = make(map[string]string)
+
+// The symbols typeset as text when typed in Unicode, e.g. †.
+const textSymbolChars = "†‡⋄⋆△▹▽◃⨿∥⊥′"
+
+func init() {
+	for _, m := range []map[string]string{letters, operators, uprightOperators, bigOperators, integrals} {
+		for name, s := range m {
+			if s[0] >= utf8.RuneSelf && !strings.Contains(textSymbolChars, s) {
+				symbolNames[s] = name
+			}
+		}
+	}
+}
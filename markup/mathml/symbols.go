@@ -0,0 +1,539 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mathml
+
+// The symbols, keyed by the command names without the backslash.
+
+// Letters, which are italic.
+var letters = map[string]string{
+	"alpha":      "α",
+	"beta":       "β",
+	"gamma":      "γ",
+	"delta":      "δ",
+	"epsilon":    "ϵ",
+	"varepsilon": "ε",
+	"zeta":       "ζ",
+	"eta":        "η",
+	"theta":      "θ",
+	"vartheta":   "ϑ",
+	"iota":       "ι",
+	"kappa":      "κ",
+	"lambda":     "λ",
+	"mu":         "μ",
+	"nu":         "ν",
+	"xi":         "ξ",
+	"omicron":    "ο",
+	"pi":         "π",
+	"varpi":      "ϖ",
+	"rho":        "ρ",
+	"varrho":     "ϱ",
+	"sigma":      "σ",
+	"varsigma":   "ς",
+	"tau":        "τ",
+	"upsilon":    "υ",
+	"phi":        "ϕ",
+	"varphi":     "φ",
+	"chi":        "χ",
+	"psi":        "ψ",
+	"omega":      "ω",
+}
+
+// Ordinary symbols, which are upright.
+var ordinals = map[string]string{
+	"varkappa": "ϰ",
+	"Gamma":    "Γ",
+	"Delta":    "Δ",
+	"Theta":    "Θ",
+	"Lambda":   "Λ",
+	"Xi":       "Ξ",
+	"Pi":       "Π",
+	"Sigma":    "Σ",
+	"Upsilon":  "Υ",
+	"Phi":      "Φ",
+	"Psi":      "Ψ",
+	"Omega":    "Ω",
+
+	"aleph":       "ℵ",
+	"beth":        "ℶ",
+	"ell":         "ℓ",
+	"hbar":        "ℏ",
+	"hslash":      "ℏ",
+	"imath":       "ı",
+	"jmath":       "ȷ",
+	"Re":          "ℜ",
+	"Im":          "ℑ",
+	"wp":          "℘",
+	"infty":       "∞",
+	"partial":     "∂",
+	"nabla":       "∇",
+	"emptyset":    "∅",
+	"varnothing":  "∅",
+	"forall":      "∀",
+	"exists":      "∃",
+	"nexists":     "∄",
+	"top":         "⊤",
+	"bot":         "⊥",
+	"angle":       "∠",
+	"triangle":    "△",
+	"complement":  "∁",
+	"clubsuit":    "♣",
+	"diamondsuit": "♢",
+	"heartsuit":   "♡",
+	"spadesuit":   "♠",
+	"flat":        "♭",
+	"natural":     "♮",
+	"sharp":       "♯",
+	"checkmark":   "✓",
+	"neg":         "¬",
+	"lnot":        "¬",
+	"surd":        "√",
+	"vert":        "∣",
+	"Vert":        "∥",
+	"|":           "∥",
+	"backslash":   "\\",
+	"#":           "#",
+	"$":           "$",
+	"%":           "%",
+	"&":           "&",
+	"_":           "_",
+}
+
+var operators = map[string]string{
+	// Binary operators.
+	"pm":              "±",
+	"mp":              "∓",
+	"times":           "×",
+	"div":             "÷",
+	"cdot":            "⋅",
+	"ast":             "∗",
+	"star":            "⋆",
+	"circ":            "∘",
+	"bullet":          "∙",
+	"oplus":           "⊕",
+	"ominus":          "⊖",
+	"otimes":          "⊗",
+	"oslash":          "⊘",
+	"odot":            "⊙",
+	"cup":             "∪",
+	"cap":             "∩",
+	"sqcup":           "⊔",
+	"sqcap":           "⊓",
+	"uplus":           "⊎",
+	"vee":             "∨",
+	"lor":             "∨",
+	"wedge":           "∧",
+	"land":            "∧",
+	"setminus":        "∖",
+	"smallsetminus":   "∖",
+	"wr":              "≀",
+	"diamond":         "⋄",
+	"bigtriangleup":   "△",
+	"bigtriangledown": "▽",
+	"triangleleft":    "◃",
+	"triangleright":   "▹",
+	"dagger":          "†",
+	"ddagger":         "‡",
+	"amalg":           "⨿",
+	"ldots":           "…",
+	"dots":            "…",
+	"dotsc":           "…",
+	"dotso":           "…",
+	"cdots":           "⋯",
+	"dotsb":           "⋯",
+	"dotsm":           "⋯",
+	"ddots":           "⋱",
+
+	// Relations.
+	"leq":               "≤",
+	"le":                "≤",
+	"geq":               "≥",
+	"ge":                "≥",
+	"leqslant":          "⩽",
+	"geqslant":          "⩾",
+	"lt":                "<",
+	"gt":                ">",
+	"nless":             "≮",
+	"ngtr":              "≯",
+	"nleq":              "≰",
+	"ngeq":              "≱",
+	"equiv":             "≡",
+	"approx":            "≈",
+	"approxeq":          "≊",
+	"sim":               "∼",
+	"simeq":             "≃",
+	"cong":              "≅",
+	"ncong":             "≆",
+	"propto":            "∝",
+	"ll":                "≪",
+	"gg":                "≫",
+	"subset":            "⊂",
+	"supset":            "⊃",
+	"subseteq":          "⊆",
+	"supseteq":          "⊇",
+	"subsetneq":         "⊊",
+	"supsetneq":         "⊋",
+	"nsubseteq":         "⊈",
+	"nsupseteq":         "⊉",
+	"sqsubseteq":        "⊑",
+	"sqsupseteq":        "⊒",
+	"in":                "∈",
+	"ni":                "∋",
+	"mid":               "∣",
+	"nmid":              "∤",
+	"parallel":          "∥",
+	"nparallel":         "∦",
+	"perp":              "⊥",
+	"models":            "⊨",
+	"vdash":             "⊢",
+	"dashv":             "⊣",
+	"prec":              "≺",
+	"succ":              "≻",
+	"preceq":            "⪯",
+	"succeq":            "⪰",
+	"asymp":             "≍",
+	"doteq":             "≐",
+	"triangleq":         "≜",
+	"therefore":         "∴",
+	"because":           "∵",
+	"to":                "→",
+	"rightarrow":        "→",
+	"gets":              "←",
+	"leftarrow":         "←",
+	"leftrightarrow":    "↔",
+	"Rightarrow":        "⇒",
+	"Leftarrow":         "⇐",
+	"Leftrightarrow":    "⇔",
+	"mapsto":            "↦",
+	"longrightarrow":    "⟶",
+	"longleftarrow":     "⟵",
+	"longmapsto":        "⟼",
+	"Longrightarrow":    "⟹",
+	"Longleftarrow":     "⟸",
+	"hookrightarrow":    "↪",
+	"hookleftarrow":     "↩",
+	"nearrow":           "↗",
+	"searrow":           "↘",
+	"swarrow":           "↙",
+	"nwarrow":           "↖",
+	"leadsto":           "⇝",
+	"rightleftharpoons": "⇌",
+	"uparrow":           "↑",
+	"downarrow":         "↓",
+	"updownarrow":       "↕",
+	"Uparrow":           "⇑",
+	"Downarrow":         "⇓",
+	"Updownarrow":       "⇕",
+}
+
+// Operators which are upright, as they are in KaTeX.
+var uprightOperators = map[string]string{
+	"prime": "′",
+	"ne":    "≠",
+	"neq":   "≠",
+	"notin": "∉",
+}
+
+var punctuation = map[string]string{
+	"cdotp": "⋅",
+	"ldotp": ".",
+}
+
+// The delimiters, which may also follow \left, \right and \big etc.
+var delimiters = map[string]string{
+	"{":           "{",
+	"}":           "}",
+	"|":           "∥",
+	"lbrace":      "{",
+	"rbrace":      "}",
+	"lbrack":      "[",
+	"rbrack":      "]",
+	"langle":      "⟨",
+	"rangle":      "⟩",
+	"vert":        "∣",
+	"lvert":       "∣",
+	"rvert":       "∣",
+	"Vert":        "∥",
+	"lVert":       "∥",
+	"rVert":       "∥",
+	"lfloor":      "⌊",
+	"rfloor":      "⌋",
+	"lceil":       "⌈",
+	"rceil":       "⌉",
+	"lgroup":      "⟮",
+	"rgroup":      "⟯",
+	"backslash":   "\\",
+	"uparrow":     "↑",
+	"downarrow":   "↓",
+	"updownarrow": "↕",
+	"Uparrow":     "⇑",
+	"Downarrow":   "⇓",
+	"Updownarrow": "⇕",
+}
+
+// The sizes of \big etc.
+var delimiterSizes = map[string]string{
+	"big":   "1.2em",
+	"bigl":  "1.2em",
+	"bigm":  "1.2em",
+	"bigr":  "1.2em",
+	"Big":   "1.8em",
+	"Bigl":  "1.8em",
+	"Bigm":  "1.8em",
+	"Bigr":  "1.8em",
+	"bigg":  "2.4em",
+	"biggl": "2.4em",
+	"biggm": "2.4em",
+	"biggr": "2.4em",
+	"Bigg":  "3em",
+	"Biggl": "3em",
+	"Biggm": "3em",
+	"Biggr": "3em",
+}
+
+// Operators with limits in display style.
+var bigOperators = map[string]string{
+	"sum":       "∑",
+	"prod":      "∏",
+	"coprod":    "∐",
+	"bigcup":    "⋃",
+	"bigcap":    "⋂",
+	"bigvee":    "⋁",
+	"bigwedge":  "⋀",
+	"bigoplus":  "⨁",
+	"bigotimes": "⨂",
+	"bigodot":   "⨀",
+	"biguplus":  "⨄",
+	"bigsqcup":  "⨆",
+}
+
+var integrals = map[string]string{
+	"int":    "∫",
+	"iint":   "∬",
+	"iiint":  "∭",
+	"oint":   "∮",
+	"oiint":  "∯",
+	"oiiint": "∰",
+}
+
+var functions = map[string]string{
+	"arccos": "arccos",
+	"arcsin": "arcsin",
+	"arctan": "arctan",
+	"arg":    "arg",
+	"cos":    "cos",
+	"cosh":   "cosh",
+	"cot":    "cot",
+	"coth":   "coth",
+	"csc":    "csc",
+	"deg":    "deg",
+	"dim":    "dim",
+	"exp":    "exp",
+	"hom":    "hom",
+	"ker":    "ker",
+	"lg":     "lg",
+	"ln":     "ln",
+	"log":    "log",
+	"sec":    "sec",
+	"sin":    "sin",
+	"sinh":   "sinh",
+	"tan":    "tan",
+	"tanh":   "tanh",
+}
+
+// Functions with limits in display style.
+var limitFunctions = map[string]string{
+	"det": "det",
+	"gcd": "gcd",
+	"inf": "inf",
+	"lim": "lim",
+	"max": "max",
+	"min": "min",
+	"Pr":  "Pr",
+	"sup": "sup",
+}
+
+// Functions with limits in display style, which are upright as they are
+// defined with \operatorname* in KaTeX.
+var limitOperatorNames = map[string]string{
+	"liminf": "lim\u2009inf",
+	"limsup": "lim\u2009sup",
+}
+
+type accent struct {
+	mark     string
+	stretchy bool
+
+	// Whether the mark goes below.
+	under bool
+
+	// Set for \overbrace and \underbrace, whose scripts always go above or
+	// below.
+	braces bool
+}
+
+var accents = map[string]accent{
+	"hat":                {mark: "^"},
+	"widehat":            {mark: "^", stretchy: true},
+	"check":              {mark: "ˇ"},
+	"widecheck":          {mark: "ˇ", stretchy: true},
+	"tilde":              {mark: "~"},
+	"widetilde":          {mark: "~", stretchy: true},
+	"acute":              {mark: "ˊ"},
+	"grave":              {mark: "ˋ"},
+	"dot":                {mark: "˙"},
+	"ddot":               {mark: "¨"},
+	"breve":              {mark: "˘"},
+	"bar":                {mark: "ˉ"},
+	"vec":                {mark: "\u20d7"},
+	"mathring":           {mark: "˚"},
+	"overline":           {mark: "‾", stretchy: true},
+	"underline":          {mark: "‾", stretchy: true, under: true},
+	"overrightarrow":     {mark: "→", stretchy: true},
+	"overleftarrow":      {mark: "←", stretchy: true},
+	"overleftrightarrow": {mark: "↔", stretchy: true},
+	"overbrace":          {mark: "⏞", stretchy: true, braces: true},
+	"underbrace":         {mark: "⏟", stretchy: true, under: true, braces: true},
+}
+
+var fontVariants = map[string]string{
+	"mathrm":   "normal",
+	"mathbf":   "bold",
+	"mathit":   "italic",
+	"mathbb":   "double-struck",
+	"mathcal":  "script",
+	"mathscr":  "script",
+	"mathfrak": "fraktur",
+	"mathsf":   "sans-serif",
+	"mathtt":   "monospace",
+}
+
+// textFont is the font in text, e.g. in \textbf{\textit{...}}.
+type textFont struct {
+	family, weight, shape string
+}
+
+// variant returns the mathvariant of f.
+func (f textFont) variant() string {
+	bold, italic := f.weight == "bf", f.shape == "it"
+	switch {
+	case f.family == "tt":
+		return "monospace"
+	case f.family == "sf" && bold && italic:
+		return "sans-serif-bold-italic"
+	case f.family == "sf" && italic:
+		return "sans-serif-italic"
+	case f.family == "sf" && bold:
+		return "bold-sans-serif"
+	case f.family == "sf":
+		return "sans-serif"
+	case bold && italic:
+		return "bold-italic"
+	case italic:
+		return "italic"
+	case bold:
+		return "bold"
+	}
+	return ""
+}
+
+// The text commands, with the parts of the font they set.
+var textFonts = map[string]textFont{
+	"text":       {},
+	"textnormal": {family: "rm", weight: "md", shape: "up"},
+	"textrm":     {family: "rm"},
+	"textsf":     {family: "sf"},
+	"texttt":     {family: "tt"},
+	"textmd":     {weight: "md"},
+	"textbf":     {weight: "bf"},
+	"textup":     {shape: "up"},
+	"textit":     {shape: "it"},
+}
+
+var spaces = map[string]string{
+	",":             "0.1667em",
+	"thinspace":     "0.1667em",
+	":":             "0.2222em",
+	">":             "0.2222em",
+	"medspace":      "0.2222em",
+	";":             "0.2778em",
+	"thickspace":    "0.2778em",
+	"!":             "-0.1667em",
+	"negthinspace":  "-0.1667em",
+	"negmedspace":   "-0.2222em",
+	"negthickspace": "-0.2778em",
+	"enspace":       "0.5em",
+	"quad":          "1em",
+	"qquad":         "2em",
+}
+
+// The spaces written as characters, keyed by width.
+var spaceChars = map[string]string{
+	"0.0556em":  "\u200a",
+	"0.1667em":  "\u2009",
+	"0.2222em":  "\u2005",
+	"0.2778em":  "\u2005\u200a",
+	"-0.0556em": "\u200a\u2063",
+	"-0.1667em": "\u2009\u2063",
+	"-0.2222em": "\u205f\u2063",
+	"-0.2778em": "\u2005\u2063",
+}
+
+// The symbols allowed in text, e.g. in \text.
+var textSymbols = map[string]string{
+	"{":             "{",
+	"}":             "}",
+	"#":             "#",
+	"$":             "$",
+	"%":             "%",
+	"&":             "&",
+	"_":             "_",
+	"textbackslash": "\\",
+	"textendash":    "–",
+	"textemdash":    "—",
+}
+
+// The characters typeset as ordinary symbols.
+var charOrdinals = map[string]string{
+	"/": "/",
+	".": ".",
+	"@": "@",
+	`"`: `"`,
+	"`": "‘",
+	"|": "∣",
+}
+
+// The characters typeset as operators.
+var charOperators = map[string]string{
+	"+": "+",
+	"-": "−",
+	"*": "∗",
+	"=": "=",
+	"<": "<",
+	">": ">",
+	":": ":",
+}
+
+// with returns f with the parts set in g.
+func (f textFont) with(g textFont) textFont {
+	if g.family != "" {
+		f.family = g.family
+	}
+	if g.weight != "" {
+		f.weight = g.weight
+	}
+	if g.shape != "" {
+		f.shape = g.shape
+	}
+	return f
+}
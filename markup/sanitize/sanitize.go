@@ -0,0 +1,275 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sanitize provides allow-list based HTML sanitization of rendered content.
+package sanitize
+
+import (
+	"bytes"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/html"
+)
+
+// DefaultPolicyName is the name of the built-in policy.
+const DefaultPolicyName = "default"
+
+// DefaultPolicy is the built-in policy. It allows the elements and attributes
+// commonly produced by the Markdown renderers, and links to http, https and
+// mailto URLs.
+var DefaultPolicy = Policy{
+	Elements: []string{
+		"a", "abbr", "b", "blockquote", "br", "caption", "cite", "code", "dd", "del",
+		"details", "dfn", "div", "dl", "dt", "em", "figcaption", "figure",
+		"h1", "h2", "h3", "h4", "h5", "h6", "hr", "i", "img", "ins", "kbd", "li",
+		"mark", "ol", "p", "pre", "q", "s", "samp", "small", "span", "strong",
+		"sub", "summary", "sup", "table", "tbody", "td", "tfoot", "th", "thead",
+		"tr", "u", "ul",
+	},
+	Attributes: map[string][]string{
+		"*":       {"class", "dir", "id", "lang", "title"},
+		"a":       {"href", "rel"},
+		"details": {"open"},
+		"img":     {"alt", "height", "src", "width"},
+		"ol":      {"start"},
+		"td":      {"align", "colspan", "rowspan"},
+		"th":      {"align", "colspan", "rowspan"},
+	},
+	URLSchemes: []string{"http", "https", "mailto"},
+}
+
+// Config configures the HTML sanitization policies.
+type Config struct {
+	// The named policies. A page selects a policy with the sanitize front
+	// matter key, e.g. set for a whole section using cascade.
+	// A policy named default replaces the built-in default policy.
+	Policies map[string]Policy
+}
+
+// Policy is an allow-list of HTML elements and attributes.
+type Policy struct {
+	// The elements to keep. The tags of other elements are removed, but
+	// their text content is kept, except for elements such as script and
+	// style, which are removed entirely.
+	Elements []string
+
+	// The attributes to keep, keyed by element name. Use "*" for attributes
+	// allowed on all elements.
+	Attributes map[string][]string
+
+	// The URL schemes allowed in URL attributes (e.g. href and src).
+	// Relative URLs are always allowed. Defaults to http, https and mailto.
+	URLSchemes []string
+}
+
+// Elements that are removed with their content when not allowed.
+var dropContentElements = map[string]bool{
+	"embed":    true,
+	"iframe":   true,
+	"noscript": true,
+	"object":   true,
+	"script":   true,
+	"style":    true,
+	"svg":      true,
+	"template": true,
+	"textarea": true,
+	"title":    true,
+}
+
+// Attributes holding URLs.
+var urlAttributes = map[string]bool{
+	"action":     true,
+	"background": true,
+	"cite":       true,
+	"formaction": true,
+	"href":       true,
+	"longdesc":   true,
+	"poster":     true,
+	"src":        true,
+	"xlink:href": true,
+}
+
+// Sanitizer sanitizes HTML using a set of compiled policies.
+type Sanitizer struct {
+	policies map[string]*policy
+}
+
+// New creates a new Sanitizer for the given config.
+func New(cfg Config) (*Sanitizer, error) {
+	s := &Sanitizer{
+		policies: map[string]*policy{
+			DefaultPolicyName: compile(DefaultPolicy),
+		},
+	}
+
+	for name, p := range cfg.Policies {
+		if len(p.Elements) == 0 {
+			return nil, errors.Errorf("sanitize policy %q: no elements allowed", name)
+		}
+		s.policies[strings.ToLower(name)] = compile(p)
+	}
+
+	return s, nil
+}
+
+// Sanitize sanitizes b using the policy with the given name.
+func (s *Sanitizer) Sanitize(policyName string, b []byte) ([]byte, error) {
+	p, found := s.policies[strings.ToLower(policyName)]
+	if !found {
+		return nil, errors.Errorf("sanitize policy %q not found", policyName)
+	}
+	return p.sanitize(b), nil
+}
+
+type policy struct {
+	elements   map[string]bool
+	attributes map[string]map[string]bool
+	urlSchemes map[string]bool
+}
+
+func compile(p Policy) *policy {
+	pp := &policy{
+		elements:   make(map[string]bool),
+		attributes: make(map[string]map[string]bool),
+		urlSchemes: make(map[string]bool),
+	}
+
+	for _, el := range p.Elements {
+		pp.elements[strings.ToLower(el)] = true
+	}
+
+	for el, attrs := range p.Attributes {
+		el = strings.ToLower(el)
+		m := make(map[string]bool)
+		for _, attr := range attrs {
+			m[strings.ToLower(attr)] = true
+		}
+		pp.attributes[el] = m
+	}
+
+	urlSchemes := p.URLSchemes
+	if len(urlSchemes) == 0 {
+		urlSchemes = DefaultPolicy.URLSchemes
+	}
+
+	for _, scheme := range urlSchemes {
+		pp.urlSchemes[strings.ToLower(scheme)] = true
+	}
+
+	return pp
+}
+
+var textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+func (p *policy) sanitize(b []byte) []byte {
+	var (
+		buf       bytes.Buffer
+		skipTag   string
+		skipDepth int
+	)
+
+	buf.Grow(len(b))
+
+	z := html.NewTokenizer(bytes.NewReader(b))
+
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			// io.EOF or a read error; we're reading from memory.
+			break
+		}
+
+		t := z.Token()
+
+		if skipDepth > 0 {
+			if t.Data == skipTag {
+				switch tt {
+				case html.StartTagToken:
+					skipDepth++
+				case html.EndTagToken:
+					skipDepth--
+				}
+			}
+			continue
+		}
+
+		// Comments and doctypes are always removed.
+		switch tt {
+		case html.TextToken:
+			textEscaper.WriteString(&buf, t.Data)
+		case html.StartTagToken, html.SelfClosingTagToken:
+			if !p.elements[t.Data] {
+				if tt == html.StartTagToken && dropContentElements[t.Data] {
+					skipTag = t.Data
+					skipDepth = 1
+				}
+				continue
+			}
+			buf.WriteByte('<')
+			buf.WriteString(t.Data)
+			for _, attr := range t.Attr {
+				if !p.allowAttr(t.Data, attr) {
+					continue
+				}
+				buf.WriteByte(' ')
+				buf.WriteString(attr.Key)
+				buf.WriteString(`="`)
+				buf.WriteString(html.EscapeString(attr.Val))
+				buf.WriteByte('"')
+			}
+			if tt == html.SelfClosingTagToken {
+				buf.WriteString(" /")
+			}
+			buf.WriteByte('>')
+		case html.EndTagToken:
+			if p.elements[t.Data] {
+				buf.WriteString("</")
+				buf.WriteString(t.Data)
+				buf.WriteByte('>')
+			}
+		}
+	}
+
+	return buf.Bytes()
+}
+
+func (p *policy) allowAttr(el string, attr html.Attribute) bool {
+	if attr.Namespace != "" {
+		return false
+	}
+
+	key := strings.ToLower(attr.Key)
+
+	if !p.attributes[el][key] && !p.attributes["*"][key] {
+		return false
+	}
+
+	if urlAttributes[key] {
+		return p.allowURL(attr.Val)
+	}
+
+	return true
+}
+
+func (p *policy) allowURL(s string) bool {
+	u, err := url.Parse(strings.TrimSpace(s))
+	if err != nil {
+		return false
+	}
+	if u.Scheme == "" {
+		return true
+	}
+	return p.urlSchemes[strings.ToLower(u.Scheme)]
+}
@@ -0,0 +1,61 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sanitize
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestSanitize(t *testing.T) {
+	c := qt.New(t)
+
+	s, err := New(Config{
+		Policies: map[string]Policy{
+			"Strict": {
+				Elements:   []string{"p", "a"},
+				Attributes: map[string][]string{"a": {"href"}},
+				URLSchemes: []string{"https"},
+			},
+		},
+	})
+	c.Assert(err, qt.IsNil)
+
+	for _, test := range []struct {
+		policy string
+		in     string
+		expect string
+	}{
+		{"default", `<p class="c" onclick="x()">Hello <b>World</b></p>`, `<p class="c">Hello <b>World</b></p>`},
+		{"default", `<p>A<script>alert(1)</script>B<style>p{}</style></p>`, `<p>AB</p>`},
+		{"default", `<iframe src="https://example.org"></iframe>After`, `After`},
+		{"default", `<object><object>x</object>y</object>After`, `After`},
+		{"default", `<a href="javascript:alert(1)">J</a><a href="/rel/">R</a>`, `<a>J</a><a href="/rel/">R</a>`},
+		{"default", `<a href="java&#x09;script:alert(1)">J</a>`, `<a>J</a>`},
+		{"default", `<img src="https://example.org/a.png" alt="A &quot;B&quot;"/><!-- comment -->`, `<img src="https://example.org/a.png" alt="A &#34;B&#34;" />`},
+		{"default", `<p>1 &lt; 2 &amp; 3</p>`, `<p>1 &lt; 2 &amp; 3</p>`},
+		{"strict", `<p><a href="http://example.org" title="T">A</a><a href="https://example.org">B</a><em>C</em></p>`, `<p><a>A</a><a href="https://example.org">B</a>C</p>`},
+	} {
+		b, err := s.Sanitize(test.policy, []byte(test.in))
+		c.Assert(err, qt.IsNil)
+		c.Assert(string(b), qt.Equals, test.expect, qt.Commentf(test.in))
+	}
+
+	_, err = s.Sanitize("foo", []byte("<p>foo</p>"))
+	c.Assert(err, qt.ErrorMatches, `sanitize policy "foo" not found`)
+
+	_, err = New(Config{Policies: map[string]Policy{"empty": {}}})
+	c.Assert(err, qt.Not(qt.IsNil))
+}
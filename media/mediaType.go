@@ -191,6 +191,10 @@ var (
 	WEBMType = newMediaType("video", "webm", []string{"webm"})
 	GPPType  = newMediaType("video", "3gpp", []string{"3gpp", "3gp"})
 
+	// Common document types
+	PDFType  = newMediaType("application", "pdf", []string{"pdf"})
+	EPUBType = newMediaTypeWithMimeSuffix("application", "epub", "zip", []string{"epub"})
+
 	OctetType = newMediaType("application", "octet-stream", nil)
 )
 
@@ -224,6 +228,8 @@ var DefaultTypes = Types{
 	OGGType,
 	WEBMType,
 	GPPType,
+	PDFType,
+	EPUBType,
 }
 
 func init() {
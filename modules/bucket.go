@@ -0,0 +1,289 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modules
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/gohugoio/hugo/config/netpolicy"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"golang.org/x/sync/errgroup"
+
+	"gocloud.dev/blob"
+	"gocloud.dev/blob/fileblob"
+	"gocloud.dev/blob/gcsblob"
+	"gocloud.dev/blob/s3blob"
+	"gocloud.dev/gcp"
+)
+
+// The URL schemes supported in mount sources, e.g.
+// "s3://mybucket?region=eu-west-1&prefix=media/".
+var bucketSchemes = map[string]bool{
+	"s3":   true,
+	"gs":   true,
+	"file": true,
+}
+
+const (
+	bucketsCacheDir      = "_buckets"
+	bucketManifestName   = ".hugo_bucket.json"
+	bucketDownloadWorker = 10
+)
+
+// isBucketURL reports whether the mount source s is an object storage bucket.
+func isBucketURL(s string) bool {
+	i := strings.Index(s, "://")
+	return i > 0 && bucketSchemes[s[:i]]
+}
+
+// bucketManifest maps the object keys mirrored to their ETag.
+type bucketManifest map[string]string
+
+// syncBucket mirrors the objects in the bucket at bucketURL to a directory
+// below cacheDir in fs and returns that directory. Objects are only
+// downloaded when their ETag (the content MD5 if the provider lists it,
+// the modification time and size if not) changed since the last sync,
+// and objects deleted from the bucket are removed.
+//
+// If the bucket cannot be listed, e.g. when offline, any existing mirror is
+// used as is, with a warning.
+func (c *collector) syncBucket(bucketURL string) (string, error) {
+	if c.ccfg.CacheDir == "" {
+		return "", errors.New("no cache dir configured for bucket mounts")
+	}
+
+	u, err := url.Parse(bucketURL)
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(c.ccfg.CacheDir, bucketsCacheDir, fmt.Sprintf("%s_%x", u.Scheme, md5.Sum([]byte(bucketURL))))
+	manifestFilename := filepath.Join(dir, bucketManifestName)
+
+	manifest := make(bucketManifest)
+	if b, err := afero.ReadFile(c.fs, manifestFilename); err == nil {
+		if err := json.Unmarshal(b, &manifest); err != nil {
+			manifest = make(bucketManifest)
+		}
+	}
+
+	ctx := context.Background()
+
+	bucket, err := c.openBucket(ctx, bucketURL)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to open bucket %q", bucketURL)
+	}
+	defer bucket.Close()
+
+	objects := make(bucketManifest)
+	iter := bucket.List(nil)
+	for {
+		obj, err := iter.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if _, statErr := c.fs.Stat(manifestFilename); statErr == nil {
+				c.logger.Warnf("failed to list bucket %q, using the cached copy: %s", bucketURL, err)
+				return dir, nil
+			}
+			return "", errors.Wrapf(err, "failed to list bucket %q", bucketURL)
+		}
+		if obj.IsDir || strings.HasSuffix(obj.Key, "/") || obj.Key == bucketManifestName {
+			continue
+		}
+		objects[obj.Key] = objectETag(obj)
+	}
+
+	var (
+		mu sync.Mutex
+		g  errgroup.Group
+		// Limits the number of concurrent downloads.
+		sem = make(chan struct{}, bucketDownloadWorker)
+	)
+
+	for key, etag := range objects {
+		key, etag := key, etag
+		filename, err := mirrorFilename(dir, key)
+		if err != nil {
+			return "", err
+		}
+
+		if manifest[key] == etag {
+			if _, err := c.fs.Stat(filename); err == nil {
+				continue
+			}
+		}
+
+		g.Go(func() error {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			if err := c.downloadObject(ctx, bucket, key, filename); err != nil {
+				return errors.Wrapf(err, "failed to download %q from bucket %q", key, bucketURL)
+			}
+
+			mu.Lock()
+			manifest[key] = etag
+			mu.Unlock()
+
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return "", err
+	}
+
+	for key := range manifest {
+		if _, found := objects[key]; found {
+			continue
+		}
+		delete(manifest, key)
+		if filename, err := mirrorFilename(dir, key); err == nil {
+			if err := c.fs.Remove(filename); err != nil && !os.IsNotExist(err) {
+				return "", err
+			}
+		}
+	}
+
+	b, err := json.Marshal(manifest)
+	if err != nil {
+		return "", err
+	}
+
+	if err := c.fs.MkdirAll(dir, 0777); err != nil {
+		return "", err
+	}
+
+	return dir, afero.WriteFile(c.fs, manifestFilename, b, 0666)
+}
+
+// openBucket opens the bucket at bucketURL with the network policy applied
+// to the requests to the cloud provider.
+func (c *collector) openBucket(ctx context.Context, bucketURL string) (*blob.Bucket, error) {
+	policy := c.ccfg.NetworkPolicy
+	if policy == nil {
+		return blob.OpenBucket(ctx, bucketURL)
+	}
+
+	mux := new(blob.URLMux)
+	mux.RegisterBucket(fileblob.Scheme, &fileblob.URLOpener{})
+	mux.RegisterBucket(s3blob.Scheme, &policyS3Opener{policy: policy})
+	mux.RegisterBucket(gcsblob.Scheme, &policyGCSOpener{policy: policy})
+
+	return mux.OpenBucket(ctx, bucketURL)
+}
+
+// policyS3Opener opens S3 buckets with the default AWS session and an HTTP
+// client enforcing policy.
+type policyS3Opener struct {
+	policy *netpolicy.Policy
+}
+
+func (o *policyS3Opener) OpenBucketURL(ctx context.Context, u *url.URL) (*blob.Bucket, error) {
+	sess, err := session.NewSessionWithOptions(session.Options{
+		SharedConfigState: session.SharedConfigEnable,
+		Config:            aws.Config{HTTPClient: o.policy.Client(&http.Client{})},
+	})
+	if err != nil {
+		return nil, err
+	}
+	opener := &s3blob.URLOpener{ConfigProvider: sess}
+	return opener.OpenBucketURL(ctx, u)
+}
+
+// policyGCSOpener opens Google Cloud Storage buckets with the default
+// credentials and an HTTP client enforcing policy.
+type policyGCSOpener struct {
+	policy *netpolicy.Policy
+}
+
+func (o *policyGCSOpener) OpenBucketURL(ctx context.Context, u *url.URL) (*blob.Bucket, error) {
+	creds, err := gcp.DefaultCredentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+	client, err := gcp.NewHTTPClient(o.policy.Transport(gcp.DefaultTransport()), gcp.CredentialsTokenSource(creds))
+	if err != nil {
+		return nil, err
+	}
+	opener := &gcsblob.URLOpener{Client: client}
+	return opener.OpenBucketURL(ctx, u)
+}
+
+func (c *collector) downloadObject(ctx context.Context, bucket *blob.Bucket, key, filename string) error {
+	r, err := bucket.NewReader(ctx, key, nil)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	if err := c.fs.MkdirAll(filepath.Dir(filename), 0777); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first, so an interrupted download does not
+	// leave a partial file behind.
+	tmp := filename + ".tmp"
+	f, err := c.fs.Create(tmp)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		c.fs.Remove(tmp)
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return c.fs.Rename(tmp, filename)
+}
+
+// objectETag returns the content MD5, if listed, or else a value derived
+// from the modification time and size of obj.
+func objectETag(obj *blob.ListObject) string {
+	if len(obj.MD5) > 0 {
+		return hex.EncodeToString(obj.MD5)
+	}
+	return fmt.Sprintf("%d-%d", obj.ModTime.UnixNano(), obj.Size)
+}
+
+// bucketFilename returns the filename in dir to mirror the object with the
+// given key to.
+func mirrorFilename(dir, key string) (string, error) {
+	filename := filepath.Join(dir, filepath.FromSlash(key))
+	if !strings.HasPrefix(filename, dir+string(os.PathSeparator)) {
+		return "", errors.Errorf("invalid object key %q", key)
+	}
+	return filename, nil
+}
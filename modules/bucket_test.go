@@ -0,0 +1,91 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modules
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestIsBucketURL(t *testing.T) {
+	c := qt.New(t)
+
+	c.Assert(isBucketURL("s3://mybucket?region=eu-west-1"), qt.IsTrue)
+	c.Assert(isBucketURL("gs://mybucket"), qt.IsTrue)
+	c.Assert(isBucketURL("file:///my/dir"), qt.IsTrue)
+	c.Assert(isBucketURL("content"), qt.IsFalse)
+	c.Assert(isBucketURL("/abs/content"), qt.IsFalse)
+	c.Assert(isBucketURL("ftp://example.org"), qt.IsFalse)
+}
+
+func TestSyncBucket(t *testing.T) {
+	c := qt.New(t)
+
+	bucketDir, err := ioutil.TempDir("", "hugo-bucket")
+	c.Assert(err, qt.IsNil)
+	defer os.RemoveAll(bucketDir)
+
+	writeObject := func(key, content string) {
+		filename := filepath.Join(bucketDir, filepath.FromSlash(key))
+		c.Assert(os.MkdirAll(filepath.Dir(filename), 0777), qt.IsNil)
+		c.Assert(ioutil.WriteFile(filename, []byte(content), 0666), qt.IsNil)
+	}
+
+	writeObject("media/a.txt", "A")
+	writeObject("media/sub/b.txt", "B")
+	writeObject("other/c.txt", "C")
+
+	fs := afero.NewMemMapFs()
+	client := NewClient(ClientConfig{Fs: fs, CacheDir: "/cache"})
+	collector := &collector{Client: client}
+
+	bucketURL := "file://" + filepath.ToSlash(bucketDir) + "?prefix=media/"
+
+	readFile := func(filename string) string {
+		b, err := afero.ReadFile(fs, filename)
+		c.Assert(err, qt.IsNil)
+		return string(b)
+	}
+
+	dir, err := collector.syncBucket(bucketURL)
+	c.Assert(err, qt.IsNil)
+	c.Assert(filepath.Dir(dir), qt.Equals, filepath.FromSlash("/cache/_buckets"))
+	c.Assert(readFile(filepath.Join(dir, "a.txt")), qt.Equals, "A")
+	c.Assert(readFile(filepath.Join(dir, "sub", "b.txt")), qt.Equals, "B")
+	_, err = fs.Stat(filepath.Join(dir, "c.txt"))
+	c.Assert(os.IsNotExist(err), qt.IsTrue)
+
+	// Unchanged objects are not downloaded again.
+	c.Assert(afero.WriteFile(fs, filepath.Join(dir, "a.txt"), []byte("Local"), 0666), qt.IsNil)
+	writeObject("media/sub/b.txt", "B2")
+	c.Assert(os.Remove(filepath.Join(bucketDir, "media", "a.txt")), qt.IsNil)
+	writeObject("media/d.txt", "D")
+
+	dir2, err := collector.syncBucket(bucketURL)
+	c.Assert(err, qt.IsNil)
+	c.Assert(dir2, qt.Equals, dir)
+	_, err = fs.Stat(filepath.Join(dir, "a.txt"))
+	c.Assert(os.IsNotExist(err), qt.IsTrue)
+	c.Assert(readFile(filepath.Join(dir, "sub", "b.txt")), qt.Equals, "B2")
+	c.Assert(readFile(filepath.Join(dir, "d.txt")), qt.Equals, "D")
+
+	_, err = collector.syncBucket("file:///does/not/exist")
+	c.Assert(err, qt.Not(qt.IsNil))
+}
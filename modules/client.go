@@ -41,6 +41,7 @@ import (
 	"github.com/gohugoio/hugo/common/loggers"
 
 	"github.com/gohugoio/hugo/config"
+	"github.com/gohugoio/hugo/config/netpolicy"
 
 	"github.com/rogpeppe/go-internal/module"
 
@@ -89,6 +90,10 @@ func NewClient(cfg ClientConfig) *Client {
 		"GOPRIVATE", mcfg.Private,
 		"GONOPROXY", mcfg.NoProxy)
 
+	// Restrict the module downloads, including the indirect dependencies,
+	// to the allowed hosts.
+	config.SetEnvVars(&env, cfg.NetworkPolicy.GoEnv(mcfg.Proxy, os.Getenv("GOSUMDB"))...)
+
 	if cfg.CacheDir != "" {
 		// Module cache stored below $GOPATH/pkg
 		config.SetEnvVars(&env, "GOPATH", cfg.CacheDir)
@@ -545,13 +550,13 @@ func (c *Client) runGo(
 	}
 
 	stderr := new(bytes.Buffer)
-	cmd, err := hexec.SafeCommandContext(ctx, "go", args...)
+	cmd, done, err := hexec.CommandContext(ctx, c.ccfg.ExecConfig, c.ccfg.WorkingDir, "go", args...)
 	if err != nil {
 		return err
 	}
+	defer done()
 
 	cmd.Env = c.environ
-	cmd.Dir = c.ccfg.WorkingDir
 	cmd.Stdout = stdout
 	cmd.Stderr = io.MultiWriter(stderr, os.Stderr)
 
@@ -658,6 +663,13 @@ type ClientConfig struct {
 
 	CacheDir     string // Module cache
 	ModuleConfig Config
+
+	// Restricts the module downloads. This can be nil.
+	NetworkPolicy *netpolicy.Policy
+
+	// The configuration the security policy and the limits of the go
+	// command are read from. This can be nil.
+	ExecConfig config.Provider
 }
 
 func (c ClientConfig) shouldIgnoreVendor(path string) bool {
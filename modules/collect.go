@@ -260,6 +260,9 @@ func (c *collector) add(owner *moduleAdapter, moduleImport Import, disabled bool
 		if moduleDir == "" {
 			if c.GoModulesFilename != "" && isProbablyModule(modulePath) {
 				// Try to "go get" it and reload the module configuration.
+				if err := c.ccfg.NetworkPolicy.CheckModule(modulePath); err != nil {
+					return nil, err
+				}
 				if err := c.Get(modulePath); err != nil {
 					return nil, err
 				}
@@ -627,15 +630,42 @@ func (c *collector) normalizeMounts(owner *moduleAdapter, mounts []Mount) ([]Mou
 	for _, mnt := range mounts {
 		errMsg := fmt.Sprintf("invalid module config for %q", owner.Path())
 
-		if mnt.Source == "" || mnt.Target == "" {
+		if (mnt.Source == "" && mnt.SQL == nil && mnt.Feed == nil) || mnt.Target == "" {
 			return nil, errors.New(errMsg + ": both source and target must be set")
 		}
 
+		var isMirror bool
+		if isBucketURL(mnt.Source) {
+			// Mirror the bucket to the module cache and mount that.
+			dir, err := c.syncBucket(mnt.Source)
+			if err != nil {
+				return nil, errors.Wrap(err, errMsg)
+			}
+			mnt.Source = dir
+			isMirror = true
+		} else if mnt.SQL != nil {
+			// Write the rows to the module cache and mount that.
+			dir, err := c.syncSQL(mnt.SQL)
+			if err != nil {
+				return nil, errors.Wrap(err, errMsg)
+			}
+			mnt.Source = dir
+			isMirror = true
+		} else if mnt.Feed != nil {
+			// Write the feed items to the module cache and mount that.
+			dir, err := c.syncFeeds(mnt.Feed)
+			if err != nil {
+				return nil, errors.Wrap(err, errMsg)
+			}
+			mnt.Source = dir
+			isMirror = true
+		}
+
 		mnt.Source = filepath.Clean(mnt.Source)
 		mnt.Target = filepath.Clean(mnt.Target)
 		var sourceDir string
 
-		if owner.projectMod && filepath.IsAbs(mnt.Source) {
+		if (owner.projectMod || isMirror) && filepath.IsAbs(mnt.Source) {
 			// Abs paths in the main project is allowed.
 			sourceDir = mnt.Source
 		} else {
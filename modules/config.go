@@ -244,8 +244,15 @@ func decodeConfig(cfg config.Provider, pathReplacements map[string]string) (Conf
 		}
 
 		for i, mnt := range c.Mounts {
-			mnt.Source = filepath.Clean(mnt.Source)
+			if !isBucketURL(mnt.Source) {
+				mnt.Source = filepath.Clean(mnt.Source)
+			}
 			mnt.Target = filepath.Clean(mnt.Target)
+			if mnt.SQL != nil {
+				if err := mnt.SQL.validate(); err != nil {
+					return c, err
+				}
+			}
 			c.Mounts[i] = mnt
 		}
 
@@ -379,6 +386,13 @@ type Mount struct {
 
 	Lang string // any language code associated with this mount.
 
+	// If set, the rows returned by this database query are mounted as
+	// content files and Source is not used.
+	SQL *SQLSource
+
+	// If set, the items in these RSS, Atom or JSON feeds are mounted as
+	// content files and Source is not used.
+	Feed *FeedSource
 }
 
 func (m Mount) Component() string {
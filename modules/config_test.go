@@ -14,7 +14,9 @@
 package modules
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gohugoio/hugo/common/hugo"
 
@@ -120,6 +122,60 @@ path="github.com/bep/mycomponent"
 
 		}
 	})
+
+	c.Run("SQL mount", func(c *qt.C) {
+		tomlConfig := `
+[module]
+[[module.mounts]]
+target="content/products"
+[module.mounts.sql]
+driver="postgres"
+dsn="postgres://localhost/shop"
+query="SELECT * FROM products"
+key="slug"
+updatedAt="updated_at"
+`
+		cfg, err := config.FromConfigString(tomlConfig, "toml")
+		c.Assert(err, qt.IsNil)
+
+		mcfg, err := DecodeConfig(cfg)
+		c.Assert(err, qt.IsNil)
+		c.Assert(len(mcfg.Mounts), qt.Equals, 1)
+		c.Assert(mcfg.Mounts[0].SQL, qt.DeepEquals, &SQLSource{
+			Driver:    "postgres",
+			DSN:       "postgres://localhost/shop",
+			Query:     "SELECT * FROM products",
+			Key:       "slug",
+			UpdatedAt: "updated_at",
+		})
+
+		cfg, err = config.FromConfigString(strings.Replace(tomlConfig, `driver="postgres"`, `driver="mysql"`, 1), "toml")
+		c.Assert(err, qt.IsNil)
+		_, err = DecodeConfig(cfg)
+		c.Assert(err, qt.ErrorMatches, `sql: unknown driver "mysql", must be one of .*`)
+	})
+
+	c.Run("Feed mount", func(c *qt.C) {
+		tomlConfig := `
+[module]
+[[module.mounts]]
+target="content/planet"
+[module.mounts.feed]
+urls=["https://example.org/index.xml"]
+refresh="30m"
+`
+		cfg, err := config.FromConfigString(tomlConfig, "toml")
+		c.Assert(err, qt.IsNil)
+
+		mcfg, err := DecodeConfig(cfg)
+		c.Assert(err, qt.IsNil)
+		c.Assert(len(mcfg.Mounts), qt.Equals, 1)
+		c.Assert(mcfg.Mounts[0].Feed, qt.DeepEquals, &FeedSource{
+			URLs:    []string{"https://example.org/index.xml"},
+			Refresh: "30m",
+		})
+		c.Assert(mcfg.Mounts[0].Feed.RefreshInterval(), qt.Equals, 30*time.Minute)
+	})
 }
 
 func TestDecodeConfigBothOldAndNewProvided(t *testing.T) {
@@ -0,0 +1,291 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modules
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"github.com/spf13/cast"
+)
+
+const (
+	feedsCacheDir          = "_feeds"
+	feedManifestName       = ".hugo_feeds.json"
+	feedDefaultRefresh     = time.Hour
+	feedFetchTimeout       = 30 * time.Second
+	feedMaxSize            = 10 << 20
+	feedItemFilenameLength = 16
+)
+
+// FeedSource configures a mount of the items in one or more RSS, Atom or
+// JSON feeds, one HTML content file per item, e.g.:
+//
+//	[[module.mounts]]
+//	target = "content/planet"
+//	[module.mounts.feed]
+//	urls = ["https://example.org/index.xml", "https://example.com/feed.json"]
+//	refresh = "30m"
+//
+// Items are identified by their GUID, and items no longer in a feed are
+// kept. The title, date, link, author, summary (as description) and the
+// feed's title and URL are written to the front matter.
+type FeedSource struct {
+	// The feeds to ingest.
+	URLs []string
+
+	// How often to fetch the feeds, e.g. "30m". Default is "1h".
+	// The server rebuilds the site with this interval.
+	Refresh string
+}
+
+// RefreshInterval returns how often to fetch the feeds.
+func (f *FeedSource) RefreshInterval() time.Duration {
+	if f.Refresh == "" {
+		return feedDefaultRefresh
+	}
+	d, err := cast.ToDurationE(f.Refresh)
+	if err != nil || d < 0 {
+		return feedDefaultRefresh
+	}
+	return d
+}
+
+func (f *FeedSource) validate() error {
+	if len(f.URLs) == 0 {
+		return errors.New("feed: no urls set")
+	}
+	for _, u := range f.URLs {
+		if !strings.HasPrefix(u, "http://") && !strings.HasPrefix(u, "https://") {
+			return errors.Errorf("feed: invalid url %q", u)
+		}
+	}
+	if f.Refresh != "" {
+		if d, err := cast.ToDurationE(f.Refresh); err != nil || d < 0 {
+			return errors.Errorf("feed: invalid refresh %q", f.Refresh)
+		}
+	}
+	return nil
+}
+
+type feedManifest struct {
+	// Maps feed URLs to their state.
+	Feeds map[string]*feedState
+
+	// Maps the item GUIDs to a hash of their file content.
+	Items map[string]string
+}
+
+type feedState struct {
+	Title        string
+	ETag         string
+	LastModified string
+	Fetched      time.Time
+}
+
+// syncFeeds writes the items in the feeds in f to a directory below
+// cacheDir in fs and returns that directory. Feeds are fetched at most once
+// per refresh interval, using conditional requests, and feeds that cannot
+// be fetched are skipped with a warning.
+func (c *collector) syncFeeds(f *FeedSource) (string, error) {
+	if err := f.validate(); err != nil {
+		return "", err
+	}
+
+	if c.ccfg.CacheDir == "" {
+		return "", errors.New("no cache dir configured for feed mounts")
+	}
+
+	dir := filepath.Join(c.ccfg.CacheDir, feedsCacheDir, fmt.Sprintf("%x", md5.Sum([]byte(strings.Join(f.URLs, "\n")))))
+	manifestFilename := filepath.Join(dir, feedManifestName)
+
+	var manifest feedManifest
+	if b, err := afero.ReadFile(c.fs, manifestFilename); err == nil {
+		if err := json.Unmarshal(b, &manifest); err != nil {
+			manifest = feedManifest{}
+		}
+	}
+	if manifest.Feeds == nil {
+		manifest.Feeds = make(map[string]*feedState)
+	}
+	if manifest.Items == nil {
+		manifest.Items = make(map[string]string)
+	}
+
+	if err := c.fs.MkdirAll(dir, 0777); err != nil {
+		return "", err
+	}
+
+	client := c.ccfg.NetworkPolicy.Client(&http.Client{Timeout: feedFetchTimeout})
+	refresh := f.RefreshInterval()
+	seen := make(map[string]bool)
+
+	for _, u := range f.URLs {
+		state := manifest.Feeds[u]
+		if state == nil {
+			state = &feedState{}
+			manifest.Feeds[u] = state
+		}
+
+		if !state.Fetched.IsZero() && time.Since(state.Fetched) < refresh {
+			continue
+		}
+
+		fd, err := fetchFeed(client, u, state)
+		if err != nil {
+			c.logger.Warnf("failed to fetch feed %q: %s", u, err)
+			continue
+		}
+
+		state.Fetched = time.Now()
+
+		if fd == nil {
+			// Not modified.
+			continue
+		}
+
+		state.Title = fd.Title
+
+		for _, item := range fd.Items {
+			if item.GUID == "" || seen[item.GUID] {
+				continue
+			}
+			seen[item.GUID] = true
+
+			b, err := feedItemToFile(item, state.Title, u)
+			if err != nil {
+				return "", errors.Wrapf(err, "feed: failed to write item %q", item.GUID)
+			}
+
+			filename := filepath.Join(dir, feedItemFilename(item.GUID))
+			hash := fmt.Sprintf("%x", md5.Sum(b))
+			if manifest.Items[item.GUID] == hash {
+				if _, err := c.fs.Stat(filename); err == nil {
+					continue
+				}
+			}
+
+			if err := afero.WriteFile(c.fs, filename, b, 0666); err != nil {
+				return "", err
+			}
+			manifest.Items[item.GUID] = hash
+		}
+	}
+
+	b, err := json.Marshal(manifest)
+	if err != nil {
+		return "", err
+	}
+
+	return dir, afero.WriteFile(c.fs, manifestFilename, b, 0666)
+}
+
+// fetchFeed fetches and parses the feed at u. It returns nil if the feed
+// is not modified since the last fetch.
+func fetchFeed(client *http.Client, u string, state *feedState) (*feed, error) {
+	req, err := http.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if state.ETag != "" {
+		req.Header.Set("If-None-Match", state.ETag)
+	}
+	if state.LastModified != "" {
+		req.Header.Set("If-Modified-Since", state.LastModified)
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotModified {
+		return nil, nil
+	}
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, errors.Errorf("unexpected status %s", res.Status)
+	}
+
+	b, err := ioutil.ReadAll(io.LimitReader(res.Body, feedMaxSize))
+	if err != nil {
+		return nil, err
+	}
+
+	fd, err := parseFeed(b)
+	if err != nil {
+		return nil, err
+	}
+
+	state.ETag = res.Header.Get("ETag")
+	state.LastModified = res.Header.Get("Last-Modified")
+
+	return fd, nil
+}
+
+// feedItemFilename returns the filename for the item with the given GUID.
+func feedItemFilename(guid string) string {
+	return fmt.Sprintf("%x", md5.Sum([]byte(guid)))[:feedItemFilenameLength] + ".html"
+}
+
+// feedItemToFile creates a HTML content file with JSON front matter from
+// item.
+func feedItemToFile(item feedItem, feedTitle, feedURL string) ([]byte, error) {
+	frontMatter := map[string]interface{}{
+		"title":     item.Title,
+		"link":      item.Link,
+		"guid":      item.GUID,
+		"feedTitle": feedTitle,
+		"feedURL":   feedURL,
+	}
+	if !item.Date.IsZero() {
+		frontMatter["date"] = item.Date
+	}
+	if item.Author != "" {
+		frontMatter["author"] = item.Author
+	}
+	if item.Summary != "" {
+		frontMatter["description"] = item.Summary
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(frontMatter); err != nil {
+		return nil, err
+	}
+
+	content := item.Content
+	if content == "" {
+		content = item.Summary
+	}
+	if content != "" {
+		buf.WriteString("\n")
+		buf.WriteString(content)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,301 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modules
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"html"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+)
+
+var feedDateLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC3339,
+	time.RFC822Z,
+	time.RFC822,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+type feed struct {
+	Title string
+	Items []feedItem
+}
+
+type feedItem struct {
+	GUID    string
+	Title   string
+	Link    string
+	Author  string
+	Summary string
+	Content string
+	Date    time.Time
+}
+
+// parseFeed parses b as a RSS 1.0, RSS 2.0, Atom or JSON feed.
+func parseFeed(b []byte) (*feed, error) {
+	b = bytes.TrimSpace(bytes.TrimPrefix(b, []byte("\xef\xbb\xbf")))
+	if len(b) == 0 {
+		return nil, errors.New("feed: empty document")
+	}
+
+	var (
+		fd  *feed
+		err error
+	)
+
+	if b[0] == '{' {
+		fd, err = parseJSONFeed(b)
+	} else {
+		fd, err = parseXMLFeed(b)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i, item := range fd.Items {
+		if item.GUID != "" {
+			continue
+		}
+		if item.Link != "" {
+			fd.Items[i].GUID = item.Link
+		} else if item.Title != "" {
+			fd.Items[i].GUID = item.Title + "|" + item.Date.Format(time.RFC3339)
+		}
+	}
+
+	return fd, nil
+}
+
+type xmlFeed struct {
+	XMLName xml.Name
+	Title   string `xml:"title"`
+	Channel struct {
+		Title string    `xml:"title"`
+		Items []rssItem `xml:"item"`
+	} `xml:"channel"`
+
+	// RSS 1.0 puts the items next to the channel.
+	Items   []rssItem   `xml:"item"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type rssItem struct {
+	GUID        string `xml:"guid"`
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	Author      string `xml:"author"`
+	Creator     string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	Description string `xml:"description"`
+	Content     string `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	PubDate     string `xml:"pubDate"`
+	Date        string `xml:"http://purl.org/dc/elements/1.1/ date"`
+	About       string `xml:"http://www.w3.org/1999/02/22-rdf-syntax-ns# about,attr"`
+}
+
+type atomEntry struct {
+	ID    string `xml:"id"`
+	Title string `xml:"title"`
+	Links []struct {
+		Href string `xml:"href,attr"`
+		Rel  string `xml:"rel,attr"`
+	} `xml:"link"`
+	Authors []struct {
+		Name string `xml:"name"`
+	} `xml:"author"`
+	Summary   atomText `xml:"summary"`
+	Content   atomText `xml:"content"`
+	Published string   `xml:"published"`
+	Updated   string   `xml:"updated"`
+}
+
+// atomText is an Atom text construct, which is either text, escaped HTML or
+// inline XHTML.
+type atomText struct {
+	Type     string `xml:"type,attr"`
+	Text     string `xml:",chardata"`
+	InnerXML string `xml:",innerxml"`
+}
+
+func (t atomText) String() string {
+	switch t.Type {
+	case "xhtml":
+		return strings.TrimSpace(t.InnerXML)
+	case "html":
+		return strings.TrimSpace(t.Text)
+	default:
+		return html.EscapeString(strings.TrimSpace(t.Text))
+	}
+}
+
+func parseXMLFeed(b []byte) (*feed, error) {
+	var x xmlFeed
+	dec := xml.NewDecoder(bytes.NewReader(b))
+	dec.Strict = false
+	dec.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
+		// Most feeds are UTF-8, and we treat the rest as such too.
+		return input, nil
+	}
+	if err := dec.Decode(&x); err != nil {
+		return nil, errors.Wrap(err, "feed: failed to parse XML")
+	}
+
+	fd := &feed{}
+
+	switch x.XMLName.Local {
+	case "rss", "RDF":
+		fd.Title = strings.TrimSpace(x.Channel.Title)
+		for _, it := range append(x.Channel.Items, x.Items...) {
+			item := feedItem{
+				GUID:    strings.TrimSpace(it.GUID),
+				Title:   strings.TrimSpace(it.Title),
+				Link:    strings.TrimSpace(it.Link),
+				Author:  strings.TrimSpace(it.Author),
+				Summary: strings.TrimSpace(it.Description),
+				Content: strings.TrimSpace(it.Content),
+				Date:    parseFeedDate(it.PubDate),
+			}
+			if item.GUID == "" {
+				item.GUID = strings.TrimSpace(it.About)
+			}
+			if item.Author == "" {
+				item.Author = strings.TrimSpace(it.Creator)
+			}
+			if item.Date.IsZero() {
+				item.Date = parseFeedDate(it.Date)
+			}
+			fd.Items = append(fd.Items, item)
+		}
+	case "feed":
+		fd.Title = strings.TrimSpace(x.Title)
+		for _, e := range x.Entries {
+			item := feedItem{
+				GUID:    strings.TrimSpace(e.ID),
+				Title:   strings.TrimSpace(e.Title),
+				Summary: e.Summary.String(),
+				Content: e.Content.String(),
+				Date:    parseFeedDate(e.Published),
+			}
+			for _, l := range e.Links {
+				if l.Rel == "" || l.Rel == "alternate" {
+					item.Link = strings.TrimSpace(l.Href)
+					break
+				}
+			}
+			var authors []string
+			for _, a := range e.Authors {
+				if name := strings.TrimSpace(a.Name); name != "" {
+					authors = append(authors, name)
+				}
+			}
+			item.Author = strings.Join(authors, ", ")
+			if item.Date.IsZero() {
+				item.Date = parseFeedDate(e.Updated)
+			}
+			fd.Items = append(fd.Items, item)
+		}
+	default:
+		return nil, errors.Errorf("feed: unsupported feed format %q", x.XMLName.Local)
+	}
+
+	return fd, nil
+}
+
+type jsonFeed struct {
+	Title string `json:"title"`
+	Items []struct {
+		ID            interface{}  `json:"id"`
+		URL           string       `json:"url"`
+		Title         string       `json:"title"`
+		ContentHTML   string       `json:"content_html"`
+		ContentText   string       `json:"content_text"`
+		Summary       string       `json:"summary"`
+		DatePublished string       `json:"date_published"`
+		DateModified  string       `json:"date_modified"`
+		Author        *jsonAuthor  `json:"author"`
+		Authors       []jsonAuthor `json:"authors"`
+	} `json:"items"`
+}
+
+type jsonAuthor struct {
+	Name string `json:"name"`
+}
+
+func parseJSONFeed(b []byte) (*feed, error) {
+	var j jsonFeed
+	if err := json.Unmarshal(b, &j); err != nil {
+		return nil, errors.Wrap(err, "feed: failed to parse JSON")
+	}
+
+	fd := &feed{Title: strings.TrimSpace(j.Title)}
+	for _, it := range j.Items {
+		item := feedItem{
+			Title:   strings.TrimSpace(it.Title),
+			Link:    strings.TrimSpace(it.URL),
+			Summary: strings.TrimSpace(it.Summary),
+			Content: strings.TrimSpace(it.ContentHTML),
+			Date:    parseFeedDate(it.DatePublished),
+		}
+		if it.ID != nil {
+			// The id is a string in the spec, but some feeds use numbers.
+			item.GUID = strings.TrimSpace(cast.ToString(it.ID))
+		}
+		if item.Content == "" {
+			item.Content = html.EscapeString(strings.TrimSpace(it.ContentText))
+		}
+		if item.Date.IsZero() {
+			item.Date = parseFeedDate(it.DateModified)
+		}
+		authors := it.Authors
+		if it.Author != nil {
+			authors = append(authors, *it.Author)
+		}
+		var names []string
+		for _, a := range authors {
+			if name := strings.TrimSpace(a.Name); name != "" {
+				names = append(names, name)
+			}
+		}
+		item.Author = strings.Join(names, ", ")
+		fd.Items = append(fd.Items, item)
+	}
+
+	return fd, nil
+}
+
+// parseFeedDate parses the date formats commonly used in feeds. It returns
+// the zero time if s cannot be parsed.
+func parseFeedDate(s string) time.Time {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}
+	}
+	for _, layout := range feedDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
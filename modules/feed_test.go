@@ -0,0 +1,215 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modules
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gohugoio/hugo/common/loggers"
+	"github.com/spf13/afero"
+
+	qt "github.com/frankban/quicktest"
+)
+
+const testRSSFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:content="http://purl.org/rss/1.0/modules/content/">
+<channel>
+  <title>Blog A</title>
+  <item>
+    <title>First</title>
+    <link>https://a.org/first/</link>
+    <guid>https://a.org/first/</guid>
+    <pubDate>Mon, 03 May 2021 10:00:00 +0000</pubDate>
+    <dc:creator>Jane</dc:creator>
+    <description>The first post.</description>
+    <content:encoded><![CDATA[<p>First <b>post</b>.</p>]]></content:encoded>
+  </item>
+  <item>
+    <title>First again</title>
+    <guid>https://a.org/first/</guid>
+  </item>
+</channel>
+</rss>`
+
+const testAtomFeed = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Blog B</title>
+  <entry>
+    <id>tag:b.org,2021:1</id>
+    <title>Atom post</title>
+    <link rel="alternate" href="https://b.org/post/"/>
+    <updated>2021-05-04T08:00:00Z</updated>
+    <author><name>John</name></author>
+    <content type="html">&lt;p&gt;Atom content.&lt;/p&gt;</content>
+  </entry>
+</feed>`
+
+const testJSONFeed = `{
+  "version": "https://jsonfeed.org/version/1.1",
+  "title": "Blog C",
+  "items": [
+    {"id": 42, "url": "https://c.org/42/", "title": "JSON post", "content_text": "a < b", "date_published": "2021-05-05T09:00:00+02:00", "authors": [{"name": "Kim"}]}
+  ]
+}`
+
+func TestParseFeed(t *testing.T) {
+	c := qt.New(t)
+
+	fd, err := parseFeed([]byte(testRSSFeed))
+	c.Assert(err, qt.IsNil)
+	c.Assert(fd.Title, qt.Equals, "Blog A")
+	c.Assert(fd.Items, qt.HasLen, 2)
+	c.Assert(fd.Items[0], qt.DeepEquals, feedItem{
+		GUID:    "https://a.org/first/",
+		Title:   "First",
+		Link:    "https://a.org/first/",
+		Author:  "Jane",
+		Summary: "The first post.",
+		Content: "<p>First <b>post</b>.</p>",
+		Date:    time.Date(2021, 5, 3, 10, 0, 0, 0, time.FixedZone("", 0)),
+	})
+
+	fd, err = parseFeed([]byte(testAtomFeed))
+	c.Assert(err, qt.IsNil)
+	c.Assert(fd.Title, qt.Equals, "Blog B")
+	c.Assert(fd.Items, qt.HasLen, 1)
+	c.Assert(fd.Items[0].GUID, qt.Equals, "tag:b.org,2021:1")
+	c.Assert(fd.Items[0].Link, qt.Equals, "https://b.org/post/")
+	c.Assert(fd.Items[0].Author, qt.Equals, "John")
+	c.Assert(fd.Items[0].Content, qt.Equals, "<p>Atom content.</p>")
+	c.Assert(fd.Items[0].Date.Equal(time.Date(2021, 5, 4, 8, 0, 0, 0, time.UTC)), qt.IsTrue)
+
+	fd, err = parseFeed([]byte(testJSONFeed))
+	c.Assert(err, qt.IsNil)
+	c.Assert(fd.Title, qt.Equals, "Blog C")
+	c.Assert(fd.Items, qt.HasLen, 1)
+	c.Assert(fd.Items[0].GUID, qt.Equals, "42")
+	c.Assert(fd.Items[0].Content, qt.Equals, "a &lt; b")
+	c.Assert(fd.Items[0].Author, qt.Equals, "Kim")
+	c.Assert(fd.Items[0].Date.Equal(time.Date(2021, 5, 5, 7, 0, 0, 0, time.UTC)), qt.IsTrue)
+
+	// Items without a GUID are identified by their link.
+	fd, err = parseFeed([]byte(`<rss><channel><item><link>https://d.org/</link></item></channel></rss>`))
+	c.Assert(err, qt.IsNil)
+	c.Assert(fd.Items[0].GUID, qt.Equals, "https://d.org/")
+
+	_, err = parseFeed([]byte(`<html></html>`))
+	c.Assert(err, qt.ErrorMatches, `feed: unsupported feed format "html"`)
+	_, err = parseFeed([]byte(" "))
+	c.Assert(err, qt.ErrorMatches, "feed: empty document")
+}
+
+func TestSyncFeeds(t *testing.T) {
+	c := qt.New(t)
+
+	var (
+		mu       sync.Mutex
+		requests = make(map[string]int)
+		rss      = testRSSFeed
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests[r.URL.Path]++
+		switch r.URL.Path {
+		case "/rss.xml":
+			if r.Header.Get("If-None-Match") == `"v1"` && rss == testRSSFeed {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"v1"`)
+			w.Write([]byte(rss))
+		case "/atom.xml":
+			w.Write([]byte(testAtomFeed))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	countRequests := func(path string) int {
+		mu.Lock()
+		defer mu.Unlock()
+		return requests[path]
+	}
+
+	fs := afero.NewMemMapFs()
+	client := NewClient(ClientConfig{Fs: fs, CacheDir: "/cache", Logger: loggers.NewErrorLogger()})
+	collector := &collector{Client: client}
+
+	source := &FeedSource{URLs: []string{srv.URL + "/rss.xml", srv.URL + "/atom.xml", srv.URL + "/missing.xml"}}
+
+	readFile := func(filename string) string {
+		b, err := afero.ReadFile(fs, filename)
+		c.Assert(err, qt.IsNil)
+		return string(b)
+	}
+
+	dir, err := collector.syncFeeds(source)
+	c.Assert(err, qt.IsNil)
+	c.Assert(filepath.Dir(dir), qt.Equals, filepath.FromSlash("/cache/_feeds"))
+
+	first := filepath.Join(dir, feedItemFilename("https://a.org/first/"))
+	c.Assert(readFile(first), qt.Equals, `{
+  "author": "Jane",
+  "date": "2021-05-03T10:00:00Z",
+  "description": "The first post.",
+  "feedTitle": "Blog A",
+  "feedURL": "`+srv.URL+`/rss.xml",
+  "guid": "https://a.org/first/",
+  "link": "https://a.org/first/",
+  "title": "First"
+}
+
+<p>First <b>post</b>.</p>`)
+	c.Assert(readFile(filepath.Join(dir, feedItemFilename("tag:b.org,2021:1"))), qt.Contains, `"title": "Atom post"`)
+
+	files, err := afero.ReadDir(fs, dir)
+	c.Assert(err, qt.IsNil)
+	c.Assert(files, qt.HasLen, 3) // 2 items and the manifest.
+
+	// The feeds are not fetched again within the refresh interval.
+	_, err = collector.syncFeeds(source)
+	c.Assert(err, qt.IsNil)
+	c.Assert(countRequests("/rss.xml"), qt.Equals, 1)
+	c.Assert(countRequests("/missing.xml"), qt.Equals, 2)
+
+	// Unchanged feeds are not downloaded again.
+	source.Refresh = "0s"
+	c.Assert(afero.WriteFile(fs, first, []byte("Local"), 0666), qt.IsNil)
+	_, err = collector.syncFeeds(source)
+	c.Assert(err, qt.IsNil)
+	c.Assert(countRequests("/rss.xml"), qt.Equals, 2)
+	c.Assert(readFile(first), qt.Equals, "Local")
+
+	// Items no longer in a feed are kept.
+	mu.Lock()
+	rss = `<rss><channel><title>Blog A</title><item><guid>https://a.org/second/</guid><title>Second</title></item></channel></rss>`
+	mu.Unlock()
+	_, err = collector.syncFeeds(source)
+	c.Assert(err, qt.IsNil)
+	c.Assert(readFile(first), qt.Equals, "Local")
+	c.Assert(readFile(filepath.Join(dir, feedItemFilename("https://a.org/second/"))), qt.Contains, `"title": "Second"`)
+
+	_, err = collector.syncFeeds(&FeedSource{URLs: []string{"ftp://example.org/feed.xml"}})
+	c.Assert(err, qt.ErrorMatches, `feed: invalid url "ftp://example.org/feed.xml"`)
+	_, err = collector.syncFeeds(&FeedSource{URLs: []string{srv.URL}, Refresh: "often"})
+	c.Assert(err, qt.ErrorMatches, `feed: invalid refresh "often"`)
+}
@@ -0,0 +1,304 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modules
+
+import (
+	"bytes"
+	"crypto/md5"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+
+	// The database/sql drivers available to SQL mounts.
+	_ "github.com/lib/pq"
+	_ "modernc.org/sqlite"
+)
+
+const (
+	sqlCacheDir        = "_sql"
+	sqlManifestName    = ".hugo_sql.json"
+	sqlDefaultFileExt  = "md"
+	sqlUpdatedAtFormat = time.RFC3339Nano
+)
+
+// SQLSource configures a read-only mount of the rows returned by a database
+// query, one content file per row, e.g.:
+//
+//	[[module.mounts]]
+//	target = "content/products"
+//	[module.mounts.sql]
+//	driver = "postgres"
+//	dsn = "postgres://localhost/shop"
+//	query = "SELECT slug, title, price, description, updated_at FROM products"
+//	key = "slug"
+//	content = "description"
+//	updatedAt = "updated_at"
+//
+// The columns, except key and content, are written to the front matter.
+type SQLSource struct {
+	// The database driver, "postgres" (github.com/lib/pq) or "sqlite"
+	// (modernc.org/sqlite, with the path to the database file as the DSN).
+	// Any other driver is rejected when the config is decoded.
+	Driver string
+
+	// The data source name, in the format of the driver.
+	DSN string
+
+	// The query to run.
+	Query string
+
+	// The column with the path of the file, relative to the mount target and
+	// without extension, e.g. "shoes" or "shoes/_index".
+	Key string
+
+	// The column with the content, if any.
+	Content string
+
+	// The column with the time the row was last updated, if any. If set, only
+	// rows updated since the last build are written. If not, rows are
+	// compared by their content.
+	UpdatedAt string
+
+	// The file extension, default "md".
+	Ext string
+}
+
+// sqlManifest maps the keys of the rows mirrored to their updated-at value
+// or content hash.
+type sqlManifest map[string]string
+
+func (s *SQLSource) validate() error {
+	if s.Driver == "" || s.Query == "" || s.Key == "" {
+		return errors.New("sql: driver, query and key must be set")
+	}
+	for _, driver := range sql.Drivers() {
+		if driver == s.Driver {
+			return nil
+		}
+	}
+	return errors.Errorf("sql: unknown driver %q, must be one of %s", s.Driver, strings.Join(sql.Drivers(), ", "))
+}
+
+// syncSQL writes the rows returned by the query in s to a directory below
+// cacheDir in fs and returns that directory. Files are only written for new
+// or changed rows, and files for rows no longer returned are removed.
+//
+// If the query fails, e.g. when the database is down, any existing mirror is
+// used as is, with a warning.
+func (c *collector) syncSQL(s *SQLSource) (string, error) {
+	if err := s.validate(); err != nil {
+		return "", err
+	}
+
+	if c.ccfg.CacheDir == "" {
+		return "", errors.New("no cache dir configured for sql mounts")
+	}
+
+	ext := strings.TrimPrefix(s.Ext, ".")
+	if ext == "" {
+		ext = sqlDefaultFileExt
+	}
+
+	id := fmt.Sprintf("%s_%x", s.Driver, md5.Sum([]byte(strings.Join([]string{s.DSN, s.Query, s.Key, s.Content, s.UpdatedAt, ext}, "\x00"))))
+	dir := filepath.Join(c.ccfg.CacheDir, sqlCacheDir, id)
+	manifestFilename := filepath.Join(dir, sqlManifestName)
+
+	manifest := make(sqlManifest)
+	if b, err := afero.ReadFile(c.fs, manifestFilename); err == nil {
+		if err := json.Unmarshal(b, &manifest); err != nil {
+			manifest = make(sqlManifest)
+		}
+	}
+
+	rows, err := querySQL(s)
+	if err != nil {
+		if _, statErr := c.fs.Stat(manifestFilename); statErr == nil {
+			c.logger.Warnf("failed to query %s database, using the cached copy: %s", s.Driver, err)
+			return dir, nil
+		}
+		return "", err
+	}
+
+	seen := make(map[string]bool)
+	for _, row := range rows {
+		key, ok := row[s.Key]
+		if !ok {
+			return "", errors.Errorf("sql: key column %q not found", s.Key)
+		}
+		path := strings.Trim(filepath.ToSlash(fmt.Sprint(key)), "/")
+		if path == "" {
+			return "", errors.New("sql: empty key")
+		}
+		if seen[path] {
+			return "", errors.Errorf("sql: duplicate key %q", path)
+		}
+		seen[path] = true
+
+		filename, err := mirrorFilename(dir, path+"."+ext)
+		if err != nil {
+			return "", err
+		}
+
+		var (
+			b       []byte
+			version string
+		)
+
+		if s.UpdatedAt != "" {
+			version = sqlUpdatedAt(row[s.UpdatedAt])
+		}
+
+		if version == "" {
+			// No updated-at value, compare the content.
+			if b, err = sqlRowToFile(s, row); err != nil {
+				return "", errors.Wrapf(err, "sql: failed to write %q", path)
+			}
+			version = fmt.Sprintf("%x", md5.Sum(b))
+		}
+
+		if manifest[path] == version {
+			if _, err := c.fs.Stat(filename); err == nil {
+				continue
+			}
+		}
+
+		if b == nil {
+			if b, err = sqlRowToFile(s, row); err != nil {
+				return "", errors.Wrapf(err, "sql: failed to write %q", path)
+			}
+		}
+
+		if err := c.fs.MkdirAll(filepath.Dir(filename), 0777); err != nil {
+			return "", err
+		}
+		if err := afero.WriteFile(c.fs, filename, b, 0666); err != nil {
+			return "", err
+		}
+		manifest[path] = version
+	}
+
+	for path := range manifest {
+		if seen[path] {
+			continue
+		}
+		delete(manifest, path)
+		if filename, err := mirrorFilename(dir, path+"."+ext); err == nil {
+			if err := c.fs.Remove(filename); err != nil && !os.IsNotExist(err) {
+				return "", err
+			}
+		}
+	}
+
+	b, err := json.Marshal(manifest)
+	if err != nil {
+		return "", err
+	}
+
+	if err := c.fs.MkdirAll(dir, 0777); err != nil {
+		return "", err
+	}
+
+	return dir, afero.WriteFile(c.fs, manifestFilename, b, 0666)
+}
+
+// querySQL runs the query in s and returns the rows as column/value maps.
+func querySQL(s *SQLSource) ([]map[string]interface{}, error) {
+	db, err := sql.Open(s.Driver, s.DSN)
+	if err != nil {
+		return nil, errors.Wrap(err, "sql")
+	}
+	defer db.Close()
+
+	rows, err := db.Query(s.Query)
+	if err != nil {
+		return nil, errors.Wrap(err, "sql")
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, errors.Wrap(err, "sql")
+	}
+
+	var result []map[string]interface{}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, errors.Wrap(err, "sql")
+		}
+
+		row := make(map[string]interface{})
+		for i, column := range columns {
+			v := values[i]
+			if b, ok := v.([]byte); ok {
+				v = string(b)
+			}
+			row[column] = v
+		}
+		result = append(result, row)
+	}
+
+	return result, errors.Wrap(rows.Err(), "sql")
+}
+
+// sqlRowToFile creates a content file with JSON front matter from row.
+func sqlRowToFile(s *SQLSource, row map[string]interface{}) ([]byte, error) {
+	frontMatter := make(map[string]interface{})
+	for column, v := range row {
+		if column == s.Key || column == s.Content || v == nil {
+			continue
+		}
+		frontMatter[column] = v
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(frontMatter); err != nil {
+		return nil, err
+	}
+
+	if s.Content != "" {
+		if v := row[s.Content]; v != nil {
+			buf.WriteString("\n")
+			buf.WriteString(fmt.Sprint(v))
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// sqlUpdatedAt returns a string representation of the updated-at value v.
+func sqlUpdatedAt(v interface{}) string {
+	switch vv := v.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		return vv.UTC().Format(sqlUpdatedAtFormat)
+	default:
+		return fmt.Sprint(vv)
+	}
+}
@@ -0,0 +1,224 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modules
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gohugoio/hugo/common/loggers"
+	"github.com/spf13/afero"
+
+	qt "github.com/frankban/quicktest"
+)
+
+// testSQLDriver is a database/sql driver returning the rows in tables for
+// any query, with the DSN as the table name.
+type testSQLDriver struct {
+	mu     sync.Mutex
+	tables map[string]*testSQLRows
+}
+
+type testSQLRows struct {
+	columns []string
+	values  [][]driver.Value
+	i       int
+}
+
+var testSQL = &testSQLDriver{tables: make(map[string]*testSQLRows)}
+
+func init() {
+	sql.Register("hugotest", testSQL)
+}
+
+func (d *testSQLDriver) set(dsn string, columns []string, values ...[]driver.Value) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if columns == nil {
+		delete(d.tables, dsn)
+		return
+	}
+	d.tables[dsn] = &testSQLRows{columns: columns, values: values}
+}
+
+func (d *testSQLDriver) Open(dsn string) (driver.Conn, error) {
+	return &testSQLConn{d: d, dsn: dsn}, nil
+}
+
+type testSQLConn struct {
+	d   *testSQLDriver
+	dsn string
+}
+
+func (c *testSQLConn) Prepare(query string) (driver.Stmt, error) { return c, nil }
+func (c *testSQLConn) Close() error                              { return nil }
+func (c *testSQLConn) Begin() (driver.Tx, error)                 { return nil, driver.ErrSkip }
+func (c *testSQLConn) NumInput() int                             { return -1 }
+
+func (c *testSQLConn) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, driver.ErrSkip
+}
+
+func (c *testSQLConn) Query(args []driver.Value) (driver.Rows, error) {
+	c.d.mu.Lock()
+	defer c.d.mu.Unlock()
+	rows, found := c.d.tables[c.dsn]
+	if !found {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return &testSQLRows{columns: rows.columns, values: rows.values}, nil
+}
+
+func (r *testSQLRows) Columns() []string { return r.columns }
+func (r *testSQLRows) Close() error      { return nil }
+
+func (r *testSQLRows) Next(dest []driver.Value) error {
+	if r.i >= len(r.values) {
+		return io.EOF
+	}
+	copy(dest, r.values[r.i])
+	r.i++
+	return nil
+}
+
+func TestSyncSQL(t *testing.T) {
+	c := qt.New(t)
+
+	columns := []string{"slug", "title", "body", "updated_at"}
+	t1 := time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC)
+	t2 := t1.Add(time.Hour)
+
+	testSQL.set("products", columns,
+		[]driver.Value{"shoes", "Shoes", []byte("Nice **shoes**."), t1},
+		[]driver.Value{"hats/cap", "Cap", "A cap.", t1},
+	)
+
+	fs := afero.NewMemMapFs()
+	client := NewClient(ClientConfig{Fs: fs, CacheDir: "/cache", Logger: loggers.NewErrorLogger()})
+	collector := &collector{Client: client}
+
+	source := &SQLSource{
+		Driver:    "hugotest",
+		DSN:       "products",
+		Query:     "SELECT * FROM products",
+		Key:       "slug",
+		Content:   "body",
+		UpdatedAt: "updated_at",
+	}
+
+	readFile := func(filename string) string {
+		b, err := afero.ReadFile(fs, filename)
+		c.Assert(err, qt.IsNil)
+		return string(b)
+	}
+
+	dir, err := collector.syncSQL(source)
+	c.Assert(err, qt.IsNil)
+	c.Assert(filepath.Dir(dir), qt.Equals, filepath.FromSlash("/cache/_sql"))
+	c.Assert(readFile(filepath.Join(dir, "shoes.md")), qt.Equals, `{
+  "title": "Shoes",
+  "updated_at": "2021-05-01T12:00:00Z"
+}
+
+Nice **shoes**.`)
+	c.Assert(readFile(filepath.Join(dir, "hats", "cap.md")), qt.Contains, `"title": "Cap"`)
+
+	// Only rows with a new updated-at value are written.
+	c.Assert(afero.WriteFile(fs, filepath.Join(dir, "shoes.md"), []byte("Local"), 0666), qt.IsNil)
+	testSQL.set("products", columns,
+		[]driver.Value{"shoes", "Shoes", "Nice shoes.", t1},
+		[]driver.Value{"boots", "Boots", "Boots.", t2},
+	)
+
+	dir2, err := collector.syncSQL(source)
+	c.Assert(err, qt.IsNil)
+	c.Assert(dir2, qt.Equals, dir)
+	c.Assert(readFile(filepath.Join(dir, "shoes.md")), qt.Equals, "Local")
+	c.Assert(readFile(filepath.Join(dir, "boots.md")), qt.Contains, `"title": "Boots"`)
+	_, err = fs.Stat(filepath.Join(dir, "hats", "cap.md"))
+	c.Assert(os.IsNotExist(err), qt.IsTrue)
+
+	// Without an updated-at column the content is compared.
+	source.UpdatedAt = ""
+	dir3, err := collector.syncSQL(source)
+	c.Assert(err, qt.IsNil)
+	c.Assert(dir3, qt.Not(qt.Equals), dir)
+	c.Assert(readFile(filepath.Join(dir3, "shoes.md")), qt.Contains, "Nice shoes.")
+
+	// The cached copy is used when the query fails.
+	testSQL.set("products", nil)
+	dir4, err := collector.syncSQL(source)
+	c.Assert(err, qt.IsNil)
+	c.Assert(dir4, qt.Equals, dir3)
+
+	source.DSN = "missing"
+	_, err = collector.syncSQL(source)
+	c.Assert(err, qt.Not(qt.IsNil))
+
+	_, err = collector.syncSQL(&SQLSource{Driver: "hugotest"})
+	c.Assert(err, qt.ErrorMatches, "sql: driver, query and key must be set")
+
+	_, err = collector.syncSQL(&SQLSource{Driver: "mysql", Query: "SELECT * FROM products", Key: "slug"})
+	c.Assert(err, qt.ErrorMatches, `sql: unknown driver "mysql", must be one of .*postgres, sqlite`)
+}
+
+func TestSyncSQLSQLite(t *testing.T) {
+	c := qt.New(t)
+
+	dbFilename := filepath.Join(c.TempDir(), "shop.db")
+	db, err := sql.Open("sqlite", dbFilename)
+	c.Assert(err, qt.IsNil)
+	_, err = db.Exec(`
+CREATE TABLE products (slug TEXT PRIMARY KEY, title TEXT, price INTEGER, description TEXT);
+INSERT INTO products VALUES ('shoes', 'Shoes', 120, 'Nice **shoes**.'), ('hats/cap', 'Cap', 25, NULL);
+`)
+	c.Assert(err, qt.IsNil)
+	c.Assert(db.Close(), qt.IsNil)
+
+	fs := afero.NewMemMapFs()
+	client := NewClient(ClientConfig{Fs: fs, CacheDir: "/cache", Logger: loggers.NewErrorLogger()})
+	collector := &collector{Client: client}
+
+	dir, err := collector.syncSQL(&SQLSource{
+		Driver:  "sqlite",
+		DSN:     dbFilename,
+		Query:   "SELECT slug, title, price, description FROM products WHERE price > 20 ORDER BY slug",
+		Key:     "slug",
+		Content: "description",
+	})
+	c.Assert(err, qt.IsNil)
+
+	b, err := afero.ReadFile(fs, filepath.Join(dir, "shoes.md"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(b), qt.Equals, `{
+  "price": 120,
+  "title": "Shoes"
+}
+
+Nice **shoes**.`)
+
+	b, err = afero.ReadFile(fs, filepath.Join(dir, "hats", "cap.md"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(b), qt.Equals, `{
+  "price": 25,
+  "title": "Cap"
+}
+`)
+}
@@ -18,6 +18,7 @@ import (
 	"sync"
 
 	"github.com/gohugoio/hugo/helpers"
+	"github.com/gohugoio/hugo/media"
 )
 
 // These may be used as content sections with potential conflicts. Avoid that.
@@ -175,6 +176,8 @@ func resolvePageTemplate(d LayoutDescriptor, f Format) []string {
 		b.addKind()
 	case "404":
 		b.addLayoutVariations("404")
+		// Section specific 404 pages, e.g. docs/404.html.
+		b.addSectionType()
 		b.addTypeVariations("")
 	}
 
@@ -204,6 +207,48 @@ func resolvePageTemplate(d LayoutDescriptor, f Format) []string {
 		layouts = append(layouts, "_internal/_default/rss.xml")
 	}
 
+	if !d.Baseof && d.isList() && f.Name == SearchIndexFormat.Name {
+		layouts = append(layouts, "_internal/_default/list.searchindex.json")
+	}
+
+	if !d.RenderingHook && !d.Baseof && f.Name == ContentAPIFormat.Name {
+		if d.isList() {
+			layouts = append(layouts, "_internal/_default/list.contentapi.json")
+		} else if d.Kind == "page" {
+			layouts = append(layouts, "_internal/_default/single.contentapi.json")
+		}
+	}
+
+	if !d.RenderingHook && !d.Baseof && (f.Name == EmailFormat.Name || f.Name == EmailTextFormat.Name || f.Name == PrintFormat.Name) {
+		var internal string
+		suffix := strings.ToLower(f.Name) + "." + f.MediaType.FirstSuffix.Suffix
+		if d.isList() {
+			internal = "_internal/_default/list." + suffix
+		} else if d.Kind == "page" {
+			internal = "_internal/_default/single." + suffix
+		}
+
+		if internal != "" {
+			// Layouts for the web rarely work in email or for a printed
+			// section, so prefer the built-in templates to any layout not
+			// made for this format.
+			var specific, generic []string
+			for _, l := range layouts {
+				if strings.Contains(l, "."+strings.ToLower(f.Name)+".") {
+					specific = append(specific, l)
+				} else {
+					generic = append(generic, l)
+				}
+			}
+			layouts = append(append(specific, internal), generic...)
+		}
+	}
+
+	if !d.RenderingHook && !d.Baseof && f.Projected && f.MediaType.SubType == media.JSONType.SubType {
+		// Renders the projection configured for this output format, if any.
+		layouts = append(layouts, "_internal/_default/projection.json")
+	}
+
 	return layouts
 }
 
@@ -40,6 +40,8 @@ func TestLayout(t *testing.T) {
 
 		htmlFormat = HTMLFormat
 
+		projectedJSONType = JSONFormat
+
 		noExtDelimFormat = Format{
 			Name:      "NEM",
 			MediaType: noExtNoDelimMediaType,
@@ -53,6 +55,8 @@ func TestLayout(t *testing.T) {
 		}
 	)
 
+	projectedJSONType.Projected = true
+
 	for _, this := range []struct {
 		name             string
 		layoutDescriptor LayoutDescriptor
@@ -884,6 +888,16 @@ func TestLayout(t *testing.T) {
 				"_default/single.json",
 			},
 		},
+		{
+			"Page projected",
+			LayoutDescriptor{Kind: "page"},
+			"", projectedJSONType,
+			[]string{
+				"_default/single.json.json",
+				"_default/single.json",
+				"_internal/_default/projection.json",
+			},
+		},
 		{
 			"Reserved section, shortcodes",
 			LayoutDescriptor{Kind: "section", Section: "shortcodes", Type: "shortcodes"},
@@ -932,6 +946,21 @@ func TestLayout(t *testing.T) {
 				"404.html",
 			},
 		},
+		{
+			"404, HTML section",
+			LayoutDescriptor{Kind: "404", Section: "docs", Lang: "de"},
+			"", htmlFormat,
+			[]string{
+				"docs/404.de.html.html",
+				"docs/404.html.html",
+				"docs/404.de.html",
+				"docs/404.html",
+				"404.de.html.html",
+				"404.html.html",
+				"404.de.html",
+				"404.html",
+			},
+		},
 		{
 			"404, HTML baseof",
 			LayoutDescriptor{Kind: "404", Baseof: true},
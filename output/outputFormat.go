@@ -38,6 +38,15 @@ type Format struct {
 	// Must be set to a value when there are two or more conflicting mediatype for the same resource.
 	Path string `json:"path"`
 
+	// Enable to put Path before the sections of list pages, as for regular
+	// pages, e.g. /api/posts/ instead of /posts/api/.
+	PathBeforeSections bool `json:"pathBeforeSections"`
+
+	// Projected is set for the JSON output formats with a projection in the
+	// site config. Pages without a template for such a format are rendered
+	// from the projection.
+	Projected bool `json:"-"`
+
 	// The base output file name used when not using "ugly URLs", defaults to "index".
 	BaseName string `json:"baseName"`
 
@@ -62,6 +71,19 @@ type Format struct {
 	// HTML, AMP etc. This is used to decide when to create alias redirects etc.
 	IsHTML bool `json:"isHTML"`
 
+	// IsEmail marks a HTML format for email. The CSS in its style elements
+	// is inlined into style attributes, and its URLs are made absolute.
+	IsEmail bool `json:"isEmail"`
+
+	// IsLite marks a HTML format as a lightweight variant of a page, with
+	// scripts and embeds removed. See the lite configuration.
+	IsLite bool `json:"isLite"`
+
+	// IsPrint marks a HTML format for print. The IDs and links in pages
+	// combined into one document are made unique, and its headings can be
+	// numbered and listed in a table of contents. See the printdoc package.
+	IsPrint bool `json:"isPrint"`
+
 	// Enable to ignore the global uglyURLs setting.
 	NoUgly bool `json:"noUgly"`
 
@@ -80,6 +102,22 @@ type Format struct {
 	// behaviour is wanted.
 	Permalinkable bool `json:"permalinkable"`
 
+	// Enable to write the rendered output to disk while the template executes,
+	// without holding it all in memory. This is useful for very large outputs,
+	// e.g. JSON or CSV exports. Any post processing needing the complete
+	// output (minification, URL canonicalization) is skipped for this format.
+	Stream bool `json:"stream"`
+
+	// Enable to list the URLs of this output format in the sitemap. This can
+	// be overridden per page with the sitemap.formats front matter setting.
+	// If none of a page's output formats has this set, the first is listed.
+	Sitemap bool `json:"sitemap"`
+
+	// Enable to keep this output format out of search engine indexes. Its
+	// URLs are never listed in the sitemap, and the built-in robots.txt
+	// template disallows them.
+	NoIndex bool `json:"noIndex"`
+
 	// Setting this to a non-zero value will be used as the first sort criteria.
 	Weight int `json:"weight"`
 }
@@ -106,6 +144,17 @@ var (
 		Rel:         "alternate",
 	}
 
+	ContentAPIFormat = Format{
+		Name:               "ContentAPI",
+		MediaType:          media.JSONType,
+		BaseName:           "index",
+		Path:               "api",
+		PathBeforeSections: true,
+		IsPlainText:        true,
+		NotAlternative:     true,
+		Rel:                "alternate",
+	}
+
 	CSSFormat = Format{
 		Name:           "CSS",
 		MediaType:      media.CSSType,
@@ -122,6 +171,55 @@ var (
 		Rel:         "alternate",
 	}
 
+	EmailFormat = Format{
+		Name:           "Email",
+		MediaType:      media.HTMLType,
+		BaseName:       "email",
+		Rel:            "alternate",
+		IsHTML:         true,
+		IsEmail:        true,
+		NoUgly:         true,
+		NotAlternative: true,
+		NoIndex:        true,
+	}
+
+	// LiteFormat is a lightweight variant of the HTML pages.
+	LiteFormat = Format{
+		Name:      "Lite",
+		MediaType: media.HTMLType,
+		BaseName:  "index",
+		Path:      "lite",
+		Rel:       "alternate",
+		IsHTML:    true,
+		IsLite:    true,
+	}
+
+	// PrintFormat combines a page, or the pages in a section, into one
+	// document for print.
+	PrintFormat = Format{
+		Name:           "Print",
+		MediaType:      media.HTMLType,
+		BaseName:       "print",
+		Rel:            "alternate",
+		IsHTML:         true,
+		IsPrint:        true,
+		NoUgly:         true,
+		NotAlternative: true,
+		NoIndex:        true,
+	}
+
+	// EmailTextFormat is the plain text alternative to EmailFormat.
+	EmailTextFormat = Format{
+		Name:           "EmailText",
+		MediaType:      media.TextType,
+		BaseName:       "email",
+		Rel:            "alternate",
+		IsPlainText:    true,
+		NoUgly:         true,
+		NotAlternative: true,
+		NoIndex:        true,
+	}
+
 	HTMLFormat = Format{
 		Name:          "HTML",
 		MediaType:     media.HTMLType,
@@ -129,6 +227,7 @@ var (
 		Rel:           "canonical",
 		IsHTML:        true,
 		Permalinkable: true,
+		Sitemap:       true,
 
 		// Weight will be used as first sort criteria. HTML will, by default,
 		// be rendered first, but set it to 10 so it's easy to put one above it.
@@ -152,6 +251,14 @@ var (
 		Rel:            "manifest",
 	}
 
+	LLMsTxtFormat = Format{
+		Name:        "LLMS",
+		MediaType:   media.TextType,
+		BaseName:    "llms",
+		IsPlainText: true,
+		Rel:         "alternate",
+	}
+
 	RobotsTxtFormat = Format{
 		Name:        "ROBOTS",
 		MediaType:   media.TextType,
@@ -168,6 +275,15 @@ var (
 		Rel:       "alternate",
 	}
 
+	SearchIndexFormat = Format{
+		Name:           "SearchIndex",
+		MediaType:      media.JSONType,
+		BaseName:       "searchindex",
+		IsPlainText:    true,
+		NotAlternative: true,
+		Rel:            "alternate",
+	}
+
 	SitemapFormat = Format{
 		Name:      "Sitemap",
 		MediaType: media.XMLType,
@@ -181,13 +297,20 @@ var (
 var DefaultFormats = Formats{
 	AMPFormat,
 	CalendarFormat,
+	ContentAPIFormat,
 	CSSFormat,
 	CSVFormat,
+	EmailFormat,
+	EmailTextFormat,
 	HTMLFormat,
 	JSONFormat,
+	LiteFormat,
 	WebAppManifestFormat,
+	LLMsTxtFormat,
+	PrintFormat,
 	RobotsTxtFormat,
 	RSSFormat,
+	SearchIndexFormat,
 	SitemapFormat,
 }
 
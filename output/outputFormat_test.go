@@ -68,7 +68,16 @@ func TestDefaultTypes(t *testing.T) {
 	c.Assert(RSSFormat.NoUgly, qt.Equals, true)
 	c.Assert(CalendarFormat.IsHTML, qt.Equals, false)
 
-	c.Assert(len(DefaultFormats), qt.Equals, 10)
+	c.Assert(EmailFormat.Name, qt.Equals, "Email")
+	c.Assert(EmailFormat.MediaType, qt.Equals, media.HTMLType)
+	c.Assert(EmailFormat.IsHTML, qt.Equals, true)
+	c.Assert(EmailFormat.IsEmail, qt.Equals, true)
+	c.Assert(EmailFormat.NoIndex, qt.Equals, true)
+	c.Assert(EmailTextFormat.MediaType, qt.Equals, media.TextType)
+	c.Assert(EmailTextFormat.IsPlainText, qt.Equals, true)
+	c.Assert(EmailTextFormat.IsEmail, qt.Equals, false)
+
+	c.Assert(len(DefaultFormats), qt.Equals, 17)
 
 }
 
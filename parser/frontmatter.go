@@ -14,11 +14,14 @@
 package parser
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"io"
+	"time"
 
 	"github.com/gohugoio/hugo/parser/metadecoders"
+	"github.com/gohugoio/hugo/parser/pageparser"
 
 	"github.com/BurntSushi/toml"
 
@@ -105,3 +108,54 @@ func InterfaceToFrontMatter(in interface{}, format metadecoders.Format, w io.Wri
 		return InterfaceToConfig(in, format, w)
 	}
 }
+
+// ConvertFrontMatter converts the front matter in the content read from r to
+// format. It returns nil if there is no front matter, if the front matter is
+// already in format or if it's in a format we cannot write, e.g. Org mode.
+// Any comments in converted front matter are lost.
+func ConvertFrontMatter(r io.Reader, format metadecoders.Format) ([]byte, error) {
+	pf, err := pageparser.ParseFrontMatterAndContent(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if !IsConvertibleFrontMatter(pf) || pf.FrontMatterFormat == format {
+		return nil, nil
+	}
+
+	return FrontMatterToContent(pf, format)
+}
+
+// IsConvertibleFrontMatter reports whether pf has front matter in a format
+// that can be converted to another.
+func IsConvertibleFrontMatter(pf pageparser.ContentFrontMatter) bool {
+	switch pf.FrontMatterFormat {
+	case metadecoders.JSON, metadecoders.TOML, metadecoders.YAML:
+		return true
+	default:
+		return false
+	}
+}
+
+// FrontMatterToContent returns the front matter in pf, written in format,
+// followed by the content in pf.
+func FrontMatterToContent(pf pageparser.ContentFrontMatter, format metadecoders.Format) ([]byte, error) {
+	// better handling of dates in formats that don't have support for them
+	if pf.FrontMatterFormat == metadecoders.JSON || pf.FrontMatterFormat == metadecoders.YAML || pf.FrontMatterFormat == metadecoders.TOML {
+		for k, v := range pf.FrontMatter {
+			switch vv := v.(type) {
+			case time.Time:
+				pf.FrontMatter[k] = vv.Format(time.RFC3339)
+			}
+		}
+	}
+
+	var b bytes.Buffer
+	if err := InterfaceToFrontMatter(pf.FrontMatter, format, &b); err != nil {
+		return nil, err
+	}
+
+	b.Write(pf.Content)
+
+	return b.Bytes(), nil
+}
@@ -0,0 +1,488 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gohugoio/hugo/parser/metadecoders"
+	"github.com/gohugoio/hugo/parser/pageparser"
+)
+
+// FrontMatterEditOp is the operation in a FrontMatterEdit.
+type FrontMatterEditOp int
+
+const (
+	// FrontMatterSet sets Key to Value.
+	FrontMatterSet FrontMatterEditOp = iota
+
+	// FrontMatterDelete deletes Key.
+	FrontMatterDelete
+
+	// FrontMatterRename renames Key to NewKey.
+	FrontMatterRename
+
+	// FrontMatterMove moves the top level Key into the top level map NewKey,
+	// e.g. author into params.
+	FrontMatterMove
+)
+
+// FrontMatterEdit describes a change to a front matter key. Nested keys are
+// separated by a ".", e.g. "params.author", and renamed keys keep their
+// parent. Keys are matched case insensitively.
+type FrontMatterEdit struct {
+	Op     FrontMatterEditOp
+	Key    string
+	Value  interface{}
+	NewKey string
+}
+
+var (
+	yamlKeyRe = regexp.MustCompile(`^(["']?)([^\s:"'#-][^:"'#]*?)(["']?)\s*:(\s|$)`)
+	tomlKeyRe = regexp.MustCompile(`^\s*(["']?)([^\s="'#\[]+)(["']?)\s*=`)
+
+	// The first part of the key of a TOML table, e.g. "params" in
+	// [params.author] or [[params.links]].
+	tomlTableRe = regexp.MustCompile(`(?s)^(\s*\[\[?\s*)([A-Za-z0-9_-]+)(\s*[.\]].*)$`)
+)
+
+// EditFrontMatter applies edits to the front matter in content and returns
+// the new content and whether it changed.
+//
+// YAML and TOML front matter is edited line by line, which preserves the
+// comments and the order of the keys not edited. Other front matter, and
+// values that cannot be written on top level lines, e.g. maps in TOML, are
+// re-encoded, which loses any comments.
+func EditFrontMatter(content []byte, edits ...FrontMatterEdit) ([]byte, bool, error) {
+	for _, edit := range edits {
+		if edit.Key == "" || (edit.Op == FrontMatterRename && edit.NewKey == "") {
+			return nil, false, errors.New("front matter key must not be empty")
+		}
+		if edit.Op == FrontMatterRename && strings.Contains(edit.NewKey, ".") {
+			return nil, false, fmt.Errorf("cannot rename %q to %q: the new key must not contain a \".\"", edit.Key, edit.NewKey)
+		}
+		if edit.Op == FrontMatterMove && (edit.NewKey == "" || strings.Contains(edit.Key, ".") || strings.Contains(edit.NewKey, ".")) {
+			return nil, false, fmt.Errorf("cannot move %q into %q: both keys must be top level keys", edit.Key, edit.NewKey)
+		}
+	}
+
+	psr, err := pageparser.Parse(bytes.NewReader(content), pageparser.Config{})
+	if err != nil {
+		return nil, false, err
+	}
+
+	var (
+		fm     pageparser.Item
+		format metadecoders.Format
+	)
+
+	iter := psr.Iterator()
+	iter.PeekWalk(func(item pageparser.Item) bool {
+		if item.IsFrontMatter() {
+			fm = item
+			format = pageparser.FormatFromFrontMatterType(item.Type)
+			return false
+		}
+		return true
+	})
+
+	if format == "" {
+		// No front matter. Create it using the default format if needed.
+		var b bytes.Buffer
+		b.WriteString(tomlDelimLf)
+		b.WriteString(tomlDelimLf)
+		b.Write(content)
+		edited, changed, err := EditFrontMatter(b.Bytes(), edits...)
+		if err != nil || !changed {
+			return content, false, err
+		}
+		return edited, true, nil
+	}
+
+	source := string(fm.Val)
+
+	e := &frontMatterEditor{format: format, lines: strings.SplitAfter(source, "\n")}
+	if format == metadecoders.YAML || format == metadecoders.TOML {
+		for _, edit := range edits {
+			if err := e.apply(edit); err != nil {
+				if err != errNotLineEditable {
+					return nil, false, err
+				}
+				e.lines = nil
+				break
+			}
+		}
+	}
+
+	var edited string
+	if e.lines != nil && (format == metadecoders.YAML || format == metadecoders.TOML) {
+		edited = strings.Join(e.lines, "")
+	} else {
+		edited, err = reencodeFrontMatter(source, format, edits)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	if edited == source {
+		return content, false, nil
+	}
+
+	var b bytes.Buffer
+	b.Write(content[:fm.Pos])
+	b.WriteString(edited)
+	b.Write(content[fm.Pos+len(fm.Val):])
+
+	return b.Bytes(), true, nil
+}
+
+var errNotLineEditable = errors.New("front matter cannot be edited line by line")
+
+type frontMatterEditor struct {
+	format metadecoders.Format
+	lines  []string
+}
+
+func (e *frontMatterEditor) apply(edit FrontMatterEdit) error {
+	if strings.Contains(edit.Key, ".") {
+		// Nested keys.
+		return errNotLineEditable
+	}
+
+	start, end := e.find(edit.Key)
+
+	switch edit.Op {
+	case FrontMatterDelete:
+		if start != -1 {
+			e.lines = append(e.lines[:start], e.lines[end:]...)
+		}
+	case FrontMatterRename:
+		if e.format == metadecoders.TOML {
+			e.renameTables(edit.Key, edit.NewKey)
+		}
+		if start == -1 {
+			return nil
+		}
+		if s, _ := e.find(edit.NewKey); s != -1 && !strings.EqualFold(edit.Key, edit.NewKey) {
+			return fmt.Errorf("cannot rename %q to %q: key already exists", edit.Key, edit.NewKey)
+		}
+		line := e.lines[start]
+		i := strings.Index(line, e.keyIn(line))
+		e.lines[start] = line[:i] + edit.NewKey + line[i+len(e.keyIn(line)):]
+	case FrontMatterMove:
+		if e.format == metadecoders.TOML {
+			e.renameTables(edit.Key, edit.NewKey+"."+edit.Key)
+		}
+		if start == -1 {
+			return nil
+		}
+		moved := append([]string(nil), e.lines[start:end]...)
+		if last := moved[len(moved)-1]; !strings.HasSuffix(last, "\n") {
+			moved[len(moved)-1] = last + "\n"
+		}
+		e.lines = append(e.lines[:start], e.lines[end:]...)
+		return e.insertInto(edit.NewKey, e.keyIn(moved[0]), moved)
+	case FrontMatterSet:
+		key := edit.Key
+		if start != -1 {
+			key = e.keyIn(e.lines[start])
+		}
+		var b bytes.Buffer
+		if err := InterfaceToConfig(map[string]interface{}{key: edit.Value}, e.format, &b); err != nil {
+			return err
+		}
+		entry := b.String()
+		if e.format == metadecoders.TOML && strings.HasPrefix(strings.TrimSpace(entry), "[") {
+			// A table.
+			return errNotLineEditable
+		}
+		if start == -1 {
+			start, end = e.insertPos(), e.insertPos()
+		}
+		lines := append([]string{entry}, e.lines[end:]...)
+		e.lines = append(e.lines[:start], lines...)
+	}
+
+	return nil
+}
+
+// keyIn returns the key defined in line, or an empty string if none.
+func (e *frontMatterEditor) keyIn(line string) string {
+	re := yamlKeyRe
+	if e.format == metadecoders.TOML {
+		re = tomlKeyRe
+	}
+	m := re.FindStringSubmatch(line)
+	if m == nil || m[1] != m[3] {
+		return ""
+	}
+	return m[2]
+}
+
+// isTableHeader reports whether line starts a TOML table.
+func (e *frontMatterEditor) isTableHeader(line string) bool {
+	return e.format == metadecoders.TOML && strings.HasPrefix(strings.TrimSpace(line), "[") && e.keyIn(line) == ""
+}
+
+// isContinuation reports whether line belongs to the value of the key on a
+// previous line.
+func (e *frontMatterEditor) isContinuation(line string) bool {
+	if e.format == metadecoders.YAML {
+		return strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") || strings.HasPrefix(line, "-")
+	}
+	return e.keyIn(line) == "" && !e.isTableHeader(line) && !strings.HasPrefix(strings.TrimSpace(line), "#")
+}
+
+// find returns the range of the lines defining the top level key, or -1 if
+// not found.
+func (e *frontMatterEditor) find(key string) (int, int) {
+	for i, line := range e.lines {
+		if e.isTableHeader(line) {
+			// The rest of the keys are in tables.
+			break
+		}
+		if e.format == metadecoders.YAML && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			continue
+		}
+		if !strings.EqualFold(e.keyIn(line), key) {
+			continue
+		}
+
+		end := i + 1
+		for j := i + 1; j < len(e.lines); j++ {
+			l := e.lines[j]
+			if strings.TrimSpace(l) == "" {
+				continue
+			}
+			if !e.isContinuation(l) {
+				break
+			}
+			end = j + 1
+		}
+
+		return i, end
+	}
+
+	return -1, -1
+}
+
+// renameTables renames the TOML tables of the top level key, e.g. [author]
+// and [author.social].
+func (e *frontMatterEditor) renameTables(key, newKey string) {
+	for i, line := range e.lines {
+		if !e.isTableHeader(line) {
+			continue
+		}
+		if m := tomlTableRe.FindStringSubmatch(line); m != nil && strings.EqualFold(m[2], key) {
+			e.lines[i] = m[1] + newKey + m[3]
+		}
+	}
+}
+
+// insertInto inserts the lines defining key into the top level map
+// mapKey, which is created if not set.
+func (e *frontMatterEditor) insertInto(mapKey, key string, lines []string) error {
+	if e.format == metadecoders.TOML {
+		if start, _ := e.find(mapKey); start != -1 {
+			// Set as a key in the root table, e.g. params = { a = 1 }.
+			return errNotLineEditable
+		}
+		for i, line := range e.lines {
+			if m := tomlTableRe.FindStringSubmatch(line); m != nil && e.isTableHeader(line) && strings.EqualFold(m[2], mapKey) && isTableEnd(m[1], m[3]) {
+				for _, l := range e.lines[i+1:] {
+					if e.isTableHeader(l) {
+						break
+					}
+					if strings.EqualFold(e.keyIn(l), key) {
+						return fmt.Errorf("cannot move %q into %q: key already exists", key, mapKey)
+					}
+				}
+				e.insert(i+1, lines...)
+				return nil
+			}
+		}
+		pos := len(e.lines)
+		if pos > 0 && e.lines[pos-1] == "" {
+			pos--
+		}
+		if pos > 0 && !strings.HasSuffix(e.lines[pos-1], "\n") {
+			e.lines[pos-1] += "\n"
+		}
+		e.insert(pos, append([]string{"\n", "[" + mapKey + "]\n"}, lines...)...)
+		return nil
+	}
+
+	start, end := e.find(mapKey)
+	if start == -1 {
+		pos := e.insertPos()
+		e.insert(pos, append([]string{mapKey + ":\n"}, indentLines(lines, "  ")...)...)
+		return nil
+	}
+
+	header := e.lines[start]
+	value := header[strings.Index(header, ":")+1:]
+	if i := strings.Index(value, "#"); i != -1 {
+		value = value[:i]
+	}
+	if strings.TrimSpace(value) != "" {
+		// Not a block map, e.g. params: {a: 1}.
+		return errNotLineEditable
+	}
+
+	indent := "  "
+	for _, l := range e.lines[start+1 : end] {
+		if strings.TrimSpace(l) != "" {
+			indent = l[:len(l)-len(strings.TrimLeft(l, " \t"))]
+			break
+		}
+	}
+	for _, l := range e.lines[start+1 : end] {
+		if strings.HasPrefix(l, indent) && strings.EqualFold(e.keyIn(l[len(indent):]), key) {
+			return fmt.Errorf("cannot move %q into %q: key already exists", key, mapKey)
+		}
+	}
+
+	e.insert(end, indentLines(lines, indent)...)
+
+	return nil
+}
+
+// isTableEnd reports whether a table header ends after its first key part,
+// e.g. [params], but not [params.author] or [[params]].
+func isTableEnd(start, rest string) bool {
+	return !strings.Contains(start, "[[") && strings.HasPrefix(strings.TrimSpace(rest), "]") && !strings.HasPrefix(strings.TrimSpace(rest), "]]")
+}
+
+func (e *frontMatterEditor) insert(pos int, lines ...string) {
+	e.lines = append(e.lines[:pos], append(lines, e.lines[pos:]...)...)
+}
+
+func indentLines(lines []string, indent string) []string {
+	indented := make([]string, len(lines))
+	for i, l := range lines {
+		if strings.TrimSpace(l) != "" {
+			l = indent + l
+		}
+		indented[i] = l
+	}
+	return indented
+}
+
+// insertPos returns the line index to insert new top level keys at.
+func (e *frontMatterEditor) insertPos() int {
+	for i, line := range e.lines {
+		if e.isTableHeader(line) {
+			return i
+		}
+	}
+	if n := len(e.lines); n > 0 && e.lines[n-1] == "" {
+		// The empty string after the last newline.
+		return n - 1
+	}
+	return len(e.lines)
+}
+
+func reencodeFrontMatter(source string, format metadecoders.Format, edits []FrontMatterEdit) (string, error) {
+	if format == metadecoders.ORG {
+		return "", fmt.Errorf("editing %s front matter is not supported", format)
+	}
+
+	m, err := metadecoders.Default.UnmarshalToMap([]byte(source), format)
+	if err != nil {
+		return "", err
+	}
+
+	for _, edit := range edits {
+		parent, key := m, edit.Key
+		if parts := strings.Split(edit.Key, "."); len(parts) > 1 {
+			for _, p := range parts[:len(parts)-1] {
+				k := findKey(parent, p)
+				child, ok := parent[k].(map[string]interface{})
+				if !ok {
+					if edit.Op != FrontMatterSet {
+						parent = nil
+						break
+					}
+					child = make(map[string]interface{})
+					parent[k] = child
+				}
+				parent = child
+			}
+			key = parts[len(parts)-1]
+		}
+		if parent == nil {
+			continue
+		}
+
+		k := findKey(parent, key)
+
+		switch edit.Op {
+		case FrontMatterSet:
+			parent[k] = edit.Value
+		case FrontMatterDelete:
+			delete(parent, k)
+		case FrontMatterMove:
+			v, found := parent[k]
+			if !found {
+				continue
+			}
+			target, ok := parent[findKey(parent, edit.NewKey)].(map[string]interface{})
+			if !ok {
+				if _, found := parent[findKey(parent, edit.NewKey)]; found {
+					return "", fmt.Errorf("cannot move %q into %q: not a map", edit.Key, edit.NewKey)
+				}
+				target = make(map[string]interface{})
+				parent[edit.NewKey] = target
+			}
+			if _, found := target[findKey(target, k)]; found {
+				return "", fmt.Errorf("cannot move %q into %q: key already exists", edit.Key, edit.NewKey)
+			}
+			delete(parent, k)
+			target[k] = v
+		case FrontMatterRename:
+			v, found := parent[k]
+			if !found {
+				continue
+			}
+			newKey := edit.NewKey
+			if _, found := parent[findKey(parent, newKey)]; found && !strings.EqualFold(k, newKey) {
+				return "", fmt.Errorf("cannot rename %q to %q: key already exists", edit.Key, edit.NewKey)
+			}
+			delete(parent, k)
+			parent[newKey] = v
+		}
+	}
+
+	var b bytes.Buffer
+	if err := InterfaceToConfig(m, format, &b); err != nil {
+		return "", err
+	}
+
+	return b.String(), nil
+}
+
+// findKey returns the key in m matching key case insensitively, or key if
+// not found.
+func findKey(m map[string]interface{}, key string) string {
+	for k := range m {
+		if strings.EqualFold(k, key) {
+			return k
+		}
+	}
+	return key
+}
@@ -0,0 +1,157 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestEditFrontMatter(t *testing.T) {
+	c := qt.New(t)
+
+	yamlContent := `---
+# The title.
+title: "My Post"
+tags:
+- a
+- b
+draft: true
+---
+Content.
+`
+
+	tomlContent := `+++
+# The title.
+title = "My Post"
+tags = [
+  "a",
+  "b",
+]
+
+[params]
+draft = true
++++
+Content.
+`
+
+	for _, test := range []struct {
+		name     string
+		content  string
+		edits    []FrontMatterEdit
+		expected string
+		changed  bool
+	}{
+		{
+			"YAML set existing", yamlContent,
+			[]FrontMatterEdit{{Op: FrontMatterSet, Key: "TITLE", Value: "New Title"}},
+			"---\n# The title.\ntitle: New Title\ntags:\n- a\n- b\ndraft: true\n---\nContent.\n", true,
+		},
+		{
+			"YAML set new", yamlContent,
+			[]FrontMatterEdit{{Op: FrontMatterSet, Key: "weight", Value: 10}},
+			"---\n# The title.\ntitle: \"My Post\"\ntags:\n- a\n- b\ndraft: true\nweight: 10\n---\nContent.\n", true,
+		},
+		{
+			"YAML delete list", yamlContent,
+			[]FrontMatterEdit{{Op: FrontMatterDelete, Key: "tags"}},
+			"---\n# The title.\ntitle: \"My Post\"\ndraft: true\n---\nContent.\n", true,
+		},
+		{
+			"YAML rename", yamlContent,
+			[]FrontMatterEdit{{Op: FrontMatterRename, Key: "tags", NewKey: "categories"}},
+			"---\n# The title.\ntitle: \"My Post\"\ncategories:\n- a\n- b\ndraft: true\n---\nContent.\n", true,
+		},
+		{
+			"YAML delete missing", yamlContent,
+			[]FrontMatterEdit{{Op: FrontMatterDelete, Key: "nope"}},
+			yamlContent, false,
+		},
+		{
+			"TOML set new before tables", tomlContent,
+			[]FrontMatterEdit{{Op: FrontMatterSet, Key: "weight", Value: 10}},
+			"+++\n# The title.\ntitle = \"My Post\"\ntags = [\n  \"a\",\n  \"b\",\n]\n\nweight = 10\n[params]\ndraft = true\n+++\nContent.\n", true,
+		},
+		{
+			"TOML delete multiline array", tomlContent,
+			[]FrontMatterEdit{{Op: FrontMatterDelete, Key: "tags"}},
+			"+++\n# The title.\ntitle = \"My Post\"\n\n[params]\ndraft = true\n+++\nContent.\n", true,
+		},
+		{
+			"TOML set nested", tomlContent,
+			[]FrontMatterEdit{{Op: FrontMatterSet, Key: "params.draft", Value: false}},
+			"+++\ntags = [\"a\", \"b\"]\ntitle = \"My Post\"\n\n[params]\n  draft = false\n+++\nContent.\n", true,
+		},
+		{
+			"YAML move", yamlContent,
+			[]FrontMatterEdit{{Op: FrontMatterMove, Key: "tags", NewKey: "params"}, {Op: FrontMatterMove, Key: "draft", NewKey: "params"}},
+			"---\n# The title.\ntitle: \"My Post\"\nparams:\n  tags:\n  - a\n  - b\n  draft: true\n---\nContent.\n", true,
+		},
+		{
+			"YAML move into existing", "---\ntitle: \"My Post\"\nauthor: Jane # The author.\nparams:\n    # Existing.\n    color: red\n---\n",
+			[]FrontMatterEdit{{Op: FrontMatterMove, Key: "author", NewKey: "params"}},
+			"---\ntitle: \"My Post\"\nparams:\n    # Existing.\n    color: red\n    author: Jane # The author.\n---\n", true,
+		},
+		{
+			"YAML move into flow map", "---\nauthor: Jane\nparams: {color: red}\n---\n",
+			[]FrontMatterEdit{{Op: FrontMatterMove, Key: "author", NewKey: "params"}},
+			"---\nparams:\n  author: Jane\n  color: red\n---\n", true,
+		},
+		{
+			"TOML move", tomlContent,
+			[]FrontMatterEdit{{Op: FrontMatterMove, Key: "tags", NewKey: "params"}},
+			"+++\n# The title.\ntitle = \"My Post\"\n\n[params]\ntags = [\n  \"a\",\n  \"b\",\n]\ndraft = true\n+++\nContent.\n", true,
+		},
+		{
+			"TOML move new params", "+++\ntitle = \"My Post\"\nauthor = \"Jane\"\n\n[social]\ntwitter = \"jane\"\n+++\n",
+			[]FrontMatterEdit{{Op: FrontMatterMove, Key: "author", NewKey: "params"}, {Op: FrontMatterMove, Key: "social", NewKey: "params"}},
+			"+++\ntitle = \"My Post\"\n\n[params.social]\ntwitter = \"jane\"\n\n[params]\nauthor = \"Jane\"\n+++\n", true,
+		},
+		{
+			"TOML rename table", "+++\ntitle = \"My Post\"\n[_build] # Build options.\nlist = \"never\"\n+++\n",
+			[]FrontMatterEdit{{Op: FrontMatterRename, Key: "_build", NewKey: "build"}},
+			"+++\ntitle = \"My Post\"\n[build] # Build options.\nlist = \"never\"\n+++\n", true,
+		},
+		{
+			"JSON", "{\n\"title\": \"My Post\"\n}\nContent.\n",
+			[]FrontMatterEdit{{Op: FrontMatterRename, Key: "title", NewKey: "linkTitle"}},
+			"{\n   \"linkTitle\": \"My Post\"\n}\nContent.\n", true,
+		},
+		{
+			"No front matter", "Content.\n",
+			[]FrontMatterEdit{{Op: FrontMatterSet, Key: "title", Value: "My Post"}},
+			"+++\ntitle = \"My Post\"\n+++\nContent.\n", true,
+		},
+		{
+			"No front matter, nothing to delete", "Content.\n",
+			[]FrontMatterEdit{{Op: FrontMatterDelete, Key: "title"}},
+			"Content.\n", false,
+		},
+	} {
+		test := test
+		c.Run(test.name, func(c *qt.C) {
+			b, changed, err := EditFrontMatter([]byte(test.content), test.edits...)
+			c.Assert(err, qt.IsNil)
+			c.Assert(string(b), qt.Equals, test.expected)
+			c.Assert(changed, qt.Equals, test.changed)
+		})
+	}
+
+	_, _, err := EditFrontMatter([]byte(yamlContent), FrontMatterEdit{Op: FrontMatterRename, Key: "tags", NewKey: "title"})
+	c.Assert(err, qt.ErrorMatches, `cannot rename "tags" to "title": key already exists`)
+
+	_, _, err = EditFrontMatter([]byte("---\nauthor: Jane\nparams:\n  author: John\n---\n"), FrontMatterEdit{Op: FrontMatterMove, Key: "author", NewKey: "params"})
+	c.Assert(err, qt.ErrorMatches, `cannot move "author" into "params": key already exists`)
+}
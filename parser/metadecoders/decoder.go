@@ -17,7 +17,9 @@ import (
 	"bytes"
 	"encoding/csv"
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
+	"io"
 	"regexp"
 	"strings"
 
@@ -29,6 +31,7 @@ import (
 	"github.com/spf13/afero"
 	"github.com/spf13/cast"
 	jww "github.com/spf13/jwalterweatherman"
+	"golang.org/x/net/html/charset"
 	yaml "gopkg.in/yaml.v2"
 )
 
@@ -169,6 +172,8 @@ func (d Decoder) UnmarshalTo(data []byte, f Format, v interface{}) error {
 		}
 	case CSV:
 		return d.unmarshalCSV(data, v)
+	case XML:
+		err = d.unmarshalXML(data, v)
 
 	default:
 		return errors.Errorf("unmarshal of format %q is not supported", f)
@@ -202,6 +207,96 @@ func (d Decoder) unmarshalCSV(data []byte, v interface{}) error {
 	return nil
 }
 
+// unmarshalXML unmarshals the XML document in data into a map with the
+// child elements of the root element, keyed by their local names. The
+// attributes are stored with a "-" prefix, and the text of elements with
+// attributes or child elements is stored as "#text". Repeated elements
+// become slices, and elements with text only become strings.
+func (d Decoder) unmarshalXML(data []byte, v interface{}) error {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	dec.CharsetReader = charset.NewReaderLabel
+
+	var root map[string]interface{}
+	for root == nil {
+		tok, err := dec.Token()
+		if err != nil {
+			if err == io.EOF {
+				return errors.New("no root element")
+			}
+			return err
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			el, err := decodeXMLElement(dec, start)
+			if err != nil {
+				return err
+			}
+			if s, ok := el.(string); ok {
+				el = map[string]interface{}{"#text": s}
+			}
+			root = el.(map[string]interface{})
+		}
+	}
+
+	switch v.(type) {
+	case *map[string]interface{}:
+		*v.(*map[string]interface{}) = root
+	case *interface{}:
+		*v.(*interface{}) = root
+	default:
+		return errors.Errorf("XML cannot be unmarshaled into %T", v)
+	}
+
+	return nil
+}
+
+func decodeXMLElement(dec *xml.Decoder, start xml.StartElement) (interface{}, error) {
+	m := make(map[string]interface{})
+	for _, attr := range start.Attr {
+		name := attr.Name.Local
+		if attr.Name.Space == "xmlns" {
+			name = "xmlns:" + name
+		}
+		m["-"+name] = attr.Value
+	}
+
+	var text strings.Builder
+
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			child, err := decodeXMLElement(dec, tok)
+			if err != nil {
+				return nil, err
+			}
+			name := tok.Name.Local
+			switch existing := m[name].(type) {
+			case nil:
+				m[name] = child
+			case []interface{}:
+				m[name] = append(existing, child)
+			default:
+				m[name] = []interface{}{existing, child}
+			}
+		case xml.CharData:
+			text.Write(tok)
+		case xml.EndElement:
+			s := strings.TrimSpace(text.String())
+			if len(m) == 0 {
+				return s, nil
+			}
+			if s != "" {
+				m["#text"] = s
+			}
+			return m, nil
+		}
+	}
+}
+
 func parseORGDate(s string) string {
 	r := regexp.MustCompile(`[<\[](\d{4}-\d{2}-\d{2}) .*[>\]]`)
 	if m := r.FindStringSubmatch(s); m != nil {
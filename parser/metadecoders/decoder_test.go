@@ -74,8 +74,28 @@ func TestUnmarshalToInterface(t *testing.T) {
 		{`a: "b"`, YAML, expect},
 		{`a,b,c`, CSV, [][]string{{"a", "b", "c"}}},
 		{"a: Easy!\nb:\n  c: 2\n  d: [3, 4]", YAML, map[string]interface{}{"a": "Easy!", "b": map[string]interface{}{"c": 2, "d": []interface{}{3, 4}}}},
+		{`<root><a>b</a></root>`, XML, expect},
+		{`<?xml version="1.0" encoding="ISO-8859-1"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9" xmlns:xhtml="http://www.w3.org/1999/xhtml">
+  <url><loc>https://example.org/</loc><xhtml:link rel="alternate" hreflang="fr" href="https://example.org/fr/"/></url>
+  <url><loc>https://example.org/caf` + "\xe9" + `/</loc></url>
+  <note>Text <![CDATA[with <markup>]]><b>bold</b></note>
+</urlset>`, XML, map[string]interface{}{
+			"-xmlns":       "http://www.sitemaps.org/schemas/sitemap/0.9",
+			"-xmlns:xhtml": "http://www.w3.org/1999/xhtml",
+			"url": []interface{}{
+				map[string]interface{}{
+					"loc":  "https://example.org/",
+					"link": map[string]interface{}{"-rel": "alternate", "-hreflang": "fr", "-href": "https://example.org/fr/"},
+				},
+				map[string]interface{}{"loc": "https://example.org/café/"},
+			},
+			"note": map[string]interface{}{"#text": "Text with <markup>", "b": "bold"},
+		}},
 		// errors
 		{`a = "`, TOML, false},
+		{`<a><b></a>`, XML, false},
+		{`no root`, XML, false},
 	} {
 		msg := qt.Commentf("%d: %s", i, test.format)
 		m, err := d.Unmarshal([]byte(test.data), test.format)
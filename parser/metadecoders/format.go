@@ -30,6 +30,7 @@ const (
 	TOML Format = "toml"
 	YAML Format = "yaml"
 	CSV  Format = "csv"
+	XML  Format = "xml"
 )
 
 // FormatFromString turns formatStr, typically a file extension without any ".",
@@ -51,6 +52,8 @@ func FormatFromString(formatStr string) Format {
 		return ORG
 	case "csv":
 		return CSV
+	case "xml":
+		return XML
 	}
 
 	return ""
@@ -68,10 +71,14 @@ func FormatFromMediaType(m media.Type) Format {
 	return ""
 }
 
-// FormatFromContentString tries to detect the format (JSON, YAML or TOML)
-// in the given string.
+// FormatFromContentString tries to detect the format (JSON, YAML, TOML,
+// CSV or XML) in the given string.
 // It return an empty string if no format could be detected.
 func (d Decoder) FormatFromContentString(data string) Format {
+	if strings.HasPrefix(strings.TrimSpace(data), "<") {
+		return XML
+	}
+
 	csvIdx := strings.IndexRune(data, d.Delimiter)
 	jsonIdx := strings.Index(data, "{")
 	yamlIdx := strings.Index(data, ":")
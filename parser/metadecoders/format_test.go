@@ -34,6 +34,7 @@ func TestFormatFromString(t *testing.T) {
 		{"config.toml", TOML},
 		{"tOMl", TOML},
 		{"org", ORG},
+		{"sitemap.xml", XML},
 		{"foo", ""},
 	} {
 		c.Assert(FormatFromString(test.s), qt.Equals, test.expect)
@@ -49,6 +50,7 @@ func TestFormatFromMediaType(t *testing.T) {
 		{media.JSONType, JSON},
 		{media.YAMLType, YAML},
 		{media.TOMLType, TOML},
+		{media.XMLType, XML},
 		{media.CalendarType, ""},
 	} {
 		c.Assert(FormatFromMediaType(test.m), qt.Equals, test.expect)
@@ -70,6 +72,8 @@ func TestFormatFromContentString(t *testing.T) {
 		{`foo:"bar"`, YAML},
 		{`{ "foo": "bar"`, JSON},
 		{`a,b,c"`, CSV},
+		{`<?xml version="1.0"?><a foo="bar"/>`, XML},
+		{"\n  <rss><channel/></rss>", XML},
 		{`asdfasdf`, Format("")},
 		{``, Format("")},
 	} {
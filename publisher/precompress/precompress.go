@@ -0,0 +1,472 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package precompress writes compressed siblings (e.g. index.html.gz) next
+// to the published files, so they can be served as-is by web servers
+// supporting precompressed files.
+package precompress
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gohugoio/hugo/common/hexec"
+	"github.com/gohugoio/hugo/common/maps"
+	"github.com/gohugoio/hugo/config"
+	"github.com/gohugoio/hugo/docshelper"
+	"github.com/gohugoio/hugo/helpers"
+	"github.com/gohugoio/hugo/output"
+	"github.com/gohugoio/hugo/parser"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+const precompressConfigKey = "precompress"
+
+// Config configures the precompression of the published files.
+type Config struct {
+	// The encodings to write, one or more of gzip and br.
+	Encodings []string
+
+	// External commands to write additional compressed siblings with.
+	Commands []CommandConfig
+
+	// The names of the output formats to precompress, e.g. HTML, JSON.
+	// If not set, all output formats will be precompressed.
+	Formats []string
+
+	// The file extensions of the processed resources and static files to
+	// precompress.
+	Extensions []string
+
+	// Files smaller than this (in bytes) will not be precompressed.
+	MinSize int
+}
+
+// CommandConfig configures an external command used to precompress the
+// published files.
+type CommandConfig struct {
+	// The extension of the compressed files written, e.g. ".zst".
+	Ext string
+
+	// The command to run, e.g. "zstd". It gets the file content on stdin and
+	// must write the compressed content to stdout.
+	Command string
+
+	// The arguments to the command, e.g. ["-19", "-c"].
+	Args []string
+}
+
+var defaultConfig = Config{
+	Extensions: []string{".css", ".html", ".js", ".json", ".svg", ".txt", ".xml"},
+	MinSize:    256,
+}
+
+// DecodeConfig creates a precompress Config from the given configuration.
+func DecodeConfig(cfg config.Provider) (conf Config, err error) {
+	conf = defaultConfig
+	conf.Extensions = nil
+
+	defer func() {
+		if conf.Extensions == nil {
+			conf.Extensions = append([]string(nil), defaultConfig.Extensions...)
+		}
+	}()
+
+	v := cfg.Get(precompressConfigKey)
+	if v == nil {
+		return
+	}
+
+	m := maps.ToStringMap(v)
+
+	if err = mapstructure.WeakDecode(m, &conf); err != nil {
+		return
+	}
+
+	for i, enc := range conf.Encodings {
+		enc = strings.ToLower(enc)
+		if _, found := encoders[enc]; !found {
+			err = errors.Errorf("%s: unsupported encoding %q", precompressConfigKey, enc)
+			return
+		}
+		conf.Encodings[i] = enc
+	}
+
+	for i, command := range conf.Commands {
+		if command.Command == "" {
+			err = errors.Errorf("%s: no command set", precompressConfigKey)
+			return
+		}
+		if !strings.HasPrefix(command.Ext, ".") || len(command.Ext) < 2 {
+			err = errors.Errorf("%s: invalid extension %q for command %q", precompressConfigKey, command.Ext, command.Command)
+			return
+		}
+		conf.Commands[i].Ext = strings.ToLower(command.Ext)
+	}
+
+	for i, ext := range conf.Extensions {
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		conf.Extensions[i] = strings.ToLower(ext)
+	}
+
+	return
+}
+
+type encoder struct {
+	ext       string
+	newWriter func(w io.Writer) (io.WriteCloser, error)
+}
+
+var encoders = map[string]encoder{
+	"gzip": {
+		ext: ".gz",
+		newWriter: func(w io.Writer) (io.WriteCloser, error) {
+			return gzip.NewWriterLevel(w, gzip.BestCompression)
+		},
+	},
+	"br": {
+		ext: ".br",
+		newWriter: func(w io.Writer) (io.WriteCloser, error) {
+			return brotli.NewWriterLevel(w, brotli.BestCompression), nil
+		},
+	},
+}
+
+// Client writes the compressed siblings of the published files. A nil
+// Client doesn't write anything.
+type Client struct {
+	conf     Config
+	encoders []encoder
+}
+
+// New creates a new Client from the given configuration. It returns nil if
+// precompression is not enabled. The external commands are run with the
+// exec and network policies of cfg applied.
+func New(cfg config.Provider) (*Client, error) {
+	conf, err := DecodeConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(conf.Encodings) == 0 && len(conf.Commands) == 0 {
+		return nil, nil
+	}
+
+	c := &Client{conf: conf}
+
+	for _, enc := range conf.Encodings {
+		c.encoders = append(c.encoders, encoders[enc])
+	}
+
+	for _, command := range conf.Commands {
+		command := command
+		c.encoders = append(c.encoders, encoder{
+			ext: command.Ext,
+			newWriter: func(w io.Writer) (io.WriteCloser, error) {
+				return newCommandWriter(cfg, command, w)
+			},
+		})
+	}
+
+	return c, nil
+}
+
+// EnabledForFormat reports whether the page outputs in f are precompressed.
+func (c *Client) EnabledForFormat(f output.Format) bool {
+	if c == nil {
+		return false
+	}
+
+	if len(c.conf.Formats) == 0 {
+		return true
+	}
+
+	for _, name := range c.conf.Formats {
+		if strings.EqualFold(name, f.Name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// EnabledForFile reports whether the resource or static file with the given
+// filename is precompressed.
+func (c *Client) EnabledForFile(filename string) bool {
+	if c == nil {
+		return false
+	}
+
+	ext := strings.ToLower(filepath.Ext(filename))
+	for _, e := range c.conf.Extensions {
+		if e == ext {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsCompressed reports whether filename is a compressed sibling written by c.
+func (c *Client) IsCompressed(filename string) bool {
+	if c == nil {
+		return false
+	}
+
+	ext := strings.ToLower(filepath.Ext(filename))
+	for _, enc := range c.encoders {
+		if enc.ext == ext {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NewWriter returns a writer that writes a compressed copy of everything
+// written to it, for every configured encoding, next to targetPath in fs
+// when closed. If less than MinSize bytes are written, any existing
+// compressed siblings are removed instead.
+func (c *Client) NewWriter(fs afero.Fs, targetPath string) io.WriteCloser {
+	return &writer{c: c, fs: fs, targetPath: targetPath}
+}
+
+// Fs returns fs with the files created in it also written compressed, if
+// enabled for the file, and the compressed siblings removed with the file.
+func (c *Client) Fs(fs afero.Fs) afero.Fs {
+	if c == nil {
+		return fs
+	}
+	return &precompressFs{Fs: fs, c: c}
+}
+
+// removeCompressed removes the compressed siblings of filename, if any.
+func (c *Client) removeCompressed(fs afero.Fs, filename string) error {
+	for _, enc := range c.encoders {
+		if err := fs.Remove(filename + enc.ext); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// writer buffers the content until MinSize is reached and then streams it
+// to the compressed files.
+type writer struct {
+	c          *Client
+	fs         afero.Fs
+	targetPath string
+
+	buf      bytes.Buffer
+	files    []io.Closer
+	encoders []io.WriteCloser
+	err      error
+	closed   bool
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+
+	n := len(p)
+
+	if w.encoders == nil {
+		w.buf.Write(p)
+		if w.buf.Len() < w.c.conf.MinSize {
+			return n, nil
+		}
+		if w.err = w.open(); w.err != nil {
+			return 0, w.err
+		}
+		p = w.buf.Bytes()
+		defer w.buf.Reset()
+	}
+
+	for _, enc := range w.encoders {
+		if _, err := enc.Write(p); err != nil {
+			w.err = errors.Wrapf(err, "failed to precompress %q", w.targetPath)
+			return 0, w.err
+		}
+	}
+
+	return n, nil
+}
+
+func (w *writer) open() error {
+	w.encoders = make([]io.WriteCloser, 0, len(w.c.encoders))
+	for _, enc := range w.c.encoders {
+		f, err := helpers.OpenFileForWriting(w.fs, w.targetPath+enc.ext)
+		if err != nil {
+			return errors.Wrapf(err, "failed to precompress %q", w.targetPath)
+		}
+		w.files = append(w.files, f)
+		ew, err := enc.newWriter(f)
+		if err != nil {
+			return errors.Wrapf(err, "failed to precompress %q", w.targetPath)
+		}
+		w.encoders = append(w.encoders, ew)
+	}
+	return nil
+}
+
+// Close flushes the encoders and closes the files. It's safe to call more than once.
+func (w *writer) Close() error {
+	if w.closed {
+		return w.err
+	}
+	w.closed = true
+
+	if w.encoders == nil && w.err == nil {
+		// Too small, make sure no stale copies are left behind.
+		w.err = w.c.removeCompressed(w.fs, w.targetPath)
+		return w.err
+	}
+
+	for _, c := range w.encoders {
+		if err := c.Close(); err != nil && w.err == nil {
+			w.err = errors.Wrapf(err, "failed to precompress %q", w.targetPath)
+		}
+	}
+	for _, c := range w.files {
+		if err := c.Close(); err != nil && w.err == nil {
+			w.err = err
+		}
+	}
+
+	return w.err
+}
+
+// commandWriter pipes the content written to it through an external command.
+type commandWriter struct {
+	stdin  io.WriteCloser
+	stderr bytes.Buffer
+	wait   func() error
+}
+
+func newCommandWriter(cfg config.Provider, conf CommandConfig, w io.Writer) (io.WriteCloser, error) {
+	cmd, done, err := hexec.Command(cfg, "", conf.Command, conf.Args...)
+	if err != nil {
+		return nil, err
+	}
+
+	cw := &commandWriter{}
+	cmd.Stdout = w
+	cmd.Stderr = &cw.stderr
+
+	cw.stdin, err = cmd.StdinPipe()
+	if err != nil {
+		done()
+		return nil, err
+	}
+
+	if err := cmd.Start(); err != nil {
+		done()
+		return nil, errors.Wrapf(err, "failed to start %q", conf.Command)
+	}
+
+	cw.wait = func() error {
+		defer done()
+		if err := cmd.Wait(); err != nil {
+			return errors.Errorf("%s failed: %s: %s", conf.Command, err, strings.TrimSpace(cw.stderr.String()))
+		}
+		return nil
+	}
+
+	return cw, nil
+}
+
+func (w *commandWriter) Write(p []byte) (int, error) {
+	return w.stdin.Write(p)
+}
+
+func (w *commandWriter) Close() error {
+	w.stdin.Close()
+	return w.wait()
+}
+
+type precompressFs struct {
+	afero.Fs
+	c *Client
+}
+
+func (fs *precompressFs) Create(name string) (afero.File, error) {
+	f, err := fs.Fs.Create(name)
+	if err != nil || !fs.c.EnabledForFile(name) {
+		return f, err
+	}
+	return &precompressFile{File: f, w: fs.c.NewWriter(fs.Fs, name)}, nil
+}
+
+func (fs *precompressFs) OpenFile(name string, flag int, perm os.FileMode) (afero.File, error) {
+	f, err := fs.Fs.OpenFile(name, flag, perm)
+	if err != nil || flag&(os.O_WRONLY|os.O_RDWR) == 0 || flag&os.O_TRUNC == 0 || !fs.c.EnabledForFile(name) {
+		return f, err
+	}
+	return &precompressFile{File: f, w: fs.c.NewWriter(fs.Fs, name)}, nil
+}
+
+func (fs *precompressFs) Remove(name string) error {
+	if err := fs.Fs.Remove(name); err != nil {
+		return err
+	}
+	return fs.c.removeCompressed(fs.Fs, name)
+}
+
+func (fs *precompressFs) RemoveAll(path string) error {
+	if err := fs.Fs.RemoveAll(path); err != nil {
+		return err
+	}
+	return fs.c.removeCompressed(fs.Fs, path)
+}
+
+type precompressFile struct {
+	afero.File
+	w io.WriteCloser
+}
+
+func (f *precompressFile) Write(p []byte) (int, error) {
+	n, err := f.File.Write(p)
+	if err != nil {
+		return n, err
+	}
+	return f.w.Write(p[:n])
+}
+
+func (f *precompressFile) WriteString(s string) (int, error) {
+	return f.Write([]byte(s))
+}
+
+func (f *precompressFile) Close() error {
+	err := f.w.Close()
+	if cerr := f.File.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func init() {
+	docsProvider := func() docshelper.DocProvider {
+		return docshelper.DocProvider{"config": map[string]interface{}{precompressConfigKey: parser.LowerCaseCamelJSONMarshaller{Value: defaultConfig}}}
+	}
+	docshelper.AddDocProviderFunc(docsProvider)
+}
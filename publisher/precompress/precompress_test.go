@@ -0,0 +1,196 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package precompress
+
+import (
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gohugoio/hugo/config"
+	"github.com/gohugoio/hugo/output"
+	"github.com/spf13/afero"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestDecodeConfig(t *testing.T) {
+	c := qt.New(t)
+
+	v := config.New()
+	client, err := New(v)
+	c.Assert(err, qt.IsNil)
+	c.Assert(client, qt.IsNil)
+	c.Assert(client.EnabledForFormat(output.HTMLFormat), qt.Equals, false)
+	c.Assert(client.EnabledForFile("main.css"), qt.Equals, false)
+
+	v.Set("precompress", map[string]interface{}{
+		"encodings":  []string{"GZIP", "br"},
+		"formats":    []string{"html"},
+		"extensions": []string{"CSS", ".js"},
+		"minSize":    10,
+		"commands": []map[string]interface{}{
+			{"ext": ".ZST", "command": "zstd", "args": []string{"-c"}},
+		},
+	})
+	conf, err := DecodeConfig(v)
+	c.Assert(err, qt.IsNil)
+	c.Assert(conf.Encodings, qt.DeepEquals, []string{"gzip", "br"})
+	c.Assert(conf.Extensions, qt.DeepEquals, []string{".css", ".js"})
+	c.Assert(conf.Commands, qt.DeepEquals, []CommandConfig{{Ext: ".zst", Command: "zstd", Args: []string{"-c"}}})
+	c.Assert(conf.MinSize, qt.Equals, 10)
+
+	client, err = New(v)
+	c.Assert(err, qt.IsNil)
+	c.Assert(client.EnabledForFormat(output.HTMLFormat), qt.Equals, true)
+	c.Assert(client.EnabledForFormat(output.JSONFormat), qt.Equals, false)
+	c.Assert(client.EnabledForFile("css/main.css"), qt.Equals, true)
+	c.Assert(client.EnabledForFile("images/sunset.jpg"), qt.Equals, false)
+	c.Assert(client.IsCompressed("css/main.css.zst"), qt.Equals, true)
+	c.Assert(client.IsCompressed("css/main.css"), qt.Equals, false)
+
+	v.Set("precompress", map[string]interface{}{
+		"encodings": []string{"zstd"},
+	})
+	_, err = DecodeConfig(v)
+	c.Assert(err, qt.ErrorMatches, `.*unsupported encoding "zstd"`)
+
+	v = config.New()
+	v.Set("precompress", map[string]interface{}{
+		"commands": []map[string]interface{}{
+			{"ext": "zst", "command": "zstd"},
+		},
+	})
+	_, err = DecodeConfig(v)
+	c.Assert(err, qt.ErrorMatches, `.*invalid extension "zst".*`)
+}
+
+func newTestClient(c *qt.C, conf map[string]interface{}) *Client {
+	v := config.New()
+	v.Set("precompress", conf)
+	client, err := New(v)
+	c.Assert(err, qt.IsNil)
+	return client
+}
+
+func readGzip(c *qt.C, fs afero.Fs, filename string) string {
+	f, err := fs.Open(filename)
+	c.Assert(err, qt.IsNil)
+	defer f.Close()
+	r, err := gzip.NewReader(f)
+	c.Assert(err, qt.IsNil)
+	b, err := ioutil.ReadAll(r)
+	c.Assert(err, qt.IsNil)
+	return string(b)
+}
+
+func TestWriter(t *testing.T) {
+	c := qt.New(t)
+
+	fs := afero.NewMemMapFs()
+	client := newTestClient(c, map[string]interface{}{
+		"encodings": []string{"gzip", "br"},
+	})
+	content := strings.Repeat("<p>Hello precompressed world.</p>\n", 20)
+
+	write := func(target, s string) {
+		w := client.NewWriter(fs, target)
+		// Write in chunks to cover the buffering below minSize.
+		for _, line := range strings.SplitAfter(s, "\n") {
+			_, err := io.WriteString(w, line)
+			c.Assert(err, qt.IsNil)
+		}
+		c.Assert(w.Close(), qt.IsNil)
+	}
+
+	write("index.html", content)
+	write("small.html", "<p>Small</p>")
+
+	c.Assert(readGzip(c, fs, "index.html.gz"), qt.Equals, content)
+
+	brf, err := fs.Open("index.html.br")
+	c.Assert(err, qt.IsNil)
+	defer brf.Close()
+	b, err := ioutil.ReadAll(brotli.NewReader(brf))
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(b), qt.Equals, content)
+
+	exists, _ := afero.Exists(fs, "small.html.gz")
+	c.Assert(exists, qt.Equals, false)
+
+	// The file shrunk below minSize.
+	write("index.html", "<p>Small</p>")
+	for _, filename := range []string{"index.html.gz", "index.html.br"} {
+		exists, _ := afero.Exists(fs, filename)
+		c.Assert(exists, qt.Equals, false, qt.Commentf(filename))
+	}
+}
+
+func TestFs(t *testing.T) {
+	c := qt.New(t)
+
+	memfs := afero.NewMemMapFs()
+	client := newTestClient(c, map[string]interface{}{
+		"encodings":  []string{"gzip"},
+		"extensions": []string{".css"},
+		"minSize":    0,
+	})
+	fs := client.Fs(memfs)
+
+	c.Assert(afero.WriteFile(fs, "css/main.css", []byte("body { color: red; }"), 0666), qt.IsNil)
+	c.Assert(afero.WriteFile(fs, "images/sunset.jpg", []byte("jpg"), 0666), qt.IsNil)
+
+	b, err := afero.ReadFile(memfs, "css/main.css")
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(b), qt.Equals, "body { color: red; }")
+	c.Assert(readGzip(c, memfs, "css/main.css.gz"), qt.Equals, "body { color: red; }")
+
+	exists, _ := afero.Exists(memfs, "images/sunset.jpg.gz")
+	c.Assert(exists, qt.Equals, false)
+
+	c.Assert(fs.Remove("css/main.css"), qt.IsNil)
+	exists, _ = afero.Exists(memfs, "css/main.css.gz")
+	c.Assert(exists, qt.Equals, false)
+
+	var disabled *Client
+	c.Assert(disabled.Fs(memfs), qt.Equals, memfs)
+}
+
+func TestCommand(t *testing.T) {
+	if _, err := exec.LookPath("gzip"); err != nil {
+		t.Skip("gzip not found")
+	}
+
+	c := qt.New(t)
+
+	fs := afero.NewMemMapFs()
+	client := newTestClient(c, map[string]interface{}{
+		"commands": []map[string]interface{}{
+			{"ext": ".gz", "command": "gzip", "args": []string{"-c", "-9"}},
+		},
+		"minSize": 0,
+	})
+	content := strings.Repeat("Hello command world.\n", 20)
+
+	w := client.NewWriter(fs, "index.html")
+	_, err := io.WriteString(w, content)
+	c.Assert(err, qt.IsNil)
+	c.Assert(w.Close(), qt.IsNil)
+
+	c.Assert(readGzip(c, fs, "index.html.gz"), qt.Equals, content)
+}
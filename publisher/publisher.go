@@ -14,11 +14,18 @@
 package publisher
 
 import (
+	"crypto/md5"
+	"encoding/hex"
 	"errors"
+	"hash"
 	"io"
 	"net/url"
+	"path/filepath"
 	"sync/atomic"
 
+	"github.com/gohugoio/hugo/common/loggers"
+
+	"github.com/gohugoio/hugo/config"
 	"github.com/gohugoio/hugo/resources"
 
 	"github.com/gohugoio/hugo/media"
@@ -31,9 +38,16 @@ import (
 	"github.com/spf13/afero"
 
 	"github.com/gohugoio/hugo/output"
+	"github.com/gohugoio/hugo/publisher/precompress"
 	"github.com/gohugoio/hugo/transform"
+	"github.com/gohugoio/hugo/transform/canonicalhtml"
+	"github.com/gohugoio/hugo/transform/email"
+	"github.com/gohugoio/hugo/transform/lite"
 	"github.com/gohugoio/hugo/transform/livereloadinject"
 	"github.com/gohugoio/hugo/transform/metainject"
+	"github.com/gohugoio/hugo/transform/outboundlinks"
+	"github.com/gohugoio/hugo/transform/printdoc"
+	"github.com/gohugoio/hugo/transform/resourcehints"
 	"github.com/gohugoio/hugo/transform/urlreplacers"
 )
 
@@ -65,6 +79,16 @@ type Descriptor struct {
 	// Enable to minify the output using the OutputFormat defined above to
 	// pick the correct minifier configuration.
 	Minify bool
+
+	// If set, will be called with the MD5 hash of the published bytes.
+	OnContentHash func(hash string)
+
+	// Enable to replace the resource hints placeholder in the HTML with hints
+	// for the published resources the page references.
+	ResourceHints bool
+
+	// The URL of the next page, if paginated, to prefetch in the resource hints.
+	NextPageURL string
 }
 
 // DestinationPublisher is the default and currently only publisher in Hugo. This
@@ -72,7 +96,14 @@ type Descriptor struct {
 type DestinationPublisher struct {
 	fs                    afero.Fs
 	min                   minifiers.Client
+	precompress           *precompress.Client
 	htmlElementsCollector *htmlElementsCollector
+	outboundLinks         *outboundlinks.Rewriter
+	baseURL               string
+	publishedResources    *resources.PublishedResources
+	canonicalHTML         transform.Transformer
+	lite                  *lite.Lite
+	logger                loggers.Logger
 }
 
 // NewDestinationPublisher creates a new DestinationPublisher.
@@ -83,11 +114,89 @@ func NewDestinationPublisher(rs *resources.Spec, outputFormats output.Formats, m
 	if rs.BuildConfig.WriteStats {
 		classCollector = newHTMLElementsCollector()
 	}
-	pub = DestinationPublisher{fs: fs, htmlElementsCollector: classCollector}
+	pub = DestinationPublisher{
+		fs:                    fs,
+		htmlElementsCollector: classCollector,
+		precompress:           rs.Precompress,
+		baseURL:               cfg.GetString("baseURL"),
+		publishedResources:    rs.PublishedResources,
+		logger:                rs.Logger,
+	}
 	pub.min, err = minifiers.New(mediaTypes, outputFormats, cfg)
+	if err != nil {
+		return
+	}
+	pub.outboundLinks, err = newOutboundLinksRewriter(cfg)
+	if err != nil {
+		return
+	}
+	pub.canonicalHTML, err = newCanonicalHTMLTransformer(cfg)
+	if err != nil {
+		return
+	}
+	pub.lite, err = newLite(rs)
 	return
 }
 
+func (p DestinationPublisher) lookupPublishedResource(url string) (resourcehints.Resource, bool) {
+	r, found := p.publishedResources.Get(url)
+	if !found {
+		return resourcehints.Resource{}, false
+	}
+	return resourcehints.Resource{MediaType: r.MediaType, Integrity: r.Integrity}, true
+}
+
+func newCanonicalHTMLTransformer(cfg config.Provider) (transform.Transformer, error) {
+	conf, err := canonicalhtml.DecodeConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if !conf.Enable {
+		return nil, nil
+	}
+	return canonicalhtml.Transformer(conf), nil
+}
+
+func newLite(rs *resources.Spec) (*lite.Lite, error) {
+	conf, err := lite.DecodeConfig(rs.Cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var lang string
+	if rs.Language != nil {
+		lang = rs.Language.Lang
+	}
+	staticFs := rs.BaseFs.SourceFilesystems.StaticFs(lang)
+
+	// The stylesheets are either published by the site, e.g. from
+	// resources.Get, or copied from /static after the build.
+	readFile := func(filename string) ([]byte, error) {
+		filename = filepath.FromSlash(filename)
+		b, err := afero.ReadFile(rs.BaseFs.PublishFs, filename)
+		if err == nil {
+			return b, nil
+		}
+		return afero.ReadFile(staticFs, filename)
+	}
+
+	return lite.New(conf, rs.Cfg.GetString("baseURL"), readFile), nil
+}
+
+func newOutboundLinksRewriter(cfg config.Provider) (*outboundlinks.Rewriter, error) {
+	conf, err := outboundlinks.DecodeConfig(cfg)
+	if err != nil || !conf.Enabled() {
+		return nil, err
+	}
+
+	var siteHost string
+	if u, err := url.Parse(cfg.GetString("baseURL")); err == nil {
+		siteHost = u.Hostname()
+	}
+
+	return outboundlinks.New(conf, siteHost)
+}
+
 // Publish applies any relevant transformations and writes the file
 // to its destination, e.g. /public.
 func (p DestinationPublisher) Publish(d Descriptor) error {
@@ -107,6 +216,15 @@ func (p DestinationPublisher) Publish(d Descriptor) error {
 			return err
 		}
 
+		if d.OutputFormat.IsLite && p.lite != nil {
+			if err := p.lite.CheckSize(d.TargetPath, b.Len()); err != nil {
+				if p.lite.FailOnMaxSize() {
+					return err
+				}
+				p.logger.Warnln(err)
+			}
+		}
+
 		// This is now what we write to disk.
 		src = b
 	}
@@ -123,26 +241,61 @@ func (p DestinationPublisher) Publish(d Descriptor) error {
 		w = io.MultiWriter(w, newHTMLElementsCollectorWriter(p.htmlElementsCollector))
 	}
 
+	var contentHash hash.Hash
+	if d.OnContentHash != nil {
+		contentHash = md5.New()
+		w = io.MultiWriter(w, contentHash)
+	}
+
+	var precompressw io.WriteCloser
+	if p.precompress.EnabledForFormat(d.OutputFormat) {
+		precompressw = p.precompress.NewWriter(p.fs, d.TargetPath)
+		defer precompressw.Close()
+		w = io.MultiWriter(w, precompressw)
+	}
+
 	_, err = io.Copy(w, src)
-	if err == nil && d.StatCounter != nil {
+	if err != nil {
+		return err
+	}
+
+	if precompressw != nil {
+		if err := precompressw.Close(); err != nil {
+			return err
+		}
+	}
+
+	if contentHash != nil {
+		d.OnContentHash(hex.EncodeToString(contentHash.Sum(nil)))
+	}
+
+	if d.StatCounter != nil {
 		atomic.AddUint64(d.StatCounter, uint64(1))
 	}
 
-	return err
+	return nil
 }
 
 func (p DestinationPublisher) PublishStats() PublishStats {
-	if p.htmlElementsCollector == nil {
-		return PublishStats{}
+	var stats PublishStats
+
+	if p.htmlElementsCollector != nil {
+		stats.HTMLElements = p.htmlElementsCollector.getHTMLElements()
 	}
 
-	return PublishStats{
-		HTMLElements: p.htmlElementsCollector.getHTMLElements(),
+	if p.outboundLinks != nil {
+		stats.OutboundLinks = p.outboundLinks.Links()
 	}
+
+	return stats
 }
 
 type PublishStats struct {
 	HTMLElements HTMLElements `json:"htmlElements"`
+
+	// The rewritten outbound links, mapping the rewritten paths to the
+	// original URLs.
+	OutboundLinks map[string]string `json:"outboundLinks,omitempty"`
 }
 
 // Publisher publishes a result file.
@@ -155,8 +308,22 @@ type Publisher interface {
 func (p DestinationPublisher) createTransformerChain(f Descriptor) transform.Chain {
 	transformers := transform.NewEmpty()
 
+	if f.OutputFormat.Stream {
+		// The transformers need the complete content.
+		return transformers
+	}
+
 	isHTML := f.OutputFormat.IsHTML
 
+	// This needs the URLs as handed to the templates, so it goes first.
+	if isHTML && f.ResourceHints {
+		transformers = append(transformers, resourcehints.New(resourcehints.Options{
+			BaseURL: p.baseURL,
+			Lookup:  p.lookupPublishedResource,
+			Next:    f.NextPageURL,
+		}))
+	}
+
 	if f.AbsURLPath != "" {
 		if isHTML {
 			transformers = append(transformers, urlreplacers.NewAbsURLTransformer(f.AbsURLPath))
@@ -167,6 +334,18 @@ func (p DestinationPublisher) createTransformerChain(f Descriptor) transform.Cha
 	}
 
 	if isHTML {
+		if f.OutputFormat.IsLite && p.lite != nil {
+			transformers = append(transformers, p.lite.Transformer(f.TargetPath))
+		}
+
+		if f.OutputFormat.IsEmail {
+			transformers = append(transformers, email.InlineCSS)
+		}
+
+		if f.OutputFormat.IsPrint {
+			transformers = append(transformers, printdoc.Transform)
+		}
+
 		if f.LiveReloadBaseURL != nil {
 			transformers = append(transformers, livereloadinject.New(*f.LiveReloadBaseURL))
 		}
@@ -176,6 +355,14 @@ func (p DestinationPublisher) createTransformerChain(f Descriptor) transform.Cha
 			transformers = append(transformers, metainject.HugoGenerator)
 		}
 
+		if p.outboundLinks != nil {
+			transformers = append(transformers, p.outboundLinks.Transform)
+		}
+
+		// Format the final HTML, before any minification.
+		if p.canonicalHTML != nil {
+			transformers = append(transformers, p.canonicalHTML)
+		}
 	}
 
 	if p.min.MinifyOutput {
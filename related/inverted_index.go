@@ -60,6 +60,10 @@ An example site config.toml:
 	name  = "date"
 	weight = 1
 	pattern = "2006"
+	[[related.indices]]
+	name  = "embedding"
+	type = "vector"
+	weight = 50
 */
 type Config struct {
 	// Only include matches >= threshold, a normalized rank between 0 and 100.
@@ -68,6 +72,11 @@ type Config struct {
 	// To get stable "See also" sections we, by default, exclude newer related pages.
 	IncludeNewer bool
 
+	// Also search the pages in other languages, in the same sections as the
+	// pages searched, that are not translated to the language searched in.
+	// This is useful for partially translated sites.
+	IncludeOtherLanguages bool
+
 	// Will lower case all string values and queries to the indices.
 	// May get better results, but at a slight performance cost.
 	ToLower bool
@@ -104,8 +113,30 @@ type IndexConfig struct {
 	// Will lower case all string values in and queries tothis index.
 	// May get better accurate results, but at a slight performance cost.
 	ToLower bool
+
+	// The index type, "keywords" (default) or "vector". A vector index ranks
+	// documents by the cosine similarity of their embeddings, scaled by the
+	// index weight. Embeddings can be given as lists of numbers, else they
+	// are computed from the text given.
+	Type string
+
+	// The number of dimensions in the embeddings computed from text for a
+	// vector index. Defaults to 256.
+	Dimensions int
 }
 
+// IsVector returns whether this is a vector index.
+func (cfg IndexConfig) IsVector() bool {
+	return cfg.Type == indexTypeVector
+}
+
+const (
+	indexTypeKeywords = "keywords"
+	indexTypeVector   = "vector"
+
+	defaultVectorDimensions = 256
+)
+
 // Document is the interface an indexable document in Hugo must fulfill.
 type Document interface {
 	// RelatedKeywords returns a list of keywords for the given index config.
@@ -125,6 +156,9 @@ type InvertedIndex struct {
 	cfg   Config
 	index map[string]map[Keyword][]Document
 
+	// The document embeddings for the vector indices.
+	vectors map[string][]documentVector
+
 	minWeight int
 	maxWeight int
 }
@@ -142,7 +176,7 @@ func (idx *InvertedIndex) getIndexCfg(name string) (IndexConfig, bool) {
 // NewInvertedIndex creates a new InvertedIndex.
 // Documents to index must be added in Add.
 func NewInvertedIndex(cfg Config) *InvertedIndex {
-	idx := &InvertedIndex{index: make(map[string]map[Keyword][]Document), cfg: cfg}
+	idx := &InvertedIndex{index: make(map[string]map[Keyword][]Document), vectors: make(map[string][]documentVector), cfg: cfg}
 	for _, conf := range cfg.Indices {
 		idx.index[conf.Name] = make(map[Keyword][]Document)
 		if conf.Weight < idx.minWeight {
@@ -175,6 +209,15 @@ func (idx *InvertedIndex) Add(docs ...Document) error {
 				continue
 			}
 
+			if config.IsVector() {
+				for _, keyword := range words {
+					if v, ok := keyword.(VectorKeyword); ok && len(v) > 0 {
+						idx.vectors[config.Name] = append(idx.vectors[config.Name], documentVector{doc: doc, v: v.normalize()})
+					}
+				}
+				continue
+			}
+
 			for _, keyword := range words {
 				setm[keyword] = append(setm[keyword], doc)
 			}
@@ -263,6 +306,10 @@ func (idx *InvertedIndex) SearchDoc(doc Document, indices ...string) ([]Document
 
 // ToKeywords returns a Keyword slice of the given input.
 func (cfg IndexConfig) ToKeywords(v interface{}) ([]Keyword, error) {
+	if cfg.IsVector() {
+		return cfg.toVectorKeywords(v)
+	}
+
 	var (
 		keywords []Keyword
 		toLower  = cfg.ToLower
@@ -350,6 +397,32 @@ func (idx *InvertedIndex) searchDate(upperDate time.Time, query ...queryElement)
 			return []Document{}, fmt.Errorf("index config for %q not found", el.Index)
 		}
 
+		if config.IsVector() {
+			for _, kw := range el.Keywords {
+				v, ok := kw.(VectorKeyword)
+				if !ok {
+					continue
+				}
+				v = v.normalize()
+				for _, dv := range idx.vectors[el.Index] {
+					if applyDateFilter && dv.doc.PublishDate().After(upperDate) {
+						continue
+					}
+					weight := int(math.Round(float64(config.Weight) * v.dot(dv.v)))
+					if weight <= 0 {
+						continue
+					}
+					r, found := matchm[dv.doc]
+					if !found {
+						matchm[dv.doc] = newRank(dv.doc, weight)
+					} else {
+						r.addWeight(weight)
+					}
+				}
+			}
+			continue
+		}
+
 		for _, kw := range el.Keywords {
 			if docs, found := setm[kw]; found {
 				for _, doc := range docs {
@@ -431,6 +504,20 @@ func DecodeConfig(m maps.Params) (Config, error) {
 		}
 	}
 
+	for i, index := range c.Indices {
+		switch strings.ToLower(index.Type) {
+		case "", indexTypeKeywords:
+			c.Indices[i].Type = indexTypeKeywords
+		case indexTypeVector:
+			c.Indices[i].Type = indexTypeVector
+			if index.Dimensions <= 0 {
+				c.Indices[i].Dimensions = defaultVectorDimensions
+			}
+		default:
+			return Config{}, fmt.Errorf("related index %q: invalid type %q", index.Name, index.Type)
+		}
+	}
+
 	return c, nil
 }
 
@@ -212,6 +212,89 @@ func TestToKeywordsToLower(t *testing.T) {
 	})
 }
 
+func TestSearchVector(t *testing.T) {
+	c := qt.New(t)
+
+	config := Config{
+		Threshold: 20,
+		Indices: IndexConfigs{
+			IndexConfig{Name: "tags", Weight: 100},
+			IndexConfig{Name: "embedding", Type: "vector", Weight: 100},
+		},
+	}
+
+	idx := NewInvertedIndex(config)
+
+	vectorDoc := func(name string, v ...float64) *testDoc {
+		d := newTestDoc("tags")
+		d.name = name
+		d.keywords["embedding"] = []Keyword{VectorKeyword(v)}
+		return d
+	}
+
+	docs := []Document{
+		vectorDoc("a", 1, 0, 0),
+		vectorDoc("b", 0.9, 0.1, 0),
+		vectorDoc("c", 0, 0, 1),
+		vectorDoc("d", 0.5, 0.5, 0).addKeywords("tags", "x"),
+	}
+
+	c.Assert(idx.Add(docs...), qt.IsNil)
+	c.Assert(len(idx.vectors["embedding"]), qt.Equals, 4)
+
+	m, err := idx.search(newQueryElement("embedding", VectorKeyword{2, 0, 0}))
+	c.Assert(err, qt.IsNil)
+	c.Assert(len(m), qt.Equals, 3)
+	c.Assert(m[0], qt.Equals, docs[0])
+	c.Assert(m[1], qt.Equals, docs[1])
+	c.Assert(m[2], qt.Equals, docs[3])
+
+	// Blended with the keyword index.
+	m, err = idx.search(
+		newQueryElement("embedding", VectorKeyword{0, 0, 1}),
+		newQueryElement("tags", StringKeyword("x")),
+	)
+	c.Assert(err, qt.IsNil)
+	c.Assert(len(m), qt.Equals, 2)
+	// Same weight, newest first.
+	c.Assert(m[0], qt.Equals, docs[3])
+	c.Assert(m[1], qt.Equals, docs[2])
+}
+
+func TestTextToVector(t *testing.T) {
+	c := qt.New(t)
+
+	v1 := TextToVector("Hugo is a fast static site generator.", 64)
+	v2 := TextToVector("A static site generator, Hugo is fast!", 64)
+	v3 := TextToVector("Bananas are yellow.", 64)
+
+	c.Assert(len(v1), qt.Equals, 64)
+	c.Assert(v1.normalize().dot(v2.normalize()) > 0.999, qt.IsTrue)
+	c.Assert(v1.normalize().dot(v3.normalize()) < 0.5, qt.IsTrue)
+}
+
+func TestDecodeConfigVector(t *testing.T) {
+	c := qt.New(t)
+
+	cfg, err := DecodeConfig(map[string]interface{}{
+		"indices": []map[string]interface{}{
+			{"name": "tags", "weight": 100},
+			{"name": "embedding", "type": "Vector", "weight": 50},
+		},
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(cfg.Indices[0].IsVector(), qt.IsFalse)
+	c.Assert(cfg.Indices[1].IsVector(), qt.IsTrue)
+	c.Assert(cfg.Indices[1].Dimensions, qt.Equals, 256)
+
+	_, err = DecodeConfig(map[string]interface{}{
+		"indices": []map[string]interface{}{
+			{"name": "embedding", "type": "foo"},
+		},
+	})
+	c.Assert(err, qt.Not(qt.IsNil))
+}
+
 func BenchmarkRelatedNewIndex(b *testing.B) {
 	pages := make([]*testDoc, 100)
 	numkeywords := 30
@@ -0,0 +1,135 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package related
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/spf13/cast"
+)
+
+var _ Keyword = VectorKeyword(nil)
+
+// VectorKeyword is an embedding used as a search keyword in a vector index.
+type VectorKeyword []float64
+
+func (v VectorKeyword) String() string {
+	return fmt.Sprint([]float64(v))
+}
+
+// normalize returns v scaled to unit length.
+func (v VectorKeyword) normalize() VectorKeyword {
+	var sum float64
+	for _, f := range v {
+		sum += f * f
+	}
+	if sum == 0 || sum == 1 {
+		return v
+	}
+
+	norm := math.Sqrt(sum)
+	n := make(VectorKeyword, len(v))
+	for i, f := range v {
+		n[i] = f / norm
+	}
+	return n
+}
+
+// dot returns the dot product of v and other, which for normalized vectors
+// is their cosine similarity. Vectors of different lengths are not similar.
+func (v VectorKeyword) dot(other VectorKeyword) float64 {
+	if len(v) != len(other) {
+		return 0
+	}
+	var sum float64
+	for i := range v {
+		sum += v[i] * other[i]
+	}
+	return sum
+}
+
+type documentVector struct {
+	doc Document
+	v   VectorKeyword
+}
+
+func (cfg IndexConfig) toVectorKeywords(v interface{}) ([]Keyword, error) {
+	switch vv := v.(type) {
+	case nil:
+		return nil, nil
+	case VectorKeyword:
+		return []Keyword{vv}, nil
+	case string:
+		return []Keyword{TextToVector(vv, cfg.Dimensions)}, nil
+	case []float64:
+		return []Keyword{VectorKeyword(vv)}, nil
+	case []string:
+		// Lists in front matter are stored as strings.
+		vec := make(VectorKeyword, len(vv))
+		for i, f := range vv {
+			var err error
+			vec[i], err = cast.ToFloat64E(f)
+			if err != nil {
+				return nil, fmt.Errorf("invalid embedding for index %q: %s", cfg.Name, err)
+			}
+		}
+		return []Keyword{vec}, nil
+	case []interface{}:
+		vec := make(VectorKeyword, len(vv))
+		for i, f := range vv {
+			var err error
+			vec[i], err = cast.ToFloat64E(f)
+			if err != nil {
+				return nil, fmt.Errorf("invalid embedding for index %q: %s", cfg.Name, err)
+			}
+		}
+		return []Keyword{vec}, nil
+	default:
+		return nil, fmt.Errorf("indexing currently not supported for vector index %q and type %T", cfg.Name, vv)
+	}
+}
+
+// TextToVector computes an embedding with the given number of dimensions for
+// the words in s, hashing each word to a dimension. Texts sharing many words
+// get similar embeddings.
+func TextToVector(s string, dimensions int) VectorKeyword {
+	if dimensions <= 0 {
+		dimensions = defaultVectorDimensions
+	}
+
+	v := make(VectorKeyword, dimensions)
+	h := fnv.New64a()
+
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	for _, w := range words {
+		h.Reset()
+		h.Write([]byte(w))
+		sum := h.Sum64()
+		// Use the top bit as a sign to reduce the bias from hash collisions.
+		if sum>>63 == 1 {
+			v[sum%uint64(dimensions)]--
+		} else {
+			v[sum%uint64(dimensions)]++
+		}
+	}
+
+	return v
+}
@@ -0,0 +1,126 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	"html/template"
+	"io"
+	"sort"
+	"sync"
+)
+
+// AssetReportInput is an input file of a bundle in the asset report.
+type AssetReportInput struct {
+	// The path of the input, relative to the working dir if inside it.
+	Path string `json:"path"`
+
+	// The size in bytes of the part of the bundle coming from this input.
+	Bytes int `json:"bytes"`
+}
+
+// AssetReportBundle describes the composition of a bundle built by
+// js.Build.
+type AssetReportBundle struct {
+	// The entry point, e.g. "js/main.js".
+	EntryPoint string `json:"entryPoint"`
+
+	// The target path of the bundle, before any fingerprinting.
+	Target string `json:"target"`
+
+	// The size in bytes of the bundle.
+	Bytes int `json:"bytes"`
+
+	// The inputs, the largest first.
+	Inputs []AssetReportInput `json:"inputs"`
+}
+
+// AssetReport collects the composition of the bundles built in a build.
+// It is written to hugo_asset_report.json and hugo_asset_report.html
+// when build.writeAssetReport is enabled, to track the bundle sizes.
+type AssetReport struct {
+	mu      sync.Mutex
+	bundles map[string]AssetReportBundle
+}
+
+// Add adds b to the report, replacing any bundle with the same target.
+func (r *AssetReport) Add(b AssetReportBundle) {
+	sort.SliceStable(b.Inputs, func(i, j int) bool {
+		if b.Inputs[i].Bytes != b.Inputs[j].Bytes {
+			return b.Inputs[i].Bytes > b.Inputs[j].Bytes
+		}
+		return b.Inputs[i].Path < b.Inputs[j].Path
+	})
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.bundles == nil {
+		r.bundles = make(map[string]AssetReportBundle)
+	}
+	r.bundles[b.Target] = b
+}
+
+// Bundles returns the bundles sorted by target.
+func (r *AssetReport) Bundles() []AssetReportBundle {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	bundles := make([]AssetReportBundle, 0, len(r.bundles))
+	for _, b := range r.bundles {
+		bundles = append(bundles, b)
+	}
+	sort.Slice(bundles, func(i, j int) bool {
+		return bundles[i].Target < bundles[j].Target
+	})
+	return bundles
+}
+
+var assetReportTemplate = template.Must(template.New("").Funcs(template.FuncMap{
+	"percent": func(part, total int) float64 {
+		if total == 0 {
+			return 0
+		}
+		return float64(part) * 100 / float64(total)
+	},
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Asset report</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 2em; }
+th, td { text-align: left; padding: 0.2em 0.5em; border-bottom: 1px solid #ddd; }
+td.bytes { text-align: right; white-space: nowrap; }
+.bar { background: #4a90d9; height: 0.8em; }
+</style>
+</head>
+<body>
+<h1>Asset report</h1>
+{{ range . }}{{ $total := .Bytes }}
+<h2>{{ .Target }}</h2>
+<p>Entry point: {{ .EntryPoint }}, {{ .Bytes }} bytes.</p>
+<table>
+<tr><th>Input</th><th>Bytes</th><th>%</th><th></th></tr>
+{{ range .Inputs }}{{ $p := percent .Bytes $total }}<tr><td>{{ .Path }}</td><td class="bytes">{{ .Bytes }}</td><td class="bytes">{{ printf "%.1f" $p }}</td><td><div class="bar" style="width: {{ printf "%.1f" $p }}%"></div></td></tr>
+{{ end }}</table>
+{{ end }}
+</body>
+</html>
+`))
+
+// WriteHTML writes the report as an HTML page to w.
+func (r *AssetReport) WriteHTML(w io.Writer) error {
+	return assetReportTemplate.Execute(w, r.Bundles())
+}
@@ -0,0 +1,49 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	"bytes"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestAssetReport(t *testing.T) {
+	c := qt.New(t)
+
+	r := &AssetReport{}
+	r.Add(AssetReportBundle{EntryPoint: "js/b.js", Target: "js/b.js", Bytes: 1})
+	r.Add(AssetReportBundle{EntryPoint: "js/a.js", Target: "js/a.js", Bytes: 1})
+	r.Add(AssetReportBundle{EntryPoint: "js/a.js", Target: "js/a.js", Bytes: 100, Inputs: []AssetReportInput{
+		{Path: "js/a.js", Bytes: 10},
+		{Path: "node_modules/lib/index.js", Bytes: 80},
+		{Path: "js/c.js", Bytes: 10},
+	}})
+
+	bundles := r.Bundles()
+	c.Assert(bundles, qt.HasLen, 2)
+	c.Assert(bundles[0].Target, qt.Equals, "js/a.js")
+	c.Assert(bundles[0].Bytes, qt.Equals, 100)
+	c.Assert(bundles[0].Inputs, qt.DeepEquals, []AssetReportInput{
+		{Path: "node_modules/lib/index.js", Bytes: 80},
+		{Path: "js/a.js", Bytes: 10},
+		{Path: "js/c.js", Bytes: 10},
+	})
+	c.Assert(bundles[1].Target, qt.Equals, "js/b.js")
+
+	var buf bytes.Buffer
+	c.Assert(r.WriteHTML(&buf), qt.IsNil)
+	c.Assert(buf.String(), qt.Contains, "<td>node_modules/lib/index.js</td><td class=\"bytes\">80</td><td class=\"bytes\">80.0</td>")
+}
@@ -0,0 +1,87 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	"strings"
+	"sync"
+)
+
+// AssetsManifestEntry describes a published resource in the assets manifest.
+type AssetsManifestEntry struct {
+	// The relative URL of the published resource.
+	URL string `json:"url"`
+
+	// The integrity hash, set if the resource is fingerprinted.
+	Integrity string `json:"integrity,omitempty"`
+}
+
+// AssetsManifest maps the logical names of the resources published by the
+// resource transformations, e.g. "css/main.css", to their final URLs.
+// It is written to assets-manifest.json when build.writeAssetsManifest is
+// enabled, so external systems can reference the assets built by Hugo.
+type AssetsManifest struct {
+	mu      sync.Mutex
+	entries map[string]AssetsManifestEntry
+}
+
+func (m *AssetsManifest) add(name string, e AssetsManifestEntry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.entries == nil {
+		m.entries = make(map[string]AssetsManifestEntry)
+	}
+
+	if old, found := m.entries[name]; found && old.Integrity != "" && e.Integrity == "" {
+		// Prefer the fingerprinted variant.
+		return
+	}
+
+	m.entries[name] = e
+}
+
+// Entries returns a copy of the entries keyed by their logical names.
+func (m *AssetsManifest) Entries() map[string]AssetsManifestEntry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entries := make(map[string]AssetsManifestEntry)
+	for k, v := range m.entries {
+		entries[k] = v
+	}
+	return entries
+}
+
+// insertedPart returns the part inserted into s1 to get s2, e.g. the
+// fingerprint in "main.1234.css" given "main.css".
+func insertedPart(s1, s2 string) string {
+	var j int
+	for j < len(s1) && j < len(s2) && s1[len(s1)-1-j] == s2[len(s2)-1-j] {
+		j++
+	}
+	var i int
+	for i < len(s1)-j && i < len(s2)-j && s1[i] == s2[i] {
+		i++
+	}
+	return s2[i : len(s2)-j]
+}
+
+// logicalPath returns targetPath with the fingerprint removed.
+func logicalPath(targetPath, fingerprint string) string {
+	if fingerprint == "" {
+		return targetPath
+	}
+	return strings.Replace(targetPath, fingerprint, "", 1)
+}
@@ -0,0 +1,42 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestAssetsManifest(t *testing.T) {
+	c := qt.New(t)
+
+	c.Assert(insertedPart("css/main.css", "css/main.abc.css"), qt.Equals, ".abc")
+	c.Assert(insertedPart("css/main.min.css", "css/main.min.abc.css"), qt.Equals, ".abc")
+	c.Assert(insertedPart("main", "main.abc"), qt.Equals, ".abc")
+	c.Assert(insertedPart("main.css", "main.css"), qt.Equals, "")
+	c.Assert(logicalPath("css/main.min.abc.css", ".abc"), qt.Equals, "css/main.min.css")
+	c.Assert(logicalPath("css/main.css", ""), qt.Equals, "css/main.css")
+
+	m := &AssetsManifest{}
+	m.add("main.css", AssetsManifestEntry{URL: "/main.abc.css", Integrity: "sha256-abc"})
+	m.add("main.css", AssetsManifestEntry{URL: "/main.css"})
+	m.add("main.js", AssetsManifestEntry{URL: "/main.js"})
+	m.add("main.js", AssetsManifestEntry{URL: "/main.abc.js", Integrity: "sha256-abc"})
+
+	c.Assert(m.Entries(), qt.DeepEquals, map[string]AssetsManifestEntry{
+		"main.css": {URL: "/main.abc.css", Integrity: "sha256-abc"},
+		"main.js":  {URL: "/main.abc.js", Integrity: "sha256-abc"},
+	})
+}
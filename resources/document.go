@@ -0,0 +1,99 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	"io"
+	"sync"
+
+	"github.com/gohugoio/hugo/media"
+	"github.com/gohugoio/hugo/resources/documents"
+	"github.com/gohugoio/hugo/resources/resource"
+)
+
+var (
+	_ resource.DocumentMetaProvider = (*documentResource)(nil)
+	_ resource.Cloner               = (*documentResource)(nil)
+)
+
+// documentResource represents a PDF or EPUB document.
+type documentResource struct {
+	metaInit sync.Once
+	meta     *documents.Metadata
+
+	baseResource
+}
+
+func (d *documentResource) DocumentMeta() *documents.Metadata {
+	d.metaInit.Do(func() {
+		f, err := d.ReadSeekCloser()
+		if err != nil {
+			d.getSpec().Logger.Warnf("Unable to open document %q: %s", d.Key(), err)
+			return
+		}
+		defer f.Close()
+
+		var meta *documents.Metadata
+		if d.MediaType().Type() == media.EPUBType.Type() {
+			var size int64
+			size, err = f.Seek(0, io.SeekEnd)
+			if err == nil {
+				meta, err = documents.DecodeEPUB(readerAt{f}, size)
+			}
+		} else {
+			meta, err = documents.DecodePDF(f)
+		}
+
+		if err != nil {
+			d.getSpec().Logger.Warnf("Unable to decode document metadata from %q: %s", d.Key(), err)
+			return
+		}
+
+		d.meta = meta
+	})
+
+	return d.meta
+}
+
+func (d *documentResource) Clone() resource.Resource {
+	return &documentResource{baseResource: d.baseResource.Clone().(baseResource)}
+}
+
+func (d *documentResource) cloneWithUpdates(u *transformationUpdate) (baseResource, error) {
+	base, err := d.baseResource.cloneWithUpdates(u)
+	if err != nil {
+		return nil, err
+	}
+	if u.isContentChanged() {
+		return base, nil
+	}
+	return &documentResource{baseResource: base}, nil
+}
+
+// readerAt adapts an io.ReadSeeker to an io.ReaderAt. It is not safe for
+// concurrent use.
+type readerAt struct {
+	io.ReadSeeker
+}
+
+func (r readerAt) ReadAt(p []byte, off int64) (int, error) {
+	if _, err := r.Seek(off, io.SeekStart); err != nil {
+		return 0, err
+	}
+	n, err := io.ReadFull(r, p)
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+	return n, err
+}
@@ -0,0 +1,69 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	"archive/zip"
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/gohugoio/hugo/resources/resource"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestDocumentMeta(t *testing.T) {
+	c := qt.New(t)
+
+	spec := newTestResourceSpec(specDescriptor{c: c})
+
+	newDocument := func(name, content string) resource.Resource {
+		filename := filepath.Join(spec.WorkingDir, name)
+		writeToFs(t, spec.Fs.Source, filename, content)
+		r, err := spec.New(ResourceSourceDescriptor{Fs: spec.Fs.Source, TargetPaths: newTargetPaths("/a"), LazyPublish: true, RelTargetFilename: name, SourceFilename: filename})
+		c.Assert(err, qt.IsNil)
+		return r
+	}
+
+	var b bytes.Buffer
+	zw := zip.NewWriter(&b)
+	for name, content := range map[string]string{
+		"META-INF/container.xml": `<container><rootfiles><rootfile full-path="content.opf"/></rootfiles></container>`,
+		"content.opf":            `<package><metadata><dc:title>The Book</dc:title><dc:creator>Jane Doe</dc:creator></metadata></package>`,
+	} {
+		w, err := zw.Create(name)
+		c.Assert(err, qt.IsNil)
+		_, err = w.Write([]byte(content))
+		c.Assert(err, qt.IsNil)
+	}
+	c.Assert(zw.Close(), qt.IsNil)
+
+	epub := newDocument("book.epub", b.String())
+	c.Assert(epub.MediaType().Type(), qt.Equals, "application/epub+zip")
+	meta := epub.(resource.DocumentMetaProvider).DocumentMeta()
+	c.Assert(meta, qt.Not(qt.IsNil))
+	c.Assert(meta.Title, qt.Equals, "The Book")
+	c.Assert(meta.Author, qt.Equals, "Jane Doe")
+	c.Assert(meta.PageCount, qt.Equals, 0)
+
+	// Invalid documents have no metadata.
+	pdf := newDocument("broken.pdf", "not a PDF")
+	c.Assert(pdf.MediaType().Type(), qt.Equals, "application/pdf")
+	c.Assert(pdf.(resource.DocumentMetaProvider).DocumentMeta(), qt.IsNil)
+
+	// Nor have other resources.
+	txt := newDocument("notes.txt", "Notes")
+	c.Assert(txt.(resource.DocumentMetaProvider).DocumentMeta(), qt.IsNil)
+}
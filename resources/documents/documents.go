@@ -0,0 +1,32 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package documents reads metadata from PDF and EPUB documents.
+package documents
+
+import (
+	"time"
+)
+
+// Metadata holds the metadata of a document.
+type Metadata struct {
+	// The number of pages. This is 0 for EPUB documents, which have no
+	// fixed pages.
+	PageCount int
+
+	Title  string
+	Author string
+
+	// The creation date, the publication date for EPUB documents.
+	Created time.Time
+}
@@ -0,0 +1,175 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package documents
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+)
+
+// newTestPDF creates a PDF document with the given objects, numbered from 1,
+// and trailer.
+func newTestPDF(trailer string, objects ...string) []byte {
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.5\n%\xe2\xe3\xcf\xd3\n")
+	for i, obj := range objects {
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	fmt.Fprintf(&b, "trailer\n%s\nstartxref\n0\n%%%%EOF\n", trailer)
+	return b.Bytes()
+}
+
+// newTestObjectStream creates an object stream with the given objects.
+func newTestObjectStream(objects map[int]string) string {
+	var header, body strings.Builder
+	for num := 1; len(objects) > 0; num++ {
+		obj, found := objects[num]
+		if !found {
+			continue
+		}
+		fmt.Fprintf(&header, "%d %d ", num, body.Len())
+		body.WriteString(obj + "\n")
+		delete(objects, num)
+	}
+	data := header.String() + body.String()
+
+	var z bytes.Buffer
+	zw := zlib.NewWriter(&z)
+	zw.Write([]byte(data))
+	zw.Close()
+
+	n := strings.Count(header.String(), " ") / 2
+	return fmt.Sprintf("<< /Type /ObjStm /N %d /First %d /Filter /FlateDecode /Length %d >>\nstream\n%s\nendstream", n, len(header.String()), z.Len(), z.String())
+}
+
+func TestDecodePDF(t *testing.T) {
+	c := qt.New(t)
+
+	c.Run("Plain", func(c *qt.C) {
+		pdf := newTestPDF("<< /Size 6 /Root 1 0 R /Info 5 0 R >>",
+			"<< /Type /Catalog /Pages 2 0 R >>",
+			"<< /Type /Pages /Kids [3 0 R 4 0 R] /Count 2 >>",
+			"<< /Type /Page /Parent 2 0 R >>",
+			"<< /Type /Page /Parent 2 0 R >>",
+			`<< /Title (A \(Live\)stream: \316bis) /TitleX (No) /Author <FEFF00480075006700EF> /CreationDate (D:20210315143000+01'00') >>`,
+		)
+
+		m, err := DecodePDF(bytes.NewReader(pdf))
+		c.Assert(err, qt.IsNil)
+		c.Assert(m.PageCount, qt.Equals, 2)
+		c.Assert(m.Title, qt.Equals, "A (Live)stream: Îbis")
+		c.Assert(m.Author, qt.Equals, "Hugï")
+		c.Assert(m.Created.Equal(time.Date(2021, 3, 15, 13, 30, 0, 0, time.UTC)), qt.IsTrue)
+	})
+
+	c.Run("Object stream", func(c *qt.C) {
+		objstm := newTestObjectStream(map[int]string{
+			2: "<< /Type /Catalog /Pages 3 0 R >>",
+			3: "<< /Type /Pages /Kids [] /Count 12 >>",
+			4: "<< /Title 5 0 R /Author (Jane Doe) >>",
+			5: "(Compressed)",
+		})
+		pdf := newTestPDF("<< /Size 6 /Root 2 0 R /Info 4 0 R >>", objstm)
+
+		m, err := DecodePDF(bytes.NewReader(pdf))
+		c.Assert(err, qt.IsNil)
+		c.Assert(m.PageCount, qt.Equals, 12)
+		c.Assert(m.Title, qt.Equals, "Compressed")
+		c.Assert(m.Author, qt.Equals, "Jane Doe")
+		c.Assert(m.Created.IsZero(), qt.IsTrue)
+	})
+
+	c.Run("Encrypted", func(c *qt.C) {
+		pdf := newTestPDF("<< /Size 4 /Root 1 0 R /Info 3 0 R /Encrypt 4 0 R >>",
+			"<< /Type /Catalog /Pages 2 0 R >>",
+			"<< /Type /Pages /Kids [] /Count 3 >>",
+			"<< /Title (\x8a\x01\xff) >>",
+		)
+
+		m, err := DecodePDF(bytes.NewReader(pdf))
+		c.Assert(err, qt.IsNil)
+		c.Assert(m.PageCount, qt.Equals, 3)
+		c.Assert(m.Title, qt.Equals, "")
+	})
+
+	_, err := DecodePDF(strings.NewReader("not a pdf"))
+	c.Assert(err, qt.ErrorMatches, "pdf: not a PDF document")
+}
+
+func TestParsePDFDate(t *testing.T) {
+	c := qt.New(t)
+
+	for _, test := range []struct {
+		in     string
+		expect time.Time
+	}{
+		{"D:20210315143059Z", time.Date(2021, 3, 15, 14, 30, 59, 0, time.UTC)},
+		{"D:20210315143059-05'30'", time.Date(2021, 3, 15, 20, 0, 59, 0, time.UTC)},
+		{"D:20210315", time.Date(2021, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{"2021", time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"D:21", time.Time{}},
+		{"", time.Time{}},
+	} {
+		c.Assert(parsePDFDate(test.in).Equal(test.expect), qt.IsTrue, qt.Commentf(test.in))
+	}
+}
+
+func TestDecodeEPUB(t *testing.T) {
+	c := qt.New(t)
+
+	var b bytes.Buffer
+	zw := zip.NewWriter(&b)
+	for name, content := range map[string]string{
+		"mimetype": "application/epub+zip",
+		"META-INF/container.xml": `<?xml version="1.0"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles>
+    <rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/>
+  </rootfiles>
+</container>`,
+		"OEBPS/content.opf": `<?xml version="1.0" encoding="UTF-8"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+    <dc:title> The Book </dc:title>
+    <dc:creator>Jane Doe</dc:creator>
+    <dc:creator>John Doe</dc:creator>
+    <dc:date>2020-06-01</dc:date>
+  </metadata>
+</package>`,
+	} {
+		w, err := zw.Create(name)
+		c.Assert(err, qt.IsNil)
+		_, err = w.Write([]byte(content))
+		c.Assert(err, qt.IsNil)
+	}
+	c.Assert(zw.Close(), qt.IsNil)
+
+	m, err := DecodeEPUB(bytes.NewReader(b.Bytes()), int64(b.Len()))
+	c.Assert(err, qt.IsNil)
+	c.Assert(m, qt.DeepEquals, &Metadata{
+		Title:   "The Book",
+		Author:  "Jane Doe, John Doe",
+		Created: time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC),
+	})
+
+	_, err = DecodeEPUB(strings.NewReader("not a zip"), 9)
+	c.Assert(err, qt.Not(qt.IsNil))
+}
@@ -0,0 +1,113 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package documents
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const epubContainerFilename = "META-INF/container.xml"
+
+var epubDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02",
+	"2006-01",
+	"2006",
+}
+
+// DecodeEPUB reads the metadata of the EPUB document in r.
+func DecodeEPUB(r io.ReaderAt, size int64) (*Metadata, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, errors.Wrap(err, "epub")
+	}
+
+	var container struct {
+		Rootfiles []struct {
+			FullPath string `xml:"full-path,attr"`
+		} `xml:"rootfiles>rootfile"`
+	}
+
+	if err := decodeZipXML(zr, epubContainerFilename, &container); err != nil {
+		return nil, err
+	}
+
+	if len(container.Rootfiles) == 0 {
+		return nil, errors.New("epub: no rootfile found")
+	}
+
+	var pkg struct {
+		Titles   []string `xml:"metadata>title"`
+		Creators []string `xml:"metadata>creator"`
+		Dates    []string `xml:"metadata>date"`
+	}
+
+	if err := decodeZipXML(zr, path.Clean(container.Rootfiles[0].FullPath), &pkg); err != nil {
+		return nil, err
+	}
+
+	m := &Metadata{}
+
+	if len(pkg.Titles) > 0 {
+		m.Title = strings.TrimSpace(pkg.Titles[0])
+	}
+
+	var creators []string
+	for _, creator := range pkg.Creators {
+		if creator = strings.TrimSpace(creator); creator != "" {
+			creators = append(creators, creator)
+		}
+	}
+	m.Author = strings.Join(creators, ", ")
+
+	if len(pkg.Dates) > 0 {
+		m.Created = parseEPUBDate(strings.TrimSpace(pkg.Dates[0]))
+	}
+
+	return m, nil
+}
+
+func decodeZipXML(zr *zip.Reader, name string, v interface{}) error {
+	for _, f := range zr.File {
+		if f.Name != name {
+			continue
+		}
+		r, err := f.Open()
+		if err != nil {
+			return errors.Wrapf(err, "epub: failed to open %q", name)
+		}
+		defer r.Close()
+		if err := xml.NewDecoder(r).Decode(v); err != nil {
+			return errors.Wrapf(err, "epub: failed to decode %q", name)
+		}
+		return nil
+	}
+	return errors.Errorf("epub: %q not found", name)
+}
+
+func parseEPUBDate(s string) time.Time {
+	for _, layout := range epubDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,424 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package documents
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	pdfObjRe       = regexp.MustCompile(`(\d+)\s+\d+\s+obj\b`)
+	pdfInfoRe      = regexp.MustCompile(`/Info\s+(\d+)\s+\d+\s+R`)
+	pdfRootRe      = regexp.MustCompile(`/Root\s+(\d+)\s+\d+\s+R`)
+	pdfPagesRe     = regexp.MustCompile(`/Pages\s+(\d+)\s+\d+\s+R`)
+	pdfCountRe     = regexp.MustCompile(`/Count\s+(\d+)`)
+	pdfTypePagesRe = regexp.MustCompile(`/Type\s*/Pages\b`)
+	pdfObjStmRe    = regexp.MustCompile(`/Type\s*/ObjStm\b`)
+	pdfNRe         = regexp.MustCompile(`/N\s+(\d+)`)
+	pdfFirstRe     = regexp.MustCompile(`/First\s+(\d+)`)
+	pdfRefRe       = regexp.MustCompile(`^(\d+)\s+\d+\s+R`)
+	pdfEncryptRe   = regexp.MustCompile(`/Encrypt\b`)
+	pdfStreamRe    = regexp.MustCompile(`>>\s*stream(\r\n|\n|\r)`)
+)
+
+// DecodePDF reads the metadata of the PDF document in r.
+//
+// The page count is read from the document catalog, and the title, author
+// and creation date from the document information dictionary. Compressed
+// object streams are supported, but the information dictionary is ignored
+// in encrypted documents.
+func DecodePDF(r io.Reader) (*Metadata, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.HasPrefix(b, []byte("%PDF-")) {
+		return nil, errors.New("pdf: not a PDF document")
+	}
+
+	d := &pdfDocument{data: b, objects: parsePDFObjects(b)}
+
+	m := &Metadata{PageCount: d.pageCount()}
+
+	if pdfEncryptRe.Match(b) {
+		return m, nil
+	}
+
+	if num, ok := lastRef(b, pdfInfoRe); ok {
+		info := d.objects[num]
+		m.Title = d.stringValue(info, "Title")
+		m.Author = d.stringValue(info, "Author")
+		m.Created = parsePDFDate(d.stringValue(info, "CreationDate"))
+	}
+
+	return m, nil
+}
+
+type pdfDocument struct {
+	data []byte
+
+	// Maps object numbers to the object bodies, without the stream data.
+	objects map[int][]byte
+}
+
+func (d *pdfDocument) pageCount() int {
+	if root, ok := lastRef(d.data, pdfRootRe); ok {
+		if pages, ok := firstInt(d.objects[root], pdfPagesRe); ok {
+			if n, ok := firstInt(d.objects[pages], pdfCountRe); ok {
+				return n
+			}
+		}
+	}
+
+	// Fall back to the largest page tree node.
+	var count int
+	for _, obj := range d.objects {
+		if !pdfTypePagesRe.Match(obj) {
+			continue
+		}
+		if n, ok := firstInt(obj, pdfCountRe); ok && n > count {
+			count = n
+		}
+	}
+
+	return count
+}
+
+// stringValue returns the text string for key in the dictionary dict,
+// resolving any indirect reference.
+func (d *pdfDocument) stringValue(dict []byte, key string) string {
+	name := []byte("/" + key)
+	for i := 0; ; {
+		j := bytes.Index(dict[i:], name)
+		if j == -1 {
+			return ""
+		}
+		i += j + len(name)
+		if i < len(dict) && !isPDFDelimiter(dict[i]) {
+			// Another key with this prefix.
+			continue
+		}
+
+		v := bytes.TrimLeft(dict[i:], " \t\r\n\f\x00")
+		if m := pdfRefRe.FindSubmatch(v); m != nil {
+			num, _ := strconv.Atoi(string(m[1]))
+			v = bytes.TrimLeft(d.objects[num], " \t\r\n\f\x00")
+		}
+
+		s, ok := parsePDFString(v)
+		if !ok {
+			return ""
+		}
+		return decodePDFText(s)
+	}
+}
+
+// parsePDFObjects finds the objects in the PDF document b, including those
+// in compressed object streams. For objects defined more than once, e.g. in
+// incremental updates, the last definition wins.
+func parsePDFObjects(b []byte) map[int][]byte {
+	objects := make(map[int][]byte)
+
+	for _, loc := range pdfObjRe.FindAllSubmatchIndex(b, -1) {
+		num, err := strconv.Atoi(string(b[loc[2]:loc[3]]))
+		if err != nil {
+			continue
+		}
+		body := b[loc[1]:]
+		end := bytes.Index(body, []byte("endobj"))
+		if end == -1 {
+			continue
+		}
+		objects[num] = body[:end]
+	}
+
+	compressed := make(map[int][]byte)
+	for _, obj := range objects {
+		if !pdfObjStmRe.Match(obj) {
+			continue
+		}
+		for num, body := range parsePDFObjectStream(obj) {
+			compressed[num] = body
+		}
+	}
+
+	for num, body := range compressed {
+		if _, found := objects[num]; !found {
+			objects[num] = body
+		}
+	}
+
+	for num, obj := range objects {
+		// Strip any stream data, we only need the dictionaries.
+		if loc := pdfStreamRe.FindIndex(obj); loc != nil {
+			objects[num] = obj[:loc[0]+2]
+		}
+	}
+
+	return objects
+}
+
+// parsePDFObjectStream returns the objects in the object stream obj.
+func parsePDFObjectStream(obj []byte) map[int][]byte {
+	loc := pdfStreamRe.FindIndex(obj)
+	if loc == nil {
+		return nil
+	}
+	dict, data := obj[:loc[0]], obj[loc[1]:]
+	if !bytes.Contains(dict, []byte("/FlateDecode")) {
+		return nil
+	}
+
+	n, ok1 := firstInt(dict, pdfNRe)
+	first, ok2 := firstInt(dict, pdfFirstRe)
+	if !ok1 || !ok2 {
+		return nil
+	}
+
+	zr, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil
+	}
+	defer zr.Close()
+	// A stream may end with garbage after the compressed data, so ignore
+	// any error as long as we got the data.
+	data, _ = ioutil.ReadAll(zr)
+
+	if first > len(data) {
+		return nil
+	}
+
+	header := strings.Fields(string(data[:first]))
+	if len(header) < 2*n {
+		return nil
+	}
+
+	objects := make(map[int][]byte)
+	for k := 0; k < n; k++ {
+		num, err1 := strconv.Atoi(header[2*k])
+		start, err2 := strconv.Atoi(header[2*k+1])
+		if err1 != nil || err2 != nil {
+			return objects
+		}
+		end := len(data) - first
+		if k+1 < n {
+			if end, err1 = strconv.Atoi(header[2*k+3]); err1 != nil {
+				return objects
+			}
+		}
+		if start < 0 || start > end || first+end > len(data) {
+			return objects
+		}
+		objects[num] = data[first+start : first+end]
+	}
+
+	return objects
+}
+
+// parsePDFString parses the literal or hexadecimal string at the start of b.
+func parsePDFString(b []byte) ([]byte, bool) {
+	if len(b) == 0 {
+		return nil, false
+	}
+
+	switch b[0] {
+	case '(':
+		return parsePDFLiteralString(b[1:])
+	case '<':
+		end := bytes.IndexByte(b, '>')
+		if end == -1 || bytes.HasPrefix(b, []byte("<<")) {
+			return nil, false
+		}
+		h := bytes.Map(func(r rune) rune {
+			if strings.ContainsRune(" \t\r\n\f", r) {
+				return -1
+			}
+			return r
+		}, b[1:end])
+		if len(h)%2 == 1 {
+			h = append(h, '0')
+		}
+		s, err := hex.DecodeString(string(h))
+		return s, err == nil
+	}
+
+	return nil, false
+}
+
+func parsePDFLiteralString(b []byte) ([]byte, bool) {
+	var (
+		s     []byte
+		depth = 1
+	)
+
+	for i := 0; i < len(b); i++ {
+		c := b[i]
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return s, true
+			}
+		case '\\':
+			i++
+			if i >= len(b) {
+				return nil, false
+			}
+			c = b[i]
+			switch c {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r':
+				// Line continuation.
+				if i+1 < len(b) && b[i+1] == '\n' {
+					i++
+				}
+				continue
+			case '\n':
+				continue
+			default:
+				if c >= '0' && c <= '7' {
+					v := int(c - '0')
+					for k := 0; k < 2 && i+1 < len(b) && b[i+1] >= '0' && b[i+1] <= '7'; k++ {
+						i++
+						v = v*8 + int(b[i]-'0')
+					}
+					c = byte(v)
+				}
+			}
+		}
+		s = append(s, c)
+	}
+
+	return nil, false
+}
+
+// decodePDFText decodes a PDF text string, which is either UTF-16BE with a
+// byte order mark, UTF-8 with a byte order mark or PDFDocEncoding, which we
+// treat as Latin-1.
+func decodePDFText(s []byte) string {
+	switch {
+	case bytes.HasPrefix(s, []byte{0xfe, 0xff}):
+		s = s[2:]
+		u := make([]uint16, len(s)/2)
+		for i := range u {
+			u[i] = uint16(s[2*i])<<8 | uint16(s[2*i+1])
+		}
+		return strings.TrimSpace(string(utf16.Decode(u)))
+	case bytes.HasPrefix(s, []byte{0xef, 0xbb, 0xbf}):
+		return strings.TrimSpace(string(s[3:]))
+	}
+
+	r := make([]rune, len(s))
+	for i, c := range s {
+		r[i] = rune(c)
+	}
+	return strings.TrimSpace(string(r))
+}
+
+// parsePDFDate parses a PDF date on the form D:YYYYMMDDHHmmSSOHH'mm',
+// where all but the year are optional.
+func parsePDFDate(s string) time.Time {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "D:")
+
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	digits, rest := s[:i], s[i:]
+	if len(digits) > 14 {
+		digits = digits[:14]
+	}
+	digits = digits[:len(digits)-len(digits)%2]
+	if len(digits) < 4 {
+		return time.Time{}
+	}
+
+	t, err := time.Parse("20060102150405"[:len(digits)], digits)
+	if err != nil {
+		return time.Time{}
+	}
+
+	if rest == "" || rest[0] == 'Z' {
+		return t
+	}
+
+	var sign int
+	switch rest[0] {
+	case '+':
+		sign = 1
+	case '-':
+		sign = -1
+	default:
+		return t
+	}
+
+	offset := strings.ReplaceAll(rest[1:], "'", "")
+	var hh, mm int
+	if len(offset) >= 2 {
+		hh, _ = strconv.Atoi(offset[:2])
+	}
+	if len(offset) >= 4 {
+		mm, _ = strconv.Atoi(offset[2:4])
+	}
+
+	loc := time.FixedZone("", sign*(hh*3600+mm*60))
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, loc)
+}
+
+func isPDFDelimiter(c byte) bool {
+	return strings.IndexByte(" \t\r\n\f\x00()<>[]{}/%", c) != -1
+}
+
+// lastRef returns the object number in the last match of re in b.
+func lastRef(b []byte, re *regexp.Regexp) (int, bool) {
+	matches := re.FindAllSubmatch(b, -1)
+	if len(matches) == 0 {
+		return 0, false
+	}
+	num, err := strconv.Atoi(string(matches[len(matches)-1][1]))
+	return num, err == nil
+}
+
+// firstInt returns the integer in the first match of re in b.
+func firstInt(b []byte, re *regexp.Regexp) (int, bool) {
+	m := re.FindSubmatch(b)
+	if m == nil {
+		return 0, false
+	}
+	n, err := strconv.Atoi(string(m[1]))
+	return n, err == nil
+}
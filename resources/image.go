@@ -43,6 +43,7 @@ import (
 
 	"github.com/gohugoio/hugo/helpers"
 	"github.com/gohugoio/hugo/resources/images"
+	"github.com/spf13/cast"
 
 	// Blind import for image.Decode
 	_ "golang.org/x/image/webp"
@@ -203,6 +204,14 @@ func (i *imageResource) Fill(spec string) (resource.Image, error) {
 		return nil, err
 	}
 
+	fp, found, err := i.focalPoint()
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		conf.SetFocalPoint(fp)
+	}
+
 	img, err := i.doWithImageConfig(conf, func(src image.Image) (image.Image, error) {
 		return i.Proc.ApplyFiltersFromConfig(src, conf)
 	})
@@ -225,6 +234,90 @@ func (i *imageResource) Fill(spec string) (resource.Image, error) {
 	return img, err
 }
 
+// Profile processes the image with the named profile in the imaging config,
+// e.g. "fill 1600x900 webp q80".
+func (i *imageResource) Profile(name string) (resource.Image, error) {
+	action, spec, found := i.Proc.Cfg.Cfg.Profile(name)
+	if !found {
+		return nil, errors.Errorf("image profile %q not found", name)
+	}
+
+	switch action {
+	case "resize":
+		return i.Resize(spec)
+	case "fit":
+		return i.Fit(spec)
+	default:
+		return i.Fill(spec)
+	}
+}
+
+// Derived returns the images processed with the profiles listed in the
+// profiles resource param, keyed by profile name. The param is usually
+// set by the profile rules in the imaging config, see AssignImageProfiles.
+func (i *imageResource) Derived() (map[string]resource.Image, error) {
+	profiles := cast.ToStringSlice(i.Params()["profiles"])
+	derived := make(map[string]resource.Image, len(profiles))
+
+	for _, name := range profiles {
+		img, err := i.Profile(name)
+		if err != nil {
+			return nil, err
+		}
+		derived[strings.ToLower(name)] = img
+	}
+
+	return derived, nil
+}
+
+// focalPoint returns the focal point of the image set in the focalPoint
+// resource param, e.g. in front matter, or in the Exif metadata.
+func (i *imageResource) focalPoint() (images.FocalPoint, bool, error) {
+	if v, found := i.Params()["focalpoint"]; found {
+		fp, err := images.DecodeFocalPoint(v)
+		if err != nil {
+			return fp, false, errors.Wrapf(err, "image %q", i.Name())
+		}
+		return fp, true, nil
+	}
+
+	if x := i.Exif(); x != nil {
+		fp, found := images.FocalPointFromExif(x.Tags, i.root.Width(), i.root.Height())
+		return fp, found, nil
+	}
+
+	return images.FocalPoint{}, false, nil
+}
+
+// Publish publishes the original image with the metadata removed as set in
+// the stripMetadata resource param or the imaging config.
+func (i *imageResource) Publish() error {
+	mode, err := i.stripMetadataMode()
+	if err != nil {
+		return err
+	}
+
+	if mode == images.StripMetadataNone {
+		return i.baseResource.Publish()
+	}
+
+	return i.publishWith(func(dst io.Writer, src io.Reader) error {
+		return images.StripMetadata(dst, src, i.Format, mode)
+	})
+}
+
+func (i *imageResource) stripMetadataMode() (string, error) {
+	if v, found := i.Params()["stripmetadata"]; found {
+		mode, err := images.DecodeStripMetadataMode(cast.ToString(v))
+		if err != nil {
+			return "", errors.Wrapf(err, "image %q", i.Name())
+		}
+		return mode, nil
+	}
+
+	return i.Proc.Cfg.Cfg.StripMetadata, nil
+}
+
 func (i *imageResource) Filter(filters ...interface{}) (resource.Image, error) {
 	conf := images.GetDefaultImageConfig("filter", i.Proc.Cfg)
 
@@ -266,6 +359,14 @@ func (i *imageResource) doWithImageConfig(conf images.ImageConfig, f func(src im
 			return nil, nil, &os.PathError{Op: errOp, Path: errPath, Err: err}
 		}
 
+		if a, ok := src.(*images.AnimatedImage); ok {
+			if conf.TargetFormat.SupportsAnimation() {
+				src = a.LimitFrames(conf.MaxFrames)
+			} else {
+				src = a.Frames[0]
+			}
+		}
+
 		converted, err := f(src)
 		if err != nil {
 			return nil, nil, &os.PathError{Op: errOp, Path: errPath, Err: err}
@@ -281,10 +382,12 @@ func (i *imageResource) doWithImageConfig(conf images.ImageConfig, f func(src im
 			if bgColor == nil {
 				bgColor = i.Proc.Cfg.BgColor
 			}
-			tmp := image.NewRGBA(converted.Bounds())
-			draw.Draw(tmp, tmp.Bounds(), image.NewUniform(bgColor), image.Point{}, draw.Src)
-			draw.Draw(tmp, tmp.Bounds(), converted, converted.Bounds().Min, draw.Over)
-			converted = tmp
+			converted, _ = images.ApplyToFrames(converted, func(img image.Image) (image.Image, error) {
+				tmp := image.NewRGBA(img.Bounds())
+				draw.Draw(tmp, tmp.Bounds(), image.NewUniform(bgColor), image.Point{}, draw.Src)
+				draw.Draw(tmp, tmp.Bounds(), img, img.Bounds().Min, draw.Over)
+				return tmp, nil
+			})
 		}
 
 		if conf.TargetFormat == images.PNG {
@@ -334,8 +437,7 @@ func (i *imageResource) DecodeImage() (image.Image, error) {
 		return nil, _errors.Wrap(err, "failed to open image for decode")
 	}
 	defer f.Close()
-	img, _, err := image.Decode(f)
-	return img, err
+	return images.Decode(f)
 }
 
 func (i *imageResource) clone(img image.Image) *imageResource {
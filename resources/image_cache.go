@@ -20,17 +20,24 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/BurntSushi/locker"
 	"github.com/gohugoio/hugo/resources/images"
 
 	"github.com/gohugoio/hugo/cache/filecache"
 	"github.com/gohugoio/hugo/helpers"
 )
 
+// imageCache holds the processed images. It's shared between the languages,
+// see Spec.ShareImageCache.
 type imageCache struct {
 	pathSpec *helpers.PathSpec
 
 	fileCache *filecache.Cache
 
+	// Makes sure an image variation is only processed once when requested
+	// concurrently, e.g. by languages rendered in parallel.
+	nlocker *locker.Locker
+
 	mu    sync.RWMutex
 	store map[string]*resourceAdapter
 }
@@ -67,6 +74,11 @@ func (c *imageCache) getOrCreate(
 	createImage func() (*imageResource, image.Image, error)) (*resourceAdapter, error) {
 	relTarget := parent.relTargetPathFromConfig(conf)
 	memKey := parent.relTargetPathForRel(relTarget.path(), false, false, false)
+	if dirs := parent.getResourcePaths().baseTargetPathDirs; len(dirs) > 0 {
+		// In multihost mode the image is published to the directory of
+		// each language using it.
+		memKey = strings.Join(dirs, ",") + "/" + memKey
+	}
 	memKey = c.normalizeKey(memKey)
 
 	// For the file cache we want to generate and store it once if possible.
@@ -85,6 +97,19 @@ func (c *imageCache) getOrCreate(
 		return cachedImage, nil
 	}
 
+	// This is a potentially long running operation, so get a named lock.
+	c.nlocker.Lock(memKey)
+	defer c.nlocker.Unlock(memKey)
+
+	// Double check the in-memory store.
+	c.mu.RLock()
+	cachedImage, found = c.store[memKey]
+	c.mu.RUnlock()
+
+	if found {
+		return cachedImage, nil
+	}
+
 	var img *imageResource
 
 	// These funcs are protected by a named lock.
@@ -139,7 +164,8 @@ func (c *imageCache) getOrCreate(
 	// The definition of this counter is not that we have processed that amount
 	// (e.g. resized etc.), it can be fetched from file cache,
 	//  but the count of processed image variations for this site.
-	c.pathSpec.ProcessingStats.Incr(&c.pathSpec.ProcessingStats.ProcessedImages)
+	stats := parent.getSpec().ProcessingStats
+	stats.Incr(&stats.ProcessedImages)
 
 	_, err := c.fileCache.ReadOrCreate(fileKey, read, create)
 	if err != nil {
@@ -149,13 +175,9 @@ func (c *imageCache) getOrCreate(
 	// The file is now stored in this cache.
 	img.setSourceFs(c.fileCache.Fs)
 
-	c.mu.Lock()
-	if cachedImage, found = c.store[memKey]; found {
-		c.mu.Unlock()
-		return cachedImage, nil
-	}
-
 	imgAdapter := newResourceAdapter(parent.getSpec(), true, img)
+
+	c.mu.Lock()
 	c.store[memKey] = imgAdapter
 	c.mu.Unlock()
 
@@ -163,5 +185,5 @@ func (c *imageCache) getOrCreate(
 }
 
 func newImageCache(fileCache *filecache.Cache, ps *helpers.PathSpec) *imageCache {
-	return &imageCache{fileCache: fileCache, pathSpec: ps, store: make(map[string]*resourceAdapter)}
+	return &imageCache{fileCache: fileCache, pathSpec: ps, nlocker: locker.NewLocker(), store: make(map[string]*resourceAdapter)}
 }
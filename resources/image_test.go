@@ -16,6 +16,7 @@ package resources
 import (
 	"fmt"
 	"image"
+	"image/gif"
 	"io/ioutil"
 	"math/big"
 	"math/rand"
@@ -39,7 +40,9 @@ import (
 	"github.com/gohugoio/hugo/helpers"
 
 	"github.com/gohugoio/hugo/media"
+	"github.com/gohugoio/hugo/output"
 	"github.com/gohugoio/hugo/resources/images"
+	"github.com/gohugoio/hugo/resources/images/exif"
 	"github.com/gohugoio/hugo/resources/resource"
 	"github.com/google/go-cmp/cmp"
 
@@ -330,6 +333,48 @@ func TestImageTransformConcurrent(t *testing.T) {
 	wg.Wait()
 }
 
+func TestImageTransformConcurrentSharedCache(t *testing.T) {
+	c := qt.New(t)
+
+	spec1, workDir := newTestResourceOsFs(c)
+	defer func() {
+		os.Remove(workDir)
+	}()
+
+	// E.g. the specs of two languages rendered in parallel.
+	spec2, err := NewSpec(spec1.PathSpec, spec1.FileCaches, nil, nil, nil, output.DefaultFormats, media.DefaultTypes)
+	c.Assert(err, qt.IsNil)
+	spec2.ShareImageCache(spec1)
+
+	images := []resource.Image{
+		fetchImageForSpec(spec1, c, "sunset.jpg"),
+		fetchImageForSpec(spec2, c, "sunset.jpg"),
+	}
+
+	var wg sync.WaitGroup
+	resized := make([]resource.Image, 20)
+	for i := 0; i < len(resized); i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			img, err := images[i%2].Resize("300x")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resized[i] = img
+		}(i)
+	}
+	wg.Wait()
+
+	for _, img := range resized {
+		c.Assert(img, qt.Equals, resized[0])
+	}
+
+	// Processed once.
+	c.Assert(spec1.ProcessingStats.ProcessedImages, qt.Equals, uint64(1))
+}
+
 func TestImageWithMetadata(t *testing.T) {
 	c := qt.New(t)
 
@@ -351,6 +396,178 @@ func TestImageWithMetadata(t *testing.T) {
 	c.Assert(resized.Name(), qt.Equals, "Sunset #1")
 }
 
+func TestImageFillFocalPoint(t *testing.T) {
+	c := qt.New(t)
+
+	image := fetchSunset(c)
+
+	meta := []map[string]interface{}{
+		{
+			"src": "*.jpg",
+			"params": map[string]interface{}{
+				"focalPoint": []interface{}{0.25, 0.5},
+			},
+		},
+	}
+
+	c.Assert(AssignMetadata(meta, image), qt.IsNil)
+
+	filled, err := image.Fill("200x200")
+	c.Assert(err, qt.IsNil)
+	c.Assert(filled.RelPermalink(), qt.Equals, "/a/sunset_hu59e56ffff1bc1d8d122b1403d34e039f_90587_200x200_fill_q68_linear_fp250_500.jpg")
+	c.Assert(filled.Width(), qt.Equals, 200)
+	c.Assert(filled.Height(), qt.Equals, 200)
+
+	// An anchor set for the image is used instead.
+	smart, err := image.Fill("200x200 smart")
+	c.Assert(err, qt.IsNil)
+	c.Assert(smart.RelPermalink(), qt.Equals, "/a/sunset_hu59e56ffff1bc1d8d122b1403d34e039f_90587_200x200_fill_q68_linear_smart1.jpg")
+
+	meta[0]["params"] = map[string]interface{}{"focalPoint": "2 3"}
+	image = fetchSunset(c)
+	c.Assert(AssignMetadata(meta, image), qt.IsNil)
+	_, err = image.Fill("200x200")
+	c.Assert(err, qt.ErrorMatches, ".*invalid focal point.*")
+}
+
+func TestImageAnimatedGIF(t *testing.T) {
+	c := qt.New(t)
+
+	spec := newTestResourceSpec(specDescriptor{c: c})
+	fileCache := spec.FileCaches.ImageCache().Fs
+
+	decodeGIF := func(img resource.Image) *gif.GIF {
+		c.Helper()
+		f, err := fileCache.Open(path.Base(img.RelPermalink()))
+		c.Assert(err, qt.IsNil)
+		defer f.Close()
+		g, err := gif.DecodeAll(f)
+		c.Assert(err, qt.IsNil)
+		return g
+	}
+
+	image := fetchImageForSpec(spec, c, "giphy.gif")
+	c.Assert(image.Width(), qt.Equals, 40)
+
+	resized, err := image.Resize("20x")
+	c.Assert(err, qt.IsNil)
+	c.Assert(resized.Width(), qt.Equals, 20)
+	c.Assert(resized.Height(), qt.Equals, 15)
+	g := decodeGIF(resized)
+	c.Assert(g.Image, qt.HasLen, 4)
+	c.Assert(g.Delay, qt.DeepEquals, []int{20, 20, 20, 20})
+	c.Assert(g.Config.Width, qt.Equals, 20)
+
+	// Formats without animation get the first frame.
+	png, err := image.Resize("20x png")
+	c.Assert(err, qt.IsNil)
+	c.Assert(png.MediaType().String(), qt.Equals, "image/png")
+	c.Assert(png.Width(), qt.Equals, 20)
+
+	spec.imaging.Cfg.Cfg.MaxFrames = 2
+	image = fetchImageForSpec(spec, c, "giphy.gif")
+	limited, err := image.Resize("20x")
+	c.Assert(err, qt.IsNil)
+	c.Assert(limited.RelPermalink(), qt.Contains, "_mf2_")
+	g = decodeGIF(limited)
+	c.Assert(g.Image, qt.HasLen, 2)
+	c.Assert(g.Delay, qt.DeepEquals, []int{40, 40})
+}
+
+func TestImageProfiles(t *testing.T) {
+	c := qt.New(t)
+
+	spec := newTestResourceSpec(specDescriptor{c: c})
+	spec.imaging.Cfg.Cfg.Profiles = map[string]string{
+		"hero":  "fill 200x100 png",
+		"thumb": "fit 100x100",
+	}
+	spec.imaging.Cfg.Cfg.ProfileRules = []images.ProfileRule{
+		{Section: "blog", Pattern: "*.jpg", Profiles: []string{"hero", "thumb"}},
+	}
+
+	image := fetchImageForSpec(spec, c, "sunset.jpg")
+
+	hero, err := image.Profile("Hero")
+	c.Assert(err, qt.IsNil)
+	c.Assert(hero.Width(), qt.Equals, 200)
+	c.Assert(hero.Height(), qt.Equals, 100)
+	c.Assert(hero.MediaType(), eq, media.PNGType)
+
+	_, err = image.Profile("foo")
+	c.Assert(err, qt.ErrorMatches, `image profile "foo" not found`)
+
+	derived, err := image.Derived()
+	c.Assert(err, qt.IsNil)
+	c.Assert(derived, qt.HasLen, 0)
+
+	AssignImageProfiles("docs", image)
+	c.Assert(image.Params()["profiles"], qt.IsNil)
+
+	AssignImageProfiles("blog", image)
+	derived, err = image.Derived()
+	c.Assert(err, qt.IsNil)
+	c.Assert(derived, qt.HasLen, 2)
+	c.Assert(derived["hero"].RelPermalink(), qt.Equals, hero.RelPermalink())
+	c.Assert(derived["thumb"].Width(), qt.Equals, 100)
+	c.Assert(derived["thumb"].Height(), qt.Equals, 62)
+
+	// Params set in front matter wins.
+	image = fetchImageForSpec(spec, c, "sunset.jpg")
+	c.Assert(AssignMetadata([]map[string]interface{}{{"src": "*", "params": map[string]interface{}{"profiles": []string{"thumb"}}}}, image), qt.IsNil)
+	AssignImageProfiles("blog", image)
+	derived, err = image.Derived()
+	c.Assert(err, qt.IsNil)
+	c.Assert(derived, qt.HasLen, 1)
+	c.Assert(derived["thumb"], qt.Not(qt.IsNil))
+}
+
+func TestImagePublishStripMetadata(t *testing.T) {
+	c := qt.New(t)
+
+	publish := func(mode string, params map[string]interface{}) (*exif.Exif, error) {
+		spec := newTestResourceSpec(specDescriptor{c: c})
+		spec.imaging.Cfg.Cfg.StripMetadata = mode
+
+		image := fetchImageForSpec(spec, c, "sunset.jpg")
+		if params != nil {
+			c.Assert(AssignMetadata([]map[string]interface{}{{"src": "*", "params": params}}, image), qt.IsNil)
+		}
+		if err := image.(resource.Source).Publish(); err != nil {
+			return nil, err
+		}
+
+		assertImageFile(c, spec.BaseFs.PublishFs, "a/sunset.jpg", 900, 562)
+		f, err := spec.BaseFs.PublishFs.Open(filepath.FromSlash("a/sunset.jpg"))
+		c.Assert(err, qt.IsNil)
+		defer f.Close()
+
+		return spec.imaging.DecodeExif(f)
+	}
+
+	x, err := publish(images.StripMetadataNone, nil)
+	c.Assert(err, qt.IsNil)
+	c.Assert(x.Lat, qt.Not(qt.Equals), float64(0))
+
+	x, err = publish(images.StripMetadataGPS, nil)
+	c.Assert(err, qt.IsNil)
+	c.Assert(x.Lat, qt.Equals, float64(0))
+	c.Assert(x.Long, qt.Equals, float64(0))
+	c.Assert(x.Date.Format("2006-01-02"), qt.Equals, "2017-10-27")
+
+	// The resource param overrides the config.
+	x, err = publish(images.StripMetadataGPS, map[string]interface{}{"stripMetadata": "all"})
+	c.Assert(err, qt.IsNil)
+	c.Assert(x, qt.IsNil)
+
+	x, err = publish(images.StripMetadataAll, map[string]interface{}{"stripMetadata": "none"})
+	c.Assert(err, qt.IsNil)
+	c.Assert(x.Lat, qt.Not(qt.Equals), float64(0))
+
+	_, err = publish(images.StripMetadataNone, map[string]interface{}{"stripMetadata": "foo"})
+	c.Assert(err, qt.ErrorMatches, ".*invalid stripMetadata value.*")
+}
+
 func TestImageResize8BitPNG(t *testing.T) {
 	c := qt.New(t)
 
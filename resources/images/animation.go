@@ -0,0 +1,492 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package images
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/pkg/errors"
+
+	// Blind import for image.Decode
+	_ "golang.org/x/image/webp"
+)
+
+var _ image.Image = (*AnimatedImage)(nil)
+
+// AnimatedImage is an image with more than one frame, e.g. an animated GIF
+// or WebP. It implements image.Image by way of its first frame, so it can be
+// used where a still image is expected.
+type AnimatedImage struct {
+	// The frames, all of them the size of the canvas.
+	Frames []image.Image
+
+	// How long to show each frame.
+	Delays []time.Duration
+
+	// The number of times to play the animation. 0 means forever.
+	LoopCount int
+
+	// The palette of each frame in the source GIF, if any, used when
+	// encoding the frames to GIF.
+	palettes []color.Palette
+}
+
+// ColorModel returns the color model of the first frame.
+func (a *AnimatedImage) ColorModel() color.Model {
+	return a.Frames[0].ColorModel()
+}
+
+// Bounds returns the bounds of the canvas.
+func (a *AnimatedImage) Bounds() image.Rectangle {
+	return a.Frames[0].Bounds()
+}
+
+// At returns the color of the pixel at (x, y) in the first frame.
+func (a *AnimatedImage) At(x, y int) color.Color {
+	return a.Frames[0].At(x, y)
+}
+
+// Opaque reports whether all the frames are opaque.
+func (a *AnimatedImage) Opaque() bool {
+	for _, f := range a.Frames {
+		if !IsOpaque(f) {
+			return false
+		}
+	}
+	return true
+}
+
+// LimitFrames returns an animation with at most n frames, picked at even
+// intervals, with the delays of the dropped frames added to the ones kept so
+// the animation runs at the same speed.
+func (a *AnimatedImage) LimitFrames(n int) *AnimatedImage {
+	if n <= 0 || len(a.Frames) <= n {
+		return a
+	}
+
+	b := &AnimatedImage{LoopCount: a.LoopCount}
+	for i := 0; i < n; i++ {
+		from, to := i*len(a.Frames)/n, (i+1)*len(a.Frames)/n
+		b.Frames = append(b.Frames, a.Frames[from])
+		var delay time.Duration
+		for _, d := range a.Delays[from:to] {
+			delay += d
+		}
+		b.Delays = append(b.Delays, delay)
+		if a.palettes != nil {
+			b.palettes = append(b.palettes, a.palettes[from])
+		}
+	}
+
+	return b
+}
+
+// ApplyToFrames applies f to img, or to each of its frames if it's an
+// AnimatedImage.
+func ApplyToFrames(img image.Image, f func(img image.Image) (image.Image, error)) (image.Image, error) {
+	a, ok := img.(*AnimatedImage)
+	if !ok {
+		return f(img)
+	}
+
+	b := *a
+	b.Frames = make([]image.Image, len(a.Frames))
+	for i, frame := range a.Frames {
+		var err error
+		if b.Frames[i], err = f(frame); err != nil {
+			return nil, err
+		}
+	}
+
+	return &b, nil
+}
+
+// Decode decodes the image in r. All the frames of an animated GIF or WebP
+// are decoded into an AnimatedImage.
+func Decode(r io.ReadSeeker) (image.Image, error) {
+	var header [16]byte
+	n, err := io.ReadFull(r, header[:])
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case n >= 6 && bytes.HasPrefix(header[:], []byte("GIF8")):
+		return decodeGIF(r)
+	case n == 16 && string(header[:4]) == "RIFF" && string(header[8:16]) == "WEBPVP8X":
+		b, err := ioutil.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		if len(b) > 20 && b[20]&webpAnimationFlag != 0 {
+			return decodeAnimatedWebP(b)
+		}
+		img, _, err := image.Decode(bytes.NewReader(b))
+		return img, err
+	}
+
+	img, _, err := image.Decode(r)
+	return img, err
+}
+
+func decodeGIF(r io.Reader) (image.Image, error) {
+	g, err := gif.DecodeAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(g.Image) == 1 {
+		return g.Image[0], nil
+	}
+
+	a := &AnimatedImage{LoopCount: gifLoopCountToPlays(g.LoopCount)}
+
+	canvas := image.NewNRGBA(image.Rect(0, 0, g.Config.Width, g.Config.Height))
+	for i, frame := range g.Image {
+		var previous *image.NRGBA
+		if g.Disposal != nil && g.Disposal[i] == gif.DisposalPrevious {
+			previous = cloneNRGBA(canvas)
+		}
+
+		draw.Draw(canvas, frame.Bounds(), frame, frame.Bounds().Min, draw.Over)
+		a.Frames = append(a.Frames, cloneNRGBA(canvas))
+		a.Delays = append(a.Delays, time.Duration(g.Delay[i])*10*time.Millisecond)
+		a.palettes = append(a.palettes, frame.Palette)
+
+		if g.Disposal != nil {
+			switch g.Disposal[i] {
+			case gif.DisposalBackground:
+				draw.Draw(canvas, frame.Bounds(), image.Transparent, image.Point{}, draw.Src)
+			case gif.DisposalPrevious:
+				canvas = previous
+			}
+		}
+	}
+
+	return a, nil
+}
+
+// EncodeGIF encodes a as an animated GIF.
+func (a *AnimatedImage) EncodeGIF(w io.Writer) error {
+	bounds := a.Bounds()
+	g := &gif.GIF{
+		LoopCount: playsToGIFLoopCount(a.LoopCount),
+		Config: image.Config{
+			ColorModel: color.Palette(palette.Plan9),
+			Width:      bounds.Dx(),
+			Height:     bounds.Dy(),
+		},
+	}
+
+	for i, frame := range a.Frames {
+		var p color.Palette
+		if a.palettes != nil {
+			p = a.palettes[i]
+		}
+		if p == nil {
+			p = palette.Plan9
+			if !IsOpaque(frame) {
+				p = append(color.Palette{color.Transparent}, p[:len(p)-1]...)
+			}
+		}
+		paletted := image.NewPaletted(frame.Bounds(), p)
+		draw.FloydSteinberg.Draw(paletted, paletted.Bounds(), frame, frame.Bounds().Min)
+
+		g.Image = append(g.Image, paletted)
+		g.Delay = append(g.Delay, int(a.Delays[i]/(10*time.Millisecond)))
+		// All frames cover the full canvas.
+		g.Disposal = append(g.Disposal, gif.DisposalBackground)
+	}
+
+	return gif.EncodeAll(w, g)
+}
+
+const (
+	webpAlphaFlag     = 0x10
+	webpAnimationFlag = 0x02
+
+	webpNoBlendFlag = 0x02
+	webpDisposeFlag = 0x01
+)
+
+type riffChunk struct {
+	id   string
+	data []byte
+}
+
+func (c riffChunk) writeTo(buf *bytes.Buffer) {
+	buf.WriteString(c.id)
+	binary.Write(buf, binary.LittleEndian, uint32(len(c.data)))
+	buf.Write(c.data)
+	if len(c.data)%2 == 1 {
+		buf.WriteByte(0)
+	}
+}
+
+func readRIFFChunks(b []byte) ([]riffChunk, error) {
+	var chunks []riffChunk
+	for len(b) > 0 {
+		if len(b) < 8 {
+			return nil, errors.New("webp: invalid chunk")
+		}
+		id, size := string(b[:4]), int(binary.LittleEndian.Uint32(b[4:8]))
+		b = b[8:]
+		if size > len(b) {
+			return nil, errors.Errorf("webp: invalid %s chunk size", id)
+		}
+		chunks = append(chunks, riffChunk{id: id, data: b[:size]})
+		size += size % 2
+		if size > len(b) {
+			size = len(b)
+		}
+		b = b[size:]
+	}
+	return chunks, nil
+}
+
+func readWebPChunks(b []byte) ([]riffChunk, error) {
+	if len(b) < 12 || string(b[:4]) != "RIFF" || string(b[8:12]) != "WEBP" {
+		return nil, errors.New("webp: invalid format")
+	}
+	size := int(binary.LittleEndian.Uint32(b[4:8])) + 8
+	if size > len(b) {
+		return nil, errors.New("webp: invalid RIFF size")
+	}
+	return readRIFFChunks(b[12:size])
+}
+
+func writeWebP(w io.Writer, chunks []riffChunk) error {
+	var buf bytes.Buffer
+	for _, c := range chunks {
+		c.writeTo(&buf)
+	}
+
+	var header bytes.Buffer
+	header.WriteString("RIFF")
+	binary.Write(&header, binary.LittleEndian, uint32(4+buf.Len()))
+	header.WriteString("WEBP")
+
+	if _, err := w.Write(header.Bytes()); err != nil {
+		return err
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func uint24(b []byte) int {
+	return int(b[0]) | int(b[1])<<8 | int(b[2])<<16
+}
+
+func putUint24(b []byte, v int) {
+	b[0], b[1], b[2] = byte(v), byte(v>>8), byte(v>>16)
+}
+
+func vp8xChunk(flags byte, width, height int) riffChunk {
+	data := make([]byte, 10)
+	data[0] = flags
+	putUint24(data[4:], width-1)
+	putUint24(data[7:], height-1)
+	return riffChunk{id: "VP8X", data: data}
+}
+
+func decodeAnimatedWebP(b []byte) (image.Image, error) {
+	chunks, err := readWebPChunks(b)
+	if err != nil {
+		return nil, err
+	}
+
+	var canvas *image.NRGBA
+	a := &AnimatedImage{}
+
+	for _, c := range chunks {
+		switch c.id {
+		case "VP8X":
+			if len(c.data) < 10 {
+				return nil, errors.New("webp: invalid VP8X chunk")
+			}
+			canvas = image.NewNRGBA(image.Rect(0, 0, uint24(c.data[4:])+1, uint24(c.data[7:])+1))
+		case "ANIM":
+			if len(c.data) < 6 {
+				return nil, errors.New("webp: invalid ANIM chunk")
+			}
+			a.LoopCount = int(binary.LittleEndian.Uint16(c.data[4:6]))
+		case "ANMF":
+			if canvas == nil || len(c.data) < 16 {
+				return nil, errors.New("webp: invalid ANMF chunk")
+			}
+			x, y := uint24(c.data[0:])*2, uint24(c.data[3:])*2
+			w, h := uint24(c.data[6:])+1, uint24(c.data[9:])+1
+			duration := uint24(c.data[12:])
+			flags := c.data[15]
+
+			frame, err := decodeWebPFrame(c.data[16:], w, h)
+			if err != nil {
+				return nil, err
+			}
+
+			r := image.Rect(x, y, x+w, y+h)
+			op := draw.Over
+			if flags&webpNoBlendFlag != 0 {
+				op = draw.Src
+			}
+			draw.Draw(canvas, r, frame, frame.Bounds().Min, op)
+
+			a.Frames = append(a.Frames, cloneNRGBA(canvas))
+			a.Delays = append(a.Delays, time.Duration(duration)*time.Millisecond)
+
+			if flags&webpDisposeFlag != 0 {
+				draw.Draw(canvas, r, image.Transparent, image.Point{}, draw.Src)
+			}
+		}
+	}
+
+	if len(a.Frames) == 0 {
+		return nil, errors.New("webp: no frames in animation")
+	}
+
+	if len(a.Frames) == 1 {
+		return a.Frames[0], nil
+	}
+
+	return a, nil
+}
+
+// decodeWebPFrame decodes the bitstream of a frame in an animated WebP,
+// given as an optional ALPH chunk and a VP8 chunk, or a VP8L chunk, by
+// wrapping it in a still WebP.
+func decodeWebPFrame(b []byte, width, height int) (image.Image, error) {
+	chunks, err := readRIFFChunks(b)
+	if err != nil {
+		return nil, err
+	}
+
+	var frameChunks []riffChunk
+	var hasAlpha bool
+	for _, c := range chunks {
+		switch c.id {
+		case "ALPH":
+			hasAlpha = true
+			frameChunks = append(frameChunks, c)
+		case "VP8 ", "VP8L":
+			frameChunks = append(frameChunks, c)
+		}
+	}
+
+	if hasAlpha {
+		frameChunks = append([]riffChunk{vp8xChunk(webpAlphaFlag, width, height)}, frameChunks...)
+	}
+
+	var buf bytes.Buffer
+	if err := writeWebP(&buf, frameChunks); err != nil {
+		return nil, err
+	}
+
+	img, _, err := image.Decode(&buf)
+	if err != nil {
+		return nil, errors.Wrap(err, "webp: failed to decode frame")
+	}
+
+	return img, nil
+}
+
+// encodeAnimatedWebP encodes a as an animated WebP, with each frame encoded
+// as a still WebP by encodeFrame.
+func (a *AnimatedImage) encodeAnimatedWebP(w io.Writer, encodeFrame func(w io.Writer, img image.Image) error) error {
+	bounds := a.Bounds()
+
+	var flags byte = webpAnimationFlag
+	if !a.Opaque() {
+		flags |= webpAlphaFlag
+	}
+
+	anim := make([]byte, 6)
+	binary.LittleEndian.PutUint16(anim[4:], uint16(a.LoopCount))
+
+	chunks := []riffChunk{
+		vp8xChunk(flags, bounds.Dx(), bounds.Dy()),
+		{id: "ANIM", data: anim},
+	}
+
+	for i, frame := range a.Frames {
+		var buf bytes.Buffer
+		if err := encodeFrame(&buf, frame); err != nil {
+			return err
+		}
+		frameChunks, err := readWebPChunks(buf.Bytes())
+		if err != nil {
+			return err
+		}
+
+		var data bytes.Buffer
+		header := make([]byte, 16)
+		putUint24(header[6:], bounds.Dx()-1)
+		putUint24(header[9:], bounds.Dy()-1)
+		putUint24(header[12:], int(a.Delays[i]/time.Millisecond))
+		// All frames cover the full canvas.
+		header[15] = webpNoBlendFlag
+		data.Write(header)
+		for _, c := range frameChunks {
+			switch c.id {
+			case "ALPH", "VP8 ", "VP8L":
+				c.writeTo(&data)
+			}
+		}
+
+		chunks = append(chunks, riffChunk{id: "ANMF", data: data.Bytes()})
+	}
+
+	return writeWebP(w, chunks)
+}
+
+func cloneNRGBA(src *image.NRGBA) *image.NRGBA {
+	dst := image.NewNRGBA(src.Rect)
+	copy(dst.Pix, src.Pix)
+	return dst
+}
+
+// gifLoopCountToPlays converts a GIF loop count, where 0 means forever and
+// -1 means once, to the number of times to play the animation.
+func gifLoopCountToPlays(loopCount int) int {
+	switch {
+	case loopCount == 0:
+		return 0
+	case loopCount < 0:
+		return 1
+	default:
+		return loopCount + 1
+	}
+}
+
+func playsToGIFLoopCount(plays int) int {
+	switch {
+	case plays == 0:
+		return 0
+	case plays == 1:
+		return -1
+	default:
+		return plays - 1
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package images
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func newTestGIF(c *qt.C) []byte {
+	p := color.Palette{color.Transparent, color.NRGBA{255, 0, 0, 255}, color.NRGBA{0, 0, 255, 255}}
+
+	g := &gif.GIF{LoopCount: 2}
+	for i, r := range []image.Rectangle{
+		image.Rect(0, 0, 20, 10),
+		image.Rect(0, 0, 10, 10),
+		image.Rect(10, 0, 20, 10),
+	} {
+		frame := image.NewPaletted(r, p)
+		for j := range frame.Pix {
+			frame.Pix[j] = uint8(i%2 + 1)
+		}
+		g.Image = append(g.Image, frame)
+		g.Delay = append(g.Delay, 10*(i+1))
+		g.Disposal = append(g.Disposal, gif.DisposalNone)
+	}
+
+	var buf bytes.Buffer
+	c.Assert(gif.EncodeAll(&buf, g), qt.IsNil)
+	return buf.Bytes()
+}
+
+func TestDecodeAnimatedGIF(t *testing.T) {
+	c := qt.New(t)
+
+	img, err := Decode(bytes.NewReader(newTestGIF(c)))
+	c.Assert(err, qt.IsNil)
+	a, ok := img.(*AnimatedImage)
+	c.Assert(ok, qt.IsTrue)
+	c.Assert(a.Frames, qt.HasLen, 3)
+	c.Assert(a.Bounds(), qt.Equals, image.Rect(0, 0, 20, 10))
+	c.Assert(a.Delays, qt.DeepEquals, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond})
+	c.Assert(a.LoopCount, qt.Equals, 3)
+
+	red, blue := color.NRGBA{255, 0, 0, 255}, color.NRGBA{0, 0, 255, 255}
+
+	// The frames are composited on the canvas.
+	c.Assert(a.Frames[1].At(5, 5), qt.Equals, color.Color(blue))
+	c.Assert(a.Frames[1].At(15, 5), qt.Equals, color.Color(red))
+	c.Assert(a.Frames[2].At(5, 5), qt.Equals, color.Color(blue))
+	c.Assert(a.Frames[2].At(15, 5), qt.Equals, color.Color(red))
+
+	var buf bytes.Buffer
+	c.Assert(a.EncodeGIF(&buf), qt.IsNil)
+	g, err := gif.DecodeAll(&buf)
+	c.Assert(err, qt.IsNil)
+	c.Assert(g.Image, qt.HasLen, 3)
+	c.Assert(g.Delay, qt.DeepEquals, []int{10, 20, 30})
+	c.Assert(g.LoopCount, qt.Equals, 2)
+}
+
+func TestDecodeStillGIF(t *testing.T) {
+	c := qt.New(t)
+
+	var buf bytes.Buffer
+	c.Assert(gif.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, 10, 10)), nil), qt.IsNil)
+
+	img, err := Decode(bytes.NewReader(buf.Bytes()))
+	c.Assert(err, qt.IsNil)
+	_, ok := img.(*image.Paletted)
+	c.Assert(ok, qt.IsTrue)
+}
+
+func TestAnimatedImageLimitFrames(t *testing.T) {
+	c := qt.New(t)
+
+	a := &AnimatedImage{LoopCount: 1}
+	for i := 0; i < 5; i++ {
+		a.Frames = append(a.Frames, image.NewNRGBA(image.Rect(0, 0, 1, 1)))
+		a.Delays = append(a.Delays, time.Duration(i+1)*time.Millisecond)
+	}
+
+	c.Assert(a.LimitFrames(0), qt.Equals, a)
+	c.Assert(a.LimitFrames(5), qt.Equals, a)
+
+	b := a.LimitFrames(2)
+	c.Assert(b.Frames, qt.HasLen, 2)
+	c.Assert(b.Frames[0], qt.Equals, a.Frames[0])
+	c.Assert(b.Frames[1], qt.Equals, a.Frames[2])
+	c.Assert(b.Delays, qt.DeepEquals, []time.Duration{3 * time.Millisecond, 12 * time.Millisecond})
+	c.Assert(b.LoopCount, qt.Equals, 1)
+}
+
+func TestAnimatedWebP(t *testing.T) {
+	c := qt.New(t)
+
+	// Build an animation with the frames encoded as a still WebP, as we
+	// cannot encode WebP without the extended build.
+	still, err := ioutil.ReadFile(filepath.FromSlash("../testdata/sunset.webp"))
+	c.Assert(err, qt.IsNil)
+	stillImg, err := Decode(bytes.NewReader(still))
+	c.Assert(err, qt.IsNil)
+
+	a := &AnimatedImage{
+		Frames:    []image.Image{stillImg, stillImg},
+		Delays:    []time.Duration{100 * time.Millisecond, 250 * time.Millisecond},
+		LoopCount: 3,
+	}
+
+	var buf bytes.Buffer
+	c.Assert(a.encodeAnimatedWebP(&buf, func(w io.Writer, img image.Image) error {
+		_, err := w.Write(still)
+		return err
+	}), qt.IsNil)
+
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(buf.Bytes()))
+	c.Assert(err, qt.IsNil)
+	c.Assert(format, qt.Equals, "webp")
+	c.Assert(cfg.Width, qt.Equals, stillImg.Bounds().Dx())
+
+	img, err := Decode(bytes.NewReader(buf.Bytes()))
+	c.Assert(err, qt.IsNil)
+	b, ok := img.(*AnimatedImage)
+	c.Assert(ok, qt.IsTrue)
+	c.Assert(b.Frames, qt.HasLen, 2)
+	c.Assert(b.Bounds(), qt.Equals, stillImg.Bounds())
+	c.Assert(b.Delays, qt.DeepEquals, a.Delays)
+	c.Assert(b.LoopCount, qt.Equals, 3)
+	c.Assert(b.Frames[1].At(400, 400), qt.Equals, color.NRGBAModel.Convert(stillImg.At(400, 400)))
+}
@@ -46,7 +46,8 @@ var (
 	// re-generation.
 	imageFormatsVersions = map[Format]int{
 		PNG:  3, // Fix transparency issue with 32 bit images.
-		WEBP: 2, // Fix transparency issue with 32 bit images.
+		WEBP: 3, // Preserve the frames of animated images.
+		GIF:  1, // Preserve the frames of animated images.
 	}
 
 	// Increment to mark all processed images as stale. Only use when absolutely needed.
@@ -158,6 +159,13 @@ func DecodeConfig(m map[string]interface{}) (ImagingConfig, error) {
 		i.Cfg.Exif.ExcludeFields = "GPS|Exif|Exposure[M|P|B]|Contrast|Resolution|Sharp|JPEG|Metering|Sensing|Saturation|ColorSpace|Flash|WhiteBalance"
 	}
 
+	for name := range i.Cfg.Profiles {
+		action, spec, _ := i.Cfg.Profile(name)
+		if _, err := DecodeImageConfig(action, spec, i, JPEG); err != nil {
+			return i, errors.Wrapf(err, "invalid image profile %q", name)
+		}
+	}
+
 	return i, nil
 }
 
@@ -179,9 +187,11 @@ func DecodeImageConfig(action, config string, defaults ImagingConfig, sourceForm
 
 		if part == smartCropIdentifier {
 			c.AnchorStr = smartCropIdentifier
+			c.anchorSetForImage = true
 		} else if pos, ok := anchorPositions[part]; ok {
 			c.Anchor = pos
 			c.AnchorStr = part
+			c.anchorSetForImage = true
 		} else if filter, ok := imageFilters[part]; ok {
 			c.Filter = filter
 			c.FilterStr = part
@@ -311,8 +321,25 @@ type ImageConfig struct {
 	Filter    gift.Resampling
 	FilterStr string
 
-	Anchor    gift.Anchor
-	AnchorStr string
+	Anchor            gift.Anchor
+	AnchorStr         string
+	anchorSetForImage bool // Whether the above is set for this image.
+
+	// The focal point to keep in the cropped image in Fill. If set, this is
+	// used instead of the anchor.
+	FocalPoint *FocalPoint
+
+	// The maximum number of frames to keep in animated images. 0 means all.
+	MaxFrames int
+}
+
+// SetFocalPoint sets the focal point to use in Fill, unless an anchor is set
+// for this image, e.g. "200x100 smart".
+func (i *ImageConfig) SetFocalPoint(fp FocalPoint) {
+	if i.anchorSetForImage {
+		return
+	}
+	i.FocalPoint = &fp
 }
 
 func (i ImageConfig) GetKey(format Format) string {
@@ -338,9 +365,14 @@ func (i ImageConfig) GetKey(format Format) string {
 	if i.TargetFormat == WEBP {
 		k += "_h" + strconv.Itoa(int(i.Hint))
 	}
+	if i.MaxFrames > 0 && format.SupportsAnimation() && i.TargetFormat.SupportsAnimation() {
+		k += "_mf" + strconv.Itoa(i.MaxFrames)
+	}
 
 	anchor := i.AnchorStr
-	if anchor == smartCropIdentifier {
+	if i.FocalPoint != nil {
+		anchor = i.FocalPoint.key()
+	} else if anchor == smartCropIdentifier {
 		anchor = anchor + strconv.Itoa(smartCropVersionNumber)
 	}
 
@@ -395,6 +427,22 @@ type Imaging struct {
 	// Default color used in fill operations (e.g. "fff" for white).
 	BgColor string
 
+	// The maximum number of frames to keep when processing animated GIF and
+	// WebP images, to limit their size. Default is 0, meaning all frames.
+	MaxFrames int
+
+	// The metadata to remove from the original images when published, one
+	// of "none" (default), "gps", "private" or "all", see StripMetadata.
+	// This can be set per image in the stripMetadata resource param.
+	StripMetadata string
+
+	// Named image processing profiles on the form "action config", e.g.
+	// {"hero": "fill 1600x900 webp q80"}.
+	Profiles map[string]string
+
+	// Rules applying the profiles above to page resources.
+	ProfileRules []ProfileRule
+
 	Exif ExifConfig
 }
 
@@ -403,12 +451,22 @@ func (cfg *Imaging) init() error {
 		return errors.New("image quality must be a number between 1 and 100")
 	}
 
+	if cfg.MaxFrames < 0 {
+		return errors.New("image max frames cannot be negative")
+	}
+
 	cfg.BgColor = strings.ToLower(strings.TrimPrefix(cfg.BgColor, "#"))
 	cfg.Anchor = strings.ToLower(cfg.Anchor)
 	cfg.ResampleFilter = strings.ToLower(cfg.ResampleFilter)
 	cfg.Hint = strings.ToLower(cfg.Hint)
 
-	return nil
+	var err error
+	cfg.StripMetadata, err = DecodeStripMetadataMode(cfg.StripMetadata)
+	if err != nil {
+		return err
+	}
+
+	return cfg.initProfiles()
 }
 
 type ExifConfig struct {
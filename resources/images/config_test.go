@@ -142,6 +142,7 @@ func newImageConfig(width, height, quality, rotate int, filter, anchor, bgColor
 			if v, ok := anchorPositions[anchor]; ok {
 				c.Anchor = v
 				c.AnchorStr = anchor
+				c.anchorSetForImage = true
 			}
 		}
 	}
@@ -0,0 +1,136 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package images
+
+import (
+	"image"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gohugoio/hugo/resources/images/exif"
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+)
+
+// FocalPoint is the point of interest in an image, e.g. a face, that Fill
+// keeps in the cropped image. X and Y are relative to the image width and
+// height, from 0 to 1, where 0.5 0.5 is the center.
+type FocalPoint struct {
+	X float64
+	Y float64
+}
+
+// DecodeFocalPoint decodes a focal point given as a slice of two numbers,
+// e.g. [0.3, 0.25], or as a string, e.g. "0.3 0.25" or "0.3,0.25".
+func DecodeFocalPoint(v interface{}) (FocalPoint, error) {
+	var parts []interface{}
+
+	switch vv := v.(type) {
+	case string:
+		for _, s := range strings.Fields(strings.Replace(vv, ",", " ", -1)) {
+			parts = append(parts, s)
+		}
+	default:
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			return FocalPoint{}, errors.Errorf("invalid focal point %v", v)
+		}
+		for i := 0; i < rv.Len(); i++ {
+			parts = append(parts, rv.Index(i).Interface())
+		}
+	}
+
+	if len(parts) != 2 {
+		return FocalPoint{}, errors.Errorf("invalid focal point %v: must have an x and a y value", v)
+	}
+
+	var xy [2]float64
+	for i, p := range parts {
+		f, err := cast.ToFloat64E(p)
+		if err != nil || f < 0 || f > 1 {
+			return FocalPoint{}, errors.Errorf("invalid focal point %v: values must be from 0 to 1", v)
+		}
+		xy[i] = f
+	}
+
+	return FocalPoint{X: xy[0], Y: xy[1]}, nil
+}
+
+// FocalPointFromExif returns the focal point given by the SubjectArea or
+// SubjectLocation Exif tags, if any, of an image with the given size.
+func FocalPointFromExif(tags exif.Tags, width, height int) (FocalPoint, bool) {
+	if width <= 0 || height <= 0 {
+		return FocalPoint{}, false
+	}
+
+	for _, name := range []string{"SubjectArea", "SubjectLocation"} {
+		// The first two values are the x and y of the center of the subject,
+		// in pixels.
+		vals, ok := tags[name].([]interface{})
+		if !ok || len(vals) < 2 {
+			continue
+		}
+		x, err1 := cast.ToFloat64E(vals[0])
+		y, err2 := cast.ToFloat64E(vals[1])
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		return FocalPoint{
+			X: math.Min(math.Max(x/float64(width), 0), 1),
+			Y: math.Min(math.Max(y/float64(height), 0), 1),
+		}, true
+	}
+
+	return FocalPoint{}, false
+}
+
+func (fp FocalPoint) key() string {
+	permille := func(f float64) string {
+		return strconv.Itoa(int(math.Round(f * 1000)))
+	}
+	return "fp" + permille(fp.X) + "_" + permille(fp.Y)
+}
+
+// focalPointCrop returns the largest rectangle within bounds with the aspect
+// ratio of width and height, centered on fp as far as the bounds allow.
+func focalPointCrop(bounds image.Rectangle, width, height int, fp FocalPoint) image.Rectangle {
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	if width <= 0 || height <= 0 || srcW <= 0 || srcH <= 0 {
+		return bounds
+	}
+
+	cropW := srcW
+	cropH := int(math.Round(float64(srcW) * float64(height) / float64(width)))
+	if cropH > srcH {
+		cropH = srcH
+		cropW = int(math.Round(float64(srcH) * float64(width) / float64(height)))
+	}
+
+	clamp := func(v, max int) int {
+		if v < 0 {
+			return 0
+		}
+		if v > max {
+			return max
+		}
+		return v
+	}
+
+	x := clamp(int(math.Round(fp.X*float64(srcW)))-cropW/2, srcW-cropW)
+	y := clamp(int(math.Round(fp.Y*float64(srcH)))-cropH/2, srcH-cropH)
+
+	return image.Rect(x, y, x+cropW, y+cropH).Add(bounds.Min)
+}
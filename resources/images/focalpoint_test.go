@@ -0,0 +1,92 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package images
+
+import (
+	"image"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gohugoio/hugo/resources/images/exif"
+)
+
+func TestDecodeFocalPoint(t *testing.T) {
+	c := qt.New(t)
+
+	for _, v := range []interface{}{
+		[]interface{}{0.3, 0.25},
+		[]float64{0.3, 0.25},
+		"0.3 0.25",
+		"0.3, 0.25",
+	} {
+		fp, err := DecodeFocalPoint(v)
+		c.Assert(err, qt.IsNil)
+		c.Assert(fp, qt.Equals, FocalPoint{X: 0.3, Y: 0.25})
+	}
+
+	for _, v := range []interface{}{
+		"0.3",
+		[]interface{}{0.3, 1.5},
+		"a b",
+		42,
+	} {
+		_, err := DecodeFocalPoint(v)
+		c.Assert(err, qt.Not(qt.IsNil))
+	}
+}
+
+func TestFocalPointFromExif(t *testing.T) {
+	c := qt.New(t)
+
+	fp, found := FocalPointFromExif(exif.Tags{"SubjectArea": []interface{}{200, 50, 20, 10}}, 400, 200)
+	c.Assert(found, qt.Equals, true)
+	c.Assert(fp, qt.Equals, FocalPoint{X: 0.5, Y: 0.25})
+
+	fp, found = FocalPointFromExif(exif.Tags{"SubjectLocation": []interface{}{100, 300}}, 400, 200)
+	c.Assert(found, qt.Equals, true)
+	c.Assert(fp, qt.Equals, FocalPoint{X: 0.25, Y: 1})
+
+	_, found = FocalPointFromExif(exif.Tags{"Orientation": 1}, 400, 200)
+	c.Assert(found, qt.Equals, false)
+}
+
+func TestFocalPointCrop(t *testing.T) {
+	c := qt.New(t)
+
+	bounds := image.Rect(0, 0, 900, 600)
+
+	c.Assert(focalPointCrop(bounds, 300, 300, FocalPoint{X: 0.5, Y: 0.5}), qt.Equals, image.Rect(150, 0, 750, 600))
+	c.Assert(focalPointCrop(bounds, 300, 300, FocalPoint{X: 0.2, Y: 0.5}), qt.Equals, image.Rect(0, 0, 600, 600))
+	c.Assert(focalPointCrop(bounds, 300, 300, FocalPoint{X: 0.75, Y: 0.5}), qt.Equals, image.Rect(300, 0, 900, 600))
+	c.Assert(focalPointCrop(bounds, 900, 300, FocalPoint{X: 0.5, Y: 0.1}), qt.Equals, image.Rect(0, 0, 900, 300))
+	c.Assert(focalPointCrop(bounds, 900, 300, FocalPoint{X: 0.5, Y: 0.5}), qt.Equals, image.Rect(0, 150, 900, 450))
+}
+
+func TestImageConfigSetFocalPoint(t *testing.T) {
+	c := qt.New(t)
+
+	cfg, err := DecodeConfig(nil)
+	c.Assert(err, qt.IsNil)
+
+	conf, err := DecodeImageConfig("fill", "200x100", cfg, JPEG)
+	c.Assert(err, qt.IsNil)
+	conf.SetFocalPoint(FocalPoint{X: 0.3, Y: 0.25})
+	c.Assert(conf.FocalPoint, qt.DeepEquals, &FocalPoint{X: 0.3, Y: 0.25})
+	c.Assert(conf.GetKey(JPEG), qt.Equals, "200x100_fill_q75_box_fp300_250")
+
+	conf, err = DecodeImageConfig("fill", "200x100 smart", cfg, JPEG)
+	c.Assert(err, qt.IsNil)
+	conf.SetFocalPoint(FocalPoint{X: 0.3, Y: 0.25})
+	c.Assert(conf.FocalPoint, qt.IsNil)
+}
@@ -85,6 +85,9 @@ func (i *Image) EncodeTo(conf ImageConfig, img image.Image, w io.Writer) error {
 		return encoder.Encode(w, img)
 
 	case GIF:
+		if a, ok := img.(*AnimatedImage); ok {
+			return a.EncodeGIF(w)
+		}
 		return gif.Encode(w, img, &gif.Options{
 			NumColors: 256,
 		})
@@ -94,14 +97,17 @@ func (i *Image) EncodeTo(conf ImageConfig, img image.Image, w io.Writer) error {
 	case BMP:
 		return bmp.Encode(w, img)
 	case WEBP:
-		return webp.Encode(
-			w,
-			img, webpoptions.EncodingOptions{
-				Quality:        conf.Quality,
-				EncodingPreset: webpoptions.EncodingPreset(conf.Hint),
-				UseSharpYuv:    true,
-			},
-		)
+		opts := webpoptions.EncodingOptions{
+			Quality:        conf.Quality,
+			EncodingPreset: webpoptions.EncodingPreset(conf.Hint),
+			UseSharpYuv:    true,
+		}
+		if a, ok := img.(*AnimatedImage); ok {
+			return a.encodeAnimatedWebP(w, func(w io.Writer, img image.Image) error {
+				return webp.Encode(w, img, opts)
+			})
+		}
+		return webp.Encode(w, img, opts)
 	default:
 		return errors.New("format not supported")
 	}
@@ -208,8 +214,17 @@ func (p *ImageProcessor) ApplyFiltersFromConfig(src image.Image, conf ImageConfi
 	case "resize":
 		filters = append(filters, gift.Resize(conf.Width, conf.Height, conf.Filter))
 	case "fill":
-		if conf.AnchorStr == smartCropIdentifier {
-			bounds, err := p.smartCrop(src, conf.Width, conf.Height, conf.Filter)
+		if conf.FocalPoint != nil {
+			// First crop it, then resize it.
+			filters = append(filters, gift.Crop(focalPointCrop(src.Bounds(), conf.Width, conf.Height, *conf.FocalPoint)))
+			filters = append(filters, gift.Resize(conf.Width, conf.Height, conf.Filter))
+		} else if conf.AnchorStr == smartCropIdentifier {
+			cropSrc := src
+			if a, ok := src.(*AnimatedImage); ok {
+				// Crop all frames to what's interesting in the first.
+				cropSrc = a.Frames[0]
+			}
+			bounds, err := p.smartCrop(cropSrc, conf.Width, conf.Height, conf.Filter)
 			if err != nil {
 				return nil, err
 			}
@@ -235,29 +250,34 @@ func (p *ImageProcessor) ApplyFiltersFromConfig(src image.Image, conf ImageConfi
 	return img, nil
 }
 
+// Filter applies the filters to src, or to each of its frames if it's an
+// AnimatedImage.
 func (p *ImageProcessor) Filter(src image.Image, filters ...gift.Filter) (image.Image, error) {
-	g := gift.New(filters...)
-	bounds := g.Bounds(src.Bounds())
-	var dst draw.Image
-	switch src.(type) {
-	case *image.RGBA:
-		dst = image.NewRGBA(bounds)
-	case *image.NRGBA:
-		dst = image.NewNRGBA(bounds)
-	case *image.Gray:
-		dst = image.NewGray(bounds)
-	default:
-		dst = image.NewNRGBA(bounds)
-	}
-	g.Draw(dst, src)
-	return dst, nil
+	return ApplyToFrames(src, func(src image.Image) (image.Image, error) {
+		g := gift.New(filters...)
+		bounds := g.Bounds(src.Bounds())
+		var dst draw.Image
+		switch src.(type) {
+		case *image.RGBA:
+			dst = image.NewRGBA(bounds)
+		case *image.NRGBA:
+			dst = image.NewNRGBA(bounds)
+		case *image.Gray:
+			dst = image.NewGray(bounds)
+		default:
+			dst = image.NewNRGBA(bounds)
+		}
+		g.Draw(dst, src)
+		return dst, nil
+	})
 }
 
 func GetDefaultImageConfig(action string, defaults ImagingConfig) ImageConfig {
 	return ImageConfig{
-		Action:  action,
-		Hint:    defaults.Hint,
-		Quality: defaults.Cfg.Quality,
+		Action:    action,
+		Hint:      defaults.Hint,
+		Quality:   defaults.Cfg.Quality,
+		MaxFrames: defaults.Cfg.MaxFrames,
 	}
 }
 
@@ -284,6 +304,11 @@ func (f Format) RequiresDefaultQuality() bool {
 	return f == JPEG || f == WEBP
 }
 
+// SupportsAnimation reports whether it supports images with more than one frame.
+func (f Format) SupportsAnimation() bool {
+	return f == GIF || f == WEBP
+}
+
 // SupportsTransparency reports whether it supports transparency in any form.
 func (f Format) SupportsTransparency() bool {
 	return f != JPEG
@@ -0,0 +1,456 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package images
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// The metadata strip modes.
+const (
+	// Keep all metadata.
+	StripMetadataNone = "none"
+
+	// Remove the GPS location from the Exif metadata and remove the XMP
+	// metadata, which may also hold the location.
+	StripMetadataGPS = "gps"
+
+	// As StripMetadataGPS, but also remove the serial numbers, the camera
+	// owner name, the unique image ID and the maker notes.
+	StripMetadataPrivate = "private"
+
+	// Remove all Exif, XMP and IPTC metadata, the comments and the text
+	// chunks. Color profiles are kept.
+	StripMetadataAll = "all"
+)
+
+// DecodeStripMetadataMode validates and normalizes the metadata strip mode
+// in s, where the empty string means StripMetadataNone.
+func DecodeStripMetadataMode(s string) (string, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	switch s {
+	case "":
+		return StripMetadataNone, nil
+	case StripMetadataNone, StripMetadataGPS, StripMetadataPrivate, StripMetadataAll:
+		return s, nil
+	default:
+		return "", errors.Errorf("invalid stripMetadata value %q, must be one of none, gps, private or all", s)
+	}
+}
+
+// StripMetadata copies the image in r, in the given format, to w with the
+// metadata removed as given by mode. Images in formats without support for
+// stripping, e.g. GIF, are copied as is.
+func StripMetadata(w io.Writer, r io.Reader, format Format, mode string) error {
+	if mode == StripMetadataNone || mode == "" {
+		_, err := io.Copy(w, r)
+		return err
+	}
+
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case JPEG:
+		b, err = stripJPEGMetadata(b, mode)
+	case PNG:
+		b, err = stripPNGMetadata(b, mode)
+	case WEBP:
+		b, err = stripWebPMetadata(b, mode)
+	case TIFF:
+		err = scrubTIFF(b, mode)
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to strip image metadata")
+	}
+
+	_, err = w.Write(b)
+	return err
+}
+
+var (
+	exifHeader = []byte("Exif\x00\x00")
+	xmpHeader  = []byte("http://ns.adobe.com/xap/1.0/\x00")
+)
+
+func stripJPEGMetadata(b []byte, mode string) ([]byte, error) {
+	if len(b) < 2 || b[0] != 0xff || b[1] != 0xd8 {
+		return nil, errors.New("invalid JPEG")
+	}
+
+	const (
+		markerAPP1  = 0xe1
+		markerAPP13 = 0xed
+		markerCOM   = 0xfe
+		markerSOS   = 0xda
+	)
+
+	out := bytes.NewBuffer(make([]byte, 0, len(b)))
+	out.Write(b[:2])
+
+	for i := 2; i < len(b); {
+		if b[i] != 0xff || i+1 >= len(b) {
+			return nil, errors.New("invalid JPEG marker")
+		}
+		marker := b[i+1]
+
+		// Markers without a length.
+		if marker == 0xff {
+			// Fill byte.
+			out.WriteByte(b[i])
+			i++
+			continue
+		}
+		if marker == 0x01 || (marker >= 0xd0 && marker <= 0xd8) {
+			out.Write(b[i : i+2])
+			i += 2
+			continue
+		}
+
+		if i+4 > len(b) {
+			return nil, errors.New("invalid JPEG segment")
+		}
+		end := i + 2 + int(binary.BigEndian.Uint16(b[i+2:]))
+		if end > len(b) {
+			return nil, errors.New("invalid JPEG segment length")
+		}
+
+		if marker == markerSOS {
+			// The image data, with no metadata after it.
+			out.Write(b[i:])
+			break
+		}
+
+		segment := b[i:end]
+		data := segment[4:]
+		i = end
+
+		switch marker {
+		case markerAPP1:
+			if mode == StripMetadataAll || bytes.HasPrefix(data, xmpHeader) {
+				continue
+			}
+			if bytes.HasPrefix(data, exifHeader) {
+				segment = append([]byte(nil), segment...)
+				if err := scrubTIFF(segment[4+len(exifHeader):], mode); err != nil {
+					// Remove the Exif metadata we cannot read.
+					continue
+				}
+			}
+		case markerAPP13, markerCOM:
+			if mode == StripMetadataAll {
+				continue
+			}
+		}
+
+		out.Write(segment)
+	}
+
+	return out.Bytes(), nil
+}
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+func stripPNGMetadata(b []byte, mode string) ([]byte, error) {
+	if !bytes.HasPrefix(b, pngSignature) {
+		return nil, errors.New("invalid PNG")
+	}
+
+	out := bytes.NewBuffer(make([]byte, 0, len(b)))
+	out.Write(pngSignature)
+
+	for i := len(pngSignature); i < len(b); {
+		if i+8 > len(b) {
+			return nil, errors.New("invalid PNG chunk")
+		}
+		end := i + 12 + int(binary.BigEndian.Uint32(b[i:]))
+		if end > len(b) || end < i {
+			return nil, errors.New("invalid PNG chunk length")
+		}
+
+		chunk := b[i:end]
+		typ, data := string(chunk[4:8]), chunk[8:len(chunk)-4]
+		i = end
+
+		switch typ {
+		case "eXIf":
+			if mode == StripMetadataAll {
+				continue
+			}
+			chunk = append([]byte(nil), chunk...)
+			if err := scrubTIFF(chunk[8:len(chunk)-4], mode); err != nil {
+				continue
+			}
+			binary.BigEndian.PutUint32(chunk[len(chunk)-4:], crc32.ChecksumIEEE(chunk[4:len(chunk)-4]))
+		case "iTXt":
+			if mode == StripMetadataAll || bytes.HasPrefix(data, []byte("XML:com.adobe.xmp\x00")) {
+				continue
+			}
+		case "tEXt", "zTXt", "tIME":
+			if mode == StripMetadataAll {
+				continue
+			}
+		}
+
+		out.Write(chunk)
+	}
+
+	return out.Bytes(), nil
+}
+
+func stripWebPMetadata(b []byte, mode string) ([]byte, error) {
+	if len(b) < 12 || string(b[:4]) != "RIFF" || string(b[8:12]) != "WEBP" {
+		return nil, errors.New("invalid WebP")
+	}
+
+	const (
+		flagEXIF = 0x08
+		flagXMP  = 0x04
+	)
+
+	out := bytes.NewBuffer(make([]byte, 0, len(b)))
+	out.Write(b[:12])
+
+	var (
+		vp8xFlags   int
+		exifRemoved = mode == StripMetadataAll
+	)
+
+	for i := 12; i < len(b); {
+		if i+8 > len(b) {
+			return nil, errors.New("invalid WebP chunk")
+		}
+		size := int(binary.LittleEndian.Uint32(b[i+4:]))
+		end := i + 8 + size + size&1
+		if end > len(b) || end < i {
+			return nil, errors.New("invalid WebP chunk length")
+		}
+
+		chunk := b[i:end]
+		i = end
+
+		switch string(chunk[:4]) {
+		case "VP8X":
+			chunk = append([]byte(nil), chunk...)
+			vp8xFlags = out.Len() + 8
+		case "EXIF":
+			if mode == StripMetadataAll {
+				continue
+			}
+			chunk = append([]byte(nil), chunk...)
+			tiff := chunk[8 : 8+size]
+			if bytes.HasPrefix(tiff, exifHeader) {
+				tiff = tiff[len(exifHeader):]
+			}
+			if err := scrubTIFF(tiff, mode); err != nil {
+				exifRemoved = true
+				continue
+			}
+		case "XMP ":
+			continue
+		}
+
+		out.Write(chunk)
+	}
+
+	result := out.Bytes()
+
+	if vp8xFlags > 0 {
+		result[vp8xFlags] &^= flagXMP
+		if exifRemoved {
+			result[vp8xFlags] &^= flagEXIF
+		}
+	}
+
+	binary.LittleEndian.PutUint32(result[4:], uint32(len(result)-8))
+
+	return result, nil
+}
+
+// The TIFF tags scrubbed.
+const (
+	tagExifIFD            = 0x8769
+	tagGPSIFD             = 0x8825
+	tagXMP                = 0x02bc
+	tagIPTC               = 0x83bb
+	tagMakerNote          = 0x927c
+	tagImageUniqueID      = 0xa420
+	tagCameraOwnerName    = 0xa430
+	tagBodySerialNumber   = 0xa431
+	tagLensSerialNumber   = 0xa435
+	tagDNGCameraSerialNum = 0xc62f
+)
+
+var privateTags = map[uint16]bool{
+	tagMakerNote:          true,
+	tagImageUniqueID:      true,
+	tagCameraOwnerName:    true,
+	tagBodySerialNumber:   true,
+	tagLensSerialNumber:   true,
+	tagDNGCameraSerialNum: true,
+}
+
+// scrubTIFF removes the metadata given by mode from the TIFF structure in
+// b, as used in Exif, in place. The values are zeroed and the GPS (and with
+// StripMetadataAll, the Exif) IFD emptied, so no offsets change.
+func scrubTIFF(b []byte, mode string) error {
+	if len(b) < 8 {
+		return errors.New("invalid TIFF header")
+	}
+
+	var order binary.ByteOrder
+	switch string(b[:4]) {
+	case "II*\x00":
+		order = binary.LittleEndian
+	case "MM\x00*":
+		order = binary.BigEndian
+	default:
+		return errors.New("invalid TIFF header")
+	}
+
+	t := &tiffScrubber{b: b, order: order}
+
+	var gpsIFD, exifIFD uint32
+	ifd0 := order.Uint32(b[4:])
+
+	err := t.walkIFD(ifd0, func(tag uint16, entry int) error {
+		switch tag {
+		case tagGPSIFD:
+			gpsIFD = order.Uint32(b[entry+8:])
+		case tagExifIFD:
+			exifIFD = order.Uint32(b[entry+8:])
+		case tagXMP:
+			// The XMP metadata may also hold the location.
+			return t.zeroValue(entry)
+		case tagIPTC:
+			if mode == StripMetadataAll {
+				return t.zeroValue(entry)
+			}
+		default:
+			if privateTags[tag] && mode != StripMetadataGPS {
+				return t.zeroValue(entry)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if gpsIFD != 0 {
+		if err := t.emptyIFD(gpsIFD); err != nil {
+			return err
+		}
+	}
+
+	if exifIFD != 0 {
+		if mode == StripMetadataAll {
+			return t.emptyIFD(exifIFD)
+		}
+		if mode == StripMetadataPrivate {
+			return t.walkIFD(exifIFD, func(tag uint16, entry int) error {
+				if privateTags[tag] {
+					return t.zeroValue(entry)
+				}
+				return nil
+			})
+		}
+	}
+
+	return nil
+}
+
+type tiffScrubber struct {
+	b     []byte
+	order binary.ByteOrder
+}
+
+// walkIFD calls fn with the tag and position of each entry in the IFD at
+// the given offset.
+func (t *tiffScrubber) walkIFD(offset uint32, fn func(tag uint16, entry int) error) error {
+	if uint64(offset)+2 > uint64(len(t.b)) {
+		return errors.New("invalid TIFF IFD offset")
+	}
+	start := int(offset)
+	n := int(t.order.Uint16(t.b[start:]))
+	if start+2+n*12 > len(t.b) {
+		return errors.New("invalid TIFF IFD")
+	}
+	for i := 0; i < n; i++ {
+		entry := start + 2 + i*12
+		if err := fn(t.order.Uint16(t.b[entry:]), entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// emptyIFD zeroes the values of the entries in the IFD at the given offset
+// and the entries themselves.
+func (t *tiffScrubber) emptyIFD(offset uint32) error {
+	if err := t.walkIFD(offset, func(tag uint16, entry int) error {
+		return t.zeroValue(entry)
+	}); err != nil {
+		return err
+	}
+
+	start := int(offset)
+	n := int(t.order.Uint16(t.b[start:]))
+	end := start + 2 + n*12 + 4
+	if end > len(t.b) {
+		end = len(t.b)
+	}
+	zero(t.b[start:end])
+
+	return nil
+}
+
+var tiffTypeSizes = map[uint16]int{
+	1: 1, 2: 1, 3: 2, 4: 4, 5: 8, 6: 1, 7: 1, 8: 2, 9: 4, 10: 8, 11: 4, 12: 8,
+}
+
+// zeroValue zeroes the value of the IFD entry at the given position.
+func (t *tiffScrubber) zeroValue(entry int) error {
+	typ := t.order.Uint16(t.b[entry+2:])
+	count := uint64(t.order.Uint32(t.b[entry+4:]))
+	size := uint64(tiffTypeSizes[typ]) * count
+
+	if size <= 4 {
+		zero(t.b[entry+8 : entry+12])
+		return nil
+	}
+
+	offset := uint64(t.order.Uint32(t.b[entry+8:]))
+	if offset+size > uint64(len(t.b)) {
+		return errors.New("invalid TIFF value offset")
+	}
+	zero(t.b[offset : offset+size])
+
+	return nil
+}
+
+func zero(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
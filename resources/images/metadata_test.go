@@ -0,0 +1,253 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package images
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"io/ioutil"
+	"testing"
+
+	"github.com/gohugoio/hugo/resources/images/exif"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestDecodeStripMetadataMode(t *testing.T) {
+	c := qt.New(t)
+
+	for _, test := range []struct {
+		in     string
+		expect string
+	}{
+		{"", StripMetadataNone},
+		{"none", StripMetadataNone},
+		{"GPS", StripMetadataGPS},
+		{" private ", StripMetadataPrivate},
+		{"all", StripMetadataAll},
+	} {
+		mode, err := DecodeStripMetadataMode(test.in)
+		c.Assert(err, qt.IsNil)
+		c.Assert(mode, qt.Equals, test.expect)
+	}
+
+	_, err := DecodeStripMetadataMode("location")
+	c.Assert(err, qt.ErrorMatches, `invalid stripMetadata value "location".*`)
+}
+
+func TestStripMetadataJPEG(t *testing.T) {
+	c := qt.New(t)
+
+	src, err := ioutil.ReadFile("../testdata/sunset.jpg")
+	c.Assert(err, qt.IsNil)
+
+	d, err := exif.NewDecoder(exif.IncludeFields(".*"))
+	c.Assert(err, qt.IsNil)
+
+	strip := func(mode string) []byte {
+		var buf bytes.Buffer
+		c.Assert(StripMetadata(&buf, bytes.NewReader(src), JPEG, mode), qt.IsNil)
+		conf, _, err := image.DecodeConfig(bytes.NewReader(buf.Bytes()))
+		c.Assert(err, qt.IsNil)
+		c.Assert(conf.Width, qt.Equals, 900)
+		return buf.Bytes()
+	}
+
+	decode := func(b []byte) *exif.Exif {
+		x, err := d.Decode(bytes.NewReader(b))
+		c.Assert(err, qt.IsNil)
+		return x
+	}
+
+	c.Assert(bytes.Equal(strip(StripMetadataNone), src), qt.IsTrue)
+
+	x := decode(src)
+	c.Assert(x.Lat, qt.Not(qt.Equals), float64(0))
+	c.Assert(x.Tags["GPSLatitude"], qt.Not(qt.IsNil))
+
+	for _, mode := range []string{StripMetadataGPS, StripMetadataPrivate} {
+		b := strip(mode)
+		c.Assert(len(b) <= len(src), qt.IsTrue)
+		x := decode(b)
+		c.Assert(x.Lat, qt.Equals, float64(0))
+		c.Assert(x.Long, qt.Equals, float64(0))
+		c.Assert(x.Tags["GPSLatitude"], qt.IsNil)
+		c.Assert(x.Tags["GPSLongitude"], qt.IsNil)
+		c.Assert(x.Tags["LensModel"], qt.Not(qt.IsNil))
+	}
+
+	b := strip(StripMetadataAll)
+	c.Assert(len(b) < len(src), qt.IsTrue)
+	c.Assert(decode(b), qt.IsNil)
+}
+
+func TestStripMetadataTIFF(t *testing.T) {
+	c := qt.New(t)
+
+	for _, mode := range []string{StripMetadataGPS, StripMetadataPrivate, StripMetadataAll} {
+		b := newTestTIFF()
+		c.Assert(scrubTIFF(b, mode), qt.IsNil)
+
+		// The GPS IFD.
+		c.Assert(binary.LittleEndian.Uint16(b[64:]), qt.Equals, uint16(0))
+
+		serial, lens := string(b[100:108]), string(b[108:116])
+		switch mode {
+		case StripMetadataGPS:
+			c.Assert(serial, qt.Equals, "SN12345\x00")
+			c.Assert(lens, qt.Equals, "LENS123\x00")
+		case StripMetadataPrivate:
+			c.Assert(serial, qt.Equals, "\x00\x00\x00\x00\x00\x00\x00\x00")
+			c.Assert(lens, qt.Equals, "LENS123\x00")
+		case StripMetadataAll:
+			c.Assert(serial, qt.Equals, "\x00\x00\x00\x00\x00\x00\x00\x00")
+			c.Assert(lens, qt.Equals, "\x00\x00\x00\x00\x00\x00\x00\x00")
+		}
+	}
+
+	c.Assert(scrubTIFF([]byte("II*\x00\xff\x00\x00\x00"), StripMetadataGPS), qt.Not(qt.IsNil))
+	c.Assert(scrubTIFF([]byte("GIF89a"), StripMetadataGPS), qt.Not(qt.IsNil))
+}
+
+func TestStripMetadataPNG(t *testing.T) {
+	c := qt.New(t)
+
+	var buf bytes.Buffer
+	c.Assert(png.Encode(&buf, image.NewGray(image.Rect(0, 0, 10, 10))), qt.IsNil)
+	encoded := buf.Bytes()
+
+	// Insert the metadata chunks after IHDR.
+	ihdrEnd := len(pngSignature) + 12 + 13
+	var src []byte
+	src = append(src, encoded[:ihdrEnd]...)
+	src = append(src, newTestPNGChunk("eXIf", newTestTIFF())...)
+	src = append(src, newTestPNGChunk("tEXt", []byte("Author\x00Hugo"))...)
+	src = append(src, newTestPNGChunk("iTXt", []byte("XML:com.adobe.xmp\x00\x00\x00\x00\x00<x:xmpmeta/>"))...)
+	src = append(src, encoded[ihdrEnd:]...)
+
+	strip := func(mode string) string {
+		var buf bytes.Buffer
+		c.Assert(StripMetadata(&buf, bytes.NewReader(src), PNG, mode), qt.IsNil)
+		_, err := png.Decode(bytes.NewReader(buf.Bytes()))
+		c.Assert(err, qt.IsNil)
+		return buf.String()
+	}
+
+	s := strip(StripMetadataGPS)
+	c.Assert(s, qt.Contains, "eXIf")
+	c.Assert(s, qt.Contains, "SN12345")
+	c.Assert(s, qt.Contains, "Author")
+	c.Assert(s, qt.Not(qt.Contains), "xmpmeta")
+
+	s = strip(StripMetadataAll)
+	c.Assert(s, qt.Not(qt.Contains), "eXIf")
+	c.Assert(s, qt.Not(qt.Contains), "Author")
+	c.Assert(s, qt.Not(qt.Contains), "xmpmeta")
+}
+
+func TestStripMetadataWebP(t *testing.T) {
+	c := qt.New(t)
+
+	const flags = 0x08 | 0x04
+
+	vp8x := make([]byte, 10)
+	vp8x[0] = flags
+	var chunks []byte
+	chunks = append(chunks, newTestWebPChunk("VP8X", vp8x)...)
+	chunks = append(chunks, newTestWebPChunk("VP8L", []byte{0x2f, 0, 0, 0, 0})...)
+	chunks = append(chunks, newTestWebPChunk("EXIF", newTestTIFF())...)
+	chunks = append(chunks, newTestWebPChunk("XMP ", []byte("<x:xmpmeta/>"))...)
+
+	src := []byte("RIFF\x00\x00\x00\x00WEBP")
+	src = append(src, chunks...)
+	binary.LittleEndian.PutUint32(src[4:], uint32(len(src)-8))
+
+	strip := func(mode string) []byte {
+		var buf bytes.Buffer
+		c.Assert(StripMetadata(&buf, bytes.NewReader(src), WEBP, mode), qt.IsNil)
+		b := buf.Bytes()
+		c.Assert(int(binary.LittleEndian.Uint32(b[4:])), qt.Equals, len(b)-8)
+		return b
+	}
+
+	b := strip(StripMetadataGPS)
+	c.Assert(string(b), qt.Contains, "EXIF")
+	c.Assert(string(b), qt.Not(qt.Contains), "xmpmeta")
+	c.Assert(b[20], qt.Equals, byte(0x08))
+
+	b = strip(StripMetadataAll)
+	c.Assert(string(b), qt.Not(qt.Contains), "EXIF")
+	c.Assert(string(b), qt.Not(qt.Contains), "xmpmeta")
+	c.Assert(b[20], qt.Equals, byte(0))
+}
+
+// newTestTIFF creates a little endian TIFF structure with a GPS IFD at
+// offset 64, a body serial number at 100 and a lens model at 108.
+func newTestTIFF() []byte {
+	b := make([]byte, 116)
+	o := binary.LittleEndian
+
+	entry := func(pos int, tag, typ uint16, count, value uint32) {
+		o.PutUint16(b[pos:], tag)
+		o.PutUint16(b[pos+2:], typ)
+		o.PutUint32(b[pos+4:], count)
+		o.PutUint32(b[pos+8:], value)
+	}
+
+	copy(b, "II*\x00")
+	o.PutUint32(b[4:], 8)
+
+	// IFD0.
+	o.PutUint16(b[8:], 2)
+	entry(10, tagExifIFD, 4, 1, 36)
+	entry(22, tagGPSIFD, 4, 1, 64)
+
+	// Exif IFD.
+	o.PutUint16(b[36:], 2)
+	entry(38, tagBodySerialNumber, 2, 8, 100)
+	entry(50, 0xa434, 2, 8, 108)
+
+	// GPS IFD.
+	o.PutUint16(b[64:], 1)
+	entry(66, 0x0001, 2, 2, uint32('N'))
+
+	copy(b[100:], "SN12345\x00")
+	copy(b[108:], "LENS123\x00")
+
+	return b
+}
+
+func newTestPNGChunk(typ string, data []byte) []byte {
+	b := make([]byte, 8, 12+len(data))
+	binary.BigEndian.PutUint32(b, uint32(len(data)))
+	copy(b[4:], typ)
+	b = append(b, data...)
+	crc := make([]byte, 4)
+	binary.BigEndian.PutUint32(crc, crc32.ChecksumIEEE(b[4:]))
+	return append(b, crc...)
+}
+
+func newTestWebPChunk(id string, data []byte) []byte {
+	b := make([]byte, 8, 8+len(data)+1)
+	copy(b, id)
+	binary.LittleEndian.PutUint32(b[4:], uint32(len(data)))
+	b = append(b, data...)
+	if len(data)%2 == 1 {
+		b = append(b, 0)
+	}
+	return b
+}
@@ -0,0 +1,88 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package images
+
+import (
+	"encoding/base64"
+	"image"
+	"image/color"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+// newPlaceholderTestImage creates a test image. The expected hashes in the
+// tests below are from the reference implementations.
+func newPlaceholderTestImage(w, h int, alpha bool) image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			a := uint8(255)
+			if alpha {
+				a = uint8((x*30 + y*40) % 256)
+			}
+			img.SetNRGBA(x, y, color.NRGBA{R: uint8((x*37 + y*11) % 256), G: uint8((x*5 + y*53) % 256), B: uint8((x*x + y*7) % 256), A: a})
+		}
+	}
+	return img
+}
+
+func TestEncodeBlurhash(t *testing.T) {
+	c := qt.New(t)
+
+	hash, err := EncodeBlurhash(newPlaceholderTestImage(8, 6, false), 4, 3)
+	c.Assert(err, qt.IsNil)
+	c.Assert(hash, qt.Equals, "LmGR~|K066=vvkNca|wKQGjIoKV^")
+
+	hash, err = EncodeBlurhash(newPlaceholderTestImage(5, 9, true), 4, 3)
+	c.Assert(err, qt.IsNil)
+	c.Assert(hash, qt.Equals, "LOFiFP3kAE^zACjZsUR+QZnRoKV^")
+
+	_, err = EncodeBlurhash(newPlaceholderTestImage(5, 9, false), 10, 3)
+	c.Assert(err, qt.Not(qt.IsNil))
+}
+
+func TestThumbHash(t *testing.T) {
+	c := qt.New(t)
+
+	for _, test := range []struct {
+		w, h       int
+		alpha      bool
+		hash       string
+		decodedW   int
+		decodedH   int
+		firstPixel color.NRGBA
+		lastPixel  color.NRGBA
+	}{
+		{8, 6, false, "VQoKLZAhWnhhiJaAd7h4dAdmUMD2", 32, 23, color.NRGBA{0, 0, 0, 255}, color.NRGBA{18, 52, 55, 255}},
+		{5, 9, true, "2PqFEwYXcKKIGclglwZr55NoWHYgCK0=", 19, 32, color.NRGBA{98, 100, 32, 28}, color.NRGBA{225, 155, 79, 190}},
+	} {
+		hash, err := EncodeThumbHash(newPlaceholderTestImage(test.w, test.h, test.alpha))
+		c.Assert(err, qt.IsNil)
+		c.Assert(base64.StdEncoding.EncodeToString(hash), qt.Equals, test.hash)
+
+		img, err := DecodeThumbHash(hash)
+		c.Assert(err, qt.IsNil)
+		c.Assert(img.Bounds().Dx(), qt.Equals, test.decodedW)
+		c.Assert(img.Bounds().Dy(), qt.Equals, test.decodedH)
+		c.Assert(img.NRGBAAt(0, 0), qt.Equals, test.firstPixel)
+		c.Assert(img.NRGBAAt(test.decodedW-1, test.decodedH-1), qt.Equals, test.lastPixel)
+	}
+
+	_, err := EncodeThumbHash(newPlaceholderTestImage(101, 10, false))
+	c.Assert(err, qt.Not(qt.IsNil))
+
+	_, err = DecodeThumbHash([]byte{1, 2, 3})
+	c.Assert(err, qt.Not(qt.IsNil))
+}
@@ -0,0 +1,124 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package images
+
+import (
+	"strings"
+
+	"github.com/gohugoio/hugo/hugofs/glob"
+	"github.com/pkg/errors"
+)
+
+// The image actions that can be used in a profile.
+var profileActions = map[string]bool{
+	"resize": true,
+	"fit":    true,
+	"fill":   true,
+}
+
+// ProfileRule assigns image processing profiles to the page resources
+// matching Pattern in the pages in Section.
+type ProfileRule struct {
+	// The first level section of the page, e.g. "blog".
+	// Matches all sections if not set.
+	Section string
+
+	// Glob pattern matching the resource name, e.g. "**.jpg".
+	// Matches all images if not set.
+	Pattern string
+
+	// The names of the profiles to apply.
+	Profiles []string
+}
+
+// Profile returns the action, e.g. "fill", and the image config, e.g.
+// "1600x900 webp q80", of the named profile.
+func (cfg Imaging) Profile(name string) (action, spec string, found bool) {
+	s, found := cfg.Profiles[strings.ToLower(name)]
+	if !found {
+		return "", "", false
+	}
+	action, spec = parseProfile(s)
+	return action, spec, true
+}
+
+// ProfilesFor returns the names of the profiles assigned by the profile
+// rules to the page resource with the given name in the given section.
+func (cfg Imaging) ProfilesFor(section, name string) []string {
+	var profiles []string
+	seen := make(map[string]bool)
+
+	for _, rule := range cfg.ProfileRules {
+		if rule.Section != "" && !strings.EqualFold(rule.Section, section) {
+			continue
+		}
+		if rule.Pattern != "" {
+			g, err := glob.GetGlob(rule.Pattern)
+			if err != nil || !g.Match(name) {
+				continue
+			}
+		}
+		for _, profile := range rule.Profiles {
+			profile = strings.ToLower(profile)
+			if !seen[profile] {
+				seen[profile] = true
+				profiles = append(profiles, profile)
+			}
+		}
+	}
+
+	return profiles
+}
+
+func (cfg *Imaging) initProfiles() error {
+	if len(cfg.Profiles) > 0 {
+		profiles := make(map[string]string)
+		for name, s := range cfg.Profiles {
+			action, spec := parseProfile(s)
+			if !profileActions[action] {
+				return errors.Errorf("invalid action %q in image profile %q, must be one of resize, fit or fill", action, name)
+			}
+			if spec == "" {
+				return errors.Errorf("image profile %q has no image config", name)
+			}
+			profiles[strings.ToLower(name)] = s
+		}
+		cfg.Profiles = profiles
+	}
+
+	for _, rule := range cfg.ProfileRules {
+		if rule.Pattern != "" {
+			if _, err := glob.GetGlob(rule.Pattern); err != nil {
+				return errors.Wrapf(err, "invalid pattern %q in image profile rule", rule.Pattern)
+			}
+		}
+		for _, profile := range rule.Profiles {
+			if _, found := cfg.Profiles[strings.ToLower(profile)]; !found {
+				return errors.Errorf("image profile %q used in profile rule not found", profile)
+			}
+		}
+	}
+
+	return nil
+}
+
+// parseProfile parses a profile on the form "fill 1600x900 webp q80".
+func parseProfile(s string) (action, spec string) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexAny(s, " \t")
+	if i == -1 {
+		return strings.ToLower(s), ""
+	}
+	return strings.ToLower(s[:i]), strings.TrimSpace(s[i+1:])
+}
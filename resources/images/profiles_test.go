@@ -0,0 +1,66 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package images
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestDecodeConfigProfiles(t *testing.T) {
+	c := qt.New(t)
+
+	imagingConfig, err := DecodeConfig(map[string]interface{}{
+		"profiles": map[string]interface{}{
+			"Hero":  "fill 1600x900 webp q80",
+			"thumb": "  Fit   300x300 ",
+		},
+		"profileRules": []interface{}{
+			map[string]interface{}{"section": "blog", "pattern": "**.jpg", "profiles": []interface{}{"hero", "thumb"}},
+			map[string]interface{}{"pattern": "cover.*", "profiles": []interface{}{"Hero"}},
+		},
+	})
+	c.Assert(err, qt.IsNil)
+	imaging := imagingConfig.Cfg
+
+	action, spec, found := imaging.Profile("hero")
+	c.Assert(found, qt.IsTrue)
+	c.Assert(action, qt.Equals, "fill")
+	c.Assert(spec, qt.Equals, "1600x900 webp q80")
+
+	action, spec, found = imaging.Profile("THUMB")
+	c.Assert(found, qt.IsTrue)
+	c.Assert(action, qt.Equals, "fit")
+	c.Assert(spec, qt.Equals, "300x300")
+
+	_, _, found = imaging.Profile("foo")
+	c.Assert(found, qt.IsFalse)
+
+	c.Assert(imaging.ProfilesFor("blog", "images/a.jpg"), qt.DeepEquals, []string{"hero", "thumb"})
+	c.Assert(imaging.ProfilesFor("Blog", "a.png"), qt.IsNil)
+	c.Assert(imaging.ProfilesFor("blog", "cover.png"), qt.DeepEquals, []string{"hero"})
+	c.Assert(imaging.ProfilesFor("docs", "cover.jpg"), qt.DeepEquals, []string{"hero"})
+	c.Assert(imaging.ProfilesFor("docs", "a.jpg"), qt.IsNil)
+
+	for _, m := range []map[string]interface{}{
+		{"profiles": map[string]interface{}{"hero": "crop 100x100"}},
+		{"profiles": map[string]interface{}{"hero": "fill"}},
+		{"profiles": map[string]interface{}{"hero": "fill webp"}},
+		{"profileRules": []interface{}{map[string]interface{}{"profiles": []interface{}{"hero"}}}},
+	} {
+		_, err := DecodeConfig(m)
+		c.Assert(err, qt.Not(qt.IsNil))
+	}
+}
@@ -0,0 +1,364 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package images
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+// The ThumbHash implementation below follows the reference implementation
+// in https://github.com/evanw/thumbhash.
+
+// jsRound rounds like JavaScript's Math.round, which the reference
+// implementation uses.
+func jsRound(v float64) float64 {
+	return math.Floor(v + 0.5)
+}
+
+// EncodeThumbHash encodes img, which must be at most 100x100 pixels, as a
+// ThumbHash.
+func EncodeThumbHash(img image.Image) ([]byte, error) {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w == 0 || h == 0 {
+		return nil, errors.New("thumbhash: empty image")
+	}
+	if w > 100 || h > 100 {
+		return nil, errors.Errorf("thumbhash: image must be at most 100x100 pixels, got %dx%d", w, h)
+	}
+
+	n := w * h
+	rgba := make([]color.NRGBA, n)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			rgba[y*w+x] = color.NRGBAModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.NRGBA)
+		}
+	}
+
+	// Determine the average color.
+	var avgR, avgG, avgB, avgA float64
+	for _, c := range rgba {
+		alpha := float64(c.A) / 255
+		avgR += alpha / 255 * float64(c.R)
+		avgG += alpha / 255 * float64(c.G)
+		avgB += alpha / 255 * float64(c.B)
+		avgA += alpha
+	}
+	if avgA > 0 {
+		avgR /= avgA
+		avgG /= avgA
+		avgB /= avgA
+	}
+
+	hasAlpha := avgA < float64(n)
+	lLimit := 7.0
+	if hasAlpha {
+		// Use fewer luminance bits if there's alpha.
+		lLimit = 5
+	}
+	maxWH := float64(w)
+	if h > w {
+		maxWH = float64(h)
+	}
+	lx := int(math.Max(1, jsRound(lLimit*float64(w)/maxWH)))
+	ly := int(math.Max(1, jsRound(lLimit*float64(h)/maxWH)))
+
+	// Convert the image from RGBA to LPQA (composite atop the average color).
+	l := make([]float64, n) // luminance
+	p := make([]float64, n) // yellow - blue
+	q := make([]float64, n) // red - green
+	a := make([]float64, n) // alpha
+	for i, c := range rgba {
+		alpha := float64(c.A) / 255
+		r := avgR*(1-alpha) + alpha/255*float64(c.R)
+		g := avgG*(1-alpha) + alpha/255*float64(c.G)
+		b := avgB*(1-alpha) + alpha/255*float64(c.B)
+		l[i] = (r + g + b) / 3
+		p[i] = (r+g)/2 - b
+		q[i] = r - g
+		a[i] = alpha
+	}
+
+	// Encode using the DCT into DC (constant) and normalized AC (varying) terms.
+	encodeChannel := func(channel []float64, nx, ny int) (dc float64, ac []float64, scale float64) {
+		fx := make([]float64, w)
+		for cy := 0; cy < ny; cy++ {
+			for cx := 0; cx*ny < nx*(ny-cy); cx++ {
+				var f float64
+				for x := 0; x < w; x++ {
+					fx[x] = math.Cos(math.Pi / float64(w) * float64(cx) * (float64(x) + 0.5))
+				}
+				for y := 0; y < h; y++ {
+					fy := math.Cos(math.Pi / float64(h) * float64(cy) * (float64(y) + 0.5))
+					for x := 0; x < w; x++ {
+						f += channel[x+y*w] * fx[x] * fy
+					}
+				}
+				f /= float64(n)
+				if cx > 0 || cy > 0 {
+					ac = append(ac, f)
+					scale = math.Max(scale, math.Abs(f))
+				} else {
+					dc = f
+				}
+			}
+		}
+		if scale > 0 {
+			for i := range ac {
+				ac[i] = 0.5 + 0.5/scale*ac[i]
+			}
+		}
+		return
+	}
+
+	maxInt := func(a, b int) int {
+		if a > b {
+			return a
+		}
+		return b
+	}
+
+	lDC, lAC, lScale := encodeChannel(l, maxInt(3, lx), maxInt(3, ly))
+	pDC, pAC, pScale := encodeChannel(p, 3, 3)
+	qDC, qAC, qScale := encodeChannel(q, 3, 3)
+	var aDC, aScale float64
+	var aAC []float64
+	if hasAlpha {
+		aDC, aAC, aScale = encodeChannel(a, 5, 5)
+	}
+
+	boolInt := func(b bool) int {
+		if b {
+			return 1
+		}
+		return 0
+	}
+
+	// Write the constants.
+	isLandscape := w > h
+	header24 := int(jsRound(63*lDC)) |
+		int(jsRound(31.5+31.5*pDC))<<6 |
+		int(jsRound(31.5+31.5*qDC))<<12 |
+		int(jsRound(31*lScale))<<18 |
+		boolInt(hasAlpha)<<23
+	header16 := ly
+	if !isLandscape {
+		header16 = lx
+	}
+	header16 |= int(jsRound(63*pScale))<<3 |
+		int(jsRound(63*qScale))<<9 |
+		boolInt(isLandscape)<<15
+
+	hash := []byte{byte(header24), byte(header24 >> 8), byte(header24 >> 16), byte(header16), byte(header16 >> 8)}
+	if hasAlpha {
+		hash = append(hash, byte(int(jsRound(15*aDC))|int(jsRound(15*aScale))<<4))
+	}
+
+	// Write the varying factors.
+	acs := [][]float64{lAC, pAC, qAC}
+	if hasAlpha {
+		acs = append(acs, aAC)
+	}
+	acStart := len(hash)
+	acIndex := 0
+	for _, ac := range acs {
+		for _, f := range ac {
+			i := acStart + acIndex>>1
+			for len(hash) <= i {
+				hash = append(hash, 0)
+			}
+			hash[i] |= byte(int(jsRound(15*f)) << ((acIndex & 1) << 2))
+			acIndex++
+		}
+	}
+
+	return hash, nil
+}
+
+// DecodeThumbHash decodes a ThumbHash into an image of at most 32x32 pixels.
+func DecodeThumbHash(hash []byte) (*image.NRGBA, error) {
+	if len(hash) < 5 {
+		return nil, errors.New("thumbhash: hash too short")
+	}
+
+	// Read the constants.
+	header24 := int(hash[0]) | int(hash[1])<<8 | int(hash[2])<<16
+	header16 := int(hash[3]) | int(hash[4])<<8
+	lDC := float64(header24&63) / 63
+	pDC := float64((header24>>6)&63)/31.5 - 1
+	qDC := float64((header24>>12)&63)/31.5 - 1
+	lScale := float64((header24>>18)&31) / 31
+	hasAlpha := header24>>23 != 0
+	pScale := float64((header16>>3)&63) / 63
+	qScale := float64((header16>>9)&63) / 63
+	isLandscape := header16>>15 != 0
+
+	lMax := 7
+	if hasAlpha {
+		lMax = 5
+	}
+	lx, ly := header16&7, lMax
+	if isLandscape {
+		lx, ly = lMax, header16&7
+	}
+	ratio := float64(lx) / float64(ly)
+	if lx < 3 {
+		lx = 3
+	}
+	if ly < 3 {
+		ly = 3
+	}
+
+	acStart := 5
+	aDC, aScale := 1.0, 0.0
+	if hasAlpha {
+		if len(hash) < 6 {
+			return nil, errors.New("thumbhash: hash too short")
+		}
+		acStart = 6
+		aDC = float64(hash[5]&15) / 15
+		aScale = float64(hash[5]>>4) / 15
+	}
+
+	// Read the varying factors (boost saturation by 1.25x to compensate for
+	// quantization).
+	acIndex := 0
+	var err error
+	decodeChannel := func(nx, ny int, scale float64) []float64 {
+		var ac []float64
+		for cy := 0; cy < ny; cy++ {
+			cx := 1
+			if cy > 0 {
+				cx = 0
+			}
+			for ; cx*ny < nx*(ny-cy); cx++ {
+				i := acStart + acIndex>>1
+				if i >= len(hash) {
+					err = errors.New("thumbhash: hash too short")
+					return nil
+				}
+				v := int(hash[i]>>((acIndex&1)<<2)) & 15
+				ac = append(ac, (float64(v)/7.5-1)*scale)
+				acIndex++
+			}
+		}
+		return ac
+	}
+	lAC := decodeChannel(lx, ly, lScale)
+	pAC := decodeChannel(3, 3, pScale*1.25)
+	qAC := decodeChannel(3, 3, qScale*1.25)
+	var aAC []float64
+	if hasAlpha {
+		aAC = decodeChannel(5, 5, aScale)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode using the DCT into RGB.
+	w, h := int(jsRound(32*ratio)), 32
+	if ratio > 1 {
+		w, h = 32, int(jsRound(32/ratio))
+	}
+
+	nx, ny := 3, 3
+	if hasAlpha {
+		nx, ny = 5, 5
+	}
+	if lx > nx {
+		nx = lx
+	}
+	if ly > ny {
+		ny = ly
+	}
+	fx := make([]float64, nx)
+	fy := make([]float64, ny)
+
+	clamp := func(v float64) uint8 {
+		return uint8(math.Max(0, 255*math.Min(1, v)))
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			l, p, q, a := lDC, pDC, qDC, aDC
+
+			// Precompute the coefficients.
+			for cx := range fx {
+				fx[cx] = math.Cos(math.Pi / float64(w) * (float64(x) + 0.5) * float64(cx))
+			}
+			for cy := range fy {
+				fy[cy] = math.Cos(math.Pi / float64(h) * (float64(y) + 0.5) * float64(cy))
+			}
+
+			// Decode L.
+			j := 0
+			for cy := 0; cy < ly; cy++ {
+				cx := 1
+				if cy > 0 {
+					cx = 0
+				}
+				fy2 := fy[cy] * 2
+				for ; cx*ly < lx*(ly-cy); cx++ {
+					l += lAC[j] * fx[cx] * fy2
+					j++
+				}
+			}
+
+			// Decode P and Q.
+			j = 0
+			for cy := 0; cy < 3; cy++ {
+				cx := 1
+				if cy > 0 {
+					cx = 0
+				}
+				fy2 := fy[cy] * 2
+				for ; cx < 3-cy; cx++ {
+					f := fx[cx] * fy2
+					p += pAC[j] * f
+					q += qAC[j] * f
+					j++
+				}
+			}
+
+			// Decode A.
+			if hasAlpha {
+				j = 0
+				for cy := 0; cy < 5; cy++ {
+					cx := 1
+					if cy > 0 {
+						cx = 0
+					}
+					fy2 := fy[cy] * 2
+					for ; cx < 5-cy; cx++ {
+						a += aAC[j] * fx[cx] * fy2
+						j++
+					}
+				}
+			}
+
+			// Convert to RGB.
+			b := l - 2.0/3*p
+			r := (3*l - b + q) / 2
+			g := r - q
+			img.SetNRGBA(x, y, color.NRGBA{R: clamp(r), G: clamp(g), B: clamp(b), A: clamp(a)})
+		}
+	}
+
+	return img, nil
+}
@@ -85,6 +85,15 @@ type ContentProvider interface {
 	WordCount() int
 	ReadingTime() int
 	Len() int
+
+	// OutputContentHash returns the MD5 hash of .Content for the current
+	// output format. It changes only when the content changes. Note that
+	// this is not the hash of the published file, see the
+	// build.writeManifest option for that.
+	OutputContentHash() string
+
+	// ContentHash is an alias for OutputContentHash.
+	ContentHash() string
 }
 
 // FileProvider provides the source file.
@@ -116,6 +125,32 @@ type InSectionPositioner interface {
 	PrevInSection() Page
 }
 
+// InPagesPositioner provides navigation in any page collection, e.g. a
+// filtered and sorted list of pages.
+type InPagesPositioner interface {
+	// NextIn returns the page after this page in pages, in the same
+	// direction as .Next, i.e. pages.Next, or nil if not found.
+	NextIn(pages interface{}) (Page, error)
+
+	// PrevIn returns the page before this page in pages, in the same
+	// direction as .Prev, i.e. pages.Prev, or nil if not found.
+	PrevIn(pages interface{}) (Page, error)
+}
+
+// InSeriesPositioner provides navigation in the series given in the series
+// front matter key.
+type InSeriesPositioner interface {
+	// SeriesPages returns the pages in the same series as this page, in
+	// series order, including this page.
+	SeriesPages() Pages
+
+	// NextInSeries returns the page after this page in its series.
+	NextInSeries() Page
+
+	// PrevInSeries returns the page before this page in its series.
+	PrevInSeries() Page
+}
+
 // InternalDependencies is considered an internal interface.
 type InternalDependencies interface {
 	GetRelatedDocsHandler() *RelatedDocsHandler
@@ -151,6 +186,11 @@ type PageMetaProvider interface {
 	// Whether this is a draft. Will only be true if run with the --buildDrafts (-D) flag.
 	Draft() bool
 
+	// ID returns the stable identifier of this page, set in front matter or
+	// generated if pageIDs.generate is enabled. It does not change when
+	// the page is moved.
+	ID() string
+
 	// IsHome returns whether this is the home page.
 	IsHome() bool
 
@@ -176,6 +216,11 @@ type PageMetaProvider interface {
 	// Param looks for a param in Page and then in Site config.
 	Param(key interface{}) (interface{}, error)
 
+	// ParamSource returns where the front matter value for the given key was
+	// set: "frontmatter", "cascade" or "defaults" (the frontMatterDefaults
+	// config), or an empty string if not set.
+	ParamSource(key string) string
+
 	// Path gets the relative path, including file name and extension if relevant,
 	// to the source of this Page. It will be relative to any content root.
 	Path() string
@@ -238,7 +283,11 @@ type PageWithoutContent interface {
 	TreeProvider
 
 	// Horizontal navigation
+	InPagesPositioner
 	InSectionPositioner
+	InSeriesPositioner
+	SequencesProvider
+	BlocksProvider
 	PageRenderProvider
 	PaginatorProvider
 	Positioner
@@ -257,6 +306,7 @@ type PageWithoutContent interface {
 	SitesProvider
 
 	// Helper methods
+	ResourceHintsProvider
 	ShortcodeInfoProvider
 	compare.Eqer
 	maps.Scratcher
@@ -303,12 +353,25 @@ type RelatedKeywordsProvider interface {
 	RelatedKeywords(cfg related.IndexConfig) ([]related.Keyword, error)
 }
 
+// ResourceHintsProvider provides resource hints for the page's head.
+type ResourceHintsProvider interface {
+	// ResourceHints returns preload links for the resources published by
+	// Hugo that are referenced after it in the published HTML, e.g. the
+	// scripts at the end of the body, and a prefetch link for the next page,
+	// if paginated. These are resolved when the page is published.
+	ResourceHints() template.HTML
+}
+
 // ShortcodeInfoProvider provides info about the shortcodes in a Page.
 type ShortcodeInfoProvider interface {
 	// HasShortcode return whether the page has a shortcode with the given name.
 	// This method is mainly motivated with the Hugo Docs site's need for a list
 	// of pages with the `todo` shortcode in it.
 	HasShortcode(name string) bool
+
+	// Shortcodes returns all the shortcode invocations in this page's content,
+	// nested shortcodes included, in the order they appear in the source.
+	Shortcodes() ShortcodeInfos
 }
 
 // SitesProvider provide accessors to get sites.
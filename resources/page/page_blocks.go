@@ -0,0 +1,73 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package page
+
+import (
+	"html/template"
+	"strings"
+
+	"github.com/gohugoio/hugo/common/maps"
+	"github.com/gohugoio/hugo/resources/resource"
+)
+
+// BlocksProvider provides the structured content blocks, e.g. FAQ items or
+// steps, declared in the blocks front matter of a page.
+type BlocksProvider interface {
+	// Blocks returns the content blocks of the page, in front matter order.
+	Blocks() Blocks
+
+	// BlockAssets returns the assets of the block types used in the page,
+	// the files matching blocks/<type>.* in the assets directory, e.g.
+	// assets/blocks/hero.css, in block order.
+	BlockAssets() (resource.Resources, error)
+
+	// RenderBlocks renders the content blocks of the page, optionally only
+	// those of the given types, each with the "blocks/<type>" layout.
+	RenderBlocks(types ...string) (template.HTML, error)
+}
+
+// Block is a structured content block declared in front matter.
+type Block struct {
+	// The lower case block type, e.g. "faq".
+	Type string
+
+	// The block params, all but the type.
+	Params maps.Params
+
+	// The zero based index of the block in the page's blocks.
+	Index int
+
+	// The page the block is declared in.
+	Page Page
+}
+
+// Param returns the block param with the given key, which may be a path,
+// e.g. "image.src".
+func (b Block) Param(key string) interface{} {
+	return b.Params.Get(strings.Split(strings.ToLower(key), ".")...)
+}
+
+// Blocks is a list of content blocks.
+type Blocks []Block
+
+// ByType returns the blocks of the given type.
+func (b Blocks) ByType(typ string) Blocks {
+	var blocks Blocks
+	for _, block := range b {
+		if strings.EqualFold(block.Type, typ) {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks
+}
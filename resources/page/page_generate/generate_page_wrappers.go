@@ -96,8 +96,11 @@ func generateMarshalJSON(c *codegen.Inspector) error {
 
 		reflect.TypeOf((*page.ChildCareProvider)(nil)).Elem(),
 		reflect.TypeOf((*page.TreeProvider)(nil)).Elem(),
+		reflect.TypeOf((*page.InPagesPositioner)(nil)).Elem(),
 		reflect.TypeOf((*page.InSectionPositioner)(nil)).Elem(),
+		reflect.TypeOf((*page.InSeriesPositioner)(nil)).Elem(),
 		reflect.TypeOf((*page.PaginatorProvider)(nil)).Elem(),
+		reflect.TypeOf((*page.ResourceHintsProvider)(nil)).Elem(),
 		reflect.TypeOf((*maps.Scratcher)(nil)).Elem(),
 	}
 
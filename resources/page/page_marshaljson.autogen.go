@@ -43,6 +43,8 @@ func MarshalPageToJSON(p Page) ([]byte, error) {
 	wordCount := p.WordCount()
 	readingTime := p.ReadingTime()
 	length := p.Len()
+	outputContentHash := p.OutputContentHash()
+	contentHash := p.ContentHash()
 	tableOfContents := p.TableOfContents()
 	rawContent := p.RawContent()
 	resourceType := p.ResourceType()
@@ -61,6 +63,7 @@ func MarshalPageToJSON(p Page) ([]byte, error) {
 	bundleType := p.BundleType()
 	description := p.Description()
 	draft := p.Draft()
+	iD := p.ID()
 	isHome := p.IsHome()
 	keywords := p.Keywords()
 	kind := p.Kind()
@@ -88,6 +91,7 @@ func MarshalPageToJSON(p Page) ([]byte, error) {
 	isTranslated := p.IsTranslated()
 	allTranslations := p.AllTranslations()
 	translations := p.Translations()
+	shortcodes := p.Shortcodes()
 	getIdentity := p.GetIdentity()
 
 	s := struct {
@@ -100,6 +104,8 @@ func MarshalPageToJSON(p Page) ([]byte, error) {
 		WordCount                int
 		ReadingTime              int
 		Len                      int
+		OutputContentHash        string
+		ContentHash              string
 		TableOfContents          template.HTML
 		RawContent               string
 		ResourceType             string
@@ -118,6 +124,7 @@ func MarshalPageToJSON(p Page) ([]byte, error) {
 		BundleType               files.ContentClass
 		Description              string
 		Draft                    bool
+		ID                       string
 		IsHome                   bool
 		Keywords                 []string
 		Kind                     string
@@ -145,6 +152,7 @@ func MarshalPageToJSON(p Page) ([]byte, error) {
 		IsTranslated             bool
 		AllTranslations          Pages
 		Translations             Pages
+		Shortcodes               ShortcodeInfos
 		GetIdentity              identity.Identity
 	}{
 		Content:                  content,
@@ -156,6 +164,8 @@ func MarshalPageToJSON(p Page) ([]byte, error) {
 		WordCount:                wordCount,
 		ReadingTime:              readingTime,
 		Len:                      length,
+		OutputContentHash:        outputContentHash,
+		ContentHash:              contentHash,
 		TableOfContents:          tableOfContents,
 		RawContent:               rawContent,
 		ResourceType:             resourceType,
@@ -174,6 +184,7 @@ func MarshalPageToJSON(p Page) ([]byte, error) {
 		BundleType:               bundleType,
 		Description:              description,
 		Draft:                    draft,
+		ID:                       iD,
 		IsHome:                   isHome,
 		Keywords:                 keywords,
 		Kind:                     kind,
@@ -201,6 +212,7 @@ func MarshalPageToJSON(p Page) ([]byte, error) {
 		IsTranslated:             isTranslated,
 		AllTranslations:          allTranslations,
 		Translations:             translations,
+		Shortcodes:               shortcodes,
 		GetIdentity:              getIdentity,
 	}
 
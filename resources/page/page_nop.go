@@ -88,6 +88,14 @@ func (p *nopPage) BaseFileName() string {
 	return ""
 }
 
+func (p *nopPage) BlockAssets() (resource.Resources, error) {
+	return nil, nil
+}
+
+func (p *nopPage) Blocks() Blocks {
+	return nil
+}
+
 func (p *nopPage) BundleType() files.ContentClass {
 	return ""
 }
@@ -198,10 +206,18 @@ func (p *nopPage) HasShortcode(name string) bool {
 	return false
 }
 
+func (p *nopPage) Shortcodes() ShortcodeInfos {
+	return nil
+}
+
 func (p *nopPage) Hugo() (h hugo.Info) {
 	return
 }
 
+func (p *nopPage) ID() string {
+	return ""
+}
+
 func (p *nopPage) InSection(other interface{}) (bool, error) {
 	return false, nil
 }
@@ -318,6 +334,10 @@ func (p *nopPage) Param(key interface{}) (interface{}, error) {
 	return nil, nil
 }
 
+func (p *nopPage) ParamSource(key string) string {
+	return ""
+}
+
 func (p *nopPage) Params() maps.Params {
 	return nil
 }
@@ -330,6 +350,14 @@ func (p *nopPage) Parent() Page {
 	return nil
 }
 
+func (p *nopPage) OutputContentHash() string {
+	return ""
+}
+
+func (p *nopPage) ContentHash() string {
+	return ""
+}
+
 func (p *nopPage) Path() string {
 	return ""
 }
@@ -362,6 +390,30 @@ func (p *nopPage) NextInSection() Page {
 	return nil
 }
 
+func (p *nopPage) PrevIn(pages interface{}) (Page, error) {
+	return nil, nil
+}
+
+func (p *nopPage) NextIn(pages interface{}) (Page, error) {
+	return nil, nil
+}
+
+func (p *nopPage) Sequences() Sequences {
+	return nil
+}
+
+func (p *nopPage) SeriesPages() Pages {
+	return nil
+}
+
+func (p *nopPage) PrevInSeries() Page {
+	return nil
+}
+
+func (p *nopPage) NextInSeries() Page {
+	return nil
+}
+
 func (p *nopPage) PrevPage() Page {
 	return nil
 }
@@ -398,6 +450,14 @@ func (p *nopPage) RenderString(args ...interface{}) (template.HTML, error) {
 	return "", nil
 }
 
+func (p *nopPage) RenderBlocks(types ...string) (template.HTML, error) {
+	return "", nil
+}
+
+func (p *nopPage) ResourceHints() template.HTML {
+	return ""
+}
+
 func (p *nopPage) ResourceType() string {
 	return ""
 }
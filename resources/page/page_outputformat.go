@@ -18,6 +18,7 @@ package page
 import (
 	"strings"
 
+	"github.com/gohugoio/hugo/config"
 	"github.com/gohugoio/hugo/media"
 	"github.com/gohugoio/hugo/output"
 )
@@ -83,3 +84,36 @@ func (o OutputFormats) Get(name string) *OutputFormat {
 	}
 	return nil
 }
+
+// Sitemap returns the output formats to list in the sitemap for a page with
+// the given sitemap config.
+// Output formats with noIndex set are never listed.
+func (o OutputFormats) Sitemap(cfg config.Sitemap) OutputFormats {
+	if cfg.Disable {
+		return nil
+	}
+
+	var formats OutputFormats
+	for _, f := range o {
+		if f.Format.NoIndex {
+			continue
+		}
+		if len(cfg.Formats) > 0 {
+			for _, name := range cfg.Formats {
+				if strings.EqualFold(f.Format.Name, name) {
+					formats = append(formats, f)
+					break
+				}
+			}
+		} else if f.Format.Sitemap {
+			formats = append(formats, f)
+		}
+	}
+
+	if formats == nil && len(cfg.Formats) == 0 && len(o) > 0 && !o[0].Format.NoIndex {
+		// None of the output formats is marked for the sitemap, use the main one.
+		formats = o[:1]
+	}
+
+	return formats
+}
@@ -147,17 +147,23 @@ func CreateTargetPaths(d TargetPathDescriptor) (tp TargetPaths) {
 		isUgly = true
 	}
 
+	typePath := d.Type.Path
+
 	if d.Kind != KindPage && d.URL == "" && len(d.Sections) > 0 {
+		if d.Type.PathBeforeSections && typePath != "" {
+			pagePath = pjoin(pagePath, typePath)
+			typePath = ""
+		}
 		if d.ExpandedPermalink != "" {
 			pagePath = pjoin(pagePath, d.ExpandedPermalink)
 		} else {
-			pagePath = pjoin(d.Sections...)
+			pagePath = pjoin(pagePath, pjoin(d.Sections...))
 		}
 		needsBase = false
 	}
 
-	if d.Type.Path != "" {
-		pagePath = pjoin(pagePath, d.Type.Path)
+	if typePath != "" {
+		pagePath = pjoin(pagePath, typePath)
 	}
 
 	if d.Kind != KindHome && d.URL != "" {
@@ -37,6 +37,10 @@ func TestPageTargetPath(t *testing.T) {
 		BaseName:  "_redirects",
 	}
 
+	// Path before the sections of list pages.
+	apiFormat := output.ContentAPIFormat
+	apiFormat.NoUgly = true
+
 	for _, langPrefixPath := range []string{"", "no"} {
 		for _, langPrefixLink := range []string{"", "no"} {
 			for _, uglyURLs := range []bool{false, true} {
@@ -56,6 +60,12 @@ func TestPageTargetPath(t *testing.T) {
 						BaseName: "_index",
 						Type:     output.HTMLFormat,
 					}, TargetPaths{TargetFilename: "/sect1/index.html", SubResourceBaseTarget: "/sect1", Link: "/sect1/"}},
+					{"ContentAPI section list", TargetPathDescriptor{
+						Kind:     KindSection,
+						Sections: []string{"sect1"},
+						BaseName: "_index",
+						Type:     apiFormat,
+					}, TargetPaths{TargetFilename: "/api/sect1/index.json", SubResourceBaseTarget: "/api/sect1", Link: "/api/sect1/index.json"}},
 					{"HTML taxonomy term", TargetPathDescriptor{
 						Kind:     KindTerm,
 						Sections: []string{"tags", "hugo"},
@@ -0,0 +1,93 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package page
+
+import "strings"
+
+// SequencesProvider provides the named page sequences a page is in, e.g. the
+// steps of a tutorial, defined in the sequences site config.
+type SequencesProvider interface {
+	// Sequences returns the sequences the page is in.
+	Sequences() Sequences
+}
+
+// Sequences holds the positions of a page in the sequences it is in, keyed
+// by the lower case sequence name.
+type Sequences map[string]*SequencePosition
+
+// Get returns the position of the page in the named sequence, or nil if
+// it's not in it.
+func (s Sequences) Get(name string) *SequencePosition {
+	return s[strings.ToLower(name)]
+}
+
+// SequencePosition is the position of a page in a named sequence of pages.
+type SequencePosition struct {
+	// The sequence name.
+	Name string
+
+	// The pages in the sequence, in order.
+	Pages Pages
+
+	// The zero based index of the page in Pages.
+	Index int
+}
+
+// Position returns the one based position of the page in the sequence.
+func (s *SequencePosition) Position() int {
+	return s.Index + 1
+}
+
+// Len returns the number of pages in the sequence.
+func (s *SequencePosition) Len() int {
+	return len(s.Pages)
+}
+
+// Next returns the page after the page in the sequence, or nil if it's the
+// last.
+func (s *SequencePosition) Next() Page {
+	if s.Index >= len(s.Pages)-1 {
+		return nil
+	}
+	return s.Pages[s.Index+1]
+}
+
+// Prev returns the page before the page in the sequence, or nil if it's the
+// first.
+func (s *SequencePosition) Prev() Page {
+	if s.Index <= 0 {
+		return nil
+	}
+	return s.Pages[s.Index-1]
+}
+
+// First returns the first page in the sequence.
+func (s *SequencePosition) First() Page {
+	return s.Pages[0]
+}
+
+// Last returns the last page in the sequence.
+func (s *SequencePosition) Last() Page {
+	return s.Pages[len(s.Pages)-1]
+}
+
+// IsFirst returns whether the page is the first in the sequence.
+func (s *SequencePosition) IsFirst() bool {
+	return s.Index == 0
+}
+
+// IsLast returns whether the page is the last in the sequence.
+func (s *SequencePosition) IsLast() bool {
+	return s.Index == len(s.Pages)-1
+}
@@ -0,0 +1,50 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package page
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestSequencePosition(t *testing.T) {
+	c := qt.New(t)
+
+	p1, p2, p3 := newTestPage(), newTestPage(), newTestPage()
+	pages := Pages{p1, p2, p3}
+
+	seqs := Sequences{
+		"basics": &SequencePosition{Name: "basics", Pages: pages, Index: 0},
+	}
+
+	c.Assert(seqs.Get("missing"), qt.IsNil)
+
+	first := seqs.Get("Basics")
+	c.Assert(first, qt.Not(qt.IsNil))
+	c.Assert(first.Position(), qt.Equals, 1)
+	c.Assert(first.Len(), qt.Equals, 3)
+	c.Assert(first.Prev(), qt.IsNil)
+	c.Assert(first.Next(), qt.Equals, p2)
+	c.Assert(first.IsFirst(), qt.IsTrue)
+	c.Assert(first.IsLast(), qt.IsFalse)
+
+	last := &SequencePosition{Name: "basics", Pages: pages, Index: 2}
+	c.Assert(last.Position(), qt.Equals, 3)
+	c.Assert(last.Prev(), qt.Equals, p2)
+	c.Assert(last.Next(), qt.IsNil)
+	c.Assert(last.First(), qt.Equals, p1)
+	c.Assert(last.Last(), qt.Equals, p3)
+	c.Assert(last.IsLast(), qt.IsTrue)
+}
@@ -0,0 +1,93 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package page
+
+import (
+	"reflect"
+)
+
+// ShortcodeInfo describes a shortcode invocation in a Page's content.
+type ShortcodeInfo struct {
+	// The shortcode name.
+	Name string
+
+	// The shortcode parameters. This is either a map (named parameters)
+	// or a slice (positional parameters).
+	Params interface{}
+
+	// Whether the parameters are named.
+	IsNamedParams bool
+
+	// Whether this is an inline shortcode.
+	IsInline bool
+
+	// Zero-based ordinal in relation to its parent.
+	Ordinal int
+
+	// The nesting level, 0 for shortcodes placed directly in the content.
+	Level int
+
+	// The name of the enclosing shortcode, empty if Level is 0.
+	Parent string
+}
+
+// Get is a convenience method to look up shortcode parameters by its key.
+// It follows the same rules as .Get in the shortcode templates.
+func (s ShortcodeInfo) Get(key interface{}) interface{} {
+	if s.Params == nil {
+		return nil
+	}
+
+	v := reflect.ValueOf(s.Params)
+	if v.Len() == 0 {
+		return nil
+	}
+
+	switch key := key.(type) {
+	case int64, int32, int16, int8, int:
+		if v.Kind() != reflect.Slice {
+			return nil
+		}
+		idx := int(reflect.ValueOf(key).Int())
+		if idx < 0 || idx > v.Len()-1 {
+			return ""
+		}
+		return v.Index(idx).Interface()
+	case string:
+		if v.Kind() != reflect.Map {
+			return nil
+		}
+		x := v.MapIndex(reflect.ValueOf(key))
+		if !x.IsValid() {
+			return ""
+		}
+		return x.Interface()
+	}
+
+	return nil
+}
+
+// ShortcodeInfos is a slice of ShortcodeInfo.
+type ShortcodeInfos []ShortcodeInfo
+
+// ByName returns the shortcode invocations with the given name.
+func (s ShortcodeInfos) ByName(name string) ShortcodeInfos {
+	var matches ShortcodeInfos
+	for _, sc := range s {
+		if sc.Name == name {
+			matches = append(matches, sc)
+		}
+	}
+	return matches
+}
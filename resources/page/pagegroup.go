@@ -166,6 +166,98 @@ func (p Pages) GroupBy(key string, order ...string) (PagesGroup, error) {
 	return r, nil
 }
 
+// GroupByFunc groups by the key returned by fn for each page and with the
+// given order. Pages with a nil key are left out. Keys of different types, or
+// of types other than strings, numbers, booleans and dates, are kept in the
+// order found, or the reverse for descending order.
+// Valid values for order is asc, desc, rev and reverse.
+func (p Pages) GroupByFunc(fn func(p Page) (interface{}, error), order ...string) (PagesGroup, error) {
+	if len(p) < 1 {
+		return nil, nil
+	}
+
+	direction := "asc"
+
+	if len(order) > 0 && (strings.ToLower(order[0]) == "desc" || strings.ToLower(order[0]) == "rev" || strings.ToLower(order[0]) == "reverse") {
+		direction = "desc"
+	}
+
+	var (
+		keys      []reflect.Value
+		groups    = make(map[interface{}]Pages)
+		sameKinds = true
+	)
+
+	for _, e := range p {
+		key, err := fn(e)
+		if err != nil {
+			return nil, err
+		}
+		if key == nil {
+			continue
+		}
+		if !reflect.TypeOf(key).Comparable() {
+			return nil, fmt.Errorf("can't group by key of type %T", key)
+		}
+		if _, found := groups[key]; !found {
+			kv := reflect.ValueOf(key)
+			if len(keys) > 0 && keys[0].Kind() != kv.Kind() {
+				sameKinds = false
+			}
+			keys = append(keys, kv)
+		}
+		groups[key] = append(groups[key], e)
+	}
+
+	if sameKinds {
+		if less := groupKeyLessFunc(keys); less != nil {
+			sort.Slice(keys, func(i, j int) bool { return less(keys[i], keys[j]) })
+		}
+	}
+
+	if direction == "desc" {
+		for i, j := 0, len(keys)-1; i < j; i, j = i+1, j-1 {
+			keys[i], keys[j] = keys[j], keys[i]
+		}
+	}
+
+	r := make(PagesGroup, len(keys))
+	for i, k := range keys {
+		r[i] = PageGroup{Key: k.Interface(), Pages: groups[k.Interface()]}
+	}
+
+	return r, nil
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// groupKeyLessFunc returns the func to sort the group keys of the same kind
+// in ascending order, or nil if they can't be sorted.
+func groupKeyLessFunc(keys []reflect.Value) func(a, b reflect.Value) bool {
+	switch keys[0].Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(a, b reflect.Value) bool { return a.Int() < b.Int() }
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return func(a, b reflect.Value) bool { return a.Uint() < b.Uint() }
+	case reflect.Float32, reflect.Float64:
+		return func(a, b reflect.Value) bool { return a.Float() < b.Float() }
+	case reflect.String:
+		return func(a, b reflect.Value) bool { return a.String() < b.String() }
+	case reflect.Bool:
+		return func(a, b reflect.Value) bool { return !a.Bool() && b.Bool() }
+	case reflect.Struct:
+		for _, k := range keys {
+			if k.Type() != timeType {
+				return nil
+			}
+		}
+		return func(a, b reflect.Value) bool {
+			return a.Interface().(time.Time).Before(b.Interface().(time.Time))
+		}
+	}
+	return nil
+}
+
 // GroupByParam groups by the given page parameter key's value and with the given order.
 // Valid values for order is asc, desc, rev and reverse.
 func (p Pages) GroupByParam(key string, order ...string) (PagesGroup, error) {
@@ -141,6 +141,76 @@ func TestGroupByCalledWithEmptyPages(t *testing.T) {
 	}
 }
 
+func TestGroupByFunc(t *testing.T) {
+	t.Parallel()
+	c := qt.New(t)
+	pages := preparePageGroupTestPages(t)
+
+	groups, err := pages.GroupByFunc(func(p Page) (interface{}, error) {
+		if p.Weight() == 2 {
+			return nil, nil
+		}
+		return p.Params()["custom_param"], nil
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(reflect.DeepEqual(groups, PagesGroup{
+		{Key: "bar", Pages: Pages{pages[1], pages[3]}},
+		{Key: "baz", Pages: Pages{pages[4]}},
+		{Key: "foo", Pages: Pages{pages[0]}},
+	}), qt.Equals, true)
+
+	groups, err = pages.GroupByFunc(func(p Page) (interface{}, error) {
+		return p.Weight(), nil
+	}, "desc")
+	c.Assert(err, qt.IsNil)
+	c.Assert(reflect.DeepEqual(groups, PagesGroup{
+		{Key: 3, Pages: Pages{pages[0], pages[1]}},
+		{Key: 2, Pages: Pages{pages[2]}},
+		{Key: 1, Pages: Pages{pages[3], pages[4]}},
+	}), qt.Equals, true)
+
+	_, err = pages.GroupByFunc(func(p Page) (interface{}, error) {
+		return []string{"a"}, nil
+	})
+	c.Assert(err, qt.Not(qt.IsNil))
+
+	keys := func(groups PagesGroup) []interface{} {
+		var keys []interface{}
+		for _, g := range groups {
+			keys = append(keys, g.Key)
+		}
+		return keys
+	}
+
+	for _, test := range []struct {
+		name string
+		key  func(p Page) interface{}
+		asc  []interface{}
+	}{
+		{"float", func(p Page) interface{} { return float64(p.Weight()) / 2 }, []interface{}{0.5, 1.0, 1.5}},
+		{"bool", func(p Page) interface{} { return p.Weight() > 1 }, []interface{}{false, true}},
+		{"time", func(p Page) interface{} { return p.Date() }, []interface{}{
+			cast.ToTime("2012-01-01"), cast.ToTime("2012-03-02"), cast.ToTime("2012-04-06"),
+		}},
+	} {
+		c.Run(test.name, func(c *qt.C) {
+			fn := func(p Page) (interface{}, error) { return test.key(p), nil }
+
+			groups, err := pages.GroupByFunc(fn)
+			c.Assert(err, qt.IsNil)
+			c.Assert(keys(groups), qt.DeepEquals, test.asc)
+
+			var desc []interface{}
+			for i := len(test.asc) - 1; i >= 0; i-- {
+				desc = append(desc, test.asc[i])
+			}
+			groups, err = pages.GroupByFunc(fn, "desc")
+			c.Assert(err, qt.IsNil)
+			c.Assert(keys(groups), qt.DeepEquals, desc)
+		})
+	}
+}
+
 func TestGroupByParamCalledWithUnavailableKey(t *testing.T) {
 	t.Parallel()
 	pages := preparePageGroupTestPages(t)
@@ -0,0 +1,162 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagemeta
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/gohugoio/hugo/common/maps"
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+)
+
+// BlockSchema describes a type of structured content block declared in
+// front matter, e.g. a FAQ item.
+type BlockSchema struct {
+	// The params every block of this type must set.
+	Required []string
+
+	// The expected types of the block params.
+	Params ParamTypes
+
+	// Whether to cache the rendered blocks of this type. Blocks with the
+	// same params and layout share the output, so only enable this for
+	// layouts that do not depend on the page.
+	Cache bool
+}
+
+// BlockSchemas holds the block schemas keyed by the lower case block type.
+type BlockSchemas map[string]BlockSchema
+
+// DecodeBlockSchemasConfig decodes the blocks config, e.g.:
+//
+//	[blocks.faq]
+//	required = ["question", "answer"]
+//	cache = true
+//	[blocks.faq.params]
+//	question = "string"
+//	answer = "string"
+func DecodeBlockSchemasConfig(m map[string]interface{}) (BlockSchemas, error) {
+	schemas := make(BlockSchemas)
+	for k, v := range m {
+		typ := strings.ToLower(k)
+		mm, err := maps.ToStringMapE(v)
+		if err != nil {
+			return nil, errors.Errorf("failed to decode blocks: %s: expected a map, got %T", typ, v)
+		}
+		var schema BlockSchema
+		for kk, vv := range mm {
+			switch strings.ToLower(kk) {
+			case "required":
+				required, err := cast.ToStringSliceE(vv)
+				if err != nil {
+					return nil, errors.Wrapf(err, "failed to decode blocks: %s.required", typ)
+				}
+				for i, s := range required {
+					required[i] = strings.ToLower(s)
+				}
+				schema.Required = required
+			case "cache":
+				cache, err := cast.ToBoolE(vv)
+				if err != nil {
+					return nil, errors.Wrapf(err, "failed to decode blocks: %s.cache", typ)
+				}
+				schema.Cache = cache
+			case "params":
+				pm, err := maps.ToStringMapE(vv)
+				if err != nil {
+					return nil, errors.Errorf("failed to decode blocks: %s.params: expected a map, got %T", typ, vv)
+				}
+				schema.Params, err = DecodeParamTypesConfig(pm)
+				if err != nil {
+					return nil, errors.Wrapf(err, "failed to decode blocks: %s", typ)
+				}
+			default:
+				return nil, errors.Errorf("failed to decode blocks: %s: unknown option %q", typ, kk)
+			}
+		}
+		schemas[typ] = schema
+	}
+	return schemas, nil
+}
+
+// DecodeBlocks decodes the blocks set in front matter, a list of maps with
+// the block type set in "type". The type is removed from the returned
+// params.
+func DecodeBlocks(v interface{}) (types []string, params []maps.Params, err error) {
+	items, ok := v.([]interface{})
+	if !ok {
+		if m, ok := v.([]map[string]interface{}); ok {
+			for _, mm := range m {
+				items = append(items, mm)
+			}
+		} else {
+			return nil, nil, errors.Errorf("blocks: expected a list of maps, got %T", v)
+		}
+	}
+
+	for i, item := range items {
+		m, err := maps.ToStringMapE(item)
+		if err != nil {
+			return nil, nil, errors.Errorf("blocks[%d]: expected a map, got %T", i, item)
+		}
+		// Copy the map, so the one in front matter is left untouched.
+		p := make(maps.Params, len(m))
+		for k, v := range m {
+			p[k] = v
+		}
+		maps.PrepareParams(p)
+		typ := strings.ToLower(cast.ToString(p["type"]))
+		if typ == "" {
+			return nil, nil, errors.Errorf("blocks[%d]: type not set", i)
+		}
+		delete(p, "type")
+		types = append(types, typ)
+		params = append(params, p)
+	}
+
+	return
+}
+
+// Validate validates the params of a block of the given type against its
+// schema, converting the params not of their expected type where possible.
+// It returns an error for every problem found, if any schemas are set.
+func (s BlockSchemas) Validate(typ string, params maps.Params) []error {
+	if len(s) == 0 {
+		return nil
+	}
+
+	schema, found := s[typ]
+	if !found {
+		types := make([]string, 0, len(s))
+		for k := range s {
+			types = append(types, k)
+		}
+		sort.Strings(types)
+		return []error{errors.Errorf("block type %q not found in %v", typ, types)}
+	}
+
+	var errs []error
+	for _, name := range schema.Required {
+		if v, found := params[name]; !found || v == nil || v == "" {
+			errs = append(errs, errors.Errorf("block %q: required param %q not set", typ, name))
+		}
+	}
+	for _, err := range schema.Params.Coerce(params) {
+		errs = append(errs, errors.Wrapf(err, "block %q", typ))
+	}
+
+	return errs
+}
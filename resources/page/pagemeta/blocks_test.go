@@ -0,0 +1,90 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagemeta
+
+import (
+	"testing"
+
+	"github.com/gohugoio/hugo/common/maps"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestDecodeBlockSchemasConfig(t *testing.T) {
+	c := qt.New(t)
+
+	schemas, err := DecodeBlockSchemasConfig(map[string]interface{}{
+		"FAQ": map[string]interface{}{
+			"required": []interface{}{"Question", "answer"},
+			"params": map[string]interface{}{
+				"question": "string",
+			},
+		},
+		"steps": map[string]interface{}{},
+		"hero":  map[string]interface{}{"cache": "true"},
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(schemas, qt.DeepEquals, BlockSchemas{
+		"faq":   {Required: []string{"question", "answer"}, Params: ParamTypes{"question": ParamTypeString}},
+		"steps": {},
+		"hero":  {Cache: true},
+	})
+
+	_, err = DecodeBlockSchemasConfig(map[string]interface{}{
+		"faq": map[string]interface{}{"foo": "bar"},
+	})
+	c.Assert(err, qt.ErrorMatches, `.*unknown option "foo"`)
+
+	_, err = DecodeBlockSchemasConfig(map[string]interface{}{
+		"faq": map[string]interface{}{"params": map[string]interface{}{"question": "text"}},
+	})
+	c.Assert(err, qt.ErrorMatches, `.*unsupported type "text"`)
+}
+
+func TestDecodeBlocks(t *testing.T) {
+	c := qt.New(t)
+
+	item := map[string]interface{}{"Type": "FAQ", "Question": "Why?"}
+	types, params, err := DecodeBlocks([]interface{}{item})
+	c.Assert(err, qt.IsNil)
+	c.Assert(types, qt.DeepEquals, []string{"faq"})
+	c.Assert(params, qt.DeepEquals, []maps.Params{{"question": "Why?"}})
+	c.Assert(item["Type"], qt.Equals, "FAQ")
+
+	_, _, err = DecodeBlocks([]interface{}{map[string]interface{}{"question": "Why?"}})
+	c.Assert(err, qt.ErrorMatches, `blocks\[0\]: type not set`)
+
+	_, _, err = DecodeBlocks("faq")
+	c.Assert(err, qt.Not(qt.IsNil))
+}
+
+func TestBlockSchemasValidate(t *testing.T) {
+	c := qt.New(t)
+
+	c.Assert(BlockSchemas(nil).Validate("faq", maps.Params{}), qt.HasLen, 0)
+
+	schemas := BlockSchemas{
+		"faq": {Required: []string{"question", "answer"}, Params: ParamTypes{"weight": ParamTypeInt}},
+	}
+
+	params := maps.Params{"question": "Why?", "weight": "3"}
+	errs := schemas.Validate("faq", params)
+	c.Assert(errs, qt.HasLen, 2)
+	c.Assert(errs[0], qt.ErrorMatches, `block "faq": required param "answer" not set`)
+	c.Assert(params["weight"], qt.Equals, 3)
+
+	errs = schemas.Validate("steps", maps.Params{})
+	c.Assert(errs, qt.HasLen, 1)
+	c.Assert(errs[0], qt.ErrorMatches, `block type "steps" not found in \[faq\]`)
+}
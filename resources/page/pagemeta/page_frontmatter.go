@@ -24,6 +24,8 @@ import (
 	"github.com/gohugoio/hugo/resources/resource"
 
 	"github.com/gohugoio/hugo/config"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
 	"github.com/spf13/cast"
 )
 
@@ -423,3 +425,61 @@ func (f *frontmatterFieldHandlers) newDateGitAuthorDateHandler(setter func(d *Fr
 		return true, nil
 	}
 }
+
+// FrontMatterFormat configures the front matter format used for new content
+// and by hugo convert frontmatter, set in the frontmatter section.
+type FrontMatterFormat struct {
+	// The front matter format, "yaml", "toml" or "json". If not set, the
+	// format in the archetype is kept.
+	Format string
+
+	// The front matter formats for given archetypes, e.g. posts = "toml".
+	ArchetypeFormats map[string]string
+}
+
+// FormatFor returns the front matter format for content created from the
+// archetype kind, or an empty string if any format is allowed.
+func (c FrontMatterFormat) FormatFor(kind string) string {
+	if f, found := c.ArchetypeFormats[strings.ToLower(kind)]; found {
+		return f
+	}
+	return c.Format
+}
+
+// DecodeFrontMatterFormat decodes the format settings in the frontmatter section.
+func DecodeFrontMatterFormat(cfg config.Provider) (FrontMatterFormat, error) {
+	var c FrontMatterFormat
+	m := cfg.GetStringMap("frontmatter")
+	if m == nil {
+		return c, nil
+	}
+
+	// The date settings in the same section are decoded in newFrontmatterConfig.
+	if err := mapstructure.WeakDecode(m, &c); err != nil {
+		return c, errors.Wrap(err, "failed to decode frontmatter config")
+	}
+
+	validate := func(format string) (string, error) {
+		format = strings.ToLower(format)
+		switch format {
+		case "", "yaml", "toml", "json":
+			return format, nil
+		}
+		return "", errors.Errorf("frontmatter: invalid format %q, must be one of \"yaml\", \"toml\" or \"json\"", format)
+	}
+
+	var err error
+	if c.Format, err = validate(c.Format); err != nil {
+		return c, err
+	}
+
+	archetypeFormats := make(map[string]string)
+	for k, v := range c.ArchetypeFormats {
+		if archetypeFormats[strings.ToLower(k)], err = validate(v); err != nil {
+			return c, err
+		}
+	}
+	c.ArchetypeFormats = archetypeFormats
+
+	return c, nil
+}
@@ -254,3 +254,28 @@ func TestFrontMatterDateFieldHandler(t *testing.T) {
 	c.Assert(err, qt.IsNil)
 	c.Assert(fd.Dates.FDate, qt.Equals, d)
 }
+
+func TestDecodeFrontMatterFormat(t *testing.T) {
+	c := qt.New(t)
+
+	v := config.New()
+	v.Set("frontmatter", map[string]interface{}{
+		"date":   []string{"date", ":default"},
+		"format": "YAML",
+		"archetypeFormats": map[string]interface{}{
+			"Posts": "toml",
+		},
+	})
+
+	f, err := DecodeFrontMatterFormat(v)
+	c.Assert(err, qt.IsNil)
+	c.Assert(f.FormatFor("posts"), qt.Equals, "toml")
+	c.Assert(f.FormatFor("docs"), qt.Equals, "yaml")
+
+	v.Set("frontmatter", map[string]interface{}{
+		"format": "xml",
+	})
+
+	_, err = DecodeFrontMatterFormat(v)
+	c.Assert(err, qt.Not(qt.IsNil))
+}
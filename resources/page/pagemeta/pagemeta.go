@@ -14,7 +14,11 @@
 package pagemeta
 
 import (
+	"strings"
+
+	"github.com/gohugoio/hugo/common/maps"
 	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
 )
 
 type URLPath struct {
@@ -24,6 +28,13 @@ type URLPath struct {
 	Section   string
 }
 
+// The sources of the front matter values, see Page.ParamSource.
+const (
+	SourceFrontMatter = "frontmatter"
+	SourceCascade     = "cascade"
+	SourceDefaults    = "defaults"
+)
+
 const (
 	Never       = "never"
 	Always      = "always"
@@ -108,3 +119,26 @@ func DecodeBuildConfig(m interface{}) (BuildConfig, error) {
 
 	return b, err
 }
+
+// DecodeDefaultsConfig decodes the frontMatterDefaults config, which holds the
+// default front matter values per content type, e.g.:
+//
+//	[frontMatterDefaults.posts]
+//	toc = true
+//
+// The defaults are applied to the pages of the type, i.e. the type set in
+// front matter or the section, for the keys not set in front matter or in
+// a cascade. The types are lower case in the returned map.
+func DecodeDefaultsConfig(m map[string]interface{}) (map[string]maps.Params, error) {
+	defaults := make(map[string]maps.Params)
+	for typ, v := range m {
+		params, err := maps.ToStringMapE(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decode frontMatterDefaults for type %q", typ)
+		}
+		p := maps.Params(params)
+		maps.PrepareParams(p)
+		defaults[strings.ToLower(typ)] = p
+	}
+	return defaults, nil
+}
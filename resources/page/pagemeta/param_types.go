@@ -0,0 +1,202 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagemeta
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gohugoio/hugo/common/maps"
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+)
+
+// ParamType is the expected type of a page param, see ParamTypes.
+type ParamType string
+
+// The supported param types.
+const (
+	ParamTypeString      ParamType = "string"
+	ParamTypeBool        ParamType = "bool"
+	ParamTypeInt         ParamType = "int"
+	ParamTypeFloat       ParamType = "float"
+	ParamTypeDate        ParamType = "date"
+	ParamTypeStringSlice ParamType = "[]string"
+)
+
+// ParamTypes holds the expected types of page params keyed by their lower
+// case param path, e.g. "tags" or "author.name".
+type ParamTypes map[string]ParamType
+
+// DecodeParamTypesConfig decodes the paramTypes config, e.g.:
+//
+//	[paramTypes]
+//	tags = "[]string"
+//	featured = "bool"
+//	[paramTypes.author]
+//	name = "string"
+func DecodeParamTypesConfig(m map[string]interface{}) (ParamTypes, error) {
+	types := make(ParamTypes)
+	if err := decodeParamTypes(types, "", m); err != nil {
+		return nil, errors.Wrap(err, "failed to decode paramTypes")
+	}
+	return types, nil
+}
+
+func decodeParamTypes(types ParamTypes, prefix string, m map[string]interface{}) error {
+	for k, v := range m {
+		key := prefix + strings.ToLower(k)
+		if s, ok := v.(string); ok {
+			typ := ParamType(strings.ToLower(strings.TrimSpace(s)))
+			switch typ {
+			case ParamTypeString, ParamTypeBool, ParamTypeInt, ParamTypeFloat, ParamTypeDate, ParamTypeStringSlice:
+			default:
+				return errors.Errorf("%s: unsupported type %q", key, s)
+			}
+			types[key] = typ
+			continue
+		}
+		mm, err := maps.ToStringMapE(v)
+		if err != nil {
+			return errors.Errorf("%s: expected a type name or a map, got %T", key, v)
+		}
+		if err := decodeParamTypes(types, key+".", mm); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Coerce converts the values in params not of their expected type to that
+// type, if possible. It returns an error for every such value, converted
+// or not, ordered by key.
+func (t ParamTypes) Coerce(params maps.Params) []error {
+	keys := make([]string, 0, len(t))
+	for k := range t {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var errs []error
+
+	for _, key := range keys {
+		typ := t[key]
+		path := strings.Split(key, ".")
+		m := lookupParamsMap(params, path[:len(path)-1])
+		if m == nil {
+			continue
+		}
+		name := path[len(path)-1]
+		v, found := m[name]
+		if !found || v == nil || typ.matches(v) {
+			continue
+		}
+		vv, err := typ.convert(v)
+		if err != nil {
+			errs = append(errs, errors.Errorf("param %q: %v (%T) is not a %s", key, v, v, typ))
+			continue
+		}
+		m[name] = vv
+		errs = append(errs, errors.Errorf("param %q: converted %v (%T) to a %s", key, v, v, typ))
+	}
+
+	return errs
+}
+
+func lookupParamsMap(params maps.Params, path []string) map[string]interface{} {
+	m := map[string]interface{}(params)
+	for _, p := range path {
+		switch v := m[p].(type) {
+		case maps.Params:
+			m = v
+		case map[string]interface{}:
+			m = v
+		default:
+			return nil
+		}
+	}
+	return m
+}
+
+func (t ParamType) matches(v interface{}) bool {
+	switch t {
+	case ParamTypeString:
+		_, ok := v.(string)
+		return ok
+	case ParamTypeBool:
+		_, ok := v.(bool)
+		return ok
+	case ParamTypeInt:
+		switch v.(type) {
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+			return true
+		}
+	case ParamTypeFloat:
+		switch v.(type) {
+		case float32, float64:
+			return true
+		}
+	case ParamTypeDate:
+		_, ok := v.(time.Time)
+		return ok
+	case ParamTypeStringSlice:
+		_, ok := v.([]string)
+		return ok
+	}
+	return false
+}
+
+func (t ParamType) convert(v interface{}) (interface{}, error) {
+	switch t {
+	case ParamTypeString:
+		return cast.ToStringE(v)
+	case ParamTypeBool:
+		if s, ok := v.(string); ok {
+			v = strings.TrimSpace(s)
+		}
+		return cast.ToBoolE(v)
+	case ParamTypeInt:
+		if s, ok := v.(string); ok {
+			v = strings.TrimSpace(s)
+		}
+		return cast.ToIntE(v)
+	case ParamTypeFloat:
+		if s, ok := v.(string); ok {
+			v = strings.TrimSpace(s)
+		}
+		return cast.ToFloat64E(v)
+	case ParamTypeDate:
+		return cast.ToTimeE(v)
+	case ParamTypeStringSlice:
+		switch vv := v.(type) {
+		case string:
+			// A single value, e.g. tags = "hugo".
+			return []string{vv}, nil
+		case []interface{}:
+			a := make([]string, len(vv))
+			for i, u := range vv {
+				s, err := cast.ToStringE(u)
+				if err != nil {
+					return nil, err
+				}
+				a[i] = s
+			}
+			return a, nil
+		}
+		return nil, fmt.Errorf("unable to convert %T to []string", v)
+	}
+	return nil, fmt.Errorf("unsupported type %q", t)
+}
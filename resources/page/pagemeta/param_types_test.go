@@ -0,0 +1,90 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagemeta
+
+import (
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gohugoio/hugo/common/maps"
+)
+
+func TestDecodeParamTypesConfig(t *testing.T) {
+	c := qt.New(t)
+
+	types, err := DecodeParamTypesConfig(map[string]interface{}{
+		"Tags":     "[]string",
+		"featured": " Bool",
+		"author": map[string]interface{}{
+			"name": "string",
+		},
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(types, qt.DeepEquals, ParamTypes{
+		"tags":        ParamTypeStringSlice,
+		"featured":    ParamTypeBool,
+		"author.name": ParamTypeString,
+	})
+
+	_, err = DecodeParamTypesConfig(map[string]interface{}{"tags": "[]int"})
+	c.Assert(err, qt.ErrorMatches, `.*tags: unsupported type "\[\]int"`)
+
+	_, err = DecodeParamTypesConfig(map[string]interface{}{"tags": 32})
+	c.Assert(err, qt.Not(qt.IsNil))
+}
+
+func TestParamTypesCoerce(t *testing.T) {
+	c := qt.New(t)
+
+	types := ParamTypes{
+		"tags":        ParamTypeStringSlice,
+		"categories":  ParamTypeStringSlice,
+		"featured":    ParamTypeBool,
+		"hidden":      ParamTypeBool,
+		"rating":      ParamTypeInt,
+		"score":       ParamTypeFloat,
+		"year":        ParamTypeString,
+		"expires":     ParamTypeDate,
+		"missing":     ParamTypeBool,
+		"author.name": ParamTypeString,
+	}
+
+	params := maps.Params{
+		"tags":       "hugo",
+		"categories": []string{"go"},
+		"featured":   "false",
+		"hidden":     "maybe",
+		"rating":     " 4",
+		"score":      int64(3),
+		"year":       int64(2021),
+		"expires":    "2021-06-01",
+		"author":     maps.Params{"name": 42},
+	}
+
+	c.Assert(types.Coerce(params), qt.HasLen, 8)
+	c.Assert(params, qt.DeepEquals, maps.Params{
+		"tags":       []string{"hugo"},
+		"categories": []string{"go"},
+		"featured":   false,
+		"hidden":     "maybe",
+		"rating":     4,
+		"score":      float64(3),
+		"year":       "2021",
+		"expires":    time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC),
+		"author":     maps.Params{"name": "42"},
+	})
+
+	c.Assert(types.Coerce(params), qt.HasLen, 1)
+}
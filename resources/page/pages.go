@@ -17,6 +17,7 @@ import (
 	"fmt"
 	"math/rand"
 
+	"github.com/gohugoio/hugo/common/collections"
 	"github.com/gohugoio/hugo/compare"
 
 	"github.com/gohugoio/hugo/resources/resource"
@@ -42,6 +43,18 @@ func (ps Pages) shuffle() {
 	}
 }
 
+// ShuffleSeeded returns a copy of the pages in a pseudo-random order
+// determined by seed, e.g. a date. The same pages and seed always give the
+// same order, which makes it suitable for e.g. "random" related content that
+// should not change between rebuilds.
+func (ps Pages) ShuffleSeeded(seed interface{}) Pages {
+	shuffled := make(Pages, len(ps))
+	for i, j := range collections.SeededPerm(seed, len(ps)) {
+		shuffled[i] = ps[j]
+	}
+	return shuffled
+}
+
 // ToResources wraps resource.ResourcesConverter
 func (pages Pages) ToResources() resource.Resources {
 	r := make(resource.Resources, len(pages))
@@ -183,7 +183,12 @@ func (s *RelatedDocsHandler) getOrCreateIndex(p Pages) (*related.InvertedIndex,
 
 	searchIndex := related.NewInvertedIndex(s.cfg)
 
-	for _, page := range p {
+	pages := p
+	if s.cfg.IncludeOtherLanguages {
+		pages = appendUntranslated(p)
+	}
+
+	for _, page := range pages {
 		if err := searchIndex.Add(page); err != nil {
 			return nil, err
 		}
@@ -193,3 +198,45 @@ func (s *RelatedDocsHandler) getOrCreateIndex(p Pages) (*related.InvertedIndex,
 
 	return searchIndex, nil
 }
+
+// appendUntranslated appends the pages in other languages, in the same
+// sections as the pages in p, that are not translated to the language of p.
+func appendUntranslated(p Pages) Pages {
+	if len(p) == 0 {
+		return p
+	}
+
+	lang := p[0].Language().Lang
+
+	sections := make(map[string]bool)
+	for _, pp := range p {
+		sections[pp.Section()] = true
+	}
+
+	isTranslated := func(pp Page) bool {
+		for _, t := range pp.Translations() {
+			if t.Language().Lang == lang {
+				return true
+			}
+		}
+		return false
+	}
+
+	pages := p
+	for _, s := range p[0].Sites() {
+		if s.Language().Lang == lang {
+			continue
+		}
+		for _, pp := range s.RegularPages() {
+			if sections[pp.Section()] && !isTranslated(pp) {
+				if len(pages) == len(p) {
+					// Do not modify p.
+					pages = append(Pages(nil), p...)
+				}
+				pages = append(pages, pp)
+			}
+		}
+	}
+
+	return pages
+}
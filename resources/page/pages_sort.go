@@ -128,6 +128,14 @@ var (
 		return compare.LessStrings(p1.LinkTitle(), p2.LinkTitle())
 	}
 
+	lessPageTitleNatural = func(p1, p2 Page) bool {
+		return compare.LessStringsNatural(p1.Title(), p2.Title())
+	}
+
+	lessPageLinkTitleNatural = func(p1, p2 Page) bool {
+		return compare.LessStringsNatural(p1.LinkTitle(), p2.LinkTitle())
+	}
+
 	lessPageDate = func(p1, p2 Page) bool {
 		return p1.Date().Unix() < p2.Date().Unix()
 	}
@@ -192,6 +200,32 @@ func (p Pages) ByLinkTitle() Pages {
 	return pages
 }
 
+// ByTitleNatural sorts the Pages by title in natural order, i.e. with
+// numbers in the titles compared by value, and returns a copy.
+//
+// Adjacent invocations on the same receiver will return a cached result.
+//
+// This may safely be executed  in parallel.
+func (p Pages) ByTitleNatural() Pages {
+	const key = "pageSort.ByTitleNatural"
+
+	pages, _ := spc.get(key, pageBy(lessPageTitleNatural).Sort, p)
+	return pages
+}
+
+// ByLinkTitleNatural sorts the Pages by link title in natural order and
+// returns a copy.
+//
+// Adjacent invocations on the same receiver will return a cached result.
+//
+// This may safely be executed  in parallel.
+func (p Pages) ByLinkTitleNatural() Pages {
+	const key = "pageSort.ByLinkTitleNatural"
+
+	pages, _ := spc.get(key, pageBy(lessPageLinkTitleNatural).Sort, p)
+	return pages
+}
+
 // ByDate sorts the Pages by date and returns a copy.
 //
 // Adjacent invocations on the same receiver will return a cached result.
@@ -15,6 +15,7 @@ package page
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -97,6 +98,29 @@ func TestSortByLinkTitle(t *testing.T) {
 	}
 }
 
+func TestSortByTitleNatural(t *testing.T) {
+	t.Parallel()
+	c := qt.New(t)
+	pages := createSortTestPages(4)
+
+	for i, title := range []string{"Part 10", "Part 2", "part 1", "Part 2b"} {
+		pp := pages[i].(*testPage)
+		pp.title = title
+		pp.linkTitle = "Chapter " + strings.TrimPrefix(strings.ToLower(title), "part ")
+	}
+
+	var titles, linkTitles []string
+	for _, p := range pages.ByTitleNatural() {
+		titles = append(titles, p.Title())
+	}
+	for _, p := range pages.ByLinkTitleNatural() {
+		linkTitles = append(linkTitles, p.LinkTitle())
+	}
+
+	c.Assert(titles, qt.DeepEquals, []string{"part 1", "Part 2", "Part 2b", "Part 10"})
+	c.Assert(linkTitles, qt.DeepEquals, []string{"Chapter 1", "Chapter 2", "Chapter 2b", "Chapter 10"})
+}
+
 func TestSortByN(t *testing.T) {
 	t.Parallel()
 	d1 := time.Now()
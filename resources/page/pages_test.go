@@ -70,3 +70,20 @@ func TestToPages(t *testing.T) {
 	_, err := ToPages("not a page")
 	c.Assert(err, qt.Not(qt.IsNil))
 }
+
+func TestShuffleSeeded(t *testing.T) {
+	c := qt.New(t)
+
+	pages := createSortTestPages(10)
+
+	shuffled := pages.ShuffleSeeded("2021-06-01")
+	c.Assert(shuffled, qt.HasLen, len(pages))
+	c.Assert(pages.ShuffleSeeded("2021-06-01"), eq, shuffled)
+	c.Assert(pages.ShuffleSeeded("2021-06-02"), qt.Not(eq), shuffled)
+	c.Assert(shuffled, qt.Not(eq), pages)
+
+	// The original is left untouched.
+	c.Assert(pages[0].Weight(), qt.Equals, createSortTestPages(10)[0].Weight())
+
+	c.Assert(Pages{}.ShuffleSeeded(1), qt.HasLen, 0)
+}
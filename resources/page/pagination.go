@@ -248,6 +248,39 @@ func splitPageGroups(pageGroups PagesGroup, size int) []paginatedElement {
 	return split
 }
 
+// splitPageGroupsKeepGroups splits pageGroups into pagers without splitting
+// any group across pagers, unless the group is larger than size.
+func splitPageGroupsKeepGroups(pageGroups PagesGroup, size int) []paginatedElement {
+	var (
+		split []paginatedElement
+		pg    PagesGroup
+		count int
+	)
+
+	for _, g := range pageGroups {
+		if count > 0 && count+len(g.Pages) > size {
+			split = append(split, pg)
+			pg, count = nil, 0
+		}
+
+		if len(g.Pages) <= size {
+			pg = append(pg, g)
+			count += len(g.Pages)
+			continue
+		}
+
+		for _, pages := range splitPages(g.Pages, size) {
+			split = append(split, PagesGroup{PageGroup{Key: g.Key, Pages: pages.(Pages)}})
+		}
+	}
+
+	if count > 0 {
+		split = append(split, pg)
+	}
+
+	return split
+}
+
 func ResolvePagerSize(cfg config.Provider, options ...interface{}) (int, error) {
 	if len(options) == 0 {
 		return cfg.GetInt("paginate"), nil
@@ -266,7 +299,10 @@ func ResolvePagerSize(cfg config.Provider, options ...interface{}) (int, error)
 	return pas, nil
 }
 
-func Paginate(td TargetPathDescriptor, seq interface{}, pagerSize int) (*Paginator, error) {
+// Paginate creates a paginator for seq, which can be Pages or a PagesGroup.
+// If keepGroups is set, the groups in a PagesGroup are not split across
+// pagers, unless a group has more than pagerSize pages.
+func Paginate(td TargetPathDescriptor, seq interface{}, pagerSize int, keepGroups bool) (*Paginator, error) {
 	if pagerSize <= 0 {
 		return nil, errors.New("'paginate' configuration setting must be positive to paginate")
 	}
@@ -279,8 +315,9 @@ func Paginate(td TargetPathDescriptor, seq interface{}, pagerSize int) (*Paginat
 	if err != nil {
 		return nil, err
 	}
-	if groups != nil {
-		paginator, _ = newPaginatorFromPageGroups(groups, pagerSize, urlFactory)
+	if _, isGroups := seq.(PagesGroup); isGroups || groups != nil {
+		// Note that empty groupings are nil.
+		paginator, _ = newPaginatorFromPageGroups(groups, pagerSize, keepGroups, urlFactory)
 	} else {
 		pages, err := ToPages(seq)
 		if err != nil {
@@ -352,12 +389,17 @@ func newPaginatorFromPages(pages Pages, size int, urlFactory paginationURLFactor
 	return newPaginator(split, len(pages), size, urlFactory)
 }
 
-func newPaginatorFromPageGroups(pageGroups PagesGroup, size int, urlFactory paginationURLFactory) (*Paginator, error) {
+func newPaginatorFromPageGroups(pageGroups PagesGroup, size int, keepGroups bool, urlFactory paginationURLFactory) (*Paginator, error) {
 	if size <= 0 {
 		return nil, errors.New("Paginator size must be positive")
 	}
 
-	split := splitPageGroups(pageGroups, size)
+	var split []paginatedElement
+	if keepGroups {
+		split = splitPageGroupsKeepGroups(pageGroups, size)
+	} else {
+		split = splitPageGroups(pageGroups, size)
+	}
 
 	return newPaginator(split, pageGroups.Len(), size, urlFactory)
 }
@@ -79,6 +79,35 @@ func TestSplitPageGroups(t *testing.T) {
 	}
 }
 
+func TestSplitPageGroupsKeepGroups(t *testing.T) {
+	t.Parallel()
+	c := qt.New(t)
+	pages := createTestPages(10)
+
+	groups := PagesGroup{
+		{Key: "a", Pages: pages[0:2]},
+		{Key: "b", Pages: pages[2:4]},
+		{Key: "c", Pages: pages[4:9]},
+		{Key: "d", Pages: pages[9:10]},
+	}
+
+	chunks := splitPageGroupsKeepGroups(groups, 3)
+	c.Assert(len(chunks), qt.Equals, 5)
+
+	var keys [][]interface{}
+	for _, chunk := range chunks {
+		var k []interface{}
+		for _, g := range chunk.(PagesGroup) {
+			k = append(k, g.Key)
+		}
+		keys = append(keys, k)
+	}
+
+	c.Assert(keys, qt.DeepEquals, [][]interface{}{{"a"}, {"b"}, {"c"}, {"c"}, {"d"}})
+	c.Assert(chunks[2].Len(), qt.Equals, 3)
+	c.Assert(chunks[3].Len(), qt.Equals, 2)
+}
+
 func TestPager(t *testing.T) {
 	t.Parallel()
 	c := qt.New(t)
@@ -92,7 +121,7 @@ func TestPager(t *testing.T) {
 	_, err := newPaginatorFromPages(pages, -1, urlFactory)
 	c.Assert(err, qt.Not(qt.IsNil))
 
-	_, err = newPaginatorFromPageGroups(groups, -1, urlFactory)
+	_, err = newPaginatorFromPageGroups(groups, -1, false, urlFactory)
 	c.Assert(err, qt.Not(qt.IsNil))
 
 	pag, err := newPaginatorFromPages(pages, 5, urlFactory)
@@ -103,7 +132,7 @@ func TestPager(t *testing.T) {
 	c.Assert(first.Pages(), qt.Not(qt.HasLen), 0)
 	c.Assert(first.PageGroups(), qt.HasLen, 0)
 
-	pag, err = newPaginatorFromPageGroups(groups, 5, urlFactory)
+	pag, err = newPaginatorFromPageGroups(groups, 5, false, urlFactory)
 	c.Assert(err, qt.IsNil)
 	doTestPages(t, pag)
 	first = pag.Pagers()[0].First()
@@ -162,7 +191,7 @@ func TestPagerNoPages(t *testing.T) {
 	c.Assert(first.PageGroups(), qt.HasLen, 0)
 	c.Assert(first.Pages(), qt.HasLen, 0)
 
-	paginator, _ = newPaginatorFromPageGroups(groups, 5, urlFactory)
+	paginator, _ = newPaginatorFromPageGroups(groups, 5, false, urlFactory)
 	doTestPagerNoPages(t, paginator)
 
 	first = paginator.Pagers()[0].First()
@@ -290,7 +319,7 @@ func TestPaginationPage(t *testing.T) {
 	fivePagesFuzzyWordCount, _ := createTestPages(7).GroupBy("FuzzyWordCount", "asc")
 
 	p1, _ := newPaginatorFromPages(fivePages, 2, urlFactory)
-	p2, _ := newPaginatorFromPageGroups(fivePagesFuzzyWordCount, 2, urlFactory)
+	p2, _ := newPaginatorFromPageGroups(fivePagesFuzzyWordCount, 2, false, urlFactory)
 
 	f1 := p1.pagers[0].First()
 	f2 := p2.pagers[0].First()
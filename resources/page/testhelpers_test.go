@@ -145,6 +145,14 @@ func (p *testPage) BaseFileName() string {
 	panic("not implemented")
 }
 
+func (p *testPage) BlockAssets() (resource.Resources, error) {
+	panic("not implemented")
+}
+
+func (p *testPage) Blocks() Blocks {
+	panic("not implemented")
+}
+
 func (p *testPage) BundleType() files.ContentClass {
 	panic("not implemented")
 }
@@ -257,10 +265,18 @@ func (p *testPage) HasShortcode(name string) bool {
 	panic("not implemented")
 }
 
+func (p *testPage) Shortcodes() ShortcodeInfos {
+	panic("not implemented")
+}
+
 func (p *testPage) Hugo() hugo.Info {
 	panic("not implemented")
 }
 
+func (p *testPage) ID() string {
+	panic("not implemented")
+}
+
 func (p *testPage) InSection(other interface{}) (bool, error) {
 	panic("not implemented")
 }
@@ -359,10 +375,18 @@ func (p *testPage) Next() Page {
 	panic("not implemented")
 }
 
+func (p *testPage) NextIn(pages interface{}) (Page, error) {
+	panic("not implemented")
+}
+
 func (p *testPage) NextInSection() Page {
 	return nil
 }
 
+func (p *testPage) NextInSeries() Page {
+	return nil
+}
+
 func (p *testPage) NextPage() Page {
 	return nil
 }
@@ -395,6 +419,10 @@ func (p *testPage) Param(key interface{}) (interface{}, error) {
 	return resource.Param(p, nil, key)
 }
 
+func (p *testPage) ParamSource(key string) string {
+	panic("not implemented")
+}
+
 func (p *testPage) Params() maps.Params {
 	return p.params
 }
@@ -415,6 +443,14 @@ func (p *testPage) Permalink() string {
 	panic("not implemented")
 }
 
+func (p *testPage) OutputContentHash() string {
+	panic("not implemented")
+}
+
+func (p *testPage) ContentHash() string {
+	panic("not implemented")
+}
+
 func (p *testPage) Plain() string {
 	panic("not implemented")
 }
@@ -427,10 +463,18 @@ func (p *testPage) Prev() Page {
 	panic("not implemented")
 }
 
+func (p *testPage) PrevIn(pages interface{}) (Page, error) {
+	panic("not implemented")
+}
+
 func (p *testPage) PrevInSection() Page {
 	return nil
 }
 
+func (p *testPage) PrevInSeries() Page {
+	return nil
+}
+
 func (p *testPage) PrevPage() Page {
 	return nil
 }
@@ -475,10 +519,18 @@ func (p *testPage) Render(layout ...string) (template.HTML, error) {
 	panic("not implemented")
 }
 
+func (p *testPage) RenderBlocks(types ...string) (template.HTML, error) {
+	panic("not implemented")
+}
+
 func (p *testPage) RenderString(args ...interface{}) (template.HTML, error) {
 	panic("not implemented")
 }
 
+func (p *testPage) ResourceHints() template.HTML {
+	return ""
+}
+
 func (p *testPage) ResourceType() string {
 	panic("not implemented")
 }
@@ -516,6 +568,14 @@ func (p *testPage) SectionsPath() string {
 	return path.Join(p.sectionEntries...)
 }
 
+func (p *testPage) Sequences() Sequences {
+	panic("not implemented")
+}
+
+func (p *testPage) SeriesPages() Pages {
+	return nil
+}
+
 func (p *testPage) Site() Site {
 	panic("not implemented")
 }
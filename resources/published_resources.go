@@ -0,0 +1,58 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	"sync"
+
+	"github.com/gohugoio/hugo/media"
+)
+
+// PublishedResource describes a resource whose URL was handed to the
+// templates.
+type PublishedResource struct {
+	MediaType media.Type
+
+	// The integrity hash, set if the resource is fingerprinted.
+	Integrity string
+}
+
+// PublishedResources tracks the resources published in a build, keyed by
+// the URLs, relative or absolute, handed to the templates. This is used to
+// tell which resources a published page depends on, e.g. for resource hints.
+type PublishedResources struct {
+	m sync.Map
+}
+
+func (r *PublishedResources) add(url string, create func() PublishedResource) {
+	if r == nil || url == "" {
+		return
+	}
+	if _, found := r.m.Load(url); found {
+		return
+	}
+	r.m.Store(url, create())
+}
+
+// Get returns the resource published with the given URL, if any.
+func (r *PublishedResources) Get(url string) (PublishedResource, bool) {
+	if r == nil {
+		return PublishedResource{}, false
+	}
+	v, found := r.m.Load(url)
+	if !found {
+		return PublishedResource{}, false
+	}
+	return v.(PublishedResource), true
+}
@@ -142,6 +142,7 @@ type baseResourceInternal interface {
 	getTargetFilenames() []string
 	openDestinationsForWriting() (io.WriteCloser, error)
 	openPublishFileForWriting(relTargetPath string) (io.WriteCloser, error)
+	publishWith(write func(dst io.Writer, src io.Reader) error) error
 
 	relTargetPathForRel(rel string, addBaseTargetPath, isAbs, isURL bool) string
 }
@@ -259,6 +260,15 @@ func (l *genericResource) Permalink() string {
 }
 
 func (l *genericResource) Publish() error {
+	return l.publishWith(func(dst io.Writer, src io.Reader) error {
+		_, err := io.Copy(dst, src)
+		return err
+	})
+}
+
+// publishWith publishes the resource content, written to the destination
+// by the given func.
+func (l *genericResource) publishWith(write func(dst io.Writer, src io.Reader) error) error {
 	var err error
 	l.publishInit.Do(func() {
 		var fr hugio.ReadSeekCloser
@@ -269,13 +279,13 @@ func (l *genericResource) Publish() error {
 		defer fr.Close()
 
 		var fw io.WriteCloser
-		fw, err = helpers.OpenFilesForWriting(l.spec.BaseFs.PublishFs, l.getTargetFilenames()...)
+		fw, err = helpers.OpenFilesForWriting(l.spec.publishFs(), l.getTargetFilenames()...)
 		if err != nil {
 			return
 		}
 		defer fw.Close()
 
-		_, err = io.Copy(fw, fr)
+		err = write(fw, fr)
 	})
 
 	return err
@@ -372,6 +382,7 @@ func (r *genericResource) tryTransformedFileCache(key string, u *transformationU
 	u.mediaType = mt
 	u.data = meta.MetaData
 	u.targetPath = meta.Target
+	u.logicalPath = meta.LogicalPath
 	return f
 }
 
@@ -453,14 +464,14 @@ func (l *genericResource) openDestinationsForWriting() (w io.WriteCloser, err er
 			return
 		}
 
-		w, err = helpers.OpenFilesForWriting(l.getSpec().BaseFs.PublishFs, changedFilenames...)
+		w, err = helpers.OpenFilesForWriting(l.getSpec().publishFs(), changedFilenames...)
 	})
 
 	return
 }
 
 func (r *genericResource) openPublishFileForWriting(relTargetPath string) (io.WriteCloser, error) {
-	return helpers.OpenFilesForWriting(r.spec.BaseFs.PublishFs, r.relTargetPathsFor(relTargetPath)...)
+	return helpers.OpenFilesForWriting(r.spec.publishFs(), r.relTargetPathsFor(relTargetPath)...)
 }
 
 func (l *genericResource) permalinkFor(target string) string {
@@ -15,10 +15,12 @@ package resource
 
 import (
 	"image"
+	"time"
 
 	"github.com/gohugoio/hugo/common/maps"
 	"github.com/gohugoio/hugo/langs"
 	"github.com/gohugoio/hugo/media"
+	"github.com/gohugoio/hugo/resources/documents"
 	"github.com/gohugoio/hugo/resources/images/exif"
 
 	"github.com/gohugoio/hugo/common/hugio"
@@ -60,12 +62,39 @@ type ImageOps interface {
 	Fit(spec string) (Image, error)
 	Resize(spec string) (Image, error)
 	Filter(filters ...interface{}) (Image, error)
+	Profile(name string) (Image, error)
+	Derived() (map[string]Image, error)
 	Exif() *exif.Exif
 
 	// Internal
 	DecodeImage() (image.Image, error)
 }
 
+// Video represents a video resource.
+type Video interface {
+	Resource
+	VideoOps
+}
+
+type VideoOps interface {
+	Height() int
+	Width() int
+	Duration() time.Duration
+
+	// Poster extracts the poster frame of the video as a JPEG image.
+	Poster() (Image, error)
+
+	// PosterAt extracts the frame at the given position, e.g. "2.5s".
+	PosterAt(at string) (Image, error)
+}
+
+// DocumentMetaProvider provides the metadata of PDF and EPUB documents.
+type DocumentMetaProvider interface {
+	// DocumentMeta returns the page count, title, author and creation date
+	// of the document, nil if not available.
+	DocumentMeta() *documents.Metadata
+}
+
 type ResourceTypeProvider interface {
 	// ResourceType is the resource type. For most file types, this is the main
 	// part of the MIME type, e.g. "image", "application", "text" etc.
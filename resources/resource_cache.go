@@ -14,15 +14,22 @@
 package resources
 
 import (
+	"crypto/md5"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"io"
+	"os"
 	"path"
 	"path/filepath"
 	"regexp"
 	"strings"
 	"sync"
 
+	"github.com/gohugoio/hugo/common/hugo"
 	"github.com/gohugoio/hugo/helpers"
+	"github.com/spf13/afero"
+	"golang.org/x/sync/singleflight"
 
 	"github.com/gohugoio/hugo/hugofs/glob"
 
@@ -50,6 +57,24 @@ type ResourceCache struct {
 
 	// Provides named resource locks.
 	nlocker *locker.Locker
+
+	// Deduplicates concurrent executions of the same transformation chain.
+	transformations singleflight.Group
+
+	// The fingerprint of the files the persisted transformations may
+	// depend on. Reset on any cache invalidation.
+	inputsHashMu sync.Mutex
+	inputsHash   string
+}
+
+// The files in the working dir, besides the assets, that the persisted
+// transformations may depend on.
+var persistInputFiles = []string{
+	"package.json",
+	"package-lock.json",
+	"yarn.lock",
+	"tsconfig.json",
+	"jsconfig.json",
 }
 
 // ResourceCacheKey converts the filename into the format used in the resource
@@ -139,6 +164,55 @@ func (c *ResourceCache) clear() {
 
 	c.cache = make(map[string]interface{})
 	c.nlocker = locker.NewLocker()
+	c.resetInputsHash()
+}
+
+// getInputsHash returns a fingerprint of the files the persisted
+// transformations may depend on, i.e. the names, sizes and modification
+// times of the assets and of the persistInputFiles.
+func (c *ResourceCache) getInputsHash() (string, error) {
+	c.inputsHashMu.Lock()
+	defer c.inputsHashMu.Unlock()
+
+	if c.inputsHash != "" {
+		return c.inputsHash, nil
+	}
+
+	h := md5.New()
+	fmt.Fprintln(h, hugo.CurrentVersion.String())
+
+	write := func(name string, fi os.FileInfo) {
+		fmt.Fprintf(h, "%s|%d|%d\n", filepath.ToSlash(name), fi.Size(), fi.ModTime().UnixNano())
+	}
+
+	err := afero.Walk(c.rs.BaseFs.Assets.Fs, "", func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !fi.IsDir() {
+			write(path, fi)
+		}
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+
+	for _, name := range persistInputFiles {
+		if fi, err := c.rs.Fs.Source.Stat(filepath.Join(c.rs.WorkingDir, name)); err == nil {
+			write(name, fi)
+		}
+	}
+
+	c.inputsHash = hex.EncodeToString(h.Sum(nil))
+
+	return c.inputsHash, nil
+}
+
+func (c *ResourceCache) resetInputsHash() {
+	c.inputsHashMu.Lock()
+	c.inputsHash = ""
+	c.inputsHashMu.Unlock()
 }
 
 func (c *ResourceCache) Contains(key string) bool {
@@ -274,6 +348,8 @@ func (c *ResourceCache) DeletePartitions(partitions ...string) {
 		return
 	}
 
+	c.resetInputsHash()
+
 	c.Lock()
 	defer c.Unlock()
 
@@ -294,6 +370,8 @@ func (c *ResourceCache) DeletePartitions(partitions ...string) {
 }
 
 func (c *ResourceCache) DeleteMatches(re *regexp.Regexp) {
+	c.resetInputsHash()
+
 	c.Lock()
 	defer c.Unlock()
 
@@ -56,3 +56,30 @@ func TestResourceKeyContainsAny(t *testing.T) {
 		c.Assert(ResourceKeyContainsAny(test.key, ResourceKeyPartitions(test.filename)), qt.Equals, test.expected)
 	}
 }
+
+func TestSpecReuseCaches(t *testing.T) {
+	c := qt.New(t)
+
+	prev := newTestResourceSpec(specDescriptor{c: c})
+	img := fetchImageForSpec(prev, c, "sunset.jpg")
+	_, err := img.Resize("300x200")
+	c.Assert(err, qt.IsNil)
+	prev.ResourceCache.set("css/main.css", "main")
+
+	c.Assert(prev.imageCache.store, qt.Not(qt.HasLen), 0)
+
+	spec := newTestResourceSpec(specDescriptor{c: c})
+	spec.ReuseCaches(prev, nil)
+	c.Assert(spec.imageCache.store, qt.HasLen, len(prev.imageCache.store))
+	c.Assert(spec.ResourceCache.cache["css/main.css"], qt.Equals, "main")
+
+	spec = newTestResourceSpec(specDescriptor{c: c})
+	spec.ReuseCaches(prev, map[string]bool{CacheResources: true})
+	c.Assert(spec.imageCache.store, qt.HasLen, len(prev.imageCache.store))
+	c.Assert(spec.ResourceCache.cache, qt.HasLen, 0)
+
+	spec = newTestResourceSpec(specDescriptor{c: c})
+	spec.ReuseCaches(prev, map[string]bool{CacheImages: true, CacheResources: true})
+	c.Assert(spec.imageCache.store, qt.HasLen, 0)
+	c.Assert(spec.ResourceCache.cache, qt.HasLen, 0)
+}
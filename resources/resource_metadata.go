@@ -140,6 +140,33 @@ func AssignMetadata(metadata []map[string]interface{}, resources ...resource.Res
 	return nil
 }
 
+// AssignImageProfiles sets the profiles resource param of the images in
+// resources to the image profiles assigned to them by the profile rules for
+// the given section in the imaging config. Images that already have this
+// param set, e.g. in front matter, are left untouched.
+func AssignImageProfiles(section string, resources ...resource.Resource) {
+	for _, r := range resources {
+		var ma metaAssigner
+		if mp, ok := r.(metaAssignerProvider); ok {
+			ma = mp.getMetaAssigner()
+		} else {
+			ma, _ = r.(metaAssigner)
+		}
+
+		img, ok := ma.(*imageResource)
+		if !ok {
+			continue
+		}
+
+		profiles := img.Proc.Cfg.Cfg.ProfilesFor(section, r.Name())
+		if len(profiles) == 0 {
+			continue
+		}
+
+		img.updateParams(map[string]interface{}{"profiles": profiles})
+	}
+}
+
 func replaceResourcePlaceholders(in string, counter int) string {
 	return strings.Replace(in, counterPlaceHolder, strconv.Itoa(counter), -1)
 }
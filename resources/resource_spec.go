@@ -32,6 +32,7 @@ import (
 
 	"github.com/gohugoio/hugo/helpers"
 	"github.com/gohugoio/hugo/hugofs"
+	"github.com/gohugoio/hugo/publisher/precompress"
 	"github.com/gohugoio/hugo/resources/postpub"
 
 	"github.com/gohugoio/hugo/cache/filecache"
@@ -41,6 +42,7 @@ import (
 	"github.com/gohugoio/hugo/resources/images"
 	"github.com/gohugoio/hugo/resources/page"
 	"github.com/gohugoio/hugo/resources/resource"
+	"github.com/gohugoio/hugo/resources/videos"
 	"github.com/gohugoio/hugo/tpl"
 	"github.com/spf13/afero"
 )
@@ -63,6 +65,11 @@ func NewSpec(
 		return nil, err
 	}
 
+	videoConfig, err := videos.DecodeConfig(s.Cfg.GetStringMap("video"))
+	if err != nil {
+		return nil, err
+	}
+
 	if incr == nil {
 		incr = &identity.IncrementByOne{}
 	}
@@ -76,20 +83,31 @@ func NewSpec(
 		return nil, err
 	}
 
+	precompressClient, err := precompress.New(s.Cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	rs := &Spec{
-		PathSpec:      s,
-		Logger:        logger,
-		ErrorSender:   errorHandler,
-		imaging:       imaging,
-		incr:          incr,
-		MediaTypes:    mimeTypes,
-		OutputFormats: outputFormats,
-		Permalinks:    permalinks,
-		BuildConfig:   config.DecodeBuild(s.Cfg),
-		FileCaches:    fileCaches,
+		PathSpec:       s,
+		Logger:         logger,
+		ErrorSender:    errorHandler,
+		imaging:        imaging,
+		videoConfig:    videoConfig,
+		VideoProcessor: videos.NewProcessor(videoConfig, s.Cfg),
+		incr:           incr,
+		MediaTypes:     mimeTypes,
+		OutputFormats:  outputFormats,
+		Permalinks:     permalinks,
+		Precompress:    precompressClient,
+		BuildConfig:    config.DecodeBuild(s.Cfg),
+		FileCaches:     fileCaches,
 		PostBuildAssets: &PostBuildAssets{
 			PostProcessResources: make(map[string]postpub.PostPublishedResource),
 			JSConfigBuilder:      jsconfig.NewBuilder(),
+			AssetsManifest:       &AssetsManifest{},
+			AssetReport:          &AssetReport{},
+			PublishedResources:   &PublishedResources{},
 		},
 		imageCache: newImageCache(
 			fileCaches.ImageCache(),
@@ -117,9 +135,18 @@ type Spec struct {
 	Permalinks  page.PermalinkExpander
 	BuildConfig config.Build
 
+	// Writes the compressed siblings of the published files, nil if not enabled.
+	Precompress *precompress.Client
+
 	// Holds default filter settings etc.
 	imaging *images.ImageProcessor
 
+	videoConfig videos.Config
+
+	// Reads the video metadata and extracts the poster frames.
+	// The default runs ffprobe and ffmpeg, see the video config.
+	VideoProcessor videos.Processor
+
 	incr          identity.Incrementer
 	imageCache    *imageCache
 	ResourceCache *ResourceCache
@@ -134,6 +161,9 @@ type PostBuildAssets struct {
 	postProcessMu        sync.RWMutex
 	PostProcessResources map[string]postpub.PostPublishedResource
 	JSConfigBuilder      *jsconfig.Builder
+	AssetsManifest       *AssetsManifest
+	AssetReport          *AssetReport
+	PublishedResources   *PublishedResources
 }
 
 func (r *Spec) New(fd ResourceSourceDescriptor) (resource.Resource, error) {
@@ -163,10 +193,61 @@ func (r *Spec) ClearCaches() {
 	r.ResourceCache.clear()
 }
 
+// The in-memory caches that may be reused across config changes,
+// see ReuseCaches.
+const (
+	// CacheImages holds the processed images.
+	CacheImages = "images"
+
+	// CacheResources holds the resources, e.g. the transformed ones.
+	CacheResources = "resources"
+)
+
+// ReuseCaches copies the cached resources and processed images in prev,
+// a Spec created from a previous config, to r, except those in the
+// invalidated caches.
+func (r *Spec) ReuseCaches(prev *Spec, invalidated map[string]bool) {
+	if prev == nil || prev == r {
+		return
+	}
+
+	if !invalidated[CacheImages] && prev.imageCache != r.imageCache {
+		prev.imageCache.mu.RLock()
+		r.imageCache.mu.Lock()
+		for k, v := range prev.imageCache.store {
+			r.imageCache.store[k] = v
+		}
+		r.imageCache.mu.Unlock()
+		prev.imageCache.mu.RUnlock()
+	}
+
+	if !invalidated[CacheResources] && prev.ResourceCache != r.ResourceCache {
+		prev.ResourceCache.RLock()
+		r.ResourceCache.Lock()
+		for k, v := range prev.ResourceCache.cache {
+			r.ResourceCache.cache[k] = v
+		}
+		r.ResourceCache.Unlock()
+		prev.ResourceCache.RUnlock()
+	}
+}
+
+// ShareImageCache makes r use the processed images of other, so an image
+// variation used in more than one language is only processed once.
+func (r *Spec) ShareImageCache(other *Spec) {
+	r.imageCache = other.imageCache
+}
+
 func (r *Spec) DeleteBySubstring(s string) {
 	r.imageCache.deleteIfContains(s)
 }
 
+// publishFs returns the filesystem to publish resources to, writing the
+// compressed siblings of the files if enabled.
+func (r *Spec) publishFs() afero.Fs {
+	return r.Precompress.Fs(r.BaseFs.PublishFs)
+}
+
 func (s *Spec) String() string {
 	return "spec"
 }
@@ -308,6 +389,16 @@ func (r *Spec) newResource(sourceFs afero.Fs, fd ResourceSourceDescriptor) (reso
 
 	}
 
+	if mimeType.Type() == media.PDFType.Type() || mimeType.Type() == media.EPUBType.Type() {
+		dr := &documentResource{baseResource: gr}
+		return newResourceAdapter(gr.spec, fd.LazyPublish, dr), nil
+	}
+
+	if mimeType.MainType == "video" {
+		vr := &videoResource{baseResource: gr}
+		return newResourceAdapter(gr.spec, fd.LazyPublish, vr), nil
+	}
+
 	return newResourceAdapter(gr.spec, fd.LazyPublish, gr), nil
 }
 
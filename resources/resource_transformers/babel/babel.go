@@ -18,9 +18,7 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
-	"path"
 	"path/filepath"
-	"regexp"
 	"strconv"
 
 	"github.com/cli/safeexec"
@@ -47,7 +45,9 @@ type Options struct {
 	Compact    *bool
 	Verbose    bool
 	NoBabelrc  bool
-	SourceMap  string
+
+	// Source map options, see resources.SourceMapOptions.
+	resources.SourceMapOptions `mapstructure:",squash"`
 }
 
 // DecodeOptions decodes options to and generates command flags
@@ -62,13 +62,10 @@ func DecodeOptions(m map[string]interface{}) (opts Options, err error) {
 func (opts Options) toArgs() []string {
 	var args []string
 
-	// external is not a known constant on the babel command line
-	// .sourceMaps must be a boolean, "inline", "both", or undefined
-	switch opts.SourceMap {
-	case "external":
+	// Babel always writes an external source map, which is inlined or
+	// published as configured when the transformation is done.
+	if opts.SourceMap != "" {
 		args = append(args, "--source-maps")
-	case "inline":
-		args = append(args, "--source-maps=inline")
 	}
 	if opts.Minified {
 		args = append(args, "--minified")
@@ -157,6 +154,12 @@ func (t *babelTransformation) Transform(ctx *resources.ResourceTransformationCtx
 
 	ctx.ReplaceOutPathExtension(".js")
 
+	opts := t.options
+	if err := opts.SourceMapOptions.Validate(); err != nil {
+		return err
+	}
+	opts.SourceMap = opts.SourceMapOptions.Type(t.rs.Cfg)
+
 	var cmdArgs []string
 
 	if configFile != "" {
@@ -164,7 +167,7 @@ func (t *babelTransformation) Transform(ctx *resources.ResourceTransformationCtx
 		cmdArgs = []string{"--config-file", configFile}
 	}
 
-	if optArgs := t.options.toArgs(); len(optArgs) > 0 {
+	if optArgs := opts.toArgs(); len(optArgs) > 0 {
 		cmdArgs = append(cmdArgs, optArgs...)
 	}
 	cmdArgs = append(cmdArgs, "--filename="+ctx.SourcePath)
@@ -180,14 +183,15 @@ func (t *babelTransformation) Transform(ctx *resources.ResourceTransformationCtx
 	cmdArgs = append(cmdArgs, "--out-file="+compileOutput.Name())
 	defer os.Remove(compileOutput.Name())
 
-	cmd, err := hexec.SafeCommand(binary, cmdArgs...)
+	cmd, done, err := hexec.Command(t.rs.Cfg, "", binary, cmdArgs...)
 	if err != nil {
 		return err
 	}
+	defer done()
 
 	cmd.Stderr = io.MultiWriter(infoW, &errBuf)
 	cmd.Stdout = cmd.Stderr
-	cmd.Env = hugo.GetExecEnviron(t.rs.WorkingDir, t.rs.Cfg, t.rs.BaseFs.Assets.Fs)
+	cmd.Env = append(hugo.GetExecEnviron(t.rs.WorkingDir, t.rs.Cfg, t.rs.BaseFs.Assets.Fs), hexec.GetLimiter(t.rs.Cfg).MemoryEnv(binary)...)
 
 	stdin, err := cmd.StdinPipe()
 	if err != nil {
@@ -209,24 +213,17 @@ func (t *babelTransformation) Transform(ctx *resources.ResourceTransformationCtx
 		return err
 	}
 
+	var sourceMap []byte
 	mapFile := compileOutput.Name() + ".map"
 	if _, err := os.Stat(mapFile); err == nil {
 		defer os.Remove(mapFile)
-		sourceMap, err := ioutil.ReadFile(mapFile)
+		sourceMap, err = ioutil.ReadFile(mapFile)
 		if err != nil {
 			return err
 		}
-		if err = ctx.PublishSourceMap(string(sourceMap)); err != nil {
-			return err
-		}
-		targetPath := path.Base(ctx.OutPath) + ".map"
-		re := regexp.MustCompile(`//# sourceMappingURL=.*\n?`)
-		content = []byte(re.ReplaceAllString(string(content), "//# sourceMappingURL="+targetPath+"\n"))
 	}
 
-	ctx.To.Write(content)
-
-	return nil
+	return ctx.WriteSourceMapped(content, sourceMap, opts.SourceMapOptions)
 }
 
 // Process transforms the given Resource with the Babel processor.
@@ -0,0 +1,100 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cssmodules provides a resource transformer that scopes the class
+// names in a stylesheet, as in CSS Modules.
+package cssmodules
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/gohugoio/hugo/helpers"
+	"github.com/gohugoio/hugo/media"
+	"github.com/gohugoio/hugo/resources"
+	"github.com/gohugoio/hugo/resources/internal"
+	"github.com/gohugoio/hugo/resources/resource"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+)
+
+// Options configures the scoping of the class names.
+type Options struct {
+	// The pattern for the scoped class names. See Scope.
+	// Default is "[name]_[local]_[hash]".
+	LocalIdentName string
+}
+
+// DecodeOptions decodes options from the given map.
+func DecodeOptions(m map[string]interface{}) (opts Options, err error) {
+	if m == nil {
+		return
+	}
+
+	err = mapstructure.WeakDecode(m, &opts)
+
+	return
+}
+
+// IsModule reports whether filename is a CSS module, e.g. button.module.css.
+func IsModule(filename string) bool {
+	return strings.HasSuffix(strings.ToLower(filename), ".module.css")
+}
+
+// Client is the client used to scope class names.
+type Client struct {
+	rs *resources.Spec
+}
+
+// New creates a new Client with the given specification.
+func New(rs *resources.Spec) *Client {
+	return &Client{rs: rs}
+}
+
+type cssModulesTransformation struct {
+	options Options
+}
+
+func (t *cssModulesTransformation) Key() internal.ResourceTransformationKey {
+	return internal.NewResourceTransformationKey("cssmodules", t.options)
+}
+
+func (t *cssModulesTransformation) Transform(ctx *resources.ResourceTransformationCtx) error {
+	if ctx.InMediaType.SubType != media.CSSType.SubType {
+		return errors.Errorf("cssmodules: %s is not a stylesheet", ctx.InPath)
+	}
+
+	src, err := ioutil.ReadAll(ctx.From)
+	if err != nil {
+		return err
+	}
+
+	// Use the same filename as js.Build, so the class names match.
+	filename := strings.TrimPrefix(filepath.ToSlash(ctx.SourcePath), "/")
+
+	css, classes := Scope(string(src), filename, t.options.LocalIdentName)
+
+	ctx.Data["Classes"] = classes
+	ctx.AddOutPathIdentifier("_" + helpers.HashString(t.options))
+
+	_, err = ctx.To.Write([]byte(css))
+
+	return err
+}
+
+// Process scopes the class names in the given stylesheet. The mapping from
+// the original to the scoped class names is available in .Data.Classes.
+func (c *Client) Process(res resources.ResourceTransformer, options Options) (resource.Resource, error) {
+	return res.Transform(&cssModulesTransformation{options: options})
+}
@@ -0,0 +1,64 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cssmodules
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestScope(t *testing.T) {
+	c := qt.New(t)
+
+	css, classes := Scope(`.button { color: red; padding: .5em }
+.button.primary, div > .icon:hover { background: #fff url(a.b.png) }
+:global(.theme-dark) .button { color: white }
+:global .reset .x, .y { margin: 0 }
+:not(.primary) { opacity: 1.5 }
+@media (max-width: 600px) { .button { width: 100% } }
+@font-face { font-family: "A.b"; src: url(a.woff) }
+@keyframes fade { from { opacity: 0 } to { opacity: 1 } }
+`, "css/button.module.css", "")
+
+	c.Assert(css, qt.Equals, `.button_button_3a26b0 { color: red; padding: .5em }
+.button_button_3a26b0.button_primary_3a26b0, div > .button_icon_3a26b0:hover { background: #fff url(a.b.png) }
+.theme-dark .button_button_3a26b0 { color: white }
+.reset .x, .button_y_3a26b0 { margin: 0 }
+:not(.button_primary_3a26b0) { opacity: 1.5 }
+@media (max-width: 600px) { .button_button_3a26b0 { width: 100% } }
+@font-face { font-family: "A.b"; src: url(a.woff) }
+@keyframes fade { from { opacity: 0 } to { opacity: 1 } }
+`)
+
+	c.Assert(classes, qt.DeepEquals, map[string]string{
+		"button":  "button_button_3a26b0",
+		"primary": "button_primary_3a26b0",
+		"icon":    "button_icon_3a26b0",
+		"y":       "button_y_3a26b0",
+	})
+
+	// Class names cannot start with a digit.
+	css, _ = Scope(`.a :local(.b) {}`, "my card.module.css", "[hash]-[name]__[local]")
+	c.Assert(css, qt.Equals, `._32e570-my_card__a ._32e570-my_card__b {}`)
+}
+
+func TestIsModule(t *testing.T) {
+	c := qt.New(t)
+
+	c.Assert(IsModule("css/button.module.css"), qt.Equals, true)
+	c.Assert(IsModule("css/button.MODULE.CSS"), qt.Equals, true)
+	c.Assert(IsModule("css/button.css"), qt.Equals, false)
+	c.Assert(IsModule("css/module.css"), qt.Equals, false)
+}
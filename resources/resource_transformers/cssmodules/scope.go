@@ -0,0 +1,210 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cssmodules
+
+import (
+	"path"
+	"strings"
+
+	"github.com/gohugoio/hugo/helpers"
+	"github.com/tdewolff/parse/v2"
+	"github.com/tdewolff/parse/v2/css"
+)
+
+// DefaultLocalIdentName is the default pattern for the scoped class names.
+const DefaultLocalIdentName = "[name]_[local]_[hash]"
+
+// At-rules with nested rules, i.e. selectors, in their block.
+var nestedRulesAtRules = map[string]bool{
+	"@media":         true,
+	"@supports":      true,
+	"@document":      true,
+	"@-moz-document": true,
+	"@layer":         true,
+	"@container":     true,
+}
+
+type token struct {
+	tt   css.TokenType
+	data string
+}
+
+// Scope scopes the class names in the CSS src, read from filename, a slash
+// separated path relative to the assets dir. It returns the new CSS and
+// the mapping from the original to the scoped class names.
+//
+// The scoped class names are created from localIdentName, in which
+// [name] is replaced with the base name of filename, [local] with the
+// original class name and [hash] with a hash of filename. Class names
+// in :global(...), or following :global, are not scoped.
+func Scope(src, filename, localIdentName string) (string, map[string]string) {
+	if localIdentName == "" {
+		localIdentName = DefaultLocalIdentName
+	}
+
+	name := path.Base(filename)
+	name = strings.TrimSuffix(name, path.Ext(name))
+	name = strings.TrimSuffix(name, ".module")
+
+	r := strings.NewReplacer(
+		"[name]", sanitize(name),
+		"[hash]", helpers.MD5String(filename)[:6],
+	)
+	pattern := r.Replace(localIdentName)
+
+	classes := make(map[string]string)
+	scoped := func(local string) string {
+		if s, found := classes[local]; found {
+			return s
+		}
+		s := strings.Replace(pattern, "[local]", local, -1)
+		if s != "" && s[0] >= '0' && s[0] <= '9' {
+			// Class names cannot start with a digit.
+			s = "_" + s
+		}
+		classes[local] = s
+		return s
+	}
+
+	var tokens []token
+	l := css.NewLexer(parse.NewInputString(src))
+	for {
+		tt, data := l.Next()
+		if tt == css.ErrorToken {
+			break
+		}
+		tokens = append(tokens, token{tt: tt, data: string(data)})
+	}
+
+	var (
+		b strings.Builder
+
+		// Whether the blocks we're in contain rules, as opposed
+		// to declarations.
+		blocks []bool
+
+		// The at-rule of the current prelude, if any.
+		atRule string
+
+		// Whether we're in a :global selector, and the parenthesis
+		// depth of :global(...) and :local(...).
+		global      bool
+		globalDepth int
+		localDepths []int
+		depth       int
+	)
+
+	inRules := func() bool {
+		return len(blocks) == 0 || blocks[len(blocks)-1]
+	}
+
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+
+		if !inRules() || atRule != "" {
+			switch t.tt {
+			case css.LeftBraceToken:
+				blocks = append(blocks, inRules() && nestedRulesAtRules[atRule] || atRule == "@keyframes")
+				atRule = ""
+			case css.RightBraceToken:
+				if len(blocks) > 0 {
+					blocks = blocks[:len(blocks)-1]
+				}
+			case css.SemicolonToken:
+				atRule = ""
+			}
+			b.WriteString(t.data)
+			continue
+		}
+
+		// A selector.
+		switch t.tt {
+		case css.AtKeywordToken:
+			atRule = strings.ToLower(t.data)
+		case css.LeftBraceToken:
+			blocks = append(blocks, false)
+			global = false
+		case css.RightBraceToken:
+			if len(blocks) > 0 {
+				blocks = blocks[:len(blocks)-1]
+			}
+		case css.CommaToken:
+			if globalDepth == 0 {
+				global = false
+			}
+		case css.ColonToken:
+			if i+1 < len(tokens) {
+				next := tokens[i+1]
+				switch {
+				case next.tt == css.FunctionToken && strings.EqualFold(next.data, "global("):
+					depth++
+					globalDepth = depth
+					i++
+					continue
+				case next.tt == css.FunctionToken && strings.EqualFold(next.data, "local("):
+					depth++
+					localDepths = append(localDepths, depth)
+					i++
+					continue
+				case next.tt == css.IdentToken && strings.EqualFold(next.data, "global"):
+					global = true
+					i++
+					if i+1 < len(tokens) && tokens[i+1].tt == css.WhitespaceToken {
+						i++
+					}
+					continue
+				}
+			}
+		case css.FunctionToken, css.LeftParenthesisToken:
+			depth++
+		case css.RightParenthesisToken:
+			if globalDepth == depth {
+				globalDepth = 0
+				depth--
+				continue
+			}
+			if n := len(localDepths); n > 0 && localDepths[n-1] == depth {
+				localDepths = localDepths[:n-1]
+				depth--
+				continue
+			}
+			depth--
+		case css.DelimToken:
+			if t.data == "." && i+1 < len(tokens) && tokens[i+1].tt == css.IdentToken {
+				class := tokens[i+1].data
+				i++
+				b.WriteString(".")
+				if global || globalDepth > 0 {
+					b.WriteString(class)
+				} else {
+					b.WriteString(scoped(class))
+				}
+				continue
+			}
+		}
+
+		b.WriteString(t.data)
+	}
+
+	return b.String(), classes
+}
+
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, s)
+}
@@ -0,0 +1,110 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cssprocess provides a resource transformer that lowers nested
+// CSS, adds vendor prefixes and minifies stylesheets, covering the common
+// uses of PostCSS without any external tools.
+package cssprocess
+
+import (
+	"io/ioutil"
+	"strings"
+
+	"github.com/gohugoio/hugo/media"
+	"github.com/gohugoio/hugo/minifiers"
+	"github.com/gohugoio/hugo/resources"
+	"github.com/gohugoio/hugo/resources/internal"
+	"github.com/gohugoio/hugo/resources/resource"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+)
+
+// Options configures the processing of a stylesheet.
+type Options struct {
+	// Set to keep the nested rules, e.g. if all the targeted browsers
+	// support CSS nesting.
+	NoNesting bool
+
+	// Set to not add any vendor prefixes.
+	NoPrefixes bool
+
+	// Minify the result, using the site's minify configuration.
+	Minify bool
+}
+
+// DecodeOptions decodes options from the given map.
+func DecodeOptions(m map[string]interface{}) (opts Options, err error) {
+	if m == nil {
+		return
+	}
+
+	err = mapstructure.WeakDecode(m, &opts)
+
+	return
+}
+
+// Client is the client used to process stylesheets.
+type Client struct {
+	rs *resources.Spec
+	m  minifiers.Client
+}
+
+// New creates a new Client with the given specification.
+func New(rs *resources.Spec) (*Client, error) {
+	m, err := minifiers.New(rs.MediaTypes, rs.OutputFormats, rs.Cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{rs: rs, m: m}, nil
+}
+
+type processTransformation struct {
+	options Options
+	c       *Client
+}
+
+func (t *processTransformation) Key() internal.ResourceTransformationKey {
+	return internal.NewResourceTransformationKey("cssprocess", t.options)
+}
+
+func (t *processTransformation) Transform(ctx *resources.ResourceTransformationCtx) error {
+	if ctx.InMediaType.SubType != media.CSSType.SubType {
+		return errors.Errorf("%s is not a stylesheet", ctx.InPath)
+	}
+
+	src, err := ioutil.ReadAll(ctx.From)
+	if err != nil {
+		return err
+	}
+
+	// Any source map does not map the processed stylesheet.
+	src, _ = resources.ExtractSourceMap(src)
+	ctx.SourceMap = nil
+
+	result := Process(string(src), t.options)
+
+	if t.options.Minify {
+		ctx.AddOutPathIdentifier(".min")
+		return t.c.m.Minify(media.CSSType, ctx.To, strings.NewReader(result))
+	}
+
+	_, err = ctx.To.Write([]byte(result))
+
+	return err
+}
+
+// Process lowers the nested rules in the given stylesheet, adds vendor
+// prefixes and minifies it, as configured in options; see Process.
+func (c *Client) Process(res resources.ResourceTransformer, options Options) (resource.Resource, error) {
+	return res.Transform(&processTransformation{c: c, options: options})
+}
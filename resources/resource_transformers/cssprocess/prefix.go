@@ -0,0 +1,117 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cssprocess
+
+import "strings"
+
+// The properties still needing vendor prefixes in browsers in wide use,
+// e.g. Safari, and the prefixes to add.
+var prefixedProperties = map[string][]string{
+	"appearance":             {"-webkit-", "-moz-"},
+	"backdrop-filter":        {"-webkit-"},
+	"box-decoration-break":   {"-webkit-"},
+	"hyphens":                {"-webkit-"},
+	"initial-letter":         {"-webkit-"},
+	"mask":                   {"-webkit-"},
+	"mask-clip":              {"-webkit-"},
+	"mask-image":             {"-webkit-"},
+	"mask-origin":            {"-webkit-"},
+	"mask-position":          {"-webkit-"},
+	"mask-repeat":            {"-webkit-"},
+	"mask-size":              {"-webkit-"},
+	"print-color-adjust":     {"-webkit-"},
+	"tab-size":               {"-moz-"},
+	"text-emphasis":          {"-webkit-"},
+	"text-emphasis-color":    {"-webkit-"},
+	"text-emphasis-position": {"-webkit-"},
+	"text-emphasis-style":    {"-webkit-"},
+	"text-size-adjust":       {"-webkit-", "-moz-"},
+	"user-select":            {"-webkit-"},
+}
+
+// The property values still needing vendor prefixes, by property.
+var prefixedValues = map[string]map[string][]string{
+	"background-clip": {"text": {"-webkit-"}},
+	"position":        {"sticky": {"-webkit-"}},
+}
+
+// addPrefixes adds the prefixed variants of the declarations in the rules
+// in nodes, before the declarations, unless already set.
+func addPrefixes(nodes []*node) {
+	for _, n := range nodes {
+		switch n.typ {
+		case ruleNode:
+			n.children = prefixDeclarations(n.children)
+			addPrefixes(n.children)
+		case atRuleNode:
+			addPrefixes(n.children)
+		}
+	}
+}
+
+func prefixDeclarations(nodes []*node) []*node {
+	existing := make(map[string]bool)
+	for _, n := range nodes {
+		if n.typ == declNode {
+			existing[strings.ToLower(n.name)+":"+strings.ToLower(n.value)] = true
+			existing[strings.ToLower(n.name)] = true
+		}
+	}
+
+	var out []*node
+
+	for _, n := range nodes {
+		if n.typ != declNode {
+			out = append(out, n)
+			continue
+		}
+
+		name := strings.ToLower(n.name)
+
+		for _, prefix := range prefixedProperties[name] {
+			if !existing[prefix+name] {
+				out = append(out, &node{typ: declNode, name: prefix + n.name, value: n.value})
+			}
+		}
+
+		if values, found := prefixedValues[name]; found {
+			value, important := splitImportant(n.value)
+			for _, prefix := range values[strings.ToLower(value)] {
+				if name == "background-clip" {
+					// Safari needs the prefixed property.
+					if !existing[prefix+name] {
+						out = append(out, &node{typ: declNode, name: prefix + n.name, value: n.value})
+					}
+					continue
+				}
+				if !existing[name+":"+strings.ToLower(prefix+value+important)] {
+					out = append(out, &node{typ: declNode, name: n.name, value: prefix + value + important})
+				}
+			}
+		}
+
+		out = append(out, n)
+	}
+
+	return out
+}
+
+// splitImportant splits the value v into the value and any !important
+// suffix.
+func splitImportant(v string) (string, string) {
+	if i := strings.LastIndex(v, "!"); i != -1 && strings.EqualFold(strings.TrimSpace(v[i+1:]), "important") {
+		return strings.TrimSpace(v[:i]), " " + v[i:]
+	}
+	return v, ""
+}
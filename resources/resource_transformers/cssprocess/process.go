@@ -0,0 +1,398 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cssprocess
+
+import (
+	"strings"
+
+	"github.com/tdewolff/parse/v2"
+	"github.com/tdewolff/parse/v2/css"
+)
+
+// At-rules with nested rules, i.e. selectors, in their block.
+var nestedRulesAtRules = map[string]bool{
+	"@media":         true,
+	"@supports":      true,
+	"@document":      true,
+	"@-moz-document": true,
+	"@layer":         true,
+	"@container":     true,
+}
+
+type nodeType int
+
+const (
+	declNode nodeType = iota
+	ruleNode
+	atRuleNode
+	rawNode
+)
+
+type node struct {
+	typ nodeType
+
+	// The selector list of rules, the prelude of at-rules, or the text of
+	// raw nodes, e.g. comments and @import statements.
+	prelude string
+
+	// The property name and value of declarations.
+	name  string
+	value string
+
+	// The block of at-rules without rules, e.g. @font-face, as is.
+	block    string
+	hasBlock bool
+
+	// The declarations and nested rules of rules, and the rules of at-rules
+	// with nested rules.
+	children []*node
+}
+
+type token struct {
+	tt   css.TokenType
+	data string
+}
+
+// Process lowers the nested rules in the CSS src to plain CSS and adds the
+// vendor prefixes still needed by browsers in wide use, as configured in
+// opts. The result is formatted with one declaration per line.
+func Process(src string, opts Options) string {
+	var tokens []token
+	l := css.NewLexer(parse.NewInputString(src))
+	for {
+		tt, data := l.Next()
+		if tt == css.ErrorToken {
+			break
+		}
+		tokens = append(tokens, token{tt: tt, data: string(data)})
+	}
+
+	p := &parser{tokens: tokens}
+
+	var nodes []*node
+	for {
+		nodes = append(nodes, p.items()...)
+		if p.pos >= len(p.tokens) {
+			break
+		}
+		// An unbalanced right brace, skip it.
+		p.pos++
+	}
+
+	if !opts.NoNesting {
+		nodes = lowerNesting(nodes, nil)
+	}
+
+	if !opts.NoPrefixes {
+		addPrefixes(nodes)
+	}
+
+	var b strings.Builder
+	writeNodes(&b, nodes, 0)
+
+	return b.String()
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// items parses the items up to the end of the current block.
+func (p *parser) items() []*node {
+	var nodes []*node
+
+	for p.pos < len(p.tokens) {
+		t := p.tokens[p.pos]
+
+		switch t.tt {
+		case css.WhitespaceToken, css.SemicolonToken, css.CDOToken, css.CDCToken:
+			p.pos++
+			continue
+		case css.CommentToken:
+			nodes = append(nodes, &node{typ: rawNode, prelude: t.data})
+			p.pos++
+			continue
+		case css.RightBraceToken:
+			return nodes
+		}
+
+		prelude, hasBlock := p.prelude()
+		prelude = strings.TrimSpace(prelude)
+
+		if !hasBlock {
+			if t.tt == css.AtKeywordToken {
+				nodes = append(nodes, &node{typ: rawNode, prelude: prelude + ";"})
+			} else if i := strings.Index(prelude, ":"); i != -1 {
+				nodes = append(nodes, &node{
+					typ:   declNode,
+					name:  strings.TrimSpace(prelude[:i]),
+					value: strings.TrimSpace(prelude[i+1:]),
+				})
+			} else if prelude != "" {
+				// Invalid, keep it as is.
+				nodes = append(nodes, &node{typ: rawNode, prelude: prelude + ";"})
+			}
+			continue
+		}
+
+		// Skip the left brace.
+		p.pos++
+
+		if t.tt == css.AtKeywordToken {
+			if nestedRulesAtRules[strings.ToLower(t.data)] {
+				children := p.items()
+				p.closeBlock()
+				nodes = append(nodes, &node{typ: atRuleNode, prelude: prelude, children: children})
+			} else {
+				nodes = append(nodes, &node{typ: atRuleNode, prelude: prelude, block: p.rawBlock(), hasBlock: true})
+			}
+			continue
+		}
+
+		children := p.items()
+		p.closeBlock()
+		nodes = append(nodes, &node{typ: ruleNode, prelude: collapseWhitespace(prelude), children: children})
+	}
+
+	return nodes
+}
+
+// prelude consumes the tokens up to the next block or semicolon, returning
+// them as a string and whether a block follows. The position is left at
+// the left brace of the block.
+func (p *parser) prelude() (string, bool) {
+	var b strings.Builder
+	depth := 0
+	for p.pos < len(p.tokens) {
+		t := p.tokens[p.pos]
+		switch t.tt {
+		case css.FunctionToken, css.LeftParenthesisToken, css.LeftBracketToken:
+			depth++
+		case css.RightParenthesisToken, css.RightBracketToken:
+			depth--
+		case css.LeftBraceToken:
+			if depth <= 0 {
+				return b.String(), true
+			}
+		case css.SemicolonToken:
+			if depth <= 0 {
+				p.pos++
+				return b.String(), false
+			}
+		case css.RightBraceToken:
+			if depth <= 0 {
+				return b.String(), false
+			}
+		case css.CommentToken:
+			p.pos++
+			continue
+		}
+		b.WriteString(t.data)
+		p.pos++
+	}
+	return b.String(), false
+}
+
+// rawBlock consumes the rest of the current block, including the closing
+// brace, returning its content as is.
+func (p *parser) rawBlock() string {
+	var b strings.Builder
+	depth := 1
+	for p.pos < len(p.tokens) {
+		t := p.tokens[p.pos]
+		p.pos++
+		switch t.tt {
+		case css.LeftBraceToken:
+			depth++
+		case css.RightBraceToken:
+			depth--
+			if depth == 0 {
+				return b.String()
+			}
+		}
+		b.WriteString(t.data)
+	}
+	return b.String()
+}
+
+// closeBlock consumes the right brace closing the current block, if any.
+func (p *parser) closeBlock() {
+	if p.pos < len(p.tokens) && p.tokens[p.pos].tt == css.RightBraceToken {
+		p.pos++
+	}
+}
+
+// lowerNesting moves the rules nested in the rules in nodes to the top
+// level, or to the at-rule they are in, with their selectors resolved
+// against the selectors of their parents.
+func lowerNesting(nodes []*node, parents []string) []*node {
+	var out []*node
+
+	for _, n := range nodes {
+		switch n.typ {
+		case ruleNode:
+			selectors := resolveSelectors(splitList(n.prelude), parents)
+			out = append(out, lowerRule(selectors, n.children)...)
+		case atRuleNode:
+			if n.hasBlock {
+				out = append(out, n)
+				continue
+			}
+			var children []*node
+			if parents != nil {
+				// An at-rule nested in a rule applies to the declarations
+				// of the rule.
+				children = lowerRule(parents, n.children)
+			} else {
+				children = lowerNesting(n.children, nil)
+			}
+			if len(children) > 0 {
+				out = append(out, &node{typ: atRuleNode, prelude: n.prelude, children: children})
+			}
+		default:
+			out = append(out, n)
+		}
+	}
+
+	return out
+}
+
+// lowerRule returns the rule with the given selectors and the declarations
+// in children, followed by the nested rules in children.
+func lowerRule(selectors []string, children []*node) []*node {
+	var (
+		decls  []*node
+		nested []*node
+	)
+
+	for _, c := range children {
+		switch c.typ {
+		case declNode:
+			decls = append(decls, c)
+		case rawNode:
+			if strings.HasPrefix(c.prelude, "/*") {
+				continue
+			}
+			decls = append(decls, c)
+		default:
+			nested = append(nested, c)
+		}
+	}
+
+	var out []*node
+	if len(decls) > 0 {
+		out = append(out, &node{typ: ruleNode, prelude: strings.Join(selectors, ", "), children: decls})
+	}
+
+	return append(out, lowerNesting(nested, selectors)...)
+}
+
+// resolveSelectors resolves the nested selectors against the selectors of
+// their parent rule, if any, replacing the nesting selector, &, with the
+// parent selector, or making the selector a descendant of it.
+func resolveSelectors(selectors, parents []string) []string {
+	if parents == nil {
+		return selectors
+	}
+
+	var resolved []string
+	for _, parent := range parents {
+		for _, sel := range selectors {
+			if !strings.Contains(sel, "&") {
+				resolved = append(resolved, parent+" "+sel)
+				continue
+			}
+			replacement := parent
+			if !strings.HasPrefix(sel, "&") && hasCombinator(parent) {
+				// E.g. ".a &" with parent "b c" must match c elements in b
+				// elements in .a elements, not only c elements in such b.
+				replacement = ":is(" + parent + ")"
+			}
+			resolved = append(resolved, strings.ReplaceAll(sel, "&", replacement))
+		}
+	}
+
+	return resolved
+}
+
+func hasCombinator(s string) bool {
+	return strings.ContainsAny(splitList(s)[0], " >+~")
+}
+
+// splitList splits s on the commas not in parentheses, brackets or
+// strings.
+func splitList(s string) []string {
+	var (
+		parts []string
+		start int
+		depth int
+		quote byte
+	)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '(' || c == '[':
+			depth++
+		case c == ')' || c == ']':
+			depth--
+		case c == ',' && depth == 0:
+			parts = append(parts, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+	}
+	return append(parts, strings.TrimSpace(s[start:]))
+}
+
+func collapseWhitespace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func writeNodes(b *strings.Builder, nodes []*node, depth int) {
+	indent := strings.Repeat("  ", depth)
+
+	for _, n := range nodes {
+		b.WriteString(indent)
+		switch n.typ {
+		case declNode:
+			b.WriteString(n.name)
+			b.WriteString(": ")
+			b.WriteString(n.value)
+			b.WriteString(";\n")
+		case rawNode:
+			b.WriteString(n.prelude)
+			b.WriteString("\n")
+		case ruleNode, atRuleNode:
+			b.WriteString(n.prelude)
+			if n.hasBlock {
+				b.WriteString(" {")
+				b.WriteString(n.block)
+				b.WriteString("}\n")
+				continue
+			}
+			b.WriteString(" {\n")
+			writeNodes(b, n.children, depth+1)
+			b.WriteString(indent)
+			b.WriteString("}\n")
+		}
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cssprocess
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestProcess(t *testing.T) {
+	c := qt.New(t)
+
+	for _, test := range []struct {
+		name   string
+		opts   Options
+		in     string
+		expect string
+	}{
+		{
+			"Nesting",
+			Options{},
+			`.card {
+  color: red;
+  .title { font-weight: bold; }
+  &:hover, &.active { color: blue; }
+  > p { margin: 0 }
+  .dark & { color: white; }
+}`,
+			`.card {
+  color: red;
+}
+.card .title {
+  font-weight: bold;
+}
+.card:hover, .card.active {
+  color: blue;
+}
+.card > p {
+  margin: 0;
+}
+.dark .card {
+  color: white;
+}
+`,
+		},
+		{
+			"Nesting, multiple parents",
+			Options{},
+			`h1, h2 { a, & b { color: red } }`,
+			`h1 a, h1 b, h2 a, h2 b {
+  color: red;
+}
+`,
+		},
+		{
+			"Nesting, parent with combinator",
+			Options{},
+			`nav ul { .dark & { color: white } &.open { display: block } }`,
+			`.dark :is(nav ul) {
+  color: white;
+}
+nav ul.open {
+  display: block;
+}
+`,
+		},
+		{
+			"Nested at-rules",
+			Options{},
+			`.a {
+  color: red;
+  @media (min-width: 600px) {
+    color: blue;
+    .b { color: green }
+  }
+}
+@media print { .c { .d { display: none } } }`,
+			`.a {
+  color: red;
+}
+@media (min-width: 600px) {
+  .a {
+    color: blue;
+  }
+  .a .b {
+    color: green;
+  }
+}
+@media print {
+  .c .d {
+    display: none;
+  }
+}
+`,
+		},
+		{
+			"At-rules kept",
+			Options{},
+			`@charset "utf-8";
+@import url("a.css");
+@font-face { font-family: "F"; src: url(f.woff2); }
+@keyframes spin { from { transform: rotate(0) } to { transform: rotate(360deg) } }
+/* Comment */
+a { color: red }`,
+			`@charset "utf-8";
+@import url("a.css");
+@font-face { font-family: "F"; src: url(f.woff2); }
+@keyframes spin { from { transform: rotate(0) } to { transform: rotate(360deg) } }
+/* Comment */
+a {
+  color: red;
+}
+`,
+		},
+		{
+			"Prefixes",
+			Options{},
+			`.a { user-select: none; -webkit-appearance: none; appearance: none; position: sticky !important; background-clip: text; }`,
+			`.a {
+  -webkit-user-select: none;
+  user-select: none;
+  -webkit-appearance: none;
+  -moz-appearance: none;
+  appearance: none;
+  position: -webkit-sticky !important;
+  position: sticky !important;
+  -webkit-background-clip: text;
+  background-clip: text;
+}
+`,
+		},
+		{
+			"No nesting or prefixes",
+			Options{NoNesting: true, NoPrefixes: true},
+			`.a { user-select: none; .b { color: red } }`,
+			`.a {
+  user-select: none;
+  .b {
+    color: red;
+  }
+}
+`,
+		},
+		{
+			"Empty rules removed",
+			Options{},
+			`.a { } .b { .c { } }`,
+			``,
+		},
+		{
+			"Strings and custom properties",
+			Options{},
+			`:root { --x: 1px; } a[title="a, b"], .b::after { content: "{;}"; width: calc(var(--x) * 2) }`,
+			`:root {
+  --x: 1px;
+}
+a[title="a, b"], .b::after {
+  content: "{;}";
+  width: calc(var(--x) * 2);
+}
+`,
+		},
+	} {
+		test := test
+		c.Run(test.name, func(c *qt.C) {
+			c.Assert(Process(test.in, test.opts), qt.Equals, test.expect)
+		})
+	}
+}
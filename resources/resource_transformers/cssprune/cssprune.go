@@ -0,0 +1,272 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cssprune provides a resource transformer that removes the unused
+// selectors from a stylesheet.
+package cssprune
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/gohugoio/hugo/hugofs"
+	"github.com/gohugoio/hugo/hugofs/files"
+	"github.com/gohugoio/hugo/hugofs/glob"
+	"github.com/gohugoio/hugo/hugolib/filesystems"
+	"github.com/gohugoio/hugo/media"
+	"github.com/gohugoio/hugo/resources"
+	"github.com/gohugoio/hugo/resources/internal"
+	"github.com/gohugoio/hugo/resources/resource"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// The build stats written when build.writeStats is enabled.
+const statsFilename = "hugo_stats.json"
+
+// Options configures what is considered used when pruning.
+type Options struct {
+	// Glob patterns matching files, relative to the working dir, to look
+	// for used names in, e.g. "layouts/**.html". Any word in these files
+	// may be a tag, class name or ID.
+	Content []string
+
+	// Regular expressions matching tags, class names and IDs to always keep,
+	// e.g. for class names added by scripts.
+	Safelist []string
+
+	// Set to not use the build stats, hugo_stats.json.
+	NoStats bool
+}
+
+// DecodeOptions decodes options from the given map.
+func DecodeOptions(m map[string]interface{}) (opts Options, err error) {
+	if m == nil {
+		return
+	}
+
+	err = mapstructure.WeakDecode(m, &opts)
+
+	return
+}
+
+// Client is the client used to prune stylesheets.
+type Client struct {
+	rs *resources.Spec
+}
+
+// New creates a new Client with the given specification.
+func New(rs *resources.Spec) *Client {
+	return &Client{rs: rs}
+}
+
+type pruneTransformation struct {
+	options Options
+	c       *Client
+}
+
+func (t *pruneTransformation) Key() internal.ResourceTransformationKey {
+	return internal.NewResourceTransformationKey("cssprune", t.options)
+}
+
+func (t *pruneTransformation) Transform(ctx *resources.ResourceTransformationCtx) error {
+	if ctx.InMediaType.SubType != media.CSSType.SubType {
+		return errors.Errorf("%s is not a stylesheet", ctx.InPath)
+	}
+
+	used, err := t.c.collectUsed(t.options)
+	if err != nil {
+		return err
+	}
+
+	src, err := ioutil.ReadAll(ctx.From)
+	if err != nil {
+		return err
+	}
+
+	_, err = ctx.To.Write([]byte(Prune(string(src), used)))
+
+	return err
+}
+
+// collectUsed collects the names used in the build stats and the content
+// files.
+func (c *Client) collectUsed(opts Options) (*Used, error) {
+	used := NewUsed()
+
+	for _, s := range opts.Safelist {
+		re, err := regexp.Compile(s)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid safelist pattern %q", s)
+		}
+		used.Safelist = append(used.Safelist, re)
+	}
+
+	var found bool
+
+	if !opts.NoStats {
+		ok, err := c.addStats(used)
+		if err != nil {
+			return nil, err
+		}
+		found = ok
+	}
+
+	for _, pattern := range opts.Content {
+		ok, err := c.addContent(used, pattern)
+		if err != nil {
+			return nil, err
+		}
+		found = found || ok
+	}
+
+	if !found {
+		return nil, errors.New("no HTML to look for used selectors in; enable build.writeStats and use resources.PostProcess, or set content")
+	}
+
+	return used, nil
+}
+
+// addStats adds the names in the build stats to used, returning false if
+// there are no stats.
+func (c *Client) addStats(used *Used) (bool, error) {
+	b, err := afero.ReadFile(hugofs.Os, filepath.Join(c.rs.WorkingDir, statsFilename))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	var stats struct {
+		HTMLElements struct {
+			Tags    []string `json:"tags"`
+			Classes []string `json:"classes"`
+			IDs     []string `json:"ids"`
+		} `json:"htmlElements"`
+	}
+
+	if err := json.Unmarshal(b, &stats); err != nil {
+		return false, errors.Wrapf(err, "failed to decode %s", statsFilename)
+	}
+
+	for _, s := range stats.HTMLElements.Tags {
+		used.Tags[s] = true
+	}
+	for _, s := range stats.HTMLElements.Classes {
+		used.Classes[s] = true
+	}
+	for _, s := range stats.HTMLElements.IDs {
+		used.IDs[s] = true
+	}
+
+	return true, nil
+}
+
+var (
+	contentSplitRe = regexp.MustCompile("[\\s<>\"'`=]+")
+	contentWordRe  = regexp.MustCompile(`[A-Za-z0-9_-]+`)
+)
+
+// addContent adds the words in the files matching pattern to used,
+// returning false if no files matched.
+func (c *Client) addContent(used *Used, pattern string) (bool, error) {
+	pattern = glob.NormalizePath(pattern)
+
+	// Look in the component filesystems, e.g. layouts, if possible, as
+	// they include the themes and modules.
+	fs := c.rs.BaseFs.Work
+	if i := strings.Index(pattern, "/"); i != -1 {
+		if sfs := c.componentFs(pattern[:i]); sfs != nil {
+			fs = sfs
+			pattern = pattern[i+1:]
+		}
+	}
+
+	g, err := glob.GetGlob(pattern)
+	if err != nil {
+		return false, err
+	}
+
+	root := glob.ResolveRootDir(pattern)
+
+	var found bool
+
+	err = afero.Walk(fs, filepath.FromSlash(root), func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info.IsDir() || !g.Match(glob.NormalizePath(path)) {
+			return nil
+		}
+
+		b, err := afero.ReadFile(fs, path)
+		if err != nil {
+			return err
+		}
+
+		found = true
+
+		for _, s := range contentSplitRe.Split(string(b), -1) {
+			if s == "" {
+				continue
+			}
+			used.AddWord(s)
+			for _, w := range contentWordRe.FindAllString(s, -1) {
+				used.AddWord(w)
+			}
+		}
+
+		return nil
+	})
+
+	return found, err
+}
+
+func (c *Client) componentFs(component string) afero.Fs {
+	var sfs *filesystems.SourceFilesystem
+	switch component {
+	case files.ComponentFolderLayouts:
+		sfs = c.rs.BaseFs.Layouts
+	case files.ComponentFolderContent:
+		sfs = c.rs.BaseFs.Content
+	case files.ComponentFolderAssets:
+		sfs = c.rs.BaseFs.Assets
+	case files.ComponentFolderData:
+		sfs = c.rs.BaseFs.Data
+	case files.ComponentFolderI18n:
+		sfs = c.rs.BaseFs.I18n
+	}
+	if sfs == nil {
+		return nil
+	}
+	return sfs.Fs
+}
+
+// Prune removes the unused selectors from the given stylesheet, see Prune.
+//
+// The names used are read from the build stats written when
+// build.writeStats is enabled, which are complete when the site is built,
+// so use it with resources.PostProcess, and from any content files set
+// in the options.
+func (c *Client) Prune(res resources.ResourceTransformer, options Options) (resource.Resource, error) {
+	return res.Transform(&pruneTransformation{c: c, options: options})
+}
@@ -0,0 +1,426 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cssprune
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/tdewolff/parse/v2"
+	"github.com/tdewolff/parse/v2/css"
+)
+
+// At-rules with nested rules, i.e. selectors, in their block.
+var nestedRulesAtRules = map[string]bool{
+	"@media":         true,
+	"@supports":      true,
+	"@document":      true,
+	"@-moz-document": true,
+	"@layer":         true,
+	"@container":     true,
+}
+
+// Elements that are always considered used, as they may not be in the
+// HTML source, e.g. html and body in some templates.
+var alwaysUsedTags = map[string]bool{
+	"html": true,
+	"body": true,
+}
+
+// Used holds the names used in the HTML, the selectors matching any of
+// them are kept when pruning.
+type Used struct {
+	Tags    map[string]bool
+	Classes map[string]bool
+	IDs     map[string]bool
+
+	// The class names, IDs and tags matching any of these are always
+	// considered used.
+	Safelist []*regexp.Regexp
+}
+
+// NewUsed creates a new, empty, Used.
+func NewUsed() *Used {
+	return &Used{
+		Tags:    make(map[string]bool),
+		Classes: make(map[string]bool),
+		IDs:     make(map[string]bool),
+	}
+}
+
+// AddWord adds a word found in a content file, which may be a tag, a class
+// name or an ID.
+func (u *Used) AddWord(s string) {
+	u.Tags[strings.ToLower(s)] = true
+	u.Classes[s] = true
+	u.IDs[s] = true
+}
+
+func (u *Used) safelisted(s string) bool {
+	for _, re := range u.Safelist {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+func (u *Used) hasTag(s string) bool {
+	s = strings.ToLower(s)
+	return alwaysUsedTags[s] || u.Tags[s] || u.safelisted(s)
+}
+
+func (u *Used) hasClass(s string) bool {
+	return u.Classes[s] || u.safelisted(s)
+}
+
+func (u *Used) hasID(s string) bool {
+	return u.IDs[s] || u.safelisted(s)
+}
+
+type token struct {
+	tt   css.TokenType
+	data string
+}
+
+// Prune removes the selectors not matching any element in used from the
+// CSS src, and the rules left without selectors.
+//
+// A selector is kept if all of its type, class and ID selectors are used;
+// attribute selectors, pseudo-classes and pseudo-elements are ignored.
+// At-rules with nested rules, e.g. @media, are removed if all of their
+// rules are. Other at-rules, e.g. @font-face and @keyframes, are kept.
+func Prune(src string, used *Used) string {
+	var tokens []token
+	l := css.NewLexer(parse.NewInputString(src))
+	for {
+		tt, data := l.Next()
+		if tt == css.ErrorToken {
+			break
+		}
+		tokens = append(tokens, token{tt: tt, data: string(data)})
+	}
+
+	p := &pruner{tokens: tokens, used: used}
+
+	var b strings.Builder
+	for {
+		p.rules(&b)
+		if p.pos >= len(p.tokens) {
+			break
+		}
+		// An unbalanced right brace, keep it.
+		b.WriteString(p.tokens[p.pos].data)
+		p.pos++
+	}
+
+	return b.String()
+}
+
+type pruner struct {
+	tokens []token
+	pos    int
+	used   *Used
+}
+
+// rules writes the rules up to the end of the current block to b, and
+// returns whether any rules were written.
+func (p *pruner) rules(b *strings.Builder) bool {
+	var hasRules bool
+
+	for p.pos < len(p.tokens) {
+		t := p.tokens[p.pos]
+
+		switch t.tt {
+		case css.WhitespaceToken, css.CommentToken, css.CDOToken, css.CDCToken, css.SemicolonToken:
+			b.WriteString(t.data)
+			p.pos++
+		case css.RightBraceToken:
+			return hasRules
+		default:
+			var written bool
+			if t.tt == css.AtKeywordToken {
+				written = p.atRule(b)
+			} else {
+				written = p.rule(b)
+			}
+			if written {
+				hasRules = true
+			} else if p.pos < len(p.tokens) && p.tokens[p.pos].tt == css.WhitespaceToken {
+				// Skip the whitespace following the removed rule.
+				p.pos++
+			}
+		}
+	}
+
+	return hasRules
+}
+
+// atRule writes the at-rule at the current position to b, unless it only
+// contains unused rules, and returns whether it was written.
+func (p *pruner) atRule(b *strings.Builder) bool {
+	name := strings.ToLower(p.tokens[p.pos].data)
+	prelude, hasBlock := p.prelude()
+
+	if !hasBlock {
+		b.WriteString(prelude)
+		return true
+	}
+
+	if !nestedRulesAtRules[name] {
+		b.WriteString(prelude)
+		b.WriteString(p.block())
+		return true
+	}
+
+	// Skip the left brace.
+	p.pos++
+	var inner strings.Builder
+	hasRules := p.rules(&inner)
+	end := p.closeBlock()
+
+	if !hasRules {
+		return false
+	}
+
+	b.WriteString(prelude)
+	b.WriteString("{")
+	b.WriteString(inner.String())
+	b.WriteString(end)
+
+	return true
+}
+
+// rule writes the qualified rule at the current position to b, with the
+// unused selectors removed, and returns whether it was written.
+func (p *pruner) rule(b *strings.Builder) bool {
+	start := p.pos
+	prelude, hasBlock := p.prelude()
+
+	if !hasBlock {
+		// Invalid CSS, keep it as is.
+		b.WriteString(prelude)
+		return true
+	}
+
+	selectors := splitSelectors(p.tokens[start:p.pos])
+
+	block := p.block()
+
+	var kept []string
+	for _, sel := range selectors {
+		if p.isUsed(sel) {
+			kept = append(kept, selectorString(sel))
+		}
+	}
+
+	if len(kept) == 0 {
+		return false
+	}
+
+	if len(kept) == len(selectors) {
+		// Keep the original formatting.
+		b.WriteString(prelude)
+	} else {
+		b.WriteString(strings.Join(kept, ", "))
+		b.WriteString(" ")
+	}
+
+	b.WriteString(block)
+
+	return true
+}
+
+// prelude consumes the tokens up to the next block or semicolon, returning
+// them as a string and whether a block follows. The position is left at
+// the left brace of the block.
+func (p *pruner) prelude() (string, bool) {
+	var b strings.Builder
+	depth := 0
+	for p.pos < len(p.tokens) {
+		t := p.tokens[p.pos]
+		switch t.tt {
+		case css.FunctionToken, css.LeftParenthesisToken, css.LeftBracketToken:
+			depth++
+		case css.RightParenthesisToken, css.RightBracketToken:
+			depth--
+		case css.LeftBraceToken:
+			if depth <= 0 {
+				return b.String(), true
+			}
+		case css.SemicolonToken:
+			if depth <= 0 {
+				b.WriteString(t.data)
+				p.pos++
+				return b.String(), false
+			}
+		case css.RightBraceToken:
+			if depth <= 0 {
+				return b.String(), false
+			}
+		}
+		b.WriteString(t.data)
+		p.pos++
+	}
+	return b.String(), false
+}
+
+// block consumes the block at the current position, returning it as is.
+func (p *pruner) block() string {
+	var b strings.Builder
+	depth := 0
+	for p.pos < len(p.tokens) {
+		t := p.tokens[p.pos]
+		b.WriteString(t.data)
+		p.pos++
+		switch t.tt {
+		case css.LeftBraceToken:
+			depth++
+		case css.RightBraceToken:
+			depth--
+			if depth == 0 {
+				return b.String()
+			}
+		}
+	}
+	return b.String()
+}
+
+// closeBlock consumes the right brace closing the current block, if any.
+func (p *pruner) closeBlock() string {
+	if p.pos < len(p.tokens) && p.tokens[p.pos].tt == css.RightBraceToken {
+		p.pos++
+		return "}"
+	}
+	return ""
+}
+
+// splitSelectors splits the selector list in prelude on the top level
+// commas.
+func splitSelectors(prelude []token) [][]token {
+	var (
+		selectors [][]token
+		current   []token
+		depth     int
+	)
+	for _, t := range prelude {
+		switch t.tt {
+		case css.FunctionToken, css.LeftParenthesisToken, css.LeftBracketToken:
+			depth++
+		case css.RightParenthesisToken, css.RightBracketToken:
+			depth--
+		case css.CommaToken:
+			if depth == 0 {
+				selectors = append(selectors, current)
+				current = nil
+				continue
+			}
+		}
+		current = append(current, t)
+	}
+	return append(selectors, current)
+}
+
+func selectorString(sel []token) string {
+	var b strings.Builder
+	for _, t := range sel {
+		b.WriteString(t.data)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// isUsed reports whether all the type, class and ID selectors in sel are
+// used.
+func (p *pruner) isUsed(sel []token) bool {
+	depth := 0
+	for i := 0; i < len(sel); i++ {
+		t := sel[i]
+		switch t.tt {
+		case css.FunctionToken, css.LeftParenthesisToken, css.LeftBracketToken:
+			depth++
+			continue
+		case css.RightParenthesisToken, css.RightBracketToken:
+			depth--
+			continue
+		}
+
+		if depth > 0 {
+			// Pseudo-class arguments, e.g. :not(.a), and attribute selectors.
+			continue
+		}
+
+		switch t.tt {
+		case css.ColonToken:
+			// Skip the name of pseudo-classes and pseudo-elements.
+			for i+1 < len(sel) && sel[i+1].tt == css.ColonToken {
+				i++
+			}
+			if i+1 < len(sel) && sel[i+1].tt == css.IdentToken {
+				i++
+			}
+		case css.DelimToken:
+			if t.data == "." && i+1 < len(sel) && sel[i+1].tt == css.IdentToken {
+				i++
+				if !p.used.hasClass(unescape(sel[i].data)) {
+					return false
+				}
+			}
+		case css.HashToken:
+			if !p.used.hasID(unescape(t.data[1:])) {
+				return false
+			}
+		case css.IdentToken:
+			if !p.used.hasTag(unescape(t.data)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// unescape removes the CSS escapes in the identifier s, e.g. the
+// backslash in "sm\:flex".
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		j := i
+		for j < len(s) && j-i < 6 && isHex(s[j]) {
+			j++
+		}
+		if j == i {
+			b.WriteByte(s[i])
+			continue
+		}
+		r, _ := strconv.ParseUint(s[i:j], 16, 32)
+		b.WriteRune(rune(r))
+		i = j - 1
+		if j < len(s) && s[j] == ' ' {
+			i++
+		}
+	}
+	return b.String()
+}
+
+func isHex(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
@@ -0,0 +1,88 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cssprune
+
+import (
+	"regexp"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestPrune(t *testing.T) {
+	c := qt.New(t)
+
+	used := NewUsed()
+	for _, s := range []string{"div", "a", "ul", "li"} {
+		used.Tags[s] = true
+	}
+	for _, s := range []string{"btn", "primary", "sm:flex", "nav"} {
+		used.Classes[s] = true
+	}
+	used.IDs["main"] = true
+	used.Safelist = []*regexp.Regexp{regexp.MustCompile(`^js-`)}
+
+	css := Prune(`@charset "utf-8";
+@import url("base.css");
+/* Buttons */
+.btn { color: red }
+.btn.secondary { color: blue }
+.unused, .btn.primary:hover, a > .missing { color: green }
+div:not(.unused)::before, [data-x="a,b"] { content: "," }
+#main, #other { margin: 0 }
+table td { padding: 0 }
+.sm\:flex { display: flex }
+.js-toggle { cursor: pointer }
+* { box-sizing: border-box }
+html, body { margin: 0 }
+@media (min-width: 600px) {
+  .unused { display: none }
+  .nav li { display: inline }
+}
+@media print {
+  .unused { display: none }
+}
+@supports (display: grid) { @media screen { .nav { display: grid } .unused { color: red } } }
+@font-face { font-family: "A"; src: url(a.woff) }
+@keyframes fade { from { opacity: 0 } to { opacity: 1 } }
+`, used)
+
+	c.Assert(css, qt.Equals, `@charset "utf-8";
+@import url("base.css");
+/* Buttons */
+.btn { color: red }
+.btn.primary:hover { color: green }
+div:not(.unused)::before, [data-x="a,b"] { content: "," }
+#main { margin: 0 }
+.sm\:flex { display: flex }
+.js-toggle { cursor: pointer }
+* { box-sizing: border-box }
+html, body { margin: 0 }
+@media (min-width: 600px) {
+  .nav li { display: inline }
+}
+@supports (display: grid) { @media screen { .nav { display: grid } } }
+@font-face { font-family: "A"; src: url(a.woff) }
+@keyframes fade { from { opacity: 0 } to { opacity: 1 } }
+`)
+}
+
+func TestUnescape(t *testing.T) {
+	c := qt.New(t)
+
+	c.Assert(unescape(`sm\:flex`), qt.Equals, "sm:flex")
+	c.Assert(unescape(`w-1\/2`), qt.Equals, "w-1/2")
+	c.Assert(unescape(`\31 0`), qt.Equals, "10")
+	c.Assert(unescape(`plain`), qt.Equals, "plain")
+}
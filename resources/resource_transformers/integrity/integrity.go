@@ -14,6 +14,7 @@
 package integrity
 
 import (
+	"bytes"
 	"crypto/md5"
 	"crypto/sha256"
 	"crypto/sha512"
@@ -22,6 +23,7 @@ import (
 	"hash"
 	"html/template"
 	"io"
+	"io/ioutil"
 
 	"github.com/gohugoio/hugo/resources/internal"
 
@@ -60,6 +62,10 @@ func (t *fingerprintTransformation) Transform(ctx *resources.ResourceTransformat
 		return err
 	}
 
+	if ctx.SourceMap != nil {
+		return t.transformSourceMapped(ctx, h)
+	}
+
 	var w io.Writer
 	if rc, ok := ctx.From.(io.ReadSeeker); ok {
 		// This transformation does not change the content, so try to
@@ -81,6 +87,45 @@ func (t *fingerprintTransformation) Transform(ctx *resources.ResourceTransformat
 	return nil
 }
 
+// transformSourceMapped fingerprints a Resource with an external source map.
+// The reference to the source map is not part of the fingerprint, and is
+// updated to the fingerprinted name, which the source map is published with.
+// The integrity hash is created from the updated content.
+func (t *fingerprintTransformation) transformSourceMapped(ctx *resources.ResourceTransformationCtx, h hash.Hash) error {
+	b, err := ioutil.ReadAll(ctx.From)
+	if err != nil {
+		return err
+	}
+
+	content, _ := resources.ExtractSourceMap(b)
+	h.Write(content)
+	d, err := digest(h)
+	if err != nil {
+		return err
+	}
+	ctx.AddOutPathIdentifier("." + hex.EncodeToString(d[:]))
+
+	var buf bytes.Buffer
+	to := ctx.To
+	ctx.To = &buf
+	err = ctx.WriteSourceMapped(content, ctx.SourceMap, resources.SourceMapOptions{SourceMap: resources.SourceMapExternal})
+	ctx.To = to
+	if err != nil {
+		return err
+	}
+
+	h, _ = newHash(t.algo)
+	h.Write(buf.Bytes())
+	d, err = digest(h)
+	if err != nil {
+		return err
+	}
+	ctx.Data["Integrity"] = integrity(t.algo, d)
+
+	_, err = ctx.To.Write(buf.Bytes())
+	return err
+}
+
 func newHash(algo string) (hash.Hash, error) {
 	switch algo {
 	case "md5":
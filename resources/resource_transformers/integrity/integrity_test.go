@@ -14,10 +14,18 @@
 package integrity
 
 import (
+	"crypto/sha256"
+	"encoding/base64"
 	"html/template"
+	"io/ioutil"
+	"path/filepath"
 	"testing"
 
+	"github.com/gohugoio/hugo/media"
+	"github.com/gohugoio/hugo/resources"
+	"github.com/gohugoio/hugo/resources/internal"
 	"github.com/gohugoio/hugo/resources/resource"
+	"github.com/spf13/afero"
 
 	qt "github.com/frankban/quicktest"
 	"github.com/gohugoio/hugo/resources/resource_transformers/htesting"
@@ -67,3 +75,49 @@ func TestTransform(t *testing.T) {
 	c.Assert(err, qt.IsNil)
 	c.Assert(content, qt.Equals, "Hugo Rocks!")
 }
+
+type sourceMapTransformation struct{}
+
+func (t sourceMapTransformation) Key() internal.ResourceTransformationKey {
+	return internal.NewResourceTransformationKey("sourcemap")
+}
+
+func (t sourceMapTransformation) Transform(ctx *resources.ResourceTransformationCtx) error {
+	b, err := ioutil.ReadAll(ctx.From)
+	if err != nil {
+		return err
+	}
+	ctx.OutMediaType = media.JavascriptType
+	ctx.ReplaceOutPathExtension(".js")
+	return ctx.WriteSourceMapped(b, []byte(`{"version":3}`), resources.SourceMapOptions{SourceMap: "external"})
+}
+
+func TestTransformSourceMap(t *testing.T) {
+	c := qt.New(t)
+
+	spec, err := htesting.NewTestResourceSpec()
+	c.Assert(err, qt.IsNil)
+	client := New(spec)
+
+	r, err := htesting.NewResourceTransformerForSpec(spec, "hugo.txt", "Hugo Rocks!")
+	c.Assert(err, qt.IsNil)
+
+	r, err = r.Transform(sourceMapTransformation{})
+	c.Assert(err, qt.IsNil)
+
+	transformed, err := client.Fingerprint(r, "")
+	c.Assert(err, qt.IsNil)
+
+	// The source map reference is not part of the fingerprint.
+	c.Assert(transformed.RelPermalink(), qt.Equals, "/hugo.facf680697bdd986bfc64fdfe2c85c2dc3401e3905593bba847a70e1d7329df1.js")
+	content, err := transformed.(resource.ContentProvider).Content()
+	c.Assert(err, qt.IsNil)
+	c.Assert(content, qt.Equals, "Hugo Rocks!\n//# sourceMappingURL=hugo.facf680697bdd986bfc64fdfe2c85c2dc3401e3905593bba847a70e1d7329df1.js.map\n")
+
+	sum := sha256.Sum256([]byte(content.(string)))
+	c.Assert(transformed.Data(), qt.DeepEquals, map[string]interface{}{"Integrity": template.HTMLAttr("sha256-" + base64.StdEncoding.EncodeToString(sum[:]))})
+
+	exists, err := afero.Exists(spec.Fs.Destination, filepath.FromSlash("public/hugo.facf680697bdd986bfc64fdfe2c85c2dc3401e3905593bba847a70e1d7329df1.js.map"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(exists, qt.Equals, true)
+}
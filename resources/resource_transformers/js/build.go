@@ -14,13 +14,14 @@
 package js
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path"
 	"path/filepath"
-	"regexp"
 	"strings"
+	"sync"
 
 	"github.com/pkg/errors"
 
@@ -43,14 +44,64 @@ import (
 type Client struct {
 	rs  *resources.Spec
 	sfs *filesystems.SourceFilesystem
+
+	// Maps a bundle to its last incremental build.
+	buildsMu sync.Mutex
+	builds   map[string]*incrementalBuild
+}
+
+// incrementalBuild is an ESBuild build that can be rebuilt when any of its
+// imports change, as long as the entry point stays the same.
+type incrementalBuild struct {
+	contents string
+	result   api.BuildResult
 }
 
-// New creates a new client context.
-func New(fs *filesystems.SourceFilesystem, rs *resources.Spec) *Client {
-	return &Client{
+// New creates a new client context. If incremental is set, e.g. in server
+// mode, the builds are kept in memory to speed up any rebuilds.
+func New(fs *filesystems.SourceFilesystem, rs *resources.Spec, incremental bool) *Client {
+	c := &Client{
 		rs:  rs,
 		sfs: fs,
 	}
+	if incremental {
+		c.builds = make(map[string]*incrementalBuild)
+	}
+	return c
+}
+
+// build runs the build described by buildOptions. In incremental mode, the
+// previous build of the bundle identified by key is rebuilt if its entry
+// point is unchanged.
+func (c *Client) build(key string, buildOptions api.BuildOptions) api.BuildResult {
+	if c.builds == nil {
+		return api.Build(buildOptions)
+	}
+
+	c.buildsMu.Lock()
+	prev := c.builds[key]
+	c.buildsMu.Unlock()
+
+	if prev != nil && prev.contents == buildOptions.Stdin.Contents {
+		result := prev.result.Rebuild()
+		if len(result.Errors) == 0 {
+			return result
+		}
+		// Start over, the errors may be caused by stale state.
+	}
+
+	buildOptions.Incremental = true
+	result := api.Build(buildOptions)
+
+	c.buildsMu.Lock()
+	if len(result.Errors) == 0 {
+		c.builds[key] = &incrementalBuild{contents: buildOptions.Stdin.Contents, result: result}
+	} else {
+		delete(c.builds, key)
+	}
+	c.buildsMu.Unlock()
+
+	return result
 }
 
 type buildTransformation struct {
@@ -85,6 +136,7 @@ func (t *buildTransformation) Transform(ctx *resources.ResourceTransformationCtx
 	opts.resolveDir = t.c.rs.WorkingDir // where node_modules gets resolved
 	opts.contents = string(src)
 	opts.mediaType = ctx.InMediaType
+	opts.SourceMap = opts.SourceMapOptions.Type(t.c.rs.Cfg)
 
 	buildOptions, err := toBuildOptions(opts)
 	if err != nil {
@@ -126,7 +178,12 @@ func (t *buildTransformation) Transform(ctx *resources.ResourceTransformationCtx
 
 	}
 
-	result := api.Build(buildOptions)
+	writeAssetReport := t.c.rs.BuildConfig.WriteAssetReport
+	if writeAssetReport {
+		buildOptions.Metafile = true
+	}
+
+	result := t.c.build(ctx.SourcePath+"|"+t.Key().Value(), buildOptions)
 
 	if len(result.Errors) > 0 {
 
@@ -182,28 +239,78 @@ func (t *buildTransformation) Transform(ctx *resources.ResourceTransformationCtx
 		return errors[0]
 	}
 
+	if writeAssetReport {
+		if err := t.c.addToAssetReport(ctx, result.Metafile); err != nil {
+			return err
+		}
+	}
+
+	var content, sourceMap []byte
 	if buildOptions.Sourcemap == api.SourceMapExternal {
-		content := string(result.OutputFiles[1].Contents)
-		symPath := path.Base(ctx.OutPath) + ".map"
-		re := regexp.MustCompile(`//# sourceMappingURL=.*\n?`)
-		content = re.ReplaceAllString(content, "//# sourceMappingURL="+symPath+"\n")
+		content, sourceMap = result.OutputFiles[1].Contents, result.OutputFiles[0].Contents
+	} else {
+		content, sourceMap = resources.ExtractSourceMap(result.OutputFiles[0].Contents)
+	}
 
-		if err = ctx.PublishSourceMap(string(result.OutputFiles[0].Contents)); err != nil {
-			return err
+	return ctx.WriteSourceMapped(content, sourceMap, opts.SourceMapOptions)
+}
+
+// metafile is the part of the ESBuild metafile used in the asset report.
+type metafile struct {
+	Outputs map[string]struct {
+		Bytes  int
+		Inputs map[string]struct {
+			BytesInOutput int `json:"bytesInOutput"`
 		}
-		_, err := ctx.To.Write([]byte(content))
-		if err != nil {
-			return err
+	}
+}
+
+// addToAssetReport adds the composition of the bundle built in ctx, as
+// described by the ESBuild metafile m, to the asset report.
+func (c *Client) addToAssetReport(ctx *resources.ResourceTransformationCtx, m string) error {
+	var meta metafile
+	if err := json.Unmarshal([]byte(m), &meta); err != nil {
+		return errors.Wrap(err, "failed to decode metafile")
+	}
+
+	bundle := resources.AssetReportBundle{
+		EntryPoint: ctx.SourcePath,
+		Target:     ctx.OutPath,
+	}
+
+	for name, out := range meta.Outputs {
+		if strings.HasSuffix(name, ".map") {
+			continue
 		}
-	} else {
-		_, err := ctx.To.Write(result.OutputFiles[0].Contents)
-		if err != nil {
-			return err
+		bundle.Bytes += out.Bytes
+		for filename, in := range out.Inputs {
+			bundle.Inputs = append(bundle.Inputs, resources.AssetReportInput{
+				Path:  c.assetReportPath(ctx, filename),
+				Bytes: in.BytesInOutput,
+			})
 		}
 	}
+
+	c.rs.PostBuildAssets.AssetReport.Add(bundle)
+
 	return nil
 }
 
+// assetReportPath returns the path of the bundle input filename, relative
+// to the working dir if possible.
+func (c *Client) assetReportPath(ctx *resources.ResourceTransformationCtx, filename string) string {
+	if filename == "<stdin>" {
+		return ctx.SourcePath
+	}
+	filename = strings.TrimPrefix(filename, nsImportHugo+":")
+	if filepath.IsAbs(filename) && c.rs.WorkingDir != "" {
+		if rel, err := filepath.Rel(c.rs.WorkingDir, filename); err == nil && !strings.HasPrefix(rel, "..") {
+			filename = rel
+		}
+	}
+	return filepath.ToSlash(filename)
+}
+
 // Process process esbuild transform
 func (c *Client) Process(res resources.ResourceTransformer, opts map[string]interface{}) (resource.Resource, error) {
 	return res.Transform(
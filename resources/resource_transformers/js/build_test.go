@@ -12,3 +12,50 @@
 // limitations under the License.
 
 package js
+
+import (
+	"testing"
+
+	"github.com/evanw/esbuild/pkg/api"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestIncrementalBuild(t *testing.T) {
+	c := qt.New(t)
+
+	buildOptions := func(contents string) api.BuildOptions {
+		return api.BuildOptions{
+			Bundle: true,
+			Stdin:  &api.StdinOptions{Contents: contents},
+		}
+	}
+
+	client := New(nil, nil, true)
+
+	result := client.build("main", buildOptions("console.log(1)"))
+	c.Assert(result.Errors, qt.HasLen, 0)
+	c.Assert(string(result.OutputFiles[0].Contents), qt.Contains, "console.log(1)")
+	first := client.builds["main"]
+	c.Assert(first, qt.Not(qt.IsNil))
+
+	// Same entry point, rebuilt.
+	result = client.build("main", buildOptions("console.log(1)"))
+	c.Assert(result.Errors, qt.HasLen, 0)
+	c.Assert(client.builds["main"], qt.Equals, first)
+
+	// New entry point, new build.
+	result = client.build("main", buildOptions("console.log(2)"))
+	c.Assert(string(result.OutputFiles[0].Contents), qt.Contains, "console.log(2)")
+	c.Assert(client.builds["main"], qt.Not(qt.Equals), first)
+
+	// Failed builds are not kept.
+	result = client.build("main", buildOptions("console.log("))
+	c.Assert(result.Errors, qt.Not(qt.HasLen), 0)
+	c.Assert(client.builds["main"], qt.IsNil)
+
+	client = New(nil, nil, false)
+	result = client.build("main", buildOptions("console.log(1)"))
+	c.Assert(result.Errors, qt.HasLen, 0)
+	c.Assert(client.builds, qt.IsNil)
+}
@@ -29,6 +29,8 @@ import (
 	"github.com/gohugoio/hugo/helpers"
 	"github.com/gohugoio/hugo/hugofs"
 	"github.com/gohugoio/hugo/media"
+	"github.com/gohugoio/hugo/resources"
+	"github.com/gohugoio/hugo/resources/resource_transformers/cssmodules"
 	"github.com/mitchellh/mapstructure"
 )
 
@@ -49,8 +51,8 @@ type Options struct {
 	// Whether to minify to output.
 	Minify bool
 
-	// Whether to write mapfiles
-	SourceMap string
+	// Source map options, see resources.SourceMapOptions.
+	resources.SourceMapOptions `mapstructure:",squash"`
 
 	// The language target.
 	// One of: es2015, es2016, es2017, es2018, es2019, es2020 or esnext.
@@ -86,6 +88,12 @@ type Options struct {
 	// What to use instead of React.Fragment.
 	JSXFragment string
 
+	// The pattern for the scoped class names in imported CSS modules, i.e.
+	// *.module.css files. This must match the pattern used in any
+	// resources.CSSModules for the same files.
+	// Default is "[name]_[local]_[hash]".
+	CSSModulesLocalIdentName string
+
 	// There is/was a bug in WebKit with severe performance issue with the tracking
 	// of TDZ checks in JavaScriptCore.
 	//
@@ -239,6 +247,18 @@ func createBuildPlugins(c *Client, opts Options) ([]api.Plugin, error) {
 						return api.OnLoadResult{}, errors.Wrapf(err, "failed to read %q", args.Path)
 					}
 					c := string(b)
+					if cssmodules.IsModule(args.Path) {
+						rel, _ := fs.MakePathRelative(args.Path)
+						c, err = cssModuleToJS(c, filepath.ToSlash(rel), opts.CSSModulesLocalIdentName)
+						if err != nil {
+							return api.OnLoadResult{}, errors.Wrapf(err, "failed to load CSS module %q", args.Path)
+						}
+						return api.OnLoadResult{
+							ResolveDir: opts.resolveDir,
+							Contents:   &c,
+							Loader:     api.LoaderJS,
+						}, nil
+					}
 					return api.OnLoadResult{
 						// See https://github.com/evanw/esbuild/issues/502
 						// This allows all modules to resolve dependencies
@@ -285,6 +305,31 @@ func createBuildPlugins(c *Client, opts Options) ([]api.Plugin, error) {
 	return []api.Plugin{importResolver, paramsPlugin}, nil
 }
 
+// cssModuleToJS creates a JS module from the CSS module src, which injects
+// the scoped CSS into the document and exports the mapping from the
+// original to the scoped class names as its default export.
+func cssModuleToJS(src, filename, localIdentName string) (string, error) {
+	css, classes := cssmodules.Scope(src, filename, localIdentName)
+
+	cssJSON, err := json.Marshal(css)
+	if err != nil {
+		return "", err
+	}
+	classesJSON, err := json.Marshal(classes)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(`const css = %s;
+if (typeof document !== "undefined") {
+  const style = document.createElement("style");
+  style.textContent = css;
+  document.head.appendChild(style);
+}
+export default %s;
+`, cssJSON, classesJSON), nil
+}
+
 func toBuildOptions(opts Options) (buildOptions api.BuildOptions, err error) {
 	var target api.Target
 	switch opts.Target {
@@ -355,9 +400,9 @@ func toBuildOptions(opts Options) (buildOptions api.BuildOptions, err error) {
 	outFile := ""
 	var sourceMap api.SourceMap
 	switch opts.SourceMap {
-	case "inline":
+	case resources.SourceMapInline:
 		sourceMap = api.SourceMapInline
-	case "external":
+	case resources.SourceMapExternal:
 		sourceMap = api.SourceMapExternal
 	case "":
 		sourceMap = api.SourceMapNone
@@ -365,14 +410,19 @@ func toBuildOptions(opts Options) (buildOptions api.BuildOptions, err error) {
 		err = fmt.Errorf("unsupported sourcemap type: %q", opts.SourceMap)
 		return
 	}
+	sourcesContent := api.SourcesContentInclude
+	if !opts.IncludeSourcesContent() {
+		sourcesContent = api.SourcesContentExclude
+	}
 
 	buildOptions = api.BuildOptions{
 		Outfile: outFile,
 		Bundle:  true,
 
-		Target:    target,
-		Format:    format,
-		Sourcemap: sourceMap,
+		Target:         target,
+		Format:         format,
+		Sourcemap:      sourceMap,
+		SourcesContent: sourcesContent,
 
 		MinifyWhitespace:  opts.Minify,
 		MinifyIdentifiers: opts.Minify,
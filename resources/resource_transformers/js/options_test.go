@@ -17,6 +17,7 @@ import (
 	"testing"
 
 	"github.com/gohugoio/hugo/media"
+	"github.com/gohugoio/hugo/resources"
 
 	"github.com/evanw/esbuild/pkg/api"
 
@@ -75,7 +76,7 @@ func TestToBuildOptions(t *testing.T) {
 
 	opts, err = toBuildOptions(Options{
 		Target: "es2018", Format: "cjs", Minify: true, mediaType: media.JavascriptType,
-		SourceMap: "inline",
+		SourceMapOptions: resources.SourceMapOptions{SourceMap: "inline"},
 	})
 	c.Assert(err, qt.IsNil)
 	c.Assert(opts, qt.DeepEquals, api.BuildOptions{
@@ -93,7 +94,7 @@ func TestToBuildOptions(t *testing.T) {
 
 	opts, err = toBuildOptions(Options{
 		Target: "es2018", Format: "cjs", Minify: true, mediaType: media.JavascriptType,
-		SourceMap: "inline",
+		SourceMapOptions: resources.SourceMapOptions{SourceMap: "inline"},
 	})
 	c.Assert(err, qt.IsNil)
 	c.Assert(opts, qt.DeepEquals, api.BuildOptions{
@@ -111,7 +112,7 @@ func TestToBuildOptions(t *testing.T) {
 
 	opts, err = toBuildOptions(Options{
 		Target: "es2018", Format: "cjs", Minify: true, mediaType: media.JavascriptType,
-		SourceMap: "external",
+		SourceMapOptions: resources.SourceMapOptions{SourceMap: "external"},
 	})
 	c.Assert(err, qt.IsNil)
 	c.Assert(opts, qt.DeepEquals, api.BuildOptions{
@@ -127,3 +128,15 @@ func TestToBuildOptions(t *testing.T) {
 		},
 	})
 }
+
+func TestCSSModuleToJS(t *testing.T) {
+	c := qt.New(t)
+
+	js, err := cssModuleToJS(`.button { color: red }`, "css/button.module.css", "[local]_x")
+	c.Assert(err, qt.IsNil)
+	c.Assert(js, qt.Contains, `const css = ".button_x { color: red }";`)
+	c.Assert(js, qt.Contains, `export default {"button":"button_x"};`)
+
+	result := api.Transform(js, api.TransformOptions{Loader: api.LoaderJS})
+	c.Assert(result.Errors, qt.HasLen, 0)
+}
@@ -46,7 +46,12 @@ func (t *minifyTransformation) Key() internal.ResourceTransformationKey {
 	return internal.NewResourceTransformationKey("minify")
 }
 
+// Transform minifies the Resource. The minifiers do not create source maps,
+// so any source map of the Resource is dropped, as it would no longer
+// match. Use the minify options of e.g. js.Build to get minified resources
+// with source maps.
 func (t *minifyTransformation) Transform(ctx *resources.ResourceTransformationCtx) error {
+	ctx.SourceMap = nil
 	_ = t.m.Minify(ctx.InMediaType, ctx.To, ctx.From)
 	ctx.AddOutPathIdentifier(".min")
 	return nil
@@ -32,6 +32,7 @@ import (
 	"github.com/gohugoio/hugo/common/hugo"
 
 	"github.com/gohugoio/hugo/common/loggers"
+	"github.com/gohugoio/hugo/config"
 
 	"github.com/gohugoio/hugo/resources/internal"
 	"github.com/spf13/afero"
@@ -63,6 +64,13 @@ func DecodeOptions(m map[string]interface{}) (opts Options, err error) {
 		return
 	}
 	err = mapstructure.WeakDecode(m, &opts)
+	if err != nil {
+		return
+	}
+
+	if err = opts.SourceMapOptions.Validate(); err != nil {
+		return
+	}
 
 	if !opts.NoMap {
 		// There was for a long time a discrepancy between documentation and
@@ -92,6 +100,10 @@ type Options struct {
 
 	NoMap bool // Disable the default inline sourcemaps
 
+	// Source map options, see resources.SourceMapOptions.
+	// SourceMap defaults to "inline" unless NoMap is set.
+	resources.SourceMapOptions `mapstructure:",squash"`
+
 	// Enable inlining of @import statements.
 	// Does so recursively, but currently once only per file;
 	// that is, it's not possible to import the same file in
@@ -107,6 +119,21 @@ type Options struct {
 	Syntax      string // Custom postcss syntax
 }
 
+// sourceMapOptions returns the source map options to use for the given
+// configuration.
+func (opts Options) sourceMapOptions(cfg config.Provider) resources.SourceMapOptions {
+	smOpts := opts.SourceMapOptions
+	if opts.NoMap {
+		smOpts.SourceMap = ""
+		return smOpts
+	}
+	if smOpts.SourceMap == "" {
+		smOpts.SourceMap = resources.SourceMapInline
+	}
+	smOpts.SourceMap = smOpts.Type(cfg)
+	return smOpts
+}
+
 func (opts Options) toArgs() []string {
 	var args []string
 	if opts.NoMap {
@@ -178,6 +205,10 @@ func (t *postcssTransformation) Transform(ctx *resources.ResourceTransformationC
 		}
 	}
 
+	opts := t.options
+	sourceMapOpts := opts.sourceMapOptions(t.rs.Cfg)
+	opts.NoMap = sourceMapOpts.SourceMap == ""
+
 	var cmdArgs []string
 
 	if configFile != "" {
@@ -185,22 +216,30 @@ func (t *postcssTransformation) Transform(ctx *resources.ResourceTransformationC
 		cmdArgs = []string{"--config", configFile}
 	}
 
-	if optArgs := t.options.toArgs(); len(optArgs) > 0 {
+	if optArgs := opts.toArgs(); len(optArgs) > 0 {
 		cmdArgs = append(cmdArgs, optArgs...)
 	}
 
-	cmd, err := hexec.SafeCommand(binary, cmdArgs...)
+	cmd, done, err := hexec.Command(t.rs.Cfg, "", binary, cmdArgs...)
 	if err != nil {
 		return err
 	}
+	defer done()
 
 	var errBuf bytes.Buffer
 	infoW := loggers.LoggerToWriterWithPrefix(logger.Info(), "postcss")
 
-	cmd.Stdout = ctx.To
+	// PostCSS writes the source map inline, which we may need to publish
+	// as a separate file.
+	var out bytes.Buffer
+	if opts.NoMap {
+		cmd.Stdout = ctx.To
+	} else {
+		cmd.Stdout = &out
+	}
 	cmd.Stderr = io.MultiWriter(infoW, &errBuf)
 
-	cmd.Env = hugo.GetExecEnviron(t.rs.WorkingDir, t.rs.Cfg, t.rs.BaseFs.Assets.Fs)
+	cmd.Env = append(hugo.GetExecEnviron(t.rs.WorkingDir, t.rs.Cfg, t.rs.BaseFs.Assets.Fs), hexec.GetLimiter(t.rs.Cfg).MemoryEnv(binary)...)
 
 	stdin, err := cmd.StdinPipe()
 	if err != nil {
@@ -215,7 +254,7 @@ func (t *postcssTransformation) Transform(ctx *resources.ResourceTransformationC
 		t.rs.Assets.Fs, t.rs.Logger,
 	)
 
-	if t.options.InlineImports {
+	if opts.InlineImports {
 		var err error
 		src, err = imp.resolve()
 		if err != nil {
@@ -233,7 +272,14 @@ func (t *postcssTransformation) Transform(ctx *resources.ResourceTransformationC
 		return imp.toFileError(errBuf.String())
 	}
 
-	return nil
+	if opts.NoMap {
+		ctx.SourceMap = nil
+		return nil
+	}
+
+	content, sourceMap := resources.ExtractSourceMap(out.Bytes())
+
+	return ctx.WriteSourceMapped(content, sourceMap, sourceMapOpts)
 }
 
 type fileOffset struct {
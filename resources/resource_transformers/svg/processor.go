@@ -0,0 +1,391 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package svg
+
+import (
+	"bytes"
+	"html"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/tdewolff/parse/v2"
+	"github.com/tdewolff/parse/v2/xml"
+)
+
+var (
+	// The properties that take a color.
+	colorProperties = map[string]bool{
+		"fill":           true,
+		"stroke":         true,
+		"stop-color":     true,
+		"color":          true,
+		"flood-color":    true,
+		"lighting-color": true,
+	}
+
+	colorDeclarationRe = regexp.MustCompile(`(?i)(^|[;{\s])(fill|stroke|stop-color|color|flood-color|lighting-color)(\s*:\s*)([^;}!"']+)`)
+	urlRefRe           = regexp.MustCompile(`url\(\s*(['"]?)#`)
+	idAttrRe           = regexp.MustCompile(`\sid\s*=\s*["']([^"']+)["']`)
+)
+
+type attr struct {
+	name string
+
+	// The raw value, including any quotes, or nil if the attribute has
+	// no value.
+	raw []byte
+
+	// The value, set if modified.
+	value    string
+	modified bool
+}
+
+type element struct {
+	name    string
+	dropped bool
+}
+
+type processor struct {
+	opts   Options
+	colors map[string]string
+
+	// Matches the ID selectors in styles for the IDs in the document.
+	idSelectorRe *regexp.Regexp
+}
+
+func newProcessor(opts Options) *processor {
+	p := &processor{opts: opts}
+	if len(opts.Colors) > 0 {
+		p.colors = make(map[string]string)
+		for from, to := range opts.Colors {
+			p.colors[normalizeColor(from)] = to
+		}
+	}
+	return p
+}
+
+func (p *processor) process(src []byte) ([]byte, error) {
+	if p.opts.IDPrefix != "" {
+		var ids []string
+		for _, m := range idAttrRe.FindAllSubmatch(src, -1) {
+			ids = append(ids, regexp.QuoteMeta(string(m[1])))
+		}
+		if len(ids) > 0 {
+			p.idSelectorRe = regexp.MustCompile(`#(` + strings.Join(ids, "|") + `)\b`)
+		}
+	}
+
+	var (
+		buf bytes.Buffer
+
+		l = xml.NewLexer(parse.NewInputBytes(src))
+
+		stack     []element
+		skipDepth int
+		seenRoot  bool
+
+		// The start tag being read.
+		tagName    string
+		tagDropped bool
+		tagIsRoot  bool
+		attrs      []attr
+		inPI       bool
+	)
+
+	inStyle := func() bool {
+		return len(stack) > 0 && localName(stack[len(stack)-1].name) == "style"
+	}
+
+	for {
+		tt, data := l.Next()
+		switch tt {
+		case xml.ErrorToken:
+			if l.Err() != io.EOF {
+				return nil, l.Err()
+			}
+			return buf.Bytes(), nil
+		case xml.StartTagPIToken:
+			inPI = true
+			if !p.opts.StripMetadata {
+				buf.Write(data)
+			}
+		case xml.StartTagClosePIToken:
+			inPI = false
+			if !p.opts.StripMetadata {
+				buf.Write(data)
+			}
+		case xml.DOCTYPEToken, xml.CommentToken:
+			if !p.opts.StripMetadata && skipDepth == 0 {
+				buf.Write(data)
+			}
+		case xml.StartTagToken:
+			tagName = string(l.Text())
+			tagDropped = skipDepth > 0 || p.dropElement(tagName)
+			tagIsRoot = !seenRoot
+			seenRoot = true
+			attrs = attrs[:0]
+		case xml.AttributeToken:
+			if inPI {
+				if !p.opts.StripMetadata {
+					buf.Write(data)
+				}
+				continue
+			}
+			if tagDropped {
+				continue
+			}
+			a := attr{name: string(l.Text())}
+			if v := l.AttrVal(); v != nil {
+				a.raw = append([]byte(nil), v...)
+			}
+			if p.processAttr(&a) {
+				attrs = append(attrs, a)
+			}
+		case xml.StartTagCloseToken, xml.StartTagCloseVoidToken:
+			if !tagDropped {
+				if tagIsRoot && p.opts.Class != "" {
+					attrs = addClass(attrs, p.opts.Class)
+				}
+				buf.WriteString("<" + tagName)
+				for _, a := range attrs {
+					a.writeTo(&buf)
+				}
+				buf.Write(data)
+			}
+			if tt == xml.StartTagCloseToken {
+				stack = append(stack, element{name: tagName, dropped: tagDropped})
+				if tagDropped {
+					skipDepth++
+				}
+			}
+		case xml.EndTagToken:
+			if len(stack) == 0 {
+				continue
+			}
+			e := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if e.dropped {
+				skipDepth--
+				continue
+			}
+			buf.Write(data)
+		case xml.TextToken:
+			if skipDepth > 0 {
+				continue
+			}
+			if inStyle() {
+				buf.WriteString(p.processStyle(string(data)))
+			} else {
+				buf.Write(data)
+			}
+		case xml.CDATAToken:
+			if skipDepth > 0 {
+				continue
+			}
+			if inStyle() {
+				buf.WriteString("<![CDATA[" + p.processStyle(string(l.Text())) + "]]>")
+			} else {
+				buf.Write(data)
+			}
+		}
+	}
+}
+
+// dropElement reports whether the element with the given name should be
+// removed along with its children.
+func (p *processor) dropElement(name string) bool {
+	prefix, local := splitName(name)
+	local = strings.ToLower(local)
+
+	if p.opts.StripMetadata {
+		if local == "metadata" {
+			return true
+		}
+		if prefix != "" && prefix != "svg" {
+			// E.g. sodipodi:namedview.
+			return true
+		}
+	}
+
+	if p.opts.StripScripts {
+		if local == "script" || local == "foreignobject" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// processAttr processes a, returning false if it should be removed.
+func (p *processor) processAttr(a *attr) bool {
+	prefix, local := splitName(a.name)
+
+	if p.opts.StripMetadata {
+		if prefix == "xmlns" {
+			if local != "xlink" && local != "svg" {
+				return false
+			}
+		} else if prefix != "" && prefix != "xlink" && prefix != "xml" {
+			// E.g. inkscape:label.
+			return false
+		}
+	}
+
+	value := html.UnescapeString(unquote(a.raw))
+
+	if p.opts.StripScripts {
+		if strings.HasPrefix(strings.ToLower(local), "on") {
+			return false
+		}
+		if isUnsafeURL(value) {
+			return false
+		}
+	}
+
+	newValue := value
+
+	if p.colors != nil {
+		if colorProperties[strings.ToLower(local)] && prefix == "" {
+			if to, found := p.colors[normalizeColor(newValue)]; found {
+				newValue = to
+			}
+		} else if local == "style" && prefix == "" {
+			newValue = p.replaceColors(newValue)
+		}
+	}
+
+	if p.opts.IDPrefix != "" {
+		switch {
+		case local == "id" && prefix == "":
+			newValue = p.opts.IDPrefix + newValue
+		case local == "href" && strings.HasPrefix(newValue, "#"):
+			newValue = "#" + p.opts.IDPrefix + newValue[1:]
+		default:
+			newValue = p.prefixIDs(newValue)
+		}
+	}
+
+	if newValue != value {
+		a.value = newValue
+		a.modified = true
+	}
+
+	return true
+}
+
+// processStyle processes the content of a style element.
+func (p *processor) processStyle(s string) string {
+	if p.colors != nil {
+		s = p.replaceColors(s)
+	}
+	if p.idSelectorRe != nil {
+		// This covers both the selectors and the url(#id) references.
+		s = p.idSelectorRe.ReplaceAllString(s, "#"+p.opts.IDPrefix+"$1")
+	}
+	return s
+}
+
+// replaceColors replaces the colors in the CSS declarations in s.
+func (p *processor) replaceColors(s string) string {
+	return colorDeclarationRe.ReplaceAllStringFunc(s, func(decl string) string {
+		m := colorDeclarationRe.FindStringSubmatch(decl)
+		value := strings.TrimRight(m[4], " \t\r\n")
+		to, found := p.colors[normalizeColor(value)]
+		if !found {
+			return decl
+		}
+		return m[1] + m[2] + m[3] + to + m[4][len(value):]
+	})
+}
+
+// prefixIDs prefixes the IDs referenced with url(#id) in s.
+func (p *processor) prefixIDs(s string) string {
+	return urlRefRe.ReplaceAllString(s, "url(${1}#"+p.opts.IDPrefix)
+}
+
+func (a attr) writeTo(buf *bytes.Buffer) {
+	buf.WriteString(" " + a.name)
+	if a.modified {
+		buf.WriteString(`="` + escapeAttr(a.value) + `"`)
+	} else if a.raw != nil {
+		buf.WriteByte('=')
+		buf.Write(a.raw)
+	}
+}
+
+func addClass(attrs []attr, class string) []attr {
+	for i, a := range attrs {
+		if a.name == "class" {
+			value := a.value
+			if !a.modified {
+				value = html.UnescapeString(unquote(a.raw))
+			}
+			attrs[i].value = strings.TrimSpace(value + " " + class)
+			attrs[i].modified = true
+			return attrs
+		}
+	}
+	return append(attrs, attr{name: "class", value: class, modified: true})
+}
+
+// isUnsafeURL reports whether the URL in s can run scripts.
+func isUnsafeURL(s string) bool {
+	s = strings.Map(func(r rune) rune {
+		if r <= ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToLower(s))
+
+	if strings.Contains(s, "javascript:") || strings.Contains(s, "vbscript:") {
+		return true
+	}
+
+	return strings.HasPrefix(s, "data:") && !strings.HasPrefix(s, "data:image/") || strings.HasPrefix(s, "data:image/svg")
+}
+
+// normalizeColor lowercases s and expands any short hex color.
+func normalizeColor(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if len(s) == 4 && s[0] == '#' {
+		return string([]byte{'#', s[1], s[1], s[2], s[2], s[3], s[3]})
+	}
+	return s
+}
+
+func splitName(name string) (prefix, local string) {
+	if i := strings.IndexByte(name, ':'); i != -1 {
+		return name[:i], name[i+1:]
+	}
+	return "", name
+}
+
+func localName(name string) string {
+	_, local := splitName(name)
+	return strings.ToLower(local)
+}
+
+func unquote(raw []byte) string {
+	if len(raw) >= 2 && (raw[0] == '"' || raw[0] == '\'') && raw[len(raw)-1] == raw[0] {
+		return string(raw[1 : len(raw)-1])
+	}
+	return string(raw)
+}
+
+var attrEscaper = strings.NewReplacer(`&`, "&amp;", `<`, "&lt;", `"`, "&quot;")
+
+func escapeAttr(s string) string {
+	return attrEscaper.Replace(s)
+}
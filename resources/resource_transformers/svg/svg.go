@@ -0,0 +1,148 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package svg provides a resource transformer that optimizes and
+// manipulates SVG images.
+package svg
+
+import (
+	"bytes"
+	"io/ioutil"
+
+	"github.com/gohugoio/hugo/helpers"
+	"github.com/gohugoio/hugo/media"
+	"github.com/gohugoio/hugo/minifiers"
+	"github.com/gohugoio/hugo/resources"
+	"github.com/gohugoio/hugo/resources/internal"
+	"github.com/gohugoio/hugo/resources/resource"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+)
+
+// Options configures the SVG transformation.
+type Options struct {
+	// Minify the SVG. Default is true.
+	Minify bool
+
+	// Remove the comments, the XML declaration, the doctype, the metadata
+	// element and the elements and attributes added by editors such as
+	// Inkscape and Sketch. Default is true.
+	StripMetadata bool
+
+	// Remove the script and foreignObject elements, the event handler
+	// attributes and any javascript: URLs. Default is true.
+	StripScripts bool
+
+	// Prepare the SVG to be inlined in HTML. This implies StripMetadata and
+	// StripScripts and prefixes the IDs in the SVG, so multiple inlined SVGs
+	// do not clash. The prefix defaults to one derived from the content.
+	Inline bool
+
+	// The prefix to add to the IDs and the references to them.
+	IDPrefix string
+
+	// Colors to replace in the fill, stroke and other color properties, in
+	// attributes and styles, e.g. {"#000": "currentColor"}.
+	Colors map[string]string
+
+	// The class to add to the root svg element.
+	Class string
+}
+
+// DecodeOptions decodes options from the given map, using the defaults for
+// any options not set.
+func DecodeOptions(m map[string]interface{}) (opts Options, err error) {
+	opts = Options{
+		Minify:        true,
+		StripMetadata: true,
+		StripScripts:  true,
+	}
+
+	if m == nil {
+		return
+	}
+
+	err = mapstructure.WeakDecode(m, &opts)
+
+	return
+}
+
+// Client is the client used to do SVG transformations.
+type Client struct {
+	rs *resources.Spec
+	m  minifiers.Client
+}
+
+// New creates a new Client with the given specification.
+func New(rs *resources.Spec) (*Client, error) {
+	m, err := minifiers.New(rs.MediaTypes, rs.OutputFormats, rs.Cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{rs: rs, m: m}, nil
+}
+
+type svgTransformation struct {
+	options Options
+	m       minifiers.Client
+}
+
+func (t *svgTransformation) Key() internal.ResourceTransformationKey {
+	return internal.NewResourceTransformationKey("svg", t.options)
+}
+
+func (t *svgTransformation) Transform(ctx *resources.ResourceTransformationCtx) error {
+	if ctx.InMediaType.SubType != media.SVGType.SubType {
+		return errors.Errorf("svg: %s is not an SVG image", ctx.InPath)
+	}
+
+	src, err := ioutil.ReadAll(ctx.From)
+	if err != nil {
+		return err
+	}
+
+	opts := t.options
+	if opts.Inline {
+		opts.StripMetadata = true
+		opts.StripScripts = true
+		if opts.IDPrefix == "" {
+			opts.IDPrefix = "svg" + helpers.MD5String(string(src))[:8] + "-"
+		}
+	}
+
+	b, err := newProcessor(opts).process(src)
+	if err != nil {
+		return errors.Wrapf(err, "svg: failed to process %s", ctx.InPath)
+	}
+
+	ctx.AddOutPathIdentifier("_" + helpers.HashString(t.options))
+
+	if opts.Minify {
+		var buf bytes.Buffer
+		if err := t.m.Minify(media.SVGType, &buf, bytes.NewReader(b)); err == nil {
+			b = buf.Bytes()
+		}
+	}
+
+	_, err = ctx.To.Write(b)
+
+	return err
+}
+
+// Process transforms the given SVG Resource with the given options.
+func (c *Client) Process(res resources.ResourceTransformer, options Options) (resource.Resource, error) {
+	return res.Transform(&svgTransformation{
+		options: options,
+		m:       c.m,
+	})
+}
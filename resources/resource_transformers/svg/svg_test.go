@@ -0,0 +1,161 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package svg
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gohugoio/hugo/resources/resource"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gohugoio/hugo/resources/resource_transformers/htesting"
+)
+
+const testSVG = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE svg PUBLIC "-//W3C//DTD SVG 1.1//EN" "http://www.w3.org/Graphics/SVG/1.1/DTD/svg11.dtd">
+<!-- Created with Inkscape -->
+<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" xmlns:inkscape="http://www.inkscape.org/namespaces/inkscape" xmlns:sodipodi="http://sodipodi.sourceforge.net/DTD/sodipodi-0.dtd" width="24" height="24" onload="alert(1)" inkscape:version="1.0">
+  <metadata><rdf:RDF><cc:Work/></rdf:RDF></metadata>
+  <sodipodi:namedview id="base" pagecolor="#ffffff"/>
+  <style>.a { fill: #000; } #grad { stop-color: #FFF }</style>
+  <defs>
+    <linearGradient id="grad"><stop offset="0" stop-color="#fff"/></linearGradient>
+  </defs>
+  <script>alert("svg")</script>
+  <foreignObject><div>HTML</div></foreignObject>
+  <a href="javascript:alert(1)"><path class="a" d="M0 0h24v24H0z" fill="#000000" style="stroke: red"/></a>
+  <a xlink:href="&#106;avascript:alert(1)"><circle cx="12" cy="12" r="6" fill="url(#grad)"/></a>
+  <use href="#grad" inkscape:label="Use"/>
+</svg>
+`
+
+func TestDecodeOptions(t *testing.T) {
+	c := qt.New(t)
+
+	opts, err := DecodeOptions(nil)
+	c.Assert(err, qt.IsNil)
+	c.Assert(opts, qt.DeepEquals, Options{Minify: true, StripMetadata: true, StripScripts: true})
+
+	opts, err = DecodeOptions(map[string]interface{}{
+		"minify": "false",
+		"colors": map[string]interface{}{"#000": "currentColor"},
+		"class":  "icon",
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(opts, qt.DeepEquals, Options{
+		StripMetadata: true,
+		StripScripts:  true,
+		Colors:        map[string]string{"#000": "currentColor"},
+		Class:         "icon",
+	})
+}
+
+func TestProcess(t *testing.T) {
+	c := qt.New(t)
+
+	process := func(opts Options) string {
+		b, err := newProcessor(opts).process([]byte(testSVG))
+		c.Assert(err, qt.IsNil)
+		return string(b)
+	}
+
+	c.Run("None", func(c *qt.C) {
+		c.Assert(process(Options{}), qt.Equals, testSVG)
+	})
+
+	c.Run("Strip metadata", func(c *qt.C) {
+		s := process(Options{StripMetadata: true})
+		c.Assert(s, qt.Not(qt.Contains), "<?xml")
+		c.Assert(s, qt.Not(qt.Contains), "DOCTYPE")
+		c.Assert(s, qt.Not(qt.Contains), "Inkscape")
+		c.Assert(s, qt.Not(qt.Contains), "inkscape")
+		c.Assert(s, qt.Not(qt.Contains), "sodipodi")
+		c.Assert(s, qt.Not(qt.Contains), "rdf:RDF")
+		c.Assert(s, qt.Contains, `xmlns:xlink="http://www.w3.org/1999/xlink"`)
+		c.Assert(s, qt.Contains, `<use href="#grad"/>`)
+		c.Assert(s, qt.Contains, `<script>`)
+	})
+
+	c.Run("Strip scripts", func(c *qt.C) {
+		s := process(Options{StripScripts: true})
+		c.Assert(s, qt.Not(qt.Contains), "alert")
+		c.Assert(s, qt.Not(qt.Contains), "foreignObject")
+		c.Assert(s, qt.Not(qt.Contains), "<div>")
+		c.Assert(s, qt.Contains, `<a><path class="a"`)
+		c.Assert(s, qt.Contains, `<a><circle`)
+		c.Assert(s, qt.Contains, "<!-- Created with Inkscape -->")
+	})
+
+	c.Run("Colors", func(c *qt.C) {
+		s := process(Options{Colors: map[string]string{"#000": "currentColor", "#ffffff": "#eee", "RED": "blue"}})
+		c.Assert(s, qt.Contains, `.a { fill: currentColor; }`)
+		c.Assert(s, qt.Contains, `#grad { stop-color: #eee }`)
+		c.Assert(s, qt.Contains, `stop-color="#eee"`)
+		c.Assert(s, qt.Contains, `fill="currentColor" style="stroke: blue"`)
+		// Not a color property.
+		c.Assert(s, qt.Contains, `pagecolor="#ffffff"`)
+	})
+
+	c.Run("ID prefix", func(c *qt.C) {
+		s := process(Options{IDPrefix: "p-"})
+		c.Assert(s, qt.Contains, `#p-grad { stop-color: #FFF }`)
+		c.Assert(s, qt.Contains, `<linearGradient id="p-grad">`)
+		c.Assert(s, qt.Contains, `fill="url(#p-grad)"`)
+		c.Assert(s, qt.Contains, `<use href="#p-grad"`)
+	})
+
+	c.Run("Class", func(c *qt.C) {
+		s := process(Options{Class: "icon"})
+		c.Assert(s, qt.Contains, `inkscape:version="1.0" class="icon">`)
+		c.Assert(strings.Count(s, `class="icon"`), qt.Equals, 1)
+
+		b, err := newProcessor(Options{Class: "icon"}).process([]byte(`<svg class="logo"><path class="a"/></svg>`))
+		c.Assert(err, qt.IsNil)
+		c.Assert(string(b), qt.Equals, `<svg class="logo icon"><path class="a"/></svg>`)
+	})
+}
+
+func TestTransform(t *testing.T) {
+	c := qt.New(t)
+
+	spec, err := htesting.NewTestResourceSpec()
+	c.Assert(err, qt.IsNil)
+	client, err := New(spec)
+	c.Assert(err, qt.IsNil)
+
+	r, err := htesting.NewResourceTransformerForSpec(spec, "icon.svg", testSVG)
+	c.Assert(err, qt.IsNil)
+
+	opts, _ := DecodeOptions(map[string]interface{}{"inline": true, "colors": map[string]string{"#000": "currentColor"}})
+	transformed, err := client.Process(r, opts)
+	c.Assert(err, qt.IsNil)
+
+	c.Assert(transformed.RelPermalink(), qt.Matches, `/icon_\d+\.svg`)
+	content, err := transformed.(resource.ContentProvider).Content()
+	c.Assert(err, qt.IsNil)
+	s := content.(string)
+	c.Assert(s, qt.Not(qt.Contains), "alert")
+	c.Assert(s, qt.Not(qt.Contains), "inkscape")
+	c.Assert(s, qt.Not(qt.Contains), "\n")
+	c.Assert(s, qt.Contains, `.a{fill:currentColor}`)
+	c.Assert(s, qt.Matches, `<svg .*<linearGradient id="svg[0-9a-f]{8}-grad">.*`)
+
+	r, err = htesting.NewResourceTransformerForSpec(spec, "hugo.html", "<h1>Hugo</h1>")
+	c.Assert(err, qt.IsNil)
+	transformed, err = client.Process(r, opts)
+	c.Assert(err, qt.IsNil)
+	_, err = transformed.(resource.ContentProvider).Content()
+	c.Assert(err, qt.ErrorMatches, ".*hugo.html is not an SVG image")
+}
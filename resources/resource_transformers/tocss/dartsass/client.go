@@ -16,8 +16,13 @@
 package dartsass
 
 import (
+	"context"
 	"io"
+	"os"
 
+	"github.com/gohugoio/hugo/common/hexec"
+	"github.com/gohugoio/hugo/config"
+	"github.com/gohugoio/hugo/config/security"
 	"github.com/gohugoio/hugo/helpers"
 	"github.com/gohugoio/hugo/hugolib/filesystems"
 	"github.com/gohugoio/hugo/resources"
@@ -35,6 +40,20 @@ func New(fs *filesystems.SourceFilesystem, rs *resources.Spec) (*Client, error)
 	if !Supports() {
 		return &Client{dartSassNoAvailable: true}, nil
 	}
+
+	// The embedded Dart Sass is started by godartsass, check that it's
+	// allowed to run before starting it.
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	if err := hexec.CheckExec(rs.Cfg, dartSassEmbeddedBinaryName, nil, wd); err != nil {
+		return &Client{startErr: err}, nil
+	}
+	if err := security.Get(rs.Cfg).Audit(dartSassEmbeddedBinaryName, nil, wd, nil); err != nil {
+		return nil, err
+	}
+
 	transpiler, err := godartsass.Start(godartsass.Options{})
 	if err != nil {
 		return nil, err
@@ -48,12 +67,18 @@ type Client struct {
 	sfs                 *filesystems.SourceFilesystem
 	workFs              afero.Fs
 	transpiler          *godartsass.Transpiler
+
+	// Set if Dart Sass isn't allowed to run by the security policy.
+	startErr error
 }
 
 func (c *Client) ToCSS(res resources.ResourceTransformer, args map[string]interface{}) (resource.Resource, error) {
 	if c.dartSassNoAvailable {
 		return res.Transform(resources.NewFeatureNotAvailableTransformer(transformationName, args))
 	}
+	if c.startErr != nil {
+		return nil, c.startErr
+	}
 	return res.Transform(&transform{c: c, optsm: args})
 }
 
@@ -70,7 +95,13 @@ func (c *Client) toCSS(args godartsass.Args, src io.Reader) (godartsass.Result,
 	in := helpers.ReaderToString(src)
 	args.Source = in
 
-	res, err := c.transpiler.Execute(args)
+	release, err := hexec.GetLimiter(c.rs.Cfg).Acquire(context.Background(), dartSassEmbeddedBinaryName)
+	if err != nil {
+		return res, err
+	}
+	defer release()
+
+	res, err = c.transpiler.Execute(args)
 	if err != nil {
 		return res, err
 	}
@@ -97,7 +128,11 @@ type Options struct {
 	// One of nested, expanded, compact, compressed.
 	OutputStyle string
 
-	// When enabled, Hugo will generate a source map.
+	// Source map options, see resources.SourceMapOptions.
+	resources.SourceMapOptions `mapstructure:",squash"`
+
+	// When enabled, Hugo will generate an external source map.
+	// This is the same as setting SourceMap to "external".
 	EnableSourceMap bool
 }
 
@@ -106,10 +141,26 @@ func decodeOptions(m map[string]interface{}) (opts Options, err error) {
 		return
 	}
 	err = mapstructure.WeakDecode(m, &opts)
+	if err != nil {
+		return
+	}
 
 	if opts.TargetPath != "" {
 		opts.TargetPath = helpers.ToSlashTrimLeading(opts.TargetPath)
 	}
 
+	err = opts.SourceMapOptions.Validate()
+
 	return
 }
+
+// sourceMapOptions returns the source map options to use for the given
+// configuration.
+func (opts Options) sourceMapOptions(cfg config.Provider) resources.SourceMapOptions {
+	smOpts := opts.SourceMapOptions
+	if opts.EnableSourceMap && smOpts.SourceMap == "" {
+		smOpts.SourceMap = resources.SourceMapExternal
+	}
+	smOpts.SourceMap = smOpts.Type(cfg)
+	return smOpts
+}
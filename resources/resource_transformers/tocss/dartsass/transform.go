@@ -15,7 +15,6 @@ package dartsass
 
 import (
 	"fmt"
-	"io"
 	"net/url"
 	"path"
 	"path/filepath"
@@ -41,12 +40,14 @@ import (
 // See https://github.com/sass/dart-sass-embedded/issues/24
 const stdinPlaceholder = "HUGOSTDIN"
 
+const dartSassEmbeddedBinaryName = "dart-sass-embedded"
+
 // Supports returns whether dart-sass-embedded is found in $PATH.
 func Supports() bool {
 	if htesting.SupportsAll() {
 		return true
 	}
-	p, err := safeexec.LookPath("dart-sass-embedded")
+	p, err := safeexec.LookPath(dartSassEmbeddedBinaryName)
 	return err == nil && p != ""
 }
 
@@ -73,6 +74,8 @@ func (t *transform) Transform(ctx *resources.ResourceTransformationCtx) error {
 		ctx.ReplaceOutPathExtension(".css")
 	}
 
+	sourceMapOpts := opts.sourceMapOptions(t.c.rs.Cfg)
+
 	baseDir := path.Dir(ctx.SourcePath)
 
 	args := godartsass.Args{
@@ -83,7 +86,7 @@ func (t *transform) Transform(ctx *resources.ResourceTransformationCtx) error {
 			c:       t.c,
 		},
 		OutputStyle:     godartsass.ParseOutputStyle(opts.OutputStyle),
-		EnableSourceMap: opts.EnableSourceMap,
+		EnableSourceMap: sourceMapOpts.SourceMap != "",
 	}
 
 	// Append any workDir relative include paths
@@ -132,21 +135,7 @@ func (t *transform) Transform(ctx *resources.ResourceTransformationCtx) error {
 		return err
 	}
 
-	out := res.CSS
-
-	_, err = io.WriteString(ctx.To, out)
-	if err != nil {
-		return err
-	}
-
-	if opts.EnableSourceMap && res.SourceMap != "" {
-		if err := ctx.PublishSourceMap(res.SourceMap); err != nil {
-			return err
-		}
-		_, err = fmt.Fprintf(ctx.To, "\n\n/*# sourceMappingURL=%s */", path.Base(ctx.OutPath)+".map")
-	}
-
-	return err
+	return ctx.WriteSourceMapped([]byte(res.CSS), []byte(res.SourceMap), sourceMapOpts)
 }
 
 type importResolver struct {
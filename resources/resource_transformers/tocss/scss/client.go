@@ -16,6 +16,7 @@ package scss
 import (
 	"regexp"
 
+	"github.com/gohugoio/hugo/config"
 	"github.com/gohugoio/hugo/helpers"
 	"github.com/gohugoio/hugo/hugolib/filesystems"
 	"github.com/gohugoio/hugo/resources"
@@ -58,7 +59,11 @@ type Options struct {
 	// Precision of floating point math.
 	Precision int
 
-	// When enabled, Hugo will generate a source map.
+	// Source map options, see resources.SourceMapOptions.
+	resources.SourceMapOptions `mapstructure:",squash"`
+
+	// When enabled, Hugo will generate an external source map.
+	// This is the same as setting SourceMap to "external".
 	EnableSourceMap bool
 }
 
@@ -67,14 +72,30 @@ func DecodeOptions(m map[string]interface{}) (opts Options, err error) {
 		return
 	}
 	err = mapstructure.WeakDecode(m, &opts)
+	if err != nil {
+		return
+	}
 
 	if opts.TargetPath != "" {
 		opts.TargetPath = helpers.ToSlashTrimLeading(opts.TargetPath)
 	}
 
+	err = opts.SourceMapOptions.Validate()
+
 	return
 }
 
+// sourceMapOptions returns the source map options to use for the given
+// configuration.
+func (opts Options) sourceMapOptions(cfg config.Provider) resources.SourceMapOptions {
+	smOpts := opts.SourceMapOptions
+	if opts.EnableSourceMap && smOpts.SourceMap == "" {
+		smOpts.SourceMap = resources.SourceMapExternal
+	}
+	smOpts.SourceMap = smOpts.Type(cfg)
+	return smOpts
+}
+
 var (
 	regularCSSImportTo   = regexp.MustCompile(`.*(@import "(.*\.css)";).*`)
 	regularCSSImportFrom = regexp.MustCompile(`.*(\/\* HUGO_IMPORT_START (.*) HUGO_IMPORT_END \*\/).*`)
@@ -16,6 +16,7 @@
 package scss
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"path"
@@ -118,28 +119,35 @@ func (t *toCSSTransformation) Transform(ctx *resources.ResourceTransformationCtx
 		options.to.SassSyntax = true
 	}
 
-	if options.from.EnableSourceMap {
+	sourceMapOpts := options.from.sourceMapOptions(t.c.rs.Cfg)
 
-		options.to.SourceMapOptions.Filename = outName + ".map"
-		options.to.SourceMapOptions.Root = t.c.rs.WorkingDir
-
-		// Setting this to the relative input filename will get the source map
-		// more correct for the main entry path (main.scss typically), but
-		// it will mess up the import mappings. As a workaround, we do a replacement
-		// in the source map itself (see below).
-		// options.InputPath = inputPath
-		options.to.SourceMapOptions.OutputPath = outName
-		options.to.SourceMapOptions.Contents = true
-		options.to.SourceMapOptions.OmitURL = false
-		options.to.SourceMapOptions.EnableEmbedded = false
+	if sourceMapOpts.SourceMap == "" {
+		ctx.SourceMap = nil
+		_, err := t.c.toCSS(options.to, ctx.To, ctx.From)
+		return err
 	}
 
-	res, err := t.c.toCSS(options.to, ctx.To, ctx.From)
+	options.to.SourceMapOptions.Filename = outName + ".map"
+	options.to.SourceMapOptions.Root = t.c.rs.WorkingDir
+
+	// Setting this to the relative input filename will get the source map
+	// more correct for the main entry path (main.scss typically), but
+	// it will mess up the import mappings. As a workaround, we do a replacement
+	// in the source map itself (see below).
+	// options.InputPath = inputPath
+	options.to.SourceMapOptions.OutputPath = outName
+	options.to.SourceMapOptions.Contents = sourceMapOpts.IncludeSourcesContent()
+	options.to.SourceMapOptions.OmitURL = true
+	options.to.SourceMapOptions.EnableEmbedded = false
+
+	var b bytes.Buffer
+	res, err := t.c.toCSS(options.to, &b, ctx.From)
 	if err != nil {
 		return err
 	}
 
-	if options.from.EnableSourceMap && res.SourceMapContent != "" {
+	var mapContent string
+	if res.SourceMapContent != "" {
 		sourcePath := t.c.sfs.RealFilename(ctx.SourcePath)
 
 		if strings.HasPrefix(sourcePath, t.c.rs.WorkingDir) {
@@ -153,11 +161,10 @@ func (t *toCSSTransformation) Transform(ctx *resources.ResourceTransformationCtx
 		// This is a workaround for what looks like a bug in Libsass. But
 		// getting this resolution correct in tools like Chrome Workspaces
 		// is important enough to go this extra mile.
-		mapContent := strings.Replace(res.SourceMapContent, `stdin",`, fmt.Sprintf("%s\",", sourcePath), 1)
-
-		return ctx.PublishSourceMap(mapContent)
+		mapContent = strings.Replace(res.SourceMapContent, `stdin",`, fmt.Sprintf("%s\",", sourcePath), 1)
 	}
-	return nil
+
+	return ctx.WriteSourceMapped(b.Bytes(), []byte(mapContent), sourceMapOpts)
 }
 
 func (c *Client) toCSS(options libsass.Options, dst io.Writer, src io.Reader) (libsass.Result, error) {
@@ -0,0 +1,181 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+
+	"github.com/gohugoio/hugo/common/hugo"
+	"github.com/gohugoio/hugo/config"
+	"github.com/gohugoio/hugo/media"
+)
+
+const (
+	SourceMapInline   = "inline"
+	SourceMapExternal = "external"
+)
+
+// SourceMapOptions configures the source maps created by the resource
+// transformers, e.g. js.Build and ToCSS. Embed it in the options of a
+// transformer with `mapstructure:",squash"`.
+type SourceMapOptions struct {
+	// Either "inline", to add the source map to the resource as a data URL,
+	// or "external", to publish it next to the resource with a ".map"
+	// extension. No source map is created if not set.
+	SourceMap string
+
+	// Whether to include the content of the sources in the source map.
+	// Default is true.
+	SourcesContent *bool
+
+	// Enable to not create any source map when building for production,
+	// i.e. when hugo.Environment is "production".
+	OmitSourceMapInProduction bool
+}
+
+// Validate returns an error if the source map type is not supported.
+func (o SourceMapOptions) Validate() error {
+	switch o.SourceMap {
+	case "", SourceMapInline, SourceMapExternal:
+		return nil
+	default:
+		return fmt.Errorf("unsupported sourcemap type: %q", o.SourceMap)
+	}
+}
+
+// Type returns the type of source map to create for the given
+// configuration, "inline", "external" or empty for none.
+func (o SourceMapOptions) Type(cfg config.Provider) string {
+	if o.OmitSourceMapInProduction && cfg.GetString("environment") == hugo.EnvironmentProduction {
+		return ""
+	}
+	return o.SourceMap
+}
+
+// IncludeSourcesContent reports whether to include the content of the
+// sources in the source map.
+func (o SourceMapOptions) IncludeSourcesContent() bool {
+	return o.SourcesContent == nil || *o.SourcesContent
+}
+
+var sourceMappingURLRe = regexp.MustCompile(`[ \t]*(?://[#@] sourceMappingURL=([^\s]*)[^\r\n]*|/\*[#@] sourceMappingURL=([^\s*]*)[^*]*\*/)\r?\n?`)
+
+// ExtractSourceMap removes the source map references from content,
+// returning the source map if content has one inlined as a data URL.
+func ExtractSourceMap(content []byte) ([]byte, []byte) {
+	var sourceMap []byte
+	content = sourceMappingURLRe.ReplaceAllFunc(content, func(m []byte) []byte {
+		sub := sourceMappingURLRe.FindSubmatch(m)
+		u := sub[1]
+		if len(u) == 0 {
+			u = sub[2]
+		}
+		if i := bytes.Index(u, []byte("base64,")); i != -1 && bytes.HasPrefix(u, []byte("data:")) {
+			if b, err := base64.StdEncoding.DecodeString(string(u[i+len("base64,"):])); err == nil {
+				sourceMap = b
+			}
+		}
+		return nil
+	})
+	return content, sourceMap
+}
+
+// WriteSourceMapped writes content to To, with sourceMap added as
+// configured in opts. Any source map references in content are removed.
+//
+// An external source map is published next to the resource when all the
+// transformations are done, so transformations renaming the resource, e.g.
+// fingerprint, can update the reference and publish it under the new name.
+func (ctx *ResourceTransformationCtx) WriteSourceMapped(content, sourceMap []byte, opts SourceMapOptions) error {
+	content, _ = ExtractSourceMap(content)
+	ctx.SourceMap = nil
+	ctx.sourceMapRef = ""
+
+	if len(sourceMap) == 0 || opts.SourceMap == "" {
+		_, err := ctx.To.Write(content)
+		return err
+	}
+
+	if !opts.IncludeSourcesContent() {
+		var err error
+		sourceMap, err = updateSourceMap(sourceMap, func(m map[string]interface{}) {
+			delete(m, "sourcesContent")
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	var ref string
+	if opts.SourceMap == SourceMapInline {
+		ref = "data:application/json;base64," + base64.StdEncoding.EncodeToString(sourceMap)
+	} else {
+		ref = path.Base(ctx.OutPathOrInPath()) + ".map"
+		ctx.SourceMap = sourceMap
+		ctx.sourceMapRef = ref
+	}
+
+	if _, err := ctx.To.Write(bytes.TrimRight(content, " \t\r\n")); err != nil {
+		return err
+	}
+	_, err := ctx.To.Write([]byte(sourceMappingURLComment(ctx.OutMediaType, ref)))
+	return err
+}
+
+// OutPathOrInPath returns the target path of the transformed resource.
+func (ctx *ResourceTransformationCtx) OutPathOrInPath() string {
+	if ctx.OutPath != "" {
+		return ctx.OutPath
+	}
+	return ctx.InPath
+}
+
+// publishSourceMap publishes the external source map of the transformed
+// resource published to targetPath, if any. It is published with the name
+// the resource references it by, which may differ from the resource's name
+// if a later transformation renamed it.
+func (ctx *ResourceTransformationCtx) publishSourceMap(targetPath string) error {
+	if ctx.SourceMap == nil {
+		return nil
+	}
+
+	f, err := ctx.OpenResourcePublisher(path.Join(path.Dir(targetPath), ctx.sourceMapRef))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(ctx.SourceMap)
+	return err
+}
+
+func sourceMappingURLComment(mt media.Type, ref string) string {
+	if mt.SubType == media.CSSType.SubType {
+		return "\n/*# sourceMappingURL=" + ref + " */\n"
+	}
+	return "\n//# sourceMappingURL=" + ref + "\n"
+}
+
+func updateSourceMap(sourceMap []byte, fn func(m map[string]interface{})) ([]byte, error) {
+	var m map[string]interface{}
+	if err := json.Unmarshal(sourceMap, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse source map: %s", err)
+	}
+	fn(m)
+	return json.Marshal(m)
+}
@@ -0,0 +1,93 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+
+	"github.com/gohugoio/hugo/config"
+	"github.com/gohugoio/hugo/media"
+
+	qt "github.com/frankban/quicktest"
+)
+
+const testSourceMap = `{"version":3,"sources":["a.js"],"sourcesContent":["let a = 1;"],"mappings":"AAAA"}`
+
+func TestSourceMapOptions(t *testing.T) {
+	c := qt.New(t)
+
+	c.Assert(SourceMapOptions{SourceMap: "inline"}.Validate(), qt.IsNil)
+	c.Assert(SourceMapOptions{SourceMap: "both"}.Validate(), qt.Not(qt.IsNil))
+
+	cfg := config.New()
+	opts := SourceMapOptions{SourceMap: "external", OmitSourceMapInProduction: true}
+	c.Assert(opts.Type(cfg), qt.Equals, "external")
+	cfg.Set("environment", "production")
+	c.Assert(opts.Type(cfg), qt.Equals, "")
+	opts.OmitSourceMapInProduction = false
+	c.Assert(opts.Type(cfg), qt.Equals, "external")
+
+	c.Assert(opts.IncludeSourcesContent(), qt.Equals, true)
+	no := false
+	opts.SourcesContent = &no
+	c.Assert(opts.IncludeSourcesContent(), qt.Equals, false)
+}
+
+func TestExtractSourceMap(t *testing.T) {
+	c := qt.New(t)
+
+	encoded := base64.StdEncoding.EncodeToString([]byte(testSourceMap))
+
+	content, sourceMap := ExtractSourceMap([]byte("let a = 1;\n//# sourceMappingURL=data:application/json;base64," + encoded + "\n"))
+	c.Assert(string(content), qt.Equals, "let a = 1;\n")
+	c.Assert(string(sourceMap), qt.Equals, testSourceMap)
+
+	content, sourceMap = ExtractSourceMap([]byte("a { color: red }\n/*# sourceMappingURL=main.css.map */"))
+	c.Assert(string(content), qt.Equals, "a { color: red }\n")
+	c.Assert(sourceMap, qt.IsNil)
+}
+
+func TestWriteSourceMapped(t *testing.T) {
+	c := qt.New(t)
+
+	newCtx := func(mt media.Type) (*ResourceTransformationCtx, *bytes.Buffer) {
+		var b bytes.Buffer
+		return &ResourceTransformationCtx{To: &b, OutPath: "js/main.js", OutMediaType: mt}, &b
+	}
+
+	src := []byte("let a = 1;\n//# sourceMappingURL=old.js.map\n")
+
+	ctx, b := newCtx(media.JavascriptType)
+	c.Assert(ctx.WriteSourceMapped(src, []byte(testSourceMap), SourceMapOptions{SourceMap: "external"}), qt.IsNil)
+	c.Assert(b.String(), qt.Equals, "let a = 1;\n//# sourceMappingURL=main.js.map\n")
+	c.Assert(string(ctx.SourceMap), qt.Equals, testSourceMap)
+
+	ctx, b = newCtx(media.CSSType)
+	c.Assert(ctx.WriteSourceMapped(src, []byte(testSourceMap), SourceMapOptions{SourceMap: "inline"}), qt.IsNil)
+	c.Assert(b.String(), qt.Equals, "let a = 1;\n/*# sourceMappingURL=data:application/json;base64,"+base64.StdEncoding.EncodeToString([]byte(testSourceMap))+" */\n")
+	c.Assert(ctx.SourceMap, qt.IsNil)
+
+	no := false
+	ctx, _ = newCtx(media.JavascriptType)
+	c.Assert(ctx.WriteSourceMapped(src, []byte(testSourceMap), SourceMapOptions{SourceMap: "external", SourcesContent: &no}), qt.IsNil)
+	c.Assert(string(ctx.SourceMap), qt.Equals, `{"mappings":"AAAA","sources":["a.js"],"version":3}`)
+
+	ctx, b = newCtx(media.JavascriptType)
+	ctx.SourceMap = []byte(testSourceMap)
+	c.Assert(ctx.WriteSourceMapped(src, nil, SourceMapOptions{SourceMap: "external"}), qt.IsNil)
+	c.Assert(b.String(), qt.Equals, "let a = 1;\n")
+	c.Assert(ctx.SourceMap, qt.IsNil)
+}
@@ -21,13 +21,16 @@ import (
 	"path"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/gohugoio/hugo/common/paths"
 
 	"github.com/pkg/errors"
 
+	"github.com/gohugoio/hugo/resources/documents"
 	"github.com/gohugoio/hugo/resources/images/exif"
 	"github.com/spf13/afero"
+	"github.com/spf13/cast"
 
 	bp "github.com/gohugoio/hugo/bufferpool"
 
@@ -60,6 +63,15 @@ var transformationsToCacheOnDisk = map[string]bool{
 	"tocss-dart": true,
 }
 
+// These are the transformations that, with build.persistTransformations
+// enabled, are stored in the file cache keyed by their inputs and reused in
+// later builds.
+var transformationsToPersist = map[string]bool{
+	"jsbuild":    true,
+	"tocss":      true,
+	"tocss-dart": true,
+}
+
 func newResourceAdapter(spec *Spec, lazyPublish bool, target transformableResource) *resourceAdapter {
 	var po *publishOnce
 	if lazyPublish {
@@ -110,6 +122,14 @@ type ResourceTransformationCtx struct {
 	// to be simple types, as it needs to be serialized to JSON and back.
 	Data map[string]interface{}
 
+	// The external source map of the transformed resource, published
+	// next to it when all the transformations are done.
+	// See WriteSourceMapped.
+	SourceMap []byte
+
+	// The name the transformed resource references SourceMap by.
+	sourceMapRef string
+
 	// This is used to publish additional artifacts, e.g. source maps.
 	// We may improve this.
 	OpenResourcePublisher func(relTargetPath string) (io.WriteCloser, error)
@@ -121,19 +141,6 @@ func (ctx *ResourceTransformationCtx) AddOutPathIdentifier(identifier string) {
 	ctx.OutPath = ctx.addPathIdentifier(ctx.InPath, identifier)
 }
 
-// PublishSourceMap writes the content to the target folder of the main resource
-// with the ".map" extension added.
-func (ctx *ResourceTransformationCtx) PublishSourceMap(content string) error {
-	target := ctx.OutPath + ".map"
-	f, err := ctx.OpenResourcePublisher(target)
-	if err != nil {
-		return err
-	}
-	defer f.Close()
-	_, err = f.Write([]byte(content))
-	return err
-}
-
 // ReplaceOutPathExtension transforming InPath to OutPath replacing the file
 // extension, e.g. ".scss"
 func (ctx *ResourceTransformationCtx) ReplaceOutPathExtension(newExt string) {
@@ -184,10 +191,42 @@ func (r *resourceAdapter) Filter(filters ...interface{}) (resource.Image, error)
 	return r.getImageOps().Filter(filters...)
 }
 
+func (r *resourceAdapter) Profile(name string) (resource.Image, error) {
+	return r.getImageOps().Profile(name)
+}
+
+func (r *resourceAdapter) Derived() (map[string]resource.Image, error) {
+	return r.getImageOps().Derived()
+}
+
 func (r *resourceAdapter) Height() int {
+	if v, ok := r.getVideoOpsIfVideo(); ok {
+		return v.Height()
+	}
 	return r.getImageOps().Height()
 }
 
+func (r *resourceAdapter) Duration() time.Duration {
+	return r.getVideoOps().Duration()
+}
+
+func (r *resourceAdapter) Poster() (resource.Image, error) {
+	return r.getVideoOps().Poster()
+}
+
+func (r *resourceAdapter) PosterAt(at string) (resource.Image, error) {
+	return r.getVideoOps().PosterAt(at)
+}
+
+func (r *resourceAdapter) DocumentMeta() *documents.Metadata {
+	d, ok := r.target.(resource.DocumentMetaProvider)
+	if !ok {
+		return nil
+	}
+	r.init(false, false)
+	return d.DocumentMeta()
+}
+
 func (r *resourceAdapter) Exif() *exif.Exif {
 	return r.getImageOps().Exif()
 }
@@ -214,7 +253,10 @@ func (r *resourceAdapter) Params() maps.Params {
 
 func (r *resourceAdapter) Permalink() string {
 	r.init(true, false)
-	return r.target.Permalink()
+	r.addToAssetsManifest()
+	permalink := r.target.Permalink()
+	r.addToPublishedResources(permalink)
+	return permalink
 }
 
 func (r *resourceAdapter) Publish() error {
@@ -230,7 +272,42 @@ func (r *resourceAdapter) ReadSeekCloser() (hugio.ReadSeekCloser, error) {
 
 func (r *resourceAdapter) RelPermalink() string {
 	r.init(true, false)
-	return r.target.RelPermalink()
+	r.addToAssetsManifest()
+	relPermalink := r.target.RelPermalink()
+	r.addToPublishedResources(relPermalink)
+	return relPermalink
+}
+
+// addToAssetsManifest adds the published result of the transformations, if
+// any, to the assets manifest.
+func (r *resourceAdapter) addToAssetsManifest() {
+	if !r.spec.BuildConfig.WriteAssetsManifest || len(r.transformations) == 0 || r.transformationsErr != nil {
+		return
+	}
+
+	var integrity string
+	if m, ok := r.target.Data().(map[string]interface{}); ok {
+		integrity = cast.ToString(m["Integrity"])
+	}
+
+	r.spec.AssetsManifest.add(strings.TrimPrefix(r.logicalPath, "/"), AssetsManifestEntry{
+		URL:       r.target.RelPermalink(),
+		Integrity: integrity,
+	})
+}
+
+// addToPublishedResources tracks the resource as published with url.
+func (r *resourceAdapter) addToPublishedResources(url string) {
+	if r.transformationsErr != nil {
+		return
+	}
+	r.spec.PublishedResources.add(url, func() PublishedResource {
+		var integrity string
+		if m, ok := r.target.Data().(map[string]interface{}); ok {
+			integrity = cast.ToString(m["Integrity"])
+		}
+		return PublishedResource{MediaType: r.target.MediaType(), Integrity: integrity}
+	})
 }
 
 func (r *resourceAdapter) Resize(spec string) (resource.Image, error) {
@@ -266,6 +343,9 @@ func (r resourceAdapter) Transform(t ...ResourceTransformation) (ResourceTransfo
 }
 
 func (r *resourceAdapter) Width() int {
+	if v, ok := r.getVideoOpsIfVideo(); ok {
+		return v.Width()
+	}
 	return r.getImageOps().Width()
 }
 
@@ -282,6 +362,23 @@ func (r *resourceAdapter) getImageOps() resource.ImageOps {
 	return img
 }
 
+func (r *resourceAdapter) getVideoOps() resource.VideoOps {
+	v, ok := r.getVideoOpsIfVideo()
+	if !ok {
+		panic(fmt.Sprintf("%T is not a video", r.target))
+	}
+	return v
+}
+
+func (r *resourceAdapter) getVideoOpsIfVideo() (resource.VideoOps, bool) {
+	v, ok := r.target.(resource.VideoOps)
+	if !ok {
+		return nil, false
+	}
+	r.init(false, false)
+	return v, true
+}
+
 func (r *resourceAdapter) getMetaAssigner() metaAssigner {
 	return r.target
 }
@@ -316,6 +413,41 @@ func (r *resourceAdapter) TransformationKey() string {
 	return r.spec.ResourceCache.cleanKey(base) + "_" + helpers.MD5String(key)
 }
 
+// persistKey returns the file cache key to persist the result of the
+// transformations across builds with, or empty if not persisted. The key
+// covers the transformations, the content read from content and the other
+// files the transformations may depend on.
+func (r *resourceAdapter) persistKey(key string, content io.ReadSeeker) (string, error) {
+	if !r.spec.BuildConfig.PersistTransformations {
+		return "", nil
+	}
+
+	var persist bool
+	for _, tr := range r.transformations {
+		if transformationsToPersist[tr.Key().Name] {
+			persist = true
+			break
+		}
+	}
+	if !persist {
+		return "", nil
+	}
+
+	inputsHash, err := r.spec.ResourceCache.getInputsHash()
+	if err != nil {
+		return "", err
+	}
+	contentHash, err := helpers.MD5FromReader(content)
+	if err != nil {
+		return "", err
+	}
+	if _, err := content.Seek(0, 0); err != nil {
+		return "", err
+	}
+
+	return key + "_p" + helpers.MD5String(contentHash+inputsHash), nil
+}
+
 func (r *resourceAdapter) transform(publish, setContent bool) error {
 	cache := r.spec.ResourceCache
 
@@ -328,18 +460,31 @@ func (r *resourceAdapter) transform(publish, setContent bool) error {
 		return nil
 	}
 
-	// Acquire a write lock for the named transformation.
-	cache.nlocker.Lock(key)
-	// Check the cache again.
-	cached, found = cache.get(key)
-	if found {
-		r.resourceAdapterInner = cached.(*resourceAdapterInner)
-		cache.nlocker.Unlock(key)
-		return nil
+	// Concurrent requests for the same transformation chain (e.g. from a
+	// partial used on many pages) share one execution and its result,
+	// including any error.
+	v, err, _ := cache.transformations.Do(key, func() (interface{}, error) {
+		// Check the cache again.
+		if cached, found := cache.get(key); found {
+			return cached, nil
+		}
+		if err := r.doTransform(key, publish, setContent); err != nil {
+			return nil, err
+		}
+		cache.set(key, r.resourceAdapterInner)
+		return r.resourceAdapterInner, nil
+	})
+	if err != nil {
+		return err
 	}
 
-	defer cache.nlocker.Unlock(key)
-	defer cache.set(key, r.resourceAdapterInner)
+	r.resourceAdapterInner = v.(*resourceAdapterInner)
+
+	return nil
+}
+
+func (r *resourceAdapter) doTransform(key string, publish, setContent bool) error {
+	cache := r.spec.ResourceCache
 
 	b1 := bp.GetBuffer()
 	b2 := bp.GetBuffer()
@@ -375,9 +520,28 @@ func (r *resourceAdapter) transform(publish, setContent bool) error {
 	counter := 0
 	writeToFileCache := false
 
+	// The identifier added by any fingerprint transformation.
+	var fingerprint string
+
 	var transformedContentr io.Reader
 
-	for i, tr := range r.transformations {
+	transformations := r.transformations
+
+	// The file cache key of the result persisted across builds, if any.
+	persistKey, err := r.persistKey(key, contentrc)
+	if err != nil {
+		return err
+	}
+	if persistKey != "" {
+		if f := r.target.tryTransformedFileCache(persistKey, updates); f != nil {
+			transformedContentr = f
+			updates.sourceFs = cache.fileCache.Fs
+			defer f.Close()
+			transformations = nil
+		}
+	}
+
+	for i, tr := range transformations {
 		if i != 0 {
 			tctx.InMediaType = tctx.OutMediaType
 		}
@@ -416,7 +580,7 @@ func (r *resourceAdapter) transform(publish, setContent bool) error {
 				} else if tr.Key().Name == "tocss" {
 					errMsg = ". Check your Hugo installation; you need the extended version to build SCSS/SASS."
 				} else if tr.Key().Name == "tocss-dart" {
-					errMsg = ". You need dart-sass-embedded in your system $PATH."
+					errMsg = ". You need dart-sass-embedded in your system $PATH, Hugo does not bundle Dart Sass. Install it from https://github.com/sass/dart-sass-embedded/releases or use the \"libsass\" transpiler."
 
 				} else if tr.Key().Name == "babel" {
 					errMsg = ". You need to install Babel, see https://gohugo.io/hugo-pipes/babel/"
@@ -463,6 +627,9 @@ func (r *resourceAdapter) transform(publish, setContent bool) error {
 		}
 
 		if tctx.OutPath != "" {
+			if tr.Key().Name == "fingerprint" {
+				fingerprint = insertedPart(tctx.InPath, tctx.OutPath)
+			}
 			tctx.InPath = tctx.OutPath
 			tctx.OutPath = ""
 		}
@@ -470,6 +637,11 @@ func (r *resourceAdapter) transform(publish, setContent bool) error {
 
 	if transformedContentr == nil {
 		updates.updateFromCtx(tctx)
+		updates.logicalPath = logicalPath(updates.targetPath, fingerprint)
+
+		if err := tctx.publishSourceMap(updates.targetPath); err != nil {
+			return err
+		}
 	}
 
 	var publishwriters []io.WriteCloser
@@ -483,9 +655,13 @@ func (r *resourceAdapter) transform(publish, setContent bool) error {
 	}
 
 	if transformedContentr == nil {
-		if writeToFileCache {
+		if writeToFileCache || persistKey != "" {
+			fileCacheKey := key
+			if persistKey != "" {
+				fileCacheKey = persistKey
+			}
 			// Also write it to the cache
-			fi, metaw, err := cache.writeMeta(key, updates.toTransformedResourceMetadata())
+			fi, metaw, err := cache.writeMeta(fileCacheKey, updates.toTransformedResourceMetadata())
 			if err != nil {
 				return err
 			}
@@ -507,7 +683,7 @@ func (r *resourceAdapter) transform(publish, setContent bool) error {
 	// Also write it to memory
 	var contentmemw *bytes.Buffer
 
-	setContent = setContent || !writeToFileCache
+	setContent = setContent || !(writeToFileCache || persistKey != "")
 
 	if setContent {
 		contentmemw = bp.GetBuffer()
@@ -532,6 +708,11 @@ func (r *resourceAdapter) transform(publish, setContent bool) error {
 		return err
 	}
 	r.target = newTarget
+	r.logicalPath = updates.logicalPath
+	if r.logicalPath == "" {
+		// Cached before the logical path was stored.
+		r.logicalPath = updates.targetPath
+	}
 
 	return nil
 }
@@ -573,6 +754,10 @@ type resourceAdapterInner struct {
 
 	spec *Spec
 
+	// The target path of the transformed resource without any fingerprint,
+	// the name of it in the assets manifest.
+	logicalPath string
+
 	// Handles publishing (to /public) if needed.
 	*publishOnce
 }
@@ -596,6 +781,7 @@ type transformationUpdate struct {
 	sourceFilename *string
 	sourceFs       afero.Fs
 	targetPath     string
+	logicalPath    string
 	mediaType      media.Type
 	data           map[string]interface{}
 
@@ -608,9 +794,10 @@ func (u *transformationUpdate) isContentChanged() bool {
 
 func (u *transformationUpdate) toTransformedResourceMetadata() transformedResourceMetadata {
 	return transformedResourceMetadata{
-		MediaTypeV: u.mediaType.Type(),
-		Target:     u.targetPath,
-		MetaData:   u.data,
+		MediaTypeV:  u.mediaType.Type(),
+		Target:      u.targetPath,
+		LogicalPath: u.logicalPath,
+		MetaData:    u.data,
 	}
 }
 
@@ -623,9 +810,10 @@ func (u *transformationUpdate) updateFromCtx(ctx *ResourceTransformationCtx) {
 
 // We will persist this information to disk.
 type transformedResourceMetadata struct {
-	Target     string                 `json:"Target"`
-	MediaTypeV string                 `json:"MediaType"`
-	MetaData   map[string]interface{} `json:"Data"`
+	Target      string                 `json:"Target"`
+	LogicalPath string                 `json:"LogicalPath,omitempty"`
+	MediaTypeV  string                 `json:"MediaType"`
+	MetaData    map[string]interface{} `json:"Data"`
 }
 
 // contentReadSeekerCloser returns a ReadSeekerCloser if possible for a given Resource.
@@ -15,13 +15,16 @@ package resources
 
 import (
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"io"
 	"path/filepath"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/gohugoio/hugo/htesting"
 
@@ -314,6 +317,34 @@ func TestTransform(t *testing.T) {
 		assertNoDuplicateWrites(c, spec)
 	})
 
+	c.Run("Source map", func(c *qt.C) {
+		c.Parallel()
+
+		spec := newTestResourceSpec(specDescriptor{c: c})
+
+		transformation := &testTransformation{
+			name: "sourcemap",
+			transform: func(ctx *ResourceTransformationCtx) error {
+				in := helpers.ReaderToString(ctx.From)
+				ctx.OutMediaType = media.JavascriptType
+				ctx.ReplaceOutPathExtension(".js")
+				return ctx.WriteSourceMapped([]byte(in), []byte(`{"version":3}`), SourceMapOptions{SourceMap: "external"})
+			},
+		}
+
+		r := createTransformer(spec, "js/f1.txt", "let a = 1;")
+
+		tr, err := r.Transform(transformation, createContentReplacer("t1", "1", "2"))
+		c.Assert(err, qt.IsNil)
+		content, err := tr.(resource.ContentProvider).Content()
+		c.Assert(err, qt.IsNil)
+
+		c.Assert(content, qt.Equals, "let a = 2;\n//# sourceMappingURL=f1.js.map\n")
+		c.Assert(tr.RelPermalink(), qt.Equals, "/js/f1.t1.js")
+		assertShouldExist(c, spec, "public/js/f1.t1.js", true)
+		assertShouldExist(c, spec, "public/js/f1.js.map", true)
+	})
+
 	c.Run("Content many", func(c *qt.C) {
 		c.Parallel()
 
@@ -423,6 +454,62 @@ func TestTransform(t *testing.T) {
 
 		assertNoDuplicateWrites(c, spec)
 	})
+
+	c.Run("Concurrent, same transformation", func(c *qt.C) {
+		spec := newTestResourceSpec(specDescriptor{c: c})
+
+		var counter, failCounter int32
+		start := make(chan struct{})
+
+		newTransformation := func(name string, counter *int32, fail bool) ResourceTransformation {
+			return &testTransformation{
+				name: name,
+				transform: func(ctx *ResourceTransformationCtx) error {
+					atomic.AddInt32(counter, 1)
+					<-start
+					if fail {
+						return errors.New("failed")
+					}
+					in := helpers.ReaderToString(ctx.From)
+					fmt.Fprint(ctx.To, strings.Replace(in, "red", "blue", 1))
+					return nil
+				},
+			}
+		}
+
+		r1 := createTransformer(spec, "f1.txt", "color is red")
+		r2 := createTransformer(spec, "f2.txt", "color is red")
+
+		var wg sync.WaitGroup
+
+		for i := 0; i < 20; i++ {
+			wg.Add(2)
+			// A new transformation per call, as when executed from
+			// different templates.
+			go func() {
+				defer wg.Done()
+				tr, err := r1.Transform(newTransformation("test", &counter, false))
+				c.Assert(err, qt.IsNil)
+				content, err := tr.(resource.ContentProvider).Content()
+				c.Assert(err, qt.IsNil)
+				c.Assert(content, qt.Equals, "color is blue")
+			}()
+			go func() {
+				defer wg.Done()
+				tr, err := r2.Transform(newTransformation("fail", &failCounter, true))
+				c.Assert(err, qt.IsNil)
+				_, err = tr.(resource.ContentProvider).Content()
+				c.Assert(err, qt.ErrorMatches, ".*failed")
+			}()
+		}
+
+		time.Sleep(20 * time.Millisecond)
+		close(start)
+		wg.Wait()
+
+		c.Assert(atomic.LoadInt32(&counter), qt.Equals, int32(1))
+		c.Assert(atomic.LoadInt32(&failCounter), qt.Equals, int32(1))
+	})
 }
 
 type testTransformation struct {
@@ -0,0 +1,219 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gohugoio/hugo/common/paths"
+	"github.com/gohugoio/hugo/helpers"
+	"github.com/gohugoio/hugo/media"
+	"github.com/gohugoio/hugo/resources/images"
+	"github.com/gohugoio/hugo/resources/resource"
+	"github.com/gohugoio/hugo/resources/videos"
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+)
+
+// Increment to invalidate the cached video metadata and posters.
+const videoVersionNumber = 1
+
+var (
+	_ resource.Video  = (*videoResource)(nil)
+	_ resource.Cloner = (*videoResource)(nil)
+)
+
+// videoResource represents a video resource. The metadata is read and
+// the poster frames extracted with the spec's VideoProcessor, and both are
+// cached in the image file cache.
+type videoResource struct {
+	metaInit    sync.Once
+	metaInitErr error
+	meta        videos.Metadata
+
+	baseResource
+}
+
+func (v *videoResource) Width() int {
+	return v.getMeta().Width
+}
+
+func (v *videoResource) Height() int {
+	return v.getMeta().Height
+}
+
+func (v *videoResource) Duration() time.Duration {
+	return v.getMeta().Duration
+}
+
+func (v *videoResource) Poster() (resource.Image, error) {
+	return v.poster(v.getSpec().videoConfig.PosterPosition(v.getMeta()))
+}
+
+func (v *videoResource) PosterAt(at string) (resource.Image, error) {
+	d, err := cast.ToDurationE(at)
+	if err != nil || d < 0 {
+		return nil, errors.Errorf("invalid poster position %q", at)
+	}
+	return v.poster(d)
+}
+
+func (v *videoResource) Clone() resource.Resource {
+	return &videoResource{baseResource: v.baseResource.Clone().(baseResource)}
+}
+
+func (v *videoResource) cloneWithUpdates(u *transformationUpdate) (baseResource, error) {
+	base, err := v.baseResource.cloneWithUpdates(u)
+	if err != nil {
+		return nil, err
+	}
+	if u.isContentChanged() {
+		return base, nil
+	}
+	return &videoResource{baseResource: base}, nil
+}
+
+func (v *videoResource) getMeta() videos.Metadata {
+	v.metaInit.Do(func() {
+		key := v.videoCacheKey("json")
+
+		_, b, err := v.getSpec().FileCaches.ImageCache().GetOrCreateBytes(key, func() ([]byte, error) {
+			var m videos.Metadata
+			err := v.withFilename(func(filename string) error {
+				var err error
+				m, err = v.getSpec().VideoProcessor.Probe(filename)
+				return err
+			})
+			if err != nil {
+				return nil, err
+			}
+			return json.Marshal(m)
+		})
+		if err != nil {
+			v.metaInitErr = err
+			return
+		}
+
+		v.metaInitErr = json.Unmarshal(b, &v.meta)
+	})
+
+	if v.metaInitErr != nil {
+		panic(fmt.Sprintf("video metadata init failed for %q: %s", v.Key(), v.metaInitErr))
+	}
+
+	return v.meta
+}
+
+func (v *videoResource) poster(at time.Duration) (resource.Image, error) {
+	spec := v.getSpec()
+
+	df := v.getResourcePaths().relTargetDirFile
+	p1, _ := paths.FileAndExt(df.file)
+	h, _ := v.hash()
+	idStr := helpers.HashString(h, v.size(), videoVersionNumber, at)
+	targetPath := path.Join(df.dir, fmt.Sprintf("%s_poster_%s.jpg", p1, idStr))
+
+	r, err := spec.ResourceCache.GetOrCreate(targetPath, func() (resource.Resource, error) {
+		_, b, err := spec.FileCaches.ImageCache().GetOrCreateBytes(targetPath, func() ([]byte, error) {
+			var buf bytes.Buffer
+			err := v.withFilename(func(filename string) error {
+				return spec.VideoProcessor.Poster(filename, at, &buf)
+			})
+			if err != nil {
+				return nil, err
+			}
+			if buf.Len() == 0 {
+				return nil, errors.New("no poster frame extracted")
+			}
+			return buf.Bytes(), nil
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create poster for %q", v.Key())
+		}
+
+		content := string(b)
+		base, err := v.baseResource.cloneWithUpdates(&transformationUpdate{
+			content:    &content,
+			targetPath: targetPath,
+			mediaType:  media.JPEGType,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		ir := &imageResource{
+			Image:        images.NewImage(images.JPEG, spec.imaging, nil, base),
+			baseResource: base,
+		}
+		ir.root = ir
+
+		return newResourceAdapter(spec, true, ir), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	img, ok := r.(resource.Image)
+	if !ok {
+		return nil, errors.Errorf("poster for %q is not an image", v.Key())
+	}
+
+	return img, nil
+}
+
+func (v *videoResource) videoCacheKey(ext string) string {
+	df := v.getResourcePaths().relTargetDirFile
+	if fi := v.getFileInfo(); fi != nil {
+		df.dir = filepath.Dir(fi.Meta().Path)
+	}
+	p1, _ := paths.FileAndExt(df.file)
+	h, _ := v.hash()
+	idStr := helpers.HashString(h, v.size(), videoVersionNumber)
+	return path.Join(df.dir, fmt.Sprintf("%s_%s.%s", p1, idStr, ext))
+}
+
+// withFilename calls f with the filename of a copy of the video on disk,
+// as the processor may need to seek.
+func (v *videoResource) withFilename(f func(filename string) error) error {
+	src, err := v.ReadSeekCloser()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	tmp, err := ioutil.TempFile("", "hugo-video*"+path.Ext(v.getResourcePaths().relTargetDirFile.file))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, src); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return f(tmp.Name())
+}
@@ -0,0 +1,113 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gohugoio/hugo/resources/resource"
+	"github.com/gohugoio/hugo/resources/videos"
+
+	qt "github.com/frankban/quicktest"
+)
+
+type testVideoProcessor struct {
+	probes  int
+	posters []time.Duration
+}
+
+func (p *testVideoProcessor) Probe(filename string) (videos.Metadata, error) {
+	p.probes++
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return videos.Metadata{}, err
+	}
+	if string(b) != "video" {
+		return videos.Metadata{}, io.ErrUnexpectedEOF
+	}
+	return videos.Metadata{Width: 1280, Height: 720, Duration: 10 * time.Second}, nil
+}
+
+func (p *testVideoProcessor) Poster(filename string, at time.Duration, w io.Writer) error {
+	p.posters = append(p.posters, at)
+	f, err := os.Open(filepath.FromSlash("testdata/sunset.jpg"))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(w, f)
+	return err
+}
+
+func TestVideo(t *testing.T) {
+	c := qt.New(t)
+
+	spec := newTestResourceSpec(specDescriptor{c: c})
+	processor := &testVideoProcessor{}
+	spec.VideoProcessor = processor
+
+	filename := filepath.Join(spec.WorkingDir, "clip.mp4")
+	writeToFs(t, spec.Fs.Source, filename, "video")
+
+	r, err := spec.New(ResourceSourceDescriptor{Fs: spec.Fs.Source, TargetPaths: newTargetPaths("/a"), LazyPublish: true, RelTargetFilename: "clip.mp4", SourceFilename: filename})
+	c.Assert(err, qt.IsNil)
+
+	c.Assert(r.ResourceType(), qt.Equals, "video")
+	video, ok := r.(resource.Video)
+	c.Assert(ok, qt.IsTrue)
+
+	c.Assert(video.Width(), qt.Equals, 1280)
+	c.Assert(video.Height(), qt.Equals, 720)
+	c.Assert(video.Duration(), qt.Equals, 10*time.Second)
+	c.Assert(processor.probes, qt.Equals, 1)
+
+	poster, err := video.Poster()
+	c.Assert(err, qt.IsNil)
+	c.Assert(poster.Width(), qt.Equals, 900)
+	c.Assert(poster.MediaType().Type(), qt.Equals, "image/jpeg")
+	c.Assert(poster.RelPermalink(), qt.Matches, `/a/clip_poster_\w+\.jpg`)
+	c.Assert(processor.posters, qt.DeepEquals, []time.Duration{time.Second})
+
+	poster2, err := video.Poster()
+	c.Assert(err, qt.IsNil)
+	c.Assert(poster2.RelPermalink(), qt.Equals, poster.RelPermalink())
+	c.Assert(len(processor.posters), qt.Equals, 1)
+
+	posterAt, err := video.PosterAt("2.5s")
+	c.Assert(err, qt.IsNil)
+	c.Assert(posterAt.RelPermalink(), qt.Not(qt.Equals), poster.RelPermalink())
+	c.Assert(processor.posters[1], qt.Equals, 2500*time.Millisecond)
+
+	resized, err := posterAt.Resize("300x")
+	c.Assert(err, qt.IsNil)
+	c.Assert(resized.Width(), qt.Equals, 300)
+	assertImageFile(c, spec.BaseFs.PublishFs, resized.RelPermalink(), 300, 187)
+
+	_, err = video.PosterAt("invalid")
+	c.Assert(err, qt.Not(qt.IsNil))
+
+	// The metadata is cached on disk.
+	spec2 := newTestResourceSpec(specDescriptor{c: c, fs: spec.Fs.Source})
+	processor2 := &testVideoProcessor{}
+	spec2.VideoProcessor = processor2
+	r2, err := spec2.New(ResourceSourceDescriptor{Fs: spec.Fs.Source, TargetPaths: newTargetPaths("/a"), RelTargetFilename: "clip.mp4", SourceFilename: filename})
+	c.Assert(err, qt.IsNil)
+	c.Assert(r2.(resource.Video).Duration(), qt.Equals, 10*time.Second)
+	c.Assert(processor2.probes, qt.Equals, 0)
+}
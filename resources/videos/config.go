@@ -0,0 +1,66 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package videos
+
+import (
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+)
+
+var defaultConfig = Config{
+	FFmpeg:   "ffmpeg",
+	FFprobe:  "ffprobe",
+	PosterAt: "1s",
+}
+
+// Config configures the video processing, the video section in the site
+// config.
+type Config struct {
+	// The ffmpeg and ffprobe executables used to extract poster frames
+	// and to read the video metadata. Default "ffmpeg" and "ffprobe",
+	// looked up in PATH.
+	FFmpeg  string
+	FFprobe string
+
+	// The default position of the poster frame, e.g. "2.5s".
+	// If the video is shorter, the middle frame is used.
+	// Default is "1s".
+	PosterAt string
+
+	posterAt time.Duration
+}
+
+// DecodeConfig creates a video config from the given map.
+func DecodeConfig(m map[string]interface{}) (Config, error) {
+	c := defaultConfig
+
+	if err := mapstructure.WeakDecode(m, &c); err != nil {
+		return c, errors.Wrap(err, "failed to decode video config")
+	}
+
+	if c.FFmpeg == "" || c.FFprobe == "" {
+		return c, errors.New("video: ffmpeg and ffprobe must be set")
+	}
+
+	posterAt, err := cast.ToDurationE(c.PosterAt)
+	if err != nil || posterAt < 0 {
+		return c, errors.Errorf("video: invalid posterAt %q", c.PosterAt)
+	}
+	c.posterAt = posterAt
+
+	return c, nil
+}
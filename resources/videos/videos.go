@@ -0,0 +1,152 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package videos reads video metadata and extracts poster frames.
+package videos
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/gohugoio/hugo/common/hexec"
+	"github.com/gohugoio/hugo/config"
+	"github.com/pkg/errors"
+)
+
+// Metadata holds the metadata of a video.
+type Metadata struct {
+	Width    int
+	Height   int
+	Duration time.Duration
+}
+
+// Processor reads video metadata and extracts poster frames.
+type Processor interface {
+	// Probe reads the metadata of the video in filename.
+	Probe(filename string) (Metadata, error)
+
+	// Poster writes the frame at the given position in the video in
+	// filename to w as a JPEG image.
+	Poster(filename string, at time.Duration, w io.Writer) error
+}
+
+// PosterPosition returns the position of the poster frame in a video with
+// the given metadata.
+func (c Config) PosterPosition(m Metadata) time.Duration {
+	if m.Duration > 0 && c.posterAt >= m.Duration {
+		return m.Duration / 2
+	}
+	return c.posterAt
+}
+
+// NewProcessor creates a Processor that runs the ffprobe and ffmpeg
+// executables in cfg.
+func NewProcessor(cfg Config, execCfg config.Provider) Processor {
+	return &ffmpegProcessor{cfg: cfg, execCfg: execCfg}
+}
+
+type ffmpegProcessor struct {
+	cfg Config
+
+	// The build configuration with the limits of the external tools.
+	execCfg config.Provider
+}
+
+func (p *ffmpegProcessor) Probe(filename string) (Metadata, error) {
+	var m Metadata
+
+	var stdout bytes.Buffer
+	if err := p.run(p.cfg.FFprobe, &stdout,
+		"-v", "error",
+		"-select_streams", "v:0",
+		"-show_entries", "stream=width,height:format=duration",
+		"-of", "json",
+		filename); err != nil {
+		return m, err
+	}
+
+	return parseProbeOutput(stdout.Bytes())
+}
+
+func (p *ffmpegProcessor) Poster(filename string, at time.Duration, w io.Writer) error {
+	return p.run(p.cfg.FFmpeg, w,
+		"-v", "error",
+		"-ss", strconv.FormatFloat(at.Seconds(), 'f', 3, 64),
+		"-i", filename,
+		"-frames:v", "1",
+		"-f", "image2pipe",
+		"-vcodec", "mjpeg",
+		"-")
+}
+
+func (p *ffmpegProcessor) run(name string, stdout io.Writer, arg ...string) error {
+	cmd, done, err := hexec.Command(p.execCfg, "", name, arg...)
+	if err != nil {
+		if _, ok := err.(*exec.Error); ok {
+			return errors.Wrapf(err, "video: %s not found", name)
+		}
+		return err
+	}
+	defer done()
+
+	var stderr bytes.Buffer
+	cmd.Stdout = stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return errors.Errorf("video: %s failed: %s: %s", name, err, bytes.TrimSpace(stderr.Bytes()))
+	}
+
+	return nil
+}
+
+// parseProbeOutput parses the JSON written by ffprobe.
+func parseProbeOutput(b []byte) (Metadata, error) {
+	var m Metadata
+
+	var probe struct {
+		Streams []struct {
+			Width  int `json:"width"`
+			Height int `json:"height"`
+		} `json:"streams"`
+		Format struct {
+			Duration string `json:"duration"`
+		} `json:"format"`
+	}
+
+	if err := json.Unmarshal(b, &probe); err != nil {
+		return m, errors.Wrap(err, "video: failed to parse ffprobe output")
+	}
+
+	if len(probe.Streams) == 0 {
+		return m, errors.New("video: no video stream found")
+	}
+
+	m.Width = probe.Streams[0].Width
+	m.Height = probe.Streams[0].Height
+
+	if probe.Format.Duration != "" {
+		seconds, err := strconv.ParseFloat(probe.Format.Duration, 64)
+		if err != nil {
+			return m, fmt.Errorf("video: invalid duration %q", probe.Format.Duration)
+		}
+		m.Duration = time.Duration(seconds * float64(time.Second))
+	}
+
+	return m, nil
+}
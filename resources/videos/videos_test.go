@@ -0,0 +1,74 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package videos
+
+import (
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestDecodeConfig(t *testing.T) {
+	c := qt.New(t)
+
+	cfg, err := DecodeConfig(nil)
+	c.Assert(err, qt.IsNil)
+	c.Assert(cfg.FFmpeg, qt.Equals, "ffmpeg")
+	c.Assert(cfg.FFprobe, qt.Equals, "ffprobe")
+	c.Assert(cfg.posterAt, qt.Equals, time.Second)
+
+	cfg, err = DecodeConfig(map[string]interface{}{
+		"ffmpeg":   "/usr/local/bin/ffmpeg",
+		"posterAt": "2500ms",
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(cfg.FFmpeg, qt.Equals, "/usr/local/bin/ffmpeg")
+	c.Assert(cfg.FFprobe, qt.Equals, "ffprobe")
+	c.Assert(cfg.posterAt, qt.Equals, 2500*time.Millisecond)
+
+	_, err = DecodeConfig(map[string]interface{}{"posterAt": "soon"})
+	c.Assert(err, qt.Not(qt.IsNil))
+	_, err = DecodeConfig(map[string]interface{}{"ffprobe": ""})
+	c.Assert(err, qt.Not(qt.IsNil))
+}
+
+func TestPosterPosition(t *testing.T) {
+	c := qt.New(t)
+
+	cfg, err := DecodeConfig(map[string]interface{}{"posterAt": "3s"})
+	c.Assert(err, qt.IsNil)
+
+	c.Assert(cfg.PosterPosition(Metadata{Duration: 10 * time.Second}), qt.Equals, 3*time.Second)
+	c.Assert(cfg.PosterPosition(Metadata{Duration: 2 * time.Second}), qt.Equals, time.Second)
+	c.Assert(cfg.PosterPosition(Metadata{}), qt.Equals, 3*time.Second)
+}
+
+func TestParseProbeOutput(t *testing.T) {
+	c := qt.New(t)
+
+	m, err := parseProbeOutput([]byte(`{
+    "programs": [],
+    "streams": [{"width": 1920, "height": 1080}],
+    "format": {"duration": "12.480000"}
+}`))
+	c.Assert(err, qt.IsNil)
+	c.Assert(m, qt.DeepEquals, Metadata{Width: 1920, Height: 1080, Duration: 12480 * time.Millisecond})
+
+	_, err = parseProbeOutput([]byte(`{"streams": [], "format": {}}`))
+	c.Assert(err, qt.ErrorMatches, "video: no video stream found")
+
+	_, err = parseProbeOutput([]byte(`{"streams": [{"width": 1}], "format": {"duration": "N/A"}}`))
+	c.Assert(err, qt.Not(qt.IsNil))
+}
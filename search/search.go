@@ -0,0 +1,265 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package search builds client side search indexes.
+package search
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/gohugoio/hugo/common/maps"
+	"github.com/kljensen/snowball"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+)
+
+// DefaultConfig is the default search index config.
+var DefaultConfig = Config{
+	Stem: true,
+	Fields: FieldConfigs{
+		FieldConfig{Name: "title", Weight: 10, Store: true},
+		FieldConfig{Name: "tags", Weight: 5, Store: true},
+		FieldConfig{Name: "headings", Weight: 3, Store: true},
+		FieldConfig{Name: "summary", Weight: 1, Store: true},
+	},
+}
+
+/*
+Config configures the search index written by the SearchIndex output format.
+
+An example site config.toml:
+
+	[searchIndex]
+	stem = true
+	[[searchIndex.fields]]
+	name = "title"
+	weight = 10
+	store = true
+	[[searchIndex.fields]]
+	name = "content"
+	weight = 1
+*/
+type Config struct {
+	// Whether to reduce the tokens to their stems using the stemmer for the
+	// site language, when available.
+	Stem bool
+
+	// The fields to index. The built-in fields are title, summary, headings
+	// and content. Any other name will be looked up in the page params,
+	// e.g. tags.
+	Fields FieldConfigs
+}
+
+// FieldConfig configures a field in the search index.
+type FieldConfig struct {
+	// The field name.
+	Name string
+
+	// The weight of a token found in this field.
+	Weight int
+
+	// Whether to also store the field value in the document list,
+	// e.g. to display it in the search results.
+	Store bool
+}
+
+// FieldConfigs holds a set of field configs.
+type FieldConfigs []FieldConfig
+
+// DecodeConfig creates a search index config from the given map.
+func DecodeConfig(m maps.Params) (Config, error) {
+	if len(m) == 0 {
+		return DefaultConfig, nil
+	}
+
+	c := Config{Stem: DefaultConfig.Stem}
+
+	if err := mapstructure.WeakDecode(m, &c); err != nil {
+		return c, err
+	}
+
+	if len(c.Fields) == 0 {
+		c.Fields = DefaultConfig.Fields
+	}
+
+	for i, f := range c.Fields {
+		if f.Name == "" {
+			return c, errors.New("search index field must have a name")
+		}
+		c.Fields[i].Name = strings.ToLower(f.Name)
+	}
+
+	return c, nil
+}
+
+// Posting is a document ID and the score of a token in that document.
+type Posting [2]int
+
+// Index is a search index for one language.
+// It's meant to be serialized to JSON and consumed by a client side search library.
+type Index struct {
+	// The language code.
+	Lang string `json:"lang"`
+
+	// Whether the tokens are stemmed.
+	Stemmed bool `json:"stemmed"`
+
+	// The field weights.
+	Fields map[string]int `json:"fields"`
+
+	// The documents, with their stored fields. The ID of a document
+	// is its position in this list.
+	Documents []map[string]interface{} `json:"documents"`
+
+	// Maps a token to the documents it was found in, ordered by score.
+	Tokens map[string][]Posting `json:"tokens"`
+
+	cfg  Config
+	stem func(s string) string
+}
+
+// NewIndex creates a new Index for the given language.
+func NewIndex(cfg Config, lang string) *Index {
+	idx := &Index{
+		Lang:      lang,
+		Fields:    make(map[string]int),
+		Documents: make([]map[string]interface{}, 0),
+		Tokens:    make(map[string][]Posting),
+		cfg:       cfg,
+	}
+
+	for _, f := range cfg.Fields {
+		idx.Fields[f.Name] = f.Weight
+	}
+
+	if cfg.Stem {
+		if stemmerLang := stemmerLanguage(lang); stemmerLang != "" {
+			idx.Stemmed = true
+			idx.stem = func(s string) string {
+				stemmed, err := snowball.Stem(s, stemmerLang, false)
+				if err != nil {
+					return s
+				}
+				return stemmed
+			}
+		}
+	}
+
+	return idx
+}
+
+// Add adds a document to the index. The values are keyed by field name and
+// must be either a string or a string slice. Fields not configured will be ignored.
+func (idx *Index) Add(permalink string, values map[string]interface{}) {
+	id := len(idx.Documents)
+	doc := map[string]interface{}{
+		"id":        id,
+		"permalink": permalink,
+	}
+
+	scores := make(map[string]int)
+
+	for _, f := range idx.cfg.Fields {
+		v, found := values[f.Name]
+		if !found {
+			continue
+		}
+
+		var vals []string
+		switch vv := v.(type) {
+		case string:
+			vals = []string{vv}
+		case []string:
+			vals = vv
+		default:
+			continue
+		}
+
+		if f.Store {
+			doc[f.Name] = v
+		}
+
+		for _, v := range vals {
+			for _, token := range idx.Tokenize(v) {
+				scores[token] += f.Weight
+			}
+		}
+	}
+
+	for token, score := range scores {
+		if score <= 0 {
+			continue
+		}
+		idx.Tokens[token] = append(idx.Tokens[token], Posting{id, score})
+	}
+
+	idx.Documents = append(idx.Documents, doc)
+}
+
+// Finish sorts the postings by score. It should be called when all documents are added.
+func (idx *Index) Finish() *Index {
+	for _, postings := range idx.Tokens {
+		sort.SliceStable(postings, func(i, j int) bool {
+			return postings[i][1] > postings[j][1]
+		})
+	}
+	return idx
+}
+
+// Tokenize splits s into lower case tokens, stemmed if enabled.
+func (idx *Index) Tokenize(s string) []string {
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	tokens := make([]string, 0, len(words))
+	for _, word := range words {
+		word = strings.ToLower(word)
+		if idx.stem != nil {
+			word = idx.stem(word)
+		}
+		if word != "" {
+			tokens = append(tokens, word)
+		}
+	}
+
+	return tokens
+}
+
+// stemmerLanguage returns the stemmer language to use for the given
+// language code, or empty if not supported.
+func stemmerLanguage(lang string) string {
+	lang = strings.ToLower(lang)
+	if i := strings.IndexAny(lang, "-_"); i != -1 {
+		lang = lang[:i]
+	}
+
+	switch lang {
+	case "en":
+		return "english"
+	case "es":
+		return "spanish"
+	case "fr":
+		return "french"
+	case "ru":
+		return "russian"
+	case "sv":
+		return "swedish"
+	case "no", "nb", "nn":
+		return "norwegian"
+	}
+
+	return ""
+}
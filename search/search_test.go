@@ -0,0 +1,100 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package search
+
+import (
+	"testing"
+
+	"github.com/gohugoio/hugo/common/maps"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestDecodeConfig(t *testing.T) {
+	c := qt.New(t)
+
+	conf, err := DecodeConfig(nil)
+	c.Assert(err, qt.IsNil)
+	c.Assert(conf, qt.DeepEquals, DefaultConfig)
+
+	conf, err = DecodeConfig(maps.Params{
+		"stem": false,
+		"fields": []interface{}{
+			map[string]interface{}{"name": "Title", "weight": 7, "store": true},
+			map[string]interface{}{"name": "content", "weight": 1},
+		},
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(conf.Stem, qt.Equals, false)
+	c.Assert(conf.Fields, qt.DeepEquals, FieldConfigs{
+		{Name: "title", Weight: 7, Store: true},
+		{Name: "content", Weight: 1},
+	})
+
+	_, err = DecodeConfig(maps.Params{
+		"fields": []interface{}{map[string]interface{}{"weight": 1}},
+	})
+	c.Assert(err, qt.Not(qt.IsNil))
+}
+
+func TestTokenize(t *testing.T) {
+	c := qt.New(t)
+
+	idx := NewIndex(Config{}, "en")
+	c.Assert(idx.Stemmed, qt.Equals, false)
+	c.Assert(idx.Tokenize("Running, the Hugo-sites!"), qt.DeepEquals, []string{"running", "the", "hugo", "sites"})
+
+	idx = NewIndex(Config{Stem: true}, "en-US")
+	c.Assert(idx.Stemmed, qt.Equals, true)
+	c.Assert(idx.Tokenize("Running sites"), qt.DeepEquals, []string{"run", "site"})
+
+	idx = NewIndex(Config{Stem: true}, "nb")
+	c.Assert(idx.Stemmed, qt.Equals, true)
+
+	// No stemmer available.
+	idx = NewIndex(Config{Stem: true}, "ja")
+	c.Assert(idx.Stemmed, qt.Equals, false)
+}
+
+func TestIndex(t *testing.T) {
+	c := qt.New(t)
+
+	idx := NewIndex(Config{
+		Stem: true,
+		Fields: FieldConfigs{
+			{Name: "title", Weight: 10, Store: true},
+			{Name: "tags", Weight: 5},
+			{Name: "summary", Weight: 1},
+		},
+	}, "en")
+
+	idx.Add("/a/", map[string]interface{}{
+		"title":   "Hugo Templates",
+		"summary": "About templating.",
+		"ignored": "Ignored field.",
+	})
+	idx.Add("/b/", map[string]interface{}{
+		"title":   "Other",
+		"tags":    []string{"templates", "go"},
+		"summary": "Templates with Go.",
+	})
+	idx.Finish()
+
+	c.Assert(idx.Documents, qt.HasLen, 2)
+	c.Assert(idx.Documents[0], qt.DeepEquals, map[string]interface{}{"id": 0, "permalink": "/a/", "title": "Hugo Templates"})
+	c.Assert(idx.Fields, qt.DeepEquals, map[string]int{"title": 10, "tags": 5, "summary": 1})
+	c.Assert(idx.Tokens["templat"], qt.DeepEquals, []Posting{{0, 11}, {1, 6}})
+	c.Assert(idx.Tokens["go"], qt.DeepEquals, []Posting{{1, 6}})
+	c.Assert(idx.Tokens["ignor"], qt.IsNil)
+}
@@ -39,14 +39,27 @@ func init() {
 
 // New returns a new instance of the collections-namespaced template functions.
 func New(deps *deps.Deps) *Namespace {
+	var indexKeys []string
+	if deps.Cfg != nil {
+		indexKeys = deps.Cfg.GetStringSlice("queryIndexes")
+	}
+
+	indexes := newWhereIndexes(indexKeys)
+	deps.BuildStartListeners.Add(
+		func() {
+			indexes.reset()
+		})
+
 	return &Namespace{
-		deps: deps,
+		deps:    deps,
+		indexes: indexes,
 	}
 }
 
 // Namespace provides template functions for the "collections" namespace.
 type Namespace struct {
-	deps *deps.Deps
+	deps    *deps.Deps
+	indexes *whereIndexes
 }
 
 // After returns all the items after the first N in a rangeable list.
@@ -590,6 +603,54 @@ func (ns *Namespace) Shuffle(seq interface{}) (interface{}, error) {
 	return shuffled.Interface(), nil
 }
 
+// SampleSeeded returns n pseudo-random items from seq selected by seed, e.g.
+// a date. The same seq and seed always give the same items in the same
+// order. For Pages, the result is the first n of .ShuffleSeeded seed.
+func (ns *Namespace) SampleSeeded(seed interface{}, n interface{}, seq interface{}) (interface{}, error) {
+	if seq == nil {
+		return nil, errors.New("seq must be provided")
+	}
+
+	limit, err := cast.ToIntE(n)
+	if err != nil {
+		return nil, err
+	}
+
+	if limit < 0 {
+		return nil, errors.New("sample count must be a non-negative integer")
+	}
+
+	seqv := reflect.ValueOf(seq)
+	seqv, isNil := indirect(seqv)
+	if isNil {
+		return nil, errors.New("can't iterate over a nil value")
+	}
+
+	switch seqv.Kind() {
+	case reflect.Array, reflect.Slice:
+		// okay
+	default:
+		return nil, errors.New("can't iterate over " + reflect.ValueOf(seq).Type().String())
+	}
+
+	if limit > seqv.Len() {
+		limit = seqv.Len()
+	}
+
+	typ := seqv.Type()
+	if typ.Kind() == reflect.Array {
+		typ = reflect.SliceOf(typ.Elem())
+	}
+
+	sampled := reflect.MakeSlice(typ, limit, limit)
+
+	for i, j := range collections.SeededPerm(seed, seqv.Len())[:limit] {
+		sampled.Index(i).Set(seqv.Index(j))
+	}
+
+	return sampled.Interface(), nil
+}
+
 // Slice returns a slice of all passed arguments.
 func (ns *Namespace) Slice(args ...interface{}) interface{} {
 	if len(args) == 0 {
@@ -729,6 +729,54 @@ func TestShuffleRandomising(t *testing.T) {
 	}
 }
 
+func TestSampleSeeded(t *testing.T) {
+	t.Parallel()
+	c := qt.New(t)
+	ns := New(&deps.Deps{})
+
+	seq := []string{"a", "b", "c", "d", "e", "f"}
+
+	for i, test := range []struct {
+		seed     interface{}
+		n        interface{}
+		seq      interface{}
+		expected int
+	}{
+		{"2021-06-01", 3, seq, 3},
+		{"2021-06-01", "2", seq, 2},
+		{42, 10, seq, 6},
+		{time.Now(), 0, seq, 0},
+		{"foo", 2, [3]int{1, 2, 3}, 2},
+		{"foo", 2, &seq, 2},
+		// errors
+		{"foo", -1, seq, -1},
+		{"foo", "a", seq, -1},
+		{"foo", 2, nil, -1},
+		{"foo", 2, "abc", -1},
+		{"foo", 2, (*[]string)(nil), -1},
+	} {
+		errMsg := qt.Commentf("[%d] %v", i, test)
+
+		result, err := ns.SampleSeeded(test.seed, test.n, test.seq)
+
+		if test.expected == -1 {
+			c.Assert(err, qt.Not(qt.IsNil), errMsg)
+			continue
+		}
+
+		c.Assert(err, qt.IsNil, errMsg)
+		c.Assert(reflect.ValueOf(result).Len(), qt.Equals, test.expected, errMsg)
+
+		again, _ := ns.SampleSeeded(test.seed, test.n, test.seq)
+		c.Assert(again, qt.DeepEquals, result, errMsg)
+	}
+
+	// A sample is a prefix of a larger sample with the same seed.
+	s2, _ := ns.SampleSeeded("foo", 2, seq)
+	s4, _ := ns.SampleSeeded("foo", 4, seq)
+	c.Assert(s2, qt.DeepEquals, s4.([]string)[:2])
+}
+
 // Also see tests in commons/collection.
 func TestSlice(t *testing.T) {
 	t.Parallel()
@@ -973,7 +1021,7 @@ func ToTstXIs(slice interface{}) []TstXI {
 func newDeps(cfg config.Provider) *deps.Deps {
 	l := langs.NewLanguage("en", cfg)
 	l.Set("i18nDir", "i18n")
-	cs, err := helpers.NewContentSpec(l, loggers.NewErrorLogger(), afero.NewMemMapFs())
+	cs, err := helpers.NewContentSpec(l, loggers.NewErrorLogger(), afero.NewMemMapFs(), nil)
 	if err != nil {
 		panic(err)
 	}
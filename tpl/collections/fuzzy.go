@@ -0,0 +1,170 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collections
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/gohugoio/hugo/common/maps"
+	hcompare "github.com/gohugoio/hugo/compare"
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/cast"
+)
+
+type fuzzyMatchOptions struct {
+	// The field, method or map key path to match against, e.g. "Title" or
+	// "Params.author". The default is to match the elements themselves.
+	Key string
+
+	// The minimum similarity, from 0 to 1, of the elements to return.
+	Threshold float64
+
+	// The maximum number of elements to return. 0 means no limit.
+	Limit int
+}
+
+// FuzzyMatch returns the elements in seq similar to query, the most similar
+// first, e.g. to suggest pages on a 404 page:
+//
+//	{{ range collections.FuzzyMatch site.RegularPages "hugo templats" (dict "key" "Title" "limit" 5) }}
+//
+// The options are key, the element path to match against, threshold, the
+// minimum similarity (default 0.5), and limit, the maximum number of
+// elements to return.
+//
+// The strings are compared case insensitively. Query is matched against the
+// whole value and against every run of the same number of words in it,
+// so "templats" matches "Hugo Templates".
+func (ns *Namespace) FuzzyMatch(seq, query interface{}, options ...interface{}) (interface{}, error) {
+	if seq == nil {
+		return nil, errors.New("sequence must be provided")
+	}
+
+	q, err := cast.ToStringE(query)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := fuzzyMatchOptions{Threshold: 0.5}
+	if len(options) > 0 {
+		if len(options) > 1 {
+			return nil, errors.New("too many arguments")
+		}
+		m, err := maps.ToStringMapE(options[0])
+		if err != nil {
+			return nil, err
+		}
+		if err := mapstructure.WeakDecode(m, &opts); err != nil {
+			return nil, err
+		}
+	}
+
+	seqv, isNil := indirect(reflect.ValueOf(seq))
+	if isNil {
+		return nil, errors.New("can't iterate over a nil value")
+	}
+
+	var sliceType reflect.Type
+	var values []reflect.Value
+	switch seqv.Kind() {
+	case reflect.Array, reflect.Slice:
+		sliceType = seqv.Type()
+		for i := 0; i < seqv.Len(); i++ {
+			values = append(values, seqv.Index(i))
+		}
+	case reflect.Map:
+		sliceType = reflect.SliceOf(seqv.Type().Elem())
+		keys := seqv.MapKeys()
+		// Make the order of equally similar values predictable.
+		sort.Slice(keys, func(i, j int) bool {
+			return cast.ToString(keys[i].Interface()) < cast.ToString(keys[j].Interface())
+		})
+		for _, k := range keys {
+			values = append(values, seqv.MapIndex(k))
+		}
+	default:
+		return nil, errors.New("can't match in " + reflect.ValueOf(seq).Type().String())
+	}
+
+	var path []string
+	if key := strings.Trim(opts.Key, "."); key != "" {
+		path = strings.Split(key, ".")
+	}
+
+	type match struct {
+		v     reflect.Value
+		score float64
+	}
+
+	q = strings.ToLower(strings.TrimSpace(q))
+	var matches []match
+
+	for _, v := range values {
+		kv := v
+		if path != nil {
+			kv, err = evaluatePath(v, path)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if !kv.IsValid() {
+			continue
+		}
+		s, err := cast.ToStringE(kv.Interface())
+		if err != nil {
+			continue
+		}
+		if score := fuzzyScore(q, strings.ToLower(s)); score >= opts.Threshold {
+			matches = append(matches, match{v: v, score: score})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+
+	if opts.Limit > 0 && len(matches) > opts.Limit {
+		matches = matches[:opts.Limit]
+	}
+
+	result := reflect.MakeSlice(sliceType, len(matches), len(matches))
+	for i, m := range matches {
+		result.Index(i).Set(m.v)
+	}
+
+	return result.Interface(), nil
+}
+
+// fuzzyScore returns the similarity of q to s or, if higher, to the most
+// similar run of the same number of words in s.
+func fuzzyScore(q, s string) float64 {
+	score := hcompare.Similarity(q, s)
+
+	qn := len(strings.Fields(q))
+	words := strings.Fields(s)
+	if qn == 0 || qn >= len(words) {
+		return score
+	}
+
+	for i := 0; i+qn <= len(words); i++ {
+		if ws := hcompare.Similarity(q, strings.Join(words[i:i+qn], " ")); ws > score {
+			score = ws
+		}
+	}
+
+	return score
+}
@@ -0,0 +1,75 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collections
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gohugoio/hugo/common/maps"
+	"github.com/gohugoio/hugo/deps"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	t.Parallel()
+	c := qt.New(t)
+
+	ns := New(&deps.Deps{})
+
+	type page struct {
+		Title  string
+		Params maps.Params
+	}
+
+	pages := []page{
+		{Title: "Getting Started", Params: maps.Params{"author": "Jane"}},
+		{Title: "Hugo Templates", Params: maps.Params{"author": "John"}},
+		{Title: "Template Lookup Order", Params: maps.Params{"author": "Joan"}},
+		{Title: "Hosting", Params: maps.Params{"author": "Bob"}},
+	}
+
+	for i, test := range []struct {
+		seq     interface{}
+		query   interface{}
+		options []interface{}
+		expect  interface{}
+	}{
+		{[]string{"hugo", "Jekyll", "hugs"}, "Hugo", nil, []string{"hugo", "hugs"}},
+		{[]string{"hugs", "hugo"}, "hugo", nil, []string{"hugo", "hugs"}},
+		{[]string{"hugo", "hugs"}, "hugo", []interface{}{map[string]interface{}{"threshold": 1}}, []string{"hugo"}},
+		{[]string{"hugo", "hugs"}, "hugo", []interface{}{map[string]interface{}{"limit": 1}}, []string{"hugo"}},
+		{[]string{"hugo", "hugs"}, "jekyll", nil, []string{}},
+		{map[string]string{"a": "hugs", "b": "hugo"}, "hugo", nil, []string{"hugo", "hugs"}},
+		{pages, "templats", []interface{}{map[string]interface{}{"key": "Title"}}, []page{pages[1], pages[2]}},
+		{pages, "hugo templats", []interface{}{map[string]interface{}{"key": ".Title", "limit": 1}}, []page{pages[1]}},
+		{pages, "jon", []interface{}{map[string]interface{}{"key": "Params.author", "threshold": 0.6}}, []page{pages[1], pages[2]}},
+		// Errors
+		{nil, "hugo", nil, false},
+		{"hugo", "hugo", nil, false},
+		{[]string{"hugo"}, "hugo", []interface{}{"Title"}, false},
+		{[]string{"hugo"}, "hugo", []interface{}{map[string]interface{}{}, map[string]interface{}{}}, false},
+	} {
+		errMsg := qt.Commentf("[%d] %v", i, test)
+
+		result, err := ns.FuzzyMatch(test.seq, test.query, test.options...)
+
+		if b, ok := test.expect.(bool); ok && !b {
+			c.Assert(err, qt.Not(qt.IsNil), errMsg)
+			continue
+		}
+
+		c.Assert(err, qt.IsNil, errMsg)
+		c.Assert(result, qt.DeepEquals, test.expect, errMsg)
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collections
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/gohugoio/hugo/resources/page"
+)
+
+// GroupBy groups the pages in seq by the value returned by the function
+// fname, e.g. "partial" with a partial returning the key. As in apply, args
+// equal to "." are replaced with the page. Args on the form ".Field.Path",
+// e.g. ".Title" or ".Params.category", are replaced with the value at that
+// path in the page. Pages with a nil key are left out.
+func (ns *Namespace) GroupBy(seq interface{}, fname string, args ...interface{}) (page.PagesGroup, error) {
+	if fname == "groupBy" || fname == "apply" {
+		return nil, errors.New("can't group by " + fname)
+	}
+
+	pages, err := page.ToPages(seq)
+	if err != nil {
+		return nil, err
+	}
+
+	fnv, found := ns.lookupFunc(fname)
+	if !found {
+		return nil, errors.New("can't find function " + fname)
+	}
+
+	return pages.GroupByFunc(func(p page.Page) (interface{}, error) {
+		pv := reflect.ValueOf(p)
+		fargs := make([]interface{}, len(args))
+		for i, arg := range args {
+			fargs[i] = arg
+			s, ok := arg.(string)
+			if !ok || len(s) < 2 || s[0] != '.' {
+				continue
+			}
+			v, isNil := indirectInterface(whereValue(pv, reflect.ValueOf(s), strings.Split(s[1:], ".")))
+			if isNil || !v.IsValid() {
+				return nil, nil
+			}
+			fargs[i] = v.Interface()
+		}
+
+		v, err := applyFnToThis(fnv, pv, fargs...)
+		if err != nil || !v.IsValid() {
+			return nil, err
+		}
+
+		if v, isNil := indirectInterface(v); isNil || !v.IsValid() {
+			return nil, nil
+		}
+
+		return v.Interface(), nil
+	})
+}
@@ -134,6 +134,13 @@ func init() {
 			[][2]string{},
 		)
 
+		ns.AddMethodMapping(ctx.SampleSeeded,
+			nil,
+			[][2]string{
+				{`{{ slice "a" "b" "c" "d" | collections.SampleSeeded "2021-06-01" 2 | len }}`, `2`},
+			},
+		)
+
 		ns.AddMethodMapping(ctx.Slice,
 			[]string{"slice"},
 			[][2]string{
@@ -146,6 +153,20 @@ func init() {
 			[][2]string{},
 		)
 
+		ns.AddMethodMapping(ctx.FuzzyMatch,
+			nil,
+			[][2]string{
+				{`{{ collections.FuzzyMatch (slice "Hugo" "Jekyll" "Hugs") "hugo" }}`, `[Hugo Hugs]`},
+			},
+		)
+
+		ns.AddMethodMapping(ctx.SortNatural,
+			nil,
+			[][2]string{
+				{`{{ collections.SortNatural (slice "page10" "page2" "page1") }}`, `[page1 page2 page10]`},
+			},
+		)
+
 		ns.AddMethodMapping(ctx.Union,
 			[]string{"union"},
 			[][2]string{
@@ -168,6 +189,11 @@ func init() {
 			[][2]string{},
 		)
 
+		ns.AddMethodMapping(ctx.GroupBy,
+			[]string{"groupBy"},
+			[][2]string{},
+		)
+
 		ns.AddMethodMapping(ctx.Seq,
 			[]string{"seq"},
 			[][2]string{
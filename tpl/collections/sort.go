@@ -20,6 +20,7 @@ import (
 	"strings"
 
 	"github.com/gohugoio/hugo/common/maps"
+	hcompare "github.com/gohugoio/hugo/compare"
 	"github.com/gohugoio/hugo/tpl/compare"
 	"github.com/spf13/cast"
 )
@@ -28,6 +29,17 @@ var sortComp = compare.New(true)
 
 // Sort returns a sorted sequence.
 func (ns *Namespace) Sort(seq interface{}, args ...interface{}) (interface{}, error) {
+	return ns.sort(seq, false, args...)
+}
+
+// SortNatural returns a sequence sorted as Sort does, but with string keys
+// compared in natural order, i.e. with numbers in the strings compared by
+// value, so "page2" sorts before "page10".
+func (ns *Namespace) SortNatural(seq interface{}, args ...interface{}) (interface{}, error) {
+	return ns.sort(seq, true, args...)
+}
+
+func (ns *Namespace) sort(seq interface{}, natural bool, args ...interface{}) (interface{}, error) {
 	if seq == nil {
 		return nil, errors.New("sequence must be provided")
 	}
@@ -48,7 +60,7 @@ func (ns *Namespace) Sort(seq interface{}, args ...interface{}) (interface{}, er
 	}
 
 	// Create a list of pairs that will be used to do the sort
-	p := pairList{SortAsc: true, SliceType: sliceType}
+	p := pairList{SortAsc: true, Natural: natural, SliceType: sliceType}
 	p.Pairs = make([]pair, seqv.Len())
 
 	var sortByField string
@@ -74,21 +86,9 @@ func (ns *Namespace) Sort(seq interface{}, args ...interface{}) (interface{}, er
 			if sortByField == "" || sortByField == "value" {
 				p.Pairs[i].Key = p.Pairs[i].Value
 			} else {
-				v := p.Pairs[i].Value
-				var err error
-				for i, elemName := range path {
-					v, err = evaluateSubElem(v, elemName)
-					if err != nil {
-						return nil, err
-					}
-					if !v.IsValid() {
-						continue
-					}
-					// Special handling of lower cased maps.
-					if params, ok := v.Interface().(maps.Params); ok {
-						v = reflect.ValueOf(params.Get(path[i+1:]...))
-						break
-					}
+				v, err := evaluatePath(p.Pairs[i].Value, path)
+				if err != nil {
+					return nil, err
 				}
 				p.Pairs[i].Key = v
 			}
@@ -104,21 +104,9 @@ func (ns *Namespace) Sort(seq interface{}, args ...interface{}) (interface{}, er
 			} else if sortByField == "value" {
 				p.Pairs[i].Key = p.Pairs[i].Value
 			} else {
-				v := p.Pairs[i].Value
-				var err error
-				for i, elemName := range path {
-					v, err = evaluateSubElem(v, elemName)
-					if err != nil {
-						return nil, err
-					}
-					if !v.IsValid() {
-						continue
-					}
-					// Special handling of lower cased maps.
-					if params, ok := v.Interface().(maps.Params); ok {
-						v = reflect.ValueOf(params.Get(path[i+1:]...))
-						break
-					}
+				v, err := evaluatePath(p.Pairs[i].Value, path)
+				if err != nil {
+					return nil, err
 				}
 				p.Pairs[i].Key = v
 			}
@@ -127,6 +115,26 @@ func (ns *Namespace) Sort(seq interface{}, args ...interface{}) (interface{}, er
 	return p.sort(), nil
 }
 
+// evaluatePath returns the value of the field, method or map key path in v.
+func evaluatePath(v reflect.Value, path []string) (reflect.Value, error) {
+	var err error
+	for i, elemName := range path {
+		v, err = evaluateSubElem(v, elemName)
+		if err != nil {
+			return v, err
+		}
+		if !v.IsValid() {
+			continue
+		}
+		// Special handling of lower cased maps.
+		if params, ok := v.Interface().(maps.Params); ok {
+			v = reflect.ValueOf(params.Get(path[i+1:]...))
+			break
+		}
+	}
+	return v, nil
+}
+
 // Credit for pair sorting method goes to Andrew Gerrand
 // https://groups.google.com/forum/#!topic/golang-nuts/FT7cjmcL7gw
 // A data structure to hold a key/value pair.
@@ -139,6 +147,7 @@ type pair struct {
 type pairList struct {
 	Pairs     []pair
 	SortAsc   bool
+	Natural   bool
 	SliceType reflect.Type
 }
 
@@ -150,6 +159,13 @@ func (p pairList) Less(i, j int) bool {
 
 	if iv.IsValid() {
 		if jv.IsValid() {
+			if p.Natural {
+				if is, ok := stringValue(iv); ok {
+					if js, ok := stringValue(jv); ok {
+						return hcompare.LessStringsNatural(is, js)
+					}
+				}
+			}
 			// can only call Interface() on valid reflect Values
 			return sortComp.Lt(iv.Interface(), jv.Interface())
 		}
@@ -166,6 +182,17 @@ func (p pairList) Less(i, j int) bool {
 	return false
 }
 
+// stringValue returns the string in v, if any.
+func stringValue(v reflect.Value) (string, bool) {
+	for v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.String {
+		return "", false
+	}
+	return v.String(), true
+}
+
 // sorts a pairList and returns a slice of sorted values
 func (p pairList) sort() interface{} {
 	if p.SortAsc {
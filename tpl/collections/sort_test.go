@@ -18,6 +18,7 @@ import (
 	"reflect"
 	"testing"
 
+	qt "github.com/frankban/quicktest"
 	"github.com/gohugoio/hugo/common/maps"
 
 	"github.com/gohugoio/hugo/deps"
@@ -262,3 +263,35 @@ func TestSort(t *testing.T) {
 		})
 	}
 }
+
+func TestSortNatural(t *testing.T) {
+	t.Parallel()
+	c := qt.New(t)
+
+	ns := New(&deps.Deps{})
+
+	result, err := ns.SortNatural([]string{"page10", "page2", "Page1"})
+	c.Assert(err, qt.IsNil)
+	c.Assert(result, qt.DeepEquals, []string{"Page1", "page2", "page10"})
+
+	result, err = ns.SortNatural([]string{"page10", "page2", "Page1"}, "value", "desc")
+	c.Assert(err, qt.IsNil)
+	c.Assert(result, qt.DeepEquals, []string{"page10", "page2", "Page1"})
+
+	result, err = ns.SortNatural([]interface{}{
+		map[string]interface{}{"title": "Episode 12"},
+		map[string]interface{}{"title": "Episode 3"},
+		map[string]interface{}{"title": "Episode 1b"},
+	}, "title")
+	c.Assert(err, qt.IsNil)
+	c.Assert(result, qt.DeepEquals, []interface{}{
+		map[string]interface{}{"title": "Episode 1b"},
+		map[string]interface{}{"title": "Episode 3"},
+		map[string]interface{}{"title": "Episode 12"},
+	})
+
+	// Non-string keys are sorted as with Sort.
+	result, err = ns.SortNatural([]int{10, 2, 1})
+	c.Assert(err, qt.IsNil)
+	c.Assert(result, qt.DeepEquals, []int{1, 2, 10})
+}
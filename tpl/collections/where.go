@@ -372,40 +372,15 @@ func parseWhereArgs(args ...interface{}) (mv reflect.Value, op string, err error
 // checkWhereArray handles the where-matching logic when the seqv value is an
 // Array or Slice.
 func (ns *Namespace) checkWhereArray(seqv, kv, mv reflect.Value, path []string, op string) (interface{}, error) {
+	if v, found := ns.indexes.lookup(seqv, kv, mv, path, op); found {
+		return v, nil
+	}
+
 	rv := reflect.MakeSlice(seqv.Type(), 0, 0)
 
 	for i := 0; i < seqv.Len(); i++ {
-		var vvv reflect.Value
 		rvv := seqv.Index(i)
-
-		if kv.Kind() == reflect.String {
-			if params, ok := rvv.Interface().(maps.Params); ok {
-				vvv = reflect.ValueOf(params.Get(path...))
-			} else {
-				vvv = rvv
-				for i, elemName := range path {
-					var err error
-					vvv, err = evaluateSubElem(vvv, elemName)
-
-					if err != nil {
-						continue
-					}
-
-					if i < len(path)-1 && vvv.IsValid() {
-						if params, ok := vvv.Interface().(maps.Params); ok {
-							// The current path element is the map itself, .Params.
-							vvv = reflect.ValueOf(params.Get(path[i+1:]...))
-							break
-						}
-					}
-				}
-			}
-		} else {
-			vv, _ := indirect(rvv)
-			if vv.Kind() == reflect.Map && kv.Type().AssignableTo(vv.Type().Key()) {
-				vvv = vv.MapIndex(kv)
-			}
-		}
+		vvv := whereValue(rvv, kv, path)
 
 		if ok, err := ns.checkCondition(vvv, mv, op); ok {
 			rv = reflect.Append(rv, rvv)
@@ -416,6 +391,42 @@ func (ns *Namespace) checkWhereArray(seqv, kv, mv reflect.Value, path []string,
 	return rv.Interface(), nil
 }
 
+// whereValue returns the value of rvv to match against in where.
+func whereValue(rvv, kv reflect.Value, path []string) reflect.Value {
+	var vvv reflect.Value
+
+	if kv.Kind() == reflect.String {
+		if params, ok := rvv.Interface().(maps.Params); ok {
+			vvv = reflect.ValueOf(params.Get(path...))
+		} else {
+			vvv = rvv
+			for i, elemName := range path {
+				var err error
+				vvv, err = evaluateSubElem(vvv, elemName)
+
+				if err != nil {
+					continue
+				}
+
+				if i < len(path)-1 && vvv.IsValid() {
+					if params, ok := vvv.Interface().(maps.Params); ok {
+						// The current path element is the map itself, .Params.
+						vvv = reflect.ValueOf(params.Get(path[i+1:]...))
+						break
+					}
+				}
+			}
+		}
+	} else {
+		vv, _ := indirect(rvv)
+		if vv.Kind() == reflect.Map && kv.Type().AssignableTo(vv.Type().Key()) {
+			vvv = vv.MapIndex(kv)
+		}
+	}
+
+	return vvv
+}
+
 // checkWhereMap handles the where-matching logic when the seqv value is a Map.
 func (ns *Namespace) checkWhereMap(seqv, kv, mv reflect.Value, path []string, op string) (interface{}, error) {
 	rv := reflect.MakeMap(seqv.Type())
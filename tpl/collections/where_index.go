@@ -0,0 +1,206 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collections
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/gohugoio/hugo/resources/page"
+)
+
+// Integers with a larger absolute value cannot be represented exactly as a
+// float64.
+const maxExactInt = 1 << 53
+
+var pagesType = reflect.TypeOf(page.Pages(nil))
+
+// whereIndexes holds the indexes used by where to look up pages by the keys
+// configured in queryIndexes, e.g. "Params.category", without iterating over
+// the whole collection on every call.
+//
+// A collection gets indexed the second time it's queried with an indexed key,
+// which avoids creating indexes for one-off collections. The indexes are
+// dropped when a new build starts; page collections are recreated on every
+// build and must not be modified once created.
+type whereIndexes struct {
+	keys map[string]bool
+
+	mu      sync.Mutex
+	indexes map[whereIndexKey]*whereIndex
+}
+
+type whereIndexKey struct {
+	ptr  uintptr
+	len  int
+	path string
+}
+
+type whereIndex struct {
+	// Keeps the collection alive, so its memory address is not reused for
+	// another collection while the index is in use.
+	pages page.Pages
+	path  []string
+
+	// Set when queried for the second time.
+	init    sync.Once
+	indexed bool
+	values  map[whereIndexValue]page.Pages
+}
+
+// whereIndexValue represents a scalar value compared to others the same way
+// as in where's checkCondition. All numbers are stored as float64.
+type whereIndexValue struct {
+	kind reflect.Kind // reflect.Bool, reflect.String or reflect.Float64.
+	s    string
+	f    float64
+	b    bool
+}
+
+func newWhereIndexes(keys []string) *whereIndexes {
+	idx := &whereIndexes{
+		keys:    make(map[string]bool),
+		indexes: make(map[whereIndexKey]*whereIndex),
+	}
+	for _, k := range keys {
+		idx.keys[normalizeWhereIndexPath(k)] = true
+	}
+	return idx
+}
+
+func normalizeWhereIndexPath(s string) string {
+	return strings.ToLower(strings.Trim(s, "."))
+}
+
+func (idx *whereIndexes) reset() {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.indexes = make(map[whereIndexKey]*whereIndex)
+}
+
+// lookup looks up the pages in seqv matching mv using an index.
+// It returns false if the query cannot be answered by an index.
+func (idx *whereIndexes) lookup(seqv, kv, mv reflect.Value, path []string, op string) (interface{}, bool) {
+	if idx == nil || len(idx.keys) == 0 {
+		return nil, false
+	}
+
+	switch op {
+	case "", "=", "==", "eq":
+	default:
+		return nil, false
+	}
+
+	if kv.Kind() != reflect.String || seqv.Kind() != reflect.Slice || seqv.Type() != pagesType || seqv.Len() == 0 {
+		return nil, false
+	}
+
+	p := normalizeWhereIndexPath(strings.Join(path, "."))
+	if !idx.keys[p] {
+		return nil, false
+	}
+
+	v, ok := toWhereIndexValue(mv)
+	if !ok {
+		return nil, false
+	}
+
+	key := whereIndexKey{ptr: seqv.Pointer(), len: seqv.Len(), path: p}
+
+	idx.mu.Lock()
+	wi, found := idx.indexes[key]
+	if !found {
+		idx.indexes[key] = &whereIndex{pages: seqv.Interface().(page.Pages), path: path}
+		idx.mu.Unlock()
+		return nil, false
+	}
+	idx.mu.Unlock()
+
+	wi.init.Do(wi.build)
+
+	if !wi.indexed {
+		return nil, false
+	}
+
+	if pages, found := wi.values[v]; found {
+		return pages, true
+	}
+
+	return page.Pages{}, true
+}
+
+func (wi *whereIndex) build() {
+	values := make(map[whereIndexValue]page.Pages)
+	kv := reflect.ValueOf(strings.Join(wi.path, "."))
+	seqv := reflect.ValueOf(wi.pages)
+
+	for i, p := range wi.pages {
+		vv, isNil := indirect(whereValue(seqv.Index(i), kv, wi.path))
+		if isNil || !vv.IsValid() {
+			continue
+		}
+
+		if isUint(vv.Kind()) {
+			// where fails comparing these to other numbers.
+			return
+		}
+
+		if isInt(vv.Kind()) && (vv.Int() > maxExactInt || vv.Int() < -maxExactInt) {
+			return
+		}
+
+		v, ok := toWhereIndexValue(vv)
+		if !ok && vv.Kind() == reflect.Float32 {
+			// Compared as a float64 to other numbers.
+			v, ok = whereIndexValue{kind: reflect.Float64, f: vv.Float()}, true
+		}
+
+		if !ok {
+			// Not a scalar, will never match.
+			continue
+		}
+
+		values[v] = append(values[v], p)
+	}
+
+	wi.values = values
+	wi.indexed = true
+}
+
+// toWhereIndexValue converts v to an index value. It returns false for
+// values that cannot be compared using an index.
+func toWhereIndexValue(v reflect.Value) (whereIndexValue, bool) {
+	v, isNil := indirect(v)
+	if isNil || !v.IsValid() {
+		return whereIndexValue{}, false
+	}
+
+	switch kind := v.Kind(); {
+	case kind == reflect.Bool:
+		return whereIndexValue{kind: reflect.Bool, b: v.Bool()}, true
+	case kind == reflect.String:
+		return whereIndexValue{kind: reflect.String, s: v.String()}, true
+	case kind == reflect.Float64:
+		return whereIndexValue{kind: reflect.Float64, f: v.Float()}, true
+	case isInt(kind):
+		i := v.Int()
+		if i > maxExactInt || i < -maxExactInt {
+			return whereIndexValue{}, false
+		}
+		return whereIndexValue{kind: reflect.Float64, f: float64(i)}, true
+	}
+
+	return whereIndexValue{}, false
+}
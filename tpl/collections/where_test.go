@@ -21,7 +21,10 @@ import (
 	"testing"
 	"time"
 
+	qt "github.com/frankban/quicktest"
 	"github.com/gohugoio/hugo/common/maps"
+	"github.com/gohugoio/hugo/config"
+	"github.com/gohugoio/hugo/resources/page"
 
 	"github.com/gohugoio/hugo/deps"
 )
@@ -680,6 +683,81 @@ func TestWhere(t *testing.T) {
 	}
 }
 
+// Page has a Page method, so it cannot be embedded by its own name.
+type pageInterface = page.Page
+
+type whereIndexTestPage struct {
+	pageInterface
+	title  string
+	params maps.Params
+}
+
+func (p *whereIndexTestPage) Title() string {
+	return p.title
+}
+
+func (p *whereIndexTestPage) Params() maps.Params {
+	return p.params
+}
+
+func TestWhereIndex(t *testing.T) {
+	t.Parallel()
+	c := qt.New(t)
+
+	cfg := config.New()
+	cfg.Set("queryIndexes", []string{"Params.category", "title"})
+	ns := New(&deps.Deps{Cfg: cfg})
+
+	var pages page.Pages
+	for i, category := range []interface{}{"a", "b", "a", 1, 1.0, nil, []string{"a"}, int64(1), true} {
+		pages = append(pages, &whereIndexTestPage{
+			title:  fmt.Sprintf("P%d", i),
+			params: maps.Params{"category": category},
+		})
+	}
+
+	titles := func(result interface{}) string {
+		var s []string
+		for _, p := range result.(page.Pages) {
+			s = append(s, p.Title())
+		}
+		return strings.Join(s, ",")
+	}
+
+	for _, test := range []struct {
+		key    string
+		op     string
+		match  interface{}
+		expect string
+	}{
+		{".Params.category", "", "a", "P0,P2"},
+		{"Params.category", "eq", "b", "P1"},
+		{".Params.category", "==", 1, "P3,P4,P7"},
+		{".Params.category", "=", 1.0, "P3,P4,P7"},
+		{".Params.category", "", true, "P8"},
+		{".Params.category", "", "c", ""},
+		{".Title", "", "P1", "P1"},
+	} {
+		var results []string
+		// The second query uses the index.
+		for i := 0; i < 2; i++ {
+			result, err := ns.Where(pages, test.key, test.op, test.match)
+			c.Assert(err, qt.IsNil)
+			results = append(results, titles(result))
+		}
+		c.Assert(results, qt.DeepEquals, []string{test.expect, test.expect}, qt.Commentf("%s %v", test.key, test.match))
+	}
+
+	// Not indexed.
+	result, err := ns.Where(pages, ".Params.category", "!=", "a")
+	c.Assert(err, qt.IsNil)
+	c.Assert(titles(result), qt.Equals, "P1,P5")
+
+	c.Assert(ns.indexes.indexes, qt.HasLen, 2)
+	ns.indexes.reset()
+	c.Assert(ns.indexes.indexes, qt.HasLen, 0)
+}
+
 func TestCheckCondition(t *testing.T) {
 	t.Parallel()
 
@@ -15,6 +15,7 @@
 package crypto
 
 import (
+	"crypto"
 	"crypto/hmac"
 	"crypto/md5"
 	"crypto/sha1"
@@ -23,17 +24,25 @@ import (
 	"encoding/hex"
 	"fmt"
 	"hash"
+	"sync"
 
+	"github.com/gohugoio/hugo/deps"
 	"github.com/spf13/cast"
 )
 
 // New returns a new instance of the crypto-namespaced template functions.
-func New() *Namespace {
-	return &Namespace{}
+func New(deps *deps.Deps) *Namespace {
+	return &Namespace{deps: deps}
 }
 
 // Namespace provides template functions for the "crypto" namespace.
-type Namespace struct{}
+type Namespace struct {
+	deps *deps.Deps
+
+	// The signing keys, loaded on first use.
+	keysMu sync.Mutex
+	keys   map[string]crypto.Signer
+}
 
 // MD5 hashes the given input and returns its MD5 checksum.
 func (ns *Namespace) MD5(in interface{}) (string, error) {
@@ -17,13 +17,14 @@ import (
 	"testing"
 
 	qt "github.com/frankban/quicktest"
+	"github.com/gohugoio/hugo/deps"
 )
 
 func TestMD5(t *testing.T) {
 	t.Parallel()
 	c := qt.New(t)
 
-	ns := New()
+	ns := New(&deps.Deps{})
 
 	for i, test := range []struct {
 		in     interface{}
@@ -50,7 +51,7 @@ func TestMD5(t *testing.T) {
 func TestSHA1(t *testing.T) {
 	t.Parallel()
 	c := qt.New(t)
-	ns := New()
+	ns := New(&deps.Deps{})
 
 	for i, test := range []struct {
 		in     interface{}
@@ -77,7 +78,7 @@ func TestSHA1(t *testing.T) {
 func TestSHA256(t *testing.T) {
 	t.Parallel()
 	c := qt.New(t)
-	ns := New()
+	ns := New(&deps.Deps{})
 
 	for i, test := range []struct {
 		in     interface{}
@@ -104,7 +105,7 @@ func TestSHA256(t *testing.T) {
 func TestHMAC(t *testing.T) {
 	t.Parallel()
 	c := qt.New(t)
-	ns := New()
+	ns := New(&deps.Deps{})
 
 	for i, test := range []struct {
 		hash   interface{}
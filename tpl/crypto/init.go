@@ -22,7 +22,7 @@ const name = "crypto"
 
 func init() {
 	f := func(d *deps.Deps) *internal.TemplateFuncsNamespace {
-		ctx := New()
+		ctx := New(d)
 
 		ns := &internal.TemplateFuncsNamespace{
 			Name:    name,
@@ -58,6 +58,16 @@ func init() {
 			},
 		)
 
+		ns.AddMethodMapping(ctx.SignEd25519,
+			nil,
+			[][2]string{},
+		)
+
+		ns.AddMethodMapping(ctx.SignRSA,
+			nil,
+			[][2]string{},
+		)
+
 		return ns
 	}
 
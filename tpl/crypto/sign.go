@@ -0,0 +1,276 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crypto
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"path/filepath"
+	"strings"
+
+	"github.com/gohugoio/hugo/common/maps"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"github.com/spf13/cast"
+)
+
+const signingKeysConfigKey = "signingKeys"
+
+// signingKeyConfig configures a named private key used by SignEd25519 and
+// SignRSA.
+type signingKeyConfig struct {
+	// The PEM encoded private key or, for Ed25519, the base64 encoded seed.
+	// This is usually set with an environment variable, e.g.
+	// HUGO_SIGNINGKEYS_WEBHOOK_KEY, or encrypted with hugo secrets encrypt.
+	Key string
+
+	// The file with the key, relative to the working dir. Used if Key is
+	// not set.
+	KeyFile string
+}
+
+// signOptions configures how a message is signed.
+type signOptions struct {
+	// The encoding of the signature, one of base64url (default, without
+	// padding, as used in JWTs), base64 or hex.
+	Encoding string
+
+	// The hash function used with RSA, one of sha256 (default), sha384 or
+	// sha512.
+	Hash string
+
+	// The RSA signature scheme, one of pkcs1v15 (default) or pss.
+	Padding string
+}
+
+// SignEd25519 signs message with the Ed25519 key configured in
+// signingKeys.KEY, returning the signature encoded as set in the optional
+// options map, see signOptions.
+func (ns *Namespace) SignEd25519(key interface{}, message interface{}, options ...interface{}) (string, error) {
+	signer, msg, opts, err := ns.signArgs(key, message, options)
+	if err != nil {
+		return "", err
+	}
+
+	privateKey, ok := signer.(ed25519.PrivateKey)
+	if !ok {
+		return "", errors.Errorf("signEd25519: key %q is not an Ed25519 key", key)
+	}
+
+	return encodeSignature(ed25519.Sign(privateKey, msg), opts.Encoding)
+}
+
+// SignRSA signs message with the RSA key configured in signingKeys.KEY,
+// returning the signature encoded as set in the optional options map, see
+// signOptions. The defaults give the RS256 signatures used in JWTs.
+func (ns *Namespace) SignRSA(key interface{}, message interface{}, options ...interface{}) (string, error) {
+	signer, msg, opts, err := ns.signArgs(key, message, options)
+	if err != nil {
+		return "", err
+	}
+
+	privateKey, ok := signer.(*rsa.PrivateKey)
+	if !ok {
+		return "", errors.Errorf("signRSA: key %q is not an RSA key", key)
+	}
+
+	var h crypto.Hash
+	switch strings.ToLower(opts.Hash) {
+	case "", "sha256":
+		h = crypto.SHA256
+	case "sha384":
+		h = crypto.SHA384
+	case "sha512":
+		h = crypto.SHA512
+	default:
+		return "", errors.Errorf("signRSA: %s is not a supported hash function", opts.Hash)
+	}
+
+	hasher := h.New()
+	hasher.Write(msg)
+	digest := hasher.Sum(nil)
+
+	var sig []byte
+	switch strings.ToLower(opts.Padding) {
+	case "", "pkcs1v15":
+		sig, err = rsa.SignPKCS1v15(rand.Reader, privateKey, h, digest)
+	case "pss":
+		sig, err = rsa.SignPSS(rand.Reader, privateKey, h, digest, nil)
+	default:
+		return "", errors.Errorf("signRSA: %s is not a supported padding", opts.Padding)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return encodeSignature(sig, opts.Encoding)
+}
+
+func (ns *Namespace) signArgs(key, message interface{}, options []interface{}) (crypto.Signer, []byte, signOptions, error) {
+	var opts signOptions
+
+	name, err := cast.ToStringE(key)
+	if err != nil {
+		return nil, nil, opts, err
+	}
+	msg, err := cast.ToStringE(message)
+	if err != nil {
+		return nil, nil, opts, err
+	}
+
+	if len(options) > 1 {
+		return nil, nil, opts, errors.New("too many arguments")
+	}
+	if len(options) == 1 {
+		m, err := maps.ToStringMapE(options[0])
+		if err != nil {
+			return nil, nil, opts, err
+		}
+		if err := mapstructure.WeakDecode(m, &opts); err != nil {
+			return nil, nil, opts, err
+		}
+	}
+
+	signer, err := ns.signingKey(name)
+	if err != nil {
+		return nil, nil, opts, err
+	}
+
+	return signer, []byte(msg), opts, nil
+}
+
+// signingKey returns the key configured in signingKeys.name.
+func (ns *Namespace) signingKey(name string) (crypto.Signer, error) {
+	ns.keysMu.Lock()
+	defer ns.keysMu.Unlock()
+
+	if signer, found := ns.keys[name]; found {
+		return signer, nil
+	}
+
+	signer, err := ns.loadSigningKey(name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s.%s", signingKeysConfigKey, name)
+	}
+
+	if ns.keys == nil {
+		ns.keys = make(map[string]crypto.Signer)
+	}
+	ns.keys[name] = signer
+
+	return signer, nil
+}
+
+func (ns *Namespace) loadSigningKey(name string) (crypto.Signer, error) {
+	if ns.deps == nil || ns.deps.Cfg == nil {
+		return nil, errors.New("not configured")
+	}
+	cfg := ns.deps.Cfg
+
+	keys := maps.ToStringMap(cfg.Get(signingKeysConfigKey))
+	v, found := keys[strings.ToLower(name)]
+	if !found {
+		return nil, errors.New("not configured")
+	}
+
+	var conf signingKeyConfig
+	if err := mapstructure.WeakDecode(maps.ToStringMap(v), &conf); err != nil {
+		return nil, err
+	}
+
+	key := conf.Key
+	if key == "" && conf.KeyFile != "" {
+		filename := conf.KeyFile
+		if !filepath.IsAbs(filename) {
+			filename = filepath.Join(cfg.GetString("workingDir"), filename)
+		}
+		b, err := afero.ReadFile(ns.deps.Fs.Source, filename)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read key file")
+		}
+		key = string(b)
+	}
+	if key == "" {
+		return nil, errors.New("no key or keyFile set")
+	}
+
+	key, err := ns.deps.Secrets.Decrypt(strings.TrimSpace(key))
+	if err != nil {
+		return nil, err
+	}
+
+	return parsePrivateKey(key)
+}
+
+// parsePrivateKey parses a PEM encoded PKCS #8 or PKCS #1 private key, or
+// a base64 encoded Ed25519 seed or private key.
+func parsePrivateKey(s string) (crypto.Signer, error) {
+	block, _ := pem.Decode([]byte(s))
+	if block == nil {
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return nil, errors.New("key is neither PEM nor base64 encoded")
+		}
+		switch len(b) {
+		case ed25519.SeedSize:
+			return ed25519.NewKeyFromSeed(b), nil
+		case ed25519.PrivateKeySize:
+			return ed25519.PrivateKey(b), nil
+		default:
+			return nil, errors.Errorf("invalid Ed25519 key size %d", len(b))
+		}
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		k, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		signer, ok := k.(crypto.Signer)
+		if !ok {
+			return nil, errors.Errorf("unsupported key type %T", k)
+		}
+		switch signer.(type) {
+		case ed25519.PrivateKey, *rsa.PrivateKey:
+			return signer, nil
+		default:
+			return nil, errors.Errorf("unsupported key type %T", k)
+		}
+	default:
+		return nil, errors.Errorf("unsupported PEM block %q", block.Type)
+	}
+}
+
+func encodeSignature(sig []byte, encoding string) (string, error) {
+	switch strings.ToLower(encoding) {
+	case "", "base64url":
+		return base64.RawURLEncoding.EncodeToString(sig), nil
+	case "base64":
+		return base64.StdEncoding.EncodeToString(sig), nil
+	case "hex":
+		return hex.EncodeToString(sig), nil
+	default:
+		return "", errors.Errorf("%s is not a supported signature encoding", encoding)
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crypto
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gohugoio/hugo/config"
+	"github.com/gohugoio/hugo/config/secrets"
+	"github.com/gohugoio/hugo/deps"
+	"github.com/gohugoio/hugo/hugofs"
+	"github.com/spf13/afero"
+)
+
+func TestSign(t *testing.T) {
+	c := qt.New(t)
+
+	edPublic, edPrivate, err := ed25519.GenerateKey(rand.Reader)
+	c.Assert(err, qt.IsNil)
+	edPKCS8, err := x509.MarshalPKCS8PrivateKey(edPrivate)
+	c.Assert(err, qt.IsNil)
+	edPEM := string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: edPKCS8}))
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	c.Assert(err, qt.IsNil)
+	rsaPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)})
+
+	secretsKey, err := secrets.GenerateKey()
+	c.Assert(err, qt.IsNil)
+	decrypter, err := secrets.NewDecrypter(secretsKey)
+	c.Assert(err, qt.IsNil)
+	encryptedSeed, err := decrypter.Encrypt(base64.StdEncoding.EncodeToString(edPrivate.Seed()))
+	c.Assert(err, qt.IsNil)
+
+	v := config.New()
+	v.Set("workingDir", filepath.FromSlash("/site"))
+	v.Set("signingKeys", map[string]interface{}{
+		"webhook": map[string]interface{}{"key": edPEM},
+		"seed":    map[string]interface{}{"key": encryptedSeed},
+		"jwt":     map[string]interface{}{"keyFile": "keys/jwt.pem"},
+		"missing": map[string]interface{}{"keyFile": "keys/missing.pem"},
+		"invalid": map[string]interface{}{"key": "not a key"},
+		"empty":   map[string]interface{}{},
+	})
+
+	fs := hugofs.NewMem(v)
+	c.Assert(afero.WriteFile(fs.Source, filepath.FromSlash("/site/keys/jwt.pem"), rsaPEM, 0666), qt.IsNil)
+
+	ns := New(&deps.Deps{Cfg: v, Fs: fs, Secrets: decrypter})
+
+	msg := "Hello world, gophers!"
+
+	c.Run("Ed25519", func(c *qt.C) {
+		for _, key := range []string{"webhook", "seed"} {
+			sig, err := ns.SignEd25519(key, msg)
+			c.Assert(err, qt.IsNil)
+			b, err := base64.RawURLEncoding.DecodeString(sig)
+			c.Assert(err, qt.IsNil)
+			c.Assert(ed25519.Verify(edPublic, []byte(msg), b), qt.IsTrue)
+		}
+
+		sig, err := ns.SignEd25519("webhook", msg, map[string]interface{}{"encoding": "hex"})
+		c.Assert(err, qt.IsNil)
+		b, err := hex.DecodeString(sig)
+		c.Assert(err, qt.IsNil)
+		c.Assert(ed25519.Verify(edPublic, []byte(msg), b), qt.IsTrue)
+
+		_, err = ns.SignEd25519("jwt", msg)
+		c.Assert(err, qt.ErrorMatches, `signEd25519: key "jwt" is not an Ed25519 key`)
+	})
+
+	c.Run("RSA", func(c *qt.C) {
+		sig, err := ns.SignRSA("jwt", msg)
+		c.Assert(err, qt.IsNil)
+		b, err := base64.RawURLEncoding.DecodeString(sig)
+		c.Assert(err, qt.IsNil)
+		digest := sha256.Sum256([]byte(msg))
+		c.Assert(rsa.VerifyPKCS1v15(&rsaKey.PublicKey, crypto.SHA256, digest[:], b), qt.IsNil)
+
+		sig, err = ns.SignRSA("jwt", msg, map[string]interface{}{"hash": "sha512", "padding": "pss", "encoding": "base64"})
+		c.Assert(err, qt.IsNil)
+		b, err = base64.StdEncoding.DecodeString(sig)
+		c.Assert(err, qt.IsNil)
+		digest512 := sha512.Sum512([]byte(msg))
+		c.Assert(rsa.VerifyPSS(&rsaKey.PublicKey, crypto.SHA512, digest512[:], b, nil), qt.IsNil)
+
+		_, err = ns.SignRSA("jwt", msg, map[string]interface{}{"hash": "md5"})
+		c.Assert(err, qt.ErrorMatches, "signRSA: md5 is not a supported hash function")
+		_, err = ns.SignRSA("webhook", msg)
+		c.Assert(err, qt.ErrorMatches, `signRSA: key "webhook" is not an RSA key`)
+	})
+
+	c.Run("Errors", func(c *qt.C) {
+		_, err := ns.SignRSA("nokey", msg)
+		c.Assert(err, qt.ErrorMatches, "signingKeys.nokey: not configured")
+		_, err = ns.SignRSA("missing", msg)
+		c.Assert(err, qt.ErrorMatches, "signingKeys.missing: failed to read key file.*")
+		_, err = ns.SignRSA("invalid", msg)
+		c.Assert(err, qt.ErrorMatches, "signingKeys.invalid: key is neither PEM nor base64 encoded")
+		_, err = ns.SignRSA("empty", msg)
+		c.Assert(err, qt.ErrorMatches, "signingKeys.empty: no key or keyFile set")
+		_, err = ns.SignRSA("jwt", msg, map[string]interface{}{"encoding": "base32"})
+		c.Assert(err, qt.ErrorMatches, "base32 is not a supported signature encoding")
+
+		_, err = New(&deps.Deps{}).SignEd25519("webhook", msg)
+		c.Assert(err, qt.ErrorMatches, "signingKeys.webhook: not configured")
+	})
+}
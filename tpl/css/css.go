@@ -0,0 +1,80 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package css provides functions for processing stylesheets.
+package css
+
+import (
+	"github.com/gohugoio/hugo/deps"
+	"github.com/gohugoio/hugo/resources/resource"
+	"github.com/gohugoio/hugo/resources/resource_transformers/cssprocess"
+	"github.com/gohugoio/hugo/resources/resource_transformers/cssprune"
+	"github.com/gohugoio/hugo/tpl/internal/resourcehelpers"
+)
+
+// New returns a new instance of the css-namespaced template functions.
+func New(deps *deps.Deps) (*Namespace, error) {
+	if deps.ResourceSpec == nil {
+		return &Namespace{}, nil
+	}
+
+	processClient, err := cssprocess.New(deps.ResourceSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Namespace{
+		pruneClient:   cssprune.New(deps.ResourceSpec),
+		processClient: processClient,
+	}, nil
+}
+
+// Namespace provides template functions for the "css" namespace.
+type Namespace struct {
+	pruneClient   *cssprune.Client
+	processClient *cssprocess.Client
+}
+
+// Prune removes the selectors not used in the site's HTML from the given
+// stylesheet. Use it with resources.PostProcess to look for them in the
+// complete site.
+func (ns *Namespace) Prune(args ...interface{}) (resource.Resource, error) {
+	r, m, err := resourcehelpers.ResolveArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	options, err := cssprune.DecodeOptions(m)
+	if err != nil {
+		return nil, err
+	}
+
+	return ns.pruneClient.Prune(r, options)
+}
+
+// Process lowers the nested rules in the given stylesheet to plain CSS,
+// adds the vendor prefixes still needed and, optionally, minifies it,
+// without the need for PostCSS.
+func (ns *Namespace) Process(args ...interface{}) (resource.Resource, error) {
+	r, m, err := resourcehelpers.ResolveArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	options, err := cssprocess.DecodeOptions(m)
+	if err != nil {
+		return nil, err
+	}
+
+	return ns.processClient.Process(r, options)
+}
@@ -33,17 +33,22 @@ import (
 	"github.com/spf13/cast"
 
 	"github.com/gohugoio/hugo/cache/filecache"
+	"github.com/gohugoio/hugo/config/netpolicy"
 	"github.com/gohugoio/hugo/deps"
 	_errors "github.com/pkg/errors"
 )
 
 // New returns a new instance of the data-namespaced template functions.
 func New(deps *deps.Deps) *Namespace {
+	fixtures, fixturesErr := newRemoteFixtures(deps.Fs.Source, deps.Cfg)
+
 	return &Namespace{
 		deps:         deps,
 		cacheGetCSV:  deps.FileCaches.GetCSVCache(),
 		cacheGetJSON: deps.FileCaches.GetJSONCache(),
-		client:       http.DefaultClient,
+		client:       netpolicy.Get(deps.Cfg).Client(http.DefaultClient),
+		fixtures:     fixtures,
+		fixturesErr:  fixturesErr,
 	}
 }
 
@@ -55,6 +60,10 @@ type Namespace struct {
 	cacheGetCSV  *filecache.Cache
 
 	client *http.Client
+
+	// Set when the remote requests are recorded to, or replayed from, fixtures.
+	fixtures    *remoteFixtures
+	fixturesErr error
 }
 
 // GetCSV expects a data separator and one or n-parts of a URL to a resource which
@@ -0,0 +1,196 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gohugoio/hugo/common/maps"
+	"github.com/gohugoio/hugo/config"
+	"github.com/gohugoio/hugo/helpers"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+const remoteFixturesConfigKey = "remoteFixtures"
+
+const (
+	fixturesModeRecord = "record"
+	fixturesModeReplay = "replay"
+)
+
+// fixturesConfig configures the recording and replaying of the responses to
+// the remote requests in e.g. getJSON, so builds don't depend on remote
+// services.
+type fixturesConfig struct {
+	// Set to "record" to fetch the remote resources and store the responses
+	// as fixtures, or to "replay" to only use the stored responses, failing
+	// on the requests not recorded. Empty disables the fixtures.
+	Mode string
+
+	// The directory with the fixtures, relative to the working directory.
+	Dir string
+}
+
+var defaultFixturesConfig = fixturesConfig{
+	Dir: "fixtures/remote",
+}
+
+func decodeFixturesConfig(cfg config.Provider) (conf fixturesConfig, err error) {
+	conf = defaultFixturesConfig
+
+	v := cfg.Get(remoteFixturesConfigKey)
+	if v == nil {
+		return
+	}
+
+	if err = mapstructure.WeakDecode(maps.ToStringMap(v), &conf); err != nil {
+		return
+	}
+
+	conf.Mode = strings.ToLower(conf.Mode)
+	switch conf.Mode {
+	case "", fixturesModeRecord, fixturesModeReplay:
+	default:
+		err = errors.Errorf("%s: invalid mode %q, must be either %q or %q", remoteFixturesConfigKey, conf.Mode, fixturesModeRecord, fixturesModeReplay)
+	}
+
+	return
+}
+
+// fixture is a recorded response to a remote request.
+type fixture struct {
+	URL        string      `json:"url"`
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+
+	// The body of the response, base64 encoded if not valid UTF-8.
+	Body       string `json:"body"`
+	BodyBase64 bool   `json:"bodyBase64,omitempty"`
+}
+
+// remoteFixtures records and replays the responses to remote requests.
+type remoteFixtures struct {
+	mode string
+	dir  string
+	fs   afero.Fs
+}
+
+func newRemoteFixtures(fs afero.Fs, cfg config.Provider) (*remoteFixtures, error) {
+	conf, err := decodeFixturesConfig(cfg)
+	if err != nil || conf.Mode == "" {
+		return nil, err
+	}
+
+	dir := conf.Dir
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(cfg.GetString("workingDir"), dir)
+	}
+
+	return &remoteFixtures{mode: conf.Mode, dir: dir, fs: fs}, nil
+}
+
+// filename returns the fixture filename for req. The request headers are
+// part of the name, but not stored, as they may contain credentials.
+func (f *remoteFixtures) filename(req *http.Request) string {
+	var headers bytes.Buffer
+	req.Header.Write(&headers)
+	id := helpers.MD5String(req.Method + req.URL.String() + headers.String())
+	host := strings.Replace(req.URL.Host, ":", "_", -1)
+	return filepath.Join(f.dir, host, id+".json")
+}
+
+// do sends req with client when recording, and stores the response, or
+// returns the recorded response when replaying.
+func (f *remoteFixtures) do(client *http.Client, req *http.Request) (*http.Response, error) {
+	filename := f.filename(req)
+
+	if f.mode == fixturesModeReplay {
+		return f.replay(filename, req)
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	b, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = ioutil.NopCloser(bytes.NewReader(b))
+
+	fix := fixture{
+		URL:        req.URL.String(),
+		StatusCode: res.StatusCode,
+		Header:     res.Header,
+	}
+	if utf8.Valid(b) {
+		fix.Body = string(b)
+	} else {
+		fix.Body = base64.StdEncoding.EncodeToString(b)
+		fix.BodyBase64 = true
+	}
+
+	data, err := json.MarshalIndent(fix, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := helpers.WriteToDisk(filename, bytes.NewReader(data), f.fs); err != nil {
+		return nil, errors.Wrapf(err, "failed to record the response for %s", req.URL)
+	}
+
+	return res, nil
+}
+
+func (f *remoteFixtures) replay(filename string, req *http.Request) (*http.Response, error) {
+	data, err := afero.ReadFile(f.fs, filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.Errorf("no recorded response for %s in %q; set %s.mode to %q to record it", req.URL, f.dir, remoteFixturesConfigKey, fixturesModeRecord)
+		}
+		return nil, err
+	}
+
+	var fix fixture
+	if err := json.Unmarshal(data, &fix); err != nil {
+		return nil, errors.Wrapf(err, "failed to read the recorded response in %q", filename)
+	}
+
+	body := []byte(fix.Body)
+	if fix.BodyBase64 {
+		if body, err = base64.StdEncoding.DecodeString(fix.Body); err != nil {
+			return nil, errors.Wrapf(err, "failed to read the recorded response in %q", filename)
+		}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", fix.StatusCode, http.StatusText(fix.StatusCode)),
+		StatusCode:    fix.StatusCode,
+		Header:        fix.Header,
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
@@ -37,6 +37,10 @@ var (
 
 // getRemote loads the content of a remote file. This method is thread safe.
 func (ns *Namespace) getRemote(cache *filecache.Cache, unmarshal func([]byte) (bool, error), req *http.Request) error {
+	if ns.fixturesErr != nil {
+		return ns.fixturesErr
+	}
+
 	url := req.URL.String()
 	var headers bytes.Buffer
 	req.Header.Write(&headers)
@@ -44,13 +48,13 @@ func (ns *Namespace) getRemote(cache *filecache.Cache, unmarshal func([]byte) (b
 	var handled bool
 	var retry bool
 
-	_, b, err := cache.GetOrCreateBytes(id, func() ([]byte, error) {
+	create := func() ([]byte, error) {
 		var err error
 		handled = true
 		for i := 0; i <= resRetries; i++ {
 			ns.deps.Log.Infof("Downloading: %s ...", url)
 			var res *http.Response
-			res, err = ns.client.Do(req)
+			res, err = ns.do(req)
 			if err != nil {
 				return nil, err
 			}
@@ -83,7 +87,16 @@ func (ns *Namespace) getRemote(cache *filecache.Cache, unmarshal func([]byte) (b
 		}
 
 		return nil, err
-	})
+	}
+
+	var b []byte
+	var err error
+	if ns.fixtures != nil {
+		// The fixtures replace the cache.
+		b, err = create()
+	} else {
+		_, b, err = cache.GetOrCreateBytes(id, create)
+	}
 
 	if !handled {
 		// This is cached content and should be correct.
@@ -93,6 +106,13 @@ func (ns *Namespace) getRemote(cache *filecache.Cache, unmarshal func([]byte) (b
 	return err
 }
 
+func (ns *Namespace) do(req *http.Request) (*http.Response, error) {
+	if ns.fixtures != nil {
+		return ns.fixtures.do(ns.client, req)
+	}
+	return ns.client.Do(req)
+}
+
 // getLocal loads the content of a local file
 func getLocal(url string, fs afero.Fs, cfg config.Provider) ([]byte, error) {
 	filename := filepath.Join(cfg.GetString("workingDir"), url)
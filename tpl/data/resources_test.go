@@ -108,7 +108,7 @@ func TestScpGetRemote(t *testing.T) {
 		srv, cl := getTestServer(func(w http.ResponseWriter, r *http.Request) {
 			w.Write(test.content)
 		})
-		defer func() { srv.Close() }()
+		defer srv.Close()
 
 		ns := newTestNs()
 		ns.client = cl
@@ -137,7 +137,7 @@ func TestScpGetRemoteParallel(t *testing.T) {
 		w.Write(content)
 	})
 
-	defer func() { srv.Close() }()
+	defer srv.Close()
 
 	url := "http://Foo.Bar/foo_Bar-Foo"
 	req, err := http.NewRequest("GET", url, nil)
@@ -195,7 +195,7 @@ func newDeps(cfg config.Provider) *deps.Deps {
 	cfg.Set("allModules", modules.Modules{mod})
 
 	logger := loggers.NewIgnorableLogger(loggers.NewErrorLogger(), "none")
-	cs, err := helpers.NewContentSpec(cfg, logger, afero.NewMemMapFs())
+	cs, err := helpers.NewContentSpec(cfg, logger, afero.NewMemMapFs(), nil)
 	if err != nil {
 		panic(err)
 	}
@@ -227,3 +227,52 @@ func newTestNs() *Namespace {
 	v.Set("contentDir", "content")
 	return New(newDeps(v))
 }
+
+func TestGetJSONRemoteFixtures(t *testing.T) {
+	t.Parallel()
+	c := qt.New(t)
+
+	v := config.New()
+	v.Set("contentDir", "content")
+	v.Set("remoteFixtures", map[string]interface{}{"mode": "record"})
+	d := newDeps(v)
+
+	var requests int
+	srv, client := getTestServer(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"city":"Stockholm"}`))
+	})
+	defer srv.Close()
+
+	expect := map[string]interface{}{"city": "Stockholm"}
+
+	ns := New(d)
+	ns.client = client
+	got, err := ns.GetJSON("http://fixtures/cities")
+	c.Assert(err, qt.IsNil)
+	c.Assert(got, qt.DeepEquals, expect)
+	// The cache is bypassed when recording.
+	_, err = ns.GetJSON("http://fixtures/cities")
+	c.Assert(err, qt.IsNil)
+	c.Assert(requests, qt.Equals, 2)
+
+	v.Set("remoteFixtures", map[string]interface{}{"mode": "replay"})
+	ns = New(d)
+	ns.client = client
+	got, err = ns.GetJSON("http://fixtures/cities")
+	c.Assert(err, qt.IsNil)
+	c.Assert(got, qt.DeepEquals, expect)
+	c.Assert(requests, qt.Equals, 2)
+
+	getRemote := func(ns *Namespace, url string) error {
+		req, err := http.NewRequest("GET", url, nil)
+		c.Assert(err, qt.IsNil)
+		return ns.getRemote(ns.cacheGetJSON, func(b []byte) (bool, error) { return false, nil }, req)
+	}
+
+	c.Assert(getRemote(ns, "http://fixtures/towns"), qt.ErrorMatches, `no recorded response for http://fixtures/towns in "fixtures/remote"; set remoteFixtures.mode to "record" to record it`)
+	c.Assert(requests, qt.Equals, 2)
+
+	v.Set("remoteFixtures", map[string]interface{}{"mode": "live"})
+	c.Assert(getRemote(New(d), "http://fixtures/cities"), qt.ErrorMatches, `remoteFixtures: invalid mode "live".*`)
+}
@@ -0,0 +1,179 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package humanize provides template functions to present values in a human
+// friendly way.
+package humanize
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gohugoio/hugo/common/types"
+	"github.com/gohugoio/hugo/deps"
+	"github.com/gohugoio/hugo/tpl/inflect"
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+)
+
+// New returns a new instance of the humanize-namespaced template functions.
+func New(deps *deps.Deps) *Namespace {
+	return &Namespace{
+		deps:    deps,
+		inflect: inflect.New(),
+		now:     time.Now,
+	}
+}
+
+// Namespace provides template functions for the "humanize" namespace.
+type Namespace struct {
+	deps    *deps.Deps
+	inflect *inflect.Namespace
+	now     func() time.Time
+}
+
+// Humanize returns the humanized form of a single parameter, see
+// inflect.Humanize.
+func (ns *Namespace) Humanize(in interface{}) (string, error) {
+	return ns.inflect.Humanize(in)
+}
+
+type durationUnit struct {
+	d time.Duration
+
+	// The translation ID. The ID of the short form has a Short suffix.
+	id string
+
+	// The English defaults.
+	one, other, short string
+}
+
+var durationUnits = []durationUnit{
+	{365 * 24 * time.Hour, "durationYears", "%d year", "%d years", "%d yr"},
+	{30 * 24 * time.Hour, "durationMonths", "%d month", "%d months", "%d mo"},
+	{7 * 24 * time.Hour, "durationWeeks", "%d week", "%d weeks", "%d wk"},
+	{24 * time.Hour, "durationDays", "%d day", "%d days", "%d d"},
+	{time.Hour, "durationHours", "%d hour", "%d hours", "%d h"},
+	{time.Minute, "durationMinutes", "%d minute", "%d minutes", "%d min"},
+	{time.Second, "durationSeconds", "%d second", "%d seconds", "%d s"},
+}
+
+// Duration returns d in its largest whole unit, from seconds to years, e.g.
+// "3 minutes" or, with the style "short", "3 min". The duration may be a
+// time.Duration or a duration string, e.g. "90s" or "2w". A month is 30
+// days and a year 365 days.
+//
+// The units can be translated in the i18n files with the IDs durationYears,
+// durationMonths, durationWeeks, durationDays, durationHours,
+// durationMinutes and durationSeconds, e.g.:
+//
+//	[durationMinutes]
+//	one = "{{ .Count }} Minute"
+//	other = "{{ .Count }} Minuten"
+//
+// The IDs of the short style have a Short suffix, e.g. durationMinutesShort.
+func (ns *Namespace) Duration(d interface{}, style ...interface{}) (string, error) {
+	dd, err := toDuration(d)
+	if err != nil {
+		return "", err
+	}
+
+	var short bool
+	if len(style) > 0 {
+		s, err := cast.ToStringE(style[0])
+		if err != nil {
+			return "", err
+		}
+		switch strings.ToLower(s) {
+		case "short":
+			short = true
+		case "long":
+		default:
+			return "", errors.Errorf("invalid duration style %q, must be \"long\" or \"short\"", s)
+		}
+	}
+
+	return ns.duration(dd, short), nil
+}
+
+// RelTime returns the time between now and t, e.g. "2 years ago" or
+// "in 3 days".
+//
+// The texts can be translated in the i18n files with the IDs relTimeAgo and
+// relTimeFromNow, e.g.:
+//
+//	[relTimeAgo]
+//	other = "vor {{ . }}"
+//
+// The duration is as returned by Duration.
+func (ns *Namespace) RelTime(t interface{}) (string, error) {
+	tt, err := cast.ToTimeE(t)
+	if err != nil {
+		return "", err
+	}
+
+	d := ns.now().Sub(tt)
+	if d < 0 {
+		return ns.translate("relTimeFromNow", ns.duration(-d, false), "in %s"), nil
+	}
+
+	return ns.translate("relTimeAgo", ns.duration(d, false), "%s ago"), nil
+}
+
+func (ns *Namespace) duration(d time.Duration, short bool) string {
+	if d < 0 {
+		d = -d
+	}
+
+	unit := durationUnits[len(durationUnits)-1]
+	for _, u := range durationUnits {
+		if d >= u.d {
+			unit = u
+			break
+		}
+	}
+
+	n := int(d / unit.d)
+
+	if short {
+		return ns.translate(unit.id+"Short", n, unit.short)
+	}
+
+	if n == 1 {
+		return ns.translate(unit.id, n, unit.one)
+	}
+
+	return ns.translate(unit.id, n, unit.other)
+}
+
+// translate returns the translation of id with the given template data or,
+// if not translated, the English default formatted with the data.
+func (ns *Namespace) translate(id string, data interface{}, format string) string {
+	if ns.deps != nil && ns.deps.Translate != nil {
+		if s := ns.deps.Translate(id, data); s != "" && !strings.HasPrefix(s, "[i18n] ") {
+			return s
+		}
+	}
+	return fmt.Sprintf(format, data)
+}
+
+func toDuration(v interface{}) (time.Duration, error) {
+	switch vv := v.(type) {
+	case time.Duration:
+		return vv, nil
+	case string:
+		return types.ParseDuration(vv)
+	}
+	return cast.ToDurationE(v)
+}
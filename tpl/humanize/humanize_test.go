@@ -0,0 +1,132 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humanize
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gohugoio/hugo/deps"
+)
+
+func TestDuration(t *testing.T) {
+	t.Parallel()
+	c := qt.New(t)
+
+	ns := New(&deps.Deps{})
+
+	for _, test := range []struct {
+		d      interface{}
+		style  []interface{}
+		expect interface{}
+	}{
+		{500 * time.Millisecond, nil, "0 seconds"},
+		{time.Second, nil, "1 second"},
+		{"90s", nil, "1 minute"},
+		{3 * time.Minute, nil, "3 minutes"},
+		{3 * time.Minute, []interface{}{"short"}, "3 min"},
+		{"-2h", nil, "2 hours"},
+		{"2d", nil, "2 days"},
+		{"13d", nil, "1 week"},
+		{"45d", []interface{}{"Long"}, "1 month"},
+		{"800d", []interface{}{"short"}, "2 yr"},
+		{"2y", nil, false},
+		{"2d", []interface{}{"tiny"}, false},
+	} {
+		errMsg := qt.Commentf("%v %v", test.d, test.style)
+
+		result, err := ns.Duration(test.d, test.style...)
+
+		if b, ok := test.expect.(bool); ok && !b {
+			c.Assert(err, qt.Not(qt.IsNil), errMsg)
+			continue
+		}
+
+		c.Assert(err, qt.IsNil, errMsg)
+		c.Assert(result, qt.Equals, test.expect, errMsg)
+	}
+}
+
+func TestRelTime(t *testing.T) {
+	t.Parallel()
+	c := qt.New(t)
+
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	ns := New(&deps.Deps{})
+	ns.now = func() time.Time { return now }
+
+	for _, test := range []struct {
+		t      interface{}
+		expect interface{}
+	}{
+		{now.AddDate(-2, -1, 0), "2 years ago"},
+		{now.Add(-time.Minute), "1 minute ago"},
+		{"2021-06-04T12:00:00Z", "in 3 days"},
+		{"foo", false},
+	} {
+		result, err := ns.RelTime(test.t)
+
+		if b, ok := test.expect.(bool); ok && !b {
+			c.Assert(err, qt.Not(qt.IsNil))
+			continue
+		}
+
+		c.Assert(err, qt.IsNil)
+		c.Assert(result, qt.Equals, test.expect)
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	t.Parallel()
+	c := qt.New(t)
+
+	translations := map[string]string{
+		"durationMinutes":      "%v Minuten",
+		"durationMinutesShort": "%v Min.",
+		"relTimeAgo":           "vor %v",
+		"durationDays":         "[i18n] durationDays",
+	}
+
+	ns := New(&deps.Deps{
+		Translate: func(id string, data interface{}) string {
+			if s, found := translations[id]; found {
+				return fmt.Sprintf(s, data)
+			}
+			return ""
+		},
+	})
+	ns.now = time.Now
+
+	s, err := ns.Duration("3m")
+	c.Assert(err, qt.IsNil)
+	c.Assert(s, qt.Equals, "3 Minuten")
+
+	s, err = ns.Duration("3m", "short")
+	c.Assert(err, qt.IsNil)
+	c.Assert(s, qt.Equals, "3 Min.")
+
+	s, err = ns.Duration("3h")
+	c.Assert(err, qt.IsNil)
+	c.Assert(s, qt.Equals, "3 hours")
+
+	s, err = ns.Duration("3d")
+	c.Assert(err, qt.IsNil)
+	c.Assert(s, qt.Equals, "3 days")
+
+	s, err = ns.RelTime(time.Now().Add(-5 * time.Minute))
+	c.Assert(err, qt.IsNil)
+	c.Assert(s, qt.Equals, "vor 5 Minuten")
+}
@@ -0,0 +1,64 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humanize
+
+import (
+	"github.com/gohugoio/hugo/deps"
+	"github.com/gohugoio/hugo/tpl/internal"
+)
+
+const name = "humanize"
+
+func init() {
+	f := func(d *deps.Deps) *internal.TemplateFuncsNamespace {
+		ctx := New(d)
+
+		ns := &internal.TemplateFuncsNamespace{
+			Name: name,
+			Context: func(args ...interface{}) interface{} {
+				// Handle overlapping "humanize" namespace and func.
+				//
+				// If no args are passed to `humanize`, assume namespace usage and
+				// return namespace context.
+				//
+				// If args are passed, call Humanize().
+				if len(args) == 0 {
+					return ctx
+				}
+				s, err := ctx.Humanize(args[0])
+				if err != nil {
+					return err
+				}
+				return s
+			},
+		}
+
+		ns.AddMethodMapping(ctx.Duration,
+			nil,
+			[][2]string{
+				{`{{ humanize.Duration "90s" }}`, `1 minute`},
+				{`{{ humanize.Duration (duration "minute" 3) "short" }}`, `3 min`},
+			},
+		)
+
+		ns.AddMethodMapping(ctx.RelTime,
+			nil,
+			[][2]string{},
+		)
+
+		return ns
+	}
+
+	internal.AddTemplateFuncsNamespace(f)
+}
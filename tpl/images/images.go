@@ -15,7 +15,12 @@
 package images
 
 import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html/template"
 	"image"
+	"image/png"
 	"sync"
 
 	"github.com/pkg/errors"
@@ -38,9 +43,11 @@ import (
 // New returns a new instance of the images-namespaced template functions.
 func New(deps *deps.Deps) *Namespace {
 	return &Namespace{
-		Filters: &images.Filters{},
-		cache:   map[string]image.Config{},
-		deps:    deps,
+		Filters:      &images.Filters{},
+		cache:        map[string]image.Config{},
+		placeholders: map[string]string{},
+		srcsets:      map[string]*Srcset{},
+		deps:         deps,
 	}
 }
 
@@ -50,6 +57,12 @@ type Namespace struct {
 	cacheMu sync.RWMutex
 	cache   map[string]image.Config
 
+	placeholdersMu sync.Mutex
+	placeholders   map[string]string
+
+	srcsetsMu sync.Mutex
+	srcsets   map[string]*Srcset
+
 	deps *deps.Deps
 }
 
@@ -102,3 +115,106 @@ func (ns *Namespace) Filter(args ...interface{}) (resource.Image, error) {
 
 	return img.Filter(filtersv...)
 }
+
+// Blurhash returns a BlurHash of the image given as the last argument, e.g.
+// for a placeholder while the image loads. The number of components on the
+// x and y axis can be given first, e.g. images.Blurhash 5 4 $img.
+// The default is 4 and 3.
+func (ns *Namespace) Blurhash(args ...interface{}) (string, error) {
+	if len(args) != 1 && len(args) != 3 {
+		return "", errors.New("must provide an image and, optionally, the x and y components")
+	}
+
+	xComponents, yComponents := 4, 3
+	if len(args) == 3 {
+		var err error
+		if xComponents, err = cast.ToIntE(args[0]); err != nil {
+			return "", err
+		}
+		if yComponents, err = cast.ToIntE(args[1]); err != nil {
+			return "", err
+		}
+	}
+
+	return ns.getOrCreatePlaceholder(fmt.Sprintf("blurhash_%d_%d", xComponents, yComponents), args[len(args)-1], func(img image.Image) (string, error) {
+		return images.EncodeBlurhash(img, xComponents, yComponents)
+	})
+}
+
+// ThumbHash returns a base64 encoded ThumbHash of the given image, e.g. for
+// a placeholder while the image loads.
+func (ns *Namespace) ThumbHash(v interface{}) (string, error) {
+	return ns.getOrCreatePlaceholder("thumbhash", v, func(img image.Image) (string, error) {
+		hash, err := images.EncodeThumbHash(img)
+		if err != nil {
+			return "", err
+		}
+		return base64.StdEncoding.EncodeToString(hash), nil
+	})
+}
+
+// Placeholder returns a blurred version of the given image, decoded from its
+// ThumbHash, as a PNG data URI, e.g. for an inline placeholder while the
+// image loads.
+func (ns *Namespace) Placeholder(v interface{}) (template.URL, error) {
+	s, err := ns.getOrCreatePlaceholder("placeholder", v, func(img image.Image) (string, error) {
+		hash, err := images.EncodeThumbHash(img)
+		if err != nil {
+			return "", err
+		}
+		decoded, err := images.DecodeThumbHash(hash)
+		if err != nil {
+			return "", err
+		}
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, decoded); err != nil {
+			return "", err
+		}
+		return "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+	})
+
+	return template.URL(s), err
+}
+
+// getOrCreatePlaceholder creates a placeholder from a version of the image
+// in v downscaled to at most 100x100 pixels, which is processed and cached
+// like other image operations.
+func (ns *Namespace) getOrCreatePlaceholder(kind string, v interface{}, create func(img image.Image) (string, error)) (string, error) {
+	img, ok := v.(resource.Image)
+	if !ok {
+		return "", errors.Errorf("type %T is not an image", v)
+	}
+
+	small, err := img.Fit("100x100")
+	if err != nil {
+		return "", err
+	}
+
+	src, ok := small.(images.ImageSource)
+	if !ok {
+		return "", errors.Errorf("type %T can not be decoded", small)
+	}
+
+	key := kind + "_" + src.Key()
+
+	ns.placeholdersMu.Lock()
+	defer ns.placeholdersMu.Unlock()
+
+	if s, found := ns.placeholders[key]; found {
+		return s, nil
+	}
+
+	decoded, err := src.DecodeImage()
+	if err != nil {
+		return "", err
+	}
+
+	s, err := create(decoded)
+	if err != nil {
+		return "", err
+	}
+
+	ns.placeholders[key] = s
+
+	return s, nil
+}
@@ -117,3 +117,32 @@ func blankImage(width, height int) []byte {
 	}
 	return buf.Bytes()
 }
+
+func TestDecodeSrcsetOptions(t *testing.T) {
+	c := qt.New(t)
+
+	opts, err := decodeSrcsetOptions(nil)
+	c.Assert(err, qt.IsNil)
+	c.Assert(opts.Widths, qt.DeepEquals, defaultSrcsetWidths)
+	c.Assert(opts.Sizes, qt.Equals, "100vw")
+	c.Assert(opts.Loading, qt.Equals, "lazy")
+
+	opts, err = decodeSrcsetOptions(map[string]interface{}{
+		"widths":  []interface{}{800, "400"},
+		"formats": []string{".WebP"},
+		"ratio":   "16:9",
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(opts.Widths, qt.DeepEquals, []int{400, 800})
+	c.Assert(opts.Formats, qt.DeepEquals, []string{"webp"})
+
+	for _, m := range []map[string]interface{}{
+		{"widths": []int{0}},
+		{"formats": []string{"avif"}},
+		{"ratio": "16/9"},
+		{"quality": 101},
+	} {
+		_, err := decodeSrcsetOptions(m)
+		c.Assert(err, qt.Not(qt.IsNil))
+	}
+}
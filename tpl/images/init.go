@@ -34,6 +34,11 @@ func init() {
 			[][2]string{},
 		)
 
+		ns.AddMethodMapping(ctx.Srcset,
+			nil,
+			[][2]string{},
+		)
+
 		return ns
 	}
 
@@ -0,0 +1,329 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package images
+
+import (
+	"fmt"
+	"html/template"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gohugoio/hugo/common/maps"
+	"github.com/gohugoio/hugo/helpers"
+	"github.com/gohugoio/hugo/resources/images"
+	"github.com/gohugoio/hugo/resources/resource"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+)
+
+var defaultSrcsetWidths = []int{320, 640, 960, 1280, 1920}
+
+// SrcsetOptions configures Srcset.
+type SrcsetOptions struct {
+	// The widths of the variants to create. Widths larger than the image
+	// are skipped. Default is 320, 640, 960, 1280 and 1920.
+	Widths []int
+
+	// The sizes attribute. Default is "100vw".
+	Sizes string
+
+	// The aspect ratio to crop the variants to, e.g. "16:9". The default is
+	// to keep the aspect ratio of the image.
+	Ratio string
+
+	// The image quality (1-100), see the imaging config.
+	Quality int
+
+	// Additional formats, e.g. ["webp"], to create the picture element's
+	// source elements for, in order of preference.
+	Formats []string
+
+	// The alt, class and loading attributes of the img element.
+	// The default loading is "lazy".
+	Alt     string
+	Class   string
+	Loading string
+}
+
+// SrcsetSource is a source element in a picture element.
+type SrcsetSource struct {
+	// The MIME type, e.g. "image/webp".
+	Type string
+
+	Srcset string
+	Images []resource.Image
+}
+
+// Srcset holds the scaled variants of an image and their attributes for
+// responsive img and picture elements.
+type Srcset struct {
+	// The fallback image, the largest variant.
+	Src resource.Image
+
+	Srcset string
+	Sizes  string
+	Width  int
+	Height int
+
+	// The variants, smallest first.
+	Images []resource.Image
+
+	// The sources for the additional formats.
+	Sources []SrcsetSource
+
+	opts SrcsetOptions
+}
+
+// Img returns an img element with the src, srcset, sizes, width and height
+// attributes set.
+func (s *Srcset) Img() template.HTML {
+	var b strings.Builder
+	b.WriteString("<img")
+	writeAttr(&b, "src", s.Src.RelPermalink())
+	writeAttr(&b, "srcset", s.Srcset)
+	writeAttr(&b, "sizes", s.Sizes)
+	writeAttr(&b, "width", strconv.Itoa(s.Width))
+	writeAttr(&b, "height", strconv.Itoa(s.Height))
+	writeAttr(&b, "alt", s.opts.Alt)
+	if s.opts.Class != "" {
+		writeAttr(&b, "class", s.opts.Class)
+	}
+	writeAttr(&b, "loading", s.opts.Loading)
+	b.WriteString(">")
+	return template.HTML(b.String())
+}
+
+// Picture returns a picture element with a source element per additional
+// format and the img element from Img.
+func (s *Srcset) Picture() template.HTML {
+	var b strings.Builder
+	b.WriteString("<picture>")
+	for _, source := range s.Sources {
+		b.WriteString("<source")
+		writeAttr(&b, "type", source.Type)
+		writeAttr(&b, "srcset", source.Srcset)
+		writeAttr(&b, "sizes", s.Sizes)
+		b.WriteString(">")
+	}
+	b.WriteString(string(s.Img()))
+	b.WriteString("</picture>")
+	return template.HTML(b.String())
+}
+
+func writeAttr(b *strings.Builder, name, value string) {
+	b.WriteString(" " + name + `="` + template.HTMLEscapeString(value) + `"`)
+}
+
+// Srcset creates the scaled variants of the image given as the last
+// argument, for responsive images, e.g.
+// {{ (images.Srcset (dict "sizes" "50vw" "formats" (slice "webp")) $img).Picture }}.
+// The options, see SrcsetOptions, are optional.
+func (ns *Namespace) Srcset(args ...interface{}) (*Srcset, error) {
+	if len(args) != 1 && len(args) != 2 {
+		return nil, errors.New("must provide an image and, optionally, the options")
+	}
+
+	img, ok := args[len(args)-1].(resource.Image)
+	if !ok {
+		return nil, errors.Errorf("type %T is not an image", args[len(args)-1])
+	}
+
+	var m map[string]interface{}
+	if len(args) == 2 {
+		var err error
+		if m, err = maps.ToStringMapE(args[0]); err != nil {
+			return nil, err
+		}
+	}
+
+	opts, err := decodeSrcsetOptions(m)
+	if err != nil {
+		return nil, err
+	}
+
+	key := helpers.HashString(opts)
+	if id, ok := img.(resource.Identifier); ok {
+		key = id.Key() + "_" + key
+	} else {
+		key = img.RelPermalink() + "_" + key
+	}
+
+	ns.srcsetsMu.Lock()
+	defer ns.srcsetsMu.Unlock()
+
+	if s, found := ns.srcsets[key]; found {
+		return s, nil
+	}
+
+	s, err := newSrcset(img, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	ns.srcsets[key] = s
+
+	return s, nil
+}
+
+func newSrcset(img resource.Image, opts SrcsetOptions) (*Srcset, error) {
+	ratioW, ratioH, err := parseRatio(opts.Ratio)
+	if err != nil {
+		return nil, err
+	}
+
+	type size struct{ w, h int }
+	var sizes []size
+	for _, w := range opts.Widths {
+		var h int
+		if ratioW > 0 {
+			h = w * ratioH / ratioW
+		}
+		if w > img.Width() || h > img.Height() {
+			continue
+		}
+		sizes = append(sizes, size{w, h})
+	}
+
+	if len(sizes) == 0 {
+		// The image is smaller than all the widths, use it as is.
+		w, h := img.Width(), 0
+		if ratioW > 0 {
+			if h = w * ratioH / ratioW; h > img.Height() {
+				h = img.Height()
+				w = h * ratioW / ratioH
+			}
+		}
+		sizes = append(sizes, size{w, h})
+	}
+
+	process := func(format string) ([]resource.Image, string, error) {
+		var (
+			processed []resource.Image
+			srcset    []string
+		)
+		for _, size := range sizes {
+			spec := fmt.Sprintf("%dx", size.w)
+			if size.h > 0 {
+				spec += strconv.Itoa(size.h)
+			}
+			if format != "" {
+				spec += " " + format
+			}
+			if opts.Quality > 0 {
+				spec += " q" + strconv.Itoa(opts.Quality)
+			}
+
+			var (
+				variant resource.Image
+				err     error
+			)
+			if size.h > 0 {
+				variant, err = img.Fill(spec)
+			} else {
+				variant, err = img.Resize(spec)
+			}
+			if err != nil {
+				return nil, "", err
+			}
+
+			processed = append(processed, variant)
+			srcset = append(srcset, fmt.Sprintf("%s %dw", variant.RelPermalink(), variant.Width()))
+		}
+		return processed, strings.Join(srcset, ", "), nil
+	}
+
+	s := &Srcset{Sizes: opts.Sizes, opts: opts}
+
+	for _, format := range opts.Formats {
+		processed, srcset, err := process(format)
+		if err != nil {
+			return nil, err
+		}
+		s.Sources = append(s.Sources, SrcsetSource{
+			Type:   processed[0].MediaType().Type(),
+			Srcset: srcset,
+			Images: processed,
+		})
+	}
+
+	if s.Images, s.Srcset, err = process(""); err != nil {
+		return nil, err
+	}
+
+	s.Src = s.Images[len(s.Images)-1]
+	s.Width = s.Src.Width()
+	s.Height = s.Src.Height()
+
+	return s, nil
+}
+
+func decodeSrcsetOptions(m map[string]interface{}) (SrcsetOptions, error) {
+	opts := SrcsetOptions{
+		Sizes:   "100vw",
+		Loading: "lazy",
+	}
+
+	if err := mapstructure.WeakDecode(m, &opts); err != nil {
+		return opts, errors.Wrap(err, "failed to decode srcset options")
+	}
+
+	if len(opts.Widths) == 0 {
+		opts.Widths = defaultSrcsetWidths
+	}
+	for _, w := range opts.Widths {
+		if w <= 0 {
+			return opts, errors.Errorf("invalid srcset width %d", w)
+		}
+	}
+	widths := append([]int(nil), opts.Widths...)
+	sort.Ints(widths)
+	opts.Widths = widths
+
+	if opts.Quality < 0 || opts.Quality > 100 {
+		return opts, errors.New("image quality must be a number between 1 and 100")
+	}
+
+	formats := make([]string, len(opts.Formats))
+	for i, format := range opts.Formats {
+		format = strings.ToLower(strings.TrimPrefix(format, "."))
+		if _, found := images.ImageFormatFromExt("." + format); !found {
+			return opts, errors.Errorf("unsupported srcset format %q", format)
+		}
+		formats[i] = format
+	}
+	opts.Formats = formats
+
+	if _, _, err := parseRatio(opts.Ratio); err != nil {
+		return opts, err
+	}
+
+	return opts, nil
+}
+
+// parseRatio parses an aspect ratio on the form "16:9".
+func parseRatio(s string) (w, h int, err error) {
+	if s == "" {
+		return 0, 0, nil
+	}
+	parts := strings.Split(s, ":")
+	if len(parts) == 2 {
+		w, err1 := strconv.Atoi(strings.TrimSpace(parts[0]))
+		h, err2 := strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err1 == nil && err2 == nil && w > 0 && h > 0 {
+			return w, h, nil
+		}
+	}
+	return 0, 0, errors.Errorf("invalid aspect ratio %q, must be on the form \"16:9\"", s)
+}
@@ -29,8 +29,9 @@ func init() {
 			Context: func(args ...interface{}) interface{} { return ctx },
 		}
 
+		// The humanize func is provided by the humanize namespace.
 		ns.AddMethodMapping(ctx.Humanize,
-			[]string{"humanize"},
+			nil,
 			[][2]string{
 				{`{{ humanize "my-first-post" }}`, `My first post`},
 				{`{{ humanize "myCamelPost" }}`, `My camel post`},
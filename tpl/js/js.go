@@ -28,7 +28,7 @@ func New(deps *deps.Deps) *Namespace {
 		return &Namespace{}
 	}
 	return &Namespace{
-		client: js.New(deps.BaseFs.Assets, deps.ResourceSpec),
+		client: js.New(deps.BaseFs.Assets, deps.ResourceSpec, deps.Running),
 	}
 }
 
@@ -48,6 +48,11 @@ func init() {
 			[][2]string{},
 		)
 
+		ns.AddMethodMapping(ctx.CacheOptions,
+			nil,
+			[][2]string{},
+		)
+
 		return ns
 	}
 
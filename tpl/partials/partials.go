@@ -24,9 +24,11 @@ import (
 	"reflect"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/gohugoio/hugo/common/hreflect"
 	texttemplate "github.com/gohugoio/hugo/tpl/internal/go_templates/texttemplate"
+	"github.com/mitchellh/mapstructure"
 
 	"github.com/gohugoio/hugo/helpers"
 
@@ -45,25 +47,61 @@ type partialCacheKey struct {
 	variant interface{}
 }
 
+type partialCacheEntry struct {
+	value interface{}
+
+	// When the entry expires. If zero, it is cleared before every build.
+	expires time.Time
+
+	// The keys to invalidate the entry with.
+	keys []string
+}
+
+func (e *partialCacheEntry) isExpired(now time.Time) bool {
+	return !e.expires.IsZero() && !now.Before(e.expires)
+}
+
 // partialCache represents a cache of partials protected by a mutex.
 type partialCache struct {
 	sync.RWMutex
-	p map[partialCacheKey]interface{}
+	p map[partialCacheKey]*partialCacheEntry
 }
 
+// clear clears the cache, except for the entries cached with a TTL that have
+// not expired.
 func (p *partialCache) clear() {
 	p.Lock()
 	defer p.Unlock()
-	p.p = make(map[partialCacheKey]interface{})
+	now := time.Now()
+	for k, e := range p.p {
+		if e.expires.IsZero() || e.isExpired(now) {
+			delete(p.p, k)
+		}
+	}
+}
+
+// invalidate removes the entries cached with any of the given keys.
+func (p *partialCache) invalidate(keys ...string) {
+	p.Lock()
+	defer p.Unlock()
+	for k, e := range p.p {
+		for _, key := range e.keys {
+			if helpers.InStringArray(keys, key) {
+				delete(p.p, k)
+				break
+			}
+		}
+	}
 }
 
 // New returns a new instance of the templates-namespaced template functions.
 func New(deps *deps.Deps) *Namespace {
-	cache := &partialCache{p: make(map[partialCacheKey]interface{})}
+	cache := &partialCache{p: make(map[partialCacheKey]*partialCacheEntry)}
 	deps.BuildStartListeners.Add(
 		func() {
 			cache.clear()
 		})
+	deps.CacheInvalidationListeners.Add(cache.invalidate)
 
 	return &Namespace{
 		deps:           deps,
@@ -161,18 +199,67 @@ func (ns *Namespace) Include(name string, contextList ...interface{}) (interface
 	return result, nil
 }
 
+// CacheOptions configures how IncludeCached caches a partial.
+type CacheOptions struct {
+	// How long to cache the partial. If set, the cached partial is kept
+	// across rebuilds in server mode until it expires, and the sites are
+	// rebuilt when it does. This is meant for partials showing remote data.
+	TTL time.Duration
+
+	// Keys to invalidate the cached partial with, e.g. by a webhook in
+	// server mode.
+	Keys []string
+}
+
+// CacheOptions creates CacheOptions from the given map, to pass to
+// IncludeCached among the variants, e.g.
+//
+//	{{ partialCached "products.html" . (partials.CacheOptions (dict "ttl" "10m" "keys" "products")) }}
+func (ns *Namespace) CacheOptions(m map[string]interface{}) (CacheOptions, error) {
+	var opts CacheOptions
+
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		WeaklyTypedInput: true,
+		DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+		Result:           &opts,
+	})
+	if err != nil {
+		return opts, err
+	}
+
+	if err := decoder.Decode(m); err != nil {
+		return opts, err
+	}
+
+	if opts.TTL < 0 {
+		return opts, errors.New("cache TTL cannot be negative")
+	}
+
+	return opts, nil
+}
+
 // IncludeCached executes and caches partial templates.  The cache is created with name+variants as the key.
+// Any CacheOptions among the variants configure the caching and are not part of the key.
 func (ns *Namespace) IncludeCached(name string, context interface{}, variants ...interface{}) (interface{}, error) {
+	var opts CacheOptions
+	for i := 0; i < len(variants); i++ {
+		if o, ok := variants[i].(CacheOptions); ok {
+			opts = o
+			variants = append(variants[:i:i], variants[i+1:]...)
+			i--
+		}
+	}
+
 	key, err := createKey(name, variants...)
 	if err != nil {
 		return nil, err
 	}
 
-	result, err := ns.getOrCreate(key, context)
+	result, err := ns.getOrCreate(key, context, opts)
 	if err == errUnHashable {
 		// Try one more
 		key.variant = helpers.HashString(key.variant)
-		result, err = ns.getOrCreate(key, context)
+		result, err = ns.getOrCreate(key, context, opts)
 	}
 
 	return result, err
@@ -201,7 +288,7 @@ func createKey(name string, variants ...interface{}) (partialCacheKey, error) {
 
 var errUnHashable = errors.New("unhashable")
 
-func (ns *Namespace) getOrCreate(key partialCacheKey, context interface{}) (result interface{}, err error) {
+func (ns *Namespace) getOrCreate(key partialCacheKey, context interface{}, opts CacheOptions) (result interface{}, err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			err = r.(error)
@@ -213,14 +300,15 @@ func (ns *Namespace) getOrCreate(key partialCacheKey, context interface{}) (resu
 	}()
 
 	ns.cachedPartials.RLock()
-	p, ok := ns.cachedPartials.p[key]
+	e, ok := ns.cachedPartials.p[key]
 	ns.cachedPartials.RUnlock()
 
-	if ok {
-		return p, nil
+	if ok && !e.isExpired(time.Now()) {
+		ns.addCacheExpiry(e)
+		return e.value, nil
 	}
 
-	p, err = ns.Include(key.name, context)
+	p, err := ns.Include(key.name, context)
 	if err != nil {
 		return nil, err
 	}
@@ -228,10 +316,24 @@ func (ns *Namespace) getOrCreate(key partialCacheKey, context interface{}) (resu
 	ns.cachedPartials.Lock()
 	defer ns.cachedPartials.Unlock()
 	// Double-check.
-	if p2, ok := ns.cachedPartials.p[key]; ok {
-		return p2, nil
+	if e2, ok := ns.cachedPartials.p[key]; ok && e2 != e && !e2.isExpired(time.Now()) {
+		ns.addCacheExpiry(e2)
+		return e2.value, nil
 	}
-	ns.cachedPartials.p[key] = p
+
+	e = &partialCacheEntry{value: p, keys: opts.Keys}
+	if opts.TTL > 0 {
+		e.expires = time.Now().Add(opts.TTL)
+	}
+	ns.cachedPartials.p[key] = e
+	ns.addCacheExpiry(e)
 
 	return p, nil
 }
+
+func (ns *Namespace) addCacheExpiry(e *partialCacheEntry) {
+	if e.expires.IsZero() || ns.deps.BuildState == nil {
+		return
+	}
+	ns.deps.BuildState.AddCacheExpiry(e.expires)
+}
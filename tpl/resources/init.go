@@ -65,6 +65,16 @@ func init() {
 			[][2]string{},
 		)
 
+		ns.AddMethodMapping(ctx.SVG,
+			nil,
+			[][2]string{},
+		)
+
+		ns.AddMethodMapping(ctx.CSSModules,
+			nil,
+			[][2]string{},
+		)
+
 		return ns
 	}
 
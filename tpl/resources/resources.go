@@ -34,9 +34,11 @@ import (
 	"github.com/gohugoio/hugo/resources/resource_factories/bundler"
 	"github.com/gohugoio/hugo/resources/resource_factories/create"
 	"github.com/gohugoio/hugo/resources/resource_transformers/babel"
+	"github.com/gohugoio/hugo/resources/resource_transformers/cssmodules"
 	"github.com/gohugoio/hugo/resources/resource_transformers/integrity"
 	"github.com/gohugoio/hugo/resources/resource_transformers/minifier"
 	"github.com/gohugoio/hugo/resources/resource_transformers/postcss"
+	"github.com/gohugoio/hugo/resources/resource_transformers/svg"
 	"github.com/gohugoio/hugo/resources/resource_transformers/templates"
 	"github.com/gohugoio/hugo/resources/resource_transformers/tocss/dartsass"
 	"github.com/gohugoio/hugo/resources/resource_transformers/tocss/scss"
@@ -60,6 +62,11 @@ func New(deps *deps.Deps) (*Namespace, error) {
 		return nil, err
 	}
 
+	svgClient, err := svg.New(deps.ResourceSpec)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Namespace{
 		deps:              deps,
 		scssClientLibSass: scssClient,
@@ -70,6 +77,8 @@ func New(deps *deps.Deps) (*Namespace, error) {
 		postcssClient:     postcss.New(deps.ResourceSpec),
 		templatesClient:   templates.New(deps.ResourceSpec, deps),
 		babelClient:       babel.New(deps.ResourceSpec),
+		svgClient:         svgClient,
+		cssModulesClient:  cssmodules.New(deps.ResourceSpec),
 	}, nil
 }
 
@@ -84,6 +93,8 @@ type Namespace struct {
 	minifyClient      *minifier.Client
 	postcssClient     *postcss.Client
 	babelClient       *babel.Client
+	svgClient         *svg.Client
+	cssModulesClient  *cssmodules.Client
 	templatesClient   *templates.Client
 
 	// The Dart Client requires a os/exec process, so  only
@@ -252,24 +263,44 @@ func (ns *Namespace) Minify(r resources.ResourceTransformer) (resource.Resource,
 	return ns.minifyClient.Minify(r)
 }
 
+const (
+	// Transpiler implementation can be controlled from the client by
+	// setting the 'transpiler' option, or for all in the site config
+	// with sass.transpiler.
+	// Default is currently 'libsass', but that may change.
+	// Note that Dart Sass needs dart-sass-embedded installed, there is no
+	// fallback to LibSass if it's not.
+	transpilerDart    = "dartsass"
+	transpilerLibSass = "libsass"
+)
+
+// resolveTranspiler resolves the transpiler to use, given the transpiler
+// option t, or the site config if not set.
+func (ns *Namespace) resolveTranspiler(t interface{}) (string, error) {
+	if t == nil {
+		t = ns.deps.Cfg.GetString("sass.transpiler")
+	}
+
+	switch t {
+	case "":
+		return transpilerLibSass, nil
+	case transpilerDart, transpilerLibSass:
+		return cast.ToString(t), nil
+	default:
+		return "", errors.Errorf("unsupported transpiler %q; valid values are %q or %q", t, transpilerLibSass, transpilerDart)
+	}
+}
+
 // ToCSS converts the given Resource to CSS. You can optional provide an Options
 // object or a target path (string) as first argument.
 func (ns *Namespace) ToCSS(args ...interface{}) (resource.Resource, error) {
-	const (
-		// Transpiler implementation can be controlled from the client by
-		// setting the 'transpiler' option.
-		// Default is currently 'libsass', but that may change.
-		transpilerDart    = "dartsass"
-		transpilerLibSass = "libsass"
-	)
-
 	var (
 		r          resources.ResourceTransformer
 		m          map[string]interface{}
 		targetPath string
 		err        error
 		ok         bool
-		transpiler = transpilerLibSass
+		t          interface{}
 	)
 
 	r, targetPath, ok = resourcehelpers.ResolveIfFirstArgIsString(args)
@@ -283,14 +314,12 @@ func (ns *Namespace) ToCSS(args ...interface{}) (resource.Resource, error) {
 
 	if m != nil {
 		maps.PrepareParams(m)
-		if t, found := m["transpiler"]; found {
-			switch t {
-			case transpilerDart, transpilerLibSass:
-				transpiler = cast.ToString(t)
-			default:
-				return nil, errors.Errorf("unsupported transpiler %q; valid values are %q or %q", t, transpilerLibSass, transpilerDart)
-			}
-		}
+		t = m["transpiler"]
+	}
+
+	transpiler, err := ns.resolveTranspiler(t)
+	if err != nil {
+		return nil, err
 	}
 
 	if transpiler == transpilerLibSass {
@@ -361,3 +390,36 @@ func (ns *Namespace) Babel(args ...interface{}) (resource.Resource, error) {
 
 	return ns.babelClient.Process(r, options)
 }
+
+// SVG optimizes and manipulates the given SVG Resource. It is minified with
+// any metadata and scripts removed by default, see svg.Options.
+func (ns *Namespace) SVG(args ...interface{}) (resource.Resource, error) {
+	r, m, err := resourcehelpers.ResolveArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	options, err := svg.DecodeOptions(m)
+	if err != nil {
+		return nil, err
+	}
+
+	return ns.svgClient.Process(r, options)
+}
+
+// CSSModules scopes the class names in the given stylesheet, as in CSS
+// Modules. The mapping from the original to the scoped class names is
+// available in .Data.Classes.
+func (ns *Namespace) CSSModules(args ...interface{}) (resource.Resource, error) {
+	r, m, err := resourcehelpers.ResolveArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	options, err := cssmodules.DecodeOptions(m)
+	if err != nil {
+		return nil, err
+	}
+
+	return ns.cssModulesClient.Process(r, options)
+}
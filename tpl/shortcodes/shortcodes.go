@@ -0,0 +1,111 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package shortcodes provides template functions to render shortcodes from
+// templates.
+package shortcodes
+
+import (
+	"html/template"
+	"reflect"
+
+	"github.com/gohugoio/hugo/deps"
+	"github.com/gohugoio/hugo/resources/page"
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+)
+
+// New returns a new instance of the shortcodes-namespaced template functions.
+func New(deps *deps.Deps) *Namespace {
+	return &Namespace{
+		deps: deps,
+	}
+}
+
+// Namespace provides template functions for the "shortcodes" namespace.
+type Namespace struct {
+	deps *deps.Deps
+}
+
+// shortcodeRenderer is implemented by the Site.
+type shortcodeRenderer interface {
+	RenderShortcode(p page.Page, name string, params interface{}, inner string) (template.HTML, error)
+}
+
+type pageProvider interface {
+	Page() page.Page
+}
+
+// The render hook contexts return the Page as an interface{}.
+type hookPageProvider interface {
+	Page() interface{}
+}
+
+// Render renders the named shortcode, so a component can be shared between
+// layouts, render hooks and content. The optional args are told apart by
+// their type, and may be given in any order:
+//
+// A map sets the named params and a slice the positional params, e.g.
+// (dict "src" "image.jpg") or (slice "image.jpg").
+// A string sets the inner content.
+// A Page, or a context with a Page, e.g. the one in a render hook, sets the
+// shortcode's .Page, which defaults to the home page.
+func (ns *Namespace) Render(name interface{}, args ...interface{}) (template.HTML, error) {
+	sname, err := cast.ToStringE(name)
+	if err != nil {
+		return "", err
+	}
+
+	r, ok := ns.deps.Site.(shortcodeRenderer)
+	if !ok {
+		return "", errors.New("shortcodes.Render: not supported in this context")
+	}
+
+	var (
+		p        page.Page
+		params   interface{}
+		inner    string
+		innerSet bool
+	)
+
+	for _, arg := range args {
+		switch v := arg.(type) {
+		case pageProvider, hookPageProvider:
+			if p != nil {
+				return "", errors.New("shortcodes.Render: more than one page provided")
+			}
+			if pp, ok := v.(pageProvider); ok {
+				p = pp.Page()
+			} else if p, ok = v.(hookPageProvider).Page().(page.Page); !ok {
+				return "", errors.Errorf("shortcodes.Render: %T has no Page", arg)
+			}
+		case string, template.HTML:
+			if innerSet {
+				return "", errors.New("shortcodes.Render: more than one inner content provided")
+			}
+			inner, innerSet = cast.ToString(v), true
+		default:
+			switch reflect.ValueOf(arg).Kind() {
+			case reflect.Map, reflect.Slice:
+				if params != nil {
+					return "", errors.New("shortcodes.Render: more than one set of params provided")
+				}
+				params = arg
+			default:
+				return "", errors.Errorf("shortcodes.Render: unsupported argument type %T", arg)
+			}
+		}
+	}
+
+	return r.RenderShortcode(p, sname, params, inner)
+}
@@ -0,0 +1,65 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shortcodes
+
+import (
+	"html/template"
+	"testing"
+
+	"github.com/gohugoio/hugo/deps"
+	"github.com/gohugoio/hugo/resources/page"
+
+	qt "github.com/frankban/quicktest"
+)
+
+type testSite struct {
+	page.Site
+	p      page.Page
+	params interface{}
+	inner  string
+}
+
+func (s *testSite) RenderShortcode(p page.Page, name string, params interface{}, inner string) (template.HTML, error) {
+	s.p, s.params, s.inner = p, params, inner
+	return template.HTML("rendered " + name), nil
+}
+
+func TestRender(t *testing.T) {
+	c := qt.New(t)
+
+	site := &testSite{}
+	ns := New(&deps.Deps{Site: site})
+
+	res, err := ns.Render("box", "inner", page.NopPage, map[string]interface{}{"a": 1})
+	c.Assert(err, qt.IsNil)
+	c.Assert(res, qt.Equals, template.HTML("rendered box"))
+	c.Assert(site.p, qt.Equals, page.NopPage)
+	c.Assert(site.params, qt.DeepEquals, map[string]interface{}{"a": 1})
+	c.Assert(site.inner, qt.Equals, "inner")
+
+	_, err = ns.Render("box", []interface{}{"a"})
+	c.Assert(err, qt.IsNil)
+	c.Assert(site.p, qt.IsNil)
+	c.Assert(site.params, qt.DeepEquals, []interface{}{"a"})
+	c.Assert(site.inner, qt.Equals, "")
+
+	_, err = ns.Render("box", "a", "b")
+	c.Assert(err, qt.ErrorMatches, ".*more than one inner content provided")
+	_, err = ns.Render("box", 42)
+	c.Assert(err, qt.ErrorMatches, ".*unsupported argument type int")
+
+	ns = New(&deps.Deps{})
+	_, err = ns.Render("box")
+	c.Assert(err, qt.ErrorMatches, ".*not supported in this context")
+}
@@ -46,6 +46,20 @@ func init() {
 			[][2]string{},
 		)
 
+		ns.AddMethodMapping(ctx.Levenshtein,
+			nil,
+			[][2]string{
+				{`{{ strings.Levenshtein "kitten" "sitting" }}`, `3`},
+			},
+		)
+
+		ns.AddMethodMapping(ctx.Similarity,
+			nil,
+			[][2]string{
+				{`{{ strings.Similarity "hugo" "hugs" }}`, `0.75`},
+			},
+		)
+
 		ns.AddMethodMapping(ctx.CountWords,
 			[]string{"countwords"},
 			[][2]string{},
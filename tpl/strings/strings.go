@@ -21,6 +21,7 @@ import (
 	"strings"
 	"unicode/utf8"
 
+	"github.com/gohugoio/hugo/compare"
 	"github.com/gohugoio/hugo/deps"
 	"github.com/gohugoio/hugo/helpers"
 
@@ -501,3 +502,31 @@ func (ns *Namespace) Repeat(n, s interface{}) (string, error) {
 
 	return strings.Repeat(ss, sn), nil
 }
+
+// Levenshtein returns the Levenshtein distance between s1 and s2, i.e. the
+// number of single character edits needed to change one into the other.
+func (ns *Namespace) Levenshtein(s1, s2 interface{}) (int, error) {
+	ss1, err := cast.ToStringE(s1)
+	if err != nil {
+		return 0, err
+	}
+	ss2, err := cast.ToStringE(s2)
+	if err != nil {
+		return 0, err
+	}
+	return compare.Levenshtein(ss1, ss2), nil
+}
+
+// Similarity returns how similar s1 and s2 are, from 0 (nothing in common)
+// to 1 (equal), based on their Levenshtein distance.
+func (ns *Namespace) Similarity(s1, s2 interface{}) (float64, error) {
+	ss1, err := cast.ToStringE(s1)
+	if err != nil {
+		return 0, err
+	}
+	ss2, err := cast.ToStringE(s2)
+	if err != nil {
+		return 0, err
+	}
+	return compare.Similarity(ss1, ss2), nil
+}
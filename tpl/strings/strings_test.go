@@ -785,3 +785,64 @@ func TestRepeat(t *testing.T) {
 		c.Assert(result, qt.Equals, test.expect)
 	}
 }
+
+func TestLevenshtein(t *testing.T) {
+	t.Parallel()
+	c := qt.New(t)
+
+	for _, test := range []struct {
+		s1     interface{}
+		s2     interface{}
+		expect interface{}
+	}{
+		{"kitten", "sitting", 3},
+		{"", "abc", 3},
+		{"hugo", "hugo", 0},
+		{123, 124, 1},
+		{template.HTML("<a>"), "<b>", 1},
+		// errors
+		{tstNoStringer{}, "a", false},
+		{"a", tstNoStringer{}, false},
+	} {
+
+		result, err := ns.Levenshtein(test.s1, test.s2)
+
+		if b, ok := test.expect.(bool); ok && !b {
+			c.Assert(err, qt.Not(qt.IsNil))
+			continue
+		}
+
+		c.Assert(err, qt.IsNil)
+		c.Assert(result, qt.Equals, test.expect)
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	t.Parallel()
+	c := qt.New(t)
+
+	for _, test := range []struct {
+		s1     interface{}
+		s2     interface{}
+		expect interface{}
+	}{
+		{"hugo", "hugs", 0.75},
+		{"hugo", "hugo", 1.0},
+		{"", "", 1.0},
+		{"abc", "xyz", 0.0},
+		// errors
+		{tstNoStringer{}, "a", false},
+		{"a", tstNoStringer{}, false},
+	} {
+
+		result, err := ns.Similarity(test.s1, test.s2)
+
+		if b, ok := test.expect.(bool); ok && !b {
+			c.Assert(err, qt.Not(qt.IsNil))
+			continue
+		}
+
+		c.Assert(err, qt.IsNil)
+		c.Assert(result, qt.Equals, test.expect)
+	}
+}
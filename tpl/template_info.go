@@ -34,6 +34,22 @@ type InfoManager interface {
 	identity.Manager
 }
 
+// HashesProvider provides the hashes of the source of a template and of the
+// templates it depends on, e.g. to trace a published page back to the
+// templates it was rendered with.
+type HashesProvider interface {
+	// TemplateHashes returns the hex encoded SHA-256 hashes of the
+	// templates, keyed by template name.
+	TemplateHashes() map[string]string
+}
+
+// PageReadsProvider tells whether a template, including the partials it
+// includes, may read other pages than the page rendered and the pages below
+// it, e.g. to decide which list pages to re-render when a page changes.
+type PageReadsProvider interface {
+	ReadsOtherPages() bool
+}
+
 type defaultInfo struct {
 	identity.Manager
 	parseInfo ParseInfo
@@ -57,6 +73,11 @@ type ParseInfo struct {
 	// Set for partials with a return statement.
 	HasReturn bool
 
+	// Set for templates that may read other pages than the page rendered
+	// and the pages below it, e.g. with site.RegularPages, .GetPage or a
+	// partial with a dynamic name. This does not include the partials.
+	ReadsOtherPages bool
+
 	// Config extracted from template.
 	Config ParseConfig
 }
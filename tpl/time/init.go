@@ -87,6 +87,24 @@ func init() {
 			nil,
 			[][2]string{
 				{`{{ "1h12m10s" | time.ParseDuration }}`, `1h12m10s`},
+				{`{{ "1w2d" | time.ParseDuration }}`, `216h0m0s`},
+			},
+		)
+
+		ns.AddMethodMapping(ctx.Since,
+			nil,
+			[][2]string{},
+		)
+
+		ns.AddMethodMapping(ctx.Until,
+			nil,
+			[][2]string{},
+		)
+
+		ns.AddMethodMapping(ctx.Expand,
+			nil,
+			[][2]string{
+				{`{{ range time.Expand "DTSTART:20210105T180000Z RRULE:FREQ=WEEKLY;BYDAY=TU,TH;COUNT=3" "2021-01-01" "2022-01-01" }}{{ .Format "Jan 2 15:04" }}|{{ end }}`, `Jan 5 18:00|Jan 7 18:00|Jan 12 18:00|`},
 			},
 		)
 
@@ -0,0 +1,472 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package time
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	_time "time"
+
+	"github.com/spf13/cast"
+)
+
+// maxOccurrences is the maximum number of occurrences Expand returns.
+const maxOccurrences = 10000
+
+var rruleWeekdays = map[string]_time.Weekday{
+	"SU": _time.Sunday,
+	"MO": _time.Monday,
+	"TU": _time.Tuesday,
+	"WE": _time.Wednesday,
+	"TH": _time.Thursday,
+	"FR": _time.Friday,
+	"SA": _time.Saturday,
+}
+
+// Expand returns the occurrences of the iCalendar (RFC 5545) recurrence rule
+// in rule from (inclusive) to to (exclusive), e.g.:
+//
+//	{{ range time.Expand "DTSTART:20210105T180000Z\nRRULE:FREQ=WEEKLY;BYDAY=TU,TH" now (now.AddDate 0 1 0) }}
+//
+// The rule may start with a DTSTART, with an optional TZID, and may contain
+// EXDATE lines. If it has no DTSTART, the occurrences start at from.
+// The DAILY, WEEKLY, MONTHLY and YEARLY frequencies are supported with the
+// INTERVAL, COUNT, UNTIL, BYDAY, BYMONTHDAY, BYMONTH and WKST rule parts.
+func (ns *Namespace) Expand(rule interface{}, from, to interface{}) ([]_time.Time, error) {
+	ruleStr, err := cast.ToStringE(rule)
+	if err != nil {
+		return nil, err
+	}
+	fromTime, err := cast.ToTimeE(from)
+	if err != nil {
+		return nil, err
+	}
+	toTime, err := cast.ToTimeE(to)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := parseRecurrence(ruleStr, fromTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse recurrence rule %q: %s", ruleStr, err)
+	}
+
+	return r.between(fromTime, toTime)
+}
+
+type weekdayNum struct {
+	// The nth occurrence of the weekday in the month or year,
+	// negative if counted from the end, 0 for every occurrence.
+	n       int
+	weekday _time.Weekday
+}
+
+type recurrence struct {
+	start    _time.Time
+	freq     string
+	interval int
+	count    int
+	until    _time.Time
+	wkst     _time.Weekday
+
+	byDay      []weekdayNum
+	byMonthDay []int
+	byMonth    []int
+
+	exdates map[int64]bool
+}
+
+func parseRecurrence(s string, from _time.Time) (*recurrence, error) {
+	r := &recurrence{
+		interval: 1,
+		wkst:     _time.Monday,
+		exdates:  make(map[int64]bool),
+	}
+
+	var (
+		rrule   string
+		exdates [][2]string
+	)
+
+	for _, line := range strings.Fields(s) {
+		name, value := "RRULE", line
+		if i := strings.Index(line, ":"); i != -1 {
+			name, value = line[:i], line[i+1:]
+		}
+
+		var params string
+		if i := strings.Index(name, ";"); i != -1 {
+			name, params = name[:i], name[i+1:]
+		}
+
+		switch strings.ToUpper(name) {
+		case "DTSTART":
+			start, err := parseRecurrenceTime(value, params, from.Location())
+			if err != nil {
+				return nil, err
+			}
+			r.start = start
+		case "RRULE":
+			if rrule != "" {
+				return nil, fmt.Errorf("multiple RRULEs are not supported")
+			}
+			rrule = value
+		case "EXDATE":
+			for _, v := range strings.Split(value, ",") {
+				exdates = append(exdates, [2]string{v, params})
+			}
+		default:
+			return nil, fmt.Errorf("unsupported property %q", name)
+		}
+	}
+
+	if rrule == "" {
+		return nil, fmt.Errorf("no RRULE")
+	}
+
+	if r.start.IsZero() {
+		r.start = from
+	}
+
+	for _, v := range exdates {
+		t, err := parseRecurrenceTime(v[0], v[1], r.start.Location())
+		if err != nil {
+			return nil, err
+		}
+		r.exdates[t.Unix()] = true
+	}
+
+	for _, part := range strings.Split(rrule, ";") {
+		if part == "" {
+			continue
+		}
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid rule part %q", part)
+		}
+		key, value := strings.ToUpper(kv[0]), strings.ToUpper(kv[1])
+
+		var err error
+		switch key {
+		case "FREQ":
+			switch value {
+			case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+				r.freq = value
+			default:
+				return nil, fmt.Errorf("unsupported FREQ %q", value)
+			}
+		case "INTERVAL":
+			r.interval, err = strconv.Atoi(value)
+			if err == nil && r.interval < 1 {
+				err = fmt.Errorf("INTERVAL must be positive")
+			}
+		case "COUNT":
+			r.count, err = strconv.Atoi(value)
+			if err == nil && r.count < 1 {
+				err = fmt.Errorf("COUNT must be positive")
+			}
+		case "UNTIL":
+			r.until, err = parseRecurrenceTime(value, "", r.start.Location())
+		case "WKST":
+			wd, found := rruleWeekdays[value]
+			if !found {
+				err = fmt.Errorf("invalid WKST %q", value)
+			}
+			r.wkst = wd
+		case "BYDAY":
+			for _, v := range strings.Split(value, ",") {
+				if len(v) < 2 {
+					return nil, fmt.Errorf("invalid BYDAY %q", v)
+				}
+				wd, found := rruleWeekdays[v[len(v)-2:]]
+				if !found {
+					return nil, fmt.Errorf("invalid BYDAY %q", v)
+				}
+				var n int
+				if prefix := v[:len(v)-2]; prefix != "" {
+					n, err = strconv.Atoi(strings.TrimPrefix(prefix, "+"))
+					if err != nil || n == 0 || n < -53 || n > 53 {
+						return nil, fmt.Errorf("invalid BYDAY %q", v)
+					}
+				}
+				r.byDay = append(r.byDay, weekdayNum{n: n, weekday: wd})
+			}
+		case "BYMONTHDAY":
+			r.byMonthDay, err = parseRecurrenceInts(value, -31, 31)
+		case "BYMONTH":
+			r.byMonth, err = parseRecurrenceInts(value, 1, 12)
+		default:
+			return nil, fmt.Errorf("unsupported rule part %q", key)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if r.freq == "" {
+		return nil, fmt.Errorf("no FREQ")
+	}
+
+	for _, d := range r.byDay {
+		if d.n != 0 && r.freq != "MONTHLY" && r.freq != "YEARLY" {
+			return nil, fmt.Errorf("BYDAY with a number is only supported with FREQ=MONTHLY or FREQ=YEARLY")
+		}
+	}
+
+	return r, nil
+}
+
+// parseRecurrenceTime parses an iCalendar DATE or DATE-TIME value with the
+// given property parameters. Floating times are in loc.
+func parseRecurrenceTime(value, params string, loc *_time.Location) (_time.Time, error) {
+	for _, param := range strings.Split(params, ";") {
+		kv := strings.SplitN(param, "=", 2)
+		if len(kv) == 2 && strings.EqualFold(kv[0], "TZID") {
+			var err error
+			loc, err = _time.LoadLocation(kv[1])
+			if err != nil {
+				return _time.Time{}, err
+			}
+		}
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		return _time.Parse("20060102T150405Z", value)
+	}
+
+	layout := "20060102T150405"
+	if len(value) == len("20060102") {
+		layout = "20060102"
+	}
+
+	return _time.ParseInLocation(layout, value, loc)
+}
+
+func parseRecurrenceInts(s string, min, max int) ([]int, error) {
+	var ints []int
+	for _, v := range strings.Split(s, ",") {
+		i, err := strconv.Atoi(strings.TrimPrefix(v, "+"))
+		if err != nil || i == 0 || i < min || i > max {
+			return nil, fmt.Errorf("invalid value %q", v)
+		}
+		ints = append(ints, i)
+	}
+	return ints, nil
+}
+
+// between returns the occurrences in [from, to).
+func (r *recurrence) between(from, to _time.Time) ([]_time.Time, error) {
+	var (
+		occurrences []_time.Time
+		n           int
+	)
+
+	// The start of the first period.
+	year, month, day := r.start.Date()
+	switch r.freq {
+	case "WEEKLY":
+		day -= (int(r.start.Weekday()) - int(r.wkst) + 7) % 7
+	case "MONTHLY":
+		day = 1
+	case "YEARLY":
+		month, day = 1, 1
+	}
+
+	for i := 0; ; i += r.interval {
+		var (
+			periodStart _time.Time
+			candidates  []_time.Time
+		)
+
+		switch r.freq {
+		case "DAILY":
+			periodStart = r.date(year, month, day+i)
+			candidates = r.filter(periodStart)
+		case "WEEKLY":
+			periodStart = r.date(year, month, day+7*i)
+			for d := 0; d < 7; d++ {
+				candidates = append(candidates, r.filter(periodStart.AddDate(0, 0, d))...)
+			}
+		case "MONTHLY":
+			periodStart = r.date(year, month+_time.Month(i), 1)
+			candidates = r.monthCandidates(periodStart.Year(), periodStart.Month())
+		case "YEARLY":
+			periodStart = r.date(year+i, 1, 1)
+			candidates = r.yearCandidates(year + i)
+		}
+
+		// All occurrences in a period are at or after its start.
+		if !periodStart.Before(to) || (!r.until.IsZero() && periodStart.After(r.until)) {
+			return occurrences, nil
+		}
+
+		for _, t := range candidates {
+			if t.Before(r.start) {
+				continue
+			}
+			if !r.until.IsZero() && t.After(r.until) {
+				return occurrences, nil
+			}
+			if r.exdates[t.Unix()] {
+				continue
+			}
+			n++
+			if r.count > 0 && n > r.count {
+				return occurrences, nil
+			}
+			if !t.Before(to) {
+				return occurrences, nil
+			}
+			if t.Before(from) {
+				continue
+			}
+			if len(occurrences) == maxOccurrences {
+				return nil, fmt.Errorf("more than %d occurrences", maxOccurrences)
+			}
+			occurrences = append(occurrences, t)
+		}
+	}
+}
+
+// date returns the given date, normalized, at the time of day of start.
+func (r *recurrence) date(year int, month _time.Month, day int) _time.Time {
+	h, m, s := r.start.Clock()
+	return _time.Date(year, month, day, h, m, s, 0, r.start.Location())
+}
+
+// filter returns t in a slice if it matches BYMONTH, BYMONTHDAY and the
+// weekdays in BYDAY, or if the rule has none of them, the weekday of start
+// for WEEKLY rules.
+func (r *recurrence) filter(t _time.Time) []_time.Time {
+	if len(r.byMonth) > 0 && !containsInt(r.byMonth, int(t.Month())) {
+		return nil
+	}
+	if len(r.byMonthDay) > 0 && !matchesMonthDay(r.byMonthDay, t) {
+		return nil
+	}
+	if len(r.byDay) > 0 {
+		var found bool
+		for _, d := range r.byDay {
+			if d.weekday == t.Weekday() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil
+		}
+	} else if r.freq == "WEEKLY" && t.Weekday() != r.start.Weekday() {
+		return nil
+	}
+	return []_time.Time{t}
+}
+
+func (r *recurrence) monthCandidates(year int, month _time.Month) []_time.Time {
+	first := r.date(year, month, 1)
+	if len(r.byMonth) > 0 && !containsInt(r.byMonth, int(first.Month())) {
+		return nil
+	}
+
+	var candidates []_time.Time
+	daysInMonth := first.AddDate(0, 1, -1).Day()
+	for d := 1; d <= daysInMonth; d++ {
+		t := r.date(first.Year(), first.Month(), d)
+		switch {
+		case len(r.byMonthDay) > 0:
+			if !matchesMonthDay(r.byMonthDay, t) {
+				continue
+			}
+			if len(r.byDay) > 0 && !matchesWeekdayNum(r.byDay, t, d, daysInMonth) {
+				continue
+			}
+		case len(r.byDay) > 0:
+			if !matchesWeekdayNum(r.byDay, t, d, daysInMonth) {
+				continue
+			}
+		default:
+			if d != r.start.Day() {
+				continue
+			}
+		}
+		candidates = append(candidates, t)
+	}
+	return candidates
+}
+
+func (r *recurrence) yearCandidates(year int) []_time.Time {
+	if len(r.byDay) > 0 && len(r.byMonth) == 0 && len(r.byMonthDay) == 0 {
+		// The nth weekdays of the year.
+		var candidates []_time.Time
+		daysInYear := r.date(year, 12, 31).YearDay()
+		for d := 1; d <= daysInYear; d++ {
+			t := r.date(year, 1, d)
+			if matchesWeekdayNum(r.byDay, t, d, daysInYear) {
+				candidates = append(candidates, t)
+			}
+		}
+		return candidates
+	}
+
+	months := r.byMonth
+	if len(months) == 0 {
+		if len(r.byMonthDay) > 0 {
+			months = []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
+		} else {
+			months = []int{int(r.start.Month())}
+		}
+	}
+	months = append([]int(nil), months...)
+	sort.Ints(months)
+
+	var candidates []_time.Time
+	for _, m := range months {
+		candidates = append(candidates, r.monthCandidates(year, _time.Month(m))...)
+	}
+	return candidates
+}
+
+func matchesMonthDay(days []int, t _time.Time) bool {
+	daysInMonth := t.AddDate(0, 0, -t.Day()+1).AddDate(0, 1, -1).Day()
+	for _, d := range days {
+		if d == t.Day() || daysInMonth+d+1 == t.Day() {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesWeekdayNum reports whether t, day number d of n in its month or
+// year, matches any of the weekdays.
+func matchesWeekdayNum(weekdays []weekdayNum, t _time.Time, d, n int) bool {
+	for _, wd := range weekdays {
+		if wd.weekday != t.Weekday() {
+			continue
+		}
+		if wd.n == 0 || wd.n == (d-1)/7+1 || wd.n == -((n-d)/7+1) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsInt(ints []int, i int) bool {
+	for _, v := range ints {
+		if v == i {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package time
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExpand(t *testing.T) {
+	t.Parallel()
+
+	ns := New()
+
+	for i, test := range []struct {
+		rule   string
+		from   string
+		to     string
+		expect interface{}
+	}{
+		{"DTSTART:20210105T180000Z\nRRULE:FREQ=WEEKLY;BYDAY=TU,TH;COUNT=3", "2021-01-01", "2022-01-01", "2021-01-05T18:00 2021-01-07T18:00 2021-01-12T18:00"},
+		// The window doesn't change what COUNT counts.
+		{"DTSTART:20210105T180000Z\nRRULE:FREQ=WEEKLY;BYDAY=TU,TH;COUNT=3", "2021-01-06", "2022-01-01", "2021-01-07T18:00 2021-01-12T18:00"},
+		{"DTSTART:20210101T090000Z RRULE:FREQ=DAILY;INTERVAL=10", "2021-01-15", "2021-02-15", "2021-01-21T09:00 2021-01-31T09:00 2021-02-10T09:00"},
+		{"DTSTART:20210101T090000Z RRULE:FREQ=DAILY;UNTIL=20210103T090000Z", "2021-01-01", "2022-01-01", "2021-01-01T09:00 2021-01-02T09:00 2021-01-03T09:00"},
+		// Weekly defaults to the weekday of DTSTART (a Wednesday).
+		{"DTSTART:20210106T120000Z RRULE:FREQ=WEEKLY;INTERVAL=2", "2021-01-01", "2021-02-01", "2021-01-06T12:00 2021-01-20T12:00"},
+		// The second Tuesday and the last Friday of every month.
+		{"DTSTART:20210101T190000Z RRULE:FREQ=MONTHLY;BYDAY=2TU,-1FR", "2021-01-01", "2021-03-01", "2021-01-12T19:00 2021-01-29T19:00 2021-02-09T19:00 2021-02-26T19:00"},
+		{"DTSTART:20210131T100000Z RRULE:FREQ=MONTHLY", "2021-01-01", "2021-05-01", "2021-01-31T10:00 2021-03-31T10:00"},
+		{"DTSTART:20210101T100000Z RRULE:FREQ=MONTHLY;BYMONTHDAY=-1", "2021-01-01", "2021-04-01", "2021-01-31T10:00 2021-02-28T10:00 2021-03-31T10:00"},
+		// Friday the 13th.
+		{"DTSTART:20210101T000000Z RRULE:FREQ=MONTHLY;BYDAY=FR;BYMONTHDAY=13", "2021-01-01", "2022-01-01", "2021-08-13T00:00"},
+		// US Thanksgiving.
+		{"DTSTART:20201126T120000Z RRULE:FREQ=YEARLY;BYMONTH=11;BYDAY=4TH", "2021-01-01", "2023-01-01", "2021-11-25T12:00 2022-11-24T12:00"},
+		{"DTSTART:20200229T120000Z RRULE:FREQ=YEARLY", "2020-01-01", "2025-01-01", "2020-02-29T12:00 2024-02-29T12:00"},
+		{"DTSTART:20210101T120000Z RRULE:FREQ=YEARLY;BYDAY=-1MO", "2021-01-01", "2023-01-01", "2021-12-27T12:00 2022-12-26T12:00"},
+		{"DTSTART:20210105T180000Z\nRRULE:FREQ=WEEKLY\nEXDATE:20210112T180000Z,20210119T180000Z", "2021-01-01", "2021-02-01", "2021-01-05T18:00 2021-01-26T18:00"},
+		// No DTSTART, start at from.
+		{"FREQ=DAILY;COUNT=2", "2021-03-01T08:30:00Z", "2022-01-01", "2021-03-01T08:30 2021-03-02T08:30"},
+		{"RRULE:FREQ=HOURLY", "2021-01-01", "2022-01-01", false},
+		{"RRULE:FREQ=WEEKLY;BYDAY=1MO", "2021-01-01", "2022-01-01", false},
+		{"RRULE:FREQ=DAILY;BYSETPOS=1", "2021-01-01", "2022-01-01", false},
+		{"DTSTART:20210101", "2021-01-01", "2022-01-01", false},
+		{"RRULE:FREQ=DAILY", "2021-01-01", "3021-01-01", false},
+	} {
+		result, err := ns.Expand(test.rule, test.from, test.to)
+		if b, ok := test.expect.(bool); ok && !b {
+			if err == nil {
+				t.Errorf("[%d] Expand didn't return an expected error, got %v", i, result)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("[%d] Expand failed: %s", i, err)
+			continue
+		}
+		var got []string
+		for _, tt := range result {
+			got = append(got, tt.Format("2006-01-02T15:04"))
+		}
+		if strings.Join(got, " ") != test.expect {
+			t.Errorf("[%d] Expand got %v but expected %v", i, got, test.expect)
+		}
+	}
+}
+
+func TestExpandTimeZone(t *testing.T) {
+	t.Parallel()
+
+	ns := New()
+
+	// The occurrences keep the local time of day across the DST change.
+	result, err := ns.Expand("DTSTART;TZID=Europe/Oslo:20210321T180000 RRULE:FREQ=WEEKLY;COUNT=2", "2021-01-01", "2022-01-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 2 {
+		t.Fatalf("got %d occurrences", len(result))
+	}
+	for _, tt := range result {
+		if tt.Hour() != 18 || tt.Location().String() != "Europe/Oslo" {
+			t.Errorf("got %v", tt)
+		}
+	}
+	if d := result[1].Sub(result[0]); d != 7*24*time.Hour-time.Hour {
+		t.Errorf("got %v between occurrences", d)
+	}
+}
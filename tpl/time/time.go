@@ -18,6 +18,7 @@ import (
 	"fmt"
 	_time "time"
 
+	"github.com/gohugoio/hugo/common/types"
 	"github.com/spf13/cast"
 )
 
@@ -116,8 +117,9 @@ func (ns *Namespace) Now() _time.Time {
 // ParseDuration parses a duration string.
 // A duration string is a possibly signed sequence of
 // decimal numbers, each with optional fraction and a unit suffix,
-// such as "300ms", "-1.5h" or "2h45m".
-// Valid time units are "ns", "us" (or "µs"), "ms", "s", "m", "h".
+// such as "300ms", "-1.5h", "2h45m" or "2w3d".
+// Valid time units are "ns", "us" (or "µs"), "ms", "s", "m", "h", "d" (24h)
+// and "w" (7d).
 // See https://golang.org/pkg/time/#ParseDuration
 func (ns *Namespace) ParseDuration(in interface{}) (_time.Duration, error) {
 	s, err := cast.ToStringE(in)
@@ -125,7 +127,28 @@ func (ns *Namespace) ParseDuration(in interface{}) (_time.Duration, error) {
 		return 0, err
 	}
 
-	return _time.ParseDuration(s)
+	return types.ParseDuration(s)
+}
+
+// Since returns the time elapsed since t, e.g. the age of a page with
+// {{ time.Since .Date }}.
+func (ns *Namespace) Since(t interface{}) (_time.Duration, error) {
+	tt, err := cast.ToTimeE(t)
+	if err != nil {
+		return 0, err
+	}
+
+	return _time.Since(tt), nil
+}
+
+// Until returns the duration until t.
+func (ns *Namespace) Until(t interface{}) (_time.Duration, error) {
+	tt, err := cast.ToTimeE(t)
+	if err != nil {
+		return 0, err
+	}
+
+	return _time.Until(tt), nil
 }
 
 var durationUnits = map[string]_time.Duration{
@@ -142,10 +165,15 @@ var durationUnits = map[string]_time.Duration{
 	"m":           _time.Minute,
 	"hour":        _time.Hour,
 	"h":           _time.Hour,
+	"day":         24 * _time.Hour,
+	"d":           24 * _time.Hour,
+	"week":        7 * 24 * _time.Hour,
+	"w":           7 * 24 * _time.Hour,
 }
 
 // Duration converts the given number to a time.Duration.
-// Unit is one of nanosecond/ns, microsecond/us/µs, millisecond/ms, second/s, minute/m or hour/h.
+// Unit is one of nanosecond/ns, microsecond/us/µs, millisecond/ms, second/s, minute/m, hour/h,
+// day/d or week/w.
 func (ns *Namespace) Duration(unit interface{}, number interface{}) (_time.Duration, error) {
 	unitStr, err := cast.ToStringE(unit)
 	if err != nil {
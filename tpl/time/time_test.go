@@ -117,6 +117,10 @@ func TestDuration(t *testing.T) {
 		{"m", 20, 20 * time.Minute},
 		{"hour", 20, 20 * time.Hour},
 		{"h", 20, 20 * time.Hour},
+		{"day", 2, 48 * time.Hour},
+		{"d", 2, 48 * time.Hour},
+		{"week", 1, 7 * 24 * time.Hour},
+		{"w", 1, 7 * 24 * time.Hour},
 		{"hours", 20, false},
 		{"hour", "30", 30 * time.Hour},
 	} {
@@ -136,3 +140,63 @@ func TestDuration(t *testing.T) {
 		}
 	}
 }
+
+func TestParseDuration(t *testing.T) {
+	t.Parallel()
+
+	ns := New()
+
+	for i, test := range []struct {
+		in     interface{}
+		expect interface{}
+	}{
+		{"1h12m10s", time.Hour + 12*time.Minute + 10*time.Second},
+		{"3d12h", 84 * time.Hour},
+		{"2w", 14 * 24 * time.Hour},
+		{"2y", false},
+		{t, false},
+	} {
+		result, err := ns.ParseDuration(test.in)
+		if b, ok := test.expect.(bool); ok && !b {
+			if err == nil {
+				t.Errorf("[%d] ParseDuration didn't return an expected error, got %v", i, result)
+			}
+		} else {
+			if err != nil {
+				t.Errorf("[%d] ParseDuration failed: %s", i, err)
+				continue
+			}
+			if result != test.expect {
+				t.Errorf("[%d] ParseDuration got %v but expected %v", i, result, test.expect)
+			}
+		}
+	}
+}
+
+func TestSinceUntil(t *testing.T) {
+	t.Parallel()
+
+	ns := New()
+
+	past := time.Now().Add(-2 * time.Hour)
+
+	since, err := ns.Since(past)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if since < 2*time.Hour || since > 3*time.Hour {
+		t.Errorf("Since got %v", since)
+	}
+
+	until, err := ns.Until(past.Format(time.RFC3339))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if until > -time.Hour {
+		t.Errorf("Until got %v", until)
+	}
+
+	if _, err := ns.Since("foo"); err == nil {
+		t.Error("Since didn't return an expected error")
+	}
+}
@@ -18,7 +18,124 @@ package embedded
 
 // EmbeddedTemplates represents all embedded templates.
 var EmbeddedTemplates = [][2]string{
-	{`_default/robots.txt`, `User-agent: *`},
+	{`__h_consent.html`, `{{- define "__h_consent_start" }}{{/* These template definitions are global. */}}
+{{- if and .service.RequireConsent (not .service.Disable) }}
+<div data-hugo-consent="{{ .name }}" data-hugo-consent-category="{{ .service.Category }}">
+<template>
+{{- end -}}
+{{- end -}}
+
+{{- define "__h_consent_end" -}}
+{{- if and .service.RequireConsent (not .service.Disable) -}}
+</template>
+<p class="hugo-consent-message">This content is loaded from {{ .title }} after you have given your consent.</p>
+</div>
+{{ end -}}
+{{- end -}}
+`},
+	{`_default/list.contentapi.json`, `{{- $api := site.ContentAPI -}}
+{{- $pages := .RegularPages -}}
+{{- if .IsHome }}{{ $pages = site.RegularPages }}{{ end -}}
+{{- $api.Collection . (.Paginate $pages $api.PageSize) | jsonify -}}
+`},
+	{`_default/list.email.html`, `{{- $pages := .RegularPages -}}
+{{- if .IsHome }}{{ $pages = site.RegularPages }}{{ end -}}
+<!DOCTYPE html>
+<html lang="{{ site.Language.Lang }}">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{ .Title }}</title>
+<style>
+body { margin: 0; padding: 0; background-color: #f4f4f4; color: #333333; font-family: Arial, Helvetica, sans-serif; font-size: 16px; line-height: 1.5; }
+h1 { margin: 0 0 16px; font-size: 24px; line-height: 1.25; }
+h2 { margin: 24px 0 8px; font-size: 20px; line-height: 1.25; }
+img { max-width: 100%; height: auto; border: 0; }
+a { color: #1a73e8; }
+</style>
+</head>
+<body>
+{{ template "_internal/email/container_start.html" dict }}
+<h1>{{ .Title }}</h1>
+{{ .Content }}
+{{- range first 10 $pages }}
+<h2><a href="{{ .Permalink }}">{{ .Title }}</a></h2>
+{{ .Summary }}
+{{- end }}
+{{ template "_internal/email/button.html" (dict "url" .Permalink "text" "Read on the web") }}
+{{ template "_internal/email/container_end.html" }}
+</body>
+</html>
+`},
+	{`_default/list.emailtext.txt`, `{{- $pages := .RegularPages -}}
+{{- if .IsHome }}{{ $pages = site.RegularPages }}{{ end -}}
+{{- .Title }}
+{{ with .Content }}
+{{ . | transform.HTMLToText }}
+{{ end }}
+{{- range first 10 $pages }}
+{{ .Title }}
+{{ .Summary | transform.HTMLToText }}
+{{ .Permalink }}
+{{ end }}
+Read on the web: {{ .Permalink }}
+`},
+	{`_default/list.print.html`, `{{- $conf := .Params.print | default dict -}}
+{{- $toc := true }}{{ if isset $conf "toc" }}{{ $toc = index $conf "toc" }}{{ end -}}
+{{- $tocDepth := 3 }}{{ if isset $conf "tocdepth" }}{{ $tocDepth = index $conf "tocdepth" }}{{ end -}}
+{{- $numberHeadings := true }}{{ if isset $conf "numberheadings" }}{{ $numberHeadings = index $conf "numberheadings" }}{{ end -}}
+{{- $recursive := true }}{{ if isset $conf "recursive" }}{{ $recursive = index $conf "recursive" }}{{ end -}}
+{{- $pages := .RegularPages -}}
+{{- if $recursive }}{{ $pages = .RegularPagesRecursive }}{{ if .IsHome }}{{ $pages = site.RegularPages }}{{ end }}{{ end -}}
+<!DOCTYPE html>
+<html lang="{{ site.Language.Lang }}">
+<head>
+{{ template "_internal/print/head.html" . }}
+</head>
+<body{{ if $numberHeadings }} data-print-number-headings{{ end }}>
+<header>
+<h1 class="print-title">{{ .Title }}</h1>
+{{ .Content }}
+</header>
+{{- if $toc }}
+<nav class="print-toc" data-print-toc="{{ $tocDepth }}"></nav>
+{{- end }}
+{{- range $i, $p := $pages }}
+<article class="print-chapter" data-print-chapter="chapter-{{ add $i 1 }}" data-print-url="{{ .RelPermalink }}">
+<h1>{{ .Title }}</h1>
+{{ .Content }}
+</article>
+{{- end }}
+</body>
+</html>
+`},
+	{`_default/list.searchindex.json`, `{{- $pages := .RegularPagesRecursive -}}
+{{- if .IsHome }}{{ $pages = .Site.RegularPages }}{{ end -}}
+{{- .Site.SearchIndex $pages | jsonify -}}
+`},
+	{`_default/llms-full.txt`, `# {{ .Site.Title }}
+{{ range .Pages }}
+## {{ .Title }}
+
+Source: {{ .Permalink }}
+
+{{ .RawContent }}
+{{ end }}
+`},
+	{`_default/llms.txt`, `# {{ .Site.Title }}
+{{ with .Site.Params.description }}
+> {{ . }}
+{{ end }}
+## Pages
+{{ range .Pages }}
+- [{{ .LinkTitle }}]({{ .Permalink }}){{ with .Description }}: {{ . }}{{ end }}
+{{- end }}
+`},
+	{`_default/projection.json`, `{{- with site.Project . }}{{ . | jsonify }}{{ end -}}
+`},
+	{`_default/robots.txt`, `User-agent: *
+{{- range .Data.Pages }}{{ range .OutputFormats }}{{ if .Format.NoIndex }}
+Disallow: {{ .RelPermalink }}{{ end }}{{ end }}{{ end }}`},
 	{`_default/rss.xml`, `{{- $pctx := . -}}
 {{- if .IsHome -}}{{ $pctx = .Site }}{{- end -}}
 {{- $pages := slice -}}
@@ -58,29 +175,81 @@ var EmbeddedTemplates = [][2]string{
     {{ end }}
   </channel>
 </rss>
+`},
+	{`_default/single.contentapi.json`, `{{- site.ContentAPI.Document . | jsonify -}}
+`},
+	{`_default/single.email.html`, `<!DOCTYPE html>
+<html lang="{{ site.Language.Lang }}">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{ .Title }}</title>
+<style>
+body { margin: 0; padding: 0; background-color: #f4f4f4; color: #333333; font-family: Arial, Helvetica, sans-serif; font-size: 16px; line-height: 1.5; }
+h1 { margin: 0 0 16px; font-size: 24px; line-height: 1.25; }
+img { max-width: 100%; height: auto; border: 0; }
+a { color: #1a73e8; }
+</style>
+</head>
+<body>
+{{ template "_internal/email/container_start.html" dict }}
+<h1>{{ .Title }}</h1>
+{{ .Content }}
+{{ template "_internal/email/button.html" (dict "url" .Permalink "text" "Read on the web") }}
+{{ template "_internal/email/container_end.html" }}
+</body>
+</html>
+`},
+	{`_default/single.emailtext.txt`, `{{- .Title }}
+
+{{ .Content | transform.HTMLToText }}
+
+Read on the web: {{ .Permalink }}
+`},
+	{`_default/single.print.html`, `{{- $conf := .Params.print | default dict -}}
+{{- $toc := false }}{{ if isset $conf "toc" }}{{ $toc = index $conf "toc" }}{{ end -}}
+{{- $tocDepth := 3 }}{{ if isset $conf "tocdepth" }}{{ $tocDepth = index $conf "tocdepth" }}{{ end -}}
+{{- $numberHeadings := false }}{{ if isset $conf "numberheadings" }}{{ $numberHeadings = index $conf "numberheadings" }}{{ end -}}
+<!DOCTYPE html>
+<html lang="{{ site.Language.Lang }}">
+<head>
+{{ template "_internal/print/head.html" . }}
+</head>
+<body{{ if $numberHeadings }} data-print-number-headings{{ end }}>
+{{- if $toc }}
+<nav class="print-toc" data-print-toc="{{ $tocDepth }}"></nav>
+{{- end }}
+<article data-print-chapter="chapter-1" data-print-url="{{ .RelPermalink }}">
+<h1>{{ .Title }}</h1>
+{{ .Content }}
+</article>
+</body>
+</html>
 `},
 	{`_default/sitemap.xml`, `{{ printf "<?xml version=\"1.0\" encoding=\"utf-8\" standalone=\"yes\"?>" | safeHTML }}
 <urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9"
   xmlns:xhtml="http://www.w3.org/1999/xhtml">
-  {{ range .Data.Pages }}
+  {{ range $page := .Data.Pages }}
     {{- if .Permalink -}}
+    {{- range .OutputFormats.Sitemap .Sitemap }}
   <url>
-    <loc>{{ .Permalink }}</loc>{{ if not .Lastmod.IsZero }}
-    <lastmod>{{ safeHTML ( .Lastmod.Format "2006-01-02T15:04:05-07:00" ) }}</lastmod>{{ end }}{{ with .Sitemap.ChangeFreq }}
-    <changefreq>{{ . }}</changefreq>{{ end }}{{ if ge .Sitemap.Priority 0.0 }}
-    <priority>{{ .Sitemap.Priority }}</priority>{{ end }}{{ if .IsTranslated }}{{ range .Translations }}
+    <loc>{{ .Permalink }}</loc>{{ if not $page.Lastmod.IsZero }}
+    <lastmod>{{ safeHTML ( $page.Lastmod.Format "2006-01-02T15:04:05-07:00" ) }}</lastmod>{{ end }}{{ with $page.Sitemap.ChangeFreq }}
+    <changefreq>{{ . }}</changefreq>{{ end }}{{ if ge $page.Sitemap.Priority 0.0 }}
+    <priority>{{ $page.Sitemap.Priority }}</priority>{{ end }}{{ if $page.IsTranslated }}{{ $name := .Name }}{{ range $page.Translations }}{{ $lang := .Language.Lang }}{{ with .OutputFormats.Get $name }}
     <xhtml:link
                 rel="alternate"
-                hreflang="{{ .Language.Lang }}"
+                hreflang="{{ $lang }}"
                 href="{{ .Permalink }}"
-                />{{ end }}
+                />{{ end }}{{ end }}
     <xhtml:link
                 rel="alternate"
-                hreflang="{{ .Language.Lang }}"
+                hreflang="{{ $page.Language.Lang }}"
                 href="{{ .Permalink }}"
                 />{{ end }}
   </url>
     {{- end -}}
+    {{- end -}}
   {{ end }}
 </urlset>
 `},
@@ -97,9 +266,29 @@ var EmbeddedTemplates = [][2]string{
 </sitemapindex>
 `},
 	{`alias.html`, `<!DOCTYPE html><html><head><title>{{ .Permalink }}</title><link rel="canonical" href="{{ .Permalink }}"/><meta name="robots" content="noindex"><meta charset="utf-8" /><meta http-equiv="refresh" content="0; url={{ .Permalink }}" /></head></html>`},
+	{`analytics.html`, `{{- /* Renders the analytics snippets for all the providers configured in services. */ -}}
+{{- template "_internal/google_analytics.html" . -}}
+{{- template "_internal/matomo.html" . -}}
+{{- template "_internal/plausible.html" . -}}
+
+{{- define "__analytics_js_consent" -}}{{/* Used by the analytics templates with requireConsent set. */ -}}
+window.hugoAnalytics = window.hugoAnalytics || {
+	consented: false,
+	callbacks: [],
+	whenConsented: function(fn) { this.consented ? fn() : this.callbacks.push(fn); },
+	grantConsent: function() {
+		if (this.consented) { return; }
+		this.consented = true;
+		this.callbacks.forEach(function(fn) { fn(); });
+	}
+};
+{{- end -}}
+`},
 	{`disqus.html`, `{{- $pc := .Site.Config.Privacy.Disqus -}}
 {{- if not $pc.Disable -}}
-{{ if .Site.DisqusShortname }}<div id="disqus_thread"></div>
+{{ if .Site.DisqusShortname }}
+{{- template "__h_consent_start" (dict "name" "disqus" "service" $pc) }}
+<div id="disqus_thread"></div>
 <script type="application/javascript">
     var disqus_config = function () {
     {{with .Params.disqus_identifier }}this.page.identifier = '{{ . }}';{{end}}
@@ -118,8 +307,36 @@ var EmbeddedTemplates = [][2]string{
     })();
 </script>
 <noscript>Please enable JavaScript to view the <a href="https://disqus.com/?ref_noscript">comments powered by Disqus.</a></noscript>
-<a href="https://disqus.com" class="dsq-brlink">comments powered by <span class="logo-disqus">Disqus</span></a>{{end}}
+<a href="https://disqus.com" class="dsq-brlink">comments powered by <span class="logo-disqus">Disqus</span></a>
+{{- template "__h_consent_end" (dict "name" "disqus" "title" "Disqus" "service" $pc) }}
+{{- end }}
 {{- end -}}`},
+	{`email/button.html`, `{{- $color := .color | default "#1a73e8" -}}
+{{- $textColor := .textColor | default "#ffffff" -}}
+<table role="presentation" cellpadding="0" cellspacing="0" border="0" style="margin:24px 0">
+<tr>
+<td align="center" bgcolor="{{ $color }}" style="border-radius:4px">
+<a href="{{ .url }}" target="_blank" style="display:inline-block;padding:12px 24px;color:{{ $textColor }};font-weight:bold;text-decoration:none">{{ .text }}</a>
+</td>
+</tr>
+</table>
+`},
+	{`email/container_end.html`, `</td>
+</tr>
+</table>
+</td>
+</tr>
+</table>
+`},
+	{`email/container_start.html`, `{{- $width := .width | default 600 -}}
+{{- $background := .background | default "#ffffff" -}}
+<table role="presentation" width="100%" cellpadding="0" cellspacing="0" border="0">
+<tr>
+<td align="center">
+<table role="presentation" width="{{ $width }}" cellpadding="0" cellspacing="0" border="0" style="width:100%;max-width:{{ $width }}px;background-color:{{ $background }}">
+<tr>
+<td style="padding:{{ .padding | default 24 }}px">
+`},
 	{`google_analytics.html`, `{{- $pc := .Site.Config.Privacy.GoogleAnalytics -}}
 {{- if not $pc.Disable }}{{ with .Site.GoogleAnalytics -}}
 {{ if hasPrefix . "G-"}}
@@ -129,6 +346,11 @@ var EmbeddedTemplates = [][2]string{
 if (!doNotTrack) {
 	window.dataLayer = window.dataLayer || [];
 	function gtag(){dataLayer.push(arguments);}
+	{{- if $pc.RequireConsent }}
+	gtag('consent', 'default', { 'analytics_storage': 'denied' });
+	{{ template "__analytics_js_consent" }}
+	hugoAnalytics.whenConsented(function() { gtag('consent', 'update', { 'analytics_storage': 'granted' }); });
+	{{- end }}
 	gtag('js', new Date());
 	gtag('config', '{{ . }}', { 'anonymize_ip': {{- $pc.AnonymizeIP -}} });
 }
@@ -203,6 +425,34 @@ if (!doNotTrack) {
 	{`google_news.html`, `{{ if .IsPage }}{{ with .Params.news_keywords }}
   <meta name="news_keywords" content="{{ range $i, $kw := first 10 . }}{{ if $i }},{{ end }}{{ $kw }}{{ end }}" />
 {{ end }}{{ end }}`},
+	{`matomo.html`, `{{- $pc := .Site.Config.Privacy.Matomo -}}
+{{- $sc := .Site.Config.Services.Matomo -}}
+{{- if and (not $pc.Disable) $sc.URL $sc.SiteID }}
+<script>
+var _paq = window._paq = window._paq || [];
+{{- if $pc.RequireConsent }}
+_paq.push(['requireConsent']);
+{{ template "__analytics_js_consent" }}
+hugoAnalytics.whenConsented(function() { _paq.push(['setConsentGiven']); });
+{{- end }}
+{{- if $pc.DisableCookies }}
+_paq.push(['disableCookies']);
+{{- end }}
+{{- if $pc.RespectDoNotTrack }}
+_paq.push(['setDoNotTrack', true]);
+{{- end }}
+_paq.push(['trackPageView']);
+_paq.push(['enableLinkTracking']);
+(function() {
+	var u = {{ printf "%s/" (strings.TrimSuffix "/" $sc.URL) }};
+	_paq.push(['setTrackerUrl', u + 'matomo.php']);
+	_paq.push(['setSiteId', {{ $sc.SiteID }}]);
+	var d = document, g = d.createElement('script'), s = d.getElementsByTagName('script')[0];
+	g.async = true; g.src = u + 'matomo.js'; s.parentNode.insertBefore(g, s);
+})();
+</script>
+{{- end -}}
+`},
 	{`opengraph.html`, `<meta property="og:title" content="{{ .Title }}" />
 <meta property="og:description" content="{{ with .Description }}{{ . }}{{ else }}{{if .IsPage}}{{ .Summary }}{{ else }}{{ with .Site.Params.description }}{{ . }}{{ end }}{{ end }}{{ end }}" />
 <meta property="og:type" content="{{ if .IsPage }}article{{ else }}website{{ end }}" />
@@ -247,6 +497,35 @@ if (!doNotTrack) {
 
 {{- /* Facebook Page Admin ID for Domain Insights */}}
 {{- with .Site.Social.facebook_admin }}<meta property="fb:admins" content="{{ . }}" />{{ end }}
+`},
+	{`page_assets.html`, `{{- /*
+Builds and includes the styles and scripts in the page bundle, if any:
+index.scss, index.sass or index.css, and index.js, index.jsx, index.ts or index.tsx.
+Code shared by these pages goes in page-assets/shared.* in /assets. It is built
+separately and included before the page's own, so it is cached across pages.
+*/ -}}
+{{- $min := hugo.IsProduction -}}
+{{- with .Resources.GetMatch "index.{scss,sass,css}" }}
+{{- $styles := slice -}}
+{{- with resources.GetMatch "page-assets/shared.{scss,sass,css}" }}{{ $styles = $styles | append . }}{{ end -}}
+{{- $styles = $styles | append . -}}
+{{- range $styles }}
+{{- $r := . -}}
+{{- if ne $r.MediaType.SubType "css" }}{{ $r = $r | toCSS }}{{ end -}}
+{{- if $min }}{{ $r = $r | minify }}{{ end -}}
+{{- $r = $r | fingerprint }}
+<link rel="stylesheet" href="{{ $r.RelPermalink }}" integrity="{{ $r.Data.Integrity }}" crossorigin="anonymous">
+{{- end }}
+{{- end }}
+{{- with .Resources.GetMatch "index.{js,jsx,ts,tsx}" }}
+{{- $scripts := slice -}}
+{{- with resources.GetMatch "page-assets/shared.{js,jsx,ts,tsx}" }}{{ $scripts = $scripts | append . }}{{ end -}}
+{{- $scripts = $scripts | append . -}}
+{{- range $scripts }}
+{{- $r := . | js.Build (dict "minify" $min) | fingerprint }}
+<script src="{{ $r.RelPermalink }}" integrity="{{ $r.Data.Integrity }}" crossorigin="anonymous" defer></script>
+{{- end }}
+{{- end -}}
 `},
 	{`pagination.html`, `{{- $validFormats := slice "default" "terse" }}
 
@@ -402,6 +681,54 @@ if (!doNotTrack) {
     {{- end }}
   {{- end }}
 {{- end -}}
+`},
+	{`plausible.html`, `{{- $pc := .Site.Config.Privacy.Plausible -}}
+{{- $sc := .Site.Config.Services.Plausible -}}
+{{- if and (not $pc.Disable) $sc.Domain }}
+{{- if or $pc.RequireConsent $pc.RespectDoNotTrack }}
+<script>
+(function() {
+	{{- if $pc.RespectDoNotTrack }}
+	var dnt = (navigator.doNotTrack || window.doNotTrack || navigator.msDoNotTrack);
+	if (dnt == "1" || dnt == "yes") { return; }
+	{{- end }}
+	function load() {
+		var d = document, g = d.createElement('script'), s = d.getElementsByTagName('script')[0];
+		g.defer = true; g.setAttribute('data-domain', {{ $sc.Domain }}); g.src = {{ $sc.Script }};
+		s.parentNode.insertBefore(g, s);
+	}
+	{{- if $pc.RequireConsent }}
+	{{ template "__analytics_js_consent" }}
+	hugoAnalytics.whenConsented(load);
+	{{- else }}
+	load();
+	{{- end }}
+})();
+</script>
+{{- else }}
+<script defer data-domain="{{ $sc.Domain }}" src="{{ $sc.Script }}"></script>
+{{- end }}
+{{- end -}}
+`},
+	{`print/head.html`, `<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>{{ .Title }}</title>
+<style>
+@page { margin: 2cm; }
+body { margin: 0 auto; max-width: 48em; color: #000; font-family: Georgia, "Times New Roman", serif; font-size: 11pt; line-height: 1.5; }
+@media screen { body { padding: 2em; } }
+h1, h2, h3, h4, h5, h6 { break-after: avoid; line-height: 1.25; }
+pre, blockquote, table, figure, img { break-inside: avoid; }
+img { max-width: 100%; height: auto; }
+a { color: inherit; }
+.print-title { font-size: 2.5em; }
+.print-toc ol { list-style: none; padding-left: 1.5em; }
+.print-toc > ol { padding-left: 0; }
+.print-chapter { break-before: page; }
+.print-number { margin-right: 0.25em; }
+.footnotes { font-size: 0.9em; }
+</style>
 `},
 	{`schema.html`, `<meta itemprop="name" content="{{ .Title }}">
 <meta itemprop="description" content="{{ with .Description }}{{ . }}{{ else }}{{if .IsPage}}{{ .Summary }}{{ else }}{{ with .Site.Params.description }}{{ . }}{{ end }}{{ end }}{{ end }}">
@@ -509,7 +836,9 @@ if (!doNotTrack) {
       {{ $hideCaption := cond (eq (.Get 1) "hidecaption") "1" "0" }}
       {{ $headers := dict "Authorization" (printf "Bearer %s" $accessToken) }}
       {{ with getJSON "https://graph.facebook.com/v8.0/instagram_oembed/?url=https://instagram.com/p/" $id "/&hidecaption=" $hideCaption $headers }}
+        {{- template "__h_consent_start" (dict "name" "instagram" "service" $pc) }}
         {{ .html | safeHTML }}
+        {{- template "__h_consent_end" (dict "name" "instagram" "title" "Instagram" "service" $pc) }}
       {{ end }}
     {{- end -}}
   {{- end -}}
@@ -594,7 +923,9 @@ if (!doNotTrack) {
 {{- else -}}
 {{- $url := printf "https://api.twitter.com/1/statuses/oembed.json?id=%v&dnt=%t" (index .Params 0) $pc.EnableDNT -}}
 {{- $json := getJSON $url -}}
+{{- template "__h_consent_start" (dict "name" "twitter" "service" $pc) -}}
 {{ $json.html | safeHTML }}
+{{- template "__h_consent_end" (dict "name" "twitter" "title" "Twitter" "service" $pc) }}
 {{- end -}}
 {{- end -}}`},
 	{`shortcodes/twitter_simple.html`, `{{- $pc := .Page.Site.Config.Privacy.Twitter -}}
@@ -635,6 +966,7 @@ if (!doNotTrack) {
 {{- if $pc.Simple -}}
 {{ template "_internal/shortcodes/vimeo_simple.html" . }}
 {{- else -}}
+{{- template "__h_consent_start" (dict "name" "vimeo" "service" $pc) -}}
 {{ if .IsNamedParams }}<div {{ if .Get "class" }}class="{{ .Get "class" }}"{{ else }}style="position: relative; padding-bottom: 56.25%; height: 0; overflow: hidden;"{{ end }}>
   <iframe src="https://player.vimeo.com/video/{{ .Get "id" }}{{- if $pc.EnableDNT -}}?dnt=1{{- end -}}" {{ if not (.Get "class") }}style="position: absolute; top: 0; left: 0; width: 100%; height: 100%; border:0;" {{ end }}{{ if .Get "title"}}title="{{ .Get "title" }}"{{ else }}title="vimeo video"{{ end }} webkitallowfullscreen mozallowfullscreen allowfullscreen></iframe>
 </div>{{ else }}
@@ -642,6 +974,7 @@ if (!doNotTrack) {
   <iframe src="https://player.vimeo.com/video/{{ .Get 0 }}{{- if $pc.EnableDNT -}}?dnt=1{{- end -}}" {{ if len .Params | eq 1 }}style="position: absolute; top: 0; left: 0; width: 100%; height: 100%; border:0;" {{ end }}{{ if len .Params | eq 3 }}title="{{ .Get 2 }}"{{ else }}title="vimeo video"{{ end }} webkitallowfullscreen mozallowfullscreen allowfullscreen></iframe>
 </div>
 {{ end }}
+{{- template "__h_consent_end" (dict "name" "vimeo" "title" "Vimeo" "service" $pc) }}
 {{- end -}}
 {{- end -}}`},
 	{`shortcodes/vimeo_simple.html`, `{{- $pc := .Page.Site.Config.Privacy.Vimeo -}}
@@ -672,10 +1005,12 @@ if (!doNotTrack) {
 {{- $id := .Get "id" | default (.Get 0) -}}
 {{- $class := .Get "class" | default (.Get 1) -}}
 {{- $title := .Get "title" | default "YouTube Video" }}
+{{- template "__h_consent_start" (dict "name" "youtube" "service" $pc) }}
 <div {{ with $class }}class="{{ . }}"{{ else }}style="position: relative; padding-bottom: 56.25%; height: 0; overflow: hidden;"{{ end }}>
   <iframe src="https://{{ $ytHost }}/embed/{{ $id }}{{ with .Get "autoplay" }}{{ if eq . "true" }}?autoplay=1{{ end }}{{ end }}" {{ if not $class }}style="position: absolute; top: 0; left: 0; width: 100%; height: 100%; border:0;" {{ end }}allowfullscreen title="{{ $title }}"></iframe>
 </div>
-{{ end -}}
+{{ template "__h_consent_end" (dict "name" "youtube" "title" "YouTube" "service" $pc) }}
+{{- end -}}
 `},
 	{`twitter_cards.html`, `{{- with $.Params.images -}}
 <meta name="twitter:card" content="summary_large_image"/>
@@ -14,6 +14,8 @@
 package tplimpl
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"io"
 	"os"
 	"path/filepath"
@@ -935,12 +937,78 @@ type templateState struct {
 
 	info     templateInfo
 	baseInfo templateInfo // Set when a base template is used.
+
+	hashesInit sync.Once
+	hashes     map[string]string
+
+	readsOtherPagesInit sync.Once
+	readsOtherPages     bool
 }
 
 func (t *templateState) ParseInfo() tpl.ParseInfo {
 	return t.parseInfo
 }
 
+// TemplateHashes returns the SHA-256 hashes of the source of t, its base
+// template and the partials it includes, keyed by template name. Partials
+// included with a dynamic name are not included.
+func (t *templateState) TemplateHashes() map[string]string {
+	t.hashesInit.Do(func() {
+		t.hashes = make(map[string]string)
+		t.addTemplateHashes(t.hashes, make(map[*templateState]bool))
+	})
+	return t.hashes
+}
+
+func (t *templateState) addTemplateHashes(hashes map[string]string, seen map[*templateState]bool) {
+	if seen[t] {
+		return
+	}
+	seen[t] = true
+
+	for _, info := range []templateInfo{t.info, t.baseInfo} {
+		if info.template != "" {
+			sum := sha256.Sum256([]byte(info.template))
+			hashes[info.name] = hex.EncodeToString(sum[:])
+		}
+	}
+
+	for _, v := range t.GetIdentities() {
+		if ts, ok := v.(*templateState); ok {
+			ts.addTemplateHashes(hashes, seen)
+		}
+	}
+}
+
+// ReadsOtherPages reports whether t, its base template or the partials it
+// includes may read other pages than the page rendered and the pages below
+// it.
+func (t *templateState) ReadsOtherPages() bool {
+	t.readsOtherPagesInit.Do(func() {
+		t.readsOtherPages = t.addReadsOtherPages(make(map[*templateState]bool))
+	})
+	return t.readsOtherPages
+}
+
+func (t *templateState) addReadsOtherPages(seen map[*templateState]bool) bool {
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+
+	if t.parseInfo.ReadsOtherPages {
+		return true
+	}
+
+	for _, v := range t.GetIdentities() {
+		if ts, ok := v.(*templateState); ok && ts.addReadsOtherPages(seen) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (t *templateState) isText() bool {
 	return isText(t.Template)
 }
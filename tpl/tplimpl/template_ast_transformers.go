@@ -173,6 +173,7 @@ func (c *templateContext) applyTransformations(n parse.Node) (bool, error) {
 	case *parse.CommandNode:
 		c.collectPartialInfo(x)
 		c.collectInner(x)
+		c.collectPageReads(x)
 		keep := c.collectReturnNode(x)
 
 		for _, elem := range x.Args {
@@ -271,6 +272,335 @@ func (c *templateContext) collectInner(n *parse.CommandNode) {
 	}
 }
 
+// identsNoOtherPages are the fields and methods that don't read other pages
+// than the page rendered and the pages below it, of pages and the values
+// commonly used with them in list templates, e.g. resources and dates. Any
+// other field or method, e.g. .GetPage, .Next or .Ref, may read any page.
+var identsNoOtherPages = map[string]bool{
+	// Page.
+	"Title":                    true,
+	"LinkTitle":                true,
+	"Content":                  true,
+	"Summary":                  true,
+	"Truncated":                true,
+	"Plain":                    true,
+	"PlainWords":               true,
+	"WordCount":                true,
+	"FuzzyWordCount":           true,
+	"ReadingTime":              true,
+	"Len":                      true,
+	"TableOfContents":          true,
+	"RawContent":               true,
+	"Description":              true,
+	"Keywords":                 true,
+	"Params":                   true,
+	"Param":                    true,
+	"Data":                     true,
+	"Date":                     true,
+	"PublishDate":              true,
+	"Lastmod":                  true,
+	"ExpiryDate":               true,
+	"Weight":                   true,
+	"Draft":                    true,
+	"Kind":                     true,
+	"Type":                     true,
+	"Section":                  true,
+	"Layout":                   true,
+	"Lang":                     true,
+	"Language":                 true,
+	"File":                     true,
+	"Path":                     true,
+	"Slug":                     true,
+	"Aliases":                  true,
+	"Permalink":                true,
+	"RelPermalink":             true,
+	"IsPage":                   true,
+	"IsNode":                   true,
+	"IsHome":                   true,
+	"IsSection":                true,
+	"OutputFormats":            true,
+	"AlternativeOutputFormats": true,
+	"Pages":                    true,
+	"RegularPages":             true,
+	"RegularPagesRecursive":    true,
+	"Sections":                 true,
+	"Paginator":                true,
+	"Paginate":                 true,
+	"Resources":                true,
+	"Scratch":                  true,
+	"Store":                    true,
+
+	// Page collections and groups.
+	"ByWeight":           true,
+	"ByTitle":            true,
+	"ByLinkTitle":        true,
+	"ByDate":             true,
+	"ByPublishDate":      true,
+	"ByExpiryDate":       true,
+	"ByLastmod":          true,
+	"ByLength":           true,
+	"ByLanguage":         true,
+	"ByParam":            true,
+	"Reverse":            true,
+	"GroupBy":            true,
+	"GroupByDate":        true,
+	"GroupByParam":       true,
+	"GroupByPublishDate": true,
+	"GroupByExpiryDate":  true,
+	"GroupByLastmod":     true,
+	"Key":                true,
+	"Page":               true,
+	"Terms":              true,
+	"Alphabetical":       true,
+	"ByCount":            true,
+	"Count":              true,
+	"WeightedPages":      true,
+
+	// Paginators.
+	"PageNumber":            true,
+	"PageSize":              true,
+	"TotalPages":            true,
+	"TotalNumberOfElements": true,
+	"HasPrev":               true,
+	"HasNext":               true,
+	"URL":                   true,
+
+	// Files, resources and output formats.
+	"Name":                true,
+	"Filename":            true,
+	"Dir":                 true,
+	"Ext":                 true,
+	"BaseFileName":        true,
+	"ContentBaseName":     true,
+	"TranslationBaseName": true,
+	"LogicalName":         true,
+	"UniqueID":            true,
+	"ByType":              true,
+	"Get":                 true,
+	"GetMatch":            true,
+	"Match":               true,
+	"Rel":                 true,
+	"MediaType":           true,
+	"MainType":            true,
+	"SubType":             true,
+	"ResourceType":        true,
+	"Width":               true,
+	"Height":              true,
+	"Resize":              true,
+	"Fit":                 true,
+	"Fill":                true,
+	"Filter":              true,
+	"Exif":                true,
+
+	// Shortcodes.
+	"Inner":         true,
+	"InnerDeindent": true,
+	"IsNamedParams": true,
+	"Ordinal":       true,
+	"Position":      true,
+
+	// Dates.
+	"Format":  true,
+	"Year":    true,
+	"Month":   true,
+	"Day":     true,
+	"Weekday": true,
+	"Unix":    true,
+	"IsZero":  true,
+}
+
+// identsKeyed are the identifiers followed by map keys, e.g. .Params.author.
+var identsKeyed = map[string]bool{
+	"Params": true,
+	"Data":   true,
+}
+
+// siteIdentsNoPages are the site fields and methods that don't read any
+// pages. Any other use of the site, e.g. site.RegularPages or passing it to
+// a partial, may read any page.
+var siteIdentsNoPages = map[string]bool{
+	"Title":          true,
+	"Params":         true,
+	"BaseURL":        true,
+	"Language":       true,
+	"LanguageCode":   true,
+	"LanguagePrefix": true,
+	"Languages":      true,
+	"IsMultiLingual": true,
+	"Copyright":      true,
+	"Author":         true,
+	"Social":         true,
+	"Data":           true,
+	"Hugo":           true,
+	"IsServer":       true,
+	"Config":         true,
+}
+
+// funcNamespacesNoPages are the template function namespaces where no
+// function reads any pages.
+var funcNamespacesNoPages = map[string]bool{
+	"cast":      true,
+	"compare":   true,
+	"crypto":    true,
+	"css":       true,
+	"data":      true,
+	"debug":     true,
+	"encoding":  true,
+	"fmt":       true,
+	"hugo":      true,
+	"humanize":  true,
+	"images":    true,
+	"inflect":   true,
+	"js":        true,
+	"lang":      true,
+	"math":      true,
+	"openapi3":  true,
+	"os":        true,
+	"path":      true,
+	"reflect":   true,
+	"safe":      true,
+	"strings":   true,
+	"templates": true,
+	"time":      true,
+	"transform": true,
+}
+
+// funcsNoPages are the other template functions that don't read any pages,
+// with their namespace or by alias. Any other function, e.g. site, ref or
+// collections.Apply, may read any page. The partials are checked when
+// resolved, see collectPartialInfo.
+var funcsNoPages = make(map[string]bool)
+
+func init() {
+	for _, names := range [][]string{
+		// Go's built-in functions.
+		{
+			"and", "or", "not", "len", "index", "slice", "print", "printf", "println",
+			"html", "js", "urlquery", "eq", "ne", "lt", "le", "gt", "ge",
+		},
+		{
+			"collections.After", "after", "collections.Append", "append",
+			"collections.Complement", "complement", "collections.Delimit", "delimit",
+			"collections.Dictionary", "dict", "collections.EchoParam", "echoParam",
+			"collections.First", "first", "collections.FuzzyMatch",
+			"collections.Group", "group", "collections.GroupBy", "groupBy",
+			"collections.In", "in", "collections.Index", "collections.Intersect", "intersect",
+			"collections.IsSet", "isSet", "isset", "collections.KeyVals", "keyVals",
+			"collections.Last", "last", "collections.Merge", "merge",
+			"collections.NewScratch", "newScratch", "collections.Querify", "querify",
+			"collections.Reverse", "collections.SampleSeeded", "collections.Seq", "seq",
+			"collections.Shuffle", "shuffle", "collections.Slice", "collections.Sort", "sort",
+			"collections.SortNatural", "collections.SymDiff", "symdiff",
+			"collections.Union", "union", "collections.Uniq", "uniq", "collections.Where", "where",
+		},
+		{
+			"partials.Include", "partial", "partials.IncludeCached", "partialCached", "return",
+		},
+		{
+			"resources.Babel", "babel", "resources.CSSModules", "resources.Concat",
+			"resources.Fingerprint", "fingerprint", "resources.FromString",
+			"resources.Get", "resources.GetMatch", "resources.Match",
+			"resources.Minify", "minify", "resources.PostCSS", "postCSS",
+			"resources.PostProcess", "resources.SVG", "resources.ToCSS", "toCSS",
+		},
+		{
+			"urls.AbsLangURL", "absLangURL", "urls.AbsURL", "absURL", "urls.Anchorize", "anchorize",
+			"urls.Build", "urls.Parse", "urls.ParseURL", "urls.RelLangURL", "relLangURL",
+			"urls.RelURL", "relURL", "urls.URLize", "urlize",
+		},
+		// The aliases of the functions in funcNamespacesNoPages.
+		{
+			"float", "int", "string", "cond", "default",
+			"hmac", "md5", "sha1", "sha256", "getCSV", "getJSON",
+			"base64Decode", "base64Encode", "jsonify", "errorf", "erroridf", "warnf",
+			"imageConfig", "pluralize", "singularize", "i18n", "T",
+			"add", "sub", "mul", "div", "mod", "modBool", "pow",
+			"fileExists", "getenv", "readDir", "readFile",
+			"safeCSS", "safeHTML", "safeHTMLAttr", "safeJS", "safeJSStr", "safeURL", "sanitizeURL", "sanitizeurl",
+			"chomp", "countrunes", "countwords", "findRE", "hasPrefix", "lower", "upper",
+			"replace", "replaceRE", "slicestr", "split", "substr", "title", "trim", "truncate",
+			"dateFormat", "duration", "now",
+			"emojify", "highlight", "htmlEscape", "htmlUnescape", "markdownify", "plainify", "unmarshal",
+		},
+	} {
+		for _, name := range names {
+			funcsNoPages[name] = true
+		}
+	}
+}
+
+// collectPageReads determines if the given CommandNode may read other pages
+// than the page rendered and the pages below it.
+func (c *templateContext) collectPageReads(n *parse.CommandNode) {
+	if c.t.parseInfo.ReadsOtherPages {
+		return
+	}
+
+	for _, arg := range n.Args {
+		if c.readsOtherPages(arg) {
+			c.t.parseInfo.ReadsOtherPages = true
+			return
+		}
+	}
+}
+
+func (c *templateContext) readsOtherPages(n parse.Node) bool {
+	var (
+		fn     string
+		idents []string
+	)
+	switch nt := n.(type) {
+	case *parse.IdentifierNode:
+		fn = nt.Ident
+	case *parse.FieldNode:
+		idents = nt.Ident
+	case *parse.VariableNode:
+		// Skip the variable name.
+		idents = nt.Ident[1:]
+	case *parse.ChainNode:
+		switch node := nt.Node.(type) {
+		case *parse.PipeNode:
+			c.applyTransformations(node)
+		case *parse.IdentifierNode:
+			fn = node.Ident
+		case *parse.FieldNode:
+			idents = node.Ident
+		case *parse.VariableNode:
+			idents = node.Ident[1:]
+		}
+		idents = append(idents[:len(idents):len(idents)], nt.Field...)
+	}
+
+	if fn != "" {
+		switch {
+		case fn == "site":
+			return len(idents) == 0 || !siteIdentsNoPages[idents[0]]
+		case funcNamespacesNoPages[fn]:
+			if len(idents) > 0 {
+				idents = idents[1:]
+			}
+		case len(idents) > 0 && funcsNoPages[fn+"."+idents[0]]:
+			idents = idents[1:]
+		case !funcsNoPages[fn]:
+			return true
+		}
+	}
+
+	for i, ident := range idents {
+		if ident == "Site" {
+			return i+1 >= len(idents) || !siteIdentsNoPages[idents[i+1]]
+		}
+		if !identsNoOtherPages[ident] {
+			return true
+		}
+		if identsKeyed[ident] {
+			break
+		}
+	}
+
+	return false
+}
+
 var partialRe = regexp.MustCompile(`^partial(Cached)?$|^partials\.Include(Cached)?$`)
 
 func (c *templateContext) collectPartialInfo(x *parse.CommandNode) {
@@ -288,6 +618,11 @@ func (c *templateContext) collectPartialInfo(x *parse.CommandNode) {
 	}
 
 	if partialRe.MatchString(id) {
+		if _, ok := x.Args[1].(*parse.StringNode); !ok {
+			// We can't tell what a partial with a dynamic name reads.
+			c.t.parseInfo.ReadsOtherPages = true
+			return
+		}
 		partialName := strings.Trim(x.Args[1].String(), "\"")
 		if !strings.Contains(partialName, ".") {
 			partialName += ".html"
@@ -41,6 +41,53 @@ func TestTemplateInfoShortcode(t *testing.T) {
 	c.Assert(tti.ParseInfo().IsInner, qt.Equals, true)
 }
 
+func TestTemplateInfoReadsOtherPages(t *testing.T) {
+	c := qt.New(t)
+	d := newD(c)
+	defer d.Close()
+	h := d.Tmpl().(*templateExec)
+
+	for name, templ := range map[string]string{
+		"own.html":           `{{ .Site.Title }}|{{ site.Params.foo }}|{{ range .Pages }}{{ .Title }}{{ end }}|{{ partial "own.html" . }}`,
+		"site.html":          `{{ range first 3 site.RegularPages }}{{ .Title }}{{ end }}`,
+		"siteField.html":     `{{ range where .Site.RegularPages "Section" "blog" }}{{ .Title }}{{ end }}`,
+		"siteWith.html":      `{{ with .Site }}{{ .Title }}{{ end }}`,
+		"getPage.html":       `{{ with .GetPage "/blog" }}{{ .Title }}{{ end }}`,
+		"partial.html":       `{{ partial "site.html" . }}`,
+		"dynamic.html":       `{{ $name := "own.html" }}{{ partial $name . }}`,
+		"funcs.html":         `{{ upper .Title }}|{{ strings.ToLower .Title }}|{{ .RelPermalink | absURL }}|{{ .Params.author.name }}|{{ $.Date.Format "2006" }}`,
+		"ref.html":           `{{ ref . "/blog" }}`,
+		"refMethod.html":     `{{ .Ref (dict "path" "/blog") }}`,
+		"unknownFunc.html":   `{{ apply .Pages "partial" "own.html" "." }}`,
+		"unknownField.html":  `{{ .NextInSeries.Title }}`,
+		"partials/own.html":  `{{ .Title }}`,
+		"partials/site.html": `{{ range site.Pages }}{{ .Title }}{{ end }}`,
+	} {
+		c.Assert(h.AddTemplate(name, templ), qt.IsNil)
+	}
+
+	c.Assert(h.postTransform(), qt.IsNil)
+
+	for name, expect := range map[string]bool{
+		"own.html":          false,
+		"site.html":         true,
+		"siteField.html":    true,
+		"siteWith.html":     true,
+		"getPage.html":      true,
+		"partial.html":      true,
+		"dynamic.html":      true,
+		"funcs.html":        false,
+		"ref.html":          true,
+		"refMethod.html":    true,
+		"unknownFunc.html":  true,
+		"unknownField.html": true,
+	} {
+		tt, found := d.Tmpl().Lookup(name)
+		c.Assert(found, qt.Equals, true)
+		c.Assert(tt.(tpl.PageReadsProvider).ReadsOtherPages(), qt.Equals, expect, qt.Commentf(name))
+	}
+}
+
 // TODO(bep) move and use in other places
 func newD(c *qt.C) *deps.Deps {
 	v := newTestConfig()
@@ -81,6 +81,13 @@ func init() {
 			},
 		)
 
+		ns.AddMethodMapping(ctx.HTMLToText,
+			nil,
+			[][2]string{
+				{`{{ transform.HTMLToText "<p>Hello <strong>world</strong>!</p><ul><li>One</li></ul>" }}`, "Hello world!\n\n- One"},
+			},
+		)
+
 		ns.AddMethodMapping(ctx.Markdownify,
 			[]string{"markdownify"},
 			[][2]string{
@@ -110,6 +117,13 @@ func init() {
 			},
 		)
 
+		ns.AddMethodMapping(ctx.Query,
+			nil,
+			[][2]string{
+				{`{{ "<rss><channel><item><title>Hugo</title></item></channel></rss>" | transform.Unmarshal | transform.Query "channel/item/title" }}`, "[Hugo]"},
+			},
+		)
+
 		return ns
 	}
 
@@ -0,0 +1,351 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gohugoio/hugo/common/maps"
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+)
+
+// Query returns the values in data, typically unmarshaled with Unmarshal,
+// matching the XPath like path, e.g. "channel/item[category='news']/title".
+//
+// The steps in path are separated by "/", and "//" matches at any depth.
+// A step is a name, "*" for any element, "@name" for an attribute or
+// "text()" for the text of an element. Steps can be filtered with
+// predicates: a 1-based index, e.g. "[1]", or "[name]" and "[@name]" to
+// check that a value exists, or "[name='value']" and "[@name='value']" to
+// check its value. "[.='value']" checks the value of the element itself.
+func (ns *Namespace) Query(path interface{}, data interface{}) ([]interface{}, error) {
+	spath, err := cast.ToStringE(path)
+	if err != nil {
+		return nil, err
+	}
+
+	steps, err := parseQuery(spath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid query %q", spath)
+	}
+
+	nodes := []interface{}{data}
+	for _, step := range steps {
+		var next []interface{}
+		for _, node := range nodes {
+			var candidates []interface{}
+			if step.descendant {
+				candidates = queryDescendants(node, step.name, nil)
+			} else {
+				candidates = queryChildren(node, step.name)
+			}
+			next = append(next, step.filter(candidates)...)
+		}
+		nodes = next
+	}
+
+	return nodes, nil
+}
+
+type queryStep struct {
+	// Whether to match at any depth.
+	descendant bool
+
+	name       string
+	predicates []queryPredicate
+}
+
+type queryPredicate struct {
+	// The 1-based index to match, 0 if not an index.
+	index int
+
+	// The name, and value if hasValue, of the child or attribute to check.
+	name     string
+	value    string
+	hasValue bool
+}
+
+func (s queryStep) filter(nodes []interface{}) []interface{} {
+	for _, p := range s.predicates {
+		if p.index > 0 {
+			if p.index > len(nodes) {
+				return nil
+			}
+			nodes = nodes[p.index-1 : p.index]
+			continue
+		}
+
+		var filtered []interface{}
+		for _, node := range nodes {
+			values := []interface{}{node}
+			if p.name != "." {
+				values = queryChildren(node, p.name)
+			}
+			for _, v := range values {
+				if !p.hasValue || queryString(v) == p.value {
+					filtered = append(filtered, node)
+					break
+				}
+			}
+		}
+		nodes = filtered
+	}
+
+	return nodes
+}
+
+func parseQuery(path string) ([]queryStep, error) {
+	if path == "" {
+		return nil, errors.New("empty path")
+	}
+
+	var (
+		steps      []queryStep
+		descendant bool
+	)
+
+	// A leading "/" is the root, i.e. data.
+	if strings.HasPrefix(path, "//") {
+		descendant = true
+		path = path[2:]
+	} else {
+		path = strings.TrimPrefix(path, "/")
+	}
+
+	for {
+		i := indexStepEnd(path)
+		step, err := parseQueryStep(path[:i])
+		if err != nil {
+			return nil, err
+		}
+		step.descendant = descendant
+		steps = append(steps, step)
+
+		if i == len(path) {
+			break
+		}
+		path = path[i+1:]
+		descendant = strings.HasPrefix(path, "/")
+		if descendant {
+			path = path[1:]
+		}
+	}
+
+	return steps, nil
+}
+
+// indexStepEnd returns the index of the "/" ending the first step in path,
+// or the length of path if it's the last step.
+func indexStepEnd(path string) int {
+	var (
+		depth int
+		quote byte
+	)
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+		case c == '/' && depth == 0:
+			return i
+		}
+	}
+	return len(path)
+}
+
+func parseQueryStep(s string) (queryStep, error) {
+	var step queryStep
+
+	i := strings.IndexByte(s, '[')
+	if i == -1 {
+		i = len(s)
+	}
+	step.name = strings.TrimSpace(s[:i])
+	if step.name == "" || step.name == "@" {
+		return step, errors.Errorf("missing name in step %q", s)
+	}
+
+	s = s[i:]
+	for s != "" {
+		if s[0] != '[' {
+			return step, errors.Errorf("unexpected %q", s)
+		}
+		end := indexPredicateEnd(s)
+		if end == -1 {
+			return step, errors.Errorf("unclosed predicate %q", s)
+		}
+		p, err := parseQueryPredicate(strings.TrimSpace(s[1:end]))
+		if err != nil {
+			return step, err
+		}
+		step.predicates = append(step.predicates, p)
+		s = s[end+1:]
+	}
+
+	return step, nil
+}
+
+// indexPredicateEnd returns the index of the "]" closing the predicate s
+// starts with, -1 if not found.
+func indexPredicateEnd(s string) int {
+	var quote byte
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == ']':
+			return i
+		}
+	}
+	return -1
+}
+
+func parseQueryPredicate(s string) (queryPredicate, error) {
+	var p queryPredicate
+
+	if index, err := strconv.Atoi(s); err == nil {
+		if index < 1 {
+			return p, errors.Errorf("invalid index %d, the first is 1", index)
+		}
+		p.index = index
+		return p, nil
+	}
+
+	name, value, hasValue := s, "", false
+	if i := strings.IndexByte(s, '='); i != -1 {
+		name, value, hasValue = strings.TrimSpace(s[:i]), strings.TrimSpace(s[i+1:]), true
+		if len(value) >= 2 && (value[0] == '\'' || value[0] == '"') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		} else if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return p, errors.Errorf("invalid value %q in predicate, strings must be quoted", value)
+		}
+	}
+	if name == "" || name == "@" {
+		return p, errors.Errorf("missing name in predicate %q", s)
+	}
+
+	p.name, p.value, p.hasValue = name, value, hasValue
+
+	return p, nil
+}
+
+type queryEntry struct {
+	name  string
+	value interface{}
+}
+
+// queryEntries returns the children, attributes and text of node, sorted by
+// name, with repeated elements expanded.
+func queryEntries(node interface{}) []queryEntry {
+	var m map[string]interface{}
+	switch v := node.(type) {
+	case map[string]interface{}:
+		m = v
+	case maps.Params:
+		m = v
+	case []interface{}:
+		var entries []queryEntry
+		for _, vv := range v {
+			entries = append(entries, queryEntries(vv)...)
+		}
+		return entries
+	case string:
+		return []queryEntry{{name: "text()", value: v}}
+	default:
+		return nil
+	}
+
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var entries []queryEntry
+	for _, k := range keys {
+		name := k
+		switch {
+		case k == "#text":
+			name = "text()"
+		case strings.HasPrefix(k, "-"):
+			name = "@" + k[1:]
+		}
+
+		if vv, ok := m[k].([]interface{}); ok && !strings.HasPrefix(name, "@") {
+			for _, e := range vv {
+				entries = append(entries, queryEntry{name: name, value: e})
+			}
+			continue
+		}
+		entries = append(entries, queryEntry{name: name, value: m[k]})
+	}
+
+	return entries
+}
+
+func queryMatch(name, pattern string) bool {
+	if pattern == "*" {
+		return name != "text()" && !strings.HasPrefix(name, "@")
+	}
+	return name == pattern
+}
+
+func queryChildren(node interface{}, name string) []interface{} {
+	var children []interface{}
+	for _, e := range queryEntries(node) {
+		if queryMatch(e.name, name) {
+			children = append(children, e.value)
+		}
+	}
+	return children
+}
+
+func queryDescendants(node interface{}, name string, matches []interface{}) []interface{} {
+	for _, e := range queryEntries(node) {
+		if queryMatch(e.name, name) {
+			matches = append(matches, e.value)
+		}
+		if e.name != "text()" && !strings.HasPrefix(e.name, "@") {
+			matches = queryDescendants(e.value, name, matches)
+		}
+	}
+	return matches
+}
+
+// queryString returns the string value of v, the text of elements.
+func queryString(v interface{}) string {
+	switch vv := v.(type) {
+	case map[string]interface{}:
+		return cast.ToString(vv["#text"])
+	case maps.Params:
+		return cast.ToString(vv["#text"])
+	}
+	return cast.ToString(v)
+}
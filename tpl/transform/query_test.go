@@ -0,0 +1,96 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gohugoio/hugo/config"
+)
+
+func TestQuery(t *testing.T) {
+	c := qt.New(t)
+
+	ns := New(newDeps(config.New()))
+
+	feed := `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0">
+  <channel>
+    <title>Legacy Feed</title>
+    <item>
+      <title>First</title>
+      <category>news</category>
+      <enclosure url="https://example.org/a.mp3" type="audio/mpeg"/>
+    </item>
+    <item>
+      <title>Second</title>
+      <category>blog</category>
+      <category>news</category>
+    </item>
+    <item>
+      <title lang="fr">Troisième</title>
+      <category>blog</category>
+    </item>
+  </channel>
+</rss>`
+
+	data, err := ns.Unmarshal(feed)
+	c.Assert(err, qt.IsNil)
+
+	for _, test := range []struct {
+		path   string
+		expect interface{}
+	}{
+		{"channel/title", []interface{}{"Legacy Feed"}},
+		{"/channel/item/title", []interface{}{"First", "Second", map[string]interface{}{"-lang": "fr", "#text": "Troisième"}}},
+		{"channel/item/title/text()", []interface{}{"First", "Second", "Troisième"}},
+		{"channel/item[2]/title", []interface{}{"Second"}},
+		{"channel/item[4]/title", []interface{}(nil)},
+		{"channel/item[category='news']/title", []interface{}{"First", "Second"}},
+		{"channel/item[enclosure]/enclosure/@url", []interface{}{"https://example.org/a.mp3"}},
+		{"channel/item/title[@lang='fr']/text()", []interface{}{"Troisième"}},
+		{"channel/item/category[1]", []interface{}{"news", "blog", "blog"}},
+		{"//enclosure/@type", []interface{}{"audio/mpeg"}},
+		{"//@version", []interface{}{"2.0"}},
+		{"channel//category[.='news']", []interface{}{"news", "news"}},
+		{"channel/*[1]/title", []interface{}{"First"}},
+		{"missing/path", []interface{}(nil)},
+		// Errors.
+		{"", false},
+		{"channel/", false},
+		{"channel/item[0]", false},
+		{"channel/item[category=news]", false},
+		{"channel/item[category='news'", false},
+		{"channel/item[]", false},
+	} {
+		result, err := ns.Query(test.path, data)
+		if b, ok := test.expect.(bool); ok && !b {
+			c.Assert(err, qt.Not(qt.IsNil), qt.Commentf(test.path))
+			continue
+		}
+		c.Assert(err, qt.IsNil, qt.Commentf(test.path))
+		c.Assert(result, qt.DeepEquals, test.expect, qt.Commentf(test.path))
+	}
+
+	// JSON works the same way.
+	data, err = ns.Unmarshal(`{"items": [{"id": 1, "tags": ["a", "b"]}, {"id": 2, "tags": ["b"]}]}`)
+	c.Assert(err, qt.IsNil)
+	result, err := ns.Query("items[tags='a']/id", data)
+	c.Assert(err, qt.IsNil)
+	c.Assert(result, qt.DeepEquals, []interface{}{float64(1)})
+	result, err = ns.Query("items[id=2]/tags", data)
+	c.Assert(err, qt.IsNil)
+	c.Assert(result, qt.DeepEquals, []interface{}{"b"})
+}
@@ -22,6 +22,7 @@ import (
 
 	"github.com/gohugoio/hugo/deps"
 	"github.com/gohugoio/hugo/helpers"
+	"github.com/gohugoio/hugo/transform/email"
 	"github.com/spf13/cast"
 )
 
@@ -90,6 +91,17 @@ func (ns *Namespace) HTMLUnescape(s interface{}) (string, error) {
 	return html.UnescapeString(ss), nil
 }
 
+// HTMLToText converts the HTML in s to readable plain text, e.g. for the
+// plain text alternative of an email.
+func (ns *Namespace) HTMLToText(s interface{}) (string, error) {
+	ss, err := cast.ToStringE(s)
+	if err != nil {
+		return "", err
+	}
+
+	return email.ToText(ss)
+}
+
 // Markdownify renders a given input from Markdown to HTML.
 func (ns *Namespace) Markdownify(s interface{}) (template.HTML, error) {
 	ss, err := cast.ToStringE(s)
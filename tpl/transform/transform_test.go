@@ -207,6 +207,35 @@ And then some.
 		"<p>#First</p>\n<p>This is some <em>bold</em> text.</p>\n<h2 id=\"second\">Second</h2>\n<p>This is some more text.</p>\n<p>And then some.</p>\n"))
 }
 
+func TestHTMLToText(t *testing.T) {
+	t.Parallel()
+	c := qt.New(t)
+
+	v := config.New()
+	ns := New(newDeps(v))
+
+	for _, test := range []struct {
+		s      interface{}
+		expect interface{}
+	}{
+		{"<p>Read <a href=\"https://example.org\">this</a>.</p><p>Bye</p>", "Read this (https://example.org).\n\nBye"},
+		{template.HTML("<ol><li>One</li><li>Two</li></ol>"), "1. One\n2. Two"},
+		// errors
+		{tstNoStringer{}, false},
+	} {
+
+		result, err := ns.HTMLToText(test.s)
+
+		if b, ok := test.expect.(bool); ok && !b {
+			c.Assert(err, qt.Not(qt.IsNil))
+			continue
+		}
+
+		c.Assert(err, qt.IsNil)
+		c.Assert(result, qt.Equals, test.expect)
+	}
+}
+
 func TestPlainify(t *testing.T) {
 	t.Parallel()
 	c := qt.New(t)
@@ -241,7 +270,7 @@ func newDeps(cfg config.Provider) *deps.Deps {
 
 	l := langs.NewLanguage("en", cfg)
 
-	cs, err := helpers.NewContentSpec(l, loggers.NewErrorLogger(), afero.NewMemMapFs())
+	cs, err := helpers.NewContentSpec(l, loggers.NewErrorLogger(), afero.NewMemMapFs(), nil)
 	if err != nil {
 		panic(err)
 	}
@@ -59,6 +59,20 @@ func init() {
 			[][2]string{},
 		)
 
+		ns.AddMethodMapping(ctx.ParseURL,
+			nil,
+			[][2]string{
+				{`{{ with urls.ParseURL "https://example.org:8080/search?q=hugo#top" }}{{ .Hostname }}|{{ .Port }}|{{ .Path }}|{{ .Query.Get "q" }}|{{ .Fragment }}{{ end }}`, `example.org|8080|/search|hugo|top`},
+			},
+		)
+
+		ns.AddMethodMapping(ctx.Build,
+			nil,
+			[][2]string{
+				{`{{ urls.Build (dict "scheme" "https" "host" "example.org" "path" "/a b" "query" (dict "q" "x&y")) }}`, `https://example.org/a%20b?q=x%26y`},
+			},
+		)
+
 		ns.AddMethodMapping(ctx.Anchorize,
 			[]string{"anchorize"},
 			[][2]string{
@@ -19,7 +19,10 @@ import (
 	"fmt"
 	"html/template"
 	"net/url"
+	"reflect"
+	"strings"
 
+	"github.com/gohugoio/hugo/common/maps"
 	"github.com/gohugoio/hugo/common/urls"
 	"github.com/gohugoio/hugo/deps"
 	_errors "github.com/pkg/errors"
@@ -185,3 +188,156 @@ func (ns *Namespace) AbsLangURL(a interface{}) (template.HTML, error) {
 
 	return template.HTML(ns.deps.PathSpec.AbsURL(s, !ns.multihost)), nil
 }
+
+// URL is a parsed URL, see ParseURL.
+type URL struct {
+	Scheme   string
+	Username string
+	Host     string // The host, with the port, if any.
+	Hostname string // The host without the port.
+	Port     string
+	Path     string // The unescaped path.
+	Query    url.Values
+	Fragment string
+
+	u *url.URL
+}
+
+// String returns the URL as a string.
+func (u URL) String() string {
+	return u.u.String()
+}
+
+// IsAbs returns whether the URL is absolute, i.e. has a scheme.
+func (u URL) IsAbs() bool {
+	return u.u.IsAbs()
+}
+
+// ParseURL parses rawurl, which may be relative or absolute, into a URL
+// with the query parameters decoded, e.g.:
+//
+//	{{ with urls.ParseURL "https://example.org/search?q=hugo&tag=a&tag=b" }}
+//	  {{ .Host }} {{ .Query.Get "q" }} {{ index .Query "tag" }}
+//	{{ end }}
+func (ns *Namespace) ParseURL(rawurl interface{}) (URL, error) {
+	s, err := cast.ToStringE(rawurl)
+	if err != nil {
+		return URL{}, _errors.Wrap(err, "Error in ParseURL")
+	}
+
+	u, err := url.Parse(s)
+	if err != nil {
+		return URL{}, err
+	}
+
+	return URL{
+		Scheme:   u.Scheme,
+		Username: u.User.Username(),
+		Host:     u.Host,
+		Hostname: u.Hostname(),
+		Port:     u.Port(),
+		Path:     u.Path,
+		Query:    u.Query(),
+		Fragment: u.Fragment,
+		u:        u,
+	}, nil
+}
+
+// Build builds a URL from its parts, escaping them as needed, e.g.:
+//
+//	{{ urls.Build (dict "url" .Permalink "query" (dict "utm_source" "feed" "tags" (slice "a b" "c"))) }}
+//
+// The parts are url, a URL to start from, and scheme, host, path, query and
+// fragment, which replace those in url. The query values may be strings,
+// numbers or slices, and are added to those in url, replacing any with the
+// same name. The query parameters are sorted by name.
+func (ns *Namespace) Build(parts interface{}) (string, error) {
+	m, err := maps.ToStringMapE(parts)
+	if err != nil {
+		return "", _errors.Wrap(err, "Error in Build")
+	}
+
+	u := &url.URL{}
+	for k, v := range m {
+		if strings.ToLower(k) != "url" {
+			continue
+		}
+		s, err := cast.ToStringE(v)
+		if err != nil {
+			return "", _errors.Wrap(err, "Error in Build")
+		}
+		if u, err = url.Parse(s); err != nil {
+			return "", err
+		}
+	}
+
+	query := u.Query()
+
+	for k, v := range m {
+		k = strings.ToLower(k)
+		if k == "url" {
+			continue
+		}
+
+		if k == "query" {
+			qm, err := maps.ToStringMapE(v)
+			if err != nil {
+				return "", _errors.Wrap(err, "Error in Build: invalid query")
+			}
+			for name, value := range qm {
+				values, err := queryValues(value)
+				if err != nil {
+					return "", _errors.Wrapf(err, "Error in Build: invalid query parameter %q", name)
+				}
+				query[name] = values
+			}
+			continue
+		}
+
+		s, err := cast.ToStringE(v)
+		if err != nil {
+			return "", _errors.Wrapf(err, "Error in Build: invalid %s", k)
+		}
+
+		switch k {
+		case "scheme":
+			u.Scheme = s
+		case "host":
+			u.Host = s
+		case "path":
+			u.Path = s
+			u.RawPath = ""
+		case "fragment":
+			u.Fragment = s
+			u.RawFragment = ""
+		default:
+			return "", fmt.Errorf("Error in Build: unknown URL part %q", k)
+		}
+	}
+
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
+
+func queryValues(v interface{}) ([]string, error) {
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.Slice, reflect.Array:
+		vv := reflect.ValueOf(v)
+		values := make([]string, vv.Len())
+		for i := 0; i < vv.Len(); i++ {
+			s, err := cast.ToStringE(vv.Index(i).Interface())
+			if err != nil {
+				return nil, err
+			}
+			values[i] = s
+		}
+		return values, nil
+	}
+
+	s, err := cast.ToStringE(v)
+	if err != nil {
+		return nil, err
+	}
+	return []string{s}, nil
+}
@@ -68,3 +68,69 @@ func TestParse(t *testing.T) {
 			qt.CmpEquals(hqt.DeepAllowUnexported(&url.URL{}, url.Userinfo{})), test.expect)
 	}
 }
+
+func TestParseURL(t *testing.T) {
+	t.Parallel()
+	c := qt.New(t)
+
+	u, err := ns.ParseURL("https://jane@example.org:8080/a%20b/c?q=hugo&tag=a&tag=b#top")
+	c.Assert(err, qt.IsNil)
+	c.Assert(u.Scheme, qt.Equals, "https")
+	c.Assert(u.Username, qt.Equals, "jane")
+	c.Assert(u.Host, qt.Equals, "example.org:8080")
+	c.Assert(u.Hostname, qt.Equals, "example.org")
+	c.Assert(u.Port, qt.Equals, "8080")
+	c.Assert(u.Path, qt.Equals, "/a b/c")
+	c.Assert(u.Query.Get("q"), qt.Equals, "hugo")
+	c.Assert(u.Query["tag"], qt.DeepEquals, []string{"a", "b"})
+	c.Assert(u.Fragment, qt.Equals, "top")
+	c.Assert(u.IsAbs(), qt.IsTrue)
+	c.Assert(u.String(), qt.Equals, "https://jane@example.org:8080/a%20b/c?q=hugo&tag=a&tag=b#top")
+
+	u, err = ns.ParseURL("/docs/?page=2")
+	c.Assert(err, qt.IsNil)
+	c.Assert(u.IsAbs(), qt.IsFalse)
+	c.Assert(u.Path, qt.Equals, "/docs/")
+	c.Assert(u.Query.Get("page"), qt.Equals, "2")
+
+	_, err = ns.ParseURL(tstNoStringer{})
+	c.Assert(err, qt.Not(qt.IsNil))
+	_, err = ns.ParseURL("http://[::1")
+	c.Assert(err, qt.Not(qt.IsNil))
+}
+
+func TestBuild(t *testing.T) {
+	t.Parallel()
+	c := qt.New(t)
+
+	for _, test := range []struct {
+		parts  interface{}
+		expect interface{}
+	}{
+		{map[string]interface{}{"scheme": "https", "host": "example.org", "path": "/a b/"}, "https://example.org/a%20b/"},
+		{map[string]interface{}{"host": "example.org", "path": "docs"}, "//example.org/docs"},
+		{map[string]interface{}{"path": "/search", "query": map[string]interface{}{"q": "hugo & go", "page": 2}}, "/search?page=2&q=hugo+%26+go"},
+		{map[string]interface{}{"path": "/", "query": map[string]interface{}{"tag": []interface{}{"a", "b c"}}}, "/?tag=a&tag=b+c"},
+		{map[string]interface{}{"path": "/", "fragment": "a b"}, "/#a%20b"},
+		{map[string]interface{}{"url": "https://example.org/p/?a=1&b=2#x", "query": map[string]interface{}{"b": "3", "c": "4"}}, "https://example.org/p/?a=1&b=3&c=4#x"},
+		{map[string]interface{}{"URL": "https://example.org/p/", "Path": "/q/"}, "https://example.org/q/"},
+		// errors
+		{"https://example.org", false},
+		{map[string]interface{}{"port": "80"}, false},
+		{map[string]interface{}{"query": "a=b"}, false},
+		{map[string]interface{}{"path": tstNoStringer{}}, false},
+		{map[string]interface{}{"url": "http://[::1"}, false},
+	} {
+		errMsg := qt.Commentf("%v", test.parts)
+
+		result, err := ns.Build(test.parts)
+
+		if b, ok := test.expect.(bool); ok && !b {
+			c.Assert(err, qt.Not(qt.IsNil), errMsg)
+			continue
+		}
+
+		c.Assert(err, qt.IsNil, errMsg)
+		c.Assert(result, qt.Equals, test.expect, errMsg)
+	}
+}
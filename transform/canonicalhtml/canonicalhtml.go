@@ -0,0 +1,395 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package canonicalhtml formats the published HTML in a deterministic way,
+// so the changes in the output between builds are easy to review.
+package canonicalhtml
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gohugoio/hugo/common/maps"
+	"github.com/gohugoio/hugo/config"
+	"github.com/gohugoio/hugo/transform"
+	"github.com/mitchellh/mapstructure"
+	"golang.org/x/net/html"
+)
+
+const canonicalHTMLConfigKey = "canonicalHTML"
+
+// Config configures the formatting of the published HTML.
+type Config struct {
+	// Enable formatting of the HTML output formats.
+	Enable bool
+
+	// The indentation of nested block elements. Default is two spaces.
+	Indent string
+}
+
+var defaultConfig = Config{
+	Indent: "  ",
+}
+
+// DecodeConfig creates a canonical HTML Config from the given configuration.
+func DecodeConfig(cfg config.Provider) (conf Config, err error) {
+	conf = defaultConfig
+
+	v := cfg.Get(canonicalHTMLConfigKey)
+	if v == nil {
+		return
+	}
+
+	err = mapstructure.WeakDecode(maps.ToStringMap(v), &conf)
+
+	return
+}
+
+// Elements that are formatted on their own lines.
+var blockElements = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true,
+	"body": true, "dd": true, "details": true, "dialog": true, "div": true,
+	"dl": true, "dt": true, "fieldset": true, "figcaption": true,
+	"figure": true, "footer": true, "form": true, "h1": true, "h2": true,
+	"h3": true, "h4": true, "h5": true, "h6": true, "head": true,
+	"header": true, "hgroup": true, "hr": true, "html": true, "li": true,
+	"link": true, "main": true, "meta": true, "nav": true, "noscript": true,
+	"ol": true, "p": true, "pre": true, "script": true, "section": true,
+	"style": true, "summary": true, "table": true, "tbody": true, "td": true,
+	"template": true, "tfoot": true, "th": true, "thead": true, "title": true,
+	"tr": true, "ul": true, "base": true, "caption": true, "colgroup": true,
+	"option": true, "optgroup": true, "select": true,
+}
+
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true,
+	"hr": true, "img": true, "input": true, "link": true, "meta": true,
+	"param": true, "source": true, "track": true, "wbr": true,
+}
+
+// Elements with content that is kept as is.
+var preformattedElements = map[string]bool{
+	"pre":      true,
+	"textarea": true,
+	"script":   true,
+	"style":    true,
+}
+
+// Elements with an optional end tag, closed by the start of any of the
+// given elements.
+var impliedEndTags = map[string]map[string]bool{
+	"li":     {"li": true},
+	"dt":     {"dt": true, "dd": true},
+	"dd":     {"dt": true, "dd": true},
+	"p":      {"p": true},
+	"option": {"option": true, "optgroup": true},
+	"tr":     {"tr": true},
+	"td":     {"td": true, "th": true, "tr": true},
+	"th":     {"td": true, "th": true, "tr": true},
+}
+
+var whitespaceRe = regexp.MustCompile(`[ \t\n\f\r]+`)
+
+// Transformer returns a transformer formatting HTML with the given
+// configuration.
+func Transformer(conf Config) transform.Transformer {
+	return func(ft transform.FromTo) error {
+		_, err := ft.To().Write(Format(ft.From().Bytes(), conf.Indent))
+		return err
+	}
+}
+
+type nodeType int
+
+const (
+	elementNode nodeType = iota
+	textNode
+	rawNode
+)
+
+type node struct {
+	typ nodeType
+
+	// The tag name of elements, or the content of text and raw nodes.
+	data string
+
+	// The formatted start tag of elements.
+	startTag string
+
+	// Whether the element has an end tag.
+	hasEnd bool
+
+	children []*node
+}
+
+func (n *node) isBlock() bool {
+	return n.typ == elementNode && blockElements[n.data]
+}
+
+// Format formats the HTML document src: the attributes are sorted by name
+// and quoted the same way, the whitespace in text is collapsed, and block
+// elements are put on their own lines, indented with indent. The content
+// of elements like pre and script is kept as is.
+//
+// The elements are not otherwise changed, e.g. no omitted end tags are
+// added.
+func Format(src []byte, indent string) []byte {
+	root := parse(src)
+
+	f := &formatter{indent: indent}
+	f.blockChildren(root.children, 0)
+
+	b := f.b.Bytes()
+	b = bytes.TrimLeft(b, "\n")
+	if len(b) > 0 {
+		b = append(b, '\n')
+	}
+	return b
+}
+
+func parse(src []byte) *node {
+	root := &node{}
+	stack := []*node{root}
+
+	// The number of pre elements nested in the open pre element.
+	var preNesting int
+
+	top := func() *node {
+		return stack[len(stack)-1]
+	}
+
+	add := func(n *node) {
+		p := top()
+		p.children = append(p.children, n)
+	}
+
+	z := html.NewTokenizer(bytes.NewReader(src))
+
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			if z.Err() == io.EOF {
+				break
+			}
+			// Keep what is left as is.
+			add(&node{typ: rawNode, data: string(z.Raw())})
+			break
+		}
+
+		switch tt {
+		case html.TextToken:
+			if p := top(); p.typ == elementNode && preformattedElements[p.data] {
+				add(&node{typ: rawNode, data: string(z.Raw())})
+			} else {
+				add(&node{typ: textNode, data: string(z.Raw())})
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			if top().data == "pre" {
+				// Keep the content of pre as is.
+				if string(tagName(z.Raw())) == "pre" && tt == html.StartTagToken {
+					preNesting++
+				}
+				add(&node{typ: rawNode, data: string(z.Raw())})
+				continue
+			}
+			name, attrs := readTag(z)
+			for len(stack) > 1 && impliedEndTags[top().data][name] {
+				stack = stack[:len(stack)-1]
+			}
+			n := &node{typ: elementNode, data: name, startTag: startTag(name, attrs, tt == html.SelfClosingTagToken && !voidElements[name])}
+			add(n)
+			if tt == html.StartTagToken && !voidElements[name] {
+				stack = append(stack, n)
+			}
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			if top().data == "pre" && (string(name) != "pre" || preNesting > 0) {
+				if string(name) == "pre" {
+					preNesting--
+				}
+				add(&node{typ: rawNode, data: string(z.Raw())})
+				continue
+			}
+			i := len(stack) - 1
+			for ; i > 0; i-- {
+				if stack[i].data == string(name) {
+					break
+				}
+			}
+			if i == 0 {
+				// An end tag without a start tag, keep it.
+				add(&node{typ: rawNode, data: string(z.Raw())})
+				continue
+			}
+			stack[i].hasEnd = true
+			stack = stack[:i]
+		default:
+			// Comments and doctypes.
+			add(&node{typ: rawNode, data: string(z.Raw())})
+		}
+	}
+
+	return root
+}
+
+func readTag(z *html.Tokenizer) (string, []html.Attribute) {
+	name, hasAttr := z.TagName()
+	var attrs []html.Attribute
+	for hasAttr {
+		var key, val []byte
+		key, val, hasAttr = z.TagAttr()
+		attrs = append(attrs, html.Attribute{Key: string(key), Val: string(val)})
+	}
+	return string(name), attrs
+}
+
+// tagName returns the lower case tag name of the raw tag s.
+func tagName(s []byte) []byte {
+	s = bytes.TrimLeft(s, "</")
+	i := bytes.IndexAny(s, " \t\n\f\r/>")
+	if i != -1 {
+		s = s[:i]
+	}
+	return bytes.ToLower(s)
+}
+
+var attrValueEscaper = strings.NewReplacer(`&`, "&amp;", `"`, "&quot;")
+
+func startTag(name string, attrs []html.Attribute, selfClosing bool) string {
+	sort.SliceStable(attrs, func(i, j int) bool {
+		return attrs[i].Key < attrs[j].Key
+	})
+
+	var b strings.Builder
+	b.WriteString("<")
+	b.WriteString(name)
+	for _, a := range attrs {
+		b.WriteString(" ")
+		b.WriteString(a.Key)
+		if a.Val != "" {
+			b.WriteString(`="`)
+			b.WriteString(attrValueEscaper.Replace(a.Val))
+			b.WriteString(`"`)
+		}
+	}
+	if selfClosing {
+		b.WriteString(" /")
+	}
+	b.WriteString(">")
+	return b.String()
+}
+
+type formatter struct {
+	b      bytes.Buffer
+	indent string
+}
+
+func (f *formatter) newline(depth int) {
+	f.b.WriteString("\n")
+	f.b.WriteString(strings.Repeat(f.indent, depth))
+}
+
+// blockChildren writes nodes, the children of a block element, with the
+// block elements on their own lines.
+func (f *formatter) blockChildren(nodes []*node, depth int) {
+	var run []*node
+
+	flush := func() {
+		var b strings.Builder
+		for _, n := range run {
+			f.inlineTo(&b, n)
+		}
+		run = run[:0]
+		s := strings.TrimSpace(b.String())
+		if s == "" {
+			return
+		}
+		f.newline(depth)
+		f.b.WriteString(s)
+	}
+
+	for _, n := range nodes {
+		if !n.isBlock() {
+			if n.typ == rawNode && strings.HasPrefix(n.data, "<!") {
+				// Comments and doctypes on their own lines.
+				flush()
+				f.newline(depth)
+				f.b.WriteString(n.data)
+				continue
+			}
+			run = append(run, n)
+			continue
+		}
+		flush()
+		f.block(n, depth)
+	}
+	flush()
+}
+
+func (f *formatter) block(n *node, depth int) {
+	f.newline(depth)
+	f.b.WriteString(n.startTag)
+
+	var hasBlockChildren bool
+	for _, c := range n.children {
+		if c.isBlock() {
+			hasBlockChildren = true
+			break
+		}
+	}
+
+	switch {
+	case preformattedElements[n.data]:
+		var b strings.Builder
+		for _, c := range n.children {
+			f.inlineTo(&b, c)
+		}
+		f.b.WriteString(b.String())
+	case hasBlockChildren:
+		f.blockChildren(n.children, depth+1)
+		if n.hasEnd {
+			f.newline(depth)
+		}
+	default:
+		var b strings.Builder
+		for _, c := range n.children {
+			f.inlineTo(&b, c)
+		}
+		f.b.WriteString(strings.TrimSpace(b.String()))
+	}
+
+	if n.hasEnd {
+		f.b.WriteString("</" + n.data + ">")
+	}
+}
+
+// inlineTo writes n, and its children, to b on one line.
+func (f *formatter) inlineTo(b *strings.Builder, n *node) {
+	switch n.typ {
+	case textNode:
+		b.WriteString(whitespaceRe.ReplaceAllString(n.data, " "))
+	case rawNode:
+		b.WriteString(n.data)
+	case elementNode:
+		b.WriteString(n.startTag)
+		for _, c := range n.children {
+			f.inlineTo(b, c)
+		}
+		if n.hasEnd {
+			b.WriteString("</" + n.data + ">")
+		}
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package canonicalhtml
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gohugoio/hugo/config"
+)
+
+func TestDecodeConfig(t *testing.T) {
+	c := qt.New(t)
+
+	cfg := config.New()
+	conf, err := DecodeConfig(cfg)
+	c.Assert(err, qt.IsNil)
+	c.Assert(conf.Enable, qt.Equals, false)
+	c.Assert(conf.Indent, qt.Equals, "  ")
+
+	cfg.Set("canonicalHTML", map[string]interface{}{
+		"enable": true,
+		"indent": "\t",
+	})
+	conf, err = DecodeConfig(cfg)
+	c.Assert(err, qt.IsNil)
+	c.Assert(conf.Enable, qt.Equals, true)
+	c.Assert(conf.Indent, qt.Equals, "\t")
+}
+
+func TestFormat(t *testing.T) {
+	c := qt.New(t)
+
+	for _, test := range []struct {
+		name   string
+		in     string
+		expect string
+	}{
+		{
+			"Document",
+			`<!DOCTYPE html><html lang="en"><head>  <meta charset=utf-8><title> My   Title </title></head>
+<body><div id="main" class="a"><p>Some   <em>text</em>,
+more.</p><!-- comment --></div></body></html>`,
+			`<!DOCTYPE html>
+<html lang="en">
+  <head>
+    <meta charset="utf-8">
+    <title>My Title</title>
+  </head>
+  <body>
+    <div class="a" id="main">
+      <p>Some <em>text</em>, more.</p>
+      <!-- comment -->
+    </div>
+  </body>
+</html>
+`,
+		},
+		{
+			"Attributes",
+			`<a title='Say "hi"' href=/a?b=1&amp;c=2 data-x>Link</a>`,
+			`<a data-x href="/a?b=1&amp;c=2" title="Say &quot;hi&quot;">Link</a>
+`,
+		},
+		{
+			"Preformatted",
+			"<div><pre class=\"x\">  a\n  <b>b</b>\n   c</pre><script>if (a  <  b) {\n  c();\n}</script></div>",
+			"<div>\n  <pre class=\"x\">  a\n  <b>b</b>\n   c</pre>\n  <script>if (a  <  b) {\n  c();\n}</script>\n</div>\n",
+		},
+		{
+			"Nested pre",
+			"<pre>a<pre> b </pre>  c</pre><p>d</p>",
+			"<pre>a<pre> b </pre>  c</pre>\n<p>d</p>\n",
+		},
+		{
+			"Omitted end tags",
+			"<ul><li>a<li>b</ul>",
+			"<ul>\n  <li>a\n  <li>b\n</ul>\n",
+		},
+		{
+			"Stray end tag",
+			"<div>a</span></div>",
+			"<div>a</span></div>\n",
+		},
+		{
+			"Self closing",
+			`<div><svg viewBox="0 0 1 1"><path d="M0"/></svg><br/></div>`,
+			`<div><svg viewbox="0 0 1 1"><path d="M0" /></svg><br></div>
+`,
+		},
+		{
+			"Empty",
+			"",
+			"",
+		},
+	} {
+		test := test
+		c.Run(test.name, func(c *qt.C) {
+			got := string(Format([]byte(test.in), "  "))
+			c.Assert(got, qt.Equals, test.expect)
+			// Formatting is idempotent.
+			c.Assert(string(Format([]byte(got), "  ")), qt.Equals, got)
+		})
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package email
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gohugoio/hugo/transform"
+)
+
+func TestInline(t *testing.T) {
+	c := qt.New(t)
+
+	in := `<!DOCTYPE html>
+<html><head><style>
+body { color: #333; font-family: Arial, sans-serif }
+p, .lead { margin: 0 0 1em; }
+.lead { font-size: 18px }
+#main > p.note { color: red !important }
+table td a[target="_blank"] { color: blue }
+a:hover { color: green }
+@media (max-width: 600px) { .lead { font-size: 16px } }
+</style></head>
+<body><div id="main"><p class="lead">Lead</p><p class="note" style="color: black; padding: 0">Note</p></div>
+<table><tr><td><a href="/" target="_blank">Link</a><a href="/">Other</a></td></tr></table>
+</body></html>`
+
+	out, err := Inline([]byte(in))
+	c.Assert(err, qt.IsNil)
+	s := string(out)
+
+	c.Assert(s, qt.Contains, `<body style="color:#333;font-family:Arial,sans-serif">`)
+	c.Assert(s, qt.Contains, `<p class="lead" style="margin:0 0 1em;font-size:18px">Lead</p>`)
+	c.Assert(s, qt.Contains, `<p class="note" style="margin:0 0 1em;padding:0;color:red !important">Note</p>`)
+	c.Assert(s, qt.Contains, `<a href="/" target="_blank" style="color:blue">Link</a><a href="/">Other</a>`)
+
+	// The rules that cannot be inlined are kept.
+	c.Assert(strings.Count(s, "<style>"), qt.Equals, 1)
+	c.Assert(s, qt.Contains, `<style>a:hover{color:green;}@media (max-width:600px){.lead{font-size:16px;}}</style>`)
+
+	// Documents without style elements are not touched.
+	plain := []byte(`<p>Hello</p>`)
+	out, err = Inline(plain)
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(out), qt.Equals, `<p>Hello</p>`)
+
+	// All inlined.
+	out, err = Inline([]byte(`<html><head><style>p{color:red}</style></head><body><p>Hi</p></body></html>`))
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(out), qt.Equals, `<html><head></head><body><p style="color:red">Hi</p></body></html>`)
+}
+
+func TestInlineCSSTransformer(t *testing.T) {
+	c := qt.New(t)
+
+	var out bytes.Buffer
+	tr := transform.New(InlineCSS)
+	c.Assert(tr.Apply(&out, strings.NewReader(`<style>.x{margin:0}</style><div class="x"></div>`)), qt.IsNil)
+	c.Assert(out.String(), qt.Equals, `<html><head></head><body><div class="x" style="margin:0"></div></body></html>`)
+}
+
+func TestParseSelector(t *testing.T) {
+	c := qt.New(t)
+
+	for _, test := range []struct {
+		in          string
+		ok          bool
+		specificity int
+	}{
+		{"p", true, 1},
+		{"*", true, 0},
+		{"div.a.b", true, 2<<8 | 1},
+		{"#main > p.note", true, 1<<16 | 1<<8 | 1},
+		{"td a[target='_blank']", true, 1<<8 | 2},
+		{"a:hover", false, 0},
+		{"p::before", false, 0},
+		{"h1 + p", false, 0},
+		{"[href^=http]", false, 0},
+		{"> p", false, 0},
+	} {
+		sel, ok := parseSelector(test.in)
+		c.Assert(ok, qt.Equals, test.ok, qt.Commentf(test.in))
+		if ok {
+			c.Assert(sel.specificity(), qt.Equals, test.specificity, qt.Commentf(test.in))
+		}
+	}
+
+	c.Assert(splitSelectors(`a, p[title="a,b"] , .c`), qt.DeepEquals, []string{"a", `p[title="a,b"]`, ".c"})
+}
+
+func TestToText(t *testing.T) {
+	c := qt.New(t)
+
+	text, err := ToText(`<h1>Weekly  news</h1>
+<p>Hello <strong>world</strong>, read <a href="https://example.org/post/">the post</a>.<br>Bye!</p>
+<ul>
+  <li>One</li>
+  <li>Two
+    <ol><li>A</li><li>B</li></ol>
+  </li>
+</ul>
+<img src="a.png" alt="A picture">
+<hr>
+<p><a href="https://example.org/">https://example.org/</a></p>
+<pre>line 1
+  line 2</pre>
+<style>p { color: red }</style>`)
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(text, qt.Equals, `Weekly news
+
+Hello world, read the post (https://example.org/post/).
+Bye!
+
+- One
+- Two
+  1. A
+  2. B
+
+A picture
+
+---
+
+https://example.org/
+
+line 1
+  line 2`)
+}
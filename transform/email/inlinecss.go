@@ -0,0 +1,348 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package email creates email safe HTML and plain text from HTML documents.
+package email
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+
+	"github.com/gohugoio/hugo/transform"
+	"github.com/tdewolff/parse/v2"
+	"github.com/tdewolff/parse/v2/css"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// InlineCSS is a transformer that inlines the CSS in the style elements
+// of a HTML document into style attributes. See Inline.
+func InlineCSS(ft transform.FromTo) error {
+	b, err := Inline(ft.From().Bytes())
+	if err != nil {
+		return err
+	}
+	_, err = ft.To().Write(b)
+	return err
+}
+
+// Inline moves the CSS rules in the style elements of the HTML document src
+// into the style attributes of the elements they match, as most email
+// clients ignore style elements.
+//
+// Type, class, ID and attribute selectors, combined with the descendant and
+// child combinators, are supported. Rules that cannot be inlined, e.g.
+// those with pseudo-classes or inside media queries, are kept in a style
+// element in the document head.
+func Inline(src []byte) ([]byte, error) {
+	doc, err := html.Parse(bytes.NewReader(src))
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		styles []*html.Node
+		sheet  stylesheet
+	)
+
+	walk(doc, func(n *html.Node) {
+		if n.DataAtom == atom.Style {
+			styles = append(styles, n)
+		}
+	})
+
+	if len(styles) == 0 {
+		return src, nil
+	}
+
+	for _, n := range styles {
+		var text strings.Builder
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			text.WriteString(c.Data)
+		}
+		sheet.parse(text.String())
+	}
+
+	if len(sheet.rules) > 0 {
+		walk(doc, func(n *html.Node) {
+			if n.Type == html.ElementNode {
+				sheet.apply(n)
+			}
+		})
+	}
+
+	// Keep the first style element if needed, and remove the others.
+	for i, n := range styles {
+		if i == 0 && sheet.kept.Len() > 0 {
+			for n.FirstChild != nil {
+				n.RemoveChild(n.FirstChild)
+			}
+			n.AppendChild(&html.Node{Type: html.TextNode, Data: sheet.kept.String()})
+			continue
+		}
+		n.Parent.RemoveChild(n)
+	}
+
+	var b bytes.Buffer
+	if err := html.Render(&b, doc); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+type declaration struct {
+	property  string
+	value     string
+	important bool
+}
+
+type rule struct {
+	selector     selector
+	specificity  int
+	order        int
+	declarations []declaration
+}
+
+type stylesheet struct {
+	rules []rule
+
+	// The CSS that cannot be inlined.
+	kept strings.Builder
+}
+
+func (s *stylesheet) parse(text string) {
+	p := css.NewParser(parse.NewInputString(text), false)
+
+	var (
+		depth int
+
+		// The selectors to inline and to keep in the current top level
+		// ruleset.
+		inline []selector
+		keep   []string
+		decls  []declaration
+
+		// The selectors before the last in a selector list.
+		qualified []string
+	)
+
+	for {
+		gt, _, data := p.Next()
+		switch gt {
+		case css.ErrorGrammar:
+			return
+		case css.AtRuleGrammar:
+			s.kept.Write(data)
+			writeTokens(&s.kept, p.Values(), true)
+			s.kept.WriteString(";")
+		case css.BeginAtRuleGrammar:
+			depth++
+			s.kept.Write(data)
+			writeTokens(&s.kept, p.Values(), true)
+			s.kept.WriteString("{")
+		case css.EndAtRuleGrammar:
+			depth--
+			s.kept.WriteString("}")
+		case css.QualifiedRuleGrammar:
+			var b strings.Builder
+			writeTokens(&b, p.Values(), false)
+			qualified = append(qualified, b.String())
+		case css.BeginRulesetGrammar:
+			var b strings.Builder
+			writeTokens(&b, p.Values(), false)
+			selectors := append(qualified, b.String())
+			qualified = nil
+			if depth > 0 {
+				s.kept.WriteString(strings.Join(selectors, ","))
+				s.kept.WriteString("{")
+				continue
+			}
+			inline, keep, decls = nil, nil, nil
+			for _, sel := range splitSelectors(strings.Join(selectors, ",")) {
+				if parsed, ok := parseSelector(sel); ok {
+					inline = append(inline, parsed)
+				} else {
+					keep = append(keep, sel)
+				}
+			}
+		case css.DeclarationGrammar, css.CustomPropertyGrammar:
+			d := newDeclaration(string(data), p.Values())
+			if depth > 0 {
+				s.kept.WriteString(d.String() + ";")
+				continue
+			}
+			decls = append(decls, d)
+		case css.EndRulesetGrammar:
+			if depth > 0 {
+				s.kept.WriteString("}")
+				continue
+			}
+			for _, sel := range inline {
+				s.rules = append(s.rules, rule{
+					selector:     sel,
+					specificity:  sel.specificity(),
+					order:        len(s.rules),
+					declarations: decls,
+				})
+			}
+			if len(keep) > 0 {
+				s.kept.WriteString(strings.Join(keep, ","))
+				s.kept.WriteString("{")
+				for _, d := range decls {
+					s.kept.WriteString(d.String() + ";")
+				}
+				s.kept.WriteString("}")
+			}
+		}
+	}
+}
+
+// apply sets the style attribute of n to the declarations of the rules
+// matching n, in cascading order, followed by any existing declarations.
+func (s *stylesheet) apply(n *html.Node) {
+	type applied struct {
+		declaration
+		specificity int
+		order       int
+	}
+
+	var decls []applied
+	for _, r := range s.rules {
+		if !r.selector.match(n) {
+			continue
+		}
+		for _, d := range r.declarations {
+			decls = append(decls, applied{declaration: d, specificity: r.specificity, order: r.order})
+		}
+	}
+
+	if len(decls) == 0 {
+		return
+	}
+
+	styleIdx := -1
+	for i, a := range n.Attr {
+		if a.Namespace == "" && a.Key == "style" {
+			styleIdx = i
+			for _, d := range parseDeclarations(a.Val) {
+				// Inline styles win over any rule without !important.
+				decls = append(decls, applied{declaration: d, specificity: 1 << 30, order: len(s.rules)})
+			}
+			break
+		}
+	}
+
+	sort.SliceStable(decls, func(i, j int) bool {
+		di, dj := decls[i], decls[j]
+		if di.important != dj.important {
+			return dj.important
+		}
+		if di.specificity != dj.specificity {
+			return di.specificity < dj.specificity
+		}
+		return di.order < dj.order
+	})
+
+	// The last declaration of a property wins, and its position
+	// is kept relative to any shorthand properties.
+	var resolved []declaration
+	for _, d := range decls {
+		for i, r := range resolved {
+			if r.property == d.property {
+				resolved = append(resolved[:i], resolved[i+1:]...)
+				break
+			}
+		}
+		resolved = append(resolved, d.declaration)
+	}
+
+	var style strings.Builder
+	for i, d := range resolved {
+		if i > 0 {
+			style.WriteString(";")
+		}
+		style.WriteString(d.String())
+	}
+
+	if styleIdx == -1 {
+		n.Attr = append(n.Attr, html.Attribute{Key: "style", Val: style.String()})
+	} else {
+		n.Attr[styleIdx].Val = style.String()
+	}
+}
+
+func newDeclaration(property string, values []css.Token) declaration {
+	d := declaration{property: strings.ToLower(property)}
+
+	// Strip any !important flag.
+	end := len(values)
+	for end > 0 && values[end-1].TokenType == css.WhitespaceToken {
+		end--
+	}
+	if end >= 2 && values[end-1].TokenType == css.IdentToken && strings.EqualFold(string(values[end-1].Data), "important") {
+		k := end - 2
+		for k > 0 && values[k].TokenType == css.WhitespaceToken {
+			k--
+		}
+		if values[k].TokenType == css.DelimToken && string(values[k].Data) == "!" {
+			d.important = true
+			end = k
+		}
+	}
+
+	var b strings.Builder
+	writeTokens(&b, values[:end], false)
+	d.value = strings.TrimSpace(b.String())
+
+	return d
+}
+
+func (d declaration) String() string {
+	if d.important {
+		return d.property + ":" + d.value + " !important"
+	}
+	return d.property + ":" + d.value
+}
+
+func parseDeclarations(style string) []declaration {
+	p := css.NewParser(parse.NewInputString(style), true)
+
+	var decls []declaration
+	for {
+		gt, _, data := p.Next()
+		switch gt {
+		case css.ErrorGrammar:
+			return decls
+		case css.DeclarationGrammar, css.CustomPropertyGrammar:
+			decls = append(decls, newDeclaration(string(data), p.Values()))
+		}
+	}
+}
+
+func writeTokens(b *strings.Builder, tokens []css.Token, leadingSpace bool) {
+	for i, t := range tokens {
+		if i == 0 && leadingSpace && t.TokenType != css.WhitespaceToken {
+			b.WriteString(" ")
+		}
+		b.Write(t.Data)
+	}
+}
+
+func walk(n *html.Node, fn func(n *html.Node)) {
+	fn(n)
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		walk(c, fn)
+	}
+}
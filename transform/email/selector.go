@@ -0,0 +1,278 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package email
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// selector is a complex selector, i.e. compound selectors joined by
+// combinators, in document order.
+type selector []compound
+
+type compound struct {
+	// The combinator relating this to the previous compound selector,
+	// either ' ' (descendant) or '>' (child).
+	combinator byte
+
+	tag     string
+	id      string
+	classes []string
+	attrs   []attrSelector
+}
+
+type attrSelector struct {
+	key string
+
+	// If set, the attribute must have this value.
+	value    string
+	hasValue bool
+}
+
+// splitSelectors splits a selector list on its top level commas.
+func splitSelectors(s string) []string {
+	var (
+		parts []string
+		depth int
+		start int
+		quote byte
+	)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '(' || c == '[':
+			depth++
+		case c == ')' || c == ']':
+			depth--
+		case c == ',' && depth == 0:
+			parts = append(parts, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+	}
+	return append(parts, strings.TrimSpace(s[start:]))
+}
+
+// parseSelector parses s, returning false if it contains anything but type,
+// class, ID and attribute selectors and descendant and child combinators.
+func parseSelector(s string) (selector, bool) {
+	var (
+		sel        selector
+		cur        *compound
+		combinator byte
+	)
+
+	next := func() {
+		if cur == nil {
+			sel = append(sel, compound{combinator: combinator})
+			cur = &sel[len(sel)-1]
+			combinator = 0
+		}
+	}
+
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f':
+			if cur != nil {
+				cur = nil
+				combinator = ' '
+			}
+			i++
+		case c == '>':
+			if len(sel) == 0 {
+				return nil, false
+			}
+			cur = nil
+			combinator = '>'
+			i++
+		case c == '*':
+			next()
+			i++
+		case c == '.' || c == '#':
+			next()
+			name, n := readIdent(s[i+1:])
+			if name == "" {
+				return nil, false
+			}
+			if c == '.' {
+				cur.classes = append(cur.classes, name)
+			} else {
+				cur.id = name
+			}
+			i += n + 1
+		case c == '[':
+			next()
+			end := strings.IndexByte(s[i:], ']')
+			if end == -1 {
+				return nil, false
+			}
+			a, ok := parseAttrSelector(s[i+1 : i+end])
+			if !ok {
+				return nil, false
+			}
+			cur.attrs = append(cur.attrs, a)
+			i += end + 1
+		case isIdentChar(c):
+			if cur != nil {
+				return nil, false
+			}
+			next()
+			name, n := readIdent(s[i:])
+			cur.tag = strings.ToLower(name)
+			i += n
+		default:
+			// Pseudo-classes, pseudo-elements and other combinators.
+			return nil, false
+		}
+	}
+
+	if len(sel) == 0 || cur == nil {
+		return nil, false
+	}
+
+	return sel, true
+}
+
+func parseAttrSelector(s string) (attrSelector, bool) {
+	eq := strings.IndexByte(s, '=')
+	if eq == -1 {
+		key := strings.TrimSpace(s)
+		return attrSelector{key: strings.ToLower(key)}, key != ""
+	}
+
+	key := strings.TrimSpace(s[:eq])
+	if key == "" || strings.ContainsAny(key, "~|^$*") {
+		// Only exact matches are supported.
+		return attrSelector{}, false
+	}
+
+	value := strings.TrimSpace(s[eq+1:])
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		value = value[1 : len(value)-1]
+	}
+
+	return attrSelector{key: strings.ToLower(key), value: value, hasValue: true}, true
+}
+
+func readIdent(s string) (string, int) {
+	i := 0
+	for i < len(s) && isIdentChar(s[i]) {
+		i++
+	}
+	return s[:i], i
+}
+
+func isIdentChar(c byte) bool {
+	return c == '-' || c == '_' || c >= 0x80 ||
+		c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// specificity returns the specificity of s as a single number.
+func (s selector) specificity() int {
+	var ids, classes, tags int
+	for _, c := range s {
+		if c.id != "" {
+			ids++
+		}
+		classes += len(c.classes) + len(c.attrs)
+		if c.tag != "" {
+			tags++
+		}
+	}
+	return ids<<16 | classes<<8 | tags
+}
+
+func (s selector) match(n *html.Node) bool {
+	return s.matchAt(len(s)-1, n)
+}
+
+func (s selector) matchAt(i int, n *html.Node) bool {
+	if !s[i].match(n) {
+		return false
+	}
+	if i == 0 {
+		return true
+	}
+
+	switch s[i].combinator {
+	case '>':
+		p := n.Parent
+		return p != nil && p.Type == html.ElementNode && s.matchAt(i-1, p)
+	default:
+		for p := n.Parent; p != nil && p.Type == html.ElementNode; p = p.Parent {
+			if s.matchAt(i-1, p) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func (c compound) match(n *html.Node) bool {
+	if n.Type != html.ElementNode {
+		return false
+	}
+	if c.tag != "" && c.tag != n.Data {
+		return false
+	}
+	if c.id != "" && attr(n, "id") != c.id {
+		return false
+	}
+	if len(c.classes) > 0 {
+		classes := strings.Fields(attr(n, "class"))
+		for _, class := range c.classes {
+			if !containsString(classes, class) {
+				return false
+			}
+		}
+	}
+	for _, a := range c.attrs {
+		v, found := lookupAttr(n, a.key)
+		if !found || a.hasValue && v != a.value {
+			return false
+		}
+	}
+	return true
+}
+
+func attr(n *html.Node, key string) string {
+	v, _ := lookupAttr(n, key)
+	return v
+}
+
+func lookupAttr(n *html.Node, key string) (string, bool) {
+	for _, a := range n.Attr {
+		if a.Namespace == "" && a.Key == key {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
+
+func containsString(s []string, v string) bool {
+	for _, vv := range s {
+		if vv == v {
+			return true
+		}
+	}
+	return false
+}
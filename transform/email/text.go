@@ -0,0 +1,208 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package email
+
+import (
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// ToText converts the HTML in s to readable plain text, e.g. for the plain
+// text alternative of an email. Paragraphs and other blocks are separated
+// by blank lines, list items are prefixed with a bullet or number and the
+// URLs of links are written after the link text.
+func ToText(s string) (string, error) {
+	nodes, err := html.ParseFragment(strings.NewReader(s), &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body})
+	if err != nil {
+		return "", err
+	}
+
+	w := &textWriter{}
+	for _, n := range nodes {
+		w.node(n)
+	}
+
+	lines := strings.Split(w.b.String(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+
+	return strings.TrimSpace(strings.Join(lines, "\n")), nil
+}
+
+type list struct {
+	ordered bool
+	n       int
+}
+
+type textWriter struct {
+	b strings.Builder
+
+	// The number of newlines to write before the next text.
+	newlines int
+	// Whether we're at the start of a line.
+	lineStart bool
+	// Whether to write a space before the next text.
+	space bool
+
+	pre   int
+	lists []list
+}
+
+func (w *textWriter) text(s string) {
+	var space bool
+	if w.pre == 0 {
+		words := strings.Fields(s)
+		if len(words) == 0 {
+			w.space = w.space || s != ""
+			return
+		}
+		space = w.space || strings.TrimLeft(s, " \t\r\n\f") != s
+		w.space = strings.TrimRight(s, " \t\r\n\f") != s
+		s = strings.Join(words, " ")
+	} else if s == "" {
+		return
+	}
+
+	if w.b.Len() > 0 && w.newlines > 0 {
+		if w.newlines > 2 {
+			w.newlines = 2
+		}
+		w.b.WriteString(strings.Repeat("\n", w.newlines))
+		w.lineStart = true
+	}
+	w.newlines = 0
+
+	if w.lineStart || w.b.Len() == 0 {
+		if len(w.lists) > 1 {
+			w.b.WriteString(strings.Repeat("  ", len(w.lists)-1))
+		}
+	} else if space {
+		w.b.WriteString(" ")
+	}
+
+	w.b.WriteString(s)
+	w.lineStart = strings.HasSuffix(s, "\n")
+}
+
+func (w *textWriter) block(newlines int) {
+	if newlines > w.newlines {
+		w.newlines = newlines
+	}
+	w.space = false
+}
+
+func (w *textWriter) children(n *html.Node) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		w.node(c)
+	}
+}
+
+func (w *textWriter) node(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		w.text(n.Data)
+		return
+	case html.ElementNode:
+	default:
+		w.children(n)
+		return
+	}
+
+	switch n.DataAtom {
+	case atom.Head, atom.Script, atom.Style, atom.Template, atom.Title:
+	case atom.Br:
+		w.b.WriteString("\n")
+		w.lineStart = true
+		w.space = false
+	case atom.Hr:
+		w.block(2)
+		w.text("---")
+		w.block(2)
+	case atom.Img:
+		if alt := strings.TrimSpace(attr(n, "alt")); alt != "" {
+			w.text(alt)
+		}
+	case atom.A:
+		text := strings.Join(strings.Fields(textContent(n)), " ")
+		href := strings.TrimSpace(attr(n, "href"))
+		w.children(n)
+		if href != "" && !strings.HasPrefix(href, "#") && href != text && strings.TrimPrefix(href, "mailto:") != text {
+			w.text(" (" + href + ")")
+		}
+	case atom.Pre:
+		w.block(2)
+		w.pre++
+		w.children(n)
+		w.pre--
+		w.block(2)
+	case atom.Ul, atom.Ol:
+		if len(w.lists) > 0 {
+			w.block(1)
+		} else {
+			w.block(2)
+		}
+		w.lists = append(w.lists, list{ordered: n.DataAtom == atom.Ol})
+		w.children(n)
+		w.lists = w.lists[:len(w.lists)-1]
+		if len(w.lists) > 0 {
+			w.block(1)
+		} else {
+			w.block(2)
+		}
+	case atom.Li:
+		w.block(1)
+		prefix := "- "
+		if len(w.lists) > 0 {
+			l := &w.lists[len(w.lists)-1]
+			l.n++
+			if l.ordered {
+				prefix = strconv.Itoa(l.n) + ". "
+			}
+		}
+		w.text(prefix)
+		w.space = true
+		w.children(n)
+		w.block(1)
+	case atom.Td, atom.Th:
+		w.space = true
+		w.children(n)
+		w.space = true
+	case atom.P, atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6,
+		atom.Blockquote, atom.Table, atom.Dl, atom.Figure:
+		w.block(2)
+		w.children(n)
+		w.block(2)
+	case atom.Div, atom.Section, atom.Article, atom.Header, atom.Footer,
+		atom.Nav, atom.Aside, atom.Main, atom.Tr, atom.Dt, atom.Dd, atom.Figcaption:
+		w.block(1)
+		w.children(n)
+		w.block(1)
+	default:
+		w.children(n)
+	}
+}
+
+func textContent(n *html.Node) string {
+	var b strings.Builder
+	walk(n, func(n *html.Node) {
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+		}
+	})
+	return b.String()
+}
@@ -0,0 +1,76 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lite
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/tdewolff/parse/v2"
+	"github.com/tdewolff/parse/v2/css"
+)
+
+// rewriteCSSURLs resolves the relative URLs in the stylesheet s, published
+// at the URL path sheetPath, so they keep working when it is inlined.
+func rewriteCSSURLs(s, sheetPath string) string {
+	base := &url.URL{Path: sheetPath}
+
+	var (
+		b        strings.Builder
+		isImport bool
+	)
+
+	l := css.NewLexer(parse.NewInputString(s))
+	for {
+		tt, data := l.Next()
+		switch tt {
+		case css.ErrorToken:
+			return b.String()
+		case css.AtKeywordToken:
+			isImport = strings.EqualFold(string(data), "@import")
+			b.Write(data)
+		case css.URLToken:
+			isImport = false
+			v := strings.TrimSpace(string(data[len("url(") : len(data)-1]))
+			if len(v) >= 2 && (v[0] == '"' || v[0] == '\'') && v[len(v)-1] == v[0] {
+				v = v[1 : len(v)-1]
+			}
+			b.WriteString("url(" + strconv.Quote(resolveURL(base, v)) + ")")
+		case css.StringToken:
+			if isImport {
+				isImport = false
+				b.WriteString(strconv.Quote(resolveURL(base, string(data[1:len(data)-1]))))
+				continue
+			}
+			b.Write(data)
+		case css.WhitespaceToken, css.CommentToken:
+			b.Write(data)
+		default:
+			isImport = false
+			b.Write(data)
+		}
+	}
+}
+
+func resolveURL(base *url.URL, s string) string {
+	if s == "" || strings.HasPrefix(s, "/") || strings.HasPrefix(s, "#") {
+		return s
+	}
+	u, err := url.Parse(s)
+	if err != nil || u.IsAbs() {
+		return s
+	}
+	return base.ResolveReference(u).String()
+}
@@ -0,0 +1,383 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package lite creates lightweight variants of HTML pages, with scripts
+// and embeds removed and the site's stylesheets inlined.
+package lite
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/gohugoio/hugo/common/maps"
+	"github.com/gohugoio/hugo/config"
+	"github.com/gohugoio/hugo/transform"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+const liteConfigKey = "lite"
+
+// Config configures the output formats with IsLite set.
+type Config struct {
+	// The elements to remove, e.g. "script". When scripts are removed,
+	// the content of noscript elements is kept, and event handler
+	// attributes are removed.
+	Remove []string
+
+	// The elements to replace with a link to their source, e.g. "iframe".
+	// Elements without a source are removed.
+	Placeholders []string
+
+	// The class attribute of the placeholder links.
+	PlaceholderClass string
+
+	// Whether to inline the stylesheets published by the site into
+	// style elements.
+	InlineCSS bool
+
+	// The max size of a page, e.g. "30KB". No limit if not set.
+	MaxSize string
+
+	// If set, pages larger than MaxSize fail the build. Otherwise
+	// a warning is logged.
+	FailOnMaxSize bool
+
+	maxSize uint64
+}
+
+var defaultConfig = Config{
+	Remove:           []string{"script"},
+	Placeholders:     []string{"iframe", "video", "audio", "object", "embed"},
+	PlaceholderClass: "lite-placeholder",
+	InlineCSS:        true,
+}
+
+// DecodeConfig creates a lite Config from the given configuration.
+func DecodeConfig(cfg config.Provider) (conf Config, err error) {
+	conf = defaultConfig
+
+	v := cfg.Get(liteConfigKey)
+	if v == nil {
+		return
+	}
+
+	// Configured element lists replace the defaults.
+	conf.Remove, conf.Placeholders = nil, nil
+
+	if err = mapstructure.WeakDecode(maps.ToStringMap(v), &conf); err != nil {
+		return
+	}
+
+	if conf.Remove == nil {
+		conf.Remove = defaultConfig.Remove
+	}
+	if conf.Placeholders == nil {
+		conf.Placeholders = defaultConfig.Placeholders
+	}
+	conf.Remove = toLower(conf.Remove)
+	conf.Placeholders = toLower(conf.Placeholders)
+
+	if conf.MaxSize != "" {
+		conf.maxSize, err = humanize.ParseBytes(conf.MaxSize)
+		if err != nil {
+			err = errors.Wrapf(err, "failed to parse %s.maxSize", liteConfigKey)
+		}
+	}
+
+	return
+}
+
+// Lite creates the lightweight variants of pages.
+type Lite struct {
+	conf Config
+
+	remove       map[string]bool
+	placeholders map[string]bool
+
+	baseURL  *url.URL
+	basePath string
+
+	// Reads a file published by the site, e.g. a stylesheet.
+	readFile func(filename string) ([]byte, error)
+}
+
+// New creates a new Lite for the site with the given baseURL. readFile
+// reads a file in the publish dir, given as a slash separated path
+// relative to it.
+func New(conf Config, baseURL string, readFile func(filename string) ([]byte, error)) *Lite {
+	l := &Lite{
+		conf:         conf,
+		remove:       make(map[string]bool),
+		placeholders: make(map[string]bool),
+		basePath:     "/",
+		readFile:     readFile,
+	}
+
+	for _, name := range conf.Remove {
+		l.remove[name] = true
+	}
+	for _, name := range conf.Placeholders {
+		l.placeholders[name] = true
+	}
+
+	if u, err := url.Parse(baseURL); err == nil {
+		l.baseURL = u
+		if u.Path != "" {
+			l.basePath = strings.TrimSuffix(u.Path, "/") + "/"
+		}
+	}
+
+	return l
+}
+
+// Transformer returns the transformer for the page published to targetPath.
+func (l *Lite) Transformer(targetPath string) transform.Transformer {
+	return func(ft transform.FromTo) error {
+		b, err := l.Apply(ft.From().Bytes(), targetPath)
+		if err != nil {
+			return err
+		}
+		_, err = ft.To().Write(b)
+		return err
+	}
+}
+
+// Apply creates the lightweight variant of the HTML document src, published
+// to targetPath.
+func (l *Lite) Apply(src []byte, targetPath string) ([]byte, error) {
+	// With scripting disabled, the content of noscript elements is parsed
+	// as HTML, so we can keep it.
+	doc, err := html.ParseWithOptions(bytes.NewReader(src), html.ParseOptionEnableScripting(!l.remove["script"]))
+	if err != nil {
+		return nil, err
+	}
+
+	l.process(doc, path.Dir(strings.ReplaceAll(targetPath, "\\", "/")))
+
+	var b bytes.Buffer
+	if err := html.Render(&b, doc); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// CheckSize returns an error if size exceeds the configured max size of
+// the page published to targetPath.
+func (l *Lite) CheckSize(targetPath string, size int) error {
+	if l.conf.maxSize == 0 || uint64(size) <= l.conf.maxSize {
+		return nil
+	}
+	return fmt.Errorf("%s is %s, which exceeds the max size of %s", targetPath, humanize.Bytes(uint64(size)), humanize.Bytes(l.conf.maxSize))
+}
+
+// FailOnMaxSize reports whether pages exceeding the max size should fail
+// the build.
+func (l *Lite) FailOnMaxSize() bool {
+	return l.conf.FailOnMaxSize
+}
+
+func (l *Lite) process(n *html.Node, dir string) {
+	removeScripts := l.remove["script"]
+
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+
+		if c.Type != html.ElementNode {
+			c = next
+			continue
+		}
+
+		switch {
+		case l.remove[c.Data] && !isData(c):
+			n.RemoveChild(c)
+		case l.placeholders[c.Data]:
+			if p := l.placeholder(c); p != nil {
+				n.InsertBefore(p, c)
+			}
+			n.RemoveChild(c)
+		case c.DataAtom == atom.Noscript && removeScripts:
+			l.process(c, dir)
+			for gc := c.FirstChild; gc != nil; gc = c.FirstChild {
+				c.RemoveChild(gc)
+				n.InsertBefore(gc, c)
+			}
+			n.RemoveChild(c)
+		case c.DataAtom == atom.Link && removeScripts && isScriptPreload(c):
+			n.RemoveChild(c)
+		case c.DataAtom == atom.Link && l.conf.InlineCSS && hasRel(c, "stylesheet"):
+			if style := l.inlineStylesheet(c, dir); style != nil {
+				n.InsertBefore(style, c)
+				n.RemoveChild(c)
+			}
+		default:
+			if removeScripts {
+				removeEventHandlers(c)
+			}
+			l.process(c, dir)
+		}
+
+		c = next
+	}
+}
+
+// placeholder creates a link to the source of n, nil if it has none.
+func (l *Lite) placeholder(n *html.Node) *html.Node {
+	src := attr(n, "src")
+	if src == "" {
+		src = attr(n, "data")
+	}
+	if src == "" {
+		// E.g. the source elements of video and audio.
+		for c := n.FirstChild; c != nil && src == ""; c = c.NextSibling {
+			if c.DataAtom == atom.Source {
+				src = attr(c, "src")
+			}
+		}
+	}
+	if src == "" {
+		return nil
+	}
+
+	text := src
+	for _, key := range []string{"title", "aria-label", "alt"} {
+		if v := strings.TrimSpace(attr(n, key)); v != "" {
+			text = v
+			break
+		}
+	}
+
+	a := &html.Node{
+		Type:     html.ElementNode,
+		Data:     "a",
+		DataAtom: atom.A,
+		Attr: []html.Attribute{
+			{Key: "href", Val: src},
+			{Key: "class", Val: l.conf.PlaceholderClass},
+			{Key: "data-lite-element", Val: n.Data},
+		},
+	}
+	a.AppendChild(&html.Node{Type: html.TextNode, Data: text})
+
+	return a
+}
+
+// inlineStylesheet creates a style element with the content of the
+// stylesheet linked by n, nil if it is not published by the site.
+func (l *Lite) inlineStylesheet(n *html.Node, dir string) *html.Node {
+	filename, ok := l.resolve(attr(n, "href"), dir)
+	if !ok {
+		return nil
+	}
+
+	b, err := l.readFile(filename)
+	if err != nil {
+		return nil
+	}
+
+	css := rewriteCSSURLs(string(b), path.Join(l.basePath, filename))
+	if media := strings.TrimSpace(attr(n, "media")); media != "" && media != "all" {
+		css = "@media " + media + "{" + css + "}"
+	}
+
+	style := &html.Node{Type: html.ElementNode, Data: "style", DataAtom: atom.Style}
+	style.AppendChild(&html.Node{Type: html.TextNode, Data: css})
+
+	return style
+}
+
+// resolve resolves href in the page in dir to a path relative to the
+// publish dir, returning false if it is not on this site.
+func (l *Lite) resolve(href, dir string) (string, bool) {
+	u, err := url.Parse(strings.TrimSpace(href))
+	if err != nil || u.Path == "" {
+		return "", false
+	}
+
+	var p string
+	switch {
+	case u.Scheme != "" || u.Host != "":
+		if l.baseURL == nil || !strings.EqualFold(u.Host, l.baseURL.Host) {
+			return "", false
+		}
+		p = u.Path
+	case strings.HasPrefix(u.Path, "/"):
+		p = u.Path
+	default:
+		p = path.Join(l.basePath, dir, u.Path)
+	}
+
+	if !strings.HasPrefix(p, l.basePath) {
+		return "", false
+	}
+
+	return path.Clean(strings.TrimPrefix(p, l.basePath)), true
+}
+
+// isData reports whether n is a script element with data, e.g. JSON-LD,
+// which we keep.
+func isData(n *html.Node) bool {
+	if n.DataAtom != atom.Script {
+		return false
+	}
+	typ := strings.ToLower(strings.TrimSpace(attr(n, "type")))
+	return typ == "application/ld+json" || typ == "application/json"
+}
+
+func isScriptPreload(n *html.Node) bool {
+	return hasRel(n, "modulepreload") || hasRel(n, "preload") && strings.EqualFold(attr(n, "as"), "script")
+}
+
+func removeEventHandlers(n *html.Node) {
+	attrs := n.Attr[:0]
+	for _, a := range n.Attr {
+		if a.Namespace == "" && strings.HasPrefix(strings.ToLower(a.Key), "on") {
+			continue
+		}
+		attrs = append(attrs, a)
+	}
+	n.Attr = attrs
+}
+
+func hasRel(n *html.Node, rel string) bool {
+	for _, v := range strings.Fields(attr(n, "rel")) {
+		if strings.EqualFold(v, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+func toLower(s []string) []string {
+	lower := make([]string, len(s))
+	for i, v := range s {
+		lower[i] = strings.ToLower(v)
+	}
+	return lower
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Namespace == "" && a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
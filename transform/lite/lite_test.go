@@ -0,0 +1,119 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lite
+
+import (
+	"os"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gohugoio/hugo/config"
+)
+
+func TestDecodeConfig(t *testing.T) {
+	c := qt.New(t)
+
+	v := config.New()
+	conf, err := DecodeConfig(v)
+	c.Assert(err, qt.IsNil)
+	c.Assert(conf.Remove, qt.DeepEquals, []string{"script"})
+	c.Assert(conf.InlineCSS, qt.Equals, true)
+	c.Assert(conf.maxSize, qt.Equals, uint64(0))
+
+	v.Set("lite", map[string]interface{}{
+		"remove":       []string{"Script", "form"},
+		"placeholders": []string{"IFRAME"},
+		"inlineCSS":    false,
+		"maxSize":      "30KB",
+	})
+	conf, err = DecodeConfig(v)
+	c.Assert(err, qt.IsNil)
+	c.Assert(conf.Remove, qt.DeepEquals, []string{"script", "form"})
+	c.Assert(conf.Placeholders, qt.DeepEquals, []string{"iframe"})
+	c.Assert(conf.InlineCSS, qt.Equals, false)
+	c.Assert(conf.maxSize, qt.Equals, uint64(30000))
+
+	v.Set("lite", map[string]interface{}{"maxSize": "lots"})
+	_, err = DecodeConfig(v)
+	c.Assert(err, qt.Not(qt.IsNil))
+}
+
+func TestApply(t *testing.T) {
+	c := qt.New(t)
+
+	files := map[string]string{
+		"css/main.css":  `body { background: url(../images/bg.png) } @import "fonts.css";`,
+		"css/print.css": `body { color: black }`,
+	}
+	readFile := func(filename string) ([]byte, error) {
+		if s, ok := files[filename]; ok {
+			return []byte(s), nil
+		}
+		return nil, os.ErrNotExist
+	}
+
+	l := New(defaultConfig, "https://example.org/docs/", readFile)
+
+	out, err := l.Apply([]byte(`<!DOCTYPE html>
+<html><head>
+<link rel="stylesheet" href="https://example.org/docs/css/main.css">
+<link rel="stylesheet" href="../../../css/print.css" media="print">
+<link rel="stylesheet" href="https://cdn.example.com/lib.css">
+<link rel="stylesheet" href="/docs/css/missing.css">
+<link rel="preload" href="/docs/js/app.js" as="script">
+<script src="/docs/js/app.js"></script>
+<script type="application/ld+json">{"@type":"Article"}</script>
+</head>
+<body onload="init()">
+<h1>Title</h1>
+<iframe src="https://www.youtube.com/embed/abc" title="My video"></iframe>
+<video controls><source src="/docs/video.mp4" type="video/mp4"></video>
+<embed type="image/svg+xml">
+<noscript><img src="/docs/images/fallback.png"></noscript>
+<script>document.write("Hi")</script>
+</body></html>`), "/posts/first/lite/index.html")
+	c.Assert(err, qt.IsNil)
+
+	c.Assert(string(out), qt.Equals, `<!DOCTYPE html><html><head>
+<style>body { background: url("/docs/images/bg.png") } @import "/docs/css/fonts.css";</style>
+<style>@media print{body { color: black }}</style>
+<link rel="stylesheet" href="https://cdn.example.com/lib.css"/>
+<link rel="stylesheet" href="/docs/css/missing.css"/>
+
+
+<script type="application/ld+json">{"@type":"Article"}</script>
+</head>
+<body>
+<h1>Title</h1>
+<a href="https://www.youtube.com/embed/abc" class="lite-placeholder" data-lite-element="iframe">My video</a>
+<a href="/docs/video.mp4" class="lite-placeholder" data-lite-element="video">/docs/video.mp4</a>
+
+<img src="/docs/images/fallback.png"/>
+
+</body></html>`)
+}
+
+func TestCheckSize(t *testing.T) {
+	c := qt.New(t)
+
+	conf := defaultConfig
+	conf.maxSize = 100
+	l := New(conf, "https://example.org/", nil)
+
+	c.Assert(l.CheckSize("index.html", 100), qt.IsNil)
+	c.Assert(l.CheckSize("index.html", 101), qt.ErrorMatches, `index.html is 101 B, which exceeds the max size of 100 B`)
+
+	l = New(defaultConfig, "https://example.org/", nil)
+	c.Assert(l.CheckSize("index.html", 1<<30), qt.IsNil)
+}
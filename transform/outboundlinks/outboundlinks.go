@@ -0,0 +1,203 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package outboundlinks rewrites the outbound links in the published HTML
+// to a redirect prefix, e.g. for click measurement on the server.
+package outboundlinks
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/gobwas/glob"
+	"github.com/gohugoio/hugo/common/maps"
+	"github.com/gohugoio/hugo/config"
+	"github.com/gohugoio/hugo/helpers"
+	hglob "github.com/gohugoio/hugo/hugofs/glob"
+	"github.com/gohugoio/hugo/transform"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+)
+
+const outboundLinksConfigKey = "outboundLinks"
+
+// Config configures the rewriting of outbound links.
+type Config struct {
+	// The prefix to rewrite the outbound links to, e.g. "/go/". A link is
+	// rewritten to the prefix followed by a short ID of its URL.
+	// Rewriting is disabled if not set.
+	Prefix string
+
+	// Glob patterns for the links to rewrite. A pattern is matched against
+	// the host, e.g. "*.example.com", or if it contains a "/", against the
+	// host and path, e.g. "example.com/products/**". All outbound links
+	// are rewritten if not set.
+	Includes []string
+
+	// Glob patterns, as in Includes, for the links not to rewrite.
+	Excludes []string
+
+	// The file in the publish dir to write the mapping from the rewritten
+	// paths to the original URLs to, as JSON.
+	MappingFile string
+}
+
+var defaultConfig = Config{
+	MappingFile: "outbound_links.json",
+}
+
+// DecodeConfig creates an outbound links Config from the given configuration.
+func DecodeConfig(cfg config.Provider) (conf Config, err error) {
+	conf = defaultConfig
+
+	v := cfg.Get(outboundLinksConfigKey)
+	if v == nil {
+		return
+	}
+
+	if err = mapstructure.WeakDecode(maps.ToStringMap(v), &conf); err != nil {
+		return
+	}
+
+	if conf.Prefix != "" && !strings.HasSuffix(conf.Prefix, "/") {
+		conf.Prefix += "/"
+	}
+
+	return
+}
+
+// Enabled returns whether outbound links should be rewritten.
+func (c Config) Enabled() bool {
+	return c.Prefix != ""
+}
+
+var linkRe = regexp.MustCompile(`(?i)(<a\s[^>]*?\bhref\s*=\s*)(["'])(https?://[^"'\s>]+)(["'])`)
+
+// Rewriter rewrites the outbound links in HTML and keeps track of the
+// links rewritten.
+type Rewriter struct {
+	cfg      Config
+	siteHost string
+
+	includes []glob.Glob
+	excludes []glob.Glob
+
+	mu    sync.Mutex
+	links map[string]string
+}
+
+// New creates a new Rewriter. Links to siteHost are never rewritten.
+func New(cfg Config, siteHost string) (*Rewriter, error) {
+	r := &Rewriter{
+		cfg:      cfg,
+		siteHost: strings.ToLower(siteHost),
+		links:    make(map[string]string),
+	}
+
+	compile := func(patterns []string) ([]glob.Glob, error) {
+		var globs []glob.Glob
+		for _, pattern := range patterns {
+			g, err := hglob.GetGlob(pattern)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid outbound links pattern %q", pattern)
+			}
+			globs = append(globs, g)
+		}
+		return globs, nil
+	}
+
+	var err error
+	if r.includes, err = compile(cfg.Includes); err != nil {
+		return nil, err
+	}
+	if r.excludes, err = compile(cfg.Excludes); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Transform rewrites the outbound links in the HTML in ft.
+func (r *Rewriter) Transform(ft transform.FromTo) error {
+	b := linkRe.ReplaceAllFunc(ft.From().Bytes(), func(m []byte) []byte {
+		sm := linkRe.FindSubmatch(m)
+		if string(sm[2]) != string(sm[4]) {
+			return m
+		}
+
+		link := html.UnescapeString(string(sm[3]))
+		u, err := url.Parse(link)
+		if err != nil || !r.shouldRewrite(u) {
+			return m
+		}
+
+		p := r.cfg.Prefix + helpers.MD5String(link)[:10]
+
+		r.mu.Lock()
+		r.links[p] = link
+		r.mu.Unlock()
+
+		return []byte(string(sm[1]) + string(sm[2]) + p + string(sm[4]))
+	})
+
+	_, err := ft.To().Write(b)
+	return err
+}
+
+func (r *Rewriter) shouldRewrite(u *url.URL) bool {
+	host := strings.ToLower(u.Hostname())
+	if host == "" || host == r.siteHost {
+		return false
+	}
+
+	path := u.Path
+	if path == "" {
+		path = "/"
+	}
+
+	match := func(globs []glob.Glob, patterns []string) bool {
+		for i, g := range globs {
+			s := host
+			if strings.Contains(patterns[i], "/") {
+				s = host + path
+			}
+			if g.Match(s) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if r.includes != nil && !match(r.includes, r.cfg.Includes) {
+		return false
+	}
+
+	return !match(r.excludes, r.cfg.Excludes)
+}
+
+// Links returns a copy of the rewritten links, mapping the rewritten paths
+// to the original URLs.
+func (r *Rewriter) Links() map[string]string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	links := make(map[string]string, len(r.links))
+	for k, v := range r.links {
+		links[k] = v
+	}
+
+	return links
+}
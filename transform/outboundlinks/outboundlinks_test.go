@@ -0,0 +1,100 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package outboundlinks
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gohugoio/hugo/config"
+	"github.com/gohugoio/hugo/helpers"
+	"github.com/gohugoio/hugo/transform"
+)
+
+func TestDecodeConfig(t *testing.T) {
+	c := qt.New(t)
+
+	cfg := config.New()
+	conf, err := DecodeConfig(cfg)
+	c.Assert(err, qt.IsNil)
+	c.Assert(conf.Enabled(), qt.Equals, false)
+	c.Assert(conf.MappingFile, qt.Equals, "outbound_links.json")
+
+	cfg.Set("outboundLinks", map[string]interface{}{
+		"prefix":   "/go",
+		"excludes": []interface{}{"*.example.org"},
+	})
+	conf, err = DecodeConfig(cfg)
+	c.Assert(err, qt.IsNil)
+	c.Assert(conf.Enabled(), qt.Equals, true)
+	c.Assert(conf.Prefix, qt.Equals, "/go/")
+	c.Assert(conf.Excludes, qt.DeepEquals, []string{"*.example.org"})
+}
+
+func TestRewriter(t *testing.T) {
+	c := qt.New(t)
+
+	id := func(s string) string {
+		return "/go/" + helpers.MD5String(s)[:10]
+	}
+
+	for _, test := range []struct {
+		name     string
+		includes []string
+		excludes []string
+		in       string
+		expect   string
+	}{
+		{"Outbound", nil, nil, `<a href="https://example.org/a">`, `<a href="` + id("https://example.org/a") + `">`},
+		{"Single quotes", nil, nil, `<a class="x" href='http://example.org/'>`, `<a class="x" href='` + id("http://example.org/") + `'>`},
+		{"Escaped", nil, nil, `<a href="https://example.org/?a=1&amp;b=2">`, `<a href="` + id("https://example.org/?a=1&b=2") + `">`},
+		{"Site host", nil, nil, `<a href="https://mysite.com/a">`, `<a href="https://mysite.com/a">`},
+		{"Relative", nil, nil, `<a href="/a">`, `<a href="/a">`},
+		{"Not a link", nil, nil, `<link href="https://example.org/a.css">`, `<link href="https://example.org/a.css">`},
+		{"Included host", []string{"*.example.org"}, nil, `<a href="https://www.example.org/a">`, `<a href="` + id("https://www.example.org/a") + `">`},
+		{"Not included", []string{"*.example.org"}, nil, `<a href="https://example.com/a">`, `<a href="https://example.com/a">`},
+		{"Included path", []string{"example.org/products/**"}, nil, `<a href="https://example.org/products/b">`, `<a href="` + id("https://example.org/products/b") + `">`},
+		{"Not included path", []string{"example.org/products/**"}, nil, `<a href="https://example.org/about">`, `<a href="https://example.org/about">`},
+		{"Excluded", nil, []string{"example.org"}, `<a href="https://EXAMPLE.org/a">`, `<a href="https://EXAMPLE.org/a">`},
+	} {
+		c.Run(test.name, func(c *qt.C) {
+			r, err := New(Config{Prefix: "/go/", Includes: test.includes, Excludes: test.excludes}, "mysite.com")
+			c.Assert(err, qt.IsNil)
+
+			out := new(bytes.Buffer)
+			tr := transform.New(r.Transform)
+			c.Assert(tr.Apply(out, strings.NewReader(test.in)), qt.IsNil)
+			c.Assert(out.String(), qt.Equals, test.expect)
+		})
+	}
+}
+
+func TestRewriterLinks(t *testing.T) {
+	c := qt.New(t)
+
+	r, err := New(Config{Prefix: "/go/"}, "mysite.com")
+	c.Assert(err, qt.IsNil)
+
+	out := new(bytes.Buffer)
+	in := `<a href="https://example.org/a">A</a><a href="https://example.org/a">A</a><a href="https://example.com/">B</a>`
+	tr := transform.New(r.Transform)
+	c.Assert(tr.Apply(out, strings.NewReader(in)), qt.IsNil)
+
+	c.Assert(r.Links(), qt.DeepEquals, map[string]string{
+		"/go/" + helpers.MD5String("https://example.org/a")[:10]: "https://example.org/a",
+		"/go/" + helpers.MD5String("https://example.com/")[:10]:  "https://example.com/",
+	})
+}
@@ -0,0 +1,404 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package printdoc prepares HTML documents combining several pages, e.g.
+// all pages in a section, for printing.
+package printdoc
+
+import (
+	"bytes"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gohugoio/hugo/transform"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+const (
+	// The attributes marking the pages, or chapters, in the document, and
+	// the URL of the page each chapter was created from.
+	chapterAttr    = "data-print-chapter"
+	chapterURLAttr = "data-print-url"
+
+	// Set on the body element to number the headings.
+	numberHeadingsAttr = "data-print-number-headings"
+
+	// Set on the element to replace with the table of contents. Its value
+	// is the max heading level to include, default 3.
+	tocAttr = "data-print-toc"
+
+	defaultTOCDepth = 3
+)
+
+// Transform is a transformer that prepares a HTML document for print. See
+// Prepare.
+func Transform(ft transform.FromTo) error {
+	b, err := Prepare(ft.From().Bytes())
+	if err != nil {
+		return err
+	}
+	_, err = ft.To().Write(b)
+	return err
+}
+
+// Prepare prepares the HTML document src for print.
+//
+// The elements with a data-print-chapter attribute are the chapters of the
+// document, usually one per page. The IDs in each chapter, e.g. those of
+// footnotes, are prefixed with the chapter's name so they do not clash with
+// those of other chapters, and the links to them are updated. Links to the
+// page in a chapter's data-print-url attribute are changed to link to the
+// chapter.
+//
+// The headings in the chapters are numbered if the body element has a
+// data-print-number-headings attribute. An element with a data-print-toc
+// attribute is filled with a table of contents, with the headings up to
+// the level in the attribute's value.
+func Prepare(src []byte) ([]byte, error) {
+	doc, err := html.Parse(bytes.NewReader(src))
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		chapters []*html.Node
+		body     *html.Node
+		toc      *html.Node
+	)
+
+	walk(doc, func(n *html.Node) bool {
+		if n.Type != html.ElementNode {
+			return true
+		}
+		if n.DataAtom == atom.Body {
+			body = n
+		}
+		if _, found := lookupAttr(n, tocAttr); found && toc == nil {
+			toc = n
+		}
+		if _, found := lookupAttr(n, chapterAttr); found {
+			chapters = append(chapters, n)
+			// Nested chapters are not supported.
+			return false
+		}
+		return true
+	})
+
+	if len(chapters) == 0 {
+		return src, nil
+	}
+
+	// The chapter for each page URL.
+	urls := make(map[string]string)
+
+	for i, ch := range chapters {
+		name := attr(ch, chapterAttr)
+		if name == "" {
+			name = "chapter-" + strconv.Itoa(i+1)
+		}
+		prefixIDs(ch, name)
+		setAttr(ch, "id", name)
+		if u := attr(ch, chapterURLAttr); u != "" {
+			urls[pageKey(u)] = name
+		}
+		removeAttr(ch, chapterAttr)
+		removeAttr(ch, chapterURLAttr)
+	}
+
+	resolveLinks(doc, urls)
+
+	var headings []heading
+	for _, ch := range chapters {
+		headings = append(headings, collectHeadings(ch, attr(ch, "id"))...)
+	}
+
+	if body != nil {
+		if _, found := lookupAttr(body, numberHeadingsAttr); found {
+			numberHeadings(headings)
+			removeAttr(body, numberHeadingsAttr)
+		}
+	}
+
+	if toc != nil {
+		depth := defaultTOCDepth
+		if v, err := strconv.Atoi(attr(toc, tocAttr)); err == nil && v > 0 {
+			depth = v
+		}
+		buildTOC(toc, headings, depth)
+		removeAttr(toc, tocAttr)
+	}
+
+	var b bytes.Buffer
+	if err := html.Render(&b, doc); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// prefixIDs prefixes the IDs in the chapter ch, and the fragment links to
+// them, with name.
+func prefixIDs(ch *html.Node, name string) {
+	ids := make(map[string]bool)
+	walk(ch, func(n *html.Node) bool {
+		if n.Type == html.ElementNode && n != ch {
+			if id := attr(n, "id"); id != "" {
+				ids[id] = true
+				setAttr(n, "id", name+"-"+id)
+			}
+		}
+		return true
+	})
+
+	walk(ch, func(n *html.Node) bool {
+		if n.DataAtom != atom.A {
+			return true
+		}
+		href := attr(n, "href")
+		if strings.HasPrefix(href, "#") && ids[href[1:]] {
+			setAttr(n, "href", "#"+name+"-"+href[1:])
+		}
+		return true
+	})
+}
+
+// resolveLinks changes the links to the pages in the document to link
+// to their chapters.
+func resolveLinks(doc *html.Node, urls map[string]string) {
+	walk(doc, func(n *html.Node) bool {
+		if n.DataAtom != atom.A {
+			return true
+		}
+		href := attr(n, "href")
+		if href == "" || strings.HasPrefix(href, "#") {
+			return true
+		}
+		u, err := url.Parse(href)
+		if err != nil {
+			return true
+		}
+		name, found := urls[pageKey(href)]
+		if !found {
+			return true
+		}
+		if u.Fragment != "" {
+			setAttr(n, "href", "#"+name+"-"+u.Fragment)
+		} else {
+			setAttr(n, "href", "#"+name)
+		}
+		return true
+	})
+}
+
+// pageKey returns the path of the page URL s, so relative and absolute
+// URLs to the same page match.
+func pageKey(s string) string {
+	u, err := url.Parse(s)
+	if err != nil {
+		return s
+	}
+	p := u.Path
+	if p == "" {
+		return s
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(p, "index.html"), "/")
+}
+
+type heading struct {
+	node  *html.Node
+	level int
+}
+
+// collectHeadings collects the headings in the chapter ch, giving those
+// without an ID one.
+func collectHeadings(ch *html.Node, name string) []heading {
+	var headings []heading
+	walk(ch, func(n *html.Node) bool {
+		level := headingLevel(n)
+		if level == 0 {
+			return true
+		}
+		if attr(n, "id") == "" {
+			setAttr(n, "id", name+"-h"+strconv.Itoa(len(headings)+1))
+		}
+		headings = append(headings, heading{node: n, level: level})
+		return false
+	})
+	return headings
+}
+
+func numberHeadings(headings []heading) {
+	var counters [6]int
+	for _, h := range headings {
+		counters[h.level-1]++
+		for i := h.level; i < len(counters); i++ {
+			counters[i] = 0
+		}
+
+		parts := make([]string, h.level)
+		for i := 0; i < h.level; i++ {
+			parts[i] = strconv.Itoa(counters[i])
+		}
+
+		span := &html.Node{
+			Type:     html.ElementNode,
+			Data:     "span",
+			DataAtom: atom.Span,
+			Attr:     []html.Attribute{{Key: "class", Val: "print-number"}},
+		}
+		span.AppendChild(&html.Node{Type: html.TextNode, Data: strings.Join(parts, ".")})
+		h.node.InsertBefore(&html.Node{Type: html.TextNode, Data: " "}, h.node.FirstChild)
+		h.node.InsertBefore(span, h.node.FirstChild)
+	}
+}
+
+// buildTOC fills toc with nested ordered lists of links to the headings up
+// to the given level.
+func buildTOC(toc *html.Node, headings []heading, depth int) {
+	for toc.FirstChild != nil {
+		toc.RemoveChild(toc.FirstChild)
+	}
+
+	newElement := func(a atom.Atom) *html.Node {
+		return &html.Node{Type: html.ElementNode, Data: a.String(), DataAtom: a}
+	}
+
+	root := newElement(atom.Ol)
+
+	// The open lists, and the level of their items.
+	type open struct {
+		list  *html.Node
+		level int
+	}
+	stack := []open{{list: root}}
+
+	for _, h := range headings {
+		if h.level > depth {
+			continue
+		}
+
+		// Close the lists of deeper levels.
+		for len(stack) > 1 && h.level < stack[len(stack)-1].level {
+			stack = stack[:len(stack)-1]
+		}
+
+		top := &stack[len(stack)-1]
+		if top.level == 0 {
+			top.level = h.level
+		}
+
+		if h.level > top.level && top.list.LastChild != nil {
+			list := newElement(atom.Ol)
+			top.list.LastChild.AppendChild(list)
+			stack = append(stack, open{list: list, level: h.level})
+			top = &stack[len(stack)-1]
+		}
+
+		a := newElement(atom.A)
+		a.Attr = []html.Attribute{{Key: "href", Val: "#" + attr(h.node, "id")}}
+		for c := h.node.FirstChild; c != nil; c = c.NextSibling {
+			a.AppendChild(clone(c))
+		}
+
+		li := newElement(atom.Li)
+		li.AppendChild(a)
+		top.list.AppendChild(li)
+	}
+
+	if root.FirstChild != nil {
+		toc.AppendChild(root)
+	}
+}
+
+func headingLevel(n *html.Node) int {
+	switch n.DataAtom {
+	case atom.H1:
+		return 1
+	case atom.H2:
+		return 2
+	case atom.H3:
+		return 3
+	case atom.H4:
+		return 4
+	case atom.H5:
+		return 5
+	case atom.H6:
+		return 6
+	}
+	return 0
+}
+
+// clone returns a deep copy of n, without any IDs or links.
+func clone(n *html.Node) *html.Node {
+	c := &html.Node{Type: n.Type, Data: n.Data, DataAtom: n.DataAtom, Namespace: n.Namespace}
+	if n.DataAtom == atom.A {
+		// Nested links are not allowed, keep the content only.
+		c = &html.Node{Type: html.ElementNode, Data: "span", DataAtom: atom.Span}
+	} else {
+		for _, a := range n.Attr {
+			if a.Key != "id" {
+				c.Attr = append(c.Attr, a)
+			}
+		}
+	}
+	for cc := n.FirstChild; cc != nil; cc = cc.NextSibling {
+		c.AppendChild(clone(cc))
+	}
+	return c
+}
+
+// walk calls fn for n and its descendants, skipping the descendants of
+// nodes for which fn returns false.
+func walk(n *html.Node, fn func(n *html.Node) bool) {
+	if !fn(n) {
+		return
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		walk(c, fn)
+	}
+}
+
+func attr(n *html.Node, key string) string {
+	v, _ := lookupAttr(n, key)
+	return v
+}
+
+func lookupAttr(n *html.Node, key string) (string, bool) {
+	for _, a := range n.Attr {
+		if a.Namespace == "" && a.Key == key {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
+
+func setAttr(n *html.Node, key, val string) {
+	for i, a := range n.Attr {
+		if a.Namespace == "" && a.Key == key {
+			n.Attr[i].Val = val
+			return
+		}
+	}
+	n.Attr = append(n.Attr, html.Attribute{Key: key, Val: val})
+}
+
+func removeAttr(n *html.Node, key string) {
+	for i, a := range n.Attr {
+		if a.Namespace == "" && a.Key == key {
+			n.Attr = append(n.Attr[:i], n.Attr[i+1:]...)
+			return
+		}
+	}
+}
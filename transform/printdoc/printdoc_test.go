@@ -0,0 +1,94 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package printdoc
+
+import (
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestPrepare(t *testing.T) {
+	c := qt.New(t)
+
+	in := `<html><head></head><body data-print-number-headings>
+<nav data-print-toc="2"><p>Replaced</p></nav>
+<article data-print-chapter="intro" data-print-url="/docs/intro/">
+<h1>Intro</h1>
+<h2 id="setup">Setup</h2>
+<p>See <a href="#setup">setup</a> and <a href="/docs/usage/#flags">flags</a><sup id="fnref:1"><a href="#fn:1">1</a></sup>.</p>
+<h3>Details</h3>
+<div class="footnotes"><ol><li id="fn:1">Note. <a href="#fnref:1">↩</a></li></ol></div>
+</article>
+<article data-print-chapter="usage" data-print-url="/docs/usage/">
+<h1>Usage <a href="/docs/intro/">back</a></h1>
+<h2 id="flags">Flags</h2>
+<p>A note<sup id="fnref:1"><a href="#fn:1">1</a></sup>, see <a href="https://example.org/docs/intro/index.html">intro</a> and <a href="/docs/other/">other</a>.</p>
+<div class="footnotes"><ol><li id="fn:1">Other note.</li></ol></div>
+</article>
+</body></html>`
+
+	out, err := Prepare([]byte(in))
+	c.Assert(err, qt.IsNil)
+	s := string(out)
+
+	// IDs and fragment links.
+	c.Assert(s, qt.Contains, `<article id="intro">`)
+	c.Assert(s, qt.Contains, `<h2 id="intro-setup"><span class="print-number">1.1</span> Setup</h2>`)
+	c.Assert(s, qt.Contains, `<a href="#intro-setup">setup</a>`)
+	c.Assert(s, qt.Contains, `<sup id="intro-fnref:1"><a href="#intro-fn:1">1</a></sup>`)
+	c.Assert(s, qt.Contains, `<li id="intro-fn:1">Note. <a href="#intro-fnref:1">↩</a></li>`)
+	c.Assert(s, qt.Contains, `<sup id="usage-fnref:1"><a href="#usage-fn:1">1</a></sup>`)
+	c.Assert(s, qt.Contains, `<li id="usage-fn:1">Other note.</li>`)
+
+	// Links between the pages.
+	c.Assert(s, qt.Contains, `<a href="#usage-flags">flags</a>`)
+	c.Assert(s, qt.Contains, `<a href="#intro">intro</a>`)
+	c.Assert(s, qt.Contains, `<a href="/docs/other/">other</a>`)
+
+	// Numbered headings.
+	c.Assert(s, qt.Contains, `<h1 id="intro-h1"><span class="print-number">1</span> Intro</h1>`)
+	c.Assert(s, qt.Contains, `<h3 id="intro-h3"><span class="print-number">1.1.1</span> Details</h3>`)
+	c.Assert(s, qt.Contains, `<h1 id="usage-h1"><span class="print-number">2</span> Usage <a href="#intro">back</a></h1>`)
+	c.Assert(s, qt.Contains, `<h2 id="usage-flags"><span class="print-number">2.1</span> Flags</h2>`)
+
+	// The table of contents.
+	c.Assert(s, qt.Contains, `<nav><ol>`+
+		`<li><a href="#intro-h1"><span class="print-number">1</span> Intro</a><ol><li><a href="#intro-setup"><span class="print-number">1.1</span> Setup</a></li></ol></li>`+
+		`<li><a href="#usage-h1"><span class="print-number">2</span> Usage <span>back</span></a><ol><li><a href="#usage-flags"><span class="print-number">2.1</span> Flags</a></li></ol></li>`+
+		`</ol></nav>`)
+
+	c.Assert(s, qt.Not(qt.Contains), "data-print")
+	c.Assert(strings.Count(s, "Replaced"), qt.Equals, 0)
+
+	// Documents without chapters are not touched.
+	plain := []byte(`<p id="a">Hello</p>`)
+	out, err = Prepare(plain)
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(out), qt.Equals, string(plain))
+}
+
+func TestBuildTOCLevels(t *testing.T) {
+	c := qt.New(t)
+
+	out, err := Prepare([]byte(`<nav data-print-toc></nav><div data-print-chapter="c">` +
+		`<h2>A</h2><h4>B</h4><h3>C</h3><h2>D</h2><h1>E</h1></div>`))
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(out), qt.Contains, `<nav><ol>`+
+		`<li><a href="#c-h1">A</a><ol><li><a href="#c-h3">C</a></li></ol></li>`+
+		`<li><a href="#c-h4">D</a></li>`+
+		`<li><a href="#c-h5">E</a></li>`+
+		`</ol></nav>`)
+}
@@ -0,0 +1,221 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package resourcehints replaces a placeholder in the published HTML with
+// preconnect, preload and prefetch hints for the resources the page
+// references after it.
+package resourcehints
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+
+	"github.com/gohugoio/hugo/media"
+	"github.com/gohugoio/hugo/transform"
+)
+
+// Placeholder is replaced with the resource hints when the page is published.
+const Placeholder = "<!--__hugo_resource_hints__-->"
+
+var placeholder = []byte(Placeholder)
+
+// Resource is a resource published in the build.
+type Resource struct {
+	MediaType media.Type
+
+	// The integrity hash, set if the resource is fingerprinted.
+	Integrity string
+}
+
+// Options configures the resource hints of a page.
+type Options struct {
+	// The site's base URL, used to tell the resources on other hosts.
+	BaseURL string
+
+	// Returns the resource published with the given URL, if any.
+	Lookup func(url string) (Resource, bool)
+
+	// The URL of the next page, if paginated, to prefetch.
+	Next string
+}
+
+type hint struct {
+	rel         string
+	href        string
+	as          string
+	integrity   string
+	crossorigin string
+}
+
+func (h hint) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, `<link rel="%s" href="%s"`, h.rel, html.EscapeString(h.href))
+	if h.as != "" {
+		fmt.Fprintf(&b, ` as="%s"`, h.as)
+	}
+	if h.integrity != "" {
+		fmt.Fprintf(&b, ` integrity="%s"`, html.EscapeString(h.integrity))
+	}
+	if h.crossorigin != "" {
+		fmt.Fprintf(&b, ` crossorigin="%s"`, h.crossorigin)
+	}
+	b.WriteString(">")
+	return b.String()
+}
+
+// New creates a new transformer that replaces the first Placeholder in the
+// HTML with hints for the published resources referenced after it, as these
+// are the ones the browser discovers late:
+//
+//   - preconnect to the hosts of the resources not on the site's host
+//   - preload of the stylesheets, scripts, fonts and the first image
+//   - prefetch of the next page
+//
+// Any other Placeholder is removed.
+func New(opts Options) transform.Transformer {
+	var siteHost string
+	if u, err := url.Parse(opts.BaseURL); err == nil {
+		siteHost = strings.ToLower(u.Host)
+	}
+
+	return func(ft transform.FromTo) error {
+		b := ft.From().Bytes()
+
+		idx := bytes.Index(b, placeholder)
+		if idx == -1 {
+			_, err := ft.To().Write(b)
+			return err
+		}
+
+		rest := b[idx+len(placeholder):]
+
+		var lines []string
+		for _, h := range collectHints(rest, siteHost, opts) {
+			lines = append(lines, h.String())
+		}
+
+		if _, err := ft.To().Write(b[:idx]); err != nil {
+			return err
+		}
+		if _, err := ft.To().Write([]byte(strings.Join(lines, "\n"))); err != nil {
+			return err
+		}
+		_, err := ft.To().Write(Strip(rest))
+		return err
+	}
+}
+
+// Strip removes any Placeholder from b.
+func Strip(b []byte) []byte {
+	if !bytes.Contains(b, placeholder) {
+		return b
+	}
+	return bytes.ReplaceAll(b, placeholder, nil)
+}
+
+// Contains reports whether b contains a Placeholder.
+func Contains(b []byte) bool {
+	return bytes.Contains(b, placeholder)
+}
+
+func collectHints(b []byte, siteHost string, opts Options) []hint {
+	var (
+		preconnects []hint
+		preloads    []hint
+		seen        = make(map[string]bool)
+		imageSeen   bool
+	)
+
+	add := func(hints *[]hint, h hint) {
+		key := h.rel + " " + h.href
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		*hints = append(*hints, h)
+	}
+
+	if opts.Lookup != nil {
+		for _, href := range attributeValues(b) {
+			r, found := opts.Lookup(href)
+			if !found {
+				continue
+			}
+
+			h := hint{rel: "preload", href: href, integrity: r.Integrity}
+			switch {
+			case r.MediaType.SubType == media.CSSType.SubType:
+				h.as = "style"
+			case r.MediaType.SubType == media.JavascriptType.SubType:
+				h.as = "script"
+			case r.MediaType.MainType == "font":
+				// Fonts are always fetched in CORS mode.
+				h.as, h.crossorigin = "font", "anonymous"
+			case r.MediaType.MainType == "image":
+				if imageSeen {
+					continue
+				}
+				imageSeen = true
+				h.as = "image"
+			default:
+				continue
+			}
+			if u, err := url.Parse(href); err == nil && u.Host != "" && strings.ToLower(u.Host) != siteHost {
+				scheme := u.Scheme
+				if scheme == "" {
+					scheme = "https"
+				}
+				add(&preconnects, hint{rel: "preconnect", href: scheme + "://" + u.Host})
+			}
+
+			add(&preloads, h)
+		}
+	}
+
+	hints := append(preconnects, preloads...)
+	if opts.Next != "" {
+		hints = append(hints, hint{rel: "prefetch", href: opts.Next})
+	}
+
+	return hints
+}
+
+// attributeValues returns the quoted attribute values in the HTML in b, in
+// the order they appear.
+func attributeValues(b []byte) []string {
+	var values []string
+	for {
+		i := bytes.IndexByte(b, '=')
+		if i == -1 || i == len(b)-1 {
+			return values
+		}
+		b = b[i+1:]
+		quote := b[0]
+		if quote != '"' && quote != '\'' {
+			continue
+		}
+		end := bytes.IndexByte(b[1:], quote)
+		if end == -1 {
+			return values
+		}
+		v := string(b[1 : end+1])
+		if strings.Contains(v, "&") {
+			v = html.UnescapeString(v)
+		}
+		values = append(values, v)
+		b = b[end+2:]
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2021 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcehints
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gohugoio/hugo/media"
+	"github.com/gohugoio/hugo/transform"
+)
+
+func TestResourceHints(t *testing.T) {
+	c := qt.New(t)
+
+	fontType, _ := media.FromStringAndExt("font/woff2", "woff2")
+
+	published := map[string]Resource{
+		"/a.css":                       {MediaType: media.CSSType},
+		"/a.js":                        {MediaType: media.JavascriptType, Integrity: "sha256-abc"},
+		"https://cdn.example.org/b.js": {MediaType: media.JavascriptType},
+		"https://example.com/c.js":     {MediaType: media.JavascriptType},
+		"/hero.jpg":                    {MediaType: media.JPEGType},
+		"/b.jpg":                       {MediaType: media.JPEGType},
+		"/font.woff2":                  {MediaType: fontType},
+		"/data.json":                   {MediaType: media.JSONType},
+	}
+
+	opts := Options{
+		BaseURL: "https://example.com/",
+		Lookup: func(url string) (Resource, bool) {
+			r, found := published[url]
+			return r, found
+		},
+	}
+
+	for _, test := range []struct {
+		name   string
+		opts   Options
+		in     string
+		expect string
+	}{
+		{"No placeholder", opts, `<head><script src="/a.js"></script></head>`, `<head><script src="/a.js"></script></head>`},
+		{"No resources", opts, `<head>` + Placeholder + `</head><body></body>`, `<head></head><body></body>`},
+		{"Before placeholder", opts, `<head><link rel="stylesheet" href="/a.css">` + Placeholder + `</head>`, `<head><link rel="stylesheet" href="/a.css"></head>`},
+		{"Not published", opts, `<head>` + Placeholder + `</head><script src="/other.js"></script><img src='https://img.example.org/x.jpg'>`, `<head></head><script src="/other.js"></script><img src='https://img.example.org/x.jpg'>`},
+		{
+			"Resources",
+			opts,
+			`<head>` + Placeholder + `<link rel="stylesheet" href="/a.css"></head>
+<body><img src="/hero.jpg"><img src='/b.jpg'>
+<div style="font: url(/font.woff2)" data-font="/font.woff2"></div>
+<script src="https://cdn.example.org/b.js"></script>
+<script src="/a.js" integrity="sha256-abc"></script>
+<script src="/a.js"></script>
+<a href="/data.json">Data</a>
+` + Placeholder + `</body>`,
+			`<head><link rel="preconnect" href="https://cdn.example.org">
+<link rel="preload" href="/a.css" as="style">
+<link rel="preload" href="/hero.jpg" as="image">
+<link rel="preload" href="/font.woff2" as="font" crossorigin="anonymous">
+<link rel="preload" href="https://cdn.example.org/b.js" as="script">
+<link rel="preload" href="/a.js" as="script" integrity="sha256-abc"><link rel="stylesheet" href="/a.css"></head>
+<body><img src="/hero.jpg"><img src='/b.jpg'>
+<div style="font: url(/font.woff2)" data-font="/font.woff2"></div>
+<script src="https://cdn.example.org/b.js"></script>
+<script src="/a.js" integrity="sha256-abc"></script>
+<script src="/a.js"></script>
+<a href="/data.json">Data</a>
+</body>`,
+		},
+		{"Site host", opts, `<head>` + Placeholder + `</head><script src="https://example.com/c.js"></script>`, `<head><link rel="preload" href="https://example.com/c.js" as="script"></head><script src="https://example.com/c.js"></script>`},
+		{"Next page", Options{Next: "/page/2/"}, `<head>` + Placeholder + `</head>`, `<head><link rel="prefetch" href="/page/2/"></head>`},
+	} {
+		test := test
+		c.Run(test.name, func(c *qt.C) {
+			out := new(bytes.Buffer)
+			tr := transform.New(New(test.opts))
+			c.Assert(tr.Apply(out, strings.NewReader(test.in)), qt.IsNil)
+			c.Assert(out.String(), qt.Equals, test.expect)
+		})
+	}
+}
+
+func TestStrip(t *testing.T) {
+	c := qt.New(t)
+
+	c.Assert(Contains([]byte(`{"a": "`+Placeholder+`"}`)), qt.IsTrue)
+	c.Assert(string(Strip([]byte(`{"a": "`+Placeholder+`"}`))), qt.Equals, `{"a": ""}`)
+	c.Assert(string(Strip([]byte(`{"a": "b"}`))), qt.Equals, `{"a": "b"}`)
+}